@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/apigen"
+	"github.com/spf13/cobra"
+)
+
+const (
+	version = "0.1.0"
+
+	mkdirPerm = 0755
+	filePerm  = 0644
+)
+
+var (
+	// Flags
+	dir    string
+	output string
+)
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+var rootCmd = &cobra.Command{
+	Use:   "api-gen",
+	Short: "Generate the OpenAPI 3.1 spec of the REST API from the handler annotations",
+	Long: `api-gen reads the Swagger annotations of the API handlers, resolves the Go types
+they reference and writes an OpenAPI 3.1 document describing the REST API.`,
+	Version: version,
+	Example: `  # Regenerate the spec served by the API server
+  api-gen
+
+  # Generate the spec of another package
+  api-gen --dir ./pkg/api --output ./openapi.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runGenerate,
+}
+
+func init() {
+	rootCmd.Flags().StringVarP(&dir, "dir", "d", "./pkg/api", "directory of the package with the annotated handlers")
+	rootCmd.Flags().StringVarP(&output, "output", "o", "./pkg/api/docs/openapi.yaml", "output file")
+}
+
+func runGenerate(cmd *cobra.Command, args []string) error {
+	spec, err := apigen.Generate(dir)
+	if err != nil {
+		return fmt.Errorf("failed to generate OpenAPI spec: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(output), mkdirPerm); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := os.WriteFile(output, spec, filePerm); err != nil {
+		return fmt.Errorf("failed to write OpenAPI spec: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "✅ OpenAPI spec written to %s\n", output)
+
+	return nil
+}
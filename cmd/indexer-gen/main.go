@@ -14,11 +14,19 @@ var (
 	// Flags
 	name        string
 	events      []string
+	abiFile     string
+	abiEvents   []string
 	output      string
 	packageName string
+	indexerType string
 	importPath  string
 	force       bool
 	dryRun      bool
+	decoder     string
+	templateDir string
+	sdk         bool
+
+	maxStringLength int
 )
 
 func main() {
@@ -44,6 +52,27 @@ indexer logic, database migrations, and documentation automatically.`,
     --event "Transfer(address indexed from, address indexed to, uint256 indexed tokenId)" \
     --output ./examples/indexers/erc721
 
+  # Generate an indexer from the events of a contract ABI
+  indexer-gen --name ERC20Token \
+    --abi-file ./out/ERC20.abi.json \
+    --abi-events Transfer,Approval
+
+  # Generate an ERC-1155 indexer, ABI-decoding the arrays of TransferBatch
+  indexer-gen --name ERC1155 \
+    --abi-file ./out/ERC1155.abi.json \
+    --abi-events TransferSingle,TransferBatch \
+    --decoder abi
+
+  # Generate an indexer with a Kafka producer hook from a custom template directory
+  indexer-gen --name ERC20Token \
+    --event "Transfer(address indexed from, address indexed to, uint256 value)" \
+    --template-dir ./examples/templates/kafka-hook
+
+  # Generate an indexer with a Go client SDK and an in-memory mock client for tests
+  indexer-gen --name ERC20Token \
+    --event "Transfer(address indexed from, address indexed to, uint256 value)" \
+    --sdk
+
   # Preview generation without writing files
   indexer-gen --name MyToken \
     --event "Transfer(address,address,uint256)" \
@@ -54,28 +83,61 @@ indexer logic, database migrations, and documentation automatically.`,
 func init() {
 	rootCmd.Flags().StringVarP(&name, "name", "n", "", "indexer name (required, PascalCase, e.g., 'ERC20Token')")
 	rootCmd.Flags().StringArrayVarP(&events, "event", "e", []string{},
-		"event signature (required, can be specified multiple times)")
+		"event signature (can be specified multiple times)")
+	rootCmd.Flags().StringVar(&abiFile, "abi-file", "", "path to a JSON contract ABI to read event signatures from")
+	rootCmd.Flags().StringSliceVar(&abiEvents, "abi-events", []string{},
+		"comma-separated names of the ABI events to generate (default: all events)")
 	rootCmd.Flags().StringVarP(&output, "output", "o", "", "output directory (default: ./indexers/<name_lowercase>)")
 	rootCmd.Flags().StringVarP(&packageName, "package", "p", "", "Go package name (default: derived from name)")
+	rootCmd.Flags().StringVar(&indexerType, "type", "",
+		"indexer type the indexer is registered as and configured with (default: derived from name)")
 	rootCmd.Flags().StringVarP(&importPath, "import", "i", "", "Go import path (default: auto-detected from go.mod)")
 	rootCmd.Flags().BoolVarP(&force, "force", "f", false, "overwrite existing files")
 	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be generated without writing files")
+	rootCmd.Flags().StringVar(&decoder, "decoder", codegen.DecoderRaw,
+		"decoder of non-indexed parameters: 'raw' reads 32-byte words, "+
+			"'abi' also decodes dynamic types like arrays and tuples")
+	rootCmd.Flags().StringVar(&templateDir, "template-dir", "",
+		"directory of custom *.tmpl templates, overriding built-in templates of the same name")
+	rootCmd.Flags().BoolVar(&sdk, "sdk", false,
+		"also generate a Go client of the indexer's REST API and an in-memory mock client for tests")
+	rootCmd.Flags().IntVar(&maxStringLength, "max-string-length", codegen.DefaultMaxStringLength,
+		"maximum length in bytes of the stored string parameters, longer strings are truncated")
 
 	// Mark required flags
 	_ = rootCmd.MarkFlagRequired("name")
-	_ = rootCmd.MarkFlagRequired("event")
+	rootCmd.MarkFlagsOneRequired("event", "abi-file")
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
+	if len(abiEvents) > 0 && abiFile == "" {
+		return fmt.Errorf("--abi-events requires --abi-file")
+	}
+
+	// Events of the ABI file are generated after the ones given with --event
+	if abiFile != "" {
+		abiSignatures, err := codegen.ParseABIFile(abiFile, abiEvents)
+		if err != nil {
+			return err
+		}
+		events = append(events, abiSignatures...)
+	}
+
 	// Create generator
 	gen := &codegen.Generator{
-		Name:       name,
-		Package:    packageName,
-		Events:     events,
-		OutputDir:  output,
-		ImportPath: importPath,
-		Force:      force,
-		DryRun:     dryRun,
+		Name:        name,
+		Package:     packageName,
+		Type:        indexerType,
+		Events:      events,
+		OutputDir:   output,
+		ImportPath:  importPath,
+		Force:       force,
+		DryRun:      dryRun,
+		Decoder:     decoder,
+		TemplateDir: templateDir,
+		SDK:         sdk,
+
+		MaxStringLength: maxStringLength,
 	}
 
 	// Generate indexer files
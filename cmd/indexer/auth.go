@@ -0,0 +1,49 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// tokenSize is the number of random bytes in a generated API token.
+const tokenSize = 32
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage API authentication",
+}
+
+var authGenerateTokenCmd = &cobra.Command{
+	Use:   "generate-token",
+	Short: "Generate a random API token and the hash to configure for it",
+	Long: `Generate-token prints a new random API token and its SHA-256 hash. Add the hash to
+api.auth.api_key_hashes in the config file and hand the token to the client, which sends it
+in an "Authorization: Bearer <token>" header. The token itself is not stored anywhere.`,
+	Example: `  indexer auth generate-token`,
+	Args:    cobra.NoArgs,
+	RunE:    runAuthGenerateToken,
+}
+
+func init() {
+	authCmd.AddCommand(authGenerateTokenCmd)
+	rootCmd.AddCommand(authCmd)
+}
+
+func runAuthGenerateToken(cmd *cobra.Command, args []string) error {
+	raw := make([]byte, tokenSize)
+	if _, err := rand.Read(raw); err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	token := hex.EncodeToString(raw)
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Token: %s\n", token)
+	fmt.Fprintf(cmd.OutOrStdout(), "Hash:  %s\n", api.HashAPIKey(token))
+	fmt.Fprintln(cmd.OutOrStdout(), "\nAdd the hash to api.auth.api_key_hashes and keep the token secret, it is not shown again.")
+
+	return nil
+}
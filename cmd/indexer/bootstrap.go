@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/config"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/downloader"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	downloadermig "github.com/goran-ethernal/ChainIndexor/internal/migrations"
+	"github.com/goran-ethernal/ChainIndexor/internal/snapshot"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	snapshotURL        string
+	indexerSnapshotURL []string
+)
+
+// snapshotTarget maps a snapshot location to the database it is imported into.
+type snapshotTarget struct {
+	name     string
+	location string
+	db       pkgconfig.DatabaseConfig
+}
+
+var bootstrapCmd = &cobra.Command{
+	Use:   "bootstrap",
+	Short: "Import database snapshots and start indexing from the snapshot's last block",
+	Long: `Bootstrap downloads a pre-built downloader database snapshot, verifies it against its
+companion .sha256 checksum file, imports it and then starts indexing from the last block
+recorded in the snapshot instead of syncing from the indexers' start blocks.
+
+Snapshots can be local paths or file://, http(s)://, s3://bucket/key or gs://bucket/key URLs.
+S3 and GCS objects are fetched from their public endpoints, use a pre-signed https:// URL
+for private buckets. Indexer databases are imported with --indexer-snapshot name=URL.
+Existing databases are never overwritten.`,
+	Example: `  indexer bootstrap --config config.yaml \
+    --snapshot gs://bucket/mainnet/downloader.db \
+    --indexer-snapshot erc20=gs://bucket/mainnet/erc20.db`,
+	RunE: runBootstrap,
+}
+
+func init() {
+	bootstrapCmd.Flags().StringVarP(&configPath, "config", "c", "config.yaml", "path to configuration file")
+	bootstrapCmd.Flags().StringVar(&snapshotURL, "snapshot", "", "location of the downloader database snapshot")
+	bootstrapCmd.Flags().StringArrayVar(&indexerSnapshotURL, "indexer-snapshot", nil,
+		"indexer database snapshot as name=location (repeatable)")
+	_ = bootstrapCmd.MarkFlagRequired("snapshot")
+	rootCmd.AddCommand(bootstrapCmd)
+}
+
+func runBootstrap(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadFromFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log := logger.NewComponentLoggerFromConfig(common.ComponentDownloader, cfg.Logging)
+
+	// Snapshots hold the databases of a single chain
+	if len(cfg.Chains) > 0 {
+		return fmt.Errorf("bootstrapping from a snapshot is only supported without chains")
+	}
+
+	// Snapshots are SQLite database files
+	if cfg.Downloader.DB.Driver != pkgconfig.DBDriverSQLite {
+		return fmt.Errorf("bootstrapping from a snapshot requires the sqlite driver, got %q", cfg.Downloader.DB.Driver)
+	}
+
+	targets := []snapshotTarget{{name: "downloader", location: snapshotURL, db: cfg.Downloader.DB}}
+
+	for _, entry := range indexerSnapshotURL {
+		name, location, ok := strings.Cut(entry, "=")
+		if !ok || name == "" || location == "" {
+			return fmt.Errorf("invalid --indexer-snapshot %q: expected name=location", entry)
+		}
+
+		idxCfg := findIndexerConfig(cfg.Indexers, name)
+		if idxCfg == nil {
+			return fmt.Errorf("indexer %s from --indexer-snapshot is not configured", name)
+		}
+
+		targets = append(targets, snapshotTarget{name: name, location: location, db: idxCfg.DB})
+	}
+
+	// Check all targets up front, so a bootstrap never stops with only some snapshots imported
+	for _, target := range targets {
+		if _, err := os.Stat(target.db.Path); err == nil {
+			return fmt.Errorf("%s database %s already exists, remove it to bootstrap from a snapshot",
+				target.name, target.db.Path)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	downloadDir, err := os.MkdirTemp("", "chainindexor-snapshot-*")
+	if err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+	defer os.RemoveAll(downloadDir)
+
+	// Snapshots can be large, so rely on the context for cancellation instead of a timeout
+	client := &http.Client{}
+
+	for _, target := range targets {
+		log.Infof("Downloading %s snapshot from %s...", target.name, target.location)
+		path, err := snapshot.Download(ctx, client, target.location, downloadDir)
+		if err != nil {
+			return fmt.Errorf("failed to download %s snapshot: %w", target.name, err)
+		}
+
+		log.Infof("Importing %s snapshot into %s...", target.name, target.db.Path)
+		if err := db.ImportSnapshot(ctx, path, target.db); err != nil {
+			return fmt.Errorf("failed to import %s snapshot: %w", target.name, err)
+		}
+	}
+
+	// Bring the snapshot up to date with this version's schema.
+	// Indexer migrations run when the indexers are created.
+	log.Info("Running database migrations...")
+	if err := downloadermig.RunMigrations(cfg.Downloader.DB); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	lastBlock, err := snapshotLastBlock(cfg.Downloader.DB)
+	if err != nil {
+		return err
+	}
+	log.Infof("Snapshot imported, resuming sync from block %d", lastBlock)
+
+	return runIndexer(cmd, args)
+}
+
+// snapshotLastBlock returns the last indexed block recorded in the downloader database.
+func snapshotLastBlock(dbConfig pkgconfig.DatabaseConfig) (uint64, error) {
+	database, err := db.NewSQLiteDBFromConfig(dbConfig)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	syncManager, err := downloader.NewSyncManager(database, logger.NewNopLogger(), &db.NoOpMaintenance{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create sync manager: %w", err)
+	}
+
+	lastBlock, err := syncManager.GetLastIndexedBlock()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read snapshot sync state: %w", err)
+	}
+
+	return lastBlock, nil
+}
+
+func findIndexerConfig(indexers []pkgconfig.IndexerConfig, name string) *pkgconfig.IndexerConfig {
+	for i := range indexers {
+		if indexers[i].Name == name {
+			return &indexers[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/downloader"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
+	downloadermig "github.com/goran-ethernal/ChainIndexor/internal/migrations"
+	"github.com/goran-ethernal/ChainIndexor/internal/reorg"
+	"github.com/goran-ethernal/ChainIndexor/internal/rpc"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+	pkgdownloader "github.com/goran-ethernal/ChainIndexor/pkg/downloader"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	pkgrpc "github.com/goran-ethernal/ChainIndexor/pkg/rpc"
+)
+
+// chainStack is the downloader, with its RPC client and indexers, of a single chain.
+type chainStack struct {
+	log *logger.Logger
+
+	chainID     uint64
+	cfg         pkgconfig.Config
+	ethClient   *rpc.LoadBalancedClient
+	downloader  *downloader.Downloader
+	startBlocks *downloader.StartBlockResolver
+}
+
+// newChainStack connects to the chain's RPC endpoint and creates its downloader with the configured indexers.
+func newChainStack(
+	ctx context.Context,
+	cfg *pkgconfig.Config,
+	chain pkgconfig.ChainConfig,
+	log *logger.Logger,
+) (*chainStack, error) {
+	chainCfg := cfg.ForChain(chain)
+
+	log.Info("Connecting to Ethereum node...")
+	ethClient, err := rpc.NewLoadBalancedClient(ctx, chainCfg.Downloader.RPCURLs(), chainCfg.Downloader.Retry,
+		chainCfg.Downloader.CircuitBreaker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RPC client: %w", err)
+	}
+	// The RPC URL can hold credentials, e.g. when it is set by the CI_DOWNLOADER_RPC_URL variable
+	log.Infof("Connected to Ethereum node: %s", strings.Join(ethClient.Nodes(), ", "))
+
+	chainID, err := ethClient.ChainID(ctx)
+	if err != nil {
+		ethClient.Close()
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	// Indexing another chain than configured would silently store wrong data
+	if err := checkChainID(chainCfg.Downloader.ExpectedChainID, chainID); err != nil {
+		ethClient.Close()
+		return nil, err
+	}
+
+	// The RPC metrics are labelled with the chain ID, like the metrics of the other components
+	ethClient.SetChainID(chainID)
+
+	stack, err := buildChainStack(chainCfg, chainID, ethClient)
+	if err != nil {
+		ethClient.Close()
+		return nil, fmt.Errorf("chain %d: %w", chainID, err)
+	}
+
+	return stack, nil
+}
+
+// checkChainID returns an error if the chain ID served by the RPC endpoint is not the expected one.
+// An expected chain ID of 0 accepts any chain.
+func checkChainID(expected, actual uint64) error {
+	if expected != 0 && expected != actual {
+		return fmt.Errorf("chain %d: RPC endpoint serves chain %d, check the rpc_url", expected, actual)
+	}
+
+	return nil
+}
+
+// buildChainStack creates the downloader of a chain and registers its indexers.
+func buildChainStack(cfg pkgconfig.Config, chainID uint64, ethClient *rpc.LoadBalancedClient) (*chainStack, error) {
+	componentLogger := func(component string) *logger.Logger {
+		return logger.NewComponentLoggerFromConfig(component, cfg.Logging).WithChainID(chainID)
+	}
+	log := componentLogger(common.ComponentDownloader)
+
+	// Run downloader migrations
+	log.Info("Running database migrations...")
+	if err := downloadermig.RunMigrations(cfg.Downloader.DB); err != nil {
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	// Initialize database
+	database, err := db.NewDBFromConfig(cfg.Downloader.DB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database: %w", err)
+	}
+
+	// Initialize maintenance coordinator
+	dbMaintenance := db.NewMaintenanceCoordinator(
+		chainID,
+		cfg.Downloader.DB.Path,
+		database,
+		cfg.Downloader.Maintenance,
+		componentLogger(common.ComponentMaintenance),
+	)
+
+	// Initialize reorg detector
+	reorgDetector, err := reorg.NewReorgDetector(
+		chainID,
+		database,
+		ethClient,
+		componentLogger(common.ComponentReorgDetector),
+		dbMaintenance,
+		cfg.Downloader.HeaderCacheSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reorg detector: %w", err)
+	}
+
+	// Initialize sync manager
+	syncManager, err := downloader.NewSyncManager(
+		database,
+		componentLogger(common.ComponentSyncManager),
+		dbMaintenance,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sync manager: %w", err)
+	}
+
+	if err := syncManager.VerifyChainID(chainID); err != nil {
+		_ = database.Close()
+		return nil, err
+	}
+
+	// Initialize downloader
+	dl, err := downloader.New(
+		chainID,
+		cfg.Downloader,
+		ethClient,
+		reorgDetector,
+		syncManager,
+		dbMaintenance,
+		log,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create downloader: %w", err)
+	}
+
+	stack := &chainStack{
+		log:         log,
+		chainID:     chainID,
+		cfg:         cfg,
+		ethClient:   ethClient,
+		downloader:  dl,
+		startBlocks: downloader.NewStartBlockResolver(ethClient, database, cfg.Downloader.StartBlockDetection, log),
+	}
+
+	// Register indexers from configuration
+	log.Infof("Registering %d indexer(s)...", len(cfg.Indexers))
+	for i, idxCfg := range cfg.Indexers {
+		if idxCfg.Type == "" {
+			_ = dl.Close()
+			return nil, fmt.Errorf("indexer #%d (%s) is missing 'type' field in configuration", i+1, idxCfg.Name)
+		}
+
+		if err := stack.AddIndexer(idxCfg); err != nil {
+			_ = dl.Close()
+			return nil, err
+		}
+	}
+
+	return stack, nil
+}
+
+// AddIndexer implements config.ReloadTarget.
+func (s *chainStack) AddIndexer(idxCfg pkgconfig.IndexerConfig) error {
+	s.log.Infof("Creating indexer: %s (type: %s)", idxCfg.Name, idxCfg.Type)
+
+	metrics.SetIndexerChainID(idxCfg.Name, s.chainID)
+
+	// Indexers of contracts with auto_detect_start_block start from the deployment of the contracts
+	startBlock, err := s.startBlocks.ResolveIndexerStartBlock(context.Background(), idxCfg)
+	if err != nil {
+		return fmt.Errorf("failed to resolve start block of indexer %s: %w", idxCfg.Name, err)
+	}
+	idxCfg.StartBlock = startBlock
+
+	idx, err := indexer.Create(
+		idxCfg.Type,
+		idxCfg,
+		logger.GetDefaultLogger(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create indexer %s: %w", idxCfg.Name, err)
+	}
+
+	s.downloader.RegisterIndexer(idx)
+	s.log.Infof("✓ Registered indexer: %s", idxCfg.Name)
+
+	return nil
+}
+
+// UpdateDownloaderConfig implements config.ReloadTarget.
+func (s *chainStack) UpdateDownloaderConfig(cfg pkgconfig.DownloaderConfig) {
+	s.downloader.UpdateConfig(cfg)
+}
+
+// Close closes the downloader and the RPC client of the chain.
+func (s *chainStack) Close() {
+	if err := s.downloader.Close(); err != nil {
+		s.log.Warnf("Failed to close downloader: %v", err)
+	}
+	s.ethClient.Close()
+}
+
+// chainRouter serves the indexers of all chains to the API, routing each indexer to its chain.
+// Indexer names are unique across chains.
+type chainRouter struct {
+	stacks []*chainStack
+}
+
+// stackOf returns the chain stack of the named indexer, or nil if no chain has it.
+func (r *chainRouter) stackOf(name string) *chainStack {
+	for _, stack := range r.stacks {
+		if stack.downloader.Coordinator().GetByName(name) != nil {
+			return stack
+		}
+	}
+
+	return nil
+}
+
+// GetByName implements api.IndexerRegistry.
+func (r *chainRouter) GetByName(name string) indexer.Indexer {
+	if stack := r.stackOf(name); stack != nil {
+		return stack.downloader.Coordinator().GetByName(name)
+	}
+
+	return nil
+}
+
+// ListAll implements api.IndexerRegistry.
+func (r *chainRouter) ListAll() []indexer.Indexer {
+	var indexers []indexer.Indexer
+	for _, stack := range r.stacks {
+		indexers = slices.Concat(indexers, stack.downloader.Coordinator().ListAll())
+	}
+
+	return indexers
+}
+
+// RPCClient implements api.RPCClientProvider.
+func (r *chainRouter) RPCClient(indexerName string) pkgrpc.EthClient {
+	if stack := r.stackOf(indexerName); stack != nil {
+		return stack.ethClient
+	}
+
+	return nil
+}
+
+// PingDatabase implements api.DatabasePinger, checking the downloader database of every chain.
+func (r *chainRouter) PingDatabase(ctx context.Context) error {
+	for _, stack := range r.stacks {
+		if err := stack.downloader.PingDatabase(ctx); err != nil {
+			return fmt.Errorf("chain %d: %w", stack.chainID, err)
+		}
+	}
+
+	return nil
+}
+
+// PendingEvents implements api.PendingEventSource.
+func (r *chainRouter) PendingEvents(indexerName string) []pkgdownloader.PendingEvent {
+	if stack := r.stackOf(indexerName); stack != nil {
+		return stack.downloader.PendingEvents(indexerName)
+	}
+
+	return []pkgdownloader.PendingEvent{}
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckChainID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, checkChainID(0, 1))
+	require.NoError(t, checkChainID(11155111, 11155111))
+	require.EqualError(t, checkChainID(11155111, 1), "chain 11155111: RPC endpoint serves chain 1, check the rpc_url")
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/downloader"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mergeSourcePath string
+	mergeTargetPath string
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage downloader databases",
+}
+
+var dbMergeCmd = &cobra.Command{
+	Use:   "merge",
+	Short: "Merge the indexed data of one downloader database into another",
+	Long: `Merge copies the event logs, log and topic coverage ranges and block hashes of the
+source downloader database into the target downloader database, e.g. to combine the results
+of backfill nodes that indexed separate block ranges in parallel. Rows already present in
+the target are kept, and the coverage ranges of the target are compacted afterwards.
+
+The source database is only read. The sync state of the target database is not changed.`,
+	Example: `  indexer db merge --source ./data/backfill-5m-10m.db --target ./data/downloader.db`,
+	RunE:    runDBMerge,
+}
+
+func init() {
+	dbMergeCmd.Flags().StringVar(&mergeSourcePath, "source", "", "path to the database to merge from")
+	dbMergeCmd.Flags().StringVar(&mergeTargetPath, "target", "", "path to the database to merge into")
+	_ = dbMergeCmd.MarkFlagRequired("source")
+	_ = dbMergeCmd.MarkFlagRequired("target")
+	dbCmd.AddCommand(dbMergeCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+func runDBMerge(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log := logger.NewComponentLogger(common.ComponentDownloader, "info", false)
+
+	target := pkgconfig.DatabaseConfig{Path: mergeTargetPath}
+	target.ApplyDefaults()
+
+	log.Infof("Merging %s into %s...", mergeSourcePath, mergeTargetPath)
+	if err := downloader.MergeDatabases(ctx, mergeSourcePath, target, log); err != nil {
+		return fmt.Errorf("failed to merge databases: %w", err)
+	}
+	log.Info("Databases merged")
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listDBPath string
+	listJSON   bool
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available indexer types",
+	Long: `List all registered indexer types that can be used in the configuration file, with their
+description, version, author and number of event types.
+
+With --db, the row count of every event table of the registered types found in the given
+indexer database is listed too. The database is opened read-only.`,
+	Example: `  indexer list
+  indexer list --db ./data/erc20.sqlite
+  indexer list --json`,
+	SilenceUsage: true,
+	RunE:         runList,
+}
+
+func init() {
+	listCmd.Flags().StringVar(&listDBPath, "db", "", "path to an indexer database to count the event rows of")
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "print the list as JSON")
+	rootCmd.AddCommand(listCmd)
+}
+
+// listedIndexer is a registered indexer type as printed by the list command.
+type listedIndexer struct {
+	indexer.RegisteredFactory
+	EventTypes int `json:"event_types"`
+}
+
+// listedDatabase holds the row counts of the event tables found in a database.
+type listedDatabase struct {
+	Path   string           `json:"path"`
+	Tables map[string]int64 `json:"tables"`
+}
+
+// listOutput is the output of the list command.
+type listOutput struct {
+	Indexers []listedIndexer `json:"indexers"`
+	Database *listedDatabase `json:"database,omitempty"`
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	factories := indexer.ListRegisteredWithMeta()
+
+	output := listOutput{Indexers: make([]listedIndexer, 0, len(factories))}
+	for _, factory := range factories {
+		output.Indexers = append(output.Indexers, listedIndexer{
+			RegisteredFactory: factory,
+			EventTypes:        len(factory.EventTables),
+		})
+	}
+
+	if listDBPath != "" {
+		tables, err := countEventRows(cmd.Context(), listDBPath, factories)
+		if err != nil {
+			return err
+		}
+		output.Database = &listedDatabase{Path: listDBPath, Tables: tables}
+	}
+
+	if listJSON {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(output)
+	}
+
+	printList(cmd.OutOrStdout(), output)
+
+	return nil
+}
+
+// printList prints the list command output as tables.
+func printList(out io.Writer, output listOutput) {
+	if len(output.Indexers) == 0 {
+		fmt.Fprintln(out, "No indexer types registered")
+	} else {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tDESCRIPTION\tVERSION\tAUTHOR\tEVENTS")
+		for _, idx := range output.Indexers {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", idx.Type, orDash(idx.Description), orDash(idx.Version),
+				orDash(idx.Author), idx.EventTypes)
+		}
+		_ = w.Flush()
+	}
+
+	if output.Database == nil {
+		return
+	}
+
+	fmt.Fprintf(out, "\nEvent tables in %s:\n", output.Database.Path)
+	if len(output.Database.Tables) == 0 {
+		fmt.Fprintln(out, "  (no event tables of the registered indexer types)")
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tROWS")
+	for _, table := range slices.Sorted(maps.Keys(output.Database.Tables)) {
+		fmt.Fprintf(w, "%s\t%d\n", table, output.Database.Tables[table])
+	}
+	_ = w.Flush()
+}
+
+// countEventRows returns the row count of every event table of the given indexer types
+// that exists in the database at path.
+func countEventRows(ctx context.Context, path string, factories []indexer.RegisteredFactory) (map[string]int64, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	cfg := pkgconfig.DatabaseConfig{Path: path}
+	cfg.ApplyDefaults()
+
+	database, err := db.NewReadOnlySQLiteDBFromConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	eventTables := make(map[string]struct{})
+	for _, factory := range factories {
+		for _, table := range factory.EventTables {
+			eventTables[table] = struct{}{}
+		}
+	}
+
+	existing, err := databaseTables(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64)
+	for table := range eventTables {
+		if _, ok := existing[table]; !ok {
+			continue
+		}
+
+		var count int64
+		query := fmt.Sprintf(`SELECT COUNT(*) FROM "%s"`, strings.ReplaceAll(table, `"`, `""`))
+		if err := database.QueryRowContext(ctx, query).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count rows of %s: %w", table, err)
+		}
+		counts[table] = count
+	}
+
+	return counts, nil
+}
+
+// databaseTables returns the names of the tables of the database.
+func databaseTables(ctx context.Context, database *sql.DB) (map[string]struct{}, error) {
+	rows, err := database.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'table'")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	tables := make(map[string]struct{})
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to list tables: %w", err)
+		}
+		tables[name] = struct{}{}
+	}
+
+	return tables, rows.Err()
+}
+
+// orDash returns value, or "-" if it is empty.
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
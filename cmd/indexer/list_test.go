@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCommand(t *testing.T) {
+	t.Parallel()
+
+	code, stdout, _ := runIndexerCommand(t, "list")
+	require.Equal(t, 0, code)
+	require.Equal(t, `TYPE        DESCRIPTION                VERSION  AUTHOR  EVENTS
+erc1155     Indexes ERC1155 events     -        -       1
+erc20       Indexes ERC20 events       -        -       2
+erc721      Indexes ERC721 events      -        -       3
+governance  Indexes Governance events  -        -       2
+uniswap_v2  Indexes UniswapV2 events   -        -       4
+`, stdout)
+}
+
+func TestListCommand_JSON(t *testing.T) {
+	t.Parallel()
+
+	code, stdout, _ := runIndexerCommand(t, "list", "--json")
+	require.Equal(t, 0, code)
+
+	var output listOutput
+	require.NoError(t, json.Unmarshal([]byte(stdout), &output))
+	require.Nil(t, output.Database)
+	require.Len(t, output.Indexers, 5)
+
+	erc20 := output.Indexers[1]
+	require.Equal(t, "erc20", erc20.Type)
+	require.Equal(t, "Indexes ERC20 events", erc20.Description)
+	require.Equal(t, 2, erc20.EventTypes)
+	require.Equal(t, map[string]string{"Transfer": "transfers", "Approval": "approvals"}, erc20.EventTables)
+}
+
+func TestListCommand_Database(t *testing.T) {
+	t.Parallel()
+
+	cfg := pkgconfig.DatabaseConfig{Path: filepath.Join(t.TempDir(), "erc20.sqlite")}
+	cfg.ApplyDefaults()
+
+	database, err := db.NewSQLiteDBFromConfig(cfg)
+	require.NoError(t, err)
+	_, err = database.Exec(`
+		CREATE TABLE transfers (id INTEGER PRIMARY KEY);
+		CREATE TABLE approvals (id INTEGER PRIMARY KEY);
+		CREATE TABLE balances (id INTEGER PRIMARY KEY);
+		INSERT INTO transfers (id) VALUES (1), (2), (3);
+		INSERT INTO balances (id) VALUES (1);`)
+	require.NoError(t, err)
+	require.NoError(t, database.Close())
+
+	code, stdout, _ := runIndexerCommand(t, "list", "--db", cfg.Path)
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout, `
+Event tables in `+cfg.Path+`:
+TABLE      ROWS
+approvals  0
+transfers  3
+`)
+
+	code, stdout, _ = runIndexerCommand(t, "list", "--db", cfg.Path, "--json")
+	require.Equal(t, 0, code)
+
+	var output listOutput
+	require.NoError(t, json.Unmarshal([]byte(stdout), &output))
+	require.Equal(t, &listedDatabase{
+		Path:   cfg.Path,
+		Tables: map[string]int64{"transfers": 3, "approvals": 0},
+	}, output.Database)
+
+	code, _, stderr := runIndexerCommand(t, "list", "--db", filepath.Join(t.TempDir(), "missing.sqlite"))
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "failed to open database")
+}
@@ -2,25 +2,32 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	// Import built-in indexers to register them
+	_ "github.com/goran-ethernal/ChainIndexor/examples/indexers/erc1155"
 	_ "github.com/goran-ethernal/ChainIndexor/examples/indexers/erc20"
+	_ "github.com/goran-ethernal/ChainIndexor/examples/indexers/erc721"
+	_ "github.com/goran-ethernal/ChainIndexor/examples/indexers/governance"
+	_ "github.com/goran-ethernal/ChainIndexor/examples/indexers/uniswapv2"
+	"github.com/goran-ethernal/ChainIndexor/internal/abi"
 	"github.com/goran-ethernal/ChainIndexor/internal/common"
 	"github.com/goran-ethernal/ChainIndexor/internal/config"
-	"github.com/goran-ethernal/ChainIndexor/internal/db"
-	"github.com/goran-ethernal/ChainIndexor/internal/downloader"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
-	downloadermig "github.com/goran-ethernal/ChainIndexor/internal/migrations"
-	"github.com/goran-ethernal/ChainIndexor/internal/reorg"
-	"github.com/goran-ethernal/ChainIndexor/internal/rpc"
+	"github.com/goran-ethernal/ChainIndexor/internal/tracing"
 	"github.com/goran-ethernal/ChainIndexor/pkg/api"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/grpc"
 	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -31,15 +38,18 @@ const (
 ║   Blockchain Event Indexing Framework     ║
 ╚═══════════════════════════════════════════╝
 `
+
+	tracingShutdownTimeout = 5 * time.Second
 )
 
 var (
 	configPath string
+	dryRun     bool
 )
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -53,26 +63,10 @@ storage with support for multiple built-in indexers.`,
 	RunE:    runIndexer,
 }
 
-var listCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List available indexer types",
-	Long:  `List all registered indexer types that can be used in the configuration file.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Available indexer types:")
-		types := indexer.ListRegistered()
-		if len(types) == 0 {
-			fmt.Println("  (no indexers registered)")
-			return
-		}
-		for _, t := range types {
-			fmt.Printf("  - %s\n", t)
-		}
-	},
-}
-
 func init() {
 	rootCmd.Flags().StringVarP(&configPath, "config", "c", "config.yaml", "path to configuration file")
-	rootCmd.AddCommand(listCmd)
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"run the startup steps and fetch the logs of the start block once, without storing any data")
 }
 
 func runIndexer(cmd *cobra.Command, args []string) error {
@@ -84,6 +78,11 @@ func runIndexer(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	// Unknown indexer types are reported before connecting to the RPC node or opening any database
+	if err := indexer.ValidateIndexerTypes(cfg); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
 	// Setup context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -100,18 +99,82 @@ func runIndexer(cmd *cobra.Command, args []string) error {
 	// Initialize logger
 	log := logger.NewComponentLoggerFromConfig(common.ComponentDownloader, cfg.Logging)
 
-	// Initialize RPC client
-	log.Info("Connecting to Ethereum node...")
-	ethClient, err := rpc.NewClient(ctx, cfg.Downloader.RPCURL, cfg.Downloader.Retry)
-	if err != nil {
-		return fmt.Errorf("failed to create RPC client: %w", err)
+	chains := cfg.ChainConfigs()
+
+	// Validate configured event signatures against on-chain ABIs if enabled
+	for _, chain := range chains {
+		if chain.Downloader.ValidateABI {
+			log.Info("Validating event signatures against contract ABIs...")
+			validator := abi.NewValidator(chain.Downloader.ABIExplorer, log)
+			if err := validator.ValidateIndexers(ctx, chain.Indexers); err != nil {
+				return fmt.Errorf("event signature validation failed: %w", err)
+			}
+		}
+	}
+
+	log.Infof("Indexing %d chain(s)...", len(chains))
+	stacks := make([]*chainStack, 0, len(chains))
+	reloadTargets := make(map[uint64]config.ReloadTarget, len(chains))
+	defer func() {
+		for _, stack := range stacks {
+			stack.Close()
+		}
+	}()
+
+	for _, chain := range chains {
+		if len(chain.Indexers) == 0 {
+			log.Warn("No indexers configured. Exiting.")
+			return nil
+		}
+
+		stack, err := newChainStack(ctx, cfg, chain, log)
+		if err != nil {
+			return err
+		}
+		stacks = append(stacks, stack)
+		reloadTargets[chain.ChainID] = stack
+	}
+
+	if dryRun {
+		return runDryRun(ctx, cmd.OutOrStdout(), stacks)
 	}
-	log.Infof("Connected to Ethereum node: %s", cfg.Downloader.RPCURL)
+
+	// Initialize tracing if configured, before any chain starts downloading.
+	// Spans of a single chain are all attributed to its ID.
+	if cfg.Tracing != nil {
+		var tracingChainID uint64
+		if len(stacks) == 1 {
+			tracingChainID = stacks[0].chainID
+		}
+
+		shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, tracingChainID)
+		if err != nil {
+			return fmt.Errorf("failed to set up tracing: %w", err)
+		}
+		defer func() {
+			// The main context is already cancelled on shutdown, so flush pending spans with a fresh one
+			shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+			defer cancelShutdown()
+
+			if err := shutdownTracing(shutdownCtx); err != nil {
+				log.Warnf("Failed to shut down tracing: %v", err)
+			}
+		}()
+		log.Infof("Exporting traces to %s over %s as %s (sampling ratio: %v)",
+			cfg.Tracing.Endpoint, cfg.Tracing.Protocol, cfg.Tracing.ServiceName, cfg.Tracing.SamplingRatio)
+	}
+
+	// Reload the config on SIGHUP, applying the changes that do not require a restart
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
+	defer signal.Stop(reloadCh)
+	go config.NewConfigReloader(configPath, cfg, reloadTargets, log).Run(ctx, reloadCh)
 
 	// Initialize metrics server if enabled
 	var metricsServer *metrics.Server
 	if cfg.Metrics != nil && cfg.Metrics.Enabled {
 		metricsServer = metrics.NewServer(cfg.Metrics)
+
 		if err := metricsServer.Start(ctx); err != nil {
 			return fmt.Errorf("failed to start metrics server: %w", err)
 		}
@@ -123,110 +186,147 @@ func runIndexer(cmd *cobra.Command, args []string) error {
 		log.Infof("Metrics server started on %s%s", cfg.Metrics.ListenAddress, cfg.Metrics.Path)
 	}
 
-	// Run downloader migrations
-	log.Info("Running database migrations...")
-	if err := downloadermig.RunMigrations(cfg.Downloader.DB); err != nil {
-		return fmt.Errorf("failed to run migrations: %w", err)
+	// Start API server if enabled
+	if cfg.API != nil && cfg.API.Enabled {
+		apiServer := newAPIServer(cfg, stacks)
+		go func() {
+			if err := apiServer.Start(ctx); err != nil {
+				log.Errorf("API server error: %v", err)
+			}
+		}()
 	}
 
-	// Initialize database
-	database, err := db.NewSQLiteDBFromConfig(cfg.Downloader.DB)
-	if err != nil {
-		return fmt.Errorf("failed to create database: %w", err)
-	}
-
-	// Initialize maintenance coordinator
-	dbMaintenance := db.NewMaintenanceCoordinator(
-		cfg.Downloader.DB.Path,
-		database,
-		cfg.Downloader.Maintenance,
-		logger.NewComponentLoggerFromConfig(common.ComponentMaintenance, cfg.Logging),
-	)
-
-	// Initialize reorg detector
-	reorgDetector, err := reorg.NewReorgDetector(
-		database,
-		ethClient,
-		logger.NewComponentLoggerFromConfig(common.ComponentReorgDetector, cfg.Logging),
-		dbMaintenance,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create reorg detector: %w", err)
+	// Start gRPC server if enabled
+	if cfg.GRPC != nil && cfg.GRPC.Enabled {
+		grpcServer := grpc.NewServer(cfg.GRPC, queryRegistry(stacks),
+			logger.NewComponentLoggerFromConfig(common.ComponentAPI, cfg.Logging))
+		go func() {
+			if err := grpcServer.Start(ctx); err != nil {
+				log.Errorf("gRPC server error: %v", err)
+			}
+		}()
 	}
 
-	// Initialize sync manager
-	syncManager, err := downloader.NewSyncManager(
-		database,
-		logger.NewComponentLoggerFromConfig(common.ComponentSyncManager, cfg.Logging),
-		dbMaintenance,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create sync manager: %w", err)
-	}
-
-	// Initialize downloader
-	dl, err := downloader.New(
-		cfg.Downloader,
-		ethClient,
-		reorgDetector,
-		syncManager,
-		dbMaintenance,
-		logger.NewComponentLoggerFromConfig(common.ComponentDownloader, cfg.Logging),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create downloader: %w", err)
-	}
-	defer dl.Close()
+	// Start indexing
+	log.Info("Starting ChainIndexor...")
+
+	// A failing chain stops the others
+	group, groupCtx := errgroup.WithContext(ctx)
+	for _, stack := range stacks {
+		group.Go(func() error {
+			if err := stack.downloader.Download(groupCtx, stack.cfg); err != nil {
+				return fmt.Errorf("downloader of chain %d failed: %w", stack.chainID, err)
+			}
 
-	// Register indexers from configuration
-	log.Infof("Registering %d indexer(s)...", len(cfg.Indexers))
-	if len(cfg.Indexers) == 0 {
-		log.Warn("No indexers configured. Exiting.")
-		return nil
+			return nil
+		})
 	}
 
-	for i, idxCfg := range cfg.Indexers {
-		if idxCfg.Type == "" {
-			return fmt.Errorf("indexer #%d (%s) is missing 'type' field in configuration", i+1, idxCfg.Name)
+	downloadErr := make(chan error, 1)
+	go func() { downloadErr <- group.Wait() }()
+
+	select {
+	case err := <-downloadErr:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		// The downloaders finish the chunk they are storing and indexing before returning
+		if !drainDownloaders(stacks, log) {
+			log.Warn("ChainIndexor stopped without draining all downloaders")
+			return nil
 		}
 
-		log.Infof("Creating indexer: %s (type: %s)", idxCfg.Name, idxCfg.Type)
+		if err := <-downloadErr; err != nil && !errors.Is(err, context.Canceled) {
+			return err
+		}
+	}
+
+	log.Info("ChainIndexor stopped successfully")
+	return nil
+}
 
-		idx, err := indexer.Create(
-			idxCfg.Type,
-			idxCfg,
-			logger.GetDefaultLogger(),
-		)
+// runDryRun fetches the logs of the start block of every chain once, without storing them, and prints
+// the number of logs found. It checks that the indexer could start, without starting it.
+func runDryRun(ctx context.Context, out io.Writer, stacks []*chainStack) error {
+	for _, stack := range stacks {
+		result, err := stack.downloader.DryRun(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to create indexer %s: %w", idxCfg.Name, err)
+			return fmt.Errorf("dry run of chain %d failed: %w", stack.chainID, err)
 		}
 
-		dl.RegisterIndexer(idx)
-		log.Infof("✓ Registered indexer: %s", idxCfg.Name)
+		fmt.Fprintf(out, "Dry run of chain %d: found %d logs in block %d\n",
+			stack.chainID, len(result.Logs), result.FromBlock)
 	}
 
-	// Start API server if enabled
-	if cfg.API != nil && cfg.API.Enabled {
-		apiServer := api.NewServer(
-			cfg.API,
-			dl.Coordinator(),
-			ethClient,
-			logger.NewComponentLoggerFromConfig(common.ComponentAPI, cfg.Logging),
-		)
-		go func() {
-			if err := apiServer.Start(ctx); err != nil {
-				log.Errorf("API server error: %v", err)
-			}
-		}()
+	fmt.Fprintln(out, "Dry run completed, no data was stored")
+
+	return nil
+}
+
+// drainDownloaders waits for the downloaders of all chains to return after shutdown was requested,
+// up to the shutdown timeout of each chain. It reports whether all of them drained in time.
+func drainDownloaders(stacks []*chainStack, log *logger.Logger) bool {
+	start := time.Now()
+	drained := true
+
+	for _, stack := range stacks {
+		timeout := stack.cfg.Downloader.ShutdownTimeout.Duration
+		drainCtx, cancel := context.WithDeadline(context.Background(), start.Add(timeout))
+
+		if err := stack.downloader.WaitForDrain(drainCtx); err != nil {
+			log.Warnf("Downloader of chain %d did not finish its chunk within %s, "+
+				"it is indexed again on the next start: %v", stack.chainID, timeout, err)
+			drained = false
+		}
+		cancel()
 	}
 
-	// Start indexing
-	log.Info("Starting ChainIndexor...")
+	return drained
+}
 
-	if err := dl.Download(ctx, *cfg); err != nil {
-		return fmt.Errorf("downloader failed: %w", err)
+// queryRegistry returns the registry of the indexers of all chains.
+func queryRegistry(stacks []*chainStack) api.IndexerRegistry {
+	if len(stacks) == 1 {
+		return stacks[0].downloader.Coordinator()
 	}
 
-	log.Info("ChainIndexor stopped successfully")
-	return nil
+	return &chainRouter{stacks: stacks}
+}
+
+// newAPIServer creates the API server serving the indexers of all chains.
+// Retention previews, coverage, snapshots, reorg history and backfill progress are only served for a single chain.
+func newAPIServer(cfg *pkgconfig.Config, stacks []*chainStack) *api.Server {
+	apiLog := logger.NewComponentLoggerFromConfig(common.ComponentAPI, cfg.Logging)
+
+	if len(stacks) == 1 {
+		dl := stacks[0].downloader
+
+		apiServer := api.NewServer(cfg.API, dl.Coordinator(), stacks[0].ethClient, apiLog)
+		apiServer.SetRetentionPreviewer(dl)
+		apiServer.SetLogStore(dl.LogStore())
+		apiServer.SetProgressSource(dl.ProgressBus())
+		apiServer.SetDatabasePinger(dl)
+		apiServer.SetReorgHistorySource(dl)
+		if stacks[0].cfg.Downloader.DB.Driver != pkgconfig.DBDriverPostgres {
+			apiServer.SetDatabaseBackup(dl)
+		}
+		if stacks[0].cfg.Downloader.PendingMode {
+			apiServer.SetPendingEventSource(dl)
+		}
+		dl.Coordinator().SetLogsHandledHook(apiServer.PublishIndexedLogs)
+
+		return apiServer
+	}
+
+	router := &chainRouter{stacks: stacks}
+
+	apiServer := api.NewServer(cfg.API, router, stacks[0].ethClient, apiLog)
+	apiServer.SetPendingEventSource(router)
+	apiServer.SetDatabasePinger(router)
+	for _, stack := range stacks {
+		stack.downloader.Coordinator().SetLogsHandledHook(apiServer.PublishIndexedLogs)
+	}
+
+	return apiServer
 }
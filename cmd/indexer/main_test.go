@@ -0,0 +1,137 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunIndexer_UnknownIndexerType(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	downloaderDB := filepath.Join(dir, "downloader.db")
+
+	// The RPC node is unreachable, so the command only gets past startup if the type is not checked first
+	config := fmt.Sprintf(`
+downloader:
+  rpc_url: "http://127.0.0.1:1"
+  db:
+    path: %q
+indexers:
+  - name: "usdc"
+    type: "erc2O"
+    db:
+      path: %q
+    contracts:
+      - address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+        events: ["Transfer(address,address,uint256)"]
+`, downloaderDB, filepath.Join(dir, "usdc.db"))
+
+	code, _, stderr := runIndexerCommand(t, "--config", writeConfig(t, config))
+	require.NotZero(t, code)
+	require.Contains(t, stderr, "invalid config: indexer[0] (usdc): unknown indexer type 'erc2O', did you mean 'erc20'?")
+	require.NoFileExists(t, downloaderDB)
+}
+
+// fakeEthService serves the eth methods the indexer calls on startup and on a dry run.
+// Every block of the chain has the given logs.
+type fakeEthService struct {
+	chainID uint64
+	logs    []types.Log
+}
+
+func (s *fakeEthService) ChainId() hexutil.Uint64 {
+	return hexutil.Uint64(s.chainID)
+}
+
+func (s *fakeEthService) GetLogs(map[string]any) []types.Log {
+	return s.logs
+}
+
+func (s *fakeEthService) GetBlockByNumber(number hexutil.Uint64, _ bool) *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(uint64(number)), Difficulty: big.NewInt(1)}
+}
+
+func TestRunIndexer_DryRun(t *testing.T) {
+	t.Parallel()
+
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	transfer := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+	node := rpc.NewServer()
+	require.NoError(t, node.RegisterName("eth", &fakeEthService{
+		chainID: 1,
+		logs: []types.Log{{
+			Address:     usdc,
+			Topics:      []common.Hash{transfer, common.HexToHash("0x01"), common.HexToHash("0x02")},
+			Data:        common.LeftPadBytes(big.NewInt(1000).Bytes(), 32),
+			BlockNumber: 100,
+			TxHash:      common.HexToHash("0x03"),
+		}},
+	}))
+	t.Cleanup(node.Stop)
+
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	downloaderDB := filepath.Join(dir, "downloader.db")
+	config := fmt.Sprintf(`
+downloader:
+  rpc_url: %q
+  db:
+    path: %q
+indexers:
+  - name: "usdc"
+    type: "erc20"
+    start_block: 100
+    db:
+      path: %q
+    contracts:
+      - address: %q
+        events: ["Transfer(address,address,uint256)"]
+`, server.URL, downloaderDB, filepath.Join(dir, "usdc.db"), usdc.Hex())
+
+	code, stdout, stderr := runIndexerCommand(t, "--config", writeConfig(t, config), "--dry-run")
+	require.Zero(t, code, stderr)
+	require.Contains(t, stdout, "Dry run of chain 1: found 1 logs in block 100\n")
+	require.Contains(t, stdout, "Dry run completed, no data was stored\n")
+
+	// Neither the logs nor the block were stored
+	dbConfig := pkgconfig.DatabaseConfig{Path: downloaderDB}
+	dbConfig.ApplyDefaults()
+	database, err := db.NewReadOnlySQLiteDBFromConfig(dbConfig)
+	require.NoError(t, err)
+	defer database.Close()
+
+	for _, table := range []string{"event_logs", "log_coverage", "block_hashes"} {
+		require.Zero(t, rowCount(t, database, table), table)
+	}
+
+	var lastIndexedBlock uint64
+	require.NoError(t, database.QueryRow("SELECT last_indexed_block FROM sync_state WHERE id = 1").Scan(&lastIndexedBlock))
+	require.Zero(t, lastIndexedBlock)
+}
+
+// rowCount returns the number of rows of a table.
+func rowCount(t *testing.T, database *sql.DB, table string) int {
+	t.Helper()
+
+	var count int
+	require.NoError(t, database.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&count))
+
+	return count
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/config"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	downloadermig "github.com/goran-ethernal/ChainIndexor/internal/migrations"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var rollbackSteps int
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Manage downloader database migrations",
+}
+
+var migrateRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Revert the last applied downloader database migrations",
+	Long: `Rollback reverts the last N migrations applied to the downloader database of every
+configured chain, e.g. to go back to an older release. All migrations of a database are
+reverted in a single transaction, so a failing migration leaves the database untouched.
+
+Data stored in reverted tables and columns is lost. Back up the databases first.`,
+	Example: `  indexer migrate rollback --config config.yaml --steps 1`,
+	RunE:    runMigrateRollback,
+}
+
+func init() {
+	migrateRollbackCmd.Flags().StringVarP(&configPath, "config", "c", "config.yaml", "path to configuration file")
+	migrateRollbackCmd.Flags().IntVar(&rollbackSteps, "steps", 1, "number of migrations to revert")
+	migrateCmd.AddCommand(migrateRollbackCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
+
+func runMigrateRollback(cmd *cobra.Command, args []string) error {
+	if rollbackSteps <= 0 {
+		return fmt.Errorf("invalid --steps %d: must be greater than 0", rollbackSteps)
+	}
+
+	cfg, err := config.LoadFromFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log := logger.NewComponentLoggerFromConfig(common.ComponentDownloader, cfg.Logging)
+
+	for _, chain := range cfg.ChainConfigs() {
+		name := "downloader database"
+		if chain.ChainID != 0 {
+			name = fmt.Sprintf("downloader database of chain %d", chain.ChainID)
+		}
+
+		if err := rollbackDatabase(name, cfg.ForChain(chain).Downloader.DB, rollbackSteps, log); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rollbackDatabase reverts the last steps migrations applied to a downloader database.
+func rollbackDatabase(name string, dbConfig pkgconfig.DatabaseConfig, steps int, log *logger.Logger) error {
+	database, err := db.NewDBFromConfig(dbConfig)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer database.Close()
+
+	version, err := downloadermig.Version(database)
+	if err != nil {
+		return fmt.Errorf("failed to get migration version of %s: %w", name, err)
+	}
+
+	if steps > version {
+		return fmt.Errorf("cannot revert %d migrations of %s, only %d are applied", steps, name, version)
+	}
+
+	log.Infof("Rolling back %s from migration version %d to %d...", name, version, version-steps)
+	if err := downloadermig.RollbackTo(database, version-steps); err != nil {
+		return fmt.Errorf("failed to roll back %s: %w", name, err)
+	}
+	log.Infof("✓ Rolled back %s", name)
+
+	return nil
+}
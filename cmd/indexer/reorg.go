@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/config"
+	"github.com/goran-ethernal/ChainIndexor/internal/downloader"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reorgFromBlock uint64
+	reorgDepth     uint64
+	reorgChainID   uint64
+	reorgConfirm   bool
+)
+
+var simulateReorgCmd = &cobra.Command{
+	Use:   "simulate-reorg",
+	Short: "Roll back and re-index a block range to test reorg recovery",
+	Long: `Simulate-reorg treats the indexed blocks [from-block, from-block+depth) as reorged and
+recovers from it as the indexer recovers from a detected reorg: the logs, block hashes and
+events from from-block on are deleted from the live databases, and the blocks are fetched
+again from the RPC and handed to the indexers. It prints how many events were deleted and
+re-added, which match if the indexers handle reorgs correctly and the chain did not change.
+
+Blocks indexed after the reorged range are rolled back too, and indexed again by the next
+run of the indexer. The indexer must not be running while the command runs. As it modifies
+the live databases, the command only runs with --confirm.`,
+	Example: `  indexer simulate-reorg --config config.yaml --from-block 19500000 --depth 10 --confirm`,
+	RunE:    runSimulateReorg,
+}
+
+func init() {
+	simulateReorgCmd.Flags().StringVarP(&configPath, "config", "c", "config.yaml", "path to configuration file")
+	simulateReorgCmd.Flags().Uint64Var(&reorgFromBlock, "from-block", 0, "first block of the simulated reorg")
+	simulateReorgCmd.Flags().Uint64Var(&reorgDepth, "depth", 0, "number of reorged blocks")
+	simulateReorgCmd.Flags().Uint64Var(&reorgChainID, "chain-id", 0,
+		"chain to simulate the reorg on, required if several chains are configured")
+	simulateReorgCmd.Flags().BoolVar(&reorgConfirm, "confirm", false,
+		"confirm that the indexed data of the blocks may be deleted and re-indexed")
+	_ = simulateReorgCmd.MarkFlagRequired("from-block")
+	_ = simulateReorgCmd.MarkFlagRequired("depth")
+	rootCmd.AddCommand(simulateReorgCmd)
+}
+
+func runSimulateReorg(cmd *cobra.Command, args []string) error {
+	if !reorgConfirm {
+		return errors.New("simulate-reorg deletes and re-indexes data of the live databases, " +
+			"run it again with --confirm to proceed")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.LoadFromFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	chain, err := reorgChain(cfg.ChainConfigs(), reorgChainID)
+	if err != nil {
+		return err
+	}
+
+	log := logger.NewComponentLoggerFromConfig(common.ComponentDownloader, cfg.Logging)
+
+	stack, err := newChainStack(ctx, cfg, chain, log)
+	if err != nil {
+		return err
+	}
+	defer stack.Close()
+
+	simulation, err := stack.downloader.SimulateReorg(ctx, stack.cfg, reorgFromBlock, reorgDepth)
+	if err != nil {
+		return fmt.Errorf("failed to simulate reorg: %w", err)
+	}
+
+	printReorgSimulation(cmd.OutOrStdout(), simulation)
+
+	return nil
+}
+
+// reorgChain returns the configured chain with the given ID. Without an ID, there must be a single chain.
+func reorgChain(chains []pkgconfig.ChainConfig, chainID uint64) (pkgconfig.ChainConfig, error) {
+	if chainID == 0 {
+		if len(chains) > 1 {
+			return pkgconfig.ChainConfig{}, errors.New("several chains are configured, select one with --chain-id")
+		}
+
+		return chains[0], nil
+	}
+
+	for _, chain := range chains {
+		if chain.ChainID == chainID {
+			return chain, nil
+		}
+	}
+
+	return pkgconfig.ChainConfig{}, fmt.Errorf("chain %d is not configured", chainID)
+}
+
+// printReorgSimulation prints the summary of a simulated reorg.
+func printReorgSimulation(out io.Writer, simulation *downloader.ReorgSimulation) {
+	fmt.Fprintf(out, "Reorg simulation of blocks %d-%d:\n", simulation.FromBlock, simulation.ToBlock)
+	fmt.Fprintf(out, "  Blocks re-indexed: %d\n", simulation.BlocksReindexed)
+	fmt.Fprintf(out, "  Events deleted:    %d\n", simulation.EventsDeleted)
+	fmt.Fprintf(out, "  Events re-added:   %d\n", simulation.EventsReadded)
+	fmt.Fprintf(out, "  Duration:          %s\n", simulation.Duration.Round(time.Millisecond))
+	if simulation.BlocksToCatchUp > 0 {
+		fmt.Fprintf(out, "  The next run resumes from block %d and re-indexes the %d blocks after it\n",
+			simulation.ResumeBlock, simulation.BlocksToCatchUp)
+	}
+}
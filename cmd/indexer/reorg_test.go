@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/downloader"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorgChain(t *testing.T) {
+	t.Parallel()
+
+	mainnet := pkgconfig.ChainConfig{ChainID: 1}
+	sepolia := pkgconfig.ChainConfig{ChainID: 11155111}
+
+	chain, err := reorgChain([]pkgconfig.ChainConfig{mainnet}, 0)
+	require.NoError(t, err)
+	require.Equal(t, mainnet, chain)
+
+	chain, err = reorgChain([]pkgconfig.ChainConfig{mainnet, sepolia}, 11155111)
+	require.NoError(t, err)
+	require.Equal(t, sepolia, chain)
+
+	_, err = reorgChain([]pkgconfig.ChainConfig{mainnet, sepolia}, 0)
+	require.EqualError(t, err, "several chains are configured, select one with --chain-id")
+
+	_, err = reorgChain([]pkgconfig.ChainConfig{mainnet}, 10)
+	require.EqualError(t, err, "chain 10 is not configured")
+}
+
+func TestPrintReorgSimulation(t *testing.T) {
+	t.Parallel()
+
+	simulation := &downloader.ReorgSimulation{
+		FromBlock:       100,
+		ToBlock:         109,
+		BlocksReindexed: 10,
+		EventsDeleted:   42,
+		EventsReadded:   42,
+		ResumeBlock:     109,
+		Duration:        1234567 * time.Microsecond,
+	}
+
+	var out bytes.Buffer
+	printReorgSimulation(&out, simulation)
+	require.Equal(t, `Reorg simulation of blocks 100-109:
+  Blocks re-indexed: 10
+  Events deleted:    42
+  Events re-added:   42
+  Duration:          1.235s
+`, out.String())
+
+	simulation.BlocksToCatchUp = 5
+	out.Reset()
+	printReorgSimulation(&out, simulation)
+	require.Contains(t, out.String(), "The next run resumes from block 109 and re-indexes the 5 blocks after it")
+}
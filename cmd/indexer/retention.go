@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/config"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/fetcher/store"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	simulateMaxBlocks   uint64
+	simulateMaxDBSizeMB uint64
+)
+
+var simulateRetentionCmd = &cobra.Command{
+	Use:   "simulate-retention",
+	Short: "Preview what a retention policy would prune from the downloader databases",
+	Long: `Simulate-retention calculates which blocks the retention policy would prune from the
+downloader database of every configured chain, and how much space it would free, without
+deleting anything. The policy is the retention_policy of the config file, unless it is
+overridden with --max-blocks or --max-db-size-mb.`,
+	Example: `  indexer simulate-retention --config config.yaml
+  indexer simulate-retention --config config.yaml --max-blocks 100000`,
+	RunE: runSimulateRetention,
+}
+
+func init() {
+	simulateRetentionCmd.Flags().StringVarP(&configPath, "config", "c", "config.yaml", "path to configuration file")
+	simulateRetentionCmd.Flags().Uint64Var(&simulateMaxBlocks, "max-blocks", 0,
+		"maximum number of blocks to retain, overrides the configured policy")
+	simulateRetentionCmd.Flags().Uint64Var(&simulateMaxDBSizeMB, "max-db-size-mb", 0,
+		"maximum database size in MB, overrides the configured policy")
+	rootCmd.AddCommand(simulateRetentionCmd)
+}
+
+func runSimulateRetention(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.LoadFromFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log := logger.NewComponentLoggerFromConfig(common.ComponentLogStore, cfg.Logging)
+
+	for _, chain := range cfg.ChainConfigs() {
+		name := "downloader database"
+		if chain.ChainID != 0 {
+			name = fmt.Sprintf("downloader database of chain %d", chain.ChainID)
+		}
+
+		downloaderCfg := cfg.ForChain(chain).Downloader
+
+		var policy pkgconfig.RetentionPolicyConfig
+		if cmd.Flags().Changed("max-blocks") || cmd.Flags().Changed("max-db-size-mb") {
+			policy = pkgconfig.RetentionPolicyConfig{MaxBlocks: simulateMaxBlocks, MaxDBSizeMB: simulateMaxDBSizeMB}
+		} else if downloaderCfg.RetentionPolicy != nil {
+			policy = *downloaderCfg.RetentionPolicy
+		}
+
+		if !policy.IsEnabled() {
+			fmt.Fprintf(cmd.OutOrStdout(), "No retention policy configured for the %s\n", name)
+			continue
+		}
+
+		if err := simulateRetention(ctx, cmd.OutOrStdout(), name, downloaderCfg.DB, policy, log); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// simulateRetention prints what the retention policy would prune from a downloader database.
+func simulateRetention(
+	ctx context.Context,
+	out io.Writer,
+	name string,
+	dbConfig pkgconfig.DatabaseConfig,
+	policy pkgconfig.RetentionPolicyConfig,
+	log *logger.Logger,
+) error {
+	database, err := db.NewDBFromConfig(dbConfig)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer database.Close()
+
+	var logStore *store.LogStore
+	if dbConfig.Driver == pkgconfig.DBDriverPostgres {
+		logStore = store.NewPostgresLogStore(database, log, dbConfig, nil, &db.NoOpMaintenance{}).LogStore
+	} else {
+		logStore = store.NewLogStore(database, log, dbConfig, nil, &db.NoOpMaintenance{})
+	}
+
+	simulation, err := logStore.SimulateRetention(ctx, policy)
+	if err != nil {
+		return fmt.Errorf("failed to simulate retention of %s: %w", name, err)
+	}
+
+	fmt.Fprintf(out, "Retention simulation for the %s (max_blocks: %d, max_db_size_mb: %d):\n",
+		name, policy.MaxBlocks, policy.MaxDBSizeMB)
+	if simulation.PruneBeforeBlock == 0 {
+		fmt.Fprintln(out, "  Nothing would be pruned")
+	} else {
+		fmt.Fprintf(out, "  Prune before block: %d\n", simulation.PruneBeforeBlock)
+		fmt.Fprintf(out, "  Logs deleted:       %d\n", simulation.EstimatedLogsDeleted)
+		fmt.Fprintf(out, "  Space freed:        ~%.2f MB\n", simulation.EstimatedMBFreed)
+	}
+	fmt.Fprintf(out, "  Blocks retained:    %d-%d\n", simulation.BlockRangeRetained[0], simulation.BlockRangeRetained[1])
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/config"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/spf13/cobra"
+)
+
+// exitCodeInvalidConfig is the exit code of the validate command for an invalid config file
+const exitCodeInvalidConfig = 2
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate a config file without starting the indexer",
+	Long: `Validate loads the config file, checks it against the config schema and the validation
+rules, and checks that every indexer type is registered, without connecting to the RPC node or
+opening any database. It prints the values the defaults fill in or change, then "Config is valid".
+
+If the config is invalid, every error is printed on its own line and the command exits with
+status 2.`,
+	Example:      `  indexer validate --config config.yaml`,
+	SilenceUsage: true,
+	// Errors are printed by the command itself, one per line
+	SilenceErrors: true,
+	RunE:          runValidate,
+}
+
+func init() {
+	validateCmd.Flags().StringVarP(&configPath, "config", "c", "config.yaml", "path to configuration file")
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	errOut := cmd.ErrOrStderr()
+
+	raw, err := config.LoadRawFromFile(configPath)
+	if err != nil {
+		return invalidConfig(errOut, err)
+	}
+
+	// Defaults are applied to a fresh copy of the config, the raw values are kept for the diff
+	cfg, err := config.LoadFromFile(configPath)
+	if err != nil {
+		return invalidConfig(errOut, err)
+	}
+
+	if err := indexer.ValidateIndexerTypes(cfg); err != nil {
+		return invalidConfig(errOut, err)
+	}
+
+	out := cmd.OutOrStdout()
+
+	if defaults := defaultsDiff(raw, cfg); len(defaults) > 0 {
+		fmt.Fprintln(out, "Effective values set by defaults:")
+		for _, line := range defaults {
+			fmt.Fprintf(out, "  %s\n", line)
+		}
+	}
+
+	fmt.Fprintln(out, "Config is valid")
+
+	return nil
+}
+
+// exitCodeError makes the process exit with the given code.
+// The command that returns it has already reported the error.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// invalidConfig prints every validation error on its own line and returns an error exiting with status 2.
+func invalidConfig(out io.Writer, err error) error {
+	fmt.Fprintln(out, "Config is invalid:")
+	for _, line := range validationErrors(err) {
+		fmt.Fprintf(out, "  %s\n", line)
+	}
+
+	return &exitCodeError{code: exitCodeInvalidConfig, err: err}
+}
+
+// validationErrors splits an error, which can join several validation errors, into one line per error.
+func validationErrors(err error) []string {
+	lines := make([]string, 0)
+	for line := range strings.SplitSeq(err.Error(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// defaultsDiff lists the values of the effective config that differ from the values of the config file,
+// as "path: raw -> effective" lines sorted by path. Values missing from the file are shown as <unset>.
+func defaultsDiff(raw, effective *pkgconfig.Config) []string {
+	rawValues := make(map[string]string)
+	flattenValue("", reflect.ValueOf(raw), rawValues)
+	effectiveValues := make(map[string]string)
+	flattenValue("", reflect.ValueOf(effective), effectiveValues)
+
+	lines := make([]string, 0)
+	for _, path := range slices.Sorted(maps.Keys(effectiveValues)) {
+		value := effectiveValues[path]
+		rawValue, ok := rawValues[path]
+		if ok && rawValue == value {
+			continue
+		}
+
+		if !ok {
+			rawValue = "<unset>"
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s -> %s", path, rawValue, value))
+	}
+
+	return lines
+}
+
+// flattenValue adds the values under the given path to values, keyed by their path in the config
+// file, e.g. "downloader.db.path" or "indexers[0].start_block". Unset values are left out.
+func flattenValue(path string, v reflect.Value, values map[string]string) {
+	if !v.IsValid() || v.IsZero() {
+		return
+	}
+
+	// Values that marshal to text, like durations, are single values
+	if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			values[path] = string(text)
+			return
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		flattenValue(path, v.Elem(), values)
+	case reflect.Struct:
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			flattenValue(name, v.Field(i), values)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			flattenValue(fmt.Sprintf("%s[%d]", path, i), v.Index(i), values)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			flattenValue(fmt.Sprintf("%s.%v", path, key), v.MapIndex(key), values)
+		}
+	default:
+		values[path] = fmt.Sprint(v.Interface())
+	}
+}
+
+// exitCode returns the exit code for an error returned by a command.
+func exitCode(err error) int {
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	return 1
+}
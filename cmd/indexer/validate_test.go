@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// runMainEnv makes the test binary run the indexer command instead of the tests
+const runMainEnv = "INDEXER_TEST_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) == "1" {
+		main()
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// runIndexerCommand runs the indexer command with the given arguments in a separate process
+// and returns its exit code, stdout and stderr.
+func runIndexerCommand(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), runMainEnv+"=1")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), stdout.String(), stderr.String()
+	}
+	require.NoError(t, err)
+
+	return 0, stdout.String(), stderr.String()
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestValidateCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		config         string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{
+			name: "valid config",
+			config: `
+downloader:
+  rpc_url: "http://localhost:8545"
+  chunk_size: 1000
+  finality: "finalized"
+  finalized_lag: 0
+  poll_interval: "5s"
+  max_concurrent_gap_fills: 4
+  log_progress_every: 500
+  header_cache_size: 64
+  shutdown_timeout: "20s"
+  db:
+    path: "./data/downloader.db"
+    driver: sqlite
+    journal_mode: WAL
+    synchronous: NORMAL
+    busy_timeout: 5000
+    cache_size: 10000
+    max_open_connections: 25
+    max_idle_connections: 5
+indexers:
+  - name: "usdc"
+    type: "erc20"
+    start_block: 100
+    db:
+      path: "./data/usdc.db"
+    contracts:
+      - address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+        events:
+          - "Transfer(address,address,uint256)"
+`,
+			expectedStdout: "Effective values set by defaults:\n" +
+				"  downloader.auto_recovery: <unset> -> true\n" +
+				"  downloader.max_auto_recovery_depth: <unset> -> 64\n" +
+				"  downloader.max_auto_reorg_recoveries: <unset> -> 3\n" +
+				"  indexers[0].db.busy_timeout: <unset> -> 5000\n" +
+				"  indexers[0].db.cache_size: <unset> -> 10000\n" +
+				"  indexers[0].db.driver: <unset> -> sqlite\n" +
+				"  indexers[0].db.journal_mode: <unset> -> WAL\n" +
+				"  indexers[0].db.max_idle_connections: <unset> -> 5\n" +
+				"  indexers[0].db.max_open_connections: <unset> -> 25\n" +
+				"  indexers[0].db.synchronous: <unset> -> NORMAL\n" +
+				"  indexers[0].max_offset: <unset> -> 100000\n" +
+				"Config is valid\n",
+		},
+		{
+			name: "unknown fields",
+			config: `
+downloader:
+  rpc_url: "http://localhost:8545"
+  chunk_sise: 1000
+  db:
+    path: "./data/downloader.db"
+indexers:
+  - name: "usdc"
+    type: "erc20"
+    db:
+      path: "./data/usdc.db"
+      jurnal_mode: "WAL"
+    contracts:
+      - address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+        events: ["Transfer(address,address,uint256)"]
+`,
+			expectedCode: exitCodeInvalidConfig,
+			expectedStderr: "Config is invalid:\n" +
+				"  config file does not match schema: downloader.chunk_sise: unknown field\n" +
+				"  indexers.0.db.jurnal_mode: unknown field\n",
+		},
+		{
+			name: "invalid values",
+			config: `
+downloader:
+  db:
+    path: "./data/downloader.db"
+indexers:
+  - name: "usdc"
+    type: "erc20"
+    db:
+      path: "./data/usdc.db"
+    contracts:
+      - address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+        events: ["Transfer(address,address,uint256)"]
+`,
+			expectedCode: exitCodeInvalidConfig,
+			expectedStderr: "Config is invalid:\n" +
+				"  invalid configuration: downloader.rpc_url is required\n",
+		},
+		{
+			name: "unregistered indexer types",
+			config: `
+downloader:
+  rpc_url: "http://localhost:8545"
+  db:
+    path: "./data/downloader.db"
+indexers:
+  - name: "pairs"
+    type: "uniswap-v2"
+    db:
+      path: "./data/pairs.db"
+    contracts:
+      - address: "0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f"
+        events: ["PairCreated(address,address,address,uint256)"]
+  - name: "usdc"
+    type: "erc20"
+    db:
+      path: "./data/usdc.db"
+    contracts:
+      - address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+        events: ["Transfer(address,address,uint256)"]
+  - name: "punks"
+    type: "erc712"
+    db:
+      path: "./data/punks.db"
+    contracts:
+      - address: "0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB"
+        events: ["Transfer(address,address,uint256)"]
+  - name: "bonds"
+    type: "erc3475"
+    db:
+      path: "./data/bonds.db"
+    contracts:
+      - address: "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"
+        events: ["Issue(address,address,uint256)"]
+`,
+			expectedCode: exitCodeInvalidConfig,
+			expectedStderr: "Config is invalid:\n" +
+				"  indexer[0] (pairs): unknown indexer type 'uniswap-v2', did you mean 'uniswap_v2'? " +
+				"(registered types: erc1155, erc20, erc721, governance, uniswap_v2)\n" +
+				"  indexer[2] (punks): unknown indexer type 'erc712', did you mean 'erc721'? " +
+				"(registered types: erc1155, erc20, erc721, governance, uniswap_v2)\n" +
+				"  indexer[3] (bonds): unknown indexer type 'erc3475' " +
+				"(registered types: erc1155, erc20, erc721, governance, uniswap_v2)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			code, stdout, stderr := runIndexerCommand(t, "validate", "--config", writeConfig(t, tt.config))
+			require.Equal(t, tt.expectedCode, code, stderr)
+			require.Equal(t, tt.expectedStdout, stdout)
+			require.Equal(t, tt.expectedStderr, stderr)
+		})
+	}
+}
+
+func TestValidateCommand_ExampleConfigs(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"config.example.yaml", "config.example.json", "config.example.toml"} {
+		code, stdout, stderr := runIndexerCommand(t, "validate", "--config", filepath.Join("..", "..", name))
+		require.Zero(t, code, stderr)
+		require.Contains(t, stdout, "Config is valid", name)
+	}
+}
@@ -0,0 +1,89 @@
+package erc1155
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/indexer"
+	pkgindexer "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+)
+
+// InitEventMetadata returns metadata for all indexed events.
+func (idx *ERC1155Indexer) InitEventMetadata() map[string]*indexer.EventMetadata {
+	return map[string]*indexer.EventMetadata{
+		"transfer": {
+			Name:      "Transfer",
+			Table:     "transfers",
+			EventType: reflect.TypeOf((*Transfer)(nil)),
+			AddressColumns: []string{
+				"operator",
+				"from_address",
+				"to_address",
+			},
+			NumericColumns: []string{
+				"token_id",
+				"value",
+			},
+		},
+	}
+}
+
+// Ensure ERC1155Indexer implements pkgindexer.Queryable, pkgindexer.Exportable and pkgindexer.DistinctQueryable
+var (
+	_ pkgindexer.Queryable         = (*ERC1155Indexer)(nil)
+	_ pkgindexer.Exportable        = (*ERC1155Indexer)(nil)
+	_ pkgindexer.DistinctQueryable = (*ERC1155Indexer)(nil)
+)
+
+// QueryEvents retrieves events based on the provided query parameters.
+func (idx *ERC1155Indexer) QueryEvents(ctx context.Context, params pkgindexer.QueryParams) (any, int, error) {
+	return idx.BaseIndexer.QueryEvents(ctx, idx, params)
+}
+
+// ExportEvents calls fn for every event matching the query parameters, in block and log index order.
+func (idx *ERC1155Indexer) ExportEvents(ctx context.Context, params pkgindexer.QueryParams, maxRows uint64, fn func(event any) error) error {
+	return idx.BaseIndexer.ExportEvents(ctx, idx, params, maxRows, fn)
+}
+
+// QueryDistinctValues returns the distinct values of an event field matching the parameters.
+func (idx *ERC1155Indexer) QueryDistinctValues(ctx context.Context, params pkgindexer.DistinctParams) ([]any, error) {
+	return idx.BaseIndexer.QueryDistinctValues(ctx, idx, params)
+}
+
+// GetStats returns statistics about the indexed data.
+func (idx *ERC1155Indexer) GetStats(ctx context.Context) (pkgindexer.StatsResponse, error) {
+	return idx.BaseIndexer.GetStats(ctx, idx)
+}
+
+// GetEventTypes returns the list of event type names this indexer handles.
+func (idx *ERC1155Indexer) GetEventTypes() []string {
+	return idx.BaseIndexer.GetEventTypes(idx)
+}
+
+// GetEventSchema returns the field schema of every event this indexer handles.
+func (idx *ERC1155Indexer) GetEventSchema() []pkgindexer.EventSchema {
+	return idx.BaseIndexer.GetEventSchema(idx)
+}
+
+// QueryEventsTimeseries retrieves time-series aggregated event data.
+func (idx *ERC1155Indexer) QueryEventsTimeseries(
+	ctx context.Context,
+	params pkgindexer.TimeseriesParams,
+) ([]pkgindexer.TimeseriesDataPoint, error) {
+	return idx.BaseIndexer.QueryEventsTimeseries(ctx, idx, params)
+}
+
+// GetMetrics returns performance and processing metrics.
+func (idx *ERC1155Indexer) GetMetrics(ctx context.Context) (pkgindexer.MetricsResponse, error) {
+	return idx.BaseIndexer.GetMetrics(ctx, idx)
+}
+
+// QueryFirstEvent retrieves the earliest indexed event of the given type.
+func (idx *ERC1155Indexer) QueryFirstEvent(ctx context.Context, eventType string) (any, error) {
+	return idx.BaseIndexer.QueryFirstEvent(ctx, idx, eventType)
+}
+
+// QueryLastEvent retrieves the most recently indexed event of the given type.
+func (idx *ERC1155Indexer) QueryLastEvent(ctx context.Context, eventType string) (any, error) {
+	return idx.BaseIndexer.QueryLastEvent(ctx, idx, eventType)
+}
@@ -0,0 +1,291 @@
+package erc1155
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	gethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goran-ethernal/ChainIndexor/examples/indexers/erc1155/migrations"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/indexer"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	pkgindexer "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/russross/meddler"
+)
+
+// transferTopics is the number of topics of both transfer events: the signature, operator, from and to.
+const transferTopics = 4
+
+// eventsABIJSON declares the indexed events, used to ABI-decode their non-indexed parameters.
+const eventsABIJSON = `[
+	{"type": "event", "name": "TransferSingle", "inputs": [
+		{"name": "operator", "type": "address", "indexed": true},
+		{"name": "from", "type": "address", "indexed": true},
+		{"name": "to", "type": "address", "indexed": true},
+		{"name": "id", "type": "uint256", "indexed": false},
+		{"name": "value", "type": "uint256", "indexed": false}
+	]},
+	{"type": "event", "name": "TransferBatch", "inputs": [
+		{"name": "operator", "type": "address", "indexed": true},
+		{"name": "from", "type": "address", "indexed": true},
+		{"name": "to", "type": "address", "indexed": true},
+		{"name": "ids", "type": "uint256[]", "indexed": false},
+		{"name": "values", "type": "uint256[]", "indexed": false}
+	]}
+]`
+
+// Compile-time check to ensure ERC1155Indexer implements pkgindexer.Indexer interface.
+var _ pkgindexer.Indexer = (*ERC1155Indexer)(nil)
+
+// ERC1155Indexer indexes ERC1155 transfers. TransferSingle and TransferBatch events are stored
+// in the same table, with one row per transferred token id.
+type ERC1155Indexer struct {
+	*indexer.BaseIndexer
+	cfg config.IndexerConfig
+	log *logger.Logger
+
+	// Map of contract addresses to event topic hashes
+	eventsToIndex map[common.Address]map[common.Hash]struct{}
+
+	// ABI of the indexed events
+	eventsABI gethabi.ABI
+
+	// Event signature hashes for quick lookup
+	transferSingleTopic common.Hash
+	transferBatchTopic  common.Hash
+}
+
+// NewERC1155Indexer creates a new ERC1155 indexer.
+func NewERC1155Indexer(cfg config.IndexerConfig, log *logger.Logger) (*ERC1155Indexer, error) {
+	eventsABI, err := gethabi.JSON(strings.NewReader(eventsABIJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse events ABI: %w", err)
+	}
+
+	// Run migrations to set up the database schema
+	if err := migrations.RunMigrations(cfg.DB); err != nil {
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	// Create database connection from config
+	database, err := db.NewSQLiteDBFromConfig(cfg.DB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database: %w", err)
+	}
+
+	// Build the events to index map
+	eventsToIndex := make(map[common.Address]map[common.Hash]struct{})
+
+	for _, contract := range cfg.Contracts {
+		topics := make(map[common.Hash]struct{})
+
+		for _, eventSig := range contract.Events {
+			topic := crypto.Keccak256Hash([]byte(eventSig))
+			topics[topic] = struct{}{}
+		}
+
+		// Parse contract address from string
+		address := common.HexToAddress(contract.Address)
+		eventsToIndex[address] = topics
+	}
+
+	return &ERC1155Indexer{
+		BaseIndexer:         indexer.NewBaseIndexer(database, log, cfg),
+		cfg:                 cfg,
+		log:                 log,
+		eventsToIndex:       eventsToIndex,
+		eventsABI:           eventsABI,
+		transferSingleTopic: eventsABI.Events["TransferSingle"].ID,
+		transferBatchTopic:  eventsABI.Events["TransferBatch"].ID,
+	}, nil
+}
+
+// GetType returns the type identifier of the indexer.
+func (idx *ERC1155Indexer) GetType() string {
+	return "erc1155"
+}
+
+// GetName returns the configured name of the indexer instance.
+func (idx *ERC1155Indexer) GetName() string {
+	return idx.BaseIndexer.GetName()
+}
+
+// EventsToIndex returns the map of contract addresses to event topic hashes.
+func (idx *ERC1155Indexer) EventsToIndex() map[common.Address]map[common.Hash]struct{} {
+	return idx.eventsToIndex
+}
+
+// StartBlock returns the block number from which this indexer should start.
+func (idx *ERC1155Indexer) StartBlock() uint64 {
+	return idx.BaseIndexer.StartBlock()
+}
+
+// Close closes the database connection.
+func (idx *ERC1155Indexer) Close() error {
+	return idx.BaseIndexer.Close()
+}
+
+// Ping checks that the indexer's database is reachable.
+func (idx *ERC1155Indexer) Ping(ctx context.Context) error {
+	return idx.BaseIndexer.Ping(ctx)
+}
+
+// HandleReorg handles a blockchain reorganization by removing data from the reorg point.
+func (idx *ERC1155Indexer) HandleReorg(blockNum uint64) error {
+	return idx.BaseIndexer.HandleReorg(idx, blockNum)
+}
+
+// HandleLogs processes a batch of logs and stores a transfer row per transferred token id.
+func (idx *ERC1155Indexer) HandleLogs(logs []types.Log) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	tx, err := idx.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			idx.log.Errorf("failed to rollback transaction: %v", err)
+		}
+	}()
+
+	transferCount := 0
+
+	for _, log := range logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+
+		var (
+			transfers []*Transfer
+			err       error
+		)
+
+		switch log.Topics[0] {
+		case idx.transferSingleTopic:
+			transfers, err = idx.parseTransferSingle(&log)
+		case idx.transferBatchTopic:
+			transfers, err = idx.parseTransferBatch(&log)
+		default:
+			continue
+		}
+
+		if err != nil {
+			idx.log.Warnf("failed to parse transfer event at block %d, tx %s: %v",
+				log.BlockNumber, log.TxHash.Hex(), err)
+			continue
+		}
+
+		for _, transfer := range transfers {
+			if err := meddler.Insert(tx, "transfers", transfer); err != nil {
+				return fmt.Errorf("failed to insert transfer: %w", err)
+			}
+		}
+		transferCount += len(transfers)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Cached query results covering the stored blocks are out of date
+	idx.InvalidateQueryCache(logs)
+
+	idx.log.Infof("Indexed %d transfers", transferCount)
+
+	return nil
+}
+
+// parseTransferSingle parses a TransferSingle event from a log into a single transfer.
+// Event signature: TransferSingle(address indexed operator, address indexed from, address indexed to,
+// uint256 id, uint256 value)
+func (idx *ERC1155Indexer) parseTransferSingle(log *types.Log) ([]*Transfer, error) {
+	unpacked, err := idx.unpackTransfer(log, "TransferSingle")
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := indexer.ABIValue[string](unpacked, "id")
+	if err != nil {
+		return nil, fmt.Errorf("invalid TransferSingle event: %w", err)
+	}
+
+	value, err := indexer.ABIValue[string](unpacked, "value")
+	if err != nil {
+		return nil, fmt.Errorf("invalid TransferSingle event: %w", err)
+	}
+
+	return []*Transfer{newTransfer(log, 0, id, value)}, nil
+}
+
+// parseTransferBatch parses a TransferBatch event from a log into a transfer per token id.
+// Event signature: TransferBatch(address indexed operator, address indexed from, address indexed to,
+// uint256[] ids, uint256[] values)
+func (idx *ERC1155Indexer) parseTransferBatch(log *types.Log) ([]*Transfer, error) {
+	unpacked, err := idx.unpackTransfer(log, "TransferBatch")
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := indexer.ABIValue[[]string](unpacked, "ids")
+	if err != nil {
+		return nil, fmt.Errorf("invalid TransferBatch event: %w", err)
+	}
+
+	values, err := indexer.ABIValue[[]string](unpacked, "values")
+	if err != nil {
+		return nil, fmt.Errorf("invalid TransferBatch event: %w", err)
+	}
+
+	if len(ids) != len(values) {
+		return nil, fmt.Errorf("invalid TransferBatch event: got %d ids and %d values", len(ids), len(values))
+	}
+
+	transfers := make([]*Transfer, len(ids))
+	for i := range ids {
+		transfers[i] = newTransfer(log, uint(i), ids[i], values[i])
+	}
+
+	return transfers, nil
+}
+
+// unpackTransfer checks the topics of a transfer event and ABI-decodes its data.
+func (idx *ERC1155Indexer) unpackTransfer(log *types.Log, eventName string) (map[string]any, error) {
+	if len(log.Topics) != transferTopics {
+		return nil, fmt.Errorf("invalid %s event: expected %d topics, got %d",
+			eventName, transferTopics, len(log.Topics))
+	}
+
+	unpacked := make(map[string]any)
+	if err := idx.eventsABI.Events[eventName].Inputs.UnpackIntoMap(unpacked, log.Data); err != nil {
+		return nil, fmt.Errorf("invalid %s event: failed to decode data: %w", eventName, err)
+	}
+
+	return unpacked, nil
+}
+
+// newTransfer creates the transfer of a token id from the topics of a transfer event.
+func newTransfer(log *types.Log, batchIndex uint, id, value string) *Transfer {
+	return &Transfer{
+		BlockNumber: log.BlockNumber,
+		BlockHash:   log.BlockHash,
+		TxHash:      log.TxHash,
+		TxIndex:     log.TxIndex,
+		LogIndex:    log.Index,
+		BatchIndex:  batchIndex,
+		Operator:    common.BytesToAddress(log.Topics[1].Bytes()),
+		From:        common.BytesToAddress(log.Topics[2].Bytes()),
+		To:          common.BytesToAddress(log.Topics[3].Bytes()),
+		TokenID:     id,
+		Value:       value,
+	}
+}
@@ -0,0 +1,195 @@
+package erc1155
+
+import (
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	pkgindexer "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/russross/meddler"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	transferSingleTopic = common.HexToHash("0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62")
+	transferBatchTopic  = common.HexToHash("0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb")
+
+	operator = common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+	alice    = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob      = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+)
+
+// abiWords joins 32-byte ABI words given in hex.
+func abiWords(words ...string) []byte {
+	return common.FromHex(strings.Join(words, ""))
+}
+
+func transferLog(topic common.Hash, logIndex uint, data []byte) types.Log {
+	return types.Log{
+		Topics: []common.Hash{
+			topic,
+			common.BytesToHash(operator.Bytes()),
+			common.BytesToHash(alice.Bytes()),
+			common.BytesToHash(bob.Bytes()),
+		},
+		Data:        data,
+		BlockNumber: 100,
+		TxHash:      common.HexToHash("0x01"),
+		Index:       logIndex,
+	}
+}
+
+func newTestIndexer(t *testing.T) *ERC1155Indexer {
+	t.Helper()
+
+	dbConfig := config.DatabaseConfig{Path: path.Join(t.TempDir(), "erc1155.db")}
+	dbConfig.ApplyDefaults()
+
+	idx, err := NewERC1155Indexer(config.IndexerConfig{Name: "tokens", DB: dbConfig}, logger.NewNopLogger())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, idx.Close()) })
+
+	return idx
+}
+
+func TestERC1155Indexer_HandleLogs(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndexer(t)
+	require.Equal(t, transferSingleTopic, idx.transferSingleTopic)
+	require.Equal(t, transferBatchTopic, idx.transferBatchTopic)
+
+	// TransferSingle(id = 7, value = 100)
+	single := transferLog(transferSingleTopic, 0, abiWords(
+		"0000000000000000000000000000000000000000000000000000000000000007",
+		"0000000000000000000000000000000000000000000000000000000000000064",
+	))
+
+	// TransferBatch(ids = [1, 2^256-1], values = [10, 20]): the offsets of both arrays,
+	// then each array as its length followed by its elements
+	batch := transferLog(transferBatchTopic, 1, abiWords(
+		"0000000000000000000000000000000000000000000000000000000000000040",
+		"00000000000000000000000000000000000000000000000000000000000000a0",
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"000000000000000000000000000000000000000000000000000000000000000a",
+		"0000000000000000000000000000000000000000000000000000000000000014",
+	))
+
+	require.NoError(t, idx.HandleLogs([]types.Log{single, batch}))
+
+	var transfers []*Transfer
+	require.NoError(t, meddler.QueryAll(idx.DB, &transfers,
+		"SELECT * FROM transfers ORDER BY log_index, batch_index"))
+	require.Len(t, transfers, 3)
+
+	expected := []struct {
+		logIndex   uint
+		batchIndex uint
+		tokenID    string
+		value      string
+	}{
+		{0, 0, "7", "100"},
+		{1, 0, "1", "10"},
+		{1, 1, "115792089237316195423570985008687907853269984665640564039457584007913129639935", "20"},
+	}
+
+	for i, want := range expected {
+		require.Equal(t, want.logIndex, transfers[i].LogIndex)
+		require.Equal(t, want.batchIndex, transfers[i].BatchIndex)
+		require.Equal(t, want.tokenID, transfers[i].TokenID)
+		require.Equal(t, want.value, transfers[i].Value)
+		require.Equal(t, operator, transfers[i].Operator)
+		require.Equal(t, alice, transfers[i].From)
+		require.Equal(t, bob, transfers[i].To)
+	}
+}
+
+func TestERC1155Indexer_QueryEventsCursor(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndexer(t)
+
+	// TransferBatch(ids = [1, 2, 3], values = [10, 20, 30])
+	batch := transferLog(transferBatchTopic, 0, abiWords(
+		"0000000000000000000000000000000000000000000000000000000000000040",
+		"00000000000000000000000000000000000000000000000000000000000000c0",
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		"000000000000000000000000000000000000000000000000000000000000000a",
+		"0000000000000000000000000000000000000000000000000000000000000014",
+		"000000000000000000000000000000000000000000000000000000000000001e",
+	))
+	require.NoError(t, idx.HandleLogs([]types.Log{batch}))
+
+	// Pages of two split the transfers of the batch, which share their log index
+	tokenIDs := make([]string, 0, 3)
+	params := pkgindexer.QueryParams{EventType: "Transfer", Limit: 2, SortOrder: "asc"}
+	for {
+		events, total, err := idx.QueryEvents(t.Context(), params)
+		require.NoError(t, err)
+		require.Equal(t, 3, total)
+
+		transfers, ok := events.([]*Transfer)
+		require.True(t, ok)
+		for _, transfer := range transfers {
+			tokenIDs = append(tokenIDs, transfer.TokenID)
+		}
+		if len(transfers) < params.Limit {
+			break
+		}
+
+		cursor, ok := pkgindexer.CursorOf(reflect.ValueOf(transfers[len(transfers)-1]))
+		require.True(t, ok)
+		params.After = &cursor
+	}
+
+	require.Equal(t, []string{"1", "2", "3"}, tokenIDs)
+}
+
+func TestERC1155Indexer_ParseTransferBatchErrors(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndexer(t)
+
+	// ids = [1], values = []
+	mismatched := transferLog(transferBatchTopic, 0, abiWords(
+		"0000000000000000000000000000000000000000000000000000000000000040",
+		"0000000000000000000000000000000000000000000000000000000000000080",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+	))
+	_, err := idx.parseTransferBatch(&mismatched)
+	require.ErrorContains(t, err, "got 1 ids and 0 values")
+
+	// The offset of the ids points past the end of the data
+	truncated := transferLog(transferBatchTopic, 0, abiWords(
+		"0000000000000000000000000000000000000000000000000000000000000400",
+		"0000000000000000000000000000000000000000000000000000000000000040",
+	))
+	_, err = idx.parseTransferBatch(&truncated)
+	require.ErrorContains(t, err, "failed to decode data")
+
+	missingTopic := transferLog(transferBatchTopic, 0, nil)
+	missingTopic.Topics = missingTopic.Topics[:3]
+	_, err = idx.parseTransferBatch(&missingTopic)
+	require.ErrorContains(t, err, "expected 4 topics, got 3")
+
+	// Logs that fail to parse are skipped
+	require.NoError(t, idx.HandleLogs([]types.Log{mismatched, truncated}))
+
+	var count int
+	require.NoError(t, idx.DB.QueryRow("SELECT COUNT(*) FROM transfers").Scan(&count))
+	require.Zero(t, count)
+}
@@ -0,0 +1,33 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_transfers_operator;
+DROP INDEX IF EXISTS idx_transfers_from_address;
+DROP INDEX IF EXISTS idx_transfers_to_address;
+DROP INDEX IF EXISTS idx_transfers_token_id;
+DROP INDEX IF EXISTS idx_transfers_tx_hash;
+DROP INDEX IF EXISTS idx_transfers_block_number;
+DROP TABLE IF EXISTS transfers;
+
+-- +migrate Up
+-- TransferSingle and TransferBatch events share the table, with one row per transferred id
+CREATE TABLE IF NOT EXISTS transfers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    block_number INTEGER NOT NULL,
+    block_hash TEXT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    tx_index INTEGER NOT NULL,
+    log_index INTEGER NOT NULL,
+    batch_index INTEGER NOT NULL,
+    operator TEXT NOT NULL,
+    from_address TEXT NOT NULL,
+    to_address TEXT NOT NULL,
+    token_id TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE(tx_hash, log_index, batch_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfers_block_number ON transfers(block_number);
+CREATE INDEX IF NOT EXISTS idx_transfers_tx_hash ON transfers(tx_hash);
+CREATE INDEX IF NOT EXISTS idx_transfers_operator ON transfers(operator);
+CREATE INDEX IF NOT EXISTS idx_transfers_from_address ON transfers(from_address);
+CREATE INDEX IF NOT EXISTS idx_transfers_to_address ON transfers(to_address);
+CREATE INDEX IF NOT EXISTS idx_transfers_token_id ON transfers(token_id);
@@ -0,0 +1,25 @@
+package erc1155
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Transfer represents a TransferSingle event, or the transfer of one id of a TransferBatch event.
+// Event signatures:
+//   - TransferSingle(address indexed operator, address indexed from, address indexed to, uint256 id, uint256 value)
+//   - TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values)
+type Transfer struct {
+	ID          int64       `meddler:"id,pk"`
+	BlockNumber uint64      `meddler:"block_number"`
+	BlockHash   common.Hash `meddler:"block_hash,hash"`
+	TxHash      common.Hash `meddler:"tx_hash,hash"`
+	TxIndex     uint        `meddler:"tx_index"`
+	LogIndex    uint        `meddler:"log_index"`
+	// BatchIndex is the position of the id in the ids of a TransferBatch event, 0 for TransferSingle events
+	BatchIndex uint           `meddler:"batch_index"`
+	Operator   common.Address `meddler:"operator,address" abi:"operator,address,indexed"`
+	From       common.Address `meddler:"from_address,address" abi:"from,address,indexed"`
+	To         common.Address `meddler:"to_address,address" abi:"to,address,indexed"`
+	TokenID    string         `meddler:"token_id" abi:"id,uint256"`
+	Value      string         `meddler:"value" abi:"value,uint256"`
+}
@@ -0,0 +1,18 @@
+package erc1155
+
+import (
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+)
+
+func init() {
+	indexer.Register("erc1155", func(cfg config.IndexerConfig, log *logger.Logger) (indexer.Indexer, error) {
+		return NewERC1155Indexer(cfg, log)
+	}, indexer.FactoryMeta{
+		Description: "Indexes ERC1155 events",
+		EventTables: map[string]string{
+			"Transfer": "transfers",
+		},
+	})
+}
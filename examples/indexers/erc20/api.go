@@ -20,6 +20,9 @@ func (idx *ERC20Indexer) InitEventMetadata() map[string]*indexer.EventMetadata {
 				"from_address",
 				"to_address",
 			},
+			NumericColumns: []string{
+				"value",
+			},
 		},
 		"approval": {
 			Name:      "Approval",
@@ -29,18 +32,35 @@ func (idx *ERC20Indexer) InitEventMetadata() map[string]*indexer.EventMetadata {
 				"owner_address",
 				"spender_address",
 			},
+			NumericColumns: []string{
+				"value",
+			},
 		},
 	}
 }
 
-// Ensure ERC20Indexer implements pkgindexer.Queryable
-var _ pkgindexer.Queryable = (*ERC20Indexer)(nil)
+// Ensure ERC20Indexer implements pkgindexer.Queryable, pkgindexer.Exportable and pkgindexer.DistinctQueryable
+var (
+	_ pkgindexer.Queryable         = (*ERC20Indexer)(nil)
+	_ pkgindexer.Exportable        = (*ERC20Indexer)(nil)
+	_ pkgindexer.DistinctQueryable = (*ERC20Indexer)(nil)
+)
 
 // QueryEvents retrieves events based on the provided query parameters.
 func (idx *ERC20Indexer) QueryEvents(ctx context.Context, params pkgindexer.QueryParams) (any, int, error) {
 	return idx.BaseIndexer.QueryEvents(ctx, idx, params)
 }
 
+// ExportEvents calls fn for every event matching the query parameters, in block and log index order.
+func (idx *ERC20Indexer) ExportEvents(ctx context.Context, params pkgindexer.QueryParams, maxRows uint64, fn func(event any) error) error {
+	return idx.BaseIndexer.ExportEvents(ctx, idx, params, maxRows, fn)
+}
+
+// QueryDistinctValues returns the distinct values of an event field matching the parameters.
+func (idx *ERC20Indexer) QueryDistinctValues(ctx context.Context, params pkgindexer.DistinctParams) ([]any, error) {
+	return idx.BaseIndexer.QueryDistinctValues(ctx, idx, params)
+}
+
 // GetStats returns statistics about the indexed data.
 func (idx *ERC20Indexer) GetStats(ctx context.Context) (pkgindexer.StatsResponse, error) {
 	return idx.BaseIndexer.GetStats(ctx, idx)
@@ -51,6 +71,11 @@ func (idx *ERC20Indexer) GetEventTypes() []string {
 	return idx.BaseIndexer.GetEventTypes(idx)
 }
 
+// GetEventSchema returns the field schema of every event this indexer handles.
+func (idx *ERC20Indexer) GetEventSchema() []pkgindexer.EventSchema {
+	return idx.BaseIndexer.GetEventSchema(idx)
+}
+
 // QueryEventsTimeseries retrieves time-series aggregated event data.
 func (idx *ERC20Indexer) QueryEventsTimeseries(ctx context.Context, params pkgindexer.TimeseriesParams) ([]pkgindexer.TimeseriesDataPoint, error) {
 	return idx.BaseIndexer.QueryEventsTimeseries(ctx, idx, params)
@@ -60,3 +85,13 @@ func (idx *ERC20Indexer) QueryEventsTimeseries(ctx context.Context, params pkgin
 func (idx *ERC20Indexer) GetMetrics(ctx context.Context) (pkgindexer.MetricsResponse, error) {
 	return idx.BaseIndexer.GetMetrics(ctx, idx)
 }
+
+// QueryFirstEvent retrieves the earliest indexed event of the given type.
+func (idx *ERC20Indexer) QueryFirstEvent(ctx context.Context, eventType string) (any, error) {
+	return idx.BaseIndexer.QueryFirstEvent(ctx, idx, eventType)
+}
+
+// QueryLastEvent retrieves the most recently indexed event of the given type.
+func (idx *ERC20Indexer) QueryLastEvent(ctx context.Context, eventType string) (any, error) {
+	return idx.BaseIndexer.QueryLastEvent(ctx, idx, eventType)
+}
@@ -0,0 +1,169 @@
+package erc20
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	alice = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob   = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	carol = common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+)
+
+func transfer(blockNumber uint64, logIndex uint, from, to common.Address, value string) Transfer {
+	return Transfer{BlockNumber: blockNumber, LogIndex: logIndex, From: from, To: to, Value: value}
+}
+
+func TestMockClient_QueryTransfers(t *testing.T) {
+	mock := NewMockClient()
+	mock.AddTransfers(
+		transfer(10, 0, alice, bob, "100"),
+		transfer(12, 1, bob, carol, "40"),
+		transfer(12, 0, carol, alice, "5"),
+		transfer(15, 0, alice, carol, "60"),
+	)
+	mock.AddApprovals(Approval{BlockNumber: 11, Owner: alice, Spender: bob, Value: "1000"})
+
+	fromBlock, toBlock := uint64(11), uint64(12)
+
+	tests := []struct {
+		name   string
+		params QueryParams
+		values []string
+		total  int
+	}{
+		{
+			name:   "all transfers, newest first",
+			values: []string{"60", "40", "5", "100"},
+			total:  4,
+		},
+		{
+			name:   "ascending order",
+			params: QueryParams{SortBy: []string{"block_number"}, SortOrder: "asc"},
+			values: []string{"100", "5", "40", "60"},
+			total:  4,
+		},
+		{
+			name:   "block range",
+			params: QueryParams{FromBlock: &fromBlock, ToBlock: &toBlock},
+			values: []string{"40", "5"},
+			total:  2,
+		},
+		{
+			name:   "address of the sender or recipient",
+			params: QueryParams{Address: bob.Hex()},
+			values: []string{"40", "100"},
+			total:  2,
+		},
+		{
+			name:   "offset and limit",
+			params: QueryParams{Offset: 1, Limit: 2},
+			values: []string{"40", "5"},
+			total:  4,
+		},
+		{
+			name:   "offset past the events",
+			params: QueryParams{Offset: 10},
+			values: []string{},
+			total:  4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfers, total, err := mock.QueryTransfers(context.Background(), tt.params)
+			require.NoError(t, err)
+			require.Equal(t, tt.total, total)
+
+			values := make([]string, len(transfers))
+			for i, transfer := range transfers {
+				values[i] = transfer.Value
+			}
+			require.Equal(t, tt.values, values)
+		})
+	}
+
+	approvals, total, err := mock.QueryApprovals(context.Background(), QueryParams{Address: bob.Hex()})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Equal(t, "1000", approvals[0].Value)
+
+	_, _, err = mock.QueryTransfers(context.Background(), QueryParams{SortBy: []string{"value"}})
+	require.ErrorContains(t, err, "does not support sorting by value")
+
+	_, _, err = mock.QueryTransfers(context.Background(), QueryParams{Address: "alice"})
+	require.ErrorContains(t, err, `invalid address "alice"`)
+}
+
+func TestClient_QueryTransfers(t *testing.T) {
+	transfers := []Transfer{
+		transfer(15, 0, alice, carol, "60"),
+		transfer(12, 1, bob, carol, "40"),
+		transfer(12, 0, carol, alice, "5"),
+	}
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/indexers/my-erc20/events", r.URL.Path)
+		queries = append(queries, r.URL.RawQuery)
+
+		if r.URL.Query().Get("event_type") != "Transfer" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"Bad Request","message":"unknown event type","code":400}`))
+			return
+		}
+
+		// Pages of two events, with a cursor after the first page
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if r.URL.Query().Get("cursor") == "next" {
+			offset = 2
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		end := min(offset+min(limit, 2), len(transfers))
+
+		page := map[string]any{
+			"events": transfers[offset:end],
+			"pagination": map[string]any{
+				"total":    len(transfers),
+				"has_more": end < len(transfers),
+			},
+		}
+		if offset == 0 {
+			page["next_cursor"] = "next"
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(page))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "my-erc20", server.Client())
+
+	got, total, err := client.QueryTransfers(context.Background(), QueryParams{Address: bob.Hex()})
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, transfers, got)
+	require.Equal(t, []string{
+		"address=" + bob.Hex() + "&event_type=Transfer&limit=1000",
+		"address=" + bob.Hex() + "&cursor=next&event_type=Transfer&limit=1000",
+	}, queries)
+
+	queries = nil
+	got, total, err = client.QueryTransfers(context.Background(), QueryParams{Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, transfers[:1], got)
+	require.Equal(t, []string{"event_type=Transfer&limit=1"}, queries)
+
+	_, _, err = client.QueryApprovals(context.Background(), QueryParams{})
+	require.ErrorContains(t, err, "failed to query Approval events: 400 Bad Request: unknown event type")
+
+	_, _, err = client.QueryTransfers(context.Background(), QueryParams{Fields: []string{"value"}})
+	require.ErrorContains(t, err, "not supported by the client")
+}
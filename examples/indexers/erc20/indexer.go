@@ -2,6 +2,7 @@
 package erc20
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -104,6 +105,11 @@ func (idx *ERC20Indexer) Close() error {
 	return idx.BaseIndexer.Close()
 }
 
+// Ping checks that the indexer's database is reachable.
+func (idx *ERC20Indexer) Ping(ctx context.Context) error {
+	return idx.BaseIndexer.Ping(ctx)
+}
+
 // HandleReorg handles a blockchain reorganization by removing data from the reorg point.
 func (idx *ERC20Indexer) HandleReorg(blockNum uint64) error {
 	return idx.BaseIndexer.HandleReorg(idx, blockNum)
@@ -168,6 +174,9 @@ func (idx *ERC20Indexer) HandleLogs(logs []types.Log) error {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	// Cached query results covering the stored blocks are out of date
+	idx.InvalidateQueryCache(logs)
+
 	idx.log.Infof("Indexed %d transfers, %d approvals", transferCount, approvalCount)
 
 	return nil
@@ -2,6 +2,7 @@
 package migrations
 
 import (
+	"database/sql"
 	_ "embed"
 
 	"github.com/goran-ethernal/ChainIndexor/internal/db"
@@ -11,14 +12,23 @@ import (
 //go:embed 001_initial.sql
 var mig0001 string
 
-// RunMigrations runs all migrations for the indexer database.
-func RunMigrations(dbConfig config.DatabaseConfig) error {
-	migrations := []db.Migration{
+// migrations returns the ordered list of indexer database migrations.
+func migrations() []db.Migration {
+	return []db.Migration{
 		{
 			ID:  "001_initial.sql",
 			SQL: mig0001,
 		},
 	}
+}
+
+// RunMigrations runs all migrations for the indexer database.
+func RunMigrations(dbConfig config.DatabaseConfig) error {
+	return db.RunMigrations(dbConfig, migrations())
+}
 
-	return db.RunMigrations(dbConfig, migrations)
+// RollbackTo reverts the indexer database migrations newer than targetVersion in a single transaction.
+// Version 0 reverts every migration.
+func RollbackTo(database *sql.DB, targetVersion int) error {
+	return db.RollbackTo(database, migrations(), targetVersion)
 }
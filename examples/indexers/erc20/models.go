@@ -14,9 +14,9 @@ type Transfer struct {
 	TxHash      common.Hash `meddler:"tx_hash,hash"`
 	TxIndex     uint        `meddler:"tx_index"`
 	LogIndex    uint        `meddler:"log_index"`
-	From common.Address `meddler:"from_address,address"`
-	To common.Address `meddler:"to_address,address"`
-	Value string `meddler:"value"`
+	From common.Address `meddler:"from_address,address" abi:"from,address,indexed"`
+	To common.Address `meddler:"to_address,address" abi:"to,address,indexed"`
+	Value string `meddler:"value" abi:"value,uint256"`
 }
 
 // Approval represents a Approval event.
@@ -28,8 +28,8 @@ type Approval struct {
 	TxHash      common.Hash `meddler:"tx_hash,hash"`
 	TxIndex     uint        `meddler:"tx_index"`
 	LogIndex    uint        `meddler:"log_index"`
-	Owner common.Address `meddler:"owner_address,address"`
-	Spender common.Address `meddler:"spender_address,address"`
-	Value string `meddler:"value"`
+	Owner common.Address `meddler:"owner_address,address" abi:"owner,address,indexed"`
+	Spender common.Address `meddler:"spender_address,address" abi:"spender,address,indexed"`
+	Value string `meddler:"value" abi:"value,uint256"`
 }
 
@@ -10,5 +10,11 @@ import (
 func init() {
 	indexer.Register("erc20", func(cfg config.IndexerConfig, log *logger.Logger) (indexer.Indexer, error) {
 		return NewERC20Indexer(cfg, log)
+	}, indexer.FactoryMeta{
+		Description: "Indexes ERC20 events",
+		EventTables: map[string]string{
+			"Transfer": "transfers",
+			"Approval": "approvals",
+		},
 	})
 }
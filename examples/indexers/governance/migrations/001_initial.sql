@@ -0,0 +1,61 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_proposal_created_proposal_id;
+DROP INDEX IF EXISTS idx_proposal_created_proposer;
+DROP INDEX IF EXISTS idx_proposal_created_tx_hash;
+DROP INDEX IF EXISTS idx_proposal_created_block_number;
+DROP TABLE IF EXISTS proposal_created;
+
+
+DROP INDEX IF EXISTS idx_vote_casts_voter;
+DROP INDEX IF EXISTS idx_vote_casts_proposal_id;
+DROP INDEX IF EXISTS idx_vote_casts_tx_hash;
+DROP INDEX IF EXISTS idx_vote_casts_block_number;
+DROP TABLE IF EXISTS vote_casts;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS proposal_created (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    block_number INTEGER NOT NULL,
+    block_hash TEXT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    tx_index INTEGER NOT NULL,
+    log_index INTEGER NOT NULL,
+    proposal_id TEXT NOT NULL,
+    proposer TEXT NOT NULL,
+    targets TEXT NOT NULL,
+    call_values TEXT NOT NULL,
+    signatures TEXT NOT NULL,
+    calldatas TEXT NOT NULL,
+    vote_start TEXT NOT NULL,
+    vote_end TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE(tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_proposal_created_block_number ON proposal_created(block_number);
+CREATE INDEX IF NOT EXISTS idx_proposal_created_tx_hash ON proposal_created(tx_hash);
+CREATE INDEX IF NOT EXISTS idx_proposal_created_proposal_id ON proposal_created(proposal_id);
+CREATE INDEX IF NOT EXISTS idx_proposal_created_proposer ON proposal_created(proposer);
+
+
+CREATE TABLE IF NOT EXISTS vote_casts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    block_number INTEGER NOT NULL,
+    block_hash TEXT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    tx_index INTEGER NOT NULL,
+    log_index INTEGER NOT NULL,
+    voter TEXT NOT NULL,
+    proposal_id TEXT NOT NULL,
+    support INTEGER NOT NULL,
+    weight TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    UNIQUE(tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_vote_casts_block_number ON vote_casts(block_number);
+CREATE INDEX IF NOT EXISTS idx_vote_casts_tx_hash ON vote_casts(tx_hash);
+CREATE INDEX IF NOT EXISTS idx_vote_casts_voter ON vote_casts(voter);
+CREATE INDEX IF NOT EXISTS idx_vote_casts_proposal_id ON vote_casts(proposal_id);
+
+
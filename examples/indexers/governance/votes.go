@@ -0,0 +1,84 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Support values of a VoteCast event, as counted by Compound-style governors.
+const (
+	SupportAgainst uint8 = 0
+	SupportFor     uint8 = 1
+	SupportAbstain uint8 = 2
+)
+
+// ProposalVoteSummary is the tally of the votes cast on a proposal. The totals are the summed vote
+// weights as decimal strings, and the voter counts are the distinct voters per support type.
+type ProposalVoteSummary struct {
+	ProposalID    uint64 `json:"proposal_id" example:"42"`
+	For           string `json:"for" example:"1500000000000000000000000"`
+	Against       string `json:"against" example:"250000000000000000000000"`
+	Abstain       string `json:"abstain" example:"0"`
+	ForVoters     int    `json:"for_voters" example:"120"`
+	AgainstVoters int    `json:"against_voters" example:"35"`
+	AbstainVoters int    `json:"abstain_voters" example:"0"`
+}
+
+// QueryProposalVotes tallies the VoteCast events of a proposal. The weights are summed exactly, as
+// uint256 values are stored as decimal text. Votes with an unknown support value are not counted.
+func (idx *GovernanceIndexer) QueryProposalVotes(ctx context.Context, proposalID uint64) (*ProposalVoteSummary, error) {
+	rows, err := idx.DB.QueryContext(ctx,
+		`SELECT voter, support, weight FROM vote_casts WHERE proposal_id = ?`,
+		strconv.FormatUint(proposalID, 10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query votes: %w", err)
+	}
+	defer rows.Close()
+
+	var totals [SupportAbstain + 1]big.Int
+	var voters [SupportAbstain + 1]map[string]struct{}
+	for support := range voters {
+		voters[support] = make(map[string]struct{})
+	}
+
+	for rows.Next() {
+		var (
+			voter   string
+			support uint8
+			weight  string
+		)
+		if err := rows.Scan(&voter, &support, &weight); err != nil {
+			return nil, fmt.Errorf("failed to scan vote: %w", err)
+		}
+
+		if support > SupportAbstain {
+			idx.log.Debugf("Skipping vote of %s on proposal %d with unknown support %d", voter, proposalID, support)
+			continue
+		}
+
+		value, ok := new(big.Int).SetString(weight, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid vote weight %q", weight)
+		}
+
+		totals[support].Add(&totals[support], value)
+		voters[support][common.HexToAddress(voter).Hex()] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read votes: %w", err)
+	}
+
+	return &ProposalVoteSummary{
+		ProposalID:    proposalID,
+		For:           totals[SupportFor].String(),
+		Against:       totals[SupportAgainst].String(),
+		Abstain:       totals[SupportAbstain].String(),
+		ForVoters:     len(voters[SupportFor]),
+		AgainstVoters: len(voters[SupportAgainst]),
+		AbstainVoters: len(voters[SupportAbstain]),
+	}, nil
+}
@@ -0,0 +1,165 @@
+package governance
+
+import (
+	"context"
+	"math/big"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/russross/meddler"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	voteCastTopic = common.HexToHash("0xb8e138887d0aa13bab447e82de9d5c1777041ecd21ca36ba824ff1e6c07ddda4")
+
+	alice = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob   = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	carol = common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+)
+
+// voteLog builds a VoteCast log, ABI-encoding its support, decimal weight and reason.
+func voteLog(
+	t *testing.T, idx *GovernanceIndexer, blockNumber uint64, voter common.Address, proposalID int64,
+	support uint8, weight, reason string,
+) types.Log {
+	t.Helper()
+
+	value, ok := new(big.Int).SetString(weight, 10)
+	require.True(t, ok)
+
+	data, err := idx.eventsABI.Events["VoteCast"].Inputs.NonIndexed().Pack(support, value, reason)
+	require.NoError(t, err)
+
+	return types.Log{
+		Topics: []common.Hash{
+			voteCastTopic,
+			common.BytesToHash(voter.Bytes()),
+			common.BigToHash(big.NewInt(proposalID)),
+		},
+		Data:        data,
+		BlockNumber: blockNumber,
+		TxHash:      common.BigToHash(new(big.Int).SetUint64(blockNumber)),
+	}
+}
+
+func newTestIndexer(t *testing.T) *GovernanceIndexer {
+	t.Helper()
+
+	dbConfig := config.DatabaseConfig{Path: path.Join(t.TempDir(), "governance.db")}
+	dbConfig.ApplyDefaults()
+
+	idx, err := NewGovernanceIndexer(config.IndexerConfig{Name: "governor", DB: dbConfig}, logger.NewNopLogger())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, idx.Close()) })
+
+	return idx
+}
+
+func TestGovernanceIndexer_QueryProposalVotes(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndexer(t)
+	require.Equal(t, voteCastTopic, idx.votecastTopic)
+
+	require.NoError(t, idx.HandleLogs([]types.Log{
+		// Weights of tokens with 18 decimals, above what SQLite sums as integers
+		voteLog(t, idx, 100, alice, 7, SupportFor, "1000000000000000000000000", "Looks good"),
+		voteLog(t, idx, 101, bob, 7, SupportFor, "500000000000000000000000", ""),
+		voteLog(t, idx, 102, carol, 7, SupportAgainst, "250000000000000000000000", "Too expensive"),
+		voteLog(t, idx, 103, bob, 8, SupportAbstain, "500000000000000000000000", ""),
+		// Support values the governor does not count are skipped
+		voteLog(t, idx, 104, carol, 8, 3, "1", ""),
+	}))
+
+	summary, err := idx.QueryProposalVotes(context.Background(), 7)
+	require.NoError(t, err)
+	require.Equal(t, &ProposalVoteSummary{
+		ProposalID:    7,
+		For:           "1500000000000000000000000",
+		Against:       "250000000000000000000000",
+		Abstain:       "0",
+		ForVoters:     2,
+		AgainstVoters: 1,
+		AbstainVoters: 0,
+	}, summary)
+
+	summary, err = idx.QueryProposalVotes(context.Background(), 8)
+	require.NoError(t, err)
+	require.Equal(t, "500000000000000000000000", summary.Abstain)
+	require.Equal(t, 1, summary.AbstainVoters)
+	require.Equal(t, 0, summary.ForVoters+summary.AgainstVoters)
+
+	// Proposals without votes have empty totals
+	summary, err = idx.QueryProposalVotes(context.Background(), 9)
+	require.NoError(t, err)
+	require.Equal(t, &ProposalVoteSummary{ProposalID: 9, For: "0", Against: "0", Abstain: "0"}, summary)
+}
+
+func TestGovernanceIndexer_HandleLogs_ReasonCapped(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndexer(t)
+
+	// The reason is cut at the last character that fits in maxStringLength bytes, not in the
+	// middle of the two-byte "é"
+	reason := strings.Repeat("a", maxStringLength-1) + "é"
+	require.NoError(t, idx.HandleLogs([]types.Log{
+		voteLog(t, idx, 100, alice, 7, SupportFor, "1", reason),
+		voteLog(t, idx, 101, bob, 7, SupportAgainst, "1", "No"),
+	}))
+
+	var votes []*VoteCast
+	require.NoError(t, meddler.QueryAll(idx.DB, &votes, "SELECT * FROM vote_casts ORDER BY block_number"))
+	require.Len(t, votes, 2)
+
+	require.Equal(t, alice, votes[0].Voter)
+	require.Equal(t, "7", votes[0].Proposalid)
+	require.Equal(t, SupportFor, votes[0].Support)
+	require.Equal(t, strings.Repeat("a", maxStringLength-1), votes[0].Reason)
+	require.Equal(t, "No", votes[1].Reason)
+}
+
+func TestGovernanceIndexer_HandleLogs_ProposalCreated(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndexer(t)
+
+	data, err := idx.eventsABI.Events["ProposalCreated"].Inputs.NonIndexed().Pack(
+		alice,
+		[]common.Address{bob},
+		[]*big.Int{big.NewInt(0)},
+		[]string{"transfer(address,uint256)"},
+		[][]byte{{0xa9, 0x05, 0x9c, 0xbb}},
+		big.NewInt(110),
+		big.NewInt(200),
+		"# Fund the grants program",
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, idx.HandleLogs([]types.Log{{
+		Topics: []common.Hash{
+			idx.proposalcreatedTopic,
+			common.BigToHash(big.NewInt(7)),
+		},
+		Data:        data,
+		BlockNumber: 100,
+		TxHash:      common.HexToHash("0x01"),
+	}}))
+
+	var proposal ProposalCreated
+	require.NoError(t, meddler.QueryRow(idx.DB, &proposal, "SELECT * FROM proposal_created"))
+	require.Equal(t, "7", proposal.Proposalid)
+	require.Equal(t, alice, proposal.Proposer)
+	require.Equal(t, []common.Address{bob}, proposal.Targets)
+	require.Equal(t, []string{"0"}, proposal.Callvalues)
+	require.Equal(t, [][]byte{{0xa9, 0x05, 0x9c, 0xbb}}, proposal.Calldatas)
+	require.Equal(t, "110", proposal.Votestart)
+	require.Equal(t, "200", proposal.Voteend)
+	require.Equal(t, "# Fund the grants program", proposal.Description)
+}
@@ -0,0 +1,102 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_swaps_sender_address;
+DROP INDEX IF EXISTS idx_swaps_to_address;
+DROP INDEX IF EXISTS idx_swaps_tx_hash;
+DROP INDEX IF EXISTS idx_swaps_block_number;
+DROP TABLE IF EXISTS swaps;
+
+
+DROP INDEX IF EXISTS idx_syncs_tx_hash;
+DROP INDEX IF EXISTS idx_syncs_block_number;
+DROP TABLE IF EXISTS syncs;
+
+
+DROP INDEX IF EXISTS idx_mints_sender_address;
+DROP INDEX IF EXISTS idx_mints_tx_hash;
+DROP INDEX IF EXISTS idx_mints_block_number;
+DROP TABLE IF EXISTS mints;
+
+
+DROP INDEX IF EXISTS idx_burns_sender_address;
+DROP INDEX IF EXISTS idx_burns_to_address;
+DROP INDEX IF EXISTS idx_burns_tx_hash;
+DROP INDEX IF EXISTS idx_burns_block_number;
+DROP TABLE IF EXISTS burns;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS swaps (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    block_number INTEGER NOT NULL,
+    block_hash TEXT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    tx_index INTEGER NOT NULL,
+    log_index INTEGER NOT NULL,
+    sender_address TEXT NOT NULL,
+    amount0_in TEXT NOT NULL,
+    amount1_in TEXT NOT NULL,
+    amount0_out TEXT NOT NULL,
+    amount1_out TEXT NOT NULL,
+    to_address TEXT NOT NULL,
+    UNIQUE(tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_swaps_block_number ON swaps(block_number);
+CREATE INDEX IF NOT EXISTS idx_swaps_tx_hash ON swaps(tx_hash);
+CREATE INDEX IF NOT EXISTS idx_swaps_sender_address ON swaps(sender_address);
+CREATE INDEX IF NOT EXISTS idx_swaps_to_address ON swaps(to_address);
+
+
+CREATE TABLE IF NOT EXISTS syncs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    block_number INTEGER NOT NULL,
+    block_hash TEXT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    tx_index INTEGER NOT NULL,
+    log_index INTEGER NOT NULL,
+    reserve0 TEXT NOT NULL,
+    reserve1 TEXT NOT NULL,
+    UNIQUE(tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_syncs_block_number ON syncs(block_number);
+CREATE INDEX IF NOT EXISTS idx_syncs_tx_hash ON syncs(tx_hash);
+
+
+CREATE TABLE IF NOT EXISTS mints (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    block_number INTEGER NOT NULL,
+    block_hash TEXT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    tx_index INTEGER NOT NULL,
+    log_index INTEGER NOT NULL,
+    sender_address TEXT NOT NULL,
+    amount0 TEXT NOT NULL,
+    amount1 TEXT NOT NULL,
+    UNIQUE(tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mints_block_number ON mints(block_number);
+CREATE INDEX IF NOT EXISTS idx_mints_tx_hash ON mints(tx_hash);
+CREATE INDEX IF NOT EXISTS idx_mints_sender_address ON mints(sender_address);
+
+
+CREATE TABLE IF NOT EXISTS burns (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    block_number INTEGER NOT NULL,
+    block_hash TEXT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    tx_index INTEGER NOT NULL,
+    log_index INTEGER NOT NULL,
+    sender_address TEXT NOT NULL,
+    amount0 TEXT NOT NULL,
+    amount1 TEXT NOT NULL,
+    to_address TEXT NOT NULL,
+    UNIQUE(tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_burns_block_number ON burns(block_number);
+CREATE INDEX IF NOT EXISTS idx_burns_tx_hash ON burns(tx_hash);
+CREATE INDEX IF NOT EXISTS idx_burns_sender_address ON burns(sender_address);
+CREATE INDEX IF NOT EXISTS idx_burns_to_address ON burns(to_address);
+
+
@@ -0,0 +1,120 @@
+package uniswapv2
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	pkgindexer "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+)
+
+// Ensure UniswapV2Indexer serves the swap volume endpoint
+var _ pkgindexer.EndpointProvider = (*UniswapV2Indexer)(nil)
+
+// SwapVolume is the total of the token amounts swapped in and out of the indexed pairs, as decimal strings
+// in the smallest unit of the tokens.
+type SwapVolume struct {
+	TotalAmount0In  string `json:"total_amount0_in" example:"1500000000000000000"`
+	TotalAmount0Out string `json:"total_amount0_out" example:"250000000000000000"`
+	TotalAmount1In  string `json:"total_amount1_in" example:"400000000"`
+	TotalAmount1Out string `json:"total_amount1_out" example:"3000000000"`
+}
+
+// QuerySwapVolume sums the amounts of the swaps in the block range [fromBlock, toBlock]. With an address,
+// only the swaps it sent or received are summed, e.g. the swaps routed by the Uniswap V2 router.
+// The amounts are summed exactly, as uint256 values are stored as decimal text.
+func (idx *UniswapV2Indexer) QuerySwapVolume(
+	ctx context.Context, address string, fromBlock, toBlock uint64,
+) (*SwapVolume, error) {
+	query := `SELECT amount0_in, amount0_out, amount1_in, amount1_out FROM swaps
+		WHERE block_number >= ? AND block_number <= ?`
+	args := []any{min(fromBlock, math.MaxInt64), min(toBlock, math.MaxInt64)}
+
+	if address != "" {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("%w: invalid address %q", pkgindexer.ErrInvalidParameter, address)
+		}
+
+		lowerAddress := strings.ToLower(address)
+		query += " AND (LOWER(sender_address) = ? OR LOWER(to_address) = ?)"
+		args = append(args, lowerAddress, lowerAddress)
+	}
+
+	rows, err := idx.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query swaps: %w", err)
+	}
+	defer rows.Close()
+
+	var totals [4]big.Int
+	for rows.Next() {
+		var amounts [4]string
+		if err := rows.Scan(&amounts[0], &amounts[1], &amounts[2], &amounts[3]); err != nil {
+			return nil, fmt.Errorf("failed to scan swap: %w", err)
+		}
+
+		for i, amount := range amounts {
+			value, ok := new(big.Int).SetString(amount, 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid swap amount %q", amount)
+			}
+			totals[i].Add(&totals[i], value)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read swaps: %w", err)
+	}
+
+	return &SwapVolume{
+		TotalAmount0In:  totals[0].String(),
+		TotalAmount0Out: totals[1].String(),
+		TotalAmount1In:  totals[2].String(),
+		TotalAmount1Out: totals[3].String(),
+	}, nil
+}
+
+// Endpoints returns the endpoints the indexer serves below /api/v1/indexers/{name}/.
+func (idx *UniswapV2Indexer) Endpoints() map[string]pkgindexer.EndpointFunc {
+	return map[string]pkgindexer.EndpointFunc{
+		"stats/volume": idx.swapVolumeEndpoint,
+	}
+}
+
+// swapVolumeEndpoint serves the swap volume of the optional address, from_block and to_block parameters.
+func (idx *UniswapV2Indexer) swapVolumeEndpoint(ctx context.Context, query url.Values) (any, error) {
+	fromBlock, err := parseBlockParam(query, "from_block", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	toBlock, err := parseBlockParam(query, "to_block", math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromBlock > toBlock {
+		return nil, fmt.Errorf("%w: from_block must not be greater than to_block", pkgindexer.ErrInvalidParameter)
+	}
+
+	return idx.QuerySwapVolume(ctx, query.Get("address"), fromBlock, toBlock)
+}
+
+// parseBlockParam parses a block number parameter, returning def if it is not set.
+func parseBlockParam(query url.Values, name string, def uint64) (uint64, error) {
+	value := query.Get(name)
+	if value == "" {
+		return def, nil
+	}
+
+	block, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s", pkgindexer.ErrInvalidParameter, name)
+	}
+
+	return block, nil
+}
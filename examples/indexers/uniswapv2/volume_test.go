@@ -0,0 +1,181 @@
+package uniswapv2
+
+import (
+	"context"
+	"math/big"
+	"net/url"
+	"path"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	pkgindexer "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	swapTopic = common.HexToHash("0xd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d822")
+
+	router = common.HexToAddress("0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D")
+	alice  = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob    = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+)
+
+// swapLog builds a Swap log of the decimal amount0In, amount1In, amount0Out and amount1Out.
+func swapLog(
+	t *testing.T, blockNumber uint64, logIndex uint, sender, to common.Address, amounts ...string,
+) types.Log {
+	t.Helper()
+
+	data := make([]byte, 0, len(amounts)*common.HashLength)
+	for _, amount := range amounts {
+		value, ok := new(big.Int).SetString(amount, 10)
+		require.True(t, ok)
+		data = append(data, common.BigToHash(value).Bytes()...)
+	}
+
+	return types.Log{
+		Topics: []common.Hash{
+			swapTopic,
+			common.BytesToHash(sender.Bytes()),
+			common.BytesToHash(to.Bytes()),
+		},
+		Data:        data,
+		BlockNumber: blockNumber,
+		TxHash:      common.BigToHash(new(big.Int).SetUint64(blockNumber)),
+		Index:       logIndex,
+	}
+}
+
+func newTestIndexer(t *testing.T) *UniswapV2Indexer {
+	t.Helper()
+
+	dbConfig := config.DatabaseConfig{Path: path.Join(t.TempDir(), "uniswapv2.db")}
+	dbConfig.ApplyDefaults()
+
+	idx, err := NewUniswapV2Indexer(config.IndexerConfig{Name: "pairs", DB: dbConfig}, logger.NewNopLogger())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, idx.Close()) })
+
+	return idx
+}
+
+func TestUniswapV2Indexer_QuerySwapVolume(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndexer(t)
+	require.Equal(t, swapTopic, idx.swapTopic)
+
+	require.NoError(t, idx.HandleLogs([]types.Log{
+		// 2^64 token0 in for 3000 token1 out, above what SQLite sums as integers
+		swapLog(t, 100, 0, router, alice, "18446744073709551616", "0", "0", "3000"),
+		swapLog(t, 100, 1, router, bob, "0", "500", "250", "0"),
+		swapLog(t, 105, 0, alice, bob, "7", "0", "0", "9"),
+		// 2^256-1 of token1 in
+		swapLog(t, 110, 0, bob, bob, "0",
+			"115792089237316195423570985008687907853269984665640564039457584007913129639935", "1", "0"),
+	}))
+
+	tests := []struct {
+		name      string
+		address   string
+		fromBlock uint64
+		toBlock   uint64
+		expected  SwapVolume
+	}{
+		{
+			name:    "all swaps",
+			toBlock: 200,
+			expected: SwapVolume{
+				TotalAmount0In:  "18446744073709551623",
+				TotalAmount0Out: "251",
+				TotalAmount1In:  "115792089237316195423570985008687907853269984665640564039457584007913129640435",
+				TotalAmount1Out: "3009",
+			},
+		},
+		{
+			name:      "block range",
+			fromBlock: 100,
+			toBlock:   105,
+			expected: SwapVolume{
+				TotalAmount0In:  "18446744073709551623",
+				TotalAmount0Out: "250",
+				TotalAmount1In:  "500",
+				TotalAmount1Out: "3009",
+			},
+		},
+		{
+			name:    "sender or recipient, in any case",
+			address: "0xF39FD6E51AAD88F6F4CE6AB8827279CFFFB92266",
+			toBlock: 200,
+			expected: SwapVolume{
+				TotalAmount0In:  "18446744073709551623",
+				TotalAmount0Out: "0",
+				TotalAmount1In:  "0",
+				TotalAmount1Out: "3009",
+			},
+		},
+		{
+			name:      "no swaps",
+			fromBlock: 106,
+			toBlock:   109,
+			expected: SwapVolume{
+				TotalAmount0In:  "0",
+				TotalAmount0Out: "0",
+				TotalAmount1In:  "0",
+				TotalAmount1Out: "0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			volume, err := idx.QuerySwapVolume(context.Background(), tt.address, tt.fromBlock, tt.toBlock)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, *volume)
+		})
+	}
+
+	_, err := idx.QuerySwapVolume(context.Background(), "alice", 0, 200)
+	require.ErrorIs(t, err, pkgindexer.ErrInvalidParameter)
+}
+
+func TestUniswapV2Indexer_Endpoints(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndexer(t)
+	require.NoError(t, idx.HandleLogs([]types.Log{
+		swapLog(t, 100, 0, router, alice, "10", "0", "0", "20"),
+		swapLog(t, 200, 0, router, bob, "0", "30", "40", "0"),
+	}))
+
+	endpoint, ok := idx.Endpoints()["stats/volume"]
+	require.True(t, ok)
+
+	// Without a block range, every swap is summed
+	volume, err := endpoint(context.Background(), url.Values{})
+	require.NoError(t, err)
+	require.Equal(t, &SwapVolume{
+		TotalAmount0In:  "10",
+		TotalAmount0Out: "40",
+		TotalAmount1In:  "30",
+		TotalAmount1Out: "20",
+	}, volume)
+
+	volume, err = endpoint(context.Background(), url.Values{"from_block": {"150"}, "address": {bob.Hex()}})
+	require.NoError(t, err)
+	require.Equal(t, "40", volume.(*SwapVolume).TotalAmount0Out)
+	require.Equal(t, "0", volume.(*SwapVolume).TotalAmount1Out)
+
+	for _, query := range []url.Values{
+		{"from_block": {"-1"}},
+		{"to_block": {"latest"}},
+		{"from_block": {"10"}, "to_block": {"5"}},
+		{"address": {"bob"}},
+	} {
+		_, err := endpoint(context.Background(), query)
+		require.ErrorIs(t, err, pkgindexer.ErrInvalidParameter, query.Encode())
+	}
+}
@@ -33,7 +33,7 @@ func TestRun(t *testing.T) {
 	ctx, cancel := context.WithCancel(t.Context())
 	defer cancel()
 
-	ethClient, err := rpc.NewClient(ctx, cfg.Downloader.RPCURL, cfg.Downloader.Retry) // Example RPC URL
+	ethClient, err := rpc.NewClient(ctx, cfg.Downloader.RPCURL, cfg.Downloader.Retry, cfg.Downloader.CircuitBreaker) // Example RPC URL
 	if err != nil {
 		t.Fatalf("failed to create RPC client: %v", err)
 	}
@@ -64,6 +64,7 @@ func TestRun(t *testing.T) {
 	}
 
 	dbMaintainance := db.NewMaintenanceCoordinator(
+		cfg.Downloader.ExpectedChainID,
 		cfg.Downloader.DB.Path,
 		database,
 		cfg.Downloader.Maintenance,
@@ -71,9 +72,10 @@ func TestRun(t *testing.T) {
 	)
 
 	reorgDetector, err := reorg.NewReorgDetector(
-		database, ethClient,
+		cfg.Downloader.ExpectedChainID, database, ethClient,
 		logger.NewComponentLoggerFromConfig(common.ComponentReorgDetector, cfg.Logging),
 		dbMaintainance,
+		cfg.Downloader.HeaderCacheSize,
 	)
 	if err != nil {
 		t.Fatalf("failed to create reorg detector: %v", err)
@@ -89,6 +91,7 @@ func TestRun(t *testing.T) {
 	}
 
 	downloader, err := downloader.New(
+		cfg.Downloader.ExpectedChainID,
 		cfg.Downloader,
 		ethClient,
 		reorgDetector,
@@ -0,0 +1,159 @@
+package abi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	gethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+)
+
+// explorerStatusOK is the status value returned by Etherscan-compatible APIs on success.
+const explorerStatusOK = "1"
+
+// explorerResponse is the response envelope of Etherscan-compatible APIs.
+type explorerResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Result  string `json:"result"`
+}
+
+// Validator validates configured event signatures against verified contract ABIs
+// fetched from an Etherscan-compatible explorer API.
+type Validator struct {
+	cfg    *config.ABIExplorerConfig
+	client *http.Client
+	log    *logger.Logger
+}
+
+// NewValidator creates a new ABI validator.
+func NewValidator(cfg *config.ABIExplorerConfig, log *logger.Logger) *Validator {
+	return &Validator{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout.Duration},
+		log:    log,
+	}
+}
+
+// FetchABI fetches and parses the verified ABI of the contract at the given address.
+func (v *Validator) FetchABI(ctx context.Context, address common.Address) (*gethabi.ABI, error) {
+	reqURL, err := url.Parse(v.cfg.APIURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid explorer api_url: %w", err)
+	}
+
+	query := reqURL.Query()
+	query.Set("module", "contract")
+	query.Set("action", "getabi")
+	query.Set("address", address.Hex())
+	if v.cfg.APIKey != "" {
+		query.Set("apikey", v.cfg.APIKey)
+	}
+	reqURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ABI request: %w", err)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ABI for %s: %w", address.Hex(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch ABI for %s: unexpected status %d", address.Hex(), resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ABI response for %s: %w", address.Hex(), err)
+	}
+
+	var explorerResp explorerResponse
+	if err := json.Unmarshal(body, &explorerResp); err != nil {
+		return nil, fmt.Errorf("failed to decode ABI response for %s: %w", address.Hex(), err)
+	}
+
+	if explorerResp.Status != explorerStatusOK {
+		return nil, fmt.Errorf("explorer returned no ABI for %s: %s (%s)",
+			address.Hex(), explorerResp.Message, explorerResp.Result)
+	}
+
+	contractABI, err := gethabi.JSON(strings.NewReader(explorerResp.Result))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ABI for %s: %w", address.Hex(), err)
+	}
+
+	return &contractABI, nil
+}
+
+// ValidateIndexers validates the event signatures of every configured contract
+// against its on-chain ABI. All mismatches are reported in a single error.
+func (v *Validator) ValidateIndexers(ctx context.Context, indexers []config.IndexerConfig) error {
+	// Cache ABIs since the same contract can be indexed by multiple indexers
+	abis := make(map[common.Address]*gethabi.ABI)
+
+	var errs []error
+	for _, indexer := range indexers {
+		for _, contract := range indexer.Contracts {
+			address := common.HexToAddress(contract.Address)
+
+			contractABI, ok := abis[address]
+			if !ok {
+				var err error
+				contractABI, err = v.FetchABI(ctx, address)
+				if err != nil {
+					return fmt.Errorf("indexer %s: %w", indexer.Name, err)
+				}
+				abis[address] = contractABI
+			}
+
+			for _, event := range contract.Events {
+				if err := ValidateEventSignature(contractABI, event); err != nil {
+					errs = append(errs, fmt.Errorf("indexer %s, contract %s: %w", indexer.Name, address.Hex(), err))
+				}
+			}
+
+			v.log.Infof("validated %d event signature(s) of contract %s for indexer %s",
+				len(contract.Events), address.Hex(), indexer.Name)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateEventSignature checks that the topic0 computed from the configured signature
+// matches an event in the given ABI. If an event with the same name exists, its
+// canonical signature is included in the error to help fix the configuration.
+func ValidateEventSignature(contractABI *gethabi.ABI, signature string) error {
+	topic := crypto.Keccak256Hash([]byte(signature))
+
+	var candidates []string
+	for _, event := range contractABI.Events {
+		if event.ID == topic {
+			return nil
+		}
+
+		if strings.HasPrefix(signature, event.RawName+"(") {
+			candidates = append(candidates, event.Sig)
+		}
+	}
+
+	if len(candidates) > 0 {
+		return fmt.Errorf("event %q does not match on-chain ABI (expected one of: %s)",
+			signature, strings.Join(candidates, ", "))
+	}
+
+	return fmt.Errorf("event %q not found in on-chain ABI", signature)
+}
@@ -0,0 +1,177 @@
+package abi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	erc20Address = "0x1234567890123456789012345678901234567890"
+	erc20ABI     = `[
+		{"anonymous":false,"inputs":[
+			{"indexed":true,"name":"from","type":"address"},
+			{"indexed":true,"name":"to","type":"address"},
+			{"indexed":false,"name":"value","type":"uint256"}
+		],"name":"Transfer","type":"event"},
+		{"anonymous":false,"inputs":[
+			{"indexed":true,"name":"owner","type":"address"},
+			{"indexed":true,"name":"spender","type":"address"},
+			{"indexed":false,"name":"value","type":"uint256"}
+		],"name":"Approval","type":"event"}
+	]`
+)
+
+func newTestExplorer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		require.Equal(t, "contract", query.Get("module"))
+		require.Equal(t, "getabi", query.Get("action"))
+		require.Equal(t, "test-key", query.Get("apikey"))
+
+		resp := explorerResponse{Status: "0", Message: "NOTOK", Result: "Contract source code not verified"}
+		if strings.EqualFold(query.Get("address"), erc20Address) {
+			resp = explorerResponse{Status: "1", Message: "OK", Result: erc20ABI}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newTestValidator(t *testing.T, apiURL string) *Validator {
+	t.Helper()
+
+	return NewValidator(&config.ABIExplorerConfig{
+		APIURL:  apiURL,
+		APIKey:  "test-key",
+		Timeout: common.NewDuration(5 * time.Second),
+	}, logger.NewNopLogger())
+}
+
+func TestValidateEventSignature(t *testing.T) {
+	t.Parallel()
+
+	contractABI, err := gethabi.JSON(strings.NewReader(erc20ABI))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		signature   string
+		expectedErr string
+	}{
+		{
+			name:      "canonical signature",
+			signature: "Transfer(address,address,uint256)",
+		},
+		{
+			name:        "signature with parameter names",
+			signature:   "Transfer(address indexed from,address indexed to,uint256 value)",
+			expectedErr: "expected one of: Transfer(address,address,uint256)",
+		},
+		{
+			name:        "wrong parameter types",
+			signature:   "Approval(address,uint256)",
+			expectedErr: "does not match on-chain ABI",
+		},
+		{
+			name:        "unknown event",
+			signature:   "Deposit(address,uint256)",
+			expectedErr: "not found in on-chain ABI",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateEventSignature(&contractABI, tt.signature)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestValidator_FetchABI(t *testing.T) {
+	t.Parallel()
+
+	server := newTestExplorer(t)
+	validator := newTestValidator(t, server.URL)
+
+	contractABI, err := validator.FetchABI(t.Context(), ethcommon.HexToAddress("0x01"))
+	require.ErrorContains(t, err, "Contract source code not verified")
+	require.Nil(t, contractABI)
+
+	contractABI, err = validator.FetchABI(t.Context(), ethcommon.HexToAddress(erc20Address))
+	require.NoError(t, err)
+	require.Contains(t, contractABI.Events, "Transfer")
+	require.Contains(t, contractABI.Events, "Approval")
+}
+
+func TestValidator_ValidateIndexers(t *testing.T) {
+	t.Parallel()
+
+	server := newTestExplorer(t)
+	validator := newTestValidator(t, server.URL)
+
+	valid := []config.IndexerConfig{
+		{
+			Name: "erc20",
+			Contracts: []config.ContractConfig{
+				{
+					Address: erc20Address,
+					Events:  []string{"Transfer(address,address,uint256)", "Approval(address,address,uint256)"},
+				},
+			},
+		},
+	}
+	require.NoError(t, validator.ValidateIndexers(t.Context(), valid))
+
+	invalid := []config.IndexerConfig{
+		{
+			Name: "erc20",
+			Contracts: []config.ContractConfig{
+				{
+					Address: erc20Address,
+					Events:  []string{"Transfer(address indexed from,address indexed to,uint256 value)", "Mint(uint256)"},
+				},
+			},
+		},
+	}
+	err := validator.ValidateIndexers(t.Context(), invalid)
+	require.ErrorContains(t, err, "Transfer(address indexed from")
+	require.ErrorContains(t, err, "\"Mint(uint256)\" not found")
+
+	unverified := []config.IndexerConfig{
+		{
+			Name: "unverified",
+			Contracts: []config.ContractConfig{
+				{
+					Address: "0x0000000000000000000000000000000000000001",
+					Events:  []string{"Transfer(address,address,uint256)"},
+				},
+			},
+		},
+	}
+	err = validator.ValidateIndexers(t.Context(), unverified)
+	require.ErrorContains(t, err, "indexer unverified")
+}
@@ -0,0 +1,40 @@
+package alert
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/alert"
+)
+
+// Compile-time check to ensure LogManager implements alert.Manager interface.
+var _ alert.Manager = (*LogManager)(nil)
+
+// LogManager is an alert.Manager that writes alerts to the log.
+// It is the default manager when no other notification channel is configured.
+type LogManager struct {
+	log *logger.Logger
+}
+
+// NewLogManager creates a new LogManager.
+func NewLogManager(log *logger.Logger) *LogManager {
+	return &LogManager{log: log}
+}
+
+// Trigger logs the alert at warning level.
+func (m *LogManager) Trigger(_ context.Context, a alert.Alert) error {
+	fields := []any{
+		"type", a.Type,
+		"indexer", a.Indexer,
+		"recommended_action", a.RecommendedAction,
+	}
+	for _, k := range slices.Sorted(maps.Keys(a.Details)) {
+		fields = append(fields, k, a.Details[k])
+	}
+
+	m.log.Warnw("ALERT: "+a.Message, fields...)
+
+	return nil
+}
@@ -0,0 +1,603 @@
+// Package apigen generates an OpenAPI 3.1 document from the Swagger annotations of API handlers.
+// The types referenced by the annotations are resolved with go/types, so their schemas follow the
+// Go structs the handlers actually respond with.
+package apigen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+	"gopkg.in/yaml.v3"
+)
+
+// header is written before the generated document.
+const header = "# Code generated by api-gen from the annotations of the API handlers. DO NOT EDIT.\n"
+
+const yamlIndent = 2
+
+var (
+	// attributeRegexp matches the attributes following the description of a @Param, e.g. default(100)
+	attributeRegexp = regexp.MustCompile(`(\w+)\(([^)]*)\)`)
+
+	// pathParamRegexp matches the parameters of a path template, e.g. {name}
+	pathParamRegexp = regexp.MustCompile(`{([^}]+)}`)
+)
+
+// mimeTypes maps the aliases of @Accept and @Produce to media types.
+var mimeTypes = map[string]string{
+	"json":         "application/json",
+	"xml":          "application/xml",
+	"plain":        "text/plain",
+	"html":         "text/html",
+	"octet-stream": "application/octet-stream",
+	"event-stream": "text/event-stream",
+}
+
+// Generate reads the annotations of the Go package in dir and returns the OpenAPI document in YAML.
+func Generate(dir string) ([]byte, error) {
+	doc, err := Build(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(yamlIndent)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Build reads the annotations of the Go package in dir and returns the OpenAPI document.
+func Build(dir string) (*Document, error) {
+	// Dependencies are type-checked from source rather than read from export data,
+	// which ties the generator to the export data format of a specific Go release
+	cfg := &packages.Config{
+		Mode:  packages.LoadSyntax | packages.NeedDeps,
+		Dir:   dir,
+		Tests: false,
+	}
+
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load package: %w", err)
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected a single package in %s, found %d", dir, len(pkgs))
+	}
+
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, fmt.Errorf("failed to load package %s: %w", pkg.PkgPath, pkg.Errors[0])
+	}
+
+	g := &generator{
+		pkg:     pkg,
+		schemas: newSchemaBuilder(pkg.Fset),
+		doc: &Document{
+			OpenAPI: OpenAPIVersion,
+			Paths:   make(map[string]*PathItem),
+		},
+	}
+
+	for _, file := range pkg.Syntax {
+		if file.Doc != nil && g.doc.Info.Title == "" {
+			g.parseGeneralInfo(commentLines(file.Doc))
+		}
+	}
+	if g.doc.Info.Title == "" {
+		return nil, fmt.Errorf("no package comment of %s has a @title annotation", pkg.PkgPath)
+	}
+
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+
+			if err := g.parseOperation(file, fn); err != nil {
+				return nil, fmt.Errorf("%s: %w", pkg.Fset.Position(fn.Pos()), err)
+			}
+		}
+	}
+
+	g.doc.Components.Schemas = g.schemas.schemas
+
+	return g.doc, nil
+}
+
+// generator builds the OpenAPI document of a package.
+type generator struct {
+	pkg     *packages.Package
+	schemas *schemaBuilder
+	doc     *Document
+
+	// host, basePath and schemes are combined into the servers of the document
+	host     string
+	basePath string
+}
+
+// parseGeneralInfo reads the general API information from the package comment.
+func (g *generator) parseGeneralInfo(lines []string) {
+	info := &g.doc.Info
+	var schemes []string
+
+	for _, line := range lines {
+		attribute, value := splitAnnotation(line)
+
+		switch strings.ToLower(attribute) {
+		case "@title":
+			info.Title = value
+		case "@version":
+			info.Version = value
+		case "@description":
+			info.Description = joinLines(info.Description, value)
+		case "@contact.name":
+			g.contact().Name = value
+		case "@contact.url":
+			g.contact().URL = value
+		case "@contact.email":
+			g.contact().Email = value
+		case "@license.name":
+			g.license().Name = value
+		case "@license.url":
+			g.license().URL = value
+		case "@host":
+			g.host = value
+		case "@basepath":
+			g.basePath = value
+		case "@schemes":
+			schemes = strings.Fields(value)
+		default:
+			if extension, ok := strings.CutPrefix(attribute, "@x-"); ok {
+				if info.Extensions == nil {
+					info.Extensions = make(map[string]any)
+				}
+				info.Extensions["x-"+extension] = extensionValue(value)
+			}
+		}
+	}
+
+	if g.host == "" {
+		if g.basePath != "" {
+			g.doc.Servers = []Server{{URL: g.basePath}}
+		}
+		return
+	}
+
+	if len(schemes) == 0 {
+		schemes = []string{"http"}
+	}
+	for _, scheme := range schemes {
+		g.doc.Servers = append(g.doc.Servers, Server{URL: scheme + "://" + g.host + g.basePath})
+	}
+}
+
+func (g *generator) contact() *Contact {
+	if g.doc.Info.Contact == nil {
+		g.doc.Info.Contact = &Contact{}
+	}
+
+	return g.doc.Info.Contact
+}
+
+func (g *generator) license() *License {
+	if g.doc.Info.License == nil {
+		g.doc.Info.License = &License{}
+	}
+
+	return g.doc.Info.License
+}
+
+// parseOperation adds the operation described by the annotations of a handler.
+// Functions without a @Router annotation are not handlers and are skipped.
+func (g *generator) parseOperation(file *ast.File, fn *ast.FuncDecl) error {
+	lines := commentLines(fn.Doc)
+
+	op := &Operation{
+		OperationID: lowerFirst(fn.Name.Name),
+		Responses:   make(map[string]*Response),
+	}
+
+	var (
+		path, method string
+		accept       []string
+		produce      []string
+		params       []string
+		responses    []string
+	)
+
+	for _, line := range lines {
+		attribute, value := splitAnnotation(line)
+
+		switch strings.ToLower(attribute) {
+		case "@summary":
+			op.Summary = value
+		case "@description":
+			op.Description = joinLines(op.Description, value)
+		case "@tags":
+			for _, tag := range strings.Split(value, ",") {
+				op.Tags = append(op.Tags, strings.TrimSpace(tag))
+			}
+		case "@accept":
+			accept = append(accept, mimeTypeList(value)...)
+		case "@produce":
+			produce = append(produce, mimeTypeList(value)...)
+		case "@param":
+			// Parameters and responses are parsed last, as the media types they use may be annotated after them
+			params = append(params, value)
+		case "@success", "@failure":
+			responses = append(responses, value)
+		case "@router":
+			fields := strings.Fields(value)
+			if len(fields) != 2 {
+				return fmt.Errorf("invalid @Router %q: expected a path and a [method]", value)
+			}
+			path = fields[0]
+			method = strings.ToLower(strings.Trim(fields[1], "[]"))
+		}
+	}
+
+	if path == "" {
+		return nil
+	}
+
+	if len(accept) == 0 {
+		accept = []string{mimeTypes["json"]}
+	}
+	if len(produce) == 0 {
+		produce = []string{mimeTypes["json"]}
+	}
+
+	for _, value := range params {
+		if err := g.parseParam(file, op, value, accept); err != nil {
+			return fmt.Errorf("invalid @Param %q: %w", value, err)
+		}
+	}
+
+	for _, value := range responses {
+		if err := g.parseResponse(file, op, value, produce); err != nil {
+			return fmt.Errorf("invalid response %q: %w", value, err)
+		}
+	}
+
+	for _, match := range pathParamRegexp.FindAllStringSubmatch(path, -1) {
+		if !hasParameter(op, match[1], "path") {
+			return fmt.Errorf("path parameter %q of %s is not annotated", match[1], path)
+		}
+	}
+
+	return g.addOperation(path, method, op)
+}
+
+// addOperation adds the operation to the document under its path and method.
+func (g *generator) addOperation(path, method string, op *Operation) error {
+	item, ok := g.doc.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		g.doc.Paths[path] = item
+	}
+
+	var slot **Operation
+	switch method {
+	case "get":
+		slot = &item.Get
+	case "put":
+		slot = &item.Put
+	case "post":
+		slot = &item.Post
+	case "delete":
+		slot = &item.Delete
+	case "patch":
+		slot = &item.Patch
+	default:
+		return fmt.Errorf("unsupported method %q of %s", method, path)
+	}
+
+	if *slot != nil {
+		return fmt.Errorf("duplicate operation %s %s", strings.ToUpper(method), path)
+	}
+	*slot = op
+
+	return nil
+}
+
+// parseParam parses a @Param annotation: name in type required "description" [attributes].
+func (g *generator) parseParam(file *ast.File, op *Operation, value string, accept []string) error {
+	fields := strings.Fields(value)
+	if len(fields) < 4 { //nolint:mnd // name, in, type and required
+		return fmt.Errorf("expected name, in, type, required and description")
+	}
+	name, in, typeName := fields[0], fields[1], fields[2]
+
+	required, err := strconv.ParseBool(fields[3])
+	if err != nil {
+		return fmt.Errorf("invalid required %q", fields[3])
+	}
+
+	description, attributes := splitDescription(value)
+
+	if in == "body" {
+		schema, err := g.typeSchema(file, typeName)
+		if err != nil {
+			return err
+		}
+
+		op.RequestBody = &RequestBody{
+			Description: description,
+			Required:    required,
+			Content:     mediaTypes(accept, schema),
+		}
+		return nil
+	}
+
+	switch in {
+	case "path":
+		// Path parameters are always required
+		required = true
+	case "query", "header", "cookie":
+	default:
+		return fmt.Errorf("unsupported parameter location %q", in)
+	}
+
+	schema, err := g.typeSchema(file, typeName)
+	if err != nil {
+		return err
+	}
+
+	for _, match := range attributeRegexp.FindAllStringSubmatch(attributes, -1) {
+		switch strings.ToLower(match[1]) {
+		case "enums":
+			for _, enum := range strings.Split(match[2], ",") {
+				schema.Enum = append(schema.Enum, exampleValue(schema, strings.TrimSpace(enum)))
+			}
+		case "default":
+			schema.Default = exampleValue(schema, strings.TrimSpace(match[2]))
+		}
+	}
+
+	op.Parameters = append(op.Parameters, &Parameter{
+		Name:        name,
+		In:          in,
+		Description: description,
+		Required:    required,
+		Schema:      schema,
+	})
+
+	return nil
+}
+
+// parseResponse parses a @Success or @Failure annotation: code {kind} type "description".
+func (g *generator) parseResponse(file *ast.File, op *Operation, value string, produce []string) error {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return fmt.Errorf("expected a status code")
+	}
+
+	code, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return fmt.Errorf("invalid status code %q", fields[0])
+	}
+
+	description, _ := splitDescription(value)
+	if description == "" {
+		description = http.StatusText(code)
+	}
+
+	response := &Response{Description: description}
+
+	if len(fields) >= 3 && strings.HasPrefix(fields[1], "{") { //nolint:mnd // code, {kind} and type
+		kind := strings.Trim(fields[1], "{}")
+
+		schema, err := g.typeSchema(file, fields[2])
+		if err != nil {
+			return err
+		}
+
+		switch kind {
+		case "array":
+			schema = &Schema{Type: "array", Items: schema}
+		case "object", "string", "integer", "number", "boolean":
+		default:
+			return fmt.Errorf("unsupported response kind {%s}", kind)
+		}
+
+		response.Content = mediaTypes(produce, schema)
+	}
+
+	op.Responses[strconv.Itoa(code)] = response
+
+	return nil
+}
+
+// typeSchema returns the schema of a type named in an annotation: a primitive type,
+// a type of the package or a type of a package imported by the file, e.g. store.RetentionPreview.
+func (g *generator) typeSchema(file *ast.File, name string) (*Schema, error) {
+	switch name {
+	case "string":
+		return &Schema{Type: "string"}, nil
+	case "int", "integer":
+		return &Schema{Type: "integer"}, nil
+	case "number", "float":
+		return &Schema{Type: "number"}, nil
+	case "bool", "boolean":
+		return &Schema{Type: "boolean"}, nil
+	case "any", "interface{}", "object":
+		return &Schema{}, nil
+	}
+
+	scope := g.pkg.Types.Scope()
+	typeName := name
+	if qualifier, rest, ok := strings.Cut(name, "."); ok {
+		imported := g.importedPackage(file, qualifier)
+		if imported == nil {
+			return nil, fmt.Errorf("package %q of type %s is not imported", qualifier, name)
+		}
+		scope = imported.Scope()
+		typeName = rest
+	}
+
+	obj, ok := scope.Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found", name)
+	}
+
+	return g.schemas.schemaOf(obj.Type()), nil
+}
+
+// importedPackage returns the package the file imports under the name, or nil if there is none.
+func (g *generator) importedPackage(file *ast.File, name string) *types.Package {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		for _, imported := range g.pkg.Types.Imports() {
+			if imported.Path() != path {
+				continue
+			}
+
+			importName := imported.Name()
+			if spec.Name != nil {
+				importName = spec.Name.Name
+			}
+			if importName == name {
+				return imported
+			}
+		}
+	}
+
+	return nil
+}
+
+// mediaTypes returns the content of a body with the schema in each of the media types.
+func mediaTypes(mimes []string, schema *Schema) map[string]*MediaType {
+	content := make(map[string]*MediaType, len(mimes))
+	for _, mime := range mimes {
+		content[mime] = &MediaType{Schema: schema}
+	}
+
+	return content
+}
+
+// mimeTypeList converts a comma separated list of media types or their aliases to media types.
+func mimeTypeList(value string) []string {
+	var mimes []string
+	for _, mime := range strings.Split(value, ",") {
+		mime = strings.TrimSpace(mime)
+		if alias, ok := mimeTypes[mime]; ok {
+			mime = alias
+		}
+		if mime != "" {
+			mimes = append(mimes, mime)
+		}
+	}
+
+	return mimes
+}
+
+// hasParameter reports whether the operation has the named parameter in the location.
+func hasParameter(op *Operation, name, in string) bool {
+	for _, param := range op.Parameters {
+		if param.Name == name && param.In == in {
+			return true
+		}
+	}
+
+	return false
+}
+
+// commentLines returns the lines of a comment group, without the comment markers.
+func commentLines(group *ast.CommentGroup) []string {
+	var lines []string
+	for _, line := range strings.Split(group.Text(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// withoutAnnotations returns the lines of a comment that are not annotations.
+func withoutAnnotations(lines []string) []string {
+	var text []string
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "@") {
+			text = append(text, line)
+		}
+	}
+
+	return text
+}
+
+// splitAnnotation splits an annotation line into the attribute and its value.
+func splitAnnotation(line string) (string, string) {
+	attribute, value, _ := strings.Cut(line, " ")
+
+	return attribute, strings.TrimSpace(value)
+}
+
+// splitDescription returns the quoted description of an annotation value and what follows it.
+func splitDescription(value string) (string, string) {
+	start := strings.Index(value, `"`)
+	if start < 0 {
+		return "", ""
+	}
+
+	end := strings.Index(value[start+1:], `"`)
+	if end < 0 {
+		return value[start+1:], ""
+	}
+	end += start + 1
+
+	return value[start+1 : end], value[end+1:]
+}
+
+// extensionValue parses the value of an @x- extension as YAML, a superset of JSON.
+// Values that cannot be parsed are kept as strings.
+func extensionValue(value string) any {
+	var parsed any
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		return value
+	}
+
+	return parsed
+}
+
+// joinLines appends a line to a multi-line text.
+func joinLines(text, line string) string {
+	if text == "" {
+		return line
+	}
+
+	return text + "\n" + line
+}
+
+// lowerFirst returns the name with its first letter in lower case, e.g. GetEvents becomes getEvents.
+func lowerFirst(name string) string {
+	runes := []rune(name)
+	if len(runes) == 0 {
+		return name
+	}
+	runes[0] = unicode.ToLower(runes[0])
+
+	return string(runes)
+}
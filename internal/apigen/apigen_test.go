@@ -0,0 +1,337 @@
+package apigen
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	apiDir   = "../../pkg/api"
+	specFile = "../../pkg/api/docs/openapi.yaml"
+)
+
+// generateAPISpec generates the spec of the API package once, as loading it takes a few seconds.
+var generateAPISpec = sync.OnceValues(func() ([]byte, error) {
+	return Generate(apiDir)
+})
+
+// TestGenerate_UpToDate fails when the handler annotations changed without regenerating the checked-in spec.
+func TestGenerate_UpToDate(t *testing.T) {
+	t.Parallel()
+
+	generated, err := generateAPISpec()
+	require.NoError(t, err)
+
+	checkedIn, err := os.ReadFile(specFile)
+	require.NoError(t, err)
+
+	require.Equal(t, string(checkedIn), string(generated),
+		"%s is out of date, regenerate it with `make openapi`", specFile)
+}
+
+func TestGenerate_ValidOpenAPI31(t *testing.T) {
+	t.Parallel()
+
+	generated, err := generateAPISpec()
+	require.NoError(t, err)
+
+	var doc map[string]any
+	require.NoError(t, yaml.Unmarshal(generated, &doc))
+
+	validateOpenAPI31(t, doc)
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Build("testdata/petstore")
+	require.NoError(t, err)
+
+	t.Run("general info", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, OpenAPIVersion, doc.OpenAPI)
+		require.Equal(t, "Pet Store API", doc.Info.Title)
+		require.Equal(t, "2.0", doc.Info.Version)
+		require.Equal(t, "API for testing the OpenAPI generator", doc.Info.Description)
+		require.Equal(t, &License{Name: "MIT"}, doc.Info.License)
+		require.Nil(t, doc.Info.Contact)
+		require.Equal(t, map[string]any{"x-audience": "internal"}, doc.Info.Extensions)
+		require.Equal(t, []Server{{URL: "https://localhost:9090/v2"}}, doc.Servers)
+	})
+
+	t.Run("operations", func(t *testing.T) {
+		t.Parallel()
+
+		require.Len(t, doc.Paths, 2)
+
+		list := doc.Paths["/pets"].Get
+		require.NotNil(t, list)
+		require.Equal(t, "listPets", list.OperationID)
+		require.Equal(t, []string{"Pets", "Store"}, list.Tags)
+		require.Equal(t, "List the pets in the store,\noptionally filtered by tag", list.Description)
+		require.Equal(t, []*Parameter{
+			{
+				Name: "tag", In: "query", Description: "Tag to filter by",
+				Schema: &Schema{Type: "string", Enum: []any{"dog", "cat"}},
+			},
+			{
+				Name: "limit", In: "query", Description: "Maximum number of pets",
+				Schema: &Schema{Type: "integer", Default: int64(20)},
+			},
+		}, list.Parameters)
+		require.Equal(t, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/Pet"}},
+			list.Responses["200"].Content["application/json"].Schema)
+		require.Equal(t, "Internal error", list.Responses["500"].Description)
+
+		get := doc.Paths["/pets/{id}"].Get
+		require.NotNil(t, get)
+		require.True(t, get.Parameters[0].Required)
+		require.Equal(t, "path", get.Parameters[0].In)
+		// Responses without a description are described by their status text
+		require.Equal(t, "OK", get.Responses["200"].Description)
+
+		add := doc.Paths["/pets"].Post
+		require.NotNil(t, add)
+		require.Equal(t, &RequestBody{
+			Description: "Pet to add",
+			Required:    true,
+			Content:     map[string]*MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Pet"}}},
+		}, add.RequestBody)
+		require.Equal(t, &Schema{Type: "string"}, add.Responses["201"].Content["text/plain"].Schema)
+	})
+
+	t.Run("schemas", func(t *testing.T) {
+		t.Parallel()
+
+		require.ElementsMatch(t, []string{"Pet", "Owner", "StatsResponse", "ErrorResponse"},
+			keys(doc.Components.Schemas))
+
+		pet := doc.Components.Schemas["Pet"]
+		require.Equal(t, "object", pet.Type)
+		require.Equal(t, "A pet available for adoption", pet.Description)
+		require.Equal(t, []string{"id", "name", "tags", "chip", "born", "scores", "stats"}, pet.Required)
+		require.NotContains(t, pet.Properties, "Internal")
+		require.NotContains(t, pet.Properties, "unexposed")
+
+		zero := int64(0)
+		two := 2
+		require.Equal(t, &Schema{Type: "integer", Format: "int64", Description: "ID is the record ID"},
+			pet.Properties["id"])
+		require.Equal(t, &Schema{Type: "string", Description: "Pet name", Examples: []any{"Rex"}},
+			pet.Properties["name"])
+		require.Equal(t, &Schema{Type: "integer", Format: "int32", Minimum: &zero, Examples: []any{int64(3)}},
+			pet.Properties["age"])
+		require.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, pet.Properties["tags"])
+		require.Equal(t, &Schema{Ref: "#/components/schemas/Owner"}, pet.Properties["owner"])
+		require.Equal(t, &Schema{Type: "string"}, pet.Properties["chip"])
+		require.Equal(t, &Schema{Type: "string", Format: "date-time"}, pet.Properties["born"])
+		require.Equal(t, &Schema{Type: "string", Format: "byte"}, pet.Properties["photo"])
+		require.Equal(t, &Schema{
+			Type: "array", Items: &Schema{Type: "number", Format: "float"}, MinItems: &two, MaxItems: &two,
+		}, pet.Properties["scores"])
+		require.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{}}, pet.Properties["extra"])
+		require.Equal(t, &Schema{Ref: "#/components/schemas/StatsResponse"}, pet.Properties["stats"])
+		require.Equal(t, &Schema{
+			Type: "object", AdditionalProperties: &Schema{Type: "string"}, Description: "Free-form labels",
+		}, pet.Properties["labels"])
+
+		// Recursive types refer to their own schema
+		owner := doc.Components.Schemas["Owner"]
+		require.Equal(t, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/Pet"}},
+			owner.Properties["pets"])
+
+		// Aliased types of other packages are resolved with their doc comments
+		stats := doc.Components.Schemas["StatsResponse"]
+		require.Equal(t, "Statistics and status information for an indexer", stats.Description)
+		require.Equal(t, "Total number of events indexed", stats.Properties["total_events"].Description)
+	})
+}
+
+func TestBuild_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Build("testdata/does-not-exist")
+	require.Error(t, err)
+}
+
+func TestSplitDescription(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value       string
+		description string
+		attributes  string
+	}{
+		{value: `name path string true "Indexer name"`, description: "Indexer name"},
+		{
+			value:       `sort query string false "Sort order" Enums(asc, desc) default(asc)`,
+			description: "Sort order",
+			attributes:  " Enums(asc, desc) default(asc)",
+		},
+		{value: `200 {object} Pet`},
+		{value: `200 {object} Pet "unterminated`, description: "unterminated"},
+	}
+
+	for _, tt := range tests {
+		description, attributes := splitDescription(tt.value)
+		require.Equal(t, tt.description, description, tt.value)
+		require.Equal(t, tt.attributes, attributes, tt.value)
+	}
+}
+
+// validateOpenAPI31 checks the rules of the OpenAPI 3.1 specification that the generated documents must follow.
+func validateOpenAPI31(t *testing.T, doc map[string]any) {
+	t.Helper()
+
+	require.Equal(t, OpenAPIVersion, doc["openapi"])
+
+	info := asMap(t, doc["info"], "info")
+	require.NotEmpty(t, info["title"], "info.title is required")
+	require.NotEmpty(t, info["version"], "info.version is required")
+
+	for _, server := range asSlice(t, doc["servers"], "servers") {
+		require.NotEmpty(t, asMap(t, server, "server")["url"], "server url is required")
+	}
+
+	schemas := asMap(t, asMap(t, doc["components"], "components")["schemas"], "components.schemas")
+	componentName := regexp.MustCompile(`^[a-zA-Z0-9.\-_]+$`)
+	for name, schema := range schemas {
+		require.Regexp(t, componentName, name, "invalid component name")
+		validateSchema(t, schemas, schema, "components.schemas."+name)
+	}
+
+	operationIDs := make(map[string]bool)
+	methods := map[string]bool{"get": true, "put": true, "post": true, "delete": true, "patch": true}
+	for path, item := range asMap(t, doc["paths"], "paths") {
+		require.True(t, strings.HasPrefix(path, "/"), "path %s must start with /", path)
+
+		for method, value := range asMap(t, item, path) {
+			require.True(t, methods[method], "unexpected field %s of path %s", method, path)
+			location := method + " " + path
+			op := asMap(t, value, location)
+
+			id, _ := op["operationId"].(string)
+			require.NotEmpty(t, id, "%s: operationId is required", location)
+			require.False(t, operationIDs[id], "%s: duplicate operationId %s", location, id)
+			operationIDs[id] = true
+
+			pathParams := make(map[string]bool)
+			for _, value := range asSlice(t, op["parameters"], location+" parameters") {
+				param := asMap(t, value, location+" parameter")
+				require.NotEmpty(t, param["name"], "%s: parameter name is required", location)
+				require.Contains(t, []any{"query", "header", "path", "cookie"}, param["in"], location)
+				if param["in"] == "path" {
+					require.Equal(t, true, param["required"], "%s: path parameters must be required", location)
+					pathParams[param["name"].(string)] = true
+				}
+				validateSchema(t, schemas, param["schema"], location+" parameter "+param["name"].(string))
+			}
+
+			for _, match := range regexp.MustCompile(`{([^}]+)}`).FindAllStringSubmatch(path, -1) {
+				require.True(t, pathParams[match[1]], "%s: path parameter %s is not defined", location, match[1])
+			}
+
+			if body, ok := op["requestBody"]; ok {
+				validateContent(t, schemas, asMap(t, body, location+" requestBody")["content"], location)
+			}
+
+			responses := asMap(t, op["responses"], location+" responses")
+			require.NotEmpty(t, responses, "%s: at least one response is required", location)
+			for code, value := range responses {
+				require.Regexp(t, `^[1-5]\d\d$`, code, location)
+				response := asMap(t, value, location+" response "+code)
+				require.NotEmpty(t, response["description"], "%s: response %s needs a description", location, code)
+				if content, ok := response["content"]; ok {
+					validateContent(t, schemas, content, location)
+				}
+			}
+		}
+	}
+}
+
+// validateContent checks the media types of a request or response body.
+func validateContent(t *testing.T, schemas map[string]any, content any, location string) {
+	t.Helper()
+
+	for mime, value := range asMap(t, content, location+" content") {
+		require.Contains(t, mime, "/", "%s: invalid media type %s", location, mime)
+		validateSchema(t, schemas, asMap(t, value, location+" "+mime)["schema"], location+" "+mime)
+	}
+}
+
+// validateSchema checks that a schema uses JSON Schema types and that its references resolve.
+func validateSchema(t *testing.T, schemas map[string]any, value any, location string) {
+	t.Helper()
+
+	schema := asMap(t, value, location)
+
+	if ref, ok := schema["$ref"]; ok {
+		name, found := strings.CutPrefix(ref.(string), schemaRefPrefix)
+		require.True(t, found, "%s: unexpected reference %s", location, ref)
+		require.Contains(t, schemas, name, "%s: unresolved reference %s", location, ref)
+	}
+
+	if typ, ok := schema["type"]; ok {
+		require.Contains(t, []any{"string", "integer", "number", "boolean", "array", "object", "null"}, typ, location)
+	}
+	if examples, ok := schema["examples"]; ok {
+		asSlice(t, examples, location+" examples")
+	}
+
+	for name, property := range asMap(t, schema["properties"], location+" properties") {
+		validateSchema(t, schemas, property, location+"."+name)
+	}
+	for _, name := range asSlice(t, schema["required"], location+" required") {
+		require.Contains(t, schema["properties"], name, "%s: required property %s is not defined", location, name)
+	}
+	if items, ok := schema["items"]; ok {
+		validateSchema(t, schemas, items, location+" items")
+	}
+	if additional, ok := schema["additionalProperties"]; ok {
+		validateSchema(t, schemas, additional, location+" additionalProperties")
+	}
+}
+
+// asMap returns the value as a map, which is empty if the value is missing.
+func asMap(t *testing.T, value any, location string) map[string]any {
+	t.Helper()
+
+	if value == nil {
+		return map[string]any{}
+	}
+
+	m, ok := value.(map[string]any)
+	require.True(t, ok, "%s: expected an object, got %T", location, value)
+
+	return m
+}
+
+// asSlice returns the value as a slice, which is empty if the value is missing.
+func asSlice(t *testing.T, value any, location string) []any {
+	t.Helper()
+
+	if value == nil {
+		return nil
+	}
+
+	s, ok := value.([]any)
+	require.True(t, ok, "%s: expected an array, got %T", location, value)
+
+	return s
+}
+
+func keys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+
+	return result
+}
@@ -0,0 +1,344 @@
+package apigen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// schemaRefPrefix is the prefix of references to the schemas of the components.
+const schemaRefPrefix = "#/components/schemas/"
+
+// schemaBuilder builds the JSON schemas of Go types, as encoding/json marshals them.
+// Named struct types become component schemas that are referenced by name.
+type schemaBuilder struct {
+	fset *token.FileSet
+
+	// parsed holds the positions of the files parsed to read doc comments, which are
+	// matched with the declarations of the types by line and column
+	parsed *token.FileSet
+
+	// schemas are the component schemas by name, names the Go types they were built for
+	schemas map[string]*Schema
+	names   map[*types.TypeName]string
+
+	// files caches the parsed source files, to read the doc comments of types and fields
+	files map[string]*ast.File
+}
+
+func newSchemaBuilder(fset *token.FileSet) *schemaBuilder {
+	return &schemaBuilder{
+		fset:    fset,
+		parsed:  token.NewFileSet(),
+		schemas: make(map[string]*Schema),
+		names:   make(map[*types.TypeName]string),
+		files:   make(map[string]*ast.File),
+	}
+}
+
+// schemaOf returns the schema of a Go type.
+func (b *schemaBuilder) schemaOf(typ types.Type) *Schema {
+	typ = types.Unalias(typ)
+
+	if named, ok := typ.(*types.Named); ok {
+		if schema, ok := b.wellKnownSchema(named); ok {
+			return schema
+		}
+
+		if _, ok := named.Underlying().(*types.Struct); ok {
+			return &Schema{Ref: schemaRefPrefix + b.register(named)}
+		}
+	}
+
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		return basicSchema(t)
+	case *types.Pointer:
+		return b.schemaOf(t.Elem())
+	case *types.Slice:
+		if basic, ok := t.Elem().(*types.Basic); ok && basic.Kind() == types.Byte {
+			// encoding/json marshals byte slices as base64 strings
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schemaOf(t.Elem())}
+	case *types.Array:
+		length := int(t.Len())
+		return &Schema{Type: "array", Items: b.schemaOf(t.Elem()), MinItems: &length, MaxItems: &length}
+	case *types.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+	case *types.Struct:
+		return b.structSchema(t)
+	default:
+		// Interfaces can hold any value
+		return &Schema{}
+	}
+}
+
+// wellKnownSchema returns the schema of types with a custom JSON encoding.
+func (b *schemaBuilder) wellKnownSchema(named *types.Named) (*Schema, bool) {
+	obj := named.Obj()
+	if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+		return &Schema{Type: "string", Format: "date-time"}, true
+	}
+
+	// Types marshaled by encoding/json as JSON are described by their Go type, which may not match
+	if hasMethod(named, "MarshalJSON") {
+		return &Schema{}, true
+	}
+
+	// Types implementing encoding.TextMarshaler are marshaled as strings, like addresses and hashes
+	if hasMethod(named, "MarshalText") {
+		return &Schema{Type: "string"}, true
+	}
+
+	return nil, false
+}
+
+// register adds the component schema of a named struct type and returns its name.
+func (b *schemaBuilder) register(named *types.Named) string {
+	obj := named.Obj()
+	if name, ok := b.names[obj]; ok {
+		return name
+	}
+
+	// Types of different packages with the same name are told apart by their package name
+	name := obj.Name()
+	if _, taken := b.schemas[name]; taken && obj.Pkg() != nil {
+		name = obj.Pkg().Name() + "." + name
+	}
+
+	// The name is registered before the schema is built, so recursive types refer to themselves
+	b.names[obj] = name
+	b.schemas[name] = &Schema{}
+
+	schema := b.structSchema(named.Underlying().(*types.Struct))
+	schema.Description = b.typeDescription(obj)
+	b.schemas[name] = schema
+
+	return name
+}
+
+// structSchema returns the schema of the JSON object a struct is marshaled to.
+func (b *schemaBuilder) structSchema(st *types.Struct) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	b.addFields(schema, st)
+
+	return schema
+}
+
+// addFields adds the exported fields of the struct to the object schema.
+// Fields of embedded structs without a JSON name are promoted, like encoding/json does.
+func (b *schemaBuilder) addFields(schema *Schema, st *types.Struct) {
+	for i := range st.NumFields() {
+		field := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+
+		jsonTag := tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(jsonTag, ",")
+		if field.Embedded() && name == "" {
+			fieldType := types.Unalias(field.Type())
+			if ptr, ok := fieldType.(*types.Pointer); ok {
+				fieldType = types.Unalias(ptr.Elem())
+			}
+			if embedded, ok := fieldType.Underlying().(*types.Struct); ok {
+				b.addFields(schema, embedded)
+				continue
+			}
+		}
+
+		if !field.Exported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name()
+		}
+
+		fieldSchema := b.schemaOf(field.Type())
+		if fieldSchema.Ref != "" {
+			// The referenced schema is shared, so the field's description goes next to the reference
+			fieldSchema = &Schema{Ref: fieldSchema.Ref}
+		}
+
+		fieldSchema.Description = tag.Get("description")
+		if fieldSchema.Description == "" {
+			fieldSchema.Description = b.fieldComment(field)
+		}
+		if example, ok := tag.Lookup("example"); ok {
+			fieldSchema.Examples = []any{exampleValue(fieldSchema, example)}
+		}
+
+		schema.Properties[name] = fieldSchema
+		if !strings.Contains(options, "omitempty") && !strings.Contains(options, "omitzero") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// typeDescription returns the @Description annotation of a type declaration,
+// falling back to its doc comment.
+func (b *schemaBuilder) typeDescription(obj *types.TypeName) string {
+	file := b.file(obj.Pos())
+	if file == nil {
+		return ""
+	}
+	pos := b.fset.Position(obj.Pos())
+
+	var doc *ast.CommentGroup
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch decl := node.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok && b.samePosition(typeSpec.Name.Pos(), pos) {
+					doc = typeSpec.Doc
+					if doc == nil {
+						doc = decl.Doc
+					}
+				}
+			}
+			return false
+		default:
+			return true
+		}
+	})
+
+	if doc == nil {
+		return ""
+	}
+
+	lines := commentLines(doc)
+	for _, line := range lines {
+		if value, ok := strings.CutPrefix(line, "@Description "); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+
+	return strings.Join(withoutAnnotations(lines), " ")
+}
+
+// fieldComment returns the doc comment of a struct field, or its line comment if it has none.
+func (b *schemaBuilder) fieldComment(field *types.Var) string {
+	file := b.file(field.Pos())
+	if file == nil {
+		return ""
+	}
+	pos := b.fset.Position(field.Pos())
+
+	var comment string
+	ast.Inspect(file, func(node ast.Node) bool {
+		astField, ok := node.(*ast.Field)
+		if !ok {
+			return comment == ""
+		}
+
+		for _, name := range astField.Names {
+			if !b.samePosition(name.Pos(), pos) {
+				continue
+			}
+
+			group := astField.Doc
+			if group == nil {
+				group = astField.Comment
+			}
+			if group != nil {
+				comment = strings.Join(withoutAnnotations(commentLines(group)), " ")
+			}
+		}
+
+		return false
+	})
+
+	return comment
+}
+
+// file returns the parsed source file containing the position, or nil if it cannot be parsed.
+func (b *schemaBuilder) file(pos token.Pos) *ast.File {
+	filename := b.fset.Position(pos).Filename
+	if filename == "" {
+		return nil
+	}
+
+	if file, ok := b.files[filename]; ok {
+		return file
+	}
+
+	file, err := parser.ParseFile(b.parsed, filename, nil, parser.ParseComments)
+	if err != nil {
+		file = nil
+	}
+	b.files[filename] = file
+
+	return file
+}
+
+// samePosition reports whether a position in a parsed file is the declaration position.
+func (b *schemaBuilder) samePosition(parsed token.Pos, declared token.Position) bool {
+	position := b.parsed.Position(parsed)
+
+	return position.Line == declared.Line && position.Column == declared.Column
+}
+
+// basicSchema returns the schema of a basic type.
+func basicSchema(basic *types.Basic) *Schema {
+	zero := int64(0)
+
+	switch basic.Kind() {
+	case types.Bool:
+		return &Schema{Type: "boolean"}
+	case types.String:
+		return &Schema{Type: "string"}
+	case types.Int, types.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case types.Int8, types.Int16, types.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case types.Uint, types.Uint64, types.Uintptr:
+		return &Schema{Type: "integer", Format: "int64", Minimum: &zero}
+	case types.Uint8, types.Uint16, types.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Minimum: &zero}
+	case types.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case types.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	default:
+		return &Schema{}
+	}
+}
+
+// exampleValue converts an example or default value written in an annotation to the schema's type.
+// Values that cannot be converted are kept as strings.
+func exampleValue(schema *Schema, value string) any {
+	switch schema.Type {
+	case "integer":
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+		if v, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+
+	return value
+}
+
+// hasMethod reports whether the type or a pointer to it has the method.
+func hasMethod(named *types.Named, method string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), true, named.Obj().Pkg(), method)
+	_, ok := obj.(*types.Func)
+
+	return ok
+}
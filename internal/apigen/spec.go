@@ -0,0 +1,111 @@
+package apigen
+
+// OpenAPIVersion is the version of the OpenAPI specification the generated documents follow.
+const OpenAPIVersion = "3.1.0"
+
+// Document is the root of an OpenAPI document.
+type Document struct {
+	OpenAPI    string               `yaml:"openapi"`
+	Info       Info                 `yaml:"info"`
+	Servers    []Server             `yaml:"servers,omitempty"`
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components Components           `yaml:"components,omitempty"`
+}
+
+// Info is the metadata of the API.
+type Info struct {
+	Title       string         `yaml:"title"`
+	Description string         `yaml:"description,omitempty"`
+	Contact     *Contact       `yaml:"contact,omitempty"`
+	License     *License       `yaml:"license,omitempty"`
+	Version     string         `yaml:"version"`
+	Extensions  map[string]any `yaml:",inline"`
+}
+
+// Contact is the contact information of the API.
+type Contact struct {
+	Name  string `yaml:"name,omitempty"`
+	URL   string `yaml:"url,omitempty"`
+	Email string `yaml:"email,omitempty"`
+}
+
+// License is the license of the API.
+type License struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url,omitempty"`
+}
+
+// Server is a server the API is served from.
+type Server struct {
+	URL string `yaml:"url"`
+}
+
+// PathItem holds the operations of a path.
+type PathItem struct {
+	Get    *Operation `yaml:"get,omitempty"`
+	Put    *Operation `yaml:"put,omitempty"`
+	Post   *Operation `yaml:"post,omitempty"`
+	Delete *Operation `yaml:"delete,omitempty"`
+	Patch  *Operation `yaml:"patch,omitempty"`
+}
+
+// Operation is a single API operation on a path.
+type Operation struct {
+	Tags        []string             `yaml:"tags,omitempty"`
+	Summary     string               `yaml:"summary,omitempty"`
+	Description string               `yaml:"description,omitempty"`
+	OperationID string               `yaml:"operationId"`
+	Parameters  []*Parameter         `yaml:"parameters,omitempty"`
+	RequestBody *RequestBody         `yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+// Parameter is a path, query or header parameter of an operation.
+type Parameter struct {
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description,omitempty"`
+	Required    bool    `yaml:"required,omitempty"`
+	Schema      *Schema `yaml:"schema"`
+}
+
+// RequestBody is the request body of an operation.
+type RequestBody struct {
+	Description string                `yaml:"description,omitempty"`
+	Required    bool                  `yaml:"required,omitempty"`
+	Content     map[string]*MediaType `yaml:"content"`
+}
+
+// Response is a response of an operation.
+type Response struct {
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content,omitempty"`
+}
+
+// MediaType describes the content of a request or response body of one media type.
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Components holds the schemas referenced from the operations.
+type Components struct {
+	Schemas map[string]*Schema `yaml:"schemas,omitempty"`
+}
+
+// Schema is a JSON Schema (draft 2020-12), the schema dialect of OpenAPI 3.1.
+type Schema struct {
+	Ref                  string             `yaml:"$ref,omitempty"`
+	Type                 string             `yaml:"type,omitempty"`
+	Format               string             `yaml:"format,omitempty"`
+	Description          string             `yaml:"description,omitempty"`
+	Enum                 []any              `yaml:"enum,omitempty"`
+	Default              any                `yaml:"default,omitempty"`
+	Examples             []any              `yaml:"examples,omitempty"`
+	Minimum              *int64             `yaml:"minimum,omitempty"`
+	Items                *Schema            `yaml:"items,omitempty"`
+	MinItems             *int               `yaml:"minItems,omitempty"`
+	MaxItems             *int               `yaml:"maxItems,omitempty"`
+	Properties           map[string]*Schema `yaml:"properties,omitempty"`
+	AdditionalProperties *Schema            `yaml:"additionalProperties,omitempty"`
+	Required             []string           `yaml:"required,omitempty"`
+}
@@ -0,0 +1,96 @@
+// Package petstore is an annotated API used to test the generator.
+// @title Pet Store API
+// @version 2.0
+// @description API for testing the OpenAPI generator
+// @license.name MIT
+// @host localhost:9090
+// @basePath /v2
+// @schemes https
+// @x-audience internal
+package petstore
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+)
+
+// Address is marshaled as a string, like addresses of go-ethereum.
+type Address [20]byte
+
+// MarshalText implements encoding.TextMarshaler.
+func (a Address) MarshalText() ([]byte, error) { return nil, nil }
+
+// Base holds the fields shared by all records.
+type Base struct {
+	// ID is the record ID
+	ID int64 `json:"id"`
+}
+
+// Pet is a pet in the store.
+// @Description A pet available for adoption
+type Pet struct {
+	Base
+
+	Name      string            `json:"name" example:"Rex" description:"Pet name"`
+	Age       uint8             `json:"age,omitempty" example:"3"`
+	Tags      []string          `json:"tags"`
+	Owner     *Owner            `json:"owner,omitempty"`
+	Chip      Address           `json:"chip"`
+	Born      time.Time         `json:"born"`
+	Photo     []byte            `json:"photo,omitempty"`
+	Scores    [2]float32        `json:"scores"`
+	Extra     map[string]any    `json:"extra,omitempty"`
+	Stats     StatsResponse     `json:"stats"`
+	Internal  string            `json:"-"`
+	unexposed string            //nolint:unused // tests that unexported fields are skipped
+	Labels    map[string]string `json:"labels,omitempty"` // Free-form labels
+}
+
+// Owner owns pets.
+type Owner struct {
+	Name string `json:"name"`
+	Pets []Pet  `json:"pets"`
+}
+
+// StatsResponse is an alias of a type of another package.
+type StatsResponse = indexer.StatsResponse
+
+// ErrorResponse is returned on errors.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// ListPets lists pets.
+// @Summary List pets
+// @Description List the pets in the store,
+// @Description optionally filtered by tag
+// @Tags Pets, Store
+// @Produce json
+// @Param tag query string false "Tag to filter by" Enums(dog, cat)
+// @Param limit query int false "Maximum number of pets" default(20)
+// @Success 200 {array} Pet "Pets"
+// @Failure 500 {object} ErrorResponse "Internal error"
+// @Router /pets [get]
+func ListPets(w http.ResponseWriter, r *http.Request) {}
+
+// GetPet returns a pet.
+// @Summary Get a pet
+// @Param id path integer true "Pet ID"
+// @Success 200 {object} Pet
+// @Failure 404 {object} ErrorResponse "Pet not found"
+// @Router /pets/{id} [get]
+func GetPet(w http.ResponseWriter, r *http.Request) {}
+
+// AddPet adds a pet.
+// @Summary Add a pet
+// @Accept json
+// @Produce plain
+// @Param pet body Pet true "Pet to add"
+// @Success 201 {string} string "ID of the added pet"
+// @Router /pets [post]
+func AddPet(w http.ResponseWriter, r *http.Request) {}
+
+// helper has no annotations and is not an operation.
+func helper() {}
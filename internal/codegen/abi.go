@@ -0,0 +1,173 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// abiEntry is an entry of a JSON contract ABI. Only the fields of events are decoded.
+type abiEntry struct {
+	Type      string         `json:"type"`
+	Name      string         `json:"name"`
+	Inputs    []abiParameter `json:"inputs"`
+	Anonymous bool           `json:"anonymous"`
+}
+
+// abiParameter is an input of a JSON ABI entry. Components hold the fields of tuple types.
+type abiParameter struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Indexed    bool           `json:"indexed"`
+	Components []abiParameter `json:"components,omitempty"`
+}
+
+// ParseABIFile reads the JSON ABI array at path and returns the event signatures it declares,
+// in the order of the file, in the format accepted by ParseEventSignature.
+// Example: "Transfer(address indexed from, address indexed to, uint256 value)"
+// Only the events named in eventNames are returned, or all events when eventNames is empty.
+// Tuple parameters are expanded to their Solidity form, e.g. "(address token, uint256 amount)[]".
+func ParseABIFile(path string, eventNames []string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ABI file: %w", err)
+	}
+
+	var entries []abiEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse ABI file %s: %w", path, err)
+	}
+
+	wanted := make(map[string]bool, len(eventNames))
+	for _, name := range eventNames {
+		if name = strings.TrimSpace(name); name != "" {
+			wanted[name] = false
+		}
+	}
+
+	var signatures []string
+	for _, entry := range entries {
+		if entry.Type != "event" {
+			continue
+		}
+
+		if len(wanted) > 0 {
+			if _, ok := wanted[entry.Name]; !ok {
+				continue
+			}
+			wanted[entry.Name] = true
+		}
+
+		signature, err := abiEventSignature(entry)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", entry.Name, err)
+		}
+
+		signatures = append(signatures, signature)
+	}
+
+	for _, name := range eventNames {
+		if found, ok := wanted[strings.TrimSpace(name)]; ok && !found {
+			return nil, fmt.Errorf("event %s not found in ABI file %s", name, path)
+		}
+	}
+
+	if len(signatures) == 0 {
+		return nil, fmt.Errorf("no events found in ABI file %s", path)
+	}
+
+	return signatures, nil
+}
+
+// abiEventSignature builds the Solidity signature of an ABI event, keeping parameter names
+// and indexed keywords.
+func abiEventSignature(entry abiEntry) (string, error) {
+	if entry.Anonymous {
+		return "", fmt.Errorf("anonymous events are not supported")
+	}
+
+	params := make([]string, len(entry.Inputs))
+	for i, input := range entry.Inputs {
+		typ, err := abiTypeName(input)
+		if err != nil {
+			return "", err
+		}
+
+		parts := []string{typ}
+		if input.Indexed {
+			parts = append(parts, "indexed")
+		}
+		if input.Name != "" {
+			parts = append(parts, input.Name)
+		}
+
+		params[i] = strings.Join(parts, " ")
+	}
+
+	return entry.Name + "(" + strings.Join(params, ", ") + ")", nil
+}
+
+// abiTypeName returns the Solidity type of an ABI parameter. Tuples, including arrays
+// of tuples like "tuple[]", are recursively expanded into their named component types.
+func abiTypeName(param abiParameter) (string, error) {
+	suffix, isTuple := strings.CutPrefix(param.Type, "tuple")
+	if !isTuple {
+		return param.Type, nil
+	}
+
+	if len(param.Components) == 0 {
+		return "", fmt.Errorf("tuple parameter %s has no components", param.Name)
+	}
+
+	components := make([]string, len(param.Components))
+	for i, component := range param.Components {
+		typ, err := abiTypeName(component)
+		if err != nil {
+			return "", err
+		}
+		if component.Name != "" {
+			typ += " " + component.Name
+		}
+		components[i] = typ
+	}
+
+	return "(" + strings.Join(components, ", ") + ")" + suffix, nil
+}
+
+// EventsABIJSON returns the JSON ABI array declaring the events, the inverse of ParseABIFile.
+func EventsABIJSON(events []*EventSignature) (string, error) {
+	entries := make([]abiEntry, len(events))
+	for i, event := range events {
+		inputs := make([]abiParameter, len(event.Params))
+		for j, param := range event.Params {
+			inputs[j] = eventABIParameter(param)
+		}
+
+		entries[i] = abiEntry{Type: "event", Name: event.Name, Inputs: inputs}
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode events ABI: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// eventABIParameter returns the JSON ABI input of an event parameter. Tuple types are declared
+// as "tuple" with their fields as components, keeping array suffixes like "tuple[]".
+func eventABIParameter(param EventParam) abiParameter {
+	input := abiParameter{Name: param.Name, Type: param.Type, Indexed: param.Indexed}
+	if !param.IsTuple() {
+		return input
+	}
+
+	input.Type = "tuple" + param.Type[strings.LastIndex(param.Type, ")")+1:]
+	input.Components = make([]abiParameter, len(param.Components))
+	for i, component := range param.Components {
+		input.Components[i] = eventABIParameter(component)
+	}
+
+	return input
+}
@@ -0,0 +1,117 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseABIFile(t *testing.T) {
+	const erc20ABI = "testdata/erc20.abi.json"
+
+	tests := []struct {
+		name       string
+		path       string
+		eventNames []string
+		want       []string
+		wantErr    string
+	}{
+		{
+			name: "all events",
+			path: erc20ABI,
+			want: []string{
+				"Transfer(address indexed from, address indexed to, uint256 value)",
+				"Approval(address indexed owner, address indexed spender, uint256 value)",
+			},
+		},
+		{
+			name:       "selected events",
+			path:       erc20ABI,
+			eventNames: []string{"Approval"},
+			want:       []string{"Approval(address indexed owner, address indexed spender, uint256 value)"},
+		},
+		{
+			name:       "unknown event",
+			path:       erc20ABI,
+			eventNames: []string{"Transfer", "Mint"},
+			wantErr:    "event Mint not found",
+		},
+		{
+			name:    "missing file",
+			path:    "testdata/missing.abi.json",
+			wantErr: "failed to read ABI file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseABIFile(tt.path, tt.eventNames)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+
+			// The signatures are accepted by the signature parser the generator uses
+			for _, sig := range got {
+				_, err := ParseEventSignature(sig)
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseABIFile_Tuples(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.abi.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{
+		"type": "event",
+		"name": "OrderFilled",
+		"inputs": [
+			{"name": "maker", "type": "address", "indexed": true},
+			{"name": "order", "type": "tuple", "components": [
+				{"name": "token", "type": "address"},
+				{"name": "amounts", "type": "tuple[]", "components": [
+					{"name": "value", "type": "uint256"},
+					{"name": "fee", "type": "uint16"}
+				]}
+			]},
+			{"name": "", "type": "bytes32", "indexed": true}
+		]
+	}]`), 0600))
+
+	got, err := ParseABIFile(path, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"OrderFilled(address indexed maker, (address token, (uint256 value, uint16 fee)[] amounts) order, bytes32 indexed)",
+	}, got)
+}
+
+func TestEventsABIJSON(t *testing.T) {
+	signatures := []string{
+		"TransferSingle(address indexed operator, address indexed from, address indexed to, uint256 id, uint256 value)",
+		"TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values)",
+		"OrderFilled(bytes32 indexed orderHash, (address maker, address taker, uint256 amount) order)",
+	}
+
+	events := make([]*EventSignature, len(signatures))
+	for i, sig := range signatures {
+		event, err := ParseEventSignature(sig)
+		require.NoError(t, err)
+		events[i] = event
+	}
+
+	abiJSON, err := EventsABIJSON(events)
+	require.NoError(t, err)
+
+	// Parsing the ABI back yields the original signatures
+	path := filepath.Join(t.TempDir(), "erc1155.abi.json")
+	require.NoError(t, os.WriteFile(path, []byte(abiJSON), 0600))
+
+	got, err := ParseABIFile(path, nil)
+	require.NoError(t, err)
+	require.Equal(t, signatures, got)
+}
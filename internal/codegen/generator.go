@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -12,25 +13,49 @@ const (
 	filePerm  = 0644
 )
 
+// Decoders of the non-indexed event parameters in the generated indexers.
+const (
+	// DecoderRaw reads every non-indexed parameter from a 32-byte word of the log data,
+	// which only supports static types
+	DecoderRaw = "raw"
+	// DecoderABI decodes the log data with go-ethereum's ABI package, which also supports
+	// dynamic types like arrays, strings and bytes
+	DecoderABI = "abi"
+)
+
+// DefaultMaxStringLength is the default maximum length in bytes of the stored string parameters.
+const DefaultMaxStringLength = 1024
+
 // Generator generates indexer code from event signatures.
 type Generator struct {
-	Name       string   // Indexer name (e.g., "ERC20Token")
-	Package    string   // Go package name (e.g., "erc20token")
-	Events     []string // Event signatures
-	OutputDir  string   // Output directory path
-	ImportPath string   // Go module import path
-	Force      bool     // Overwrite existing files
-	DryRun     bool     // Don't write files, just show what would be generated
+	Name        string   // Indexer name (e.g., "ERC20Token")
+	Package     string   // Go package name (e.g., "erc20token")
+	Type        string   // Indexer type the indexer is registered as (e.g., "erc20token")
+	Events      []string // Event signatures
+	OutputDir   string   // Output directory path
+	ImportPath  string   // Go module import path
+	Force       bool     // Overwrite existing files
+	DryRun      bool     // Don't write files, just show what would be generated
+	Decoder     string   // Decoder of non-indexed parameters, DecoderRaw (default) or DecoderABI
+	TemplateDir string   // Directory of custom *.tmpl templates, overriding built-ins of the same name
+	SDK         bool     // Also generate a Go client of the REST API and its in-memory mock
+
+	// MaxStringLength is the maximum length in bytes of the stored string parameters, longer strings
+	// are truncated when the events are written (default: DefaultMaxStringLength)
+	MaxStringLength int
 }
 
 // GeneratedFiles represents the files that were generated.
 type GeneratedFiles struct {
-	IndexerFile    string // Path to indexer.go
-	ModelsFile     string // Path to models.go
-	RegisterFile   string // Path to register.go
-	APIFile        string // Path to api.go
-	MigrationsFile string // Path to migrations/migrations.go
-	ReadmeFile     string // Path to README.md
+	IndexerFile    string   // Path to indexer.go
+	ModelsFile     string   // Path to models.go
+	RegisterFile   string   // Path to register.go
+	APIFile        string   // Path to api.go
+	MigrationsFile string   // Path to migrations/migrations.go
+	ReadmeFile     string   // Path to README.md
+	ClientFile     string   // Path to <package>_client.go, only generated with the SDK
+	MockClientFile string   // Path to <package>_mock_client.go, only generated with the SDK
+	CustomFiles    []string // Paths to the files of the custom templates
 }
 
 // Generate generates all indexer files.
@@ -46,11 +71,30 @@ func (g *Generator) Generate() (*GeneratedFiles, error) {
 		return nil, fmt.Errorf("failed to parse events: %w", err)
 	}
 
+	// Decode non-indexed parameters from raw data words if no decoder is provided
+	if g.Decoder == "" {
+		g.Decoder = DecoderRaw
+	}
+
+	if err := validateTuples(events, g.Decoder); err != nil {
+		return nil, err
+	}
+
+	// Cap the stored strings if no maximum length is provided
+	if g.MaxStringLength == 0 {
+		g.MaxStringLength = DefaultMaxStringLength
+	}
+
 	// Determine package name if not provided
 	if g.Package == "" {
 		g.Package = strings.ToLower(g.Name)
 	}
 
+	// Determine indexer type if not provided
+	if g.Type == "" {
+		g.Type = ToLowerCamelCase(g.Name)
+	}
+
 	// Determine output directory if not provided
 	if g.OutputDir == "" {
 		g.OutputDir = filepath.Join(".", "indexers", g.Package)
@@ -74,8 +118,20 @@ func (g *Generator) Generate() (*GeneratedFiles, error) {
 	data := &TemplateData{
 		Name:       g.Name,
 		Package:    g.Package,
+		Type:       g.Type,
 		ImportPath: g.ImportPath,
 		Events:     events,
+		Decoder:    g.Decoder,
+		SDK:        g.SDK,
+
+		MaxStringLength: g.MaxStringLength,
+	}
+
+	// Load the templates before touching the output directory, so a broken template directory
+	// does not leave a partially generated indexer behind
+	templates, err := LoadTemplates(g.TemplateDir)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check if output directory exists
@@ -100,24 +156,51 @@ func (g *Generator) Generate() (*GeneratedFiles, error) {
 	// Generate all files
 	type fileGen struct {
 		path     *string
-		render   func(*TemplateData) (string, error)
+		template string
 		filename string
 		desc     string
 	}
 
 	files := &GeneratedFiles{}
 	fileGens := []fileGen{
-		{&files.ModelsFile, RenderModels, "models.go", "models"},
-		{&files.IndexerFile, RenderIndexer, "indexer.go", "indexer"},
-		{&files.RegisterFile, RenderRegister, "register.go", "register"},
-		{&files.APIFile, RenderAPI, "api.go", "API"},
-		{&files.MigrationsFile, RenderMigrations, "migrations/migrations.go", "migrations"},
-		{nil, RenderInitialSQL, "migrations/001_initial.sql", "initial SQL"},
-		{&files.ReadmeFile, RenderReadme, "README.md", "readme"},
+		{&files.ModelsFile, modelsTemplateFile, "models.go", "models"},
+		{&files.IndexerFile, indexerTemplateFile, "indexer.go", "indexer"},
+		{&files.RegisterFile, registerTemplateFile, "register.go", "register"},
+		{&files.APIFile, apiTemplateFile, "api.go", "API"},
+		{&files.MigrationsFile, migrationsTemplateFile, "migrations/migrations.go", "migrations"},
+		{nil, initialSQLTemplateFile, "migrations/001_initial.sql", "initial SQL"},
+		{&files.ReadmeFile, readmeTemplateFile, "README.md", "readme"},
+	}
+	sdkGens := []fileGen{
+		{&files.ClientFile, clientTemplateFile, g.Package + "_client.go", "client"},
+		{&files.MockClientFile, mockClientTemplateFile, g.Package + "_mock_client.go", "mock client"},
+	}
+
+	// Templates of the template directory that do not override a built-in one generate new files
+	builtins := make(map[string]bool, len(fileGens)+len(sdkGens))
+	for _, fg := range slices.Concat(fileGens, sdkGens) {
+		builtins[fg.template] = true
+	}
+
+	if g.SDK {
+		fileGens = append(fileGens, sdkGens...)
+	}
+
+	customTemplates := make([]string, 0, len(templates))
+	for name := range templates {
+		if !builtins[name] {
+			customTemplates = append(customTemplates, name)
+		}
+	}
+	slices.Sort(customTemplates)
+
+	files.CustomFiles = make([]string, len(customTemplates))
+	for i, name := range customTemplates {
+		fileGens = append(fileGens, fileGen{&files.CustomFiles[i], name, strings.TrimSuffix(name, templateExt), name})
 	}
 
 	for _, fg := range fileGens {
-		content, err := fg.render(data)
+		content, err := renderTemplate(fg.desc, templates[fg.template], data)
 		if err != nil {
 			return nil, fmt.Errorf("failed to render %s: %w", fg.desc, err)
 		}
@@ -145,6 +228,16 @@ func (g *Generator) validate() error {
 		return fmt.Errorf("at least one event signature is required")
 	}
 
+	switch g.Decoder {
+	case "", DecoderRaw, DecoderABI:
+	default:
+		return fmt.Errorf("unknown decoder %q, expected %q or %q", g.Decoder, DecoderRaw, DecoderABI)
+	}
+
+	if g.MaxStringLength < 0 {
+		return fmt.Errorf("max string length must not be negative: %d", g.MaxStringLength)
+	}
+
 	// Validate name format (should be PascalCase)
 	if !strings.Contains(g.Name, " ") && len(g.Name) > 0 {
 		firstChar := rune(g.Name[0])
@@ -179,6 +272,40 @@ func (g *Generator) parseEvents() ([]*EventSignature, error) {
 	return events, nil
 }
 
+// validateTuples checks that the tuple parameters of the events can be flattened into columns.
+// Tuples are decoded with the ABI decoder, and their fields must not collide with other columns.
+func validateTuples(events []*EventSignature, decoder string) error {
+	for _, event := range events {
+		for _, param := range event.Params {
+			if !param.IsTuple() {
+				continue
+			}
+
+			switch {
+			case decoder != DecoderABI:
+				return fmt.Errorf("event %s: tuple parameter %s requires the %q decoder", event.Name, param.Name, DecoderABI)
+			case param.Indexed:
+				return fmt.Errorf("event %s: indexed tuple parameter %s is not supported", event.Name, param.Name)
+			}
+		}
+
+		columns := make(map[string]bool)
+		for _, column := range event.Columns() {
+			if column.IsTuple() {
+				return fmt.Errorf("event %s: array of tuples %s is not supported", event.Name, column.Name)
+			}
+
+			name := DBFieldName(column.Name)
+			if columns[name] {
+				return fmt.Errorf("event %s: duplicate column %s of the flattened tuple parameters", event.Name, name)
+			}
+			columns[name] = true
+		}
+	}
+
+	return nil
+}
+
 // writeFile writes content to a file, respecting DryRun and Force flags.
 func (g *Generator) writeFile(path, content string) error {
 	if g.DryRun {
@@ -229,8 +356,10 @@ func (g *Generator) PrintSummary(files *GeneratedFiles) {
 	fmt.Println("\n✓ Successfully generated indexer!")
 	fmt.Printf("\nIndexer: %s\n", g.Name)
 	fmt.Printf("Package: %s\n", g.Package)
+	fmt.Printf("Type:    %s\n", g.Type)
 	fmt.Printf("Output:  %s\n", g.OutputDir)
 	fmt.Printf("Events:  %d\n", len(g.Events))
+	fmt.Printf("Decoder: %s\n", g.Decoder)
 
 	fmt.Println("\nGenerated files:")
 	fmt.Printf("  • %s\n", files.IndexerFile)
@@ -239,13 +368,20 @@ func (g *Generator) PrintSummary(files *GeneratedFiles) {
 	fmt.Printf("  • %s\n", files.APIFile)
 	fmt.Printf("  • %s\n", files.MigrationsFile)
 	fmt.Printf("  • %s\n", files.ReadmeFile)
+	if g.SDK {
+		fmt.Printf("  • %s\n", files.ClientFile)
+		fmt.Printf("  • %s\n", files.MockClientFile)
+	}
+	for _, file := range files.CustomFiles {
+		fmt.Printf("  • %s\n", file)
+	}
 
 	fmt.Println("\nNext steps:")
 	fmt.Println("  1. Review the generated code")
 	fmt.Println("  2. Add to your config.yaml:")
 	fmt.Printf("     indexers:\n")
 	fmt.Printf("       - name: \"%sIndexer\"\n", g.Name)
-	fmt.Printf("         type: \"%s\"  # Indexer type for registry\n", strings.ToLower(g.Name))
+	fmt.Printf("         type: \"%s\"  # Indexer type for registry\n", g.Type)
 	fmt.Printf("         start_block: 0\n")
 	fmt.Printf("         db:\n")
 	fmt.Printf("           path: \"./data/%s.sqlite\"\n", strings.ToLower(g.Name))
@@ -1,6 +1,7 @@
 package codegen
 
 import (
+	"go/format"
 	"os"
 	"path/filepath"
 	"testing"
@@ -37,6 +38,33 @@ func TestGenerator_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "abi decoder",
+			gen: &Generator{
+				Name:    "MyToken",
+				Events:  []string{"Transfer(address,address,uint256)"},
+				Decoder: DecoderABI,
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown decoder",
+			gen: &Generator{
+				Name:    "MyToken",
+				Events:  []string{"Transfer(address,address,uint256)"},
+				Decoder: "rlp",
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative max string length",
+			gen: &Generator{
+				Name:            "MyToken",
+				Events:          []string{"Transfer(address,address,uint256)"},
+				MaxStringLength: -1,
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid name - lowercase",
 			gen: &Generator{
@@ -158,6 +186,7 @@ func TestGenerator_Generate(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, string(migrationsContent), "//go:embed 001_initial.sql")
 	assert.Contains(t, string(migrationsContent), "func RunMigrations")
+	assert.Contains(t, string(migrationsContent), "func RollbackTo")
 
 	// Check the SQL file exists and has the expected content
 	sqlFile := filepath.Join(filepath.Dir(files.MigrationsFile), "001_initial.sql")
@@ -183,6 +212,270 @@ func TestGenerator_Generate(t *testing.T) {
 	assert.Contains(t, string(indexerContent), "github.com/goran-ethernal/ChainIndexor/pkg/config")
 }
 
+func TestGenerator_GenerateAddressColumns(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	gen := &Generator{
+		Name: "TestNFT",
+		Events: []string{
+			"Transfer(address indexed from, address indexed to, uint256 indexed tokenId)",
+			"ApprovalForAll(address indexed owner, address indexed operator, bool approved)",
+		},
+		OutputDir:  filepath.Join(tmpDir, "testnft"),
+		ImportPath: "github.com/test/indexers/testnft",
+		Force:      true,
+	}
+
+	files, err := gen.Generate()
+	require.NoError(t, err)
+
+	// The API address filter must use the column names of the generated tables
+	apiContent, err := os.ReadFile(files.APIFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(apiContent), `"from_address",`)
+	assert.Contains(t, string(apiContent), `"owner_address",`)
+	assert.Contains(t, string(apiContent), `"operator",`)
+	assert.NotContains(t, string(apiContent), "operator_address")
+
+	// Events are aggregated over their integer columns
+	assert.Contains(t, string(apiContent), "NumericColumns: []string{\n\t\t\t\t\"token_id\",\n\t\t\t},")
+
+	sqlContent, err := os.ReadFile(filepath.Join(filepath.Dir(files.MigrationsFile), "001_initial.sql"))
+	require.NoError(t, err)
+	assert.Contains(t, string(sqlContent), "operator TEXT NOT NULL")
+	assert.Contains(t, string(sqlContent), "token_id TEXT NOT NULL")
+
+	// The event tables are registered with the indexer type, aligned like gofmt
+	registerContent, err := os.ReadFile(files.RegisterFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(registerContent), `Description: "Indexes TestNFT events",`)
+	assert.Contains(t, string(registerContent), "\t\t\t\"Transfer\":       \"transfers\",\n")
+	assert.Contains(t, string(registerContent), "\t\t\t\"ApprovalForAll\": \"approval_for_alls\",\n")
+
+	formatted, err := format.Source(registerContent)
+	require.NoError(t, err)
+	assert.Equal(t, string(formatted), string(registerContent))
+}
+
+func TestGenerator_GenerateABIDecoder(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	gen := &Generator{
+		Name: "TestMultiToken",
+		Events: []string{
+			"TransferSingle(address indexed operator, address indexed from, address indexed to, uint256 id, uint256 value)",
+			"TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values)",
+		},
+		OutputDir:  filepath.Join(tmpDir, "testmultitoken"),
+		ImportPath: "github.com/test/indexers/testmultitoken",
+		Decoder:    DecoderABI,
+		Force:      true,
+	}
+
+	files, err := gen.Generate()
+	require.NoError(t, err)
+
+	// The events ABI is embedded and decodes the dynamic arrays of the log data
+	indexerContent, err := os.ReadFile(files.IndexerFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(indexerContent), `gethabi "github.com/ethereum/go-ethereum/accounts/abi"`)
+	assert.Contains(t, string(indexerContent), `const eventsABIJSON = `+"`"+`[{"type":"event","name":"TransferSingle"`)
+	assert.Contains(t, string(indexerContent), `idx.eventsABI.Events["TransferBatch"].Inputs.UnpackIntoMap(unpacked, log.Data)`)
+	assert.Contains(t, string(indexerContent), `indexer.ABIValue[[]string](unpacked, "ids")`)
+	assert.Contains(t, string(indexerContent), `indexer.ABIValue[string](unpacked, "value")`)
+	assert.NotContains(t, string(indexerContent), "expectedDataSize")
+	assert.NotContains(t, string(indexerContent), `"math/big"`)
+
+	modelsContent, err := os.ReadFile(files.ModelsFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(modelsContent), "Ids []string `meddler:\"ids,json\"")
+
+	sqlContent, err := os.ReadFile(filepath.Join(filepath.Dir(files.MigrationsFile), "001_initial.sql"))
+	require.NoError(t, err)
+	assert.Contains(t, string(sqlContent), "ids TEXT NOT NULL")
+}
+
+func TestGenerator_GenerateStrings(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	gen := &Generator{
+		Name: "TestGovernor",
+		Events: []string{
+			"VoteCast(address indexed voter, uint256 indexed proposalId, uint8 support, uint256 weight, string reason)",
+		},
+		OutputDir:       filepath.Join(tmpDir, "testgovernor"),
+		ImportPath:      "github.com/test/indexers/testgovernor",
+		Decoder:         DecoderABI,
+		MaxStringLength: 256,
+		Force:           true,
+	}
+
+	files, err := gen.Generate()
+	require.NoError(t, err)
+
+	// Strings are capped before they are written
+	indexerContent, err := os.ReadFile(files.IndexerFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(indexerContent), "const maxStringLength = 256")
+	assert.Contains(t, string(indexerContent), "indexer.TruncateString(reason, maxStringLength)")
+	assert.Contains(t, string(indexerContent), `indexer.ABIValue[uint8](unpacked, "support")`)
+
+	modelsContent, err := os.ReadFile(files.ModelsFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(modelsContent), "Support uint8 `meddler:\"support\"")
+	assert.Contains(t, string(modelsContent), "Reason string `meddler:\"reason\"")
+
+	sqlContent, err := os.ReadFile(filepath.Join(filepath.Dir(files.MigrationsFile), "001_initial.sql"))
+	require.NoError(t, err)
+	assert.Contains(t, string(sqlContent), "support INTEGER NOT NULL")
+	assert.Contains(t, string(sqlContent), "reason TEXT NOT NULL")
+
+	readmeContent, err := os.ReadFile(files.ReadmeFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(readmeContent), "  --decoder abi \\\n  --max-string-length 256 \\\n")
+
+	// Without strings, there is nothing to cap
+	gen = &Generator{
+		Name:       "TestMultiToken",
+		Events:     []string{"TransferBatch(address indexed operator, uint256[] ids, uint256[] values)"},
+		OutputDir:  filepath.Join(tmpDir, "testmultitoken"),
+		ImportPath: "github.com/test/indexers/testmultitoken",
+		Decoder:    DecoderABI,
+		Force:      true,
+	}
+
+	files, err = gen.Generate()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultMaxStringLength, gen.MaxStringLength)
+
+	indexerContent, err = os.ReadFile(files.IndexerFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(indexerContent), "maxStringLength")
+}
+
+func TestGenerator_GenerateTuples(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	gen := &Generator{
+		Name: "TestExchange",
+		Events: []string{
+			"OrderFilled(bytes32 indexed orderHash, (address maker, address taker, uint256 amount) order)",
+		},
+		OutputDir:  filepath.Join(tmpDir, "testexchange"),
+		ImportPath: "github.com/test/indexers/testexchange",
+		Decoder:    DecoderABI,
+		Force:      true,
+	}
+
+	files, err := gen.Generate()
+	require.NoError(t, err)
+
+	// The tuple is flattened into a column per field
+	sqlContent, err := os.ReadFile(filepath.Join(filepath.Dir(files.MigrationsFile), "001_initial.sql"))
+	require.NoError(t, err)
+	assert.Contains(t, string(sqlContent), "order_hash TEXT NOT NULL")
+	assert.Contains(t, string(sqlContent), "order_maker TEXT NOT NULL")
+	assert.Contains(t, string(sqlContent), "order_taker TEXT NOT NULL")
+	assert.Contains(t, string(sqlContent), "order_amount TEXT NOT NULL")
+	assert.Contains(t, string(sqlContent), "idx_order_filled_order_maker ON order_filled(order_maker)")
+	assert.NotContains(t, string(sqlContent), "order TEXT")
+
+	modelsContent, err := os.ReadFile(files.ModelsFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(modelsContent),
+		"OrderMaker common.Address `meddler:\"order_maker,address\" abi:\"order.maker,address\"`")
+	assert.Contains(t, string(modelsContent),
+		"OrderTaker common.Address `meddler:\"order_taker,address\" abi:\"order.taker,address\"`")
+	assert.Contains(t, string(modelsContent),
+		"OrderAmount string `meddler:\"order_amount\" abi:\"order.amount,uint256\"`")
+
+	// The tuple is declared with its components and unpacked field by field
+	indexerContent, err := os.ReadFile(files.IndexerFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(indexerContent), `{"name":"order","type":"tuple","indexed":false,"components":[`)
+	assert.Contains(t, string(indexerContent), `crypto.Keccak256Hash([]byte("OrderFilled(bytes32,(address,address,uint256))"))`)
+	assert.Contains(t, string(indexerContent), `indexer.ABIValue[common.Address](unpacked, "order", "maker")`)
+	assert.Contains(t, string(indexerContent), `indexer.ABIValue[string](unpacked, "order", "amount")`)
+	assert.Contains(t, string(indexerContent), "OrderAmount: orderAmount,")
+
+	t.Run("raw decoder", func(t *testing.T) {
+		gen.Decoder = DecoderRaw
+		_, err := gen.Generate()
+		require.ErrorContains(t, err, `tuple parameter order requires the "abi" decoder`)
+	})
+
+	t.Run("unsupported tuples", func(t *testing.T) {
+		for signature, expectedErr := range map[string]string{
+			"OrderFilled((address maker, uint256 amount) indexed order)":             "indexed tuple parameter order",
+			"OrderFilled((address maker, uint256 amount)[] orders)":                  "array of tuples orders",
+			"OrderFilled((address maker, (uint256 value)[] fills) order)":            "array of tuples order_fills",
+			"OrderFilled((address maker, uint256 amount) order, address orderMaker)": "duplicate column order_maker",
+		} {
+			gen.Events = []string{signature}
+			gen.Decoder = DecoderABI
+			_, err := gen.Generate()
+			require.ErrorContains(t, err, expectedErr, signature)
+		}
+	})
+}
+
+func TestGenerator_GenerateSDK(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// The ERC20 example is generated with the SDK, and tests its clients
+	gen := &Generator{
+		Name:    "ERC20",
+		Package: "erc20",
+		Events: []string{
+			"Transfer(address indexed from, address indexed to, uint256 value)",
+			"Approval(address indexed owner, address indexed spender, uint256 value)",
+		},
+		OutputDir:  filepath.Join(tmpDir, "erc20"),
+		ImportPath: "github.com/goran-ethernal/ChainIndexor/examples/indexers/erc20",
+		Force:      true,
+		SDK:        true,
+	}
+
+	files, err := gen.Generate()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(gen.OutputDir, "erc20_client.go"), files.ClientFile)
+	require.Equal(t, filepath.Join(gen.OutputDir, "erc20_mock_client.go"), files.MockClientFile)
+	assert.Empty(t, files.CustomFiles)
+
+	exampleDir := filepath.Join("..", "..", "examples", "indexers", "erc20")
+	for _, file := range []string{files.ClientFile, files.MockClientFile, files.ReadmeFile} {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		example, err := os.ReadFile(filepath.Join(exampleDir, filepath.Base(file)))
+		require.NoError(t, err)
+		assert.Equal(t, string(example), string(content), "%s is out of date, regenerate the ERC20 example", file)
+	}
+
+	clientContent, err := os.ReadFile(files.ClientFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(clientContent),
+		"func (c *Client) QueryTransfers(ctx context.Context, params QueryParams) ([]Transfer, int, error)")
+	assert.Contains(t, string(clientContent), "QueryApprovals(ctx context.Context, params QueryParams) ([]Approval, int, error)")
+
+	mockContent, err := os.ReadFile(files.MockClientFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(mockContent), "func (m *MockClient) AddTransfers(events ...Transfer)")
+	assert.Contains(t, string(mockContent), "event.Owner,\n\t\t\t\tevent.Spender,")
+
+	// Without the SDK, no client is generated
+	gen.OutputDir = filepath.Join(tmpDir, "erc20-without-sdk")
+	gen.SDK = false
+
+	files, err = gen.Generate()
+	require.NoError(t, err)
+	assert.Empty(t, files.ClientFile)
+	assert.Empty(t, files.MockClientFile)
+	assert.Empty(t, files.CustomFiles)
+	assert.NoFileExists(t, filepath.Join(gen.OutputDir, "erc20_client.go"))
+	assert.NoFileExists(t, filepath.Join(gen.OutputDir, "erc20_mock_client.go"))
+}
+
 func TestGenerator_GenerateDryRun(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -204,6 +497,100 @@ func TestGenerator_GenerateDryRun(t *testing.T) {
 	assert.NoFileExists(t, files.ReadmeFile)
 }
 
+func TestGenerator_GenerateTemplateDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	templateDir := filepath.Join(tmpDir, "templates")
+	require.NoError(t, os.MkdirAll(templateDir, 0755))
+
+	// A new template generates its own file, an override replaces the built-in README
+	hook := `package {{.Package}}
+{{range .Events}}
+// {{.Name}}Table = {{camelToSnake .Name}}
+{{- range .Params}}{{if isIndexed .}}
+// {{.Name}} {{solidityTypeToGo .Type}}
+{{- end}}{{end}}
+{{end}}`
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "hook.go.tmpl"), []byte(hook), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "README.md.tmpl"), []byte("# Custom {{.Name}}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "notes.txt"), []byte("ignored"), 0644))
+
+	gen := &Generator{
+		Name:        "TestToken",
+		Events:      []string{"TokenMinted(address indexed to, uint256 amount)"},
+		OutputDir:   filepath.Join(tmpDir, "testtoken"),
+		ImportPath:  "github.com/test/indexers/testtoken",
+		Force:       true,
+		TemplateDir: templateDir,
+	}
+
+	files, err := gen.Generate()
+	require.NoError(t, err)
+
+	hookFile := filepath.Join(gen.OutputDir, "hook.go")
+	require.Equal(t, []string{hookFile}, files.CustomFiles)
+
+	hookContent, err := os.ReadFile(hookFile)
+	require.NoError(t, err)
+	assert.Equal(t, "package testtoken\n\n// TokenMintedTable = token_minted\n// to common.Address\n", string(hookContent))
+
+	readmeContent, err := os.ReadFile(files.ReadmeFile)
+	require.NoError(t, err)
+	assert.Equal(t, "# Custom TestToken\n", string(readmeContent))
+
+	// Templates that are not overridden are still generated from the built-ins
+	modelsContent, err := os.ReadFile(files.ModelsFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(modelsContent), "type TokenMinted struct")
+
+	assert.NoFileExists(t, filepath.Join(gen.OutputDir, "notes.txt"))
+}
+
+func TestGenerator_GenerateKafkaHookExample(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	gen := &Generator{
+		Name:        "TestToken",
+		Events:      []string{"Transfer(address indexed from, address indexed to, uint256 value)"},
+		OutputDir:   filepath.Join(tmpDir, "testtoken"),
+		ImportPath:  "github.com/test/indexers/testtoken",
+		Force:       true,
+		TemplateDir: filepath.Join("..", "..", "examples", "templates", "kafka-hook"),
+	}
+
+	files, err := gen.Generate()
+	require.NoError(t, err)
+	require.Len(t, files.CustomFiles, 1)
+
+	hookContent, err := os.ReadFile(files.CustomFiles[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(hookContent), "type TestTokenKafkaHook struct")
+	assert.Contains(t, string(hookContent), "func (h *TestTokenKafkaHook) PublishTransfer(")
+	assert.Contains(t, string(hookContent), `Topic: "testtoken.transfers"`)
+}
+
+func TestGenerator_GenerateTemplateDirErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	gen := &Generator{
+		Name:        "TestToken",
+		Events:      []string{"Transfer(address,address,uint256)"},
+		OutputDir:   filepath.Join(tmpDir, "testtoken"),
+		TemplateDir: filepath.Join(tmpDir, "missing"),
+	}
+
+	_, err := gen.Generate()
+	require.ErrorContains(t, err, "failed to read template directory")
+	assert.NoDirExists(t, gen.OutputDir)
+
+	templateDir := filepath.Join(tmpDir, "templates")
+	require.NoError(t, os.MkdirAll(templateDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "broken.go.tmpl"), []byte("{{.Name"), 0644))
+
+	gen.TemplateDir = templateDir
+	_, err = gen.Generate()
+	require.ErrorContains(t, err, "failed to render broken.go.tmpl")
+}
+
 func TestGenerator_GenerateWithoutForce(t *testing.T) {
 	tmpDir := t.TempDir()
 	outputDir := filepath.Join(tmpDir, "testtoken")
@@ -3,14 +3,33 @@ package codegen
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+	"unicode"
 )
 
 // EventParam represents a parameter in an event signature.
 type EventParam struct {
-	Name    string // Parameter name (e.g., "from", "to", "value")
-	Type    string // Solidity type (e.g., "address", "uint256")
-	Indexed bool   // Whether the parameter is indexed
+	Name       string       // Parameter name (e.g., "from", "to", "value")
+	Type       string       // Solidity type (e.g., "address", "uint256", "(address,uint256)")
+	Indexed    bool         // Whether the parameter is indexed
+	Components []EventParam // Fields of tuple types
+	Path       []string     // Names of the enclosing tuple parameters and the field, set on flattened columns
+}
+
+// IsTuple reports whether the parameter is a tuple or an array of tuples.
+func (p EventParam) IsTuple() bool {
+	return len(p.Components) > 0
+}
+
+// ABIPath returns the names leading to the value of the parameter in the decoded event data:
+// the parameter name, or for flattened tuple fields the tuple name followed by the field names.
+func (p EventParam) ABIPath() []string {
+	if len(p.Path) > 0 {
+		return p.Path
+	}
+
+	return []string{p.Name}
 }
 
 // EventSignature represents a parsed event signature.
@@ -25,6 +44,7 @@ type EventSignature struct {
 //   - "Transfer(address,address,uint256)"
 //   - "Transfer(address indexed from, address indexed to, uint256 value)"
 //   - "Transfer(address from, address to, uint256 value)"
+//   - "OrderFilled(bytes32 indexed orderHash, (address maker, address taker, uint256 amount) order)"
 func ParseEventSignature(sig string) (*EventSignature, error) {
 	sig = strings.TrimSpace(sig)
 
@@ -78,135 +98,202 @@ func ParseEventSignature(sig string) (*EventSignature, error) {
 
 // parseParameters parses the parameter list from an event signature.
 func parseParameters(paramsStr string) ([]EventParam, error) {
-	paramsStr = strings.TrimSpace(paramsStr)
+	parser := &paramParser{input: paramsStr}
 
-	// Empty parameter list
-	if paramsStr == "" {
-		return []EventParam{}, nil
+	params, err := parser.parseList(false)
+	if err != nil {
+		return nil, err
+	}
+
+	parser.skipSpaces()
+	if !parser.done() {
+		return nil, fmt.Errorf("unexpected '%s'", parser.input[parser.pos:])
 	}
 
-	// Split by comma, but be careful with nested types like tuples
-	paramStrings := splitParameters(paramsStr)
+	return params, nil
+}
+
+// paramParser is a recursive-descent parser of event parameter lists, including tuple types:
+//
+//	list  = [ param { "," param } ]
+//	param = type [ "indexed" ] [ name ]
+//	type  = ( elementary | [ "tuple" ] "(" list ")" ) { "[" [ length ] "]" }
+//
+// Components of tuples are parsed as parameters that cannot be indexed.
+type paramParser struct {
+	input string
+	pos   int
+}
 
-	params := make([]EventParam, 0, len(paramStrings))
+// parseList parses a comma-separated parameter list, up to a closing parenthesis or the end of the input.
+func (p *paramParser) parseList(tuple bool) ([]EventParam, error) {
+	var params []EventParam
 	paramNames := make(map[string]bool) // Track duplicate names
 
-	for i, paramStr := range paramStrings {
-		param, err := parseParameter(strings.TrimSpace(paramStr), i)
+	p.skipSpaces()
+	if p.done() || p.peek() == ')' {
+		return []EventParam{}, nil
+	}
+
+	for index := 0; ; index++ {
+		start := p.pos
+
+		param, err := p.parseParam(index, tuple)
 		if err != nil {
-			return nil, fmt.Errorf("invalid parameter '%s': %w", paramStr, err)
+			return nil, fmt.Errorf("invalid parameter '%s': %w", strings.TrimSpace(p.input[start:p.pos]), err)
 		}
 
-		// Check for duplicate parameter names (only if named)
-		if param.Name != "" {
-			if paramNames[param.Name] {
-				return nil, fmt.Errorf("duplicate parameter name: %s", param.Name)
-			}
-			paramNames[param.Name] = true
+		// Check for duplicate parameter names
+		if paramNames[param.Name] {
+			return nil, fmt.Errorf("duplicate parameter name: %s", param.Name)
 		}
+		paramNames[param.Name] = true
 
 		params = append(params, param)
-	}
 
-	return params, nil
+		p.skipSpaces()
+		if p.done() || p.peek() != ',' {
+			return params, nil
+		}
+		p.pos++
+	}
 }
 
-// splitParameters splits parameter string by commas, handling nested structures.
-func splitParameters(paramsStr string) []string {
-	var params []string
-	var current strings.Builder
-	depth := 0
-
-	for _, ch := range paramsStr {
-		switch ch {
-		case '(':
-			depth++
-			current.WriteRune(ch)
-		case ')':
-			depth--
-			current.WriteRune(ch)
-		case ',':
-			if depth == 0 {
-				params = append(params, current.String())
-				current.Reset()
-			} else {
-				current.WriteRune(ch)
-			}
-		default:
-			current.WriteRune(ch)
+// parseParam parses a single parameter.
+// Formats:
+//   - "address" (type only)
+//   - "address from" (type + name)
+//   - "address indexed from" (type + indexed + name)
+//   - "(address maker, uint256 amount) order" (tuple type + name)
+func (p *paramParser) parseParam(index int, tuple bool) (EventParam, error) {
+	param, err := p.parseType()
+	if err != nil {
+		return EventParam{}, err
+	}
+
+	word := p.parseWord()
+	if word == "indexed" {
+		if tuple {
+			return EventParam{}, fmt.Errorf("tuple components cannot be indexed")
 		}
+		param.Indexed = true
+		word = p.parseWord()
 	}
 
-	// Add the last parameter
-	if current.Len() > 0 {
-		params = append(params, current.String())
+	param.Name = word
+	if param.Name == "" {
+		param.Name = fmt.Sprintf("param%d", index)
+	}
+
+	// Validate parameter name
+	if !regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`).MatchString(param.Name) {
+		return EventParam{}, fmt.Errorf("invalid parameter name: %s", param.Name)
 	}
 
-	return params
+	p.skipSpaces()
+	if !p.done() && p.peek() != ',' && p.peek() != ')' {
+		return EventParam{}, fmt.Errorf("too many parts in parameter definition")
+	}
+
+	return param, nil
 }
 
-// parseParameter parses a single parameter string.
-// Formats:
-//   - "address" (type only)
-//   - "address from" (type + name)
-//   - "address indexed from" (type + indexed + name)
-func parseParameter(paramStr string, index int) (EventParam, error) {
-	if paramStr == "" {
+// parseType parses an elementary or tuple type with its array suffixes. The type of a tuple is
+// its canonical form, e.g. "(address,uint256)", and its fields are returned as components.
+func (p *paramParser) parseType() (EventParam, error) {
+	var param EventParam
+
+	p.skipSpaces()
+	if p.done() {
 		return EventParam{}, fmt.Errorf("empty parameter")
 	}
 
-	parts := strings.Fields(paramStr)
-	if len(parts) == 0 {
-		return EventParam{}, fmt.Errorf("empty parameter")
+	if strings.HasPrefix(p.input[p.pos:], "tuple(") {
+		p.pos += len("tuple")
 	}
 
-	param := EventParam{}
+	if p.peek() == '(' {
+		p.pos++
 
-	// First part is always the type
-	param.Type = parts[0]
+		components, err := p.parseList(true)
+		if err != nil {
+			return EventParam{}, err
+		}
+		if len(components) == 0 {
+			return EventParam{}, fmt.Errorf("empty tuple type")
+		}
 
-	// Validate Solidity type
-	if !isValidSolidityType(param.Type) {
-		return EventParam{}, fmt.Errorf("invalid Solidity type: %s", param.Type)
+		p.skipSpaces()
+		if p.done() || p.peek() != ')' {
+			return EventParam{}, fmt.Errorf("missing closing parenthesis of tuple type")
+		}
+		p.pos++
+
+		types := make([]string, len(components))
+		for i, component := range components {
+			types[i] = component.Type
+		}
+
+		param.Type = "(" + strings.Join(types, ",") + ")"
+		param.Components = components
+	} else {
+		param.Type = p.parseWord()
 	}
 
-	// Parse remaining parts
-	switch len(parts) {
-	case 1:
-		// Type only: "address"
-		param.Name = fmt.Sprintf("param%d", index)
-		param.Indexed = false
-
-	case 2: //nolint:mnd
-		// Type + name OR type + indexed (without name)
-		if parts[1] == "indexed" {
-			param.Indexed = true
-			param.Name = fmt.Sprintf("param%d", index)
-		} else {
-			param.Name = parts[1]
-			param.Indexed = false
+	// Array suffixes, e.g. "[]" or "[3]"
+	for !p.done() && p.peek() == '[' {
+		end := strings.IndexByte(p.input[p.pos:], ']')
+		if end == -1 {
+			return EventParam{}, fmt.Errorf("missing closing bracket in type %s", param.Type)
 		}
 
-	case 3: //nolint:mnd
-		// Type + indexed + name
-		if parts[1] != "indexed" {
-			return EventParam{}, fmt.Errorf("expected 'indexed' keyword, got '%s'", parts[1])
+		suffix := p.input[p.pos : p.pos+end+1]
+		if !regexp.MustCompile(`^\[\d*\]$`).MatchString(suffix) {
+			return EventParam{}, fmt.Errorf("invalid array suffix %s in type %s", suffix, param.Type)
 		}
-		param.Indexed = true
-		param.Name = parts[2]
 
-	default:
-		return EventParam{}, fmt.Errorf("too many parts in parameter definition")
+		param.Type += suffix
+		p.pos += end + 1
 	}
 
-	// Validate parameter name
-	if param.Name != "" && !regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`).MatchString(param.Name) {
-		return EventParam{}, fmt.Errorf("invalid parameter name: %s", param.Name)
+	// Validate Solidity type
+	if !param.IsTuple() && !isValidSolidityType(param.Type) {
+		return EventParam{}, fmt.Errorf("invalid Solidity type: %s", param.Type)
 	}
 
 	return param, nil
 }
 
+// parseWord skips leading spaces and returns the following identifier, or an empty string.
+func (p *paramParser) parseWord() string {
+	p.skipSpaces()
+
+	start := p.pos
+	for !p.done() {
+		ch := p.peek()
+		if ch != '_' && !unicode.IsLetter(rune(ch)) && !unicode.IsDigit(rune(ch)) {
+			break
+		}
+		p.pos++
+	}
+
+	return p.input[start:p.pos]
+}
+
+func (p *paramParser) skipSpaces() {
+	for !p.done() && unicode.IsSpace(rune(p.peek())) {
+		p.pos++
+	}
+}
+
+func (p *paramParser) peek() byte {
+	return p.input[p.pos]
+}
+
+func (p *paramParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
 // isValidSolidityType checks if a string is a valid Solidity type.
 func isValidSolidityType(typ string) bool {
 	// Basic types
@@ -287,3 +374,47 @@ func (e *EventSignature) NonIndexedParams() []EventParam {
 	}
 	return nonIndexed
 }
+
+// Columns returns the parameters stored in the columns of the event table. Tuple parameters are
+// flattened into a column per field, named by the tuple and field names joined with '_'.
+// Example: "(address maker, uint256 amount) order" -> "order_maker", "order_amount"
+func (e *EventSignature) Columns() []EventParam {
+	var columns []EventParam
+	for _, param := range e.Params {
+		columns = appendColumns(columns, param, nil)
+	}
+	return columns
+}
+
+// NonIndexedColumns returns only the columns of the non-indexed parameters.
+func (e *EventSignature) NonIndexedColumns() []EventParam {
+	var nonIndexed []EventParam
+	for _, column := range e.Columns() {
+		if !column.Indexed {
+			nonIndexed = append(nonIndexed, column)
+		}
+	}
+	return nonIndexed
+}
+
+// appendColumns appends the column of a parameter, or the columns of the fields of a tuple parameter.
+func appendColumns(columns []EventParam, param EventParam, parent *EventParam) []EventParam {
+	if parent != nil {
+		param.Path = append(slices.Clone(parent.Path), param.Name)
+		param.Name = parent.Name + "_" + param.Name
+		param.Indexed = parent.Indexed
+	}
+
+	if !param.IsTuple() || strings.HasSuffix(param.Type, "]") {
+		return append(columns, param)
+	}
+
+	if parent == nil {
+		param.Path = []string{param.Name}
+	}
+	for _, component := range param.Components {
+		columns = appendColumns(columns, component, &param)
+	}
+
+	return columns
+}
@@ -230,6 +230,76 @@ func TestParseEventSignature(t *testing.T) {
 	}
 }
 
+func TestParseEventSignature_Tuples(t *testing.T) {
+	t.Run("named tuple", func(t *testing.T) {
+		got, err := ParseEventSignature(
+			"OrderFilled(bytes32 indexed orderHash, (address maker, address taker, uint256 amount) order)")
+		require.NoError(t, err)
+		require.Len(t, got.Params, 2)
+
+		order := got.Params[1]
+		assert.Equal(t, "order", order.Name)
+		assert.Equal(t, "(address,address,uint256)", order.Type)
+		assert.False(t, order.Indexed)
+		assert.Equal(t, []EventParam{
+			{Name: "maker", Type: "address"},
+			{Name: "taker", Type: "address"},
+			{Name: "amount", Type: "uint256"},
+		}, order.Components)
+		assert.Equal(t, "OrderFilled(bytes32,(address,address,uint256))", got.CanonicalSignature())
+	})
+
+	t.Run("nested tuples, arrays and tuple keyword", func(t *testing.T) {
+		got, err := ParseEventSignature("Settled(tuple(address, (uint256 value, uint16 fee)[] fills)[2], bool)")
+		require.NoError(t, err)
+		require.Len(t, got.Params, 2)
+
+		settlement := got.Params[0]
+		assert.Equal(t, "param0", settlement.Name)
+		assert.Equal(t, "(address,(uint256,uint16)[])[2]", settlement.Type)
+		require.Len(t, settlement.Components, 2)
+		assert.Equal(t, "param0", settlement.Components[0].Name)
+		assert.Equal(t, "fills", settlement.Components[1].Name)
+		assert.Len(t, settlement.Components[1].Components, 2)
+		assert.Equal(t, "Settled((address,(uint256,uint16)[])[2],bool)", got.CanonicalSignature())
+	})
+
+	invalid := map[string]string{
+		"Unclosed tuple":            "OrderFilled((address maker, uint256 amount order)",
+		"Empty tuple":               "OrderFilled(() order)",
+		"Indexed tuple component":   "OrderFilled((address indexed maker) order)",
+		"Invalid component type":    "OrderFilled((addr maker) order)",
+		"Duplicate component names": "OrderFilled((address maker, address maker) order)",
+		"Invalid array suffix":      "OrderFilled((address maker)[x] orders)",
+		"Too many parts":            "OrderFilled((address maker) order extra)",
+	}
+	for name, signature := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseEventSignature(signature)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEventSignature_Columns(t *testing.T) {
+	sig, err := ParseEventSignature(
+		"OrderFilled(bytes32 indexed orderHash, (address maker, (address token, uint256 amount) asset) order, uint256 fee)")
+	require.NoError(t, err)
+
+	assert.Equal(t, []EventParam{
+		{Name: "orderHash", Type: "bytes32", Indexed: true},
+		{Name: "order_maker", Type: "address", Path: []string{"order", "maker"}},
+		{Name: "order_asset_token", Type: "address", Path: []string{"order", "asset", "token"}},
+		{Name: "order_asset_amount", Type: "uint256", Path: []string{"order", "asset", "amount"}},
+		{Name: "fee", Type: "uint256"},
+	}, sig.Columns())
+
+	nonIndexed := sig.NonIndexedColumns()
+	require.Len(t, nonIndexed, 4)
+	assert.Equal(t, []string{"order", "asset", "amount"}, nonIndexed[2].ABIPath())
+	assert.Equal(t, []string{"fee"}, nonIndexed[3].ABIPath())
+}
+
 func TestEventSignature_CanonicalSignature(t *testing.T) {
 	tests := []struct {
 		name      string
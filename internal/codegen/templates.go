@@ -2,102 +2,206 @@ package codegen
 
 import (
 	"bytes"
-	_ "embed"
+	"embed"
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
 )
 
-//go:embed templates/models.go.tmpl
-var modelsTemplate string
+// templateExt is the extension of template files, stripped from the name of the generated file.
+const templateExt = ".tmpl"
 
-//go:embed templates/indexer.go.tmpl
-var indexerTemplate string
-
-//go:embed templates/register.go.tmpl
-var registerTemplate string
-
-//go:embed templates/api.go.tmpl
-var apiTemplate string
-
-//go:embed templates/migrations.go.tmpl
-var migrationsTemplate string
-
-//go:embed templates/001_initial.sql.tmpl
-var initialSQLTemplate string
+// Template files of the built-in templates.
+const (
+	modelsTemplateFile     = "models.go.tmpl"
+	indexerTemplateFile    = "indexer.go.tmpl"
+	registerTemplateFile   = "register.go.tmpl"
+	apiTemplateFile        = "api.go.tmpl"
+	migrationsTemplateFile = "migrations.go.tmpl"
+	initialSQLTemplateFile = "001_initial.sql.tmpl"
+	readmeTemplateFile     = "README.md.tmpl"
+	clientTemplateFile     = "client.go.tmpl"
+	mockClientTemplateFile = "mock_client.go.tmpl"
+)
 
-//go:embed templates/README.md.tmpl
-var readmeTemplate string
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
 
 // TemplateData represents the data passed to templates.
 type TemplateData struct {
 	Name       string            // Indexer name (PascalCase, e.g., "ERC20Token")
 	Package    string            // Go package name (lowercase, e.g., "erc20token")
+	Type       string            // Indexer type the indexer is registered as (e.g., "erc20token")
 	ImportPath string            // Full import path for the package
 	Events     []*EventSignature // Events to generate code for
+	Decoder    string            // Decoder of non-indexed parameters, DecoderRaw or DecoderABI
+	SDK        bool              // Whether the Go client SDK is generated
+
+	MaxStringLength int // Maximum length in bytes of the stored string parameters
+}
+
+// NeedsBigInt reports whether the generated indexer parses integers with math/big, which it does
+// for indexed integers and, with the raw decoder, for non-indexed integers and booleans.
+func (d *TemplateData) NeedsBigInt() bool {
+	for _, event := range d.Events {
+		for _, param := range event.Params {
+			isInteger := strings.HasPrefix(param.Type, "uint") || strings.HasPrefix(param.Type, "int")
+			if param.Indexed && isInteger {
+				return true
+			}
+			if !param.Indexed && d.Decoder != DecoderABI && (isInteger || param.Type == boolType) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// HasStringColumns reports whether the events have string columns, whose length is capped to
+// MaxStringLength when they are written.
+func (d *TemplateData) HasStringColumns() bool {
+	for _, event := range d.Events {
+		for _, column := range event.NonIndexedColumns() {
+			if column.Type == stringType {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// TablePrefix returns the prefix of the tables of the indexer.
+func (d *TemplateData) TablePrefix() string {
+	return strings.ToLower(d.Name)
+}
+
+// EventTableEntries returns the "Event": "table" entries of the event tables registered with
+// the indexer, with the tables aligned like gofmt does.
+func (d *TemplateData) EventTableEntries() []string {
+	width := 0
+	for _, event := range d.Events {
+		width = max(width, len(strconv.Quote(event.Name)))
+	}
+
+	entries := make([]string, 0, len(d.Events))
+	for _, event := range d.Events {
+		key := strconv.Quote(event.Name) + ":"
+		entries = append(entries, fmt.Sprintf("%-*s %q", width+1, key, TableName(event.Name)))
+	}
+
+	return entries
 }
 
 // RenderModels generates the models.go file content.
 func RenderModels(data *TemplateData) (string, error) {
-	return renderTemplate("models", modelsTemplate, data)
+	return renderBuiltinTemplate("models", modelsTemplateFile, data)
 }
 
 // RenderIndexer generates the indexer.go file content.
 func RenderIndexer(data *TemplateData) (string, error) {
-	return renderTemplate("indexer", indexerTemplate, data)
+	return renderBuiltinTemplate("indexer", indexerTemplateFile, data)
 }
 
 // RenderRegister generates the register.go file content.
 func RenderRegister(data *TemplateData) (string, error) {
-	return renderTemplate("register", registerTemplate, data)
+	return renderBuiltinTemplate("register", registerTemplateFile, data)
 }
 
 // RenderAPI generates the api.go file content.
 func RenderAPI(data *TemplateData) (string, error) {
-	// Add table prefix to the data
-	tablePrefix := strings.ToLower(data.Name)
-
-	// Create a custom data struct that includes table prefix
-	apiData := struct {
-		*TemplateData
-		TablePrefix string
-	}{
-		TemplateData: data,
-		TablePrefix:  tablePrefix,
-	}
-
-	// Parse and execute template directly with custom data
-	tmpl, err := template.New("api").Funcs(templateFuncs()).Parse(apiTemplate)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, apiData); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
-	}
-
-	return buf.String(), nil
+	return renderBuiltinTemplate("api", apiTemplateFile, data)
 }
 
 // RenderMigrations generates the migrations/migrations.go file content.
 func RenderMigrations(data *TemplateData) (string, error) {
-	return renderTemplate("migrations", migrationsTemplate, data)
+	return renderBuiltinTemplate("migrations", migrationsTemplateFile, data)
 }
 
 // RenderInitialSQL generates the migrations/001_initial.sql file content.
 func RenderInitialSQL(data *TemplateData) (string, error) {
-	return renderTemplate("initial_sql", initialSQLTemplate, data)
+	return renderBuiltinTemplate("initial_sql", initialSQLTemplateFile, data)
 }
 
 // RenderReadme generates the README.md file content.
 func RenderReadme(data *TemplateData) (string, error) {
-	return renderTemplate("readme", readmeTemplate, data)
+	return renderBuiltinTemplate("readme", readmeTemplateFile, data)
+}
+
+// RenderClient generates the <package>_client.go file content.
+func RenderClient(data *TemplateData) (string, error) {
+	return renderBuiltinTemplate("client", clientTemplateFile, data)
+}
+
+// RenderMockClient generates the <package>_mock_client.go file content.
+func RenderMockClient(data *TemplateData) (string, error) {
+	return renderBuiltinTemplate("mock_client", mockClientTemplateFile, data)
+}
+
+// LoadTemplates returns the built-in templates keyed by file name, with the *.tmpl files
+// of dir added to them. Files of dir named like a built-in template override it.
+// An empty dir returns only the built-in templates.
+func LoadTemplates(dir string) (map[string]string, error) {
+	entries, err := builtinTemplates.ReadDir("templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read built-in templates: %w", err)
+	}
+
+	templates := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		content, err := builtinTemplates.ReadFile(path.Join("templates", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read built-in template %s: %w", entry.Name(), err)
+		}
+		templates[entry.Name()] = string(content)
+	}
+
+	if dir == "" {
+		return templates, nil
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("template directory %s is not a directory", dir)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+templateExt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates of %s: %w", dir, err)
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", file, err)
+		}
+		templates[filepath.Base(file)] = string(content)
+	}
+
+	return templates, nil
+}
+
+// renderBuiltinTemplate renders the built-in template of the given file with the given data.
+func renderBuiltinTemplate(name, file string, data *TemplateData) (string, error) {
+	content, err := builtinTemplates.ReadFile(path.Join("templates", file))
+	if err != nil {
+		return "", fmt.Errorf("failed to read built-in template %s: %w", file, err)
+	}
+
+	return renderTemplate(name, string(content), data)
 }
 
 // renderTemplate renders a template with the given data.
 func renderTemplate(name, tmplStr string, data *TemplateData) (string, error) {
-	tmpl, err := template.New(name).Funcs(templateFuncs()).Parse(tmplStr)
+	tmpl, err := template.New(name).Funcs(TemplateFuncs()).Parse(tmplStr)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
@@ -110,14 +214,18 @@ func renderTemplate(name, tmplStr string, data *TemplateData) (string, error) {
 	return buf.String(), nil
 }
 
-// templateFuncs returns the functions available in templates.
-func templateFuncs() template.FuncMap {
+// TemplateFuncs returns the functions available in the built-in and custom templates.
+func TemplateFuncs() template.FuncMap {
 	return template.FuncMap{
 		// Type conversion functions
 		"GoTypeName":  GoTypeName,
 		"DBTypeName":  DBTypeName,
 		"DBFieldName": DBFieldName,
 		"MeddlerTag":  MeddlerTag,
+		"ABITag":      ABITag,
+
+		// ABI of the events, for the abi decoder
+		"EventsABIJSON": EventsABIJSON,
 
 		// Case conversion functions
 		"ToPascalCase":     ToPascalCase,
@@ -125,6 +233,11 @@ func templateFuncs() template.FuncMap {
 		"ToLowerCamelCase": ToLowerCamelCase,
 		"ToLower":          strings.ToLower,
 
+		// Aliases for custom templates
+		"camelToSnake":     ToSnakeCase,
+		"solidityTypeToGo": GoTypeName,
+		"isIndexed":        func(param EventParam) bool { return param.Indexed },
+
 		// String manipulation
 		"Pluralize": Pluralize,
 		"TableName": TableName,
@@ -14,6 +14,7 @@ const (
 	boolType    = "bool"
 	stringType  = "string"
 	bytesType   = "bytes"
+	uint8Type   = "uint8"
 	textType    = "TEXT"
 
 	int64Size = 64
@@ -49,6 +50,9 @@ func GoTypeName(solidityType string) string {
 			return "common.Hash"
 		}
 		return "[]byte"
+	case solidityType == uint8Type:
+		// Small enums, like the support of a governance vote, keep their type
+		return uint8Type
 	case strings.HasPrefix(solidityType, "uint"):
 		if isIntSizeLargerThan64(solidityType, "uint") {
 			return stringType
@@ -67,6 +71,8 @@ func GoTypeName(solidityType string) string {
 // DBTypeName converts a Solidity type to a database column type.
 func DBTypeName(solidityType string) string {
 	switch {
+	case strings.HasSuffix(solidityType, "]"):
+		return textType // Arrays stored as JSON
 	case solidityType == addressType:
 		return textType
 	case solidityType == boolType:
@@ -88,8 +94,6 @@ func DBTypeName(solidityType string) string {
 			return textType
 		}
 		return "INTEGER"
-	case strings.HasSuffix(solidityType, "[]") || regexp.MustCompile(`\[\d+\]$`).MatchString(solidityType):
-		return textType // Arrays stored as JSON
 	default:
 		return textType
 	}
@@ -101,16 +105,32 @@ func MeddlerTag(param EventParam) string {
 	goType := GoTypeName(param.Type)
 
 	// Special tags for common types
-	switch goType {
-	case "common.Address":
+	switch {
+	case goType == "common.Address":
 		return fmt.Sprintf(`meddler:"%s,address"`, fieldName)
-	case "common.Hash":
+	case goType == "common.Hash":
 		return fmt.Sprintf(`meddler:"%s,hash"`, fieldName)
+	case strings.HasPrefix(goType, "[]") && goType != "[]byte":
+		// Arrays are stored as JSON
+		return fmt.Sprintf(`meddler:"%s,json"`, fieldName)
 	default:
 		return fmt.Sprintf(`meddler:"%s"`, fieldName)
 	}
 }
 
+// ABITag returns the abi struct tag for a field, recording the original parameter
+// name, its Solidity type and whether it is indexed. Fields of tuple parameters are
+// named by their path, e.g. abi:"order.maker,address".
+// Example: {Name: "from", Type: "address", Indexed: true} -> abi:"from,address,indexed"
+func ABITag(param EventParam) string {
+	name := strings.Join(param.ABIPath(), ".")
+	if param.Indexed {
+		return fmt.Sprintf(`abi:"%s,%s,indexed"`, name, param.Type)
+	}
+
+	return fmt.Sprintf(`abi:"%s,%s"`, name, param.Type)
+}
+
 // DBFieldName converts a parameter name to a database field name.
 // Examples: "from" -> "from_address", "to" -> "to_address", "value" -> "value"
 func DBFieldName(paramName string) string {
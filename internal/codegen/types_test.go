@@ -18,7 +18,8 @@ func TestGoTypeName(t *testing.T) {
 		{"bytes32", "common.Hash"},
 		{"bytes4", "[]byte"},
 		{"uint", "string"},
-		{"uint8", "uint64"},
+		{"uint8", "uint8"},
+		{"uint16", "uint64"},
 		{"uint64", "uint64"},
 		{"uint72", "string"},  // > 64 bits, needs string
 		{"uint80", "string"},  // > 64 bits, needs string
@@ -74,6 +75,8 @@ func TestDBTypeName(t *testing.T) {
 		{"int128", "TEXT"},
 		{"int256", "TEXT"},
 		{"address[]", "TEXT"},
+		{"uint256[]", "TEXT"},
+		{"uint64[3]", "TEXT"},
 	}
 
 	for _, tt := range tests {
@@ -255,6 +258,16 @@ func TestMeddlerTag(t *testing.T) {
 			param: EventParam{Name: "enabled", Type: "bool"},
 			want:  `meddler:"enabled"`,
 		},
+		{
+			name:  "array type",
+			param: EventParam{Name: "ids", Type: "uint256[]"},
+			want:  `meddler:"ids,json"`,
+		},
+		{
+			name:  "bytes4 type",
+			param: EventParam{Name: "selector", Type: "bytes4"},
+			want:  `meddler:"selector"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -265,6 +278,37 @@ func TestMeddlerTag(t *testing.T) {
 	}
 }
 
+func TestABITag(t *testing.T) {
+	tests := []struct {
+		name  string
+		param EventParam
+		want  string
+	}{
+		{
+			name:  "indexed parameter",
+			param: EventParam{Name: "from", Type: "address", Indexed: true},
+			want:  `abi:"from,address,indexed"`,
+		},
+		{
+			name:  "non-indexed parameter",
+			param: EventParam{Name: "value", Type: "uint256"},
+			want:  `abi:"value,uint256"`,
+		},
+		{
+			name:  "tuple field",
+			param: EventParam{Name: "order_maker", Type: "address", Path: []string{"order", "maker"}},
+			want:  `abi:"order.maker,address"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ABITag(tt.param)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestIsIntSizeLargerThan64(t *testing.T) {
 	tests := []struct {
 		name         string
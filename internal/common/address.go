@@ -0,0 +1,20 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// NormalizeAddress parses a hex address in any letter case, with or without the 0x prefix.
+// The Hex method of the returned address gives its EIP-55 checksummed form, so addresses
+// written in different cases compare equal once normalized.
+func NormalizeAddress(addr string) (ethcommon.Address, error) {
+	addr = strings.TrimSpace(addr)
+	if !ethcommon.IsHexAddress(addr) {
+		return ethcommon.Address{}, fmt.Errorf("invalid address: %s", addr)
+	}
+
+	return ethcommon.HexToAddress(addr), nil
+}
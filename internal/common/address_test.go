@@ -0,0 +1,39 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	t.Parallel()
+
+	const checksummed = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+
+	for _, addr := range []string{
+		checksummed,
+		"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+		"0xA0B86991C6218B36C1D19D4A2E9EB0CE3606EB48",
+		"0xa0B86991C6218b36C1d19d4A2e9eB0Ce3606Eb48",
+		"0XA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		"a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+		"  0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48\n",
+	} {
+		address, err := NormalizeAddress(addr)
+		require.NoError(t, err, addr)
+		require.Equal(t, checksummed, address.Hex(), addr)
+	}
+
+	for _, addr := range []string{
+		"",
+		"0x",
+		"0x1234",
+		"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb4",
+		"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb480",
+		"0xg0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+	} {
+		_, err := NormalizeAddress(addr)
+		require.ErrorContains(t, err, "invalid address", addr)
+	}
+}
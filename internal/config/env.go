@@ -0,0 +1,221 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+)
+
+// EnvPrefix is the prefix of the environment variables that override config values.
+// The variable of a value is its key path in the config file in upper case, with the
+// path separators and dashes replaced by underscores, e.g. CI_DOWNLOADER_RPC_URL,
+// CI_METRICS_ENABLED or CI_INDEXERS_0_DB_PATH.
+const EnvPrefix = "CI_"
+
+// ApplyEnvOverrides overrides the values of the configuration with the environment variables set for them.
+//
+// List elements are addressed by their index and only the elements of the config file can be
+// overridden, lists of values are given as comma-separated values, and map entries can only be
+// overridden for the keys set in the config file. Optional sections missing from the config file
+// are created when one of their values is set. The names of the applied variables are returned.
+func ApplyEnvOverrides(cfg *pkgconfig.Config) ([]string, error) {
+	return applyEnvOverrides(cfg, os.LookupEnv)
+}
+
+// applyEnvOverrides overrides the values of the configuration with the variables returned by lookup.
+func applyEnvOverrides(cfg *pkgconfig.Config, lookup func(string) (string, bool)) ([]string, error) {
+	overlay := &envOverlay{lookup: lookup}
+	if err := overlay.apply(strings.TrimSuffix(EnvPrefix, "_"), reflect.ValueOf(cfg).Elem()); err != nil {
+		return nil, err
+	}
+
+	return overlay.applied, nil
+}
+
+// envOverlay walks a configuration and sets the values that have an environment variable.
+type envOverlay struct {
+	lookup  func(string) (string, bool)
+	applied []string
+}
+
+// apply overrides the value v, whose environment variable is name, and the values it contains.
+func (o *envOverlay) apply(name string, v reflect.Value) error {
+	if v.CanAddr() {
+		// Values parsed from text, like durations, are single values
+		if unmarshaler, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			value, ok := o.lookup(name)
+			if !ok {
+				return nil
+			}
+			if err := unmarshaler.UnmarshalText([]byte(value)); err != nil {
+				return fmt.Errorf("invalid value of environment variable %s: %w", name, err)
+			}
+			o.applied = append(o.applied, name)
+
+			return nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			return o.apply(name, v.Elem())
+		}
+
+		// An optional section is only created if one of its values is set
+		section := reflect.New(v.Type().Elem())
+		applied := len(o.applied)
+		if err := o.apply(name, section.Elem()); err != nil {
+			return err
+		}
+		if len(o.applied) > applied {
+			v.Set(section)
+		}
+
+		return nil
+	case reflect.Struct:
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if !field.IsExported() || key == "-" {
+				continue
+			}
+			if key == "" {
+				key = field.Name
+			}
+			if err := o.apply(envName(name, key), v.Field(i)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Struct {
+			for i := range v.Len() {
+				if err := o.apply(envName(name, strconv.Itoa(i)), v.Index(i)); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		value, ok := o.lookup(name)
+		if !ok {
+			return nil
+		}
+
+		values := splitList(value)
+		list := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setScalar(list.Index(i), strings.TrimSpace(value)); err != nil {
+				return fmt.Errorf("invalid value of environment variable %s: %w", name, err)
+			}
+		}
+		v.Set(list)
+		o.applied = append(o.applied, name)
+
+		return nil
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			keyName := envName(name, fmt.Sprint(key.Interface()))
+			value, ok := o.lookup(keyName)
+			if !ok {
+				continue
+			}
+
+			entry := reflect.New(v.Type().Elem()).Elem()
+			if err := setScalar(entry, value); err != nil {
+				return fmt.Errorf("invalid value of environment variable %s: %w", keyName, err)
+			}
+			v.SetMapIndex(key, entry)
+			o.applied = append(o.applied, keyName)
+		}
+
+		return nil
+	default:
+		value, ok := o.lookup(name)
+		if !ok {
+			return nil
+		}
+		if err := setScalar(v, value); err != nil {
+			return fmt.Errorf("invalid value of environment variable %s: %w", name, err)
+		}
+		o.applied = append(o.applied, name)
+
+		return nil
+	}
+}
+
+// envName returns the environment variable of the key below the value whose variable is name.
+func envName(name, key string) string {
+	return name + "_" + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// splitList splits a comma-separated list. Commas inside parentheses, like the ones
+// of event signatures, do not separate values.
+func splitList(value string) []string {
+	var (
+		values []string
+		depth  int
+		start  int
+	)
+	for i, r := range value {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				values = append(values, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(values, value[start:])
+}
+
+// setScalar parses a string, boolean or number into v.
+// The value is left out of the errors, as it may be a secret.
+func setScalar(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("expected a boolean")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return errors.New("expected an integer")
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return errors.New("expected a non-negative integer")
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return errors.New("expected a number")
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("values of type %s cannot be set from the environment", v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFromFile_EnvOverrides(t *testing.T) {
+	t.Setenv("CI_DOWNLOADER_RPC_URL", "https://mainnet.example.com/v3/secret")
+	t.Setenv("CI_DOWNLOADER_DB_PATH", "/var/lib/chainindexor/downloader.sqlite")
+	t.Setenv("CI_DOWNLOADER_CHUNK_SIZE", "2500")
+	t.Setenv("CI_DOWNLOADER_RETRY_MAX_BACKOFF", "1m")
+	t.Setenv("CI_METRICS_ENABLED", "false")
+	t.Setenv("CI_INDEXERS_0_NAME", "USDC")
+	t.Setenv("CI_INDEXERS_0_CONTRACTS_0_EVENTS", "Transfer(address,address,uint256), Approval(address,address,uint256)")
+	t.Setenv("CI_LOGGING_COMPONENT_LEVELS_SYNC_MANAGER", "debug")
+	t.Setenv("CI_API_RATE_LIMIT_BURST", "50")
+
+	for _, path := range []string{
+		"../../config.example.yaml", "../../config.example.json", "../../config.example.toml",
+	} {
+		cfg, err := LoadFromFile(path)
+		require.NoError(t, err, path)
+
+		require.Equal(t, "https://mainnet.example.com/v3/secret", cfg.Downloader.RPCURL)
+		require.Equal(t, "/var/lib/chainindexor/downloader.sqlite", cfg.Downloader.DB.Path)
+		require.Equal(t, uint64(2500), cfg.Downloader.ChunkSize)
+		require.Equal(t, time.Minute, cfg.Downloader.Retry.MaxBackoff.Duration)
+		require.False(t, cfg.Metrics.Enabled)
+		require.Equal(t, "USDC", cfg.Indexers[0].Name)
+		require.Equal(t, []string{"Transfer(address,address,uint256)", "Approval(address,address,uint256)"},
+			cfg.Indexers[0].Contracts[0].Events)
+		require.Equal(t, "debug", cfg.Logging.ComponentLevels["sync-manager"])
+
+		// The rate limit section is not in the config file, it is created for the variable
+		// and gets the defaults of its other values
+		require.NotNil(t, cfg.API.RateLimit)
+		require.Equal(t, 50, cfg.API.RateLimit.Burst)
+		require.InDelta(t, 10, cfg.API.RateLimit.RequestsPerSecond, 0)
+
+		// Values without a variable keep the value of the config file
+		require.Equal(t, "finalized", cfg.Downloader.Finality)
+		require.Equal(t, "./data/mytokenindexer.sqlite", cfg.Indexers[0].DB.Path)
+	}
+}
+
+func TestLoadFromFile_InvalidEnvOverride(t *testing.T) {
+	t.Setenv("CI_DOWNLOADER_CHUNK_SIZE", "secret-value")
+
+	_, err := LoadFromFile("../../config.example.yaml")
+	require.ErrorContains(t, err, "invalid value of environment variable CI_DOWNLOADER_CHUNK_SIZE")
+	require.NotContains(t, err.Error(), "secret-value")
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"CI_DOWNLOADER_FINALITY":        "latest",
+		"CI_DOWNLOADER_FINALIZED_LAG":   "12",
+		"CI_DOWNLOADER_POLL_INTERVAL":   "3s",
+		"CI_INDEXERS_1_START_BLOCK":     "100",
+		"CI_INDEXERS_2_NAME":            "not configured",
+		"CI_CHAINS_0_INDEXERS_0_TYPE":   "erc721",
+		"CI_DOWNLOADER_RETRY_MAX_DELAY": "unknown field",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	cfg := &config.Config{
+		Downloader: config.DownloaderConfig{PollInterval: common.NewDuration(time.Second)},
+		Indexers:   []config.IndexerConfig{{Name: "a"}, {Name: "b"}},
+	}
+
+	applied, err := applyEnvOverrides(cfg, lookup)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"CI_DOWNLOADER_FINALITY",
+		"CI_DOWNLOADER_FINALIZED_LAG",
+		"CI_DOWNLOADER_POLL_INTERVAL",
+		"CI_INDEXERS_1_START_BLOCK",
+	}, applied)
+
+	require.Equal(t, "latest", cfg.Downloader.Finality)
+	require.Equal(t, uint64(12), cfg.Downloader.FinalizedLag)
+	require.Equal(t, 3*time.Second, cfg.Downloader.PollInterval.Duration)
+	require.Equal(t, uint64(100), cfg.Indexers[1].StartBlock)
+
+	// Only the list elements of the config are overridden, and unset sections stay unset
+	require.Len(t, cfg.Indexers, 2)
+	require.Empty(t, cfg.Chains)
+	require.Nil(t, cfg.Downloader.Retry)
+	require.Nil(t, cfg.Metrics)
+}
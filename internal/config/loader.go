@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,23 +15,89 @@ import (
 
 // LoadFromFile loads configuration from a file, auto-detecting the format by extension.
 // Supported formats: .yaml, .yml, .json, .toml
+// The file is validated against the config schema first, so unknown fields are rejected.
+// Values can be overridden by environment variables, see EnvPrefix.
 func LoadFromFile(path string) (*pkgconfig.Config, error) {
+	cfg, err := LoadRawFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return processConfig(cfg)
+}
+
+// LoadRawFromFile loads configuration from a file like LoadFromFile, but returns the values
+// of the file and of the environment variable overrides, without applying defaults or validating them.
+func LoadRawFromFile(path string) (*pkgconfig.Config, error) {
 	ext := strings.ToLower(filepath.Ext(path))
 
+	validationErrors, err := ValidateSchema(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(validationErrors) > 0 {
+		errs := make([]error, len(validationErrors))
+		for i, validationErr := range validationErrors {
+			errs[i] = validationErr
+		}
+
+		return nil, fmt.Errorf("config file does not match schema: %w", errors.Join(errs...))
+	}
+
+	var cfg *pkgconfig.Config
 	switch ext {
 	case ".yaml", ".yml":
-		return LoadFromYAML(path)
+		cfg, err = decodeYAML(path)
 	case ".json":
-		return LoadFromJSON(path)
+		cfg, err = decodeJSON(path)
 	case ".toml":
-		return LoadFromTOML(path)
+		cfg, err = decodeTOML(path)
 	default:
 		return nil, fmt.Errorf("unsupported config file format: %s (supported: .yaml, .yml, .json, .toml)", ext)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := ApplyEnvOverrides(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
 }
 
 // LoadFromYAML loads configuration from a YAML file.
 func LoadFromYAML(path string) (*pkgconfig.Config, error) {
+	cfg, err := decodeYAML(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return processConfig(cfg)
+}
+
+// LoadFromJSON loads configuration from a JSON file.
+func LoadFromJSON(path string) (*pkgconfig.Config, error) {
+	cfg, err := decodeJSON(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return processConfig(cfg)
+}
+
+// LoadFromTOML loads configuration from a TOML file.
+func LoadFromTOML(path string) (*pkgconfig.Config, error) {
+	cfg, err := decodeTOML(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return processConfig(cfg)
+}
+
+// decodeYAML reads the configuration of a YAML file.
+func decodeYAML(path string) (*pkgconfig.Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -41,11 +108,11 @@ func LoadFromYAML(path string) (*pkgconfig.Config, error) {
 		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
 	}
 
-	return processConfig(&cfg)
+	return &cfg, nil
 }
 
-// LoadFromJSON loads configuration from a JSON file.
-func LoadFromJSON(path string) (*pkgconfig.Config, error) {
+// decodeJSON reads the configuration of a JSON file.
+func decodeJSON(path string) (*pkgconfig.Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -56,17 +123,17 @@ func LoadFromJSON(path string) (*pkgconfig.Config, error) {
 		return nil, fmt.Errorf("failed to parse JSON config: %w", err)
 	}
 
-	return processConfig(&cfg)
+	return &cfg, nil
 }
 
-// LoadFromTOML loads configuration from a TOML file.
-func LoadFromTOML(path string) (*pkgconfig.Config, error) {
+// decodeTOML reads the configuration of a TOML file.
+func decodeTOML(path string) (*pkgconfig.Config, error) {
 	var cfg pkgconfig.Config
 	if _, err := toml.DecodeFile(path, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse TOML config: %w", err)
 	}
 
-	return processConfig(&cfg)
+	return &cfg, nil
 }
 
 // processConfig applies defaults and validates the configuration.
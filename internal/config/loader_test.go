@@ -1,10 +1,18 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestLoadFromYAML(t *testing.T) {
@@ -119,7 +127,7 @@ func TestConfigDefaults(t *testing.T) {
 				},
 				Contracts: []config.ContractConfig{
 					{
-						Address: "0x1234",
+						Address: "0x0000000000000000000000000000000000001234",
 						Events:  []string{"Transfer(address,address,uint256)"},
 					},
 				},
@@ -199,7 +207,7 @@ func TestConfigValidation(t *testing.T) {
 						},
 						Contracts: []config.ContractConfig{
 							{
-								Address: "0x1234",
+								Address: "0x0000000000000000000000000000000000001234",
 								Events:  []string{"Transfer(address,address,uint256)"},
 							},
 						},
@@ -224,7 +232,7 @@ func TestConfigValidation(t *testing.T) {
 						},
 						Contracts: []config.ContractConfig{
 							{
-								Address: "0x1234",
+								Address: "0x0000000000000000000000000000000000001234",
 								Events:  []string{"Transfer(address,address,uint256)"},
 							},
 						},
@@ -251,7 +259,7 @@ func TestConfigValidation(t *testing.T) {
 						},
 						Contracts: []config.ContractConfig{
 							{
-								Address: "0x1234",
+								Address: "0x0000000000000000000000000000000000001234",
 								Events:  []string{"Transfer(address,address,uint256)"},
 							},
 						},
@@ -285,3 +293,617 @@ func TestConfigValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthConfigValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    *config.AuthConfig
+		wantErr string
+	}{
+		{
+			name: "disabled",
+			auth: &config.AuthConfig{},
+		},
+		{
+			name: "static keys",
+			auth: &config.AuthConfig{Enabled: true, APIKeys: []string{"secret"}},
+		},
+		{
+			name: "file key source",
+			auth: &config.AuthConfig{
+				Enabled:          true,
+				DynamicKeySource: &config.DynamicKeySourceConfig{Type: config.KeySourceFile, Path: "./keys"},
+			},
+		},
+		{
+			name:    "no keys",
+			auth:    &config.AuthConfig{Enabled: true},
+			wantErr: "api_keys, api_key_hashes or dynamic_key_source is required",
+		},
+		{
+			name: "key hashes",
+			auth: &config.AuthConfig{
+				Enabled:      true,
+				APIKeyHashes: []string{"2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"},
+			},
+		},
+		{
+			name:    "invalid key hash",
+			auth:    &config.AuthConfig{Enabled: true, APIKeyHashes: []string{"secret"}},
+			wantErr: "must be a hex-encoded SHA-256 hash",
+		},
+		{
+			name: "invalid public path",
+			auth: &config.AuthConfig{
+				Enabled:     true,
+				APIKeys:     []string{"secret"},
+				PublicPaths: []string{"health"},
+			},
+			wantErr: "must start with /",
+		},
+		{
+			name: "env key source without env_var",
+			auth: &config.AuthConfig{
+				Enabled:          true,
+				DynamicKeySource: &config.DynamicKeySourceConfig{Type: config.KeySourceEnv},
+			},
+			wantErr: "env_var is required",
+		},
+		{
+			name: "unknown key source type",
+			auth: &config.AuthConfig{
+				Enabled:          true,
+				DynamicKeySource: &config.DynamicKeySourceConfig{Type: "vault"},
+			},
+			wantErr: "invalid type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiCfg := &config.APIConfig{Enabled: true, Auth: tt.auth}
+			apiCfg.ApplyDefaults()
+
+			require.NotZero(t, tt.auth.KeyRotationInterval.Duration)
+			require.NotZero(t, tt.auth.KeyGracePeriod.Duration)
+
+			err := apiCfg.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadFromFile_Chains(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+chains:
+  - chain_id: 1
+    downloader:
+      rpc_url: "https://mainnet.example.com"
+      db:
+        path: "./data/{chain_id}/downloader.db"
+    indexers:
+      - name: "mainnet-erc20"
+        type: "erc20"
+        db:
+          path: "./data/{chain_id}/erc20.db"
+        contracts:
+          - address: "0x0000000000000000000000000000000000001234"
+            events: ["Transfer(address,address,uint256)"]
+  - chain_id: 137
+    downloader:
+      rpc_url: "https://polygon.example.com"
+      finality: "latest"
+      db:
+        path: "./data/{chain_id}/downloader.db"
+    indexers:
+      - name: "polygon-erc20"
+        type: "erc20"
+        db:
+          path: "./data/{chain_id}/erc20.db"
+        contracts:
+          - address: "0x0000000000000000000000000000000000005678"
+            events: ["Transfer(address,address,uint256)"]
+`)
+
+	cfg, err := LoadFromFile(path)
+	require.NoError(t, err)
+
+	chains := cfg.ChainConfigs()
+	require.Len(t, chains, 2)
+
+	require.Equal(t, uint64(1), chains[0].ChainID)
+	require.Equal(t, "./data/1/downloader.db", chains[0].Downloader.DB.Path)
+	require.Equal(t, "./data/1/erc20.db", chains[0].Indexers[0].DB.Path)
+	require.Equal(t, "finalized", chains[0].Downloader.Finality)
+
+	require.Equal(t, uint64(137), chains[1].ChainID)
+	require.Equal(t, "./data/137/downloader.db", chains[1].Downloader.DB.Path)
+	require.Equal(t, "./data/137/erc20.db", chains[1].Indexers[0].DB.Path)
+	require.Equal(t, "latest", chains[1].Downloader.Finality)
+
+	chainCfg := cfg.ForChain(chains[1])
+	require.Equal(t, "https://polygon.example.com", chainCfg.Downloader.RPCURL)
+	require.Equal(t, "polygon-erc20", chainCfg.Indexers[0].Name)
+	require.Empty(t, chainCfg.Chains)
+
+	// The RPC endpoint of a chain must serve the chain's ID
+	require.Equal(t, uint64(137), chainCfg.Downloader.ExpectedChainID)
+}
+
+func TestLoadFromFile_DBPathPlaceholders(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+chains:
+  - chain_id: 1
+    downloader:
+      rpc_url: "https://mainnet.example.com"
+      db:
+        path: "./data/{chain_id}/downloader-{date}.db"
+    indexers:
+      - name: "erc20"
+        type: "erc20"
+        db:
+          path: "./data/{chain_id}/{indexer_name}.db"
+        contracts:
+          - address: "0x0000000000000000000000000000000000001234"
+            events: ["Transfer(address,address,uint256)"]
+`)
+
+	cfg, err := LoadFromFile(path)
+	require.NoError(t, err)
+
+	chain := cfg.ChainConfigs()[0]
+	require.Equal(t, "./data/1/downloader-"+time.Now().Format(time.DateOnly)+".db", chain.Downloader.DB.Path)
+	require.Equal(t, "./data/1/erc20.db", chain.Indexers[0].DB.Path)
+}
+
+func TestLoadFromFile_InvalidDBPathPlaceholders(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "indexer name in the downloader path",
+			config: `
+downloader:
+  rpc_url: "https://mainnet.example.com"
+  db:
+    path: "./data/{indexer_name}.db"
+indexers:
+  - name: "erc20"
+    db:
+      path: "./data/erc20.db"
+    contracts:
+      - address: "0x0000000000000000000000000000000000001234"
+        events: ["Transfer(address,address,uint256)"]
+`,
+			wantErr: "downloader.db.path: no value for {indexer_name}",
+		},
+		{
+			name: "indexer name escaping the directory",
+			config: `
+downloader:
+  rpc_url: "https://mainnet.example.com"
+  db:
+    path: "./data/downloader.db"
+indexers:
+  - name: "../erc20"
+    db:
+      path: "./data/{indexer_name}.db"
+    contracts:
+      - address: "0x0000000000000000000000000000000000001234"
+        events: ["Transfer(address,address,uint256)"]
+`,
+			wantErr: "indexer[0] (../erc20): db.path: value \"../erc20\" of {indexer_name}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFromFile(writeConfigFile(t, "config.yaml", tt.config))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestChainConfigs_Legacy(t *testing.T) {
+	cfg, err := LoadFromFile("../../config.example.yaml")
+	require.NoError(t, err)
+
+	// A configuration without chains is promoted to a single chain
+	chains := cfg.ChainConfigs()
+	require.Len(t, chains, 1)
+	require.Zero(t, chains[0].ChainID)
+	require.Equal(t, cfg.Downloader, chains[0].Downloader)
+	require.Equal(t, cfg.Indexers, chains[0].Indexers)
+}
+
+func TestChainsValidation(t *testing.T) {
+	newChain := func(chainID uint64, dbPath, indexerName string) config.ChainConfig {
+		return config.ChainConfig{
+			ChainID: chainID,
+			Downloader: config.DownloaderConfig{
+				RPCURL: "https://test.com",
+				DB:     config.DatabaseConfig{Path: dbPath},
+			},
+			Indexers: []config.IndexerConfig{
+				{
+					Name: indexerName,
+					DB:   config.DatabaseConfig{Path: "./" + indexerName + ".db"},
+					Contracts: []config.ContractConfig{
+						{Address: "0x0000000000000000000000000000000000001234", Events: []string{"Transfer(address,address,uint256)"}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		cfg     *config.Config
+		wantErr string
+	}{
+		{
+			name: "valid chains",
+			cfg: &config.Config{Chains: []config.ChainConfig{
+				newChain(1, "./{chain_id}.db", "mainnet"),
+				newChain(137, "./{chain_id}.db", "polygon"),
+			}},
+		},
+		{
+			name: "top-level downloader with chains",
+			cfg: &config.Config{
+				Downloader: config.DownloaderConfig{RPCURL: "https://test.com"},
+				Chains:     []config.ChainConfig{newChain(1, "./1.db", "mainnet")},
+			},
+			wantErr: "downloader and indexers must be configured per chain",
+		},
+		{
+			name:    "missing chain_id",
+			cfg:     &config.Config{Chains: []config.ChainConfig{newChain(0, "./0.db", "mainnet")}},
+			wantErr: "chains[0].chain_id is required",
+		},
+		{
+			name: "duplicate chain_id",
+			cfg: &config.Config{Chains: []config.ChainConfig{
+				newChain(1, "./a.db", "mainnet"),
+				newChain(1, "./b.db", "polygon"),
+			}},
+			wantErr: "chains[1]: duplicate chain_id 1",
+		},
+		{
+			name: "shared downloader database",
+			cfg: &config.Config{Chains: []config.ChainConfig{
+				newChain(1, "./downloader.db", "mainnet"),
+				newChain(137, "./downloader.db", "polygon"),
+			}},
+			wantErr: `chains[1].downloader.db: "./downloader.db" is already used by chain 1`,
+		},
+		{
+			name: "invalid chain downloader",
+			cfg: &config.Config{Chains: []config.ChainConfig{
+				newChain(1, "./1.db", "mainnet"),
+				{ChainID: 137, Downloader: config.DownloaderConfig{DB: config.DatabaseConfig{Path: "./137.db"}}},
+			}},
+			wantErr: "chains[1].downloader.rpc_url is required",
+		},
+		{
+			name: "indexer name used by another chain",
+			cfg: &config.Config{Chains: []config.ChainConfig{
+				newChain(1, "./{chain_id}.db", "erc20"),
+				newChain(137, "./{chain_id}.db", "erc20"),
+			}},
+			wantErr: "chains[1].indexer[0]: duplicate indexer name 'erc20'",
+		},
+		{
+			name: "expected chain ID of another chain",
+			cfg: &config.Config{Chains: []config.ChainConfig{
+				func() config.ChainConfig {
+					chain := newChain(1, "./1.db", "mainnet")
+					chain.Downloader.ExpectedChainID = 11155111
+					return chain
+				}(),
+			}},
+			wantErr: "chains[0].downloader.expected_chain_id 11155111 does not match chain_id 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.ApplyDefaults()
+
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDownloaderConfig_RPCURLs(t *testing.T) {
+	downloader := config.DownloaderConfig{RPCURL: "https://primary.example.com, https://secondary.example.com"}
+	require.Equal(t, []string{"https://primary.example.com", "https://secondary.example.com"}, downloader.RPCURLs())
+
+	downloader.RPCURL = "https://primary.example.com"
+	require.Equal(t, []string{"https://primary.example.com"}, downloader.RPCURLs())
+	require.False(t, downloader.UsesWebSocket())
+
+	downloader.RPCURL = "wss://primary.example.com, ws://secondary.example.com"
+	require.True(t, downloader.UsesWebSocket())
+
+	downloader.RPCURL = "wss://primary.example.com,https://secondary.example.com"
+	require.False(t, downloader.UsesWebSocket())
+
+	// Every URL must be a websocket URL in pending mode
+	cfg := &config.Config{
+		Downloader: config.DownloaderConfig{
+			RPCURL:      "wss://primary.example.com,https://secondary.example.com",
+			PendingMode: true,
+			DB:          config.DatabaseConfig{Path: "./test.db"},
+		},
+	}
+	cfg.ApplyDefaults()
+	require.ErrorContains(t, cfg.Validate(),
+		`must be a websocket URL when pending_mode is enabled, got "https://secondary.example.com"`)
+
+	cfg.Downloader.RPCURL = "wss://primary.example.com,"
+	require.ErrorContains(t, cfg.Validate(), "downloader.rpc_url must not contain empty URLs")
+}
+
+func TestValidateIndexerTypes(t *testing.T) {
+	registered := []string{"erc721", "erc20"}
+
+	legacy := &config.Config{
+		Indexers: []config.IndexerConfig{
+			{Name: "usdc", Type: "erc20"},
+			{Name: "pairs", Type: "uniswap-v2"},
+		},
+	}
+	err := legacy.ValidateIndexerTypes(registered)
+	require.EqualError(t, err,
+		"indexer[1] (pairs): unknown indexer type 'uniswap-v2' (registered types: erc20, erc721)")
+
+	// Every indexer with an unknown type is reported
+	chains := &config.Config{
+		Chains: []config.ChainConfig{
+			{ChainID: 1, Indexers: []config.IndexerConfig{{Name: "usdc", Type: "erc20"}}},
+			{ChainID: 137, Indexers: []config.IndexerConfig{
+				{Name: "punks", Type: "erc721"},
+				{Name: "pairs", Type: "uniswap-v2"},
+				{Name: "bonds", Type: ""},
+			}},
+		},
+	}
+	err = chains.ValidateIndexerTypes(registered)
+	require.EqualError(t, err,
+		"chains[1].indexer[1] (pairs): unknown indexer type 'uniswap-v2' (registered types: erc20, erc721)\n"+
+			"chains[1].indexer[2] (bonds): unknown indexer type '' (registered types: erc20, erc721)")
+
+	require.NoError(t, chains.ValidateIndexerTypes([]string{"erc20", "erc721", "uniswap-v2", ""}))
+
+	// Types are matched case-insensitively, and a misspelled type gets the closest registered type as a suggestion
+	misspelled := &config.Config{
+		Indexers: []config.IndexerConfig{
+			{Name: "usdc", Type: "ERC20"},
+			{Name: "punks", Type: "erc712"},
+		},
+	}
+	err = misspelled.ValidateIndexerTypes(registered)
+	require.EqualError(t, err,
+		"indexer[1] (punks): unknown indexer type 'erc712', did you mean 'erc721'? (registered types: erc20, erc721)")
+}
+
+func TestLoadFromFile_SameConfigInEveryFormat(t *testing.T) {
+	yamlCfg, err := LoadFromFile("../../config.example.yaml")
+	require.NoError(t, err)
+
+	for _, path := range []string{"../../config.example.json", "../../config.example.toml"} {
+		cfg, err := LoadFromFile(path)
+		require.NoError(t, err)
+		require.Equal(t, yamlCfg, cfg, "%s differs from config.example.yaml", path)
+	}
+}
+
+func TestLoadFromFile_RoundTrip(t *testing.T) {
+	cfg, err := LoadFromFile("../../config.example.yaml")
+	require.NoError(t, err)
+
+	encoders := map[string]func(*config.Config) ([]byte, error){
+		".yaml": func(cfg *config.Config) ([]byte, error) { return yaml.Marshal(cfg) },
+		".json": func(cfg *config.Config) ([]byte, error) { return json.Marshal(cfg) },
+		".toml": func(cfg *config.Config) ([]byte, error) {
+			var buf bytes.Buffer
+			err := toml.NewEncoder(&buf).Encode(cfg)
+			return buf.Bytes(), err
+		},
+	}
+
+	for ext, encode := range encoders {
+		t.Run(ext, func(t *testing.T) {
+			data, err := encode(cfg)
+			require.NoError(t, err)
+
+			path := filepath.Join(t.TempDir(), "config"+ext)
+			require.NoError(t, os.WriteFile(path, data, 0o600))
+
+			loaded, err := LoadFromFile(path)
+			require.NoError(t, err)
+			require.True(t, reflect.DeepEqual(cfg, loaded), "config changed by a %s round trip:\n%s", ext, data)
+		})
+	}
+}
+
+func TestRetryConfig_Jitter(t *testing.T) {
+	retry := &config.RetryConfig{}
+	retry.ApplyDefaults()
+	require.InDelta(t, 0.2, retry.Jitter, 0)
+	require.NoError(t, retry.Validate())
+
+	retry.Jitter = 1
+	require.NoError(t, retry.Validate())
+
+	for _, jitter := range []float64{-0.1, 1.5} {
+		retry.Jitter = jitter
+		require.ErrorContains(t, retry.Validate(), "jitter must be between 0 and 1")
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	api := &config.APIConfig{Enabled: true, TLS: &config.TLSConfig{CertFile: "tls.crt", KeyFile: "tls.key"}}
+	api.ApplyDefaults()
+	require.Equal(t, config.TLSVersion12, api.TLS.MinVersion)
+	require.NoError(t, api.Validate())
+
+	api.TLS.MinVersion = "1.3"
+	require.NoError(t, api.Validate())
+
+	api.TLS.MinVersion = "1.1"
+	require.ErrorContains(t, api.Validate(), `tls: invalid min_version "1.1"`)
+
+	api.TLS = &config.TLSConfig{CertFile: "tls.crt", MinVersion: "1.2"}
+	require.ErrorContains(t, api.Validate(), "tls: cert_file and key_file are required")
+}
+
+func TestAddressLabelsConfig(t *testing.T) {
+	api := &config.APIConfig{
+		Enabled:       true,
+		ListenAddress: ":8080",
+		AddressLabels: map[string]string{"0x7a250d5630b4cf539739df2c5dacb4c659f2488d": "Uniswap V2 Router"},
+	}
+	require.NoError(t, api.Validate())
+
+	api.AddressLabels["router"] = "Uniswap V2 Router"
+	require.ErrorContains(t, api.Validate(), "address_labels: invalid address: router")
+
+	api.AddressLabels = map[string]string{"0x7a250d5630b4cf539739df2c5dacb4c659f2488d": ""}
+	require.ErrorContains(t, api.Validate(), "address_labels: label of 0x7a250d5630b4cf539739df2c5dacb4c659f2488d is required")
+}
+
+func TestCacheConfig(t *testing.T) {
+	cfg := &config.Config{
+		Downloader: config.DownloaderConfig{
+			RPCURL: "https://example.com",
+			DB:     config.DatabaseConfig{Path: "./test.db"},
+		},
+		Indexers: []config.IndexerConfig{{
+			Name:      "tokens",
+			Type:      "erc20",
+			DB:        config.DatabaseConfig{Path: "./tokens.db"},
+			Contracts: []config.ContractConfig{{Address: "0x0000000000000000000000000000000000000001", Events: []string{"Transfer(address,address,uint256)"}}},
+			Cache:     &config.CacheConfig{Enabled: true},
+		}},
+	}
+	cfg.ApplyDefaults()
+	require.Equal(t, 30*time.Second, cfg.Indexers[0].Cache.TTL.Duration)
+	require.Equal(t, 1000, cfg.Indexers[0].Cache.MaxEntries)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Indexers[0].Cache.MaxEntries = -1
+	require.ErrorContains(t, cfg.Validate(), "indexer[0] (tokens), cache: max_entries must be non-negative")
+}
+
+func TestAutoRecoveryConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		autoRecovery string
+		expected     bool
+	}{
+		{name: "enabled by default", expected: true},
+		{name: "enabled", autoRecovery: "  auto_recovery: true\n", expected: true},
+		{name: "disabled", autoRecovery: "  auto_recovery: false\n", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadFromFile(writeConfigFile(t, "config.yaml", `
+downloader:
+  rpc_url: "https://mainnet.example.com"
+`+tt.autoRecovery+`  db:
+    path: "./data/downloader.db"
+indexers:
+  - name: "erc20"
+    type: "erc20"
+    db:
+      path: "./data/erc20.db"
+    contracts:
+      - address: "0x0000000000000000000000000000000000001234"
+        events: ["Transfer(address,address,uint256)"]
+`))
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, cfg.Downloader.AutoRecoveryEnabled())
+		})
+	}
+}
+
+func TestMaxOffsetConfig(t *testing.T) {
+	cfg := &config.Config{
+		Downloader: config.DownloaderConfig{
+			RPCURL: "https://example.com",
+			DB:     config.DatabaseConfig{Path: "./test.db"},
+		},
+		Indexers: []config.IndexerConfig{{
+			Name:      "tokens",
+			Type:      "erc20",
+			DB:        config.DatabaseConfig{Path: "./tokens.db"},
+			Contracts: []config.ContractConfig{{Address: "0x0000000000000000000000000000000000000001", Events: []string{"Transfer(address,address,uint256)"}}},
+		}},
+	}
+	cfg.ApplyDefaults()
+	require.Equal(t, 100_000, cfg.Indexers[0].MaxOffset)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Indexers[0].MaxOffset = -1
+	require.ErrorContains(t, cfg.Validate(), "indexer[0] (tokens): max_offset must be non-negative")
+}
+
+func TestContractAddressNormalization(t *testing.T) {
+	const checksummed = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+
+	newConfig := func(addresses ...string) *config.Config {
+		contracts := make([]config.ContractConfig, 0, len(addresses))
+		for _, address := range addresses {
+			contracts = append(contracts, config.ContractConfig{
+				Address: address,
+				Events:  []string{"Transfer(address,address,uint256)"},
+			})
+		}
+
+		cfg := &config.Config{
+			Downloader: config.DownloaderConfig{
+				RPCURL: "https://example.com",
+				DB:     config.DatabaseConfig{Path: "./test.db"},
+			},
+			Indexers: []config.IndexerConfig{{
+				Name:      "tokens",
+				Type:      "erc20",
+				DB:        config.DatabaseConfig{Path: "./tokens.db"},
+				Contracts: contracts,
+			}},
+		}
+		cfg.ApplyDefaults()
+
+		return cfg
+	}
+
+	cfg := newConfig(
+		"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+		"0xA0B86991C6218B36C1D19D4A2E9EB0CE3606EB48",
+		"0xa0B86991C6218b36C1d19d4A2e9eB0Ce3606Eb48",
+	)
+	require.NoError(t, cfg.Validate())
+	for _, contract := range cfg.Indexers[0].Contracts {
+		require.Equal(t, checksummed, contract.Address)
+	}
+
+	cfg = newConfig(checksummed, "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb4")
+	require.EqualError(t, cfg.Validate(),
+		"indexer[0] (tokens), contract[1]: invalid address: 0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb4")
+}
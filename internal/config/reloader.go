@@ -0,0 +1,249 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+)
+
+// ReloadTarget is the running downloader of a chain that the reloader applies config changes to.
+type ReloadTarget interface {
+	// AddIndexer creates an indexer from its configuration and registers it with the downloader.
+	AddIndexer(cfg pkgconfig.IndexerConfig) error
+
+	// UpdateDownloaderConfig applies the chunk size and retention policy of the downloader configuration.
+	UpdateDownloaderConfig(cfg pkgconfig.DownloaderConfig)
+}
+
+// ConfigDiff lists the differences between the running configuration of a chain and the reloaded one.
+type ConfigDiff struct {
+	// AddedIndexers are the indexers that are only in the reloaded configuration
+	AddedIndexers []pkgconfig.IndexerConfig
+
+	// DownloaderChanged is set when the chunk size or the retention policy changed
+	DownloaderChanged bool
+
+	// Rejected describes the changes that require a restart and are not applied
+	Rejected []string
+}
+
+// Empty reports whether there are no changes to apply.
+func (d ConfigDiff) Empty() bool {
+	return len(d.AddedIndexers) == 0 && !d.DownloaderChanged
+}
+
+// DiffChain compares the running configuration of a chain with the reloaded one.
+// New indexers, the chunk size and the retention policy can be changed at runtime.
+// Changing anything else requires a restart and is rejected.
+func DiffChain(oldChain, newChain pkgconfig.ChainConfig) ConfigDiff {
+	var diff ConfigDiff
+
+	oldDownloader, newDownloader := oldChain.Downloader, newChain.Downloader
+	if oldDownloader.RPCURL != newDownloader.RPCURL {
+		diff.Rejected = append(diff.Rejected, "rpc_url")
+	}
+	if !reflect.DeepEqual(oldDownloader.DB, newDownloader.DB) {
+		diff.Rejected = append(diff.Rejected, "db")
+	}
+
+	diff.DownloaderChanged = oldDownloader.ChunkSize != newDownloader.ChunkSize ||
+		!reflect.DeepEqual(oldDownloader.RetentionPolicy, newDownloader.RetentionPolicy)
+
+	// Compare the remaining downloader settings with the ones handled above left out
+	oldDownloader.RPCURL, newDownloader.RPCURL = "", ""
+	oldDownloader.DB, newDownloader.DB = pkgconfig.DatabaseConfig{}, pkgconfig.DatabaseConfig{}
+	oldDownloader.ChunkSize, newDownloader.ChunkSize = 0, 0
+	oldDownloader.RetentionPolicy, newDownloader.RetentionPolicy = nil, nil
+	if !reflect.DeepEqual(oldDownloader, newDownloader) {
+		diff.Rejected = append(diff.Rejected, "downloader settings other than chunk_size and retention_policy")
+	}
+
+	oldIndexers := make(map[string]pkgconfig.IndexerConfig, len(oldChain.Indexers))
+	for _, idxCfg := range oldChain.Indexers {
+		oldIndexers[idxCfg.Name] = idxCfg
+	}
+
+	newIndexers := make(map[string]struct{}, len(newChain.Indexers))
+	for _, idxCfg := range newChain.Indexers {
+		newIndexers[idxCfg.Name] = struct{}{}
+
+		oldIdxCfg, exists := oldIndexers[idxCfg.Name]
+		switch {
+		case !exists:
+			diff.AddedIndexers = append(diff.AddedIndexers, idxCfg)
+		case !reflect.DeepEqual(oldIdxCfg, idxCfg):
+			diff.Rejected = append(diff.Rejected, fmt.Sprintf("indexer %s", idxCfg.Name))
+		}
+	}
+
+	for _, idxCfg := range oldChain.Indexers {
+		if _, exists := newIndexers[idxCfg.Name]; !exists {
+			diff.Rejected = append(diff.Rejected, fmt.Sprintf("removal of indexer %s", idxCfg.Name))
+		}
+	}
+
+	return diff
+}
+
+// ConfigReloader re-reads the config file and applies the changes that do not require a restart
+// to the running downloaders: new indexers, the chunk size, the retention policy and the log levels.
+// Other changes are logged as rejected and keep their running values.
+type ConfigReloader struct {
+	path    string
+	targets map[uint64]ReloadTarget
+	log     *logger.Logger
+
+	mu      sync.Mutex
+	current *pkgconfig.Config
+}
+
+// NewConfigReloader creates a reloader of the config file at path, which the running config was loaded from.
+// Targets are the running downloaders by configured chain ID, 0 for a config without chains.
+func NewConfigReloader(
+	path string,
+	current *pkgconfig.Config,
+	targets map[uint64]ReloadTarget,
+	log *logger.Logger,
+) *ConfigReloader {
+	return &ConfigReloader{
+		path:    path,
+		current: current,
+		targets: targets,
+		log:     log,
+	}
+}
+
+// Run reloads the config file every time a signal is received, until the context is cancelled.
+// A failed reload is logged and keeps the running config.
+func (r *ConfigReloader) Run(ctx context.Context, signals <-chan os.Signal) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-signals:
+			r.log.Infof("Received %s, reloading config from %s", sig, r.path)
+			if err := r.Reload(); err != nil {
+				r.log.Warnf("Failed to reload config: %v", err)
+			}
+		}
+	}
+}
+
+// Reload re-reads the config file and applies its changes.
+func (r *ConfigReloader) Reload() error {
+	cfg, err := LoadFromFile(r.path)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	oldChains := r.current.ChainConfigs()
+	newChains := make(map[uint64]pkgconfig.ChainConfig, len(oldChains))
+	for _, chain := range cfg.ChainConfigs() {
+		newChains[chain.ChainID] = chain
+	}
+
+	// Chains are not changed partially, so check them all before applying anything
+	if len(newChains) != len(oldChains) {
+		return fmt.Errorf("adding or removing chains requires a restart")
+	}
+	for _, oldChain := range oldChains {
+		if _, exists := newChains[oldChain.ChainID]; !exists {
+			return fmt.Errorf("removing chain %d requires a restart", oldChain.ChainID)
+		}
+	}
+
+	// The applied config keeps the running values of the rejected changes
+	applied := *r.current
+	appliedChains := make([]pkgconfig.ChainConfig, len(oldChains))
+	for i, oldChain := range oldChains {
+		appliedChains[i] = r.applyChain(oldChain, newChains[oldChain.ChainID])
+	}
+
+	if len(applied.Chains) > 0 {
+		applied.Chains = appliedChains
+	} else {
+		applied.Downloader = appliedChains[0].Downloader
+		applied.Indexers = appliedChains[0].Indexers
+	}
+
+	if !reflect.DeepEqual(r.current.Logging, cfg.Logging) && cfg.Logging != nil {
+		if err := logger.SetComponentLevels(cfg.Logging); err != nil {
+			r.log.Warnf("Failed to apply log levels: %v", err)
+		} else {
+			applied.Logging = cfg.Logging
+			r.log.Info("Log levels updated")
+		}
+	}
+
+	restartOnly := []struct {
+		name    string
+		changed bool
+	}{
+		{name: "metrics", changed: !reflect.DeepEqual(r.current.Metrics, cfg.Metrics)},
+		{name: "api", changed: !reflect.DeepEqual(r.current.API, cfg.API)},
+		{name: "tracing", changed: !reflect.DeepEqual(r.current.Tracing, cfg.Tracing)},
+		{name: "grpc", changed: !reflect.DeepEqual(r.current.GRPC, cfg.GRPC)},
+	}
+	for _, section := range restartOnly {
+		if section.changed {
+			r.log.Warnf("Changes to %s require a restart and were not applied", section.name)
+		}
+	}
+
+	r.current = &applied
+
+	return nil
+}
+
+// applyChain applies the changes of a chain to its running downloader and returns the applied chain config.
+func (r *ConfigReloader) applyChain(oldChain, newChain pkgconfig.ChainConfig) pkgconfig.ChainConfig {
+	log := r.log
+	if oldChain.ChainID != 0 {
+		log = log.WithChainID(oldChain.ChainID)
+	}
+
+	diff := DiffChain(oldChain, newChain)
+	for _, rejected := range diff.Rejected {
+		log.Warnf("Changes to %s require a restart and were not applied", rejected)
+	}
+
+	applied := oldChain
+	if diff.Empty() {
+		return applied
+	}
+
+	target, exists := r.targets[oldChain.ChainID]
+	if !exists {
+		log.Warn("No running downloader to apply the config changes to")
+		return applied
+	}
+
+	if diff.DownloaderChanged {
+		applied.Downloader.ChunkSize = newChain.Downloader.ChunkSize
+		applied.Downloader.RetentionPolicy = newChain.Downloader.RetentionPolicy
+		target.UpdateDownloaderConfig(applied.Downloader)
+		log.Infof("Applied chunk_size %d and retention_policy", applied.Downloader.ChunkSize)
+	}
+
+	// Copy the indexers, so the running config is not modified
+	applied.Indexers = append([]pkgconfig.IndexerConfig(nil), oldChain.Indexers...)
+	for _, idxCfg := range diff.AddedIndexers {
+		if err := target.AddIndexer(idxCfg); err != nil {
+			log.Warnf("Failed to add indexer %s: %v", idxCfg.Name, err)
+			continue
+		}
+
+		applied.Indexers = append(applied.Indexers, idxCfg)
+		log.Infof("✓ Added indexer: %s", idxCfg.Name)
+	}
+
+	return applied
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTarget records the config changes applied to it.
+type recordingTarget struct {
+	added      []string
+	downloader []config.DownloaderConfig
+}
+
+func (r *recordingTarget) AddIndexer(cfg config.IndexerConfig) error {
+	r.added = append(r.added, cfg.Name)
+	return nil
+}
+
+func (r *recordingTarget) UpdateDownloaderConfig(cfg config.DownloaderConfig) {
+	r.downloader = append(r.downloader, cfg)
+}
+
+const reloadConfig = `
+downloader:
+  rpc_url: "%s"
+  chunk_size: %d
+  db:
+    path: "./data/downloader.db"
+indexers:
+  - name: "erc20"
+    type: "erc20"
+    db:
+      path: "./data/erc20.db"
+    contracts:
+      - address: "0x0000000000000000000000000000000000001234"
+        events: ["Transfer(address,address,uint256)"]
+%s`
+
+const reloadAddedIndexer = `
+  - name: "erc721"
+    type: "erc721"
+    db:
+      path: "./data/erc721.db"
+    contracts:
+      - address: "0x0000000000000000000000000000000000005678"
+        events: ["Transfer(address,address,uint256)"]
+`
+
+func TestDiffChain(t *testing.T) {
+	t.Parallel()
+
+	base := config.ChainConfig{
+		Downloader: config.DownloaderConfig{
+			RPCURL:    "https://eth.example.com",
+			ChunkSize: 1000,
+			Finality:  "finalized",
+			DB:        config.DatabaseConfig{Path: "./data/downloader.db"},
+		},
+		Indexers: []config.IndexerConfig{
+			{Name: "erc20", Type: "erc20", StartBlock: 100},
+			{Name: "erc721", Type: "erc721"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		change       func(chain *config.ChainConfig)
+		wantAdded    []string
+		wantChanged  bool
+		wantRejected []string
+	}{
+		{
+			name:   "unchanged",
+			change: func(*config.ChainConfig) {},
+		},
+		{
+			name: "chunk size and retention policy",
+			change: func(chain *config.ChainConfig) {
+				chain.Downloader.ChunkSize = 500
+				chain.Downloader.RetentionPolicy = &config.RetentionPolicyConfig{MaxDBSizeMB: 100}
+			},
+			wantChanged: true,
+		},
+		{
+			name: "added indexer",
+			change: func(chain *config.ChainConfig) {
+				chain.Indexers = append(chain.Indexers, config.IndexerConfig{Name: "weth", Type: "erc20"})
+			},
+			wantAdded: []string{"weth"},
+		},
+		{
+			name: "rpc url and database",
+			change: func(chain *config.ChainConfig) {
+				chain.Downloader.RPCURL = "https://other.example.com"
+				chain.Downloader.DB.Path = "./other/downloader.db"
+			},
+			wantRejected: []string{"rpc_url", "db"},
+		},
+		{
+			name: "other downloader settings",
+			change: func(chain *config.ChainConfig) {
+				chain.Downloader.Finality = "latest"
+			},
+			wantRejected: []string{"downloader settings other than chunk_size and retention_policy"},
+		},
+		{
+			name: "changed and removed indexers",
+			change: func(chain *config.ChainConfig) {
+				chain.Indexers = []config.IndexerConfig{{Name: "erc20", Type: "erc20", StartBlock: 50}}
+			},
+			wantRejected: []string{"indexer erc20", "removal of indexer erc721"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			changed := base
+			changed.Indexers = append([]config.IndexerConfig(nil), base.Indexers...)
+			tt.change(&changed)
+
+			diff := DiffChain(base, changed)
+
+			var added []string
+			for _, idxCfg := range diff.AddedIndexers {
+				added = append(added, idxCfg.Name)
+			}
+			require.Equal(t, tt.wantAdded, added)
+			require.Equal(t, tt.wantChanged, diff.DownloaderChanged)
+			require.Equal(t, tt.wantRejected, diff.Rejected)
+		})
+	}
+}
+
+func TestConfigReloader_Reload(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfigFile(t, "config.yaml", sprintfConfig("https://eth.example.com", 1000, ""))
+	cfg, err := LoadFromFile(path)
+	require.NoError(t, err)
+
+	target := &recordingTarget{}
+	reloader := NewConfigReloader(path, cfg, map[uint64]ReloadTarget{0: target}, logger.NewNopLogger())
+
+	// The RPC URL is rejected, the chunk size and the new indexer are applied
+	require.NoError(t, os.WriteFile(path,
+		[]byte(sprintfConfig("https://other.example.com", 500, reloadAddedIndexer)), 0o600))
+	require.NoError(t, reloader.Reload())
+
+	require.Equal(t, []string{"erc721"}, target.added)
+	require.Len(t, target.downloader, 1)
+	require.Equal(t, uint64(500), target.downloader[0].ChunkSize)
+	require.Equal(t, "https://eth.example.com", target.downloader[0].RPCURL)
+
+	// Reloading the same file again applies nothing new
+	require.NoError(t, reloader.Reload())
+	require.Equal(t, []string{"erc721"}, target.added)
+	require.Len(t, target.downloader, 1)
+
+	// An invalid config file keeps the running config
+	require.NoError(t, os.WriteFile(path, []byte("downloader: ["), 0o600))
+	require.Error(t, reloader.Reload())
+}
+
+// sprintfConfig returns a config file with the given downloader settings and additional indexers.
+func sprintfConfig(rpcURL string, chunkSize int, extraIndexers string) string {
+	return fmt.Sprintf(reloadConfig, rpcURL, chunkSize, extraIndexers)
+}
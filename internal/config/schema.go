@@ -0,0 +1,259 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	pkgconfig "github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/invopop/jsonschema"
+	validator "github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaURL is the location the generated config schema is registered under in the validator.
+const schemaURL = "chainindexor-config.json"
+
+// ValidationError describes a single schema violation in a config file.
+type ValidationError struct {
+	// Field is the dotted path of the offending field, e.g. "downloader.maintenance.max_db_size"
+	Field string
+
+	// Expected is the expected JSON type of the field, empty for violations that are not type mismatches
+	Expected string
+
+	// Message describes the violation
+	Message string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// configSchema compiles the JSON Schema of pkgconfig.Config once.
+var configSchema = sync.OnceValues(func() (*validator.Schema, error) {
+	reflector := &jsonschema.Reflector{
+		Anonymous: true,
+		// No field is required by the schema, missing fields are reported by Config.Validate
+		RequiredFromJSONSchemaTags: true,
+	}
+
+	data, err := json.Marshal(reflector.Reflect(&pkgconfig.Config{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config schema: %w", err)
+	}
+
+	doc, err := validator.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config schema: %w", err)
+	}
+
+	compiler := validator.NewCompiler()
+	if err := compiler.AddResource(schemaURL, doc); err != nil {
+		return nil, fmt.Errorf("failed to add config schema: %w", err)
+	}
+
+	return compiler.Compile(schemaURL)
+})
+
+// ValidateSchema validates the config file at path against the JSON Schema generated from
+// pkgconfig.Config, before it is unmarshalled into the struct. Unlike struct unmarshalling,
+// it reports unknown fields (e.g. typos) and values of the wrong type.
+// The returned error is non-nil only if the file cannot be read or parsed.
+func ValidateSchema(path string) ([]ValidationError, error) {
+	instance, anchorKeys, err := readGenericConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := configSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.Validate(instance)
+	if err == nil {
+		return nil, nil
+	}
+
+	var schemaErr *validator.ValidationError
+	if !errors.As(err, &schemaErr) {
+		return nil, fmt.Errorf("failed to validate config schema: %w", err)
+	}
+
+	validationErrors := collectValidationErrors(schemaErr, message.NewPrinter(language.English), nil)
+
+	// Top-level YAML keys that only hold anchors for reuse elsewhere are ignored by the parser
+	validationErrors = slices.DeleteFunc(validationErrors, func(e ValidationError) bool {
+		return e.Expected == "" && slices.Contains(anchorKeys, e.Field)
+	})
+	if len(validationErrors) == 0 {
+		return nil, nil
+	}
+	slices.SortStableFunc(validationErrors, func(a, b ValidationError) int {
+		return strings.Compare(a.Field, b.Field)
+	})
+
+	return validationErrors, nil
+}
+
+// readGenericConfig reads the config file at path into generic JSON values,
+// auto-detecting the format by extension. For YAML files it also returns the
+// top-level keys whose values define an anchor.
+func readGenericConfig(path string) (any, []string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".yaml", ".yml", ".json", ".toml":
+	default:
+		return nil, nil, fmt.Errorf("unsupported config file format: %s (supported: .yaml, .yml, .json, .toml)", ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var (
+		raw        any
+		anchorKeys []string
+	)
+
+	switch ext {
+	case ".yaml", ".yml":
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse YAML config: %w", err)
+		}
+		if err := doc.Decode(&raw); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse YAML config: %w", err)
+		}
+		anchorKeys = yamlAnchorKeys(&doc)
+	case ".json":
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse JSON config: %w", err)
+		}
+	case ".toml":
+		var table map[string]any
+		if err := toml.Unmarshal(data, &table); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse TOML config: %w", err)
+		}
+		raw = table
+	}
+
+	// Round-trip through JSON, so YAML and TOML values get the types the validator expects
+	normalized, err := json.Marshal(dropNulls(raw))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert config to JSON: %w", err)
+	}
+
+	instance, err := validator.UnmarshalJSON(bytes.NewReader(normalized))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert config to JSON: %w", err)
+	}
+
+	return instance, anchorKeys, nil
+}
+
+// yamlAnchorKeys returns the top-level keys of the YAML document whose values define an anchor.
+func yamlAnchorKeys(doc *yaml.Node) []string {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var keys []string
+
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i+1].Anchor != "" {
+			keys = append(keys, root.Content[i].Value)
+		}
+	}
+
+	return keys
+}
+
+// dropNulls removes null map values. The parsers leave such fields at their zero value,
+// so an empty section (e.g. "metrics:" in YAML) is treated as absent instead of as a type mismatch.
+func dropNulls(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			if item == nil {
+				delete(val, k)
+				continue
+			}
+			val[k] = dropNulls(item)
+		}
+	case []any:
+		for i, item := range val {
+			val[i] = dropNulls(item)
+		}
+	case []map[string]any:
+		for _, item := range val {
+			dropNulls(item)
+		}
+	}
+
+	return v
+}
+
+// collectValidationErrors flattens the leaf errors of a schema validation error.
+func collectValidationErrors(
+	err *validator.ValidationError, printer *message.Printer, result []ValidationError,
+) []ValidationError {
+	if len(err.Causes) > 0 {
+		for _, cause := range err.Causes {
+			result = collectValidationErrors(cause, printer, result)
+		}
+
+		return result
+	}
+
+	field := strings.Join(err.InstanceLocation, ".")
+
+	switch errKind := err.ErrorKind.(type) {
+	case *kind.AdditionalProperties:
+		for _, property := range errKind.Properties {
+			result = append(result, ValidationError{
+				Field:   joinField(field, property),
+				Message: "unknown field",
+			})
+		}
+	case *kind.Type:
+		result = append(result, ValidationError{
+			Field:    field,
+			Expected: strings.Join(errKind.Want, " or "),
+			Message:  fmt.Sprintf("expected %s, got %s", strings.Join(errKind.Want, " or "), errKind.Got),
+		})
+	default:
+		result = append(result, ValidationError{
+			Field:   field,
+			Message: err.ErrorKind.LocalizedString(printer),
+		})
+	}
+
+	return result
+}
+
+func joinField(parent, field string) string {
+	if parent == "" {
+		return field
+	}
+
+	return parent + "." + field
+}
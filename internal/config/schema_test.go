@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestValidateSchema_ExampleConfigs(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{
+		"../../config.example.yaml",
+		"../../config.example.json",
+		"../../config.example.toml",
+	} {
+		validationErrors, err := ValidateSchema(path)
+		require.NoError(t, err, path)
+		require.Empty(t, validationErrors, path)
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected []ValidationError
+	}{
+		{
+			name: "unknown field in YAML",
+			file: "config.yaml",
+			content: `
+downloader:
+  rpc_url: "http://localhost:8545"
+  retention_policy:
+    max_db_size: 1000
+`,
+			expected: []ValidationError{
+				{Field: "downloader.retention_policy.max_db_size", Message: "unknown field"},
+			},
+		},
+		{
+			name: "wrong type in JSON",
+			file: "config.json",
+			content: `{
+  "downloader": {"rpc_url": "http://localhost:8545", "chunk_size": "5000"},
+  "indexers": [{"name": 5}]
+}`,
+			expected: []ValidationError{
+				{Field: "downloader.chunk_size", Expected: "integer", Message: "expected integer, got string"},
+				{Field: "indexers.0.name", Expected: "string", Message: "expected string, got number"},
+			},
+		},
+		{
+			name: "unknown section in TOML",
+			file: "config.toml",
+			content: `
+[downloader]
+rpc_url = "http://localhost:8545"
+
+[metric]
+enabled = true
+`,
+			expected: []ValidationError{
+				{Field: "metric", Message: "unknown field"},
+			},
+		},
+		{
+			name: "YAML anchors and empty sections",
+			file: "config.yml",
+			content: `
+common_db: &common_db
+  journal_mode: WAL
+
+downloader:
+  rpc_url: "http://localhost:8545"
+  db:
+    <<: *common_db
+    path: "./data/downloader.sqlite"
+
+metrics:
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			validationErrors, err := ValidateSchema(writeConfigFile(t, tt.file, tt.content))
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, validationErrors)
+		})
+	}
+}
+
+func TestValidateSchema_InvalidFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := ValidateSchema(writeConfigFile(t, "config.yaml", "downloader: [unclosed"))
+	require.ErrorContains(t, err, "failed to parse YAML config")
+
+	_, err = ValidateSchema("missing.yaml")
+	require.ErrorContains(t, err, "failed to read config file")
+
+	_, err = ValidateSchema("config.txt")
+	require.ErrorContains(t, err, "unsupported config file format")
+}
+
+func TestLoadFromFile_SchemaViolation(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfigFile(t, "config.yaml", `
+downloader:
+  rpc_url: "http://localhost:8545"
+  chunk_sise: 1000
+`)
+
+	_, err := LoadFromFile(path)
+	require.ErrorContains(t, err, "config file does not match schema")
+	require.ErrorContains(t, err, "downloader.chunk_sise: unknown field")
+}
@@ -2,16 +2,24 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
-	_ "github.com/mattn/go-sqlite3"
 )
 
 const dbFolderPerm = 0755
 
+// ErrEncryptionNotSupported is returned when an encryption key is configured,
+// but the binary was built without SQLCipher support.
+var ErrEncryptionNotSupported = errors.New(
+	"database encryption requires a CGO build with the sqlcipher build tag (go build -tags sqlcipher)")
+
 // ensureDBFolder ensures the directory that contains dbPath exists.
 // Example dbPath: "./data/mytokenindexer.sqlite"
 func ensureDBFolder(dbPath string) error {
@@ -19,8 +27,27 @@ func ensureDBFolder(dbPath string) error {
 	return os.MkdirAll(dir, dbFolderPerm)
 }
 
+// NewDBFromConfig opens the database of the configured driver: SQLite by default, or PostgreSQL.
+func NewDBFromConfig(cfg config.DatabaseConfig) (*sql.DB, error) {
+	switch cfg.Driver {
+	case "", config.DBDriverSQLite:
+		return NewSQLiteDBFromConfig(cfg)
+	case config.DBDriverPostgres:
+		return NewPostgresDBFromConfig(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+}
+
 // NewSQLiteDBFromConfig creates a new SQLite DB with the given configuration.
+// Placeholders left in the path, such as {date} of a configuration without defaults applied, are expanded first.
 func NewSQLiteDBFromConfig(cfg config.DatabaseConfig) (*sql.DB, error) {
+	path, err := config.ExpandDBPath(cfg.Path, nil)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Path = path
+
 	if err := ensureDBFolder(cfg.Path); err != nil {
 		return nil, fmt.Errorf("failed to ensure DB folder: %w", err)
 	}
@@ -39,6 +66,11 @@ func NewSQLiteDBFromConfig(cfg config.DatabaseConfig) (*sql.DB, error) {
 		cfg.BusyTimeout,
 	)
 
+	connStr, err = withEncryptionKey(connStr, cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	db, err := sql.Open("sqlite3", connStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
@@ -61,9 +93,135 @@ func NewSQLiteDBFromConfig(cfg config.DatabaseConfig) (*sql.DB, error) {
 		}
 	}
 
+	if cfg.RunIntegrityCheckOnStartup {
+		if err := checkIntegrity(db, cfg.IntegrityCheckMode); err != nil {
+			if cfg.FailOnIntegrityError {
+				db.Close()
+				return nil, fmt.Errorf("database %s: %w", cfg.Path, err)
+			}
+
+			logger.GetDefaultLogger().Warnf("database %s: %v", cfg.Path, err)
+		}
+	}
+
+	return db, nil
+}
+
+// checkIntegrity checks the database for corruption with PRAGMA quick_check, or in "full" mode with
+// PRAGMA integrity_check and PRAGMA foreign_key_check. It returns an error listing the reported problems.
+func checkIntegrity(db *sql.DB, mode string) error {
+	check := "quick_check"
+	switch mode {
+	case "", config.IntegrityCheckQuick:
+	case config.IntegrityCheckFull:
+		check = "integrity_check"
+	default:
+		return fmt.Errorf("unknown integrity check mode %q", mode)
+	}
+
+	rows, err := db.Query("PRAGMA " + check)
+	if err != nil {
+		return fmt.Errorf("failed to run integrity check: %w", err)
+	}
+
+	problems, err := scanIntegrityResults(rows)
+	if err != nil {
+		return fmt.Errorf("failed to read integrity check results: %w", err)
+	}
+	if len(problems) != 1 || problems[0] != "ok" {
+		return fmt.Errorf("integrity check failed: %s", strings.Join(problems, "; "))
+	}
+
+	if mode != config.IntegrityCheckFull {
+		return nil
+	}
+
+	violations, err := foreignKeyViolations(db)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("foreign key check failed: %s", strings.Join(violations, "; "))
+	}
+
+	return nil
+}
+
+// scanIntegrityResults returns the lines reported by an integrity check, a single "ok" if it passed.
+func scanIntegrityResults(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	var results []string
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// foreignKeyViolations returns the rows reported by PRAGMA foreign_key_check, e.g.
+// "transfers rowid 7 references tokens".
+func foreignKeyViolations(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run foreign key check: %w", err)
+	}
+	defer rows.Close()
+
+	var violations []string
+	for rows.Next() {
+		var (
+			table, parent string
+			rowID         sql.NullInt64
+			fkID          int64
+		)
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return nil, fmt.Errorf("failed to read foreign key check results: %w", err)
+		}
+		violations = append(violations, fmt.Sprintf("%s rowid %d references %s", table, rowID.Int64, parent))
+	}
+
+	return violations, rows.Err()
+}
+
+// NewReadOnlySQLiteDBFromConfig opens the existing SQLite database of the given configuration read-only.
+// Its transactions never take the write lock, so in WAL mode long reads such as exports
+// run next to the writes of the read-write connection.
+func NewReadOnlySQLiteDBFromConfig(cfg config.DatabaseConfig) (*sql.DB, error) {
+	connStr, err := withEncryptionKey(
+		fmt.Sprintf("file:%s?mode=ro&_busy_timeout=%d", cfg.Path, cfg.BusyTimeout), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConnections)
+	db.SetMaxIdleConns(cfg.MaxIdleConnections)
+
 	return db, nil
 }
 
+// withEncryptionKey appends the encryption key of the configuration, if any, to the connection string.
+func withEncryptionKey(connStr string, cfg config.DatabaseConfig) (string, error) {
+	if cfg.EncryptionKey == "" {
+		return connStr, nil
+	}
+
+	if !encryptionSupported {
+		return "", ErrEncryptionNotSupported
+	}
+
+	return connStr + "&_pragma_key=" + url.QueryEscape(cfg.EncryptionKey), nil
+}
+
 // DBTotalSize returns the combined size of the SQLite main file + WAL + SHM.
 // If WAL/SHM do not exist, they are simply ignored.
 func DBTotalSize(dbPath string) (int64, error) {
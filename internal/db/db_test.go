@@ -4,7 +4,9 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -92,3 +94,134 @@ func TestDBTotalSize(t *testing.T) {
 		})
 	}
 }
+
+func TestNewReadOnlySQLiteDBFromConfig(t *testing.T) {
+	cfg := config.DatabaseConfig{Path: path.Join(t.TempDir(), "test.db")}
+	cfg.ApplyDefaults()
+
+	database, err := NewSQLiteDBFromConfig(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	_, err = database.Exec("CREATE TABLE events (id INTEGER PRIMARY KEY); INSERT INTO events (id) VALUES (1)")
+	require.NoError(t, err)
+
+	readOnly, err := NewReadOnlySQLiteDBFromConfig(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { readOnly.Close() })
+
+	var count int
+	require.NoError(t, readOnly.QueryRow("SELECT COUNT(*) FROM events").Scan(&count))
+	require.Equal(t, 1, count)
+
+	_, err = readOnly.Exec("INSERT INTO events (id) VALUES (2)")
+	require.ErrorContains(t, err, "readonly")
+}
+
+func TestNewSQLiteDBFromConfig_ExpandsPath(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg := config.DatabaseConfig{Path: path.Join(dir, "{date}", "test.db")}
+	cfg.ApplyDefaults()
+
+	database, err := NewSQLiteDBFromConfig(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+	require.NoError(t, database.Ping())
+
+	require.FileExists(t, path.Join(dir, time.Now().Format(time.DateOnly), "test.db"))
+
+	_, err = NewSQLiteDBFromConfig(config.DatabaseConfig{Path: path.Join(dir, "{indexer_name}.db")})
+	require.ErrorContains(t, err, "no value for {indexer_name}")
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	cfg := config.DatabaseConfig{Path: path.Join(t.TempDir(), "test.db")}
+	cfg.ApplyDefaults()
+
+	database, err := NewSQLiteDBFromConfig(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	_, err = database.Exec(`
+		CREATE TABLE tokens (id INTEGER PRIMARY KEY);
+		CREATE TABLE transfers (id INTEGER PRIMARY KEY, token_id INTEGER REFERENCES tokens(id), value INTEGER);
+		CREATE INDEX idx_transfers_value ON transfers(value);
+		INSERT INTO tokens (id) VALUES (1);
+		INSERT INTO transfers (id, token_id, value) VALUES (1, 1, 10), (2, 1, 20);
+	`)
+	require.NoError(t, err)
+
+	t.Run("valid database", func(t *testing.T) {
+		require.NoError(t, checkIntegrity(database, config.IntegrityCheckQuick))
+		require.NoError(t, checkIntegrity(database, config.IntegrityCheckFull))
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		require.ErrorContains(t, checkIntegrity(database, "thorough"), `unknown integrity check mode "thorough"`)
+	})
+
+	t.Run("foreign key violation", func(t *testing.T) {
+		// Foreign keys are not enforced by default, so the row is inserted
+		_, err := database.Exec("INSERT INTO transfers (id, token_id, value) VALUES (3, 2, 30)")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_, err := database.Exec("DELETE FROM transfers WHERE id = 3")
+			require.NoError(t, err)
+		})
+
+		// Only the full check verifies foreign keys
+		require.NoError(t, checkIntegrity(database, config.IntegrityCheckQuick))
+		require.ErrorContains(t, checkIntegrity(database, config.IntegrityCheckFull),
+			"foreign key check failed: transfers rowid 3 references tokens")
+	})
+}
+
+func TestNewSQLiteDBFromConfig_IntegrityCheck(t *testing.T) {
+	cfg := config.DatabaseConfig{
+		Path:                       path.Join(t.TempDir(), "test.db"),
+		RunIntegrityCheckOnStartup: true,
+		IntegrityCheckMode:         config.IntegrityCheckFull,
+	}
+	cfg.ApplyDefaults()
+
+	database, err := NewSQLiteDBFromConfig(cfg)
+	require.NoError(t, err)
+
+	// Corrupt the index by changing the column it is declared on, so it no longer matches its table
+	_, err = database.Exec(`
+		CREATE TABLE transfers (id INTEGER PRIMARY KEY, sender TEXT, recipient TEXT);
+		CREATE INDEX idx_transfers_sender ON transfers(sender);
+		INSERT INTO transfers (sender, recipient) VALUES ('alice', 'bob'), ('bob', 'carol');
+		PRAGMA writable_schema = ON;
+		UPDATE sqlite_master SET sql = 'CREATE INDEX idx_transfers_sender ON transfers(recipient)'
+		WHERE name = 'idx_transfers_sender';
+		PRAGMA writable_schema = OFF;
+	`)
+	require.NoError(t, err)
+	require.NoError(t, database.Close())
+
+	t.Run("warning", func(t *testing.T) {
+		database, err := NewSQLiteDBFromConfig(cfg)
+		require.NoError(t, err)
+		require.NoError(t, database.Close())
+	})
+
+	t.Run("fail on integrity error", func(t *testing.T) {
+		cfg := cfg
+		cfg.FailOnIntegrityError = true
+
+		_, err := NewSQLiteDBFromConfig(cfg)
+		require.ErrorContains(t, err, "integrity check failed")
+	})
+
+	t.Run("quick check skips indexes", func(t *testing.T) {
+		cfg := cfg
+		cfg.FailOnIntegrityError = true
+		cfg.IntegrityCheckMode = config.IntegrityCheckQuick
+
+		database, err := NewSQLiteDBFromConfig(cfg)
+		require.NoError(t, err)
+		require.NoError(t, database.Close())
+	})
+}
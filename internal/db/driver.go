@@ -0,0 +1,14 @@
+//go:build !sqlcipher
+
+package db
+
+import (
+	"github.com/mattn/go-sqlite3"
+)
+
+// encryptionSupported reports whether the linked SQLite driver supports database encryption.
+// The default driver does not, build with the sqlcipher tag to enable it.
+const encryptionSupported = false
+
+// sqliteConn is the driver connection type of the linked SQLite driver.
+type sqliteConn = sqlite3.SQLiteConn
@@ -0,0 +1,15 @@
+//go:build sqlcipher
+
+package db
+
+import (
+	// go-sqlcipher bundles its own SQLite build and registers itself as the "sqlite3"
+	// driver, so it replaces github.com/mattn/go-sqlite3 instead of being linked next to it.
+	sqlite3 "github.com/mutecomm/go-sqlcipher/v4"
+)
+
+// encryptionSupported reports whether the linked SQLite driver supports database encryption.
+const encryptionSupported = true
+
+// sqliteConn is the driver connection type of the linked SQLite driver.
+type sqliteConn = sqlite3.SQLiteConn
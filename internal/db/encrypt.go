@@ -0,0 +1,56 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// EncryptExistingDatabase copies the unencrypted SQLite database at sourcePath into a new
+// SQLCipher database at destPath, encrypted with key. The source database is left untouched,
+// so it can be removed once the encrypted copy has been verified.
+func EncryptExistingDatabase(sourcePath, destPath, key string) error {
+	if key == "" {
+		return errors.New("encryption key is required")
+	}
+
+	if !encryptionSupported {
+		return ErrEncryptionNotSupported
+	}
+
+	if _, err := os.Stat(sourcePath); err != nil {
+		return fmt.Errorf("failed to open source database: %w", err)
+	}
+
+	if _, err := os.Stat(destPath); err == nil {
+		return fmt.Errorf("destination database %s already exists", destPath)
+	}
+
+	if err := ensureDBFolder(destPath); err != nil {
+		return fmt.Errorf("failed to ensure DB folder: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+sourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to open source database: %w", err)
+	}
+	defer db.Close()
+
+	// The attached database is only visible on the connection that attached it
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec("ATTACH DATABASE ? AS encrypted KEY ?", destPath, key); err != nil {
+		return fmt.Errorf("failed to attach encrypted database: %w", err)
+	}
+
+	if _, err := db.Exec("SELECT sqlcipher_export('encrypted')"); err != nil {
+		return fmt.Errorf("failed to export to encrypted database: %w", err)
+	}
+
+	if _, err := db.Exec("DETACH DATABASE encrypted"); err != nil {
+		return fmt.Errorf("failed to detach encrypted database: %w", err)
+	}
+
+	return nil
+}
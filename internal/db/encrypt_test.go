@@ -0,0 +1,71 @@
+package db
+
+import (
+	"path"
+	"testing"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryption_NotSupported(t *testing.T) {
+	t.Parallel()
+
+	if encryptionSupported {
+		t.Skip("built with SQLCipher support")
+	}
+
+	dir := t.TempDir()
+
+	cfg := config.DatabaseConfig{Path: path.Join(dir, "encrypted.db"), EncryptionKey: "secret"}
+	cfg.ApplyDefaults()
+
+	_, err := NewSQLiteDBFromConfig(cfg)
+	require.ErrorIs(t, err, ErrEncryptionNotSupported)
+
+	err = EncryptExistingDatabase(path.Join(dir, "plain.db"), path.Join(dir, "encrypted.db"), "secret")
+	require.ErrorIs(t, err, ErrEncryptionNotSupported)
+}
+
+func TestEncryptExistingDatabase(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sourcePath := path.Join(dir, "plain.db")
+	destPath := path.Join(dir, "encrypted.db")
+
+	require.ErrorContains(t, EncryptExistingDatabase(sourcePath, destPath, ""), "encryption key is required")
+
+	if !encryptionSupported {
+		t.Skip("requires building with the sqlcipher tag")
+	}
+
+	plainCfg := config.DatabaseConfig{Path: sourcePath}
+	plainCfg.ApplyDefaults()
+
+	plainDB, err := NewSQLiteDBFromConfig(plainCfg)
+	require.NoError(t, err)
+	_, err = plainDB.Exec("CREATE TABLE events (id INTEGER PRIMARY KEY, data TEXT); INSERT INTO events (data) VALUES ('pii')")
+	require.NoError(t, err)
+	require.NoError(t, plainDB.Close())
+
+	require.NoError(t, EncryptExistingDatabase(sourcePath, destPath, "secret"))
+	require.ErrorContains(t, EncryptExistingDatabase(sourcePath, destPath, "secret"), "already exists")
+
+	encryptedCfg := config.DatabaseConfig{Path: destPath, EncryptionKey: "secret"}
+	encryptedCfg.ApplyDefaults()
+
+	encryptedDB, err := NewSQLiteDBFromConfig(encryptedCfg)
+	require.NoError(t, err)
+	defer encryptedDB.Close()
+
+	var data string
+	require.NoError(t, encryptedDB.QueryRow("SELECT data FROM events WHERE id = 1").Scan(&data))
+	require.Equal(t, "pii", data)
+
+	// Opening with a wrong key must fail
+	wrongCfg := config.DatabaseConfig{Path: destPath, EncryptionKey: "wrong"}
+	wrongCfg.ApplyDefaults()
+	_, err = NewSQLiteDBFromConfig(wrongCfg)
+	require.Error(t, err)
+}
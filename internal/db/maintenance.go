@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -13,6 +14,9 @@ import (
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
 )
 
+// MaintenanceTask is a database operation run on every maintenance run, such as compacting tables.
+type MaintenanceTask func(ctx context.Context) error
+
 type Maintenance interface {
 	// Start begins background maintenance if enabled.
 	Start(ctx context.Context) error
@@ -25,6 +29,9 @@ type Maintenance interface {
 	GetMetrics() MaintenanceMetrics
 	// RunMaintenance performs database maintenance operations (for manual invocation).
 	RunMaintenance(ctx context.Context) error
+	// RegisterTask registers a task run at the start of every maintenance run.
+	// Registering a task under the name of another task replaces it.
+	RegisterTask(name string, task MaintenanceTask)
 }
 
 // NoOpMaintenance is a no-operation implementation of the Maintenance interface.
@@ -45,6 +52,9 @@ func (m *NoOpMaintenance) RunMaintenance(ctx context.Context) error {
 	return nil
 }
 
+// RegisterTask is a no-op, tasks are never run.
+func (m *NoOpMaintenance) RegisterTask(name string, task MaintenanceTask) {}
+
 // AcquireOperationLock is a no-op that returns an empty unlock function.
 func (m *NoOpMaintenance) AcquireOperationLock() func() {
 	return func() {}
@@ -64,6 +74,9 @@ type MaintenanceCoordinator struct {
 	dbPath string
 	log    *logger.Logger
 
+	// chainID labels the maintenance metrics
+	chainID uint64
+
 	// RWMutex: readers = operations, writer = maintenance
 	// Operations acquire read lock (shared, non-blocking with other operations)
 	// Maintenance acquires write lock (exclusive, waits for all operations to complete)
@@ -74,6 +87,10 @@ type MaintenanceCoordinator struct {
 	maintenanceCancel context.CancelFunc
 	maintenanceWg     sync.WaitGroup
 
+	// Tasks run at the start of every maintenance run, in registration order
+	tasksLock sync.Mutex
+	tasks     []namedTask
+
 	// Metrics
 	metricsLock         sync.Mutex
 	lastMaintenanceTime time.Time
@@ -81,8 +98,15 @@ type MaintenanceCoordinator struct {
 	lastMaintenanceErr  error
 }
 
-// NewMaintenanceCoordinator creates a new maintenance coordinator.
+// namedTask is a maintenance task and the name it was registered under.
+type namedTask struct {
+	name string
+	run  MaintenanceTask
+}
+
+// NewMaintenanceCoordinator creates a new maintenance coordinator, whose metrics are labelled with chainID.
 func NewMaintenanceCoordinator(
+	chainID uint64,
 	dbPath string,
 	db *sql.DB,
 	cfg *config.MaintenanceConfig,
@@ -92,21 +116,23 @@ func NewMaintenanceCoordinator(
 		return &NoOpMaintenance{}
 	}
 
-	return newMaintenanceCoordinator(dbPath, db, *cfg, log)
+	return newMaintenanceCoordinator(chainID, dbPath, db, *cfg, log)
 }
 
 // newMaintenanceCoordinator is an internal constructor for MaintenanceCoordinator.
 func newMaintenanceCoordinator(
+	chainID uint64,
 	dbPath string,
 	db *sql.DB,
 	cfg config.MaintenanceConfig,
 	log *logger.Logger,
 ) *MaintenanceCoordinator {
 	return &MaintenanceCoordinator{
-		db:     db,
-		config: cfg,
-		dbPath: dbPath,
-		log:    log.WithComponent("db-maintenance"),
+		db:      db,
+		config:  cfg,
+		dbPath:  dbPath,
+		log:     log.WithComponent("db-maintenance"),
+		chainID: chainID,
 	}
 }
 
@@ -172,14 +198,34 @@ func (m *MaintenanceCoordinator) maintenanceWorker(checkInterval time.Duration)
 	}
 }
 
+// RegisterTask registers a task run at the start of every maintenance run.
+// Registering a task under the name of another task replaces it in place.
+func (m *MaintenanceCoordinator) RegisterTask(name string, task MaintenanceTask) {
+	m.tasksLock.Lock()
+	defer m.tasksLock.Unlock()
+
+	for i := range m.tasks {
+		if m.tasks[i].name == name {
+			m.tasks[i].run = task
+			return
+		}
+	}
+
+	m.tasks = append(m.tasks, namedTask{name: name, run: task})
+}
+
 // RunMaintenance performs database maintenance operations.
-// This acquires an exclusive lock, blocking all operations until complete.
+// Registered tasks run first, alongside normal operations, so they can acquire the operation lock.
+// The rest acquires an exclusive lock, blocking all operations until complete.
 func (m *MaintenanceCoordinator) RunMaintenance(ctx context.Context) error {
 	m.log.Info("Starting database maintenance")
 	start := time.Now().UTC()
 
 	// Track maintenance run
-	MaintenanceRunsInc()
+	MaintenanceRunsInc(m.chainID)
+
+	// Step 0: registered tasks, before VACUUM reclaims the space they free up
+	tasksErr := m.runTasks(ctx)
 
 	// Acquire write lock - blocks new operations and waits for ongoing ones to complete
 	m.opLock.Lock()
@@ -190,7 +236,7 @@ func (m *MaintenanceCoordinator) RunMaintenance(ctx context.Context) error {
 		return ctx.Err()
 	}
 
-	var maintenanceErr error
+	maintenanceErr := tasksErr
 
 	initialDBSize, err := DBTotalSize(m.dbPath)
 	if err != nil {
@@ -200,7 +246,9 @@ func (m *MaintenanceCoordinator) RunMaintenance(ctx context.Context) error {
 	// Step 1: WAL Checkpoint
 	if err := m.walCheckpoint(); err != nil {
 		m.log.Errorf("WAL checkpoint failed: %v", err)
-		maintenanceErr = fmt.Errorf("WAL checkpoint failed: %w", err)
+		if maintenanceErr == nil {
+			maintenanceErr = fmt.Errorf("WAL checkpoint failed: %w", err)
+		}
 	}
 
 	// Step 2: VACUUM (if not in WAL mode or if conditions allow)
@@ -226,29 +274,50 @@ func (m *MaintenanceCoordinator) RunMaintenance(ctx context.Context) error {
 	m.metricsLock.Unlock()
 
 	// Update Prometheus metrics
-	MaintenanceDurationLog(duration)
-	MaintenanceLastRunLog()
+	MaintenanceDurationLog(m.chainID, duration)
+	MaintenanceLastRunLog(m.chainID)
 
 	if maintenanceErr != nil {
-		MaintenanceErrorInc()
+		MaintenanceErrorInc(m.chainID)
 		m.log.Warnf("Maintenance completed with errors in %v: %v", duration, maintenanceErr)
 		return maintenanceErr
 	}
 
-	MaintenanceSuccessInc()
+	MaintenanceSuccessInc(m.chainID)
 	m.log.Infof("Maintenance completed successfully in %v.", duration)
 
 	if initialDBSize > finalDBSize {
 		spaceReclaimed := uint64(initialDBSize - finalDBSize)
-		MaintenanceSpaceReclaimedLog(spaceReclaimed)
+		MaintenanceSpaceReclaimedLog(m.chainID, spaceReclaimed)
 		m.log.Infof("Maintenance cleaned: %d MB", common.BytesToMB(spaceReclaimed))
 	}
 
-	DBSizeLog(finalDBSize)
+	DBSizeLog(m.chainID, finalDBSize)
 
 	return nil
 }
 
+// runTasks runs the registered tasks in registration order. A failing task does not stop the others,
+// the error of the first failing task is returned.
+func (m *MaintenanceCoordinator) runTasks(ctx context.Context) error {
+	m.tasksLock.Lock()
+	tasks := slices.Clone(m.tasks)
+	m.tasksLock.Unlock()
+
+	var firstErr error
+	for _, task := range tasks {
+		m.log.Debugf("Running maintenance task: %s", task.name)
+		if err := task.run(ctx); err != nil {
+			m.log.Errorf("Maintenance task %s failed: %v", task.name, err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("maintenance task %s failed: %w", task.name, err)
+			}
+		}
+	}
+
+	return firstErr
+}
+
 // walCheckpoint performs a WAL checkpoint operation.
 func (m *MaintenanceCoordinator) walCheckpoint() error {
 	isWAL, err := m.isWALMode()
@@ -274,7 +343,7 @@ func (m *MaintenanceCoordinator) walCheckpoint() error {
 		m.config.WALCheckpointMode, busyCount, logFrames, checkpointedFrames)
 
 	// Track checkpoint
-	WALCheckpointInc(strings.ToLower(m.config.WALCheckpointMode))
+	WALCheckpointInc(m.chainID, strings.ToLower(m.config.WALCheckpointMode))
 
 	if busyCount > 0 {
 		m.log.Warnf("WAL checkpoint encountered %d busy pages (some pages not checkpointed)", busyCount)
@@ -301,7 +370,7 @@ func (m *MaintenanceCoordinator) vacuum() error {
 	}
 
 	// Track vacuum
-	VacuumRunsInc()
+	VacuumRunsInc(m.chainID)
 	m.log.Info("VACUUM completed successfully")
 	return nil
 }
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"os"
 	"path"
 	"sync"
@@ -61,7 +62,7 @@ func TestMaintenanceCoordinator_NewMaintenanceCoordinator(t *testing.T) {
 		WALCheckpointMode: "TRUNCATE",
 	}
 
-	coordinator := newMaintenanceCoordinator(dbPath, db, cfg, log)
+	coordinator := newMaintenanceCoordinator(0, dbPath, db, cfg, log)
 	require.NotNil(t, coordinator)
 	require.NotNil(t, coordinator.db)
 	require.Equal(t, "TRUNCATE", coordinator.config.WALCheckpointMode)
@@ -93,7 +94,7 @@ func TestMaintenanceCoordinator_RunMaintenance(t *testing.T) {
 		WALCheckpointMode: "TRUNCATE",
 	}
 
-	coordinator := newMaintenanceCoordinator(dbPath, db, cfg, log)
+	coordinator := newMaintenanceCoordinator(0, dbPath, db, cfg, log)
 
 	// Run maintenance manually
 	err = coordinator.RunMaintenance(context.Background())
@@ -106,6 +107,46 @@ func TestMaintenanceCoordinator_RunMaintenance(t *testing.T) {
 	require.NoError(t, metrics.LastMaintenanceError)
 }
 
+func TestMaintenanceCoordinator_RunMaintenanceRunsTasks(t *testing.T) {
+	t.Parallel()
+
+	db, dbPath := setupMaintenanceTestDB(t)
+	defer db.Close()
+
+	log, err := logger.NewLogger("info", true)
+	require.NoError(t, err)
+
+	coordinator := newMaintenanceCoordinator(0, dbPath, db, config.MaintenanceConfig{WALCheckpointMode: "TRUNCATE"}, log)
+
+	var calls []string
+	task := func(name string, err error) MaintenanceTask {
+		return func(ctx context.Context) error {
+			// Tasks run alongside normal operations, so they can acquire the operation lock
+			unlock := coordinator.AcquireOperationLock()
+			defer unlock()
+
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	coordinator.RegisterTask("compaction", task("compaction", errors.New("table locked")))
+	coordinator.RegisterTask("cleanup", task("cleanup", nil))
+
+	// A failing task does not keep the other tasks and the rest of the maintenance from running
+	err = coordinator.RunMaintenance(context.Background())
+	require.ErrorContains(t, err, "maintenance task compaction failed: table locked")
+	require.Equal(t, []string{"compaction", "cleanup"}, calls)
+	require.Equal(t, err, coordinator.GetMetrics().LastMaintenanceError)
+
+	// Registering a task under the same name replaces it in place
+	calls = nil
+	coordinator.RegisterTask("compaction", task("replaced", nil))
+
+	require.NoError(t, coordinator.RunMaintenance(context.Background()))
+	require.Equal(t, []string{"replaced", "cleanup"}, calls)
+}
+
 func TestMaintenanceCoordinator_WALCheckpoint(t *testing.T) {
 	t.Parallel()
 
@@ -132,7 +173,7 @@ func TestMaintenanceCoordinator_WALCheckpoint(t *testing.T) {
 		WALCheckpointMode: "TRUNCATE",
 	}
 
-	coordinator := newMaintenanceCoordinator(dbPath, db, cfg, log)
+	coordinator := newMaintenanceCoordinator(0, dbPath, db, cfg, log)
 	err = coordinator.walCheckpoint()
 	require.NoError(t, err)
 
@@ -160,7 +201,7 @@ func TestMaintenanceCoordinator_OperationLock(t *testing.T) {
 		WALCheckpointMode: "TRUNCATE",
 	}
 
-	coordinator := newMaintenanceCoordinator(dbPath, db, cfg, log)
+	coordinator := newMaintenanceCoordinator(0, dbPath, db, cfg, log)
 
 	// Test that multiple operations can acquire read lock concurrently
 	var wg sync.WaitGroup
@@ -193,7 +234,7 @@ func TestMaintenanceCoordinator_MaintenanceBlocksOperations(t *testing.T) {
 		WALCheckpointMode: "PASSIVE", // Use faster mode for testing
 	}
 
-	coordinator := newMaintenanceCoordinator(dbPath, db, cfg, log)
+	coordinator := newMaintenanceCoordinator(0, dbPath, db, cfg, log)
 
 	var operationsBlocked atomic.Bool
 	var maintenanceStarted atomic.Bool
@@ -259,7 +300,7 @@ func TestMaintenanceCoordinator_BackgroundMaintenance(t *testing.T) {
 		WALCheckpointMode: "PASSIVE",
 	}
 
-	coordinator := newMaintenanceCoordinator(dbPath, db, cfg, log)
+	coordinator := newMaintenanceCoordinator(0, dbPath, db, cfg, log)
 
 	// Start background maintenance
 	err = coordinator.Start(t.Context())
@@ -305,7 +346,7 @@ func TestMaintenanceCoordinator_StartupMaintenance(t *testing.T) {
 		WALCheckpointMode: "TRUNCATE",
 	}
 
-	coordinator := newMaintenanceCoordinator(dbPath, db, cfg, log)
+	coordinator := newMaintenanceCoordinator(0, dbPath, db, cfg, log)
 
 	// Start should run maintenance immediately
 	err = coordinator.Start(t.Context())
@@ -336,7 +377,7 @@ func TestMaintenanceCoordinator_DisabledMaintenance(t *testing.T) {
 		WALCheckpointMode: "TRUNCATE",
 	}
 
-	coordinator := newMaintenanceCoordinator(dbPath, db, cfg, log)
+	coordinator := newMaintenanceCoordinator(0, dbPath, db, cfg, log)
 
 	err = coordinator.Start(t.Context())
 	require.NoError(t, err)
@@ -365,7 +406,7 @@ func TestMaintenanceCoordinator_ContextCancellation(t *testing.T) {
 		WALCheckpointMode: "TRUNCATE",
 	}
 
-	coordinator := newMaintenanceCoordinator(dbPath, db, config, log)
+	coordinator := newMaintenanceCoordinator(0, dbPath, db, config, log)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately
@@ -389,7 +430,7 @@ func TestMaintenanceCoordinator_ConcurrentOperationsDuringMaintenance(t *testing
 		WALCheckpointMode: "PASSIVE",
 	}
 
-	coordinator := newMaintenanceCoordinator(dbPath, db, cfg, log)
+	coordinator := newMaintenanceCoordinator(0, dbPath, db, cfg, log)
 
 	var wg sync.WaitGroup
 	const numOperations = 50
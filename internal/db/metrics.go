@@ -3,17 +3,19 @@ package db
 import (
 	"time"
 
+	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 var (
 	// Maintenance metrics
-	maintenanceRuns = promauto.NewCounter(
+	maintenanceRuns = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "chainindexor_maintenance_runs_total",
 			Help: "Total number of maintenance operations",
 		},
+		[]string{"chain_id"},
 	)
 
 	maintenanceOutcomes = promauto.NewCounterVec(
@@ -21,29 +23,32 @@ var (
 			Name: "chainindexor_maintenance_outcomes_total",
 			Help: "Total number of maintenance operations by outcome",
 		},
-		[]string{"status"},
+		[]string{"chain_id", "status"},
 	)
 
-	maintenanceDuration = promauto.NewHistogram(
+	maintenanceDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "chainindexor_maintenance_duration_seconds",
 			Help:    "Duration of maintenance operations",
 			Buckets: prometheus.DefBuckets,
 		},
+		[]string{"chain_id"},
 	)
 
-	maintenanceLastRun = promauto.NewGauge(
+	maintenanceLastRun = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "chainindexor_maintenance_last_run_timestamp",
 			Help: "Unix timestamp of last maintenance run",
 		},
+		[]string{"chain_id"},
 	)
 
-	maintenanceSpaceReclaimed = promauto.NewGauge(
+	maintenanceSpaceReclaimed = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "chainindexor_maintenance_space_reclaimed_bytes",
 			Help: "Bytes reclaimed by last maintenance operation",
 		},
+		[]string{"chain_id"},
 	)
 
 	walCheckpoints = promauto.NewCounterVec(
@@ -51,14 +56,15 @@ var (
 			Name: "chainindexor_wal_checkpoint_total",
 			Help: "Total number of WAL checkpoint operations",
 		},
-		[]string{"mode"},
+		[]string{"chain_id", "mode"},
 	)
 
-	vacuumRuns = promauto.NewCounter(
+	vacuumRuns = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "chainindexor_vacuum_total",
 			Help: "Total number of VACUUM operations",
 		},
+		[]string{"chain_id"},
 	)
 
 	dbSize = promauto.NewGaugeVec(
@@ -66,42 +72,42 @@ var (
 			Name: "chainindexor_db_size_bytes",
 			Help: "Database file size in bytes",
 		},
-		[]string{"type"},
+		[]string{"chain_id", "type"},
 	)
 )
 
-func MaintenanceRunsInc() {
-	maintenanceRuns.Inc()
+func MaintenanceRunsInc(chainID uint64) {
+	maintenanceRuns.WithLabelValues(metrics.ChainLabel(chainID)).Inc()
 }
 
-func MaintenanceDurationLog(duration time.Duration) {
-	maintenanceDuration.Observe(duration.Seconds())
+func MaintenanceDurationLog(chainID uint64, duration time.Duration) {
+	maintenanceDuration.WithLabelValues(metrics.ChainLabel(chainID)).Observe(duration.Seconds())
 }
 
-func MaintenanceLastRunLog() {
-	maintenanceLastRun.Set(float64(time.Now().UTC().Unix()))
+func MaintenanceLastRunLog(chainID uint64) {
+	maintenanceLastRun.WithLabelValues(metrics.ChainLabel(chainID)).Set(float64(time.Now().UTC().Unix()))
 }
 
-func MaintenanceErrorInc() {
-	maintenanceOutcomes.WithLabelValues("error").Inc()
+func MaintenanceErrorInc(chainID uint64) {
+	maintenanceOutcomes.WithLabelValues(metrics.ChainLabel(chainID), "error").Inc()
 }
 
-func MaintenanceSuccessInc() {
-	maintenanceOutcomes.WithLabelValues("success").Inc()
+func MaintenanceSuccessInc(chainID uint64) {
+	maintenanceOutcomes.WithLabelValues(metrics.ChainLabel(chainID), "success").Inc()
 }
 
-func MaintenanceSpaceReclaimedLog(bytesReclaimed uint64) {
-	maintenanceSpaceReclaimed.Set(float64(bytesReclaimed))
+func MaintenanceSpaceReclaimedLog(chainID uint64, bytesReclaimed uint64) {
+	maintenanceSpaceReclaimed.WithLabelValues(metrics.ChainLabel(chainID)).Set(float64(bytesReclaimed))
 }
 
-func WALCheckpointInc(mode string) {
-	walCheckpoints.WithLabelValues(mode).Inc()
+func WALCheckpointInc(chainID uint64, mode string) {
+	walCheckpoints.WithLabelValues(metrics.ChainLabel(chainID), mode).Inc()
 }
 
-func VacuumRunsInc() {
-	vacuumRuns.Inc()
+func VacuumRunsInc(chainID uint64) {
+	vacuumRuns.WithLabelValues(metrics.ChainLabel(chainID)).Inc()
 }
 
-func DBSizeLog(sizeBytes int64) {
-	dbSize.WithLabelValues("total").Set(float64(sizeBytes))
+func DBSizeLog(chainID uint64, sizeBytes int64) {
+	dbSize.WithLabelValues(metrics.ChainLabel(chainID), "total").Set(float64(sizeBytes))
 }
@@ -7,7 +7,6 @@ import (
 
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
-	_ "github.com/mattn/go-sqlite3"
 	migrate "github.com/rubenv/sql-migrate"
 )
 
@@ -16,6 +15,10 @@ const (
 	dbPrefixReplacer    = "/*dbprefix*/"
 	NoLimitMigrations   = 0 // indicate that there is no limit on the number of migrations to run
 	migrationDirections = 2
+	downMarker          = "-- +migrate Down"
+
+	// migrationsTable is the table sql-migrate records the applied migrations in
+	migrationsTable = "gorp_migrations"
 )
 
 type Migration struct {
@@ -28,7 +31,7 @@ type Migration struct {
 // the database updated with the latest changes in either direction,
 // up or down.
 func RunMigrations(dbConfig config.DatabaseConfig, migrations []Migration) error {
-	db, err := NewSQLiteDBFromConfig(dbConfig)
+	db, err := NewDBFromConfig(dbConfig)
 	if err != nil {
 		return fmt.Errorf("error creating DB %w", err)
 	}
@@ -42,6 +45,27 @@ func RunMigrations(dbConfig config.DatabaseConfig, migrations []Migration) error
 	return runMigrationsDB(logger.GetDefaultLogger(), db, migrations)
 }
 
+// RollbackMigrations reverts the last `steps` applied migrations by executing
+// their Down sections in reverse order of application.
+func RollbackMigrations(dbConfig config.DatabaseConfig, migrations []Migration, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("invalid rollback steps %d: must be greater than 0", steps)
+	}
+
+	db, err := NewDBFromConfig(dbConfig)
+	if err != nil {
+		return fmt.Errorf("error creating DB %w", err)
+	}
+	defer func() {
+		err := db.Close()
+		if err != nil {
+			logger.GetDefaultLogger().Errorf("error closing DB: %v", err)
+		}
+	}()
+
+	return runMigrationsDBExtended(logger.GetDefaultLogger(), db, migrations, migrate.Down, steps)
+}
+
 func runMigrationsDB(logger *logger.Logger, db *sql.DB, migrationsParam []Migration) error {
 	return runMigrationsDBExtended(logger, db, migrationsParam, migrate.Up, NoLimitMigrations)
 }
@@ -62,29 +86,11 @@ func runMigrationsDBExtended(logger *logger.Logger,
 	}
 
 	for _, m := range fullmigrations {
-		prefixed := strings.ReplaceAll(m.SQL, dbPrefixReplacer, m.Prefix)
-		splitted := strings.Split(prefixed, UpDownSeparator)
-
-		if len(splitted) < migrationDirections {
-			return fmt.Errorf("migration %s missing '-- +migrate Up' separator", m.ID)
-		}
-
-		// splitted[0] = Down section (may include "-- +migrate Down" marker)
-		// splitted[1] = Up section
-
-		downSQL := splitted[0]
-		upSQL := splitted[1]
-
-		// Clean up Down section - remove the Down marker if present
-		downMarker := "-- +migrate Down"
-		if idx := strings.Index(downSQL, downMarker); idx != -1 {
-			downSQL = strings.TrimSpace(downSQL[idx+len(downMarker):])
-		} else {
-			downSQL = strings.TrimSpace(downSQL)
+		upSQL, downSQL, err := splitMigration(m)
+		if err != nil {
+			return err
 		}
 
-		upSQL = strings.TrimSpace(upSQL)
-
 		migs.Migrations = append(migs.Migrations, &migrate.Migration{
 			Id:   m.Prefix + m.ID,
 			Up:   []string{upSQL},
@@ -100,7 +106,7 @@ func runMigrationsDBExtended(logger *logger.Logger,
 	logger.Debugf("running migrations: (max %d/%d) migrations: %s", maxMigrations,
 		len(migs.Migrations),
 		listMigrations.String())
-	nMigrations, err := migrate.ExecMax(db, "sqlite3", migs, dir, maxMigrations)
+	nMigrations, err := migrate.ExecMax(db, dialect(db), migs, dir, maxMigrations)
 	if err != nil {
 		return fmt.Errorf("error executing migration (max %d/%d) migrations: %s . Err: %w",
 			maxMigrations, len(migs.Migrations), listMigrations.String(), err)
@@ -109,3 +115,162 @@ func runMigrationsDBExtended(logger *logger.Logger,
 	logger.Infof("successfully ran %d migrations from migrations: %s", nMigrations, listMigrations.String())
 	return nil
 }
+
+// splitMigration returns the Up and Down sections of a migration, with the table prefix applied.
+func splitMigration(m Migration) (upSQL, downSQL string, err error) {
+	prefixed := strings.ReplaceAll(m.SQL, dbPrefixReplacer, m.Prefix)
+	splitted := strings.Split(prefixed, UpDownSeparator)
+
+	if len(splitted) < migrationDirections {
+		return "", "", fmt.Errorf("migration %s missing '-- +migrate Up' separator", m.ID)
+	}
+
+	// splitted[0] = Down section (may include "-- +migrate Down" marker)
+	// splitted[1] = Up section
+
+	downSQL = splitted[0]
+	upSQL = splitted[1]
+
+	// Clean up Down section - remove the Down marker if present
+	if idx := strings.Index(downSQL, downMarker); idx != -1 {
+		downSQL = strings.TrimSpace(downSQL[idx+len(downMarker):])
+	} else {
+		downSQL = strings.TrimSpace(downSQL)
+	}
+
+	return strings.TrimSpace(upSQL), downSQL, nil
+}
+
+// MigrationVersion returns the number of migrations applied to the database.
+// The applied migrations must be the first migrations of the given ordered list.
+func MigrationVersion(db *sql.DB, migrations []Migration) (int, error) {
+	// Reading the records creates the migrations table if it does not exist yet
+	records, err := migrate.GetMigrationRecords(db, dialect(db))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	applied := make([]string, 0, len(records))
+	for _, record := range records {
+		applied = append(applied, record.Id)
+	}
+
+	return migrationVersion(applied, migrations)
+}
+
+// RollbackTo reverts the applied migrations newer than targetVersion by executing their Down
+// sections in reverse order of application. The version of a migration is its 1-based position
+// in the ordered list, and version 0 reverts every migration.
+// All migrations are reverted in a single transaction, so a failing Down section leaves the
+// database untouched. The migrations table is verified before and after the rollback.
+func RollbackTo(db *sql.DB, migrations []Migration, targetVersion int) (err error) {
+	// Make sure the migrations table exists, so it can be read inside the transaction
+	if _, err := MigrationVersion(db, migrations); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				logger.GetDefaultLogger().Errorf("failed to rollback transaction: %v", rbErr)
+			}
+		}
+	}()
+
+	version, err := migrationVersionTx(tx, migrations)
+	if err != nil {
+		return err
+	}
+
+	if targetVersion < 0 || targetVersion > version {
+		return fmt.Errorf("invalid target version %d: must be between 0 and the current version %d",
+			targetVersion, version)
+	}
+
+	for i := version - 1; i >= targetVersion; i-- {
+		m := migrations[i]
+
+		_, downSQL, err := splitMigration(m)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(downSQL); err != nil {
+			return fmt.Errorf("failed to revert migration %s: %w", m.ID, err)
+		}
+
+		if _, err := tx.Exec("DELETE FROM "+migrationsTable+" WHERE id = ?", m.Prefix+m.ID); err != nil {
+			return fmt.Errorf("failed to remove record of migration %s: %w", m.ID, err)
+		}
+	}
+
+	version, err = migrationVersionTx(tx, migrations)
+	if err != nil {
+		return err
+	}
+
+	if version != targetVersion {
+		return fmt.Errorf("migration version is %d after rollback, expected %d", version, targetVersion)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logger.GetDefaultLogger().Infof("successfully rolled back migrations to version %d", targetVersion)
+
+	return nil
+}
+
+// migrationVersionTx returns the number of migrations applied to the database, read within the transaction.
+func migrationVersionTx(tx *sql.Tx, migrations []Migration) (int, error) {
+	rows, err := tx.Query("SELECT id FROM " + migrationsTable + " ORDER BY id")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var applied []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return 0, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied = append(applied, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate applied migrations: %w", err)
+	}
+
+	return migrationVersion(applied, migrations)
+}
+
+// migrationVersion returns the number of applied migrations, verifying they are the first
+// migrations of the ordered list.
+func migrationVersion(applied []string, migrations []Migration) (int, error) {
+	if len(applied) > len(migrations) {
+		return 0, fmt.Errorf("database has %d applied migrations, only %d are known", len(applied), len(migrations))
+	}
+
+	for i, id := range applied {
+		if expected := migrations[i].Prefix + migrations[i].ID; id != expected {
+			return 0, fmt.Errorf("unexpected applied migration %s at version %d, expected %s", id, i+1, expected)
+		}
+	}
+
+	return len(applied), nil
+}
+
+// dialect returns the sql-migrate dialect of the database.
+func dialect(db *sql.DB) string {
+	if IsPostgres(db) {
+		return "postgres"
+	}
+
+	return "sqlite3"
+}
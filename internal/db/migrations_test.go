@@ -0,0 +1,270 @@
+package db
+
+import (
+	"path"
+	"testing"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stretchr/testify/require"
+)
+
+var testMigrations = []Migration{
+	{
+		ID: "001_first.sql",
+		SQL: `-- +migrate Down
+DROP TABLE IF EXISTS first;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS first (id INTEGER PRIMARY KEY);`,
+	},
+	{
+		ID: "002_second.sql",
+		SQL: `-- +migrate Down
+DROP TABLE IF EXISTS second;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS second (id INTEGER PRIMARY KEY);`,
+	},
+	{
+		ID: "003_third.sql",
+		SQL: `-- +migrate Down
+DROP TABLE IF EXISTS third;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS third (id INTEGER PRIMARY KEY);`,
+	},
+}
+
+// applyAndRollbackMigrations creates a fresh database, applies the first `apply`
+// test migrations, rolls back `rollback` of them and returns the applied migration IDs.
+func applyAndRollbackMigrations(t *testing.T, apply, rollback int) (config.DatabaseConfig, []string) {
+	t.Helper()
+
+	dbConfig := config.DatabaseConfig{Path: path.Join(t.TempDir(), "migrations_test.db")}
+	dbConfig.ApplyDefaults()
+
+	db, err := NewSQLiteDBFromConfig(dbConfig)
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, runMigrationsDBExtended(logger.GetDefaultLogger(), db, testMigrations, migrate.Up, apply))
+
+	if rollback > 0 {
+		require.NoError(t, RollbackMigrations(dbConfig, testMigrations, rollback))
+	}
+
+	records, err := migrate.GetMigrationRecords(db, "sqlite3")
+	require.NoError(t, err)
+
+	applied := make([]string, 0, len(records))
+	for _, record := range records {
+		applied = append(applied, record.Id)
+	}
+
+	return dbConfig, applied
+}
+
+func tableExists(t *testing.T, dbConfig config.DatabaseConfig, table string) bool {
+	t.Helper()
+
+	db, err := NewSQLiteDBFromConfig(dbConfig)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&count)
+	require.NoError(t, err)
+
+	return count > 0
+}
+
+func TestRollbackMigrations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		apply           int
+		rollback        int
+		expectedApplied []string
+	}{
+		{
+			name:            "no rollback",
+			apply:           3,
+			rollback:        0,
+			expectedApplied: []string{"001_first.sql", "002_second.sql", "003_third.sql"},
+		},
+		{
+			name:            "rollback last migration",
+			apply:           3,
+			rollback:        1,
+			expectedApplied: []string{"001_first.sql", "002_second.sql"},
+		},
+		{
+			name:            "rollback after partial apply",
+			apply:           2,
+			rollback:        1,
+			expectedApplied: []string{"001_first.sql"},
+		},
+		{
+			name:            "rollback everything",
+			apply:           3,
+			rollback:        3,
+			expectedApplied: []string{},
+		},
+		{
+			name:            "rollback more than applied",
+			apply:           1,
+			rollback:        5,
+			expectedApplied: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dbConfig, applied := applyAndRollbackMigrations(t, tt.apply, tt.rollback)
+			require.Equal(t, tt.expectedApplied, applied)
+
+			tables := []string{"first", "second", "third"}
+			for i, table := range tables {
+				require.Equal(t, i < len(tt.expectedApplied), tableExists(t, dbConfig, table),
+					"unexpected existence of table %s", table)
+			}
+		})
+	}
+}
+
+func TestRollbackMigrations_InvalidSteps(t *testing.T) {
+	t.Parallel()
+
+	dbConfig := config.DatabaseConfig{Path: path.Join(t.TempDir(), "migrations_test.db")}
+	dbConfig.ApplyDefaults()
+
+	err := RollbackMigrations(dbConfig, testMigrations, 0)
+	require.ErrorContains(t, err, "must be greater than 0")
+}
+
+func TestRollbackTo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		apply           int
+		targetVersion   int
+		expectedApplied []string
+		expectedErr     string
+	}{
+		{
+			name:            "rollback last migration",
+			apply:           3,
+			targetVersion:   2,
+			expectedApplied: []string{"001_first.sql", "002_second.sql"},
+		},
+		{
+			name:            "rollback everything",
+			apply:           3,
+			targetVersion:   0,
+			expectedApplied: []string{},
+		},
+		{
+			name:            "target is current version",
+			apply:           2,
+			targetVersion:   2,
+			expectedApplied: []string{"001_first.sql", "002_second.sql"},
+		},
+		{
+			name:            "target newer than current version",
+			apply:           1,
+			targetVersion:   2,
+			expectedApplied: []string{"001_first.sql"},
+			expectedErr:     "invalid target version 2",
+		},
+		{
+			name:            "negative target",
+			apply:           1,
+			targetVersion:   -1,
+			expectedApplied: []string{"001_first.sql"},
+			expectedErr:     "invalid target version -1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dbConfig, _ := applyAndRollbackMigrations(t, tt.apply, 0)
+
+			db, err := NewSQLiteDBFromConfig(dbConfig)
+			require.NoError(t, err)
+			defer db.Close()
+
+			err = RollbackTo(db, testMigrations, tt.targetVersion)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			version, err := MigrationVersion(db, testMigrations)
+			require.NoError(t, err)
+			require.Equal(t, len(tt.expectedApplied), version)
+
+			tables := []string{"first", "second", "third"}
+			for i, table := range tables {
+				require.Equal(t, i < len(tt.expectedApplied), tableExists(t, dbConfig, table),
+					"unexpected existence of table %s", table)
+			}
+		})
+	}
+}
+
+func TestRollbackTo_SingleTransaction(t *testing.T) {
+	t.Parallel()
+
+	// The Down section of the first migration fails, after the second one was already reverted
+	migrations := []Migration{
+		{
+			ID: "001_first.sql",
+			SQL: `-- +migrate Down
+DROP TABLE missing;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS first (id INTEGER PRIMARY KEY);`,
+		},
+		testMigrations[1],
+	}
+
+	dbConfig := config.DatabaseConfig{Path: path.Join(t.TempDir(), "migrations_test.db")}
+	dbConfig.ApplyDefaults()
+	require.NoError(t, RunMigrations(dbConfig, migrations))
+
+	db, err := NewSQLiteDBFromConfig(dbConfig)
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.ErrorContains(t, RollbackTo(db, migrations, 0), "failed to revert migration 001_first.sql")
+
+	version, err := MigrationVersion(db, migrations)
+	require.NoError(t, err)
+	require.Equal(t, 2, version)
+	require.True(t, tableExists(t, dbConfig, "second"))
+}
+
+func TestMigrationVersion_UnknownMigration(t *testing.T) {
+	t.Parallel()
+
+	dbConfig, _ := applyAndRollbackMigrations(t, 2, 0)
+
+	db, err := NewSQLiteDBFromConfig(dbConfig)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = MigrationVersion(db, testMigrations[1:])
+	require.ErrorContains(t, err, "unexpected applied migration 001_first.sql")
+
+	_, err = MigrationVersion(db, testMigrations[:1])
+	require.ErrorContains(t, err, "only 1 are known")
+}
@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/lib/pq"
+	"github.com/russross/meddler"
+)
+
+// NewPostgresDBFromConfig creates a new PostgreSQL DB with the given configuration.
+// Queries may use the ? placeholders of SQLite, they are rewritten to PostgreSQL's $n
+// placeholders, so the same SQL runs on both databases.
+func NewPostgresDBFromConfig(cfg config.DatabaseConfig) (*sql.DB, error) {
+	connector, err := pq.NewConnector(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse postgres dsn: %w", err)
+	}
+
+	db := sql.OpenDB(&rebindConnector{Connector: connector})
+
+	// Apply connection pool settings
+	db.SetMaxOpenConns(cfg.MaxOpenConnections)
+	db.SetMaxIdleConns(cfg.MaxIdleConnections)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+
+	return db, nil
+}
+
+// IsPostgres reports whether db was opened by NewPostgresDBFromConfig.
+func IsPostgres(db *sql.DB) bool {
+	_, ok := db.Driver().(rebindDriver)
+	return ok
+}
+
+// Meddler returns the meddler dialect to use with db. Queries written by hand can use ? placeholders
+// on any database, but the statements meddler builds, like Insert and Update, quote identifiers
+// the way the dialect does.
+func Meddler(db *sql.DB) *meddler.Database {
+	if IsPostgres(db) {
+		return meddler.PostgreSQL
+	}
+
+	return meddler.Default
+}
+
+// Rebind rewrites the ? placeholders of query to the $n placeholders of PostgreSQL.
+// Question marks in string literals, quoted identifiers and comments are kept.
+func Rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var (
+		b     strings.Builder
+		n     int
+		quote byte // the open quote character, or 0 outside of quotes
+	)
+	b.Grow(len(query) + len(query)/4) //nolint:mnd
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			b.WriteString(query[i : i+end])
+			i += end - 1
+
+			continue
+		case c == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+
+			continue
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// rebindConnector opens PostgreSQL connections that accept ? placeholders.
+type rebindConnector struct {
+	driver.Connector
+}
+
+func (c *rebindConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rebindConn{conn: conn}, nil
+}
+
+func (c *rebindConnector) Driver() driver.Driver {
+	return rebindDriver{Driver: c.Connector.Driver()}
+}
+
+// rebindDriver identifies databases opened through a rebindConnector.
+type rebindDriver struct {
+	driver.Driver
+}
+
+// rebindConn rewrites the placeholders of every query before passing it to the lib/pq connection.
+type rebindConn struct {
+	conn driver.Conn
+}
+
+var (
+	_ driver.Conn               = (*rebindConn)(nil)
+	_ driver.ConnPrepareContext = (*rebindConn)(nil)
+	_ driver.ConnBeginTx        = (*rebindConn)(nil)
+	_ driver.ExecerContext      = (*rebindConn)(nil)
+	_ driver.QueryerContext     = (*rebindConn)(nil)
+	_ driver.Pinger             = (*rebindConn)(nil)
+	_ driver.SessionResetter    = (*rebindConn)(nil)
+	_ driver.Validator          = (*rebindConn)(nil)
+)
+
+func (c *rebindConn) Prepare(query string) (driver.Stmt, error) {
+	return c.conn.Prepare(Rebind(query))
+}
+
+func (c *rebindConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, Rebind(query))
+	}
+
+	return c.conn.Prepare(Rebind(query))
+}
+
+func (c *rebindConn) Close() error {
+	return c.conn.Close()
+}
+
+//nolint:staticcheck // Begin is part of driver.Conn, BeginTx is used when available
+func (c *rebindConn) Begin() (driver.Tx, error) {
+	return c.conn.Begin()
+}
+
+func (c *rebindConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+
+	return c.conn.Begin() //nolint:staticcheck
+}
+
+func (c *rebindConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	return execer.ExecContext(ctx, Rebind(query), args)
+}
+
+func (c *rebindConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	return queryer.QueryContext(ctx, Rebind(query), args)
+}
+
+func (c *rebindConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c *rebindConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *rebindConn) IsValid() bool {
+	if validator, ok := c.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+
+	return true
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "no placeholders",
+			query:    "SELECT COUNT(*) FROM event_logs",
+			expected: "SELECT COUNT(*) FROM event_logs",
+		},
+		{
+			name:     "placeholders",
+			query:    "SELECT * FROM event_logs WHERE address = ? AND block_number BETWEEN ? AND ?",
+			expected: "SELECT * FROM event_logs WHERE address = $1 AND block_number BETWEEN $2 AND $3",
+		},
+		{
+			name:     "quoted question marks",
+			query:    `SELECT '?', "a?b" FROM t WHERE x = ?`,
+			expected: `SELECT '?', "a?b" FROM t WHERE x = $1`,
+		},
+		{
+			name:     "comments",
+			query:    "-- why?\nDELETE FROM t WHERE x < ? -- really?",
+			expected: "-- why?\nDELETE FROM t WHERE x < $1 -- really?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, Rebind(tt.query))
+		})
+	}
+}
+
+func TestNewDBFromConfig_UnknownDriver(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewDBFromConfig(config.DatabaseConfig{Driver: "mysql"})
+	require.ErrorContains(t, err, `unsupported database driver "mysql"`)
+}
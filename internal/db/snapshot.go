@@ -0,0 +1,267 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+)
+
+// ErrSnapshotMigrationMismatch is returned when a snapshot to restore was taken at another migration
+// version than the database it would replace.
+var ErrSnapshotMigrationMismatch = errors.New("snapshot migration version does not match the database")
+
+// ImportSnapshot copies the SQLite database snapshot at snapshotPath into the database
+// described by cfg using the SQLite online backup API. The destination database must not
+// exist yet, so an import never overwrites indexed data. Migrations are not run, callers
+// should run them afterwards to bring an older snapshot up to date.
+func ImportSnapshot(ctx context.Context, snapshotPath string, cfg config.DatabaseConfig) error {
+	if cfg.EncryptionKey != "" {
+		// The backup API cannot copy between plaintext and encrypted databases
+		return errors.New("snapshots cannot be imported into an encrypted database: " +
+			"import into an unencrypted database and encrypt it with EncryptExistingDatabase")
+	}
+
+	if _, err := os.Stat(snapshotPath); err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+
+	if _, err := os.Stat(cfg.Path); err == nil {
+		return fmt.Errorf("database %s already exists", cfg.Path)
+	}
+
+	if err := importSnapshot(ctx, snapshotPath, cfg); err != nil {
+		// Remove the partially imported database, so the import can be retried
+		removeDatabaseFiles(cfg.Path)
+
+		return err
+	}
+
+	return nil
+}
+
+func importSnapshot(ctx context.Context, snapshotPath string, cfg config.DatabaseConfig) error {
+	src, err := sql.Open("sqlite3", "file:"+snapshotPath+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer src.Close()
+
+	if err := checkSnapshot(ctx, src); err != nil {
+		return err
+	}
+
+	dest, err := NewSQLiteDBFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create database: %w", err)
+	}
+	defer dest.Close()
+
+	return copyDatabase(ctx, dest, src)
+}
+
+// ExportSnapshot writes a snapshot of the open database to destPath using the SQLite online
+// backup API, while the database keeps serving reads and writes. The backup copies a single
+// consistent state of the database into a temporary file, which is renamed to destPath once
+// complete, so destPath never holds a partial snapshot. The snapshot is encrypted with the key
+// of cfg, if any. destPath must not exist, so an export never overwrites a file.
+func ExportSnapshot(ctx context.Context, database *sql.DB, cfg config.DatabaseConfig, destPath string) error {
+	if _, err := os.Stat(destPath); err == nil {
+		return fmt.Errorf("snapshot %s: %w", destPath, fs.ErrExist)
+	}
+
+	tmpPath := destPath + ".tmp"
+	if err := exportSnapshot(ctx, database, cfg, tmpPath); err != nil {
+		removeDatabaseFiles(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		removeDatabaseFiles(tmpPath)
+		return fmt.Errorf("failed to move snapshot to %s: %w", destPath, err)
+	}
+
+	return nil
+}
+
+func exportSnapshot(ctx context.Context, database *sql.DB, cfg config.DatabaseConfig, path string) error {
+	destCfg := cfg
+	destCfg.Path = path
+	destCfg.RunIntegrityCheckOnStartup = false
+
+	dest, err := NewSQLiteDBFromConfig(destCfg)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	if err := copyDatabase(ctx, dest, database); err != nil {
+		dest.Close()
+		return err
+	}
+
+	// Closing the last connection checkpoints the WAL, leaving the snapshot in a single file
+	if err := dest.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreSnapshot replaces the content of the open database with the snapshot at snapshotPath
+// using the SQLite online backup API. The content is replaced in a single transaction, and
+// the database handle stays valid, so everything sharing it reads the restored data.
+// The snapshot is opened with the key of cfg, if any, must pass an integrity check and must have
+// the same migrations applied as the database, since the running code expects its schema.
+func RestoreSnapshot(ctx context.Context, database *sql.DB, cfg config.DatabaseConfig, snapshotPath string) error {
+	if _, err := os.Stat(snapshotPath); err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+
+	srcCfg := cfg
+	srcCfg.Path = snapshotPath
+
+	src, err := NewReadOnlySQLiteDBFromConfig(srcCfg)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer src.Close()
+
+	if err := checkSnapshot(ctx, src); err != nil {
+		return err
+	}
+
+	if err := checkSnapshotMigrations(ctx, database, src); err != nil {
+		return err
+	}
+
+	return copyDatabase(ctx, database, src)
+}
+
+// checkSnapshotMigrations verifies the snapshot has the same migrations applied as the database.
+func checkSnapshotMigrations(ctx context.Context, database, src *sql.DB) error {
+	applied, err := appliedMigrations(ctx, database)
+	if err != nil {
+		return fmt.Errorf("failed to read migrations of the database: %w", err)
+	}
+
+	snapshotApplied, err := appliedMigrations(ctx, src)
+	if err != nil {
+		return fmt.Errorf("failed to read migrations of the snapshot: %w", err)
+	}
+
+	if !slices.Equal(applied, snapshotApplied) {
+		return fmt.Errorf("%w: snapshot is at version %d (%s), database is at version %d (%s)",
+			ErrSnapshotMigrationMismatch,
+			len(snapshotApplied), lastMigration(snapshotApplied),
+			len(applied), lastMigration(applied),
+		)
+	}
+
+	return nil
+}
+
+// appliedMigrations returns the ids of the migrations applied to the database, in order.
+func appliedMigrations(ctx context.Context, database *sql.DB) ([]string, error) {
+	rows, err := database.QueryContext(ctx, "SELECT id FROM "+migrationsTable+" ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var applied []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		applied = append(applied, id)
+	}
+
+	return applied, rows.Err()
+}
+
+// lastMigration returns the id of the last applied migration, or "none".
+func lastMigration(applied []string) string {
+	if len(applied) == 0 {
+		return "none"
+	}
+
+	return applied[len(applied)-1]
+}
+
+// checkSnapshot fails early on snapshots that are not valid SQLite databases.
+func checkSnapshot(ctx context.Context, src *sql.DB) error {
+	var result string
+	if err := src.QueryRowContext(ctx, "PRAGMA quick_check").Scan(&result); err != nil {
+		return fmt.Errorf("snapshot is not a valid SQLite database: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("snapshot failed integrity check: %s", result)
+	}
+
+	return nil
+}
+
+// copyDatabase copies the main database of src into the main database of dest.
+func copyDatabase(ctx context.Context, dest, src *sql.DB) error {
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to source database: %w", err)
+	}
+	defer srcConn.Close()
+
+	destConn, err := dest.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to destination database: %w", err)
+	}
+	defer destConn.Close()
+
+	return destConn.Raw(func(destDriverConn any) error {
+		return srcConn.Raw(func(srcDriverConn any) error {
+			return backup(destDriverConn, srcDriverConn)
+		})
+	})
+}
+
+// removeDatabaseFiles removes a database and its WAL files.
+func removeDatabaseFiles(path string) {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		_ = os.Remove(path + suffix)
+	}
+}
+
+// backup copies the main database of the src driver connection into the main
+// database of the dest driver connection.
+func backup(dest, src any) error {
+	destConn, ok := dest.(*sqliteConn)
+	if !ok {
+		return fmt.Errorf("unexpected destination connection type %T", dest)
+	}
+
+	srcConn, ok := src.(*sqliteConn)
+	if !ok {
+		return fmt.Errorf("unexpected source connection type %T", src)
+	}
+
+	b, err := destConn.Backup("main", srcConn, "main")
+	if err != nil {
+		return fmt.Errorf("failed to start backup: %w", err)
+	}
+
+	// A negative page count copies the whole database in a single step
+	if _, err := b.Step(-1); err != nil {
+		_ = b.Finish()
+		return fmt.Errorf("failed to copy snapshot: %w", err)
+	}
+
+	if err := b.Finish(); err != nil {
+		return fmt.Errorf("failed to finish backup: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"database/sql"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportSnapshot(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	snapshotPath := path.Join(dir, "snapshot.db")
+
+	snapshotCfg := config.DatabaseConfig{Path: snapshotPath}
+	snapshotCfg.ApplyDefaults()
+
+	snapshotDB, err := NewSQLiteDBFromConfig(snapshotCfg)
+	require.NoError(t, err)
+	_, err = snapshotDB.Exec(`CREATE TABLE sync_state (id INTEGER PRIMARY KEY, last_indexed_block INTEGER);
+		INSERT INTO sync_state (id, last_indexed_block) VALUES (1, 19000000)`)
+	require.NoError(t, err)
+	require.NoError(t, snapshotDB.Close())
+
+	cfg := config.DatabaseConfig{Path: path.Join(dir, "data", "downloader.db")}
+	cfg.ApplyDefaults()
+
+	require.NoError(t, ImportSnapshot(t.Context(), snapshotPath, cfg))
+
+	imported, err := NewSQLiteDBFromConfig(cfg)
+	require.NoError(t, err)
+	defer imported.Close()
+
+	var lastBlock uint64
+	require.NoError(t, imported.QueryRow("SELECT last_indexed_block FROM sync_state WHERE id = 1").Scan(&lastBlock))
+	require.Equal(t, uint64(19000000), lastBlock)
+
+	// Existing databases are never overwritten
+	require.ErrorContains(t, ImportSnapshot(t.Context(), snapshotPath, cfg), "already exists")
+}
+
+func TestImportSnapshot_Errors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	cfg := config.DatabaseConfig{Path: path.Join(dir, "downloader.db")}
+	cfg.ApplyDefaults()
+
+	err := ImportSnapshot(t.Context(), path.Join(dir, "missing.db"), cfg)
+	require.ErrorContains(t, err, "failed to open snapshot")
+
+	invalid := path.Join(dir, "invalid.db")
+	require.NoError(t, os.WriteFile(invalid, []byte("not a database"), 0o600))
+
+	err = ImportSnapshot(t.Context(), invalid, cfg)
+	require.ErrorContains(t, err, "not a valid SQLite database")
+	require.NoFileExists(t, cfg.Path)
+
+	encryptedCfg := cfg
+	encryptedCfg.EncryptionKey = "secret"
+	require.ErrorContains(t, ImportSnapshot(t.Context(), invalid, encryptedCfg), "encrypted database")
+}
+
+func TestRestoreSnapshot_MigrationVersion(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	newDB := func(name string, migrations ...string) (*sql.DB, config.DatabaseConfig) {
+		cfg := config.DatabaseConfig{Path: path.Join(dir, name)}
+		cfg.ApplyDefaults()
+
+		database, err := NewSQLiteDBFromConfig(cfg)
+		require.NoError(t, err)
+		t.Cleanup(func() { database.Close() })
+
+		_, err = database.Exec(`CREATE TABLE sync_state (id INTEGER PRIMARY KEY, last_indexed_block INTEGER);
+			INSERT INTO sync_state (id, last_indexed_block) VALUES (1, ?)`, len(migrations))
+		require.NoError(t, err)
+
+		if migrations != nil {
+			_, err = database.Exec("CREATE TABLE " + migrationsTable + " (id TEXT PRIMARY KEY, applied_at DATETIME)")
+			require.NoError(t, err)
+		}
+		for _, id := range migrations {
+			_, err = database.Exec("INSERT INTO "+migrationsTable+" (id) VALUES (?)", id)
+			require.NoError(t, err)
+		}
+
+		return database, cfg
+	}
+
+	database, cfg := newDB("downloader.db", "001_sync.sql", "002_logs.sql")
+
+	_, older := newDB("older.db", "001_sync.sql")
+	err := RestoreSnapshot(t.Context(), database, cfg, older.Path)
+	require.ErrorIs(t, err, ErrSnapshotMigrationMismatch)
+	require.ErrorContains(t, err, "snapshot is at version 1 (001_sync.sql), database is at version 2 (002_logs.sql)")
+
+	_, unmigrated := newDB("unmigrated.db")
+	require.ErrorContains(t, RestoreSnapshot(t.Context(), database, cfg, unmigrated.Path),
+		"failed to read migrations of the snapshot")
+
+	// Rejected snapshots leave the database as it was
+	var lastBlock uint64
+	require.NoError(t, database.QueryRow("SELECT last_indexed_block FROM sync_state WHERE id = 1").Scan(&lastBlock))
+	require.Equal(t, uint64(2), lastBlock)
+
+	snapshot, _ := newDB("snapshot.db", "001_sync.sql", "002_logs.sql")
+	_, err = snapshot.Exec("UPDATE sync_state SET last_indexed_block = 19000000")
+	require.NoError(t, err)
+
+	require.NoError(t, RestoreSnapshot(t.Context(), database, cfg, path.Join(dir, "snapshot.db")))
+	require.NoError(t, database.QueryRow("SELECT last_indexed_block FROM sync_state WHERE id = 1").Scan(&lastBlock))
+	require.Equal(t, uint64(19000000), lastBlock)
+}
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+)
+
+// BatchedWrite is a write queued in a WriteBatcher.
+type BatchedWrite struct {
+	// Rows is the number of rows the write adds to the batch
+	Rows int
+
+	// Write writes the rows within the transaction of the flush
+	Write func(ctx context.Context, tx *sql.Tx) error
+
+	// Flushed is called, when set, once the batch of the write was committed or dropped,
+	// with the error of the flush
+	Flushed func(err error)
+}
+
+// WriteBatcher queues writes and commits them together in a single transaction, trading many small
+// commits for few large ones. A batch is flushed once it holds BatchSize rows, or by Run once
+// FlushInterval elapsed. Queued writes are not visible to readers, nor durable, until they are flushed,
+// so readers and writers that depend on them flush first.
+type WriteBatcher struct {
+	db          *sql.DB
+	cfg         config.WriteBatchConfig
+	maintenance Maintenance
+	log         *logger.Logger
+
+	// flushMu serializes flushes, so batches are committed in the order they were queued
+	flushMu sync.Mutex
+
+	mu      sync.Mutex
+	pending []BatchedWrite
+	rows    int
+}
+
+// NewWriteBatcher creates a write batcher committing to the given database.
+// Flushes hold the operation lock of the maintenance coordinator.
+func NewWriteBatcher(
+	database *sql.DB,
+	cfg config.WriteBatchConfig,
+	maintenance Maintenance,
+	log *logger.Logger,
+) *WriteBatcher {
+	cfg.ApplyDefaults()
+
+	return &WriteBatcher{
+		db:          database,
+		cfg:         cfg,
+		maintenance: maintenance,
+		log:         log,
+	}
+}
+
+// Add queues the write. When the batch reaches BatchSize rows, it is flushed before Add returns
+// and the error of the flush is returned.
+func (b *WriteBatcher) Add(ctx context.Context, write BatchedWrite) error {
+	b.mu.Lock()
+	b.pending = append(b.pending, write)
+	b.rows += write.Rows
+	full := b.rows >= b.cfg.BatchSize
+	b.mu.Unlock()
+
+	if !full {
+		return nil
+	}
+
+	return b.Flush(ctx)
+}
+
+// Pending returns the number of rows queued and not flushed yet.
+func (b *WriteBatcher) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.rows
+}
+
+// Flush commits all queued writes in a single transaction. If any of them fails, the whole batch
+// is rolled back and dropped, and the error is returned.
+func (b *WriteBatcher) Flush(ctx context.Context) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	writes, rows := b.pending, b.rows
+	b.pending, b.rows = nil, 0
+	b.mu.Unlock()
+
+	if len(writes) == 0 {
+		return nil
+	}
+
+	err := b.commit(ctx, writes, rows)
+	for _, write := range writes {
+		if write.Flushed != nil {
+			write.Flushed(err)
+		}
+	}
+
+	return err
+}
+
+// commit writes the batch in a single transaction.
+func (b *WriteBatcher) commit(ctx context.Context, writes []BatchedWrite, rows int) error {
+	unlock := b.maintenance.AcquireOperationLock()
+	defer unlock()
+
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			b.log.Errorf("failed to rollback transaction: %v", err)
+		}
+	}()
+
+	for _, write := range writes {
+		if err := write.Write(ctx, tx); err != nil {
+			return fmt.Errorf("failed to write batch of %d rows: %w", rows, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch of %d rows: %w", rows, err)
+	}
+
+	b.log.Debugf("Flushed %d batched writes with %d rows", len(writes), rows)
+
+	return nil
+}
+
+// Run flushes the queued writes every FlushInterval until the context is cancelled.
+// The writes queued when it is cancelled are flushed before Run returns.
+func (b *WriteBatcher) Run(ctx context.Context) {
+	// A flush in progress is not interrupted by the cancellation, which would drop its batch
+	flushCtx := context.WithoutCancel(ctx)
+
+	ticker := time.NewTicker(b.cfg.FlushInterval.Duration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := b.Flush(flushCtx); err != nil {
+				b.log.Errorf("failed to flush batched writes on shutdown: %v", err)
+			}
+
+			return
+		case <-ticker.C:
+			if err := b.Flush(flushCtx); err != nil {
+				b.log.Errorf("failed to flush batched writes: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWriteBatcher(t *testing.T, batchSize int, flushInterval time.Duration) (*WriteBatcher, *sql.DB) {
+	t.Helper()
+
+	database, _ := setupMaintenanceTestDB(t)
+	t.Cleanup(func() { database.Close() })
+
+	batcher := NewWriteBatcher(database, config.WriteBatchConfig{
+		BatchSize:     batchSize,
+		FlushInterval: common.NewDuration(flushInterval),
+	}, &NoOpMaintenance{}, logger.NewNopLogger())
+
+	return batcher, database
+}
+
+// insertRows returns a write inserting the given rows into the test_data table.
+func insertRows(data ...string) BatchedWrite {
+	return BatchedWrite{
+		Rows: len(data),
+		Write: func(ctx context.Context, tx *sql.Tx) error {
+			for _, d := range data {
+				if _, err := tx.ExecContext(ctx, `INSERT INTO test_data (data) VALUES (?)`, d); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func countRows(t *testing.T, database *sql.DB) int {
+	t.Helper()
+
+	var count int
+	require.NoError(t, database.QueryRow(`SELECT COUNT(*) FROM test_data`).Scan(&count))
+
+	return count
+}
+
+func TestWriteBatcher_FlushesFullBatch(t *testing.T) {
+	t.Parallel()
+
+	batcher, database := newTestWriteBatcher(t, 5, time.Hour)
+	ctx := context.Background()
+
+	require.NoError(t, batcher.Add(ctx, insertRows("a", "b")))
+	require.NoError(t, batcher.Add(ctx, insertRows("c", "d")))
+	require.Equal(t, 4, batcher.Pending())
+	require.Zero(t, countRows(t, database))
+
+	// The fifth row fills the batch, which is committed before Add returns
+	require.NoError(t, batcher.Add(ctx, insertRows("e")))
+	require.Zero(t, batcher.Pending())
+	require.Equal(t, 5, countRows(t, database))
+}
+
+func TestWriteBatcher_Flush(t *testing.T) {
+	t.Parallel()
+
+	batcher, database := newTestWriteBatcher(t, 100, time.Hour)
+	ctx := context.Background()
+
+	var flushErrs []error
+	write := insertRows("a", "b")
+	write.Flushed = func(err error) { flushErrs = append(flushErrs, err) }
+
+	require.NoError(t, batcher.Add(ctx, write))
+	require.Empty(t, flushErrs)
+
+	require.NoError(t, batcher.Flush(ctx))
+	require.Equal(t, 2, countRows(t, database))
+	require.Equal(t, []error{nil}, flushErrs)
+
+	// Flushing an empty batch does nothing
+	require.NoError(t, batcher.Flush(ctx))
+	require.Len(t, flushErrs, 1)
+}
+
+func TestWriteBatcher_FailedWriteDropsBatch(t *testing.T) {
+	t.Parallel()
+
+	batcher, database := newTestWriteBatcher(t, 100, time.Hour)
+	ctx := context.Background()
+	writeErr := errors.New("write failed")
+
+	var flushErr error
+	require.NoError(t, batcher.Add(ctx, insertRows("a", "b")))
+	require.NoError(t, batcher.Add(ctx, BatchedWrite{
+		Rows:    1,
+		Write:   func(context.Context, *sql.Tx) error { return writeErr },
+		Flushed: func(err error) { flushErr = err },
+	}))
+
+	err := batcher.Flush(ctx)
+	require.ErrorIs(t, err, writeErr)
+	require.ErrorIs(t, flushErr, writeErr)
+
+	// The whole batch is rolled back and not retried
+	require.Zero(t, countRows(t, database))
+	require.Zero(t, batcher.Pending())
+	require.NoError(t, batcher.Flush(ctx))
+}
+
+func TestWriteBatcher_Run(t *testing.T) {
+	t.Parallel()
+
+	batcher, database := newTestWriteBatcher(t, 100, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go batcher.Run(ctx)
+
+	// Queued rows are flushed once the flush interval elapses
+	require.NoError(t, batcher.Add(ctx, insertRows("a")))
+	require.Eventually(t, func() bool { return countRows(t, database) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestWriteBatcher_RunFlushesOnCancel(t *testing.T) {
+	t.Parallel()
+
+	batcher, database := newTestWriteBatcher(t, 100, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		batcher.Run(ctx)
+		close(done)
+	}()
+
+	// Rows queued when the context is cancelled are flushed before Run returns
+	require.NoError(t, batcher.Add(ctx, insertRows("a", "b")))
+	cancel()
+	<-done
+
+	require.Equal(t, 2, countRows(t, database))
+}
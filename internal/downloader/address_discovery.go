@@ -0,0 +1,210 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	idx "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+)
+
+// discoveredContracts are the contracts a DynamicAddressProvider indexer discovered so far.
+type discoveredContracts struct {
+	// addresses are the discovered contracts, already added to the filter
+	addresses map[common.Address]struct{}
+
+	// nextBlock is the first block that was not searched for discovered contracts yet
+	nextBlock uint64
+}
+
+// discoverAddresses adds the contracts the DynamicAddressProvider indexers discovered up to the given
+// block to the filter. The start block of a discovered contract is the block it was discovered at,
+// so the next log fetcher catches up with its logs from there.
+func (d *Downloader) discoverAddresses(ctx context.Context, upToBlock uint64) error {
+	for _, indexer := range d.coordinator.ListAll() {
+		provider, ok := indexer.(idx.DynamicAddressProvider)
+		if !ok {
+			continue
+		}
+
+		discovered, exists := d.discovered[indexer]
+		if !exists {
+			discovered = &discoveredContracts{
+				addresses: make(map[common.Address]struct{}),
+				nextBlock: indexer.StartBlock(),
+			}
+			d.discovered[indexer] = discovered
+		}
+
+		if upToBlock < discovered.nextBlock {
+			continue
+		}
+
+		addresses, err := provider.AddressFor(ctx, upToBlock)
+		if err != nil {
+			return fmt.Errorf("failed to discover addresses of indexer %s: %w", indexer.GetName(), err)
+		}
+
+		newAddresses := make([]common.Address, 0)
+		for _, addr := range addresses {
+			if _, known := discovered.addresses[addr]; !known {
+				newAddresses = append(newAddresses, addr)
+			}
+		}
+
+		if len(newAddresses) > 0 {
+			startBlocks, err := discoveryBlocks(ctx, provider, newAddresses,
+				indexer.StartBlock(), discovered.nextBlock, upToBlock)
+			if err != nil {
+				return fmt.Errorf("failed to discover addresses of indexer %s: %w", indexer.GetName(), err)
+			}
+
+			d.addDiscoveredAddresses(indexer, startBlocks, provider.DynamicEvents())
+
+			for _, addr := range newAddresses {
+				discovered.addresses[addr] = struct{}{}
+			}
+		}
+
+		discovered.nextBlock = upToBlock + 1
+	}
+
+	return nil
+}
+
+// addDiscoveredAddresses adds the discovered contracts of the indexer to the filter and routes their logs
+// to the indexer. A running download picks up the new filter before fetching the next chunk.
+func (d *Downloader) addDiscoveredAddresses(
+	indexer idx.Indexer,
+	startBlocks map[common.Address]uint64,
+	topics map[common.Hash]struct{},
+) {
+	addresses := slices.SortedFunc(maps.Keys(startBlocks), common.Address.Cmp)
+
+	d.mu.Lock()
+	for _, addr := range addresses {
+		d.addAddressLocked(addr, topics, startBlocks[addr])
+	}
+	totalAddresses := len(d.addresses)
+	d.mu.Unlock()
+
+	d.coordinator.AddAddresses(indexer, addresses, topics)
+	d.reloadPending.Store(true)
+
+	d.log.Infow("discovered contract addresses",
+		"indexer", indexer.GetName(),
+		"addresses", len(addresses),
+		"total_addresses", totalAddresses,
+	)
+}
+
+// rewindDiscovery searches the blocks from the given block for discovered contracts again,
+// since the reorg replaced them.
+func (d *Downloader) rewindDiscovery(fromBlock uint64) {
+	for _, discovered := range d.discovered {
+		discovered.nextBlock = min(discovered.nextBlock, fromBlock)
+	}
+}
+
+// discoveryBlocks returns the block each of the given addresses was discovered at. The addresses must be
+// discovered by toBlock, and those not discovered before searchFrom are searched for from there,
+// which spares the provider most queries when searchFrom is past the blocks searched before.
+func discoveryBlocks(
+	ctx context.Context,
+	provider idx.DynamicAddressProvider,
+	addresses []common.Address,
+	startBlock, searchFrom, toBlock uint64,
+) (map[common.Address]uint64, error) {
+	startBlocks := make(map[common.Address]uint64, len(addresses))
+
+	// Addresses can be discovered before the searched blocks, e.g. when the indexer of the
+	// factory events catches up with blocks that were already indexed
+	if searchFrom > startBlock {
+		earlier, later, err := partitionDiscovered(ctx, provider, addresses, searchFrom-1)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := bisectDiscovery(ctx, provider, earlier, startBlock, searchFrom-1, startBlocks); err != nil {
+			return nil, err
+		}
+
+		addresses, startBlock = later, searchFrom
+	}
+
+	if err := bisectDiscovery(ctx, provider, addresses, startBlock, toBlock, startBlocks); err != nil {
+		return nil, err
+	}
+
+	return startBlocks, nil
+}
+
+// bisectDiscovery finds the block each of the given addresses, all discovered within the block range,
+// was discovered at by repeatedly halving the range.
+func bisectDiscovery(
+	ctx context.Context,
+	provider idx.DynamicAddressProvider,
+	addresses []common.Address,
+	fromBlock, toBlock uint64,
+	startBlocks map[common.Address]uint64,
+) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	if fromBlock >= toBlock {
+		for _, addr := range addresses {
+			startBlocks[addr] = toBlock
+		}
+
+		return nil
+	}
+
+	const halves = 2
+	mid := fromBlock + (toBlock-fromBlock)/halves
+
+	earlier, later, err := partitionDiscovered(ctx, provider, addresses, mid)
+	if err != nil {
+		return err
+	}
+
+	if err := bisectDiscovery(ctx, provider, earlier, fromBlock, mid, startBlocks); err != nil {
+		return err
+	}
+
+	return bisectDiscovery(ctx, provider, later, mid+1, toBlock, startBlocks)
+}
+
+// partitionDiscovered splits the addresses into those discovered at or before the given block and the rest.
+func partitionDiscovered(
+	ctx context.Context,
+	provider idx.DynamicAddressProvider,
+	addresses []common.Address,
+	blockNumber uint64,
+) (earlier, later []common.Address, err error) {
+	if len(addresses) == 0 {
+		return nil, nil, nil
+	}
+
+	discovered, err := provider.AddressFor(ctx, blockNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	discoveredSet := make(map[common.Address]struct{}, len(discovered))
+	for _, addr := range discovered {
+		discoveredSet[addr] = struct{}{}
+	}
+
+	for _, addr := range addresses {
+		if _, ok := discoveredSet[addr]; ok {
+			earlier = append(earlier, addr)
+		} else {
+			later = append(later, addr)
+		}
+	}
+
+	return earlier, later, nil
+}
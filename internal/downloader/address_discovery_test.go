@@ -0,0 +1,153 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/indexer"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	idx "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFactoryIndexer discovers contracts at fixed blocks.
+type mockFactoryIndexer struct {
+	mockIndexer
+
+	// deployments maps every discovered contract to the block it was discovered at
+	deployments map[common.Address]uint64
+	events      map[common.Hash]struct{}
+	queries     int
+	err         error
+}
+
+func (m *mockFactoryIndexer) AddressFor(ctx context.Context, blockNumber uint64) ([]common.Address, error) {
+	m.queries++
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var addresses []common.Address
+	for addr, block := range m.deployments {
+		if block <= blockNumber {
+			addresses = append(addresses, addr)
+		}
+	}
+
+	return addresses, nil
+}
+
+func (m *mockFactoryIndexer) DynamicEvents() map[common.Hash]struct{} {
+	return m.events
+}
+
+func newDiscoveryTestDownloader() *Downloader {
+	return &Downloader{
+		log:                logger.NewNopLogger(),
+		coordinator:        indexer.NewIndexerCoordinator(logger.NewNopLogger()),
+		addresses:          make([]common.Address, 0),
+		topics:             make([][]common.Hash, 0),
+		addressStartBlocks: make(map[common.Address]uint64),
+		discovered:         make(map[idx.Indexer]*discoveredContracts),
+	}
+}
+
+func TestDiscoverAddresses(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	factory := common.HexToAddress("0x01")
+	pair1 := common.HexToAddress("0x11")
+	pair2 := common.HexToAddress("0x12")
+	pair3 := common.HexToAddress("0x13")
+	pair4 := common.HexToAddress("0x14")
+	swapTopic := common.HexToHash("0xd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d822")
+
+	provider := &mockFactoryIndexer{
+		mockIndexer: mockIndexer{
+			eventsToIndex: map[common.Address]map[common.Hash]struct{}{
+				factory: {common.HexToHash("0xaa"): {}},
+			},
+			startBlock: 100,
+		},
+		deployments: map[common.Address]uint64{pair1: 120, pair2: 137},
+		events:      map[common.Hash]struct{}{swapTopic: {}},
+	}
+
+	d := newDiscoveryTestDownloader()
+	d.RegisterIndexer(provider)
+	d.reloadPending.Store(false)
+
+	// Nothing is searched before the indexer's start block
+	require.NoError(t, d.discoverAddresses(ctx, 99))
+	require.Zero(t, provider.queries)
+
+	require.NoError(t, d.discoverAddresses(ctx, 150))
+	require.True(t, d.reloadPending.Load())
+	require.Equal(t, uint64(120), d.addressStartBlocks[pair1])
+	require.Equal(t, uint64(137), d.addressStartBlocks[pair2])
+	require.Equal(t, []common.Hash{swapTopic}, d.topics[d.indexOfAddressLocked(pair1)])
+	require.Equal(t, []common.Hash{swapTopic}, d.topics[d.indexOfAddressLocked(pair2)])
+
+	// Blocks that were already searched are not searched again
+	d.reloadPending.Store(false)
+	queries := provider.queries
+	require.NoError(t, d.discoverAddresses(ctx, 150))
+	require.Equal(t, queries, provider.queries)
+
+	// Without new contracts the filter is unchanged
+	require.NoError(t, d.discoverAddresses(ctx, 160))
+	require.False(t, d.reloadPending.Load())
+	require.Len(t, d.addresses, 3)
+
+	// A contract discovered in the new blocks, and one discovered before them,
+	// e.g. by an indexer of the factory events that caught up with older blocks
+	provider.deployments[pair3] = 175
+	provider.deployments[pair4] = 104
+	require.NoError(t, d.discoverAddresses(ctx, 200))
+	require.True(t, d.reloadPending.Load())
+	require.Equal(t, uint64(175), d.addressStartBlocks[pair3])
+	require.Equal(t, uint64(104), d.addressStartBlocks[pair4])
+	require.Equal(t, uint64(120), d.addressStartBlocks[pair1])
+	require.Len(t, d.addresses, 5)
+
+	// After a reorg the reorged blocks are searched again
+	d.rewindDiscovery(180)
+	require.Equal(t, uint64(180), d.discovered[provider].nextBlock)
+
+	provider.err = errors.New("database is locked")
+	require.ErrorContains(t, d.discoverAddresses(ctx, 200), "database is locked")
+}
+
+func TestDiscoveryBlocks(t *testing.T) {
+	t.Parallel()
+
+	deployments := map[common.Address]uint64{
+		common.HexToAddress("0x01"): 0,
+		common.HexToAddress("0x02"): 1,
+		common.HexToAddress("0x03"): 500,
+		common.HexToAddress("0x04"): 501,
+		common.HexToAddress("0x05"): 999,
+		common.HexToAddress("0x06"): 1000,
+	}
+	provider := &mockFactoryIndexer{deployments: deployments}
+
+	addresses := make([]common.Address, 0, len(deployments))
+	for addr := range deployments {
+		addresses = append(addresses, addr)
+	}
+
+	startBlocks, err := discoveryBlocks(context.Background(), provider, addresses, 0, 0, 1000)
+	require.NoError(t, err)
+	require.Equal(t, deployments, startBlocks)
+
+	// Contracts discovered before the indexer's start block start with the indexer
+	startBlocks, err = discoveryBlocks(context.Background(), provider, addresses, 400, 600, 1000)
+	require.NoError(t, err)
+	require.Equal(t, uint64(400), startBlocks[common.HexToAddress("0x01")])
+	require.Equal(t, uint64(400), startBlocks[common.HexToAddress("0x02")])
+	require.Equal(t, uint64(500), startBlocks[common.HexToAddress("0x03")])
+	require.Equal(t, uint64(1000), startBlocks[common.HexToAddress("0x06")])
+}
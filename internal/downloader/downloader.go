@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"maps"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	ialert "github.com/goran-ethernal/ChainIndexor/internal/alert"
 	internalcommon "github.com/goran-ethernal/ChainIndexor/internal/common"
 	"github.com/goran-ethernal/ChainIndexor/internal/db"
 	"github.com/goran-ethernal/ChainIndexor/internal/fetcher"
@@ -15,13 +18,18 @@ import (
 	"github.com/goran-ethernal/ChainIndexor/internal/indexer"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
-	"github.com/goran-ethernal/ChainIndexor/internal/rpc"
+	"github.com/goran-ethernal/ChainIndexor/internal/progress"
+	internalrpc "github.com/goran-ethernal/ChainIndexor/internal/rpc"
+	"github.com/goran-ethernal/ChainIndexor/internal/tracing"
 	"github.com/goran-ethernal/ChainIndexor/internal/types"
+	"github.com/goran-ethernal/ChainIndexor/pkg/alert"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
 	"github.com/goran-ethernal/ChainIndexor/pkg/downloader"
 	fch "github.com/goran-ethernal/ChainIndexor/pkg/fetcher"
+	pkgstore "github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
 	idx "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
 	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
+	"github.com/goran-ethernal/ChainIndexor/pkg/rpc"
 )
 
 var _ downloader.Downloader = (*Downloader)(nil)
@@ -31,13 +39,23 @@ var _ downloader.Downloader = (*Downloader)(nil)
 // blockchain logs to registered indexers.
 type Downloader struct {
 	cfg                    config.DownloaderConfig
-	rpc                    *rpc.Client
+	rpc                    rpc.EthClient
 	reorgDetector          reorg.Detector
 	syncManager            downloader.SyncManager
 	log                    *logger.Logger
 	coordinator            *indexer.IndexerCoordinator
 	logFetcher             fch.LogFetcher
 	maintenanceCoordinator db.Maintenance
+	progress               *EventEmitter
+	progressBus            *progress.Bus
+	alerts                 alert.Manager
+	lagMonitor             *LagMonitor
+	pendingMonitor         *PendingBlockMonitor
+	headWatcher            *fetcher.HeadWatcher
+	logBatcher             *store.LogBatcher
+
+	// chainID labels the metrics of the downloader and its components
+	chainID uint64
 
 	// Filter configuration built from registered indexers
 	mu        sync.RWMutex
@@ -46,12 +64,35 @@ type Downloader struct {
 
 	// Per-address start blocks (minimum across all indexers for that address)
 	addressStartBlocks map[common.Address]uint64
+
+	// reloadPending is set when the filter or settings changed and the log fetcher must be recreated
+	reloadPending atomic.Bool
+
+	// discovered holds the contracts each DynamicAddressProvider indexer discovered so far.
+	// It is only accessed by the download loop, and by a restore holding chunkMu
+	discovered map[idx.Indexer]*discoveredContracts
+
+	// downloadDone is closed when Download returns, nil while it has not been started. Guarded by mu
+	downloadDone chan struct{}
+
+	// chunkMu is held by the download loop while it processes a chunk, and by a restore while it
+	// replaces the database, so the database is only replaced between chunks
+	chunkMu sync.Mutex
+
+	// pausing is set while a restore waits for chunkMu, and cancelFetch cancels the fetch of the
+	// current chunk, which may wait for new blocks indefinitely. Both are guarded by mu
+	pausing     bool
+	cancelFetch context.CancelFunc
+
+	// restored is set when the database was restored and the download loop must reload the sync state
+	restored atomic.Bool
 }
 
-// New creates a new Downloader instance.
+// New creates a new Downloader instance, whose metrics are labelled with chainID.
 func New(
+	chainID uint64,
 	cfg config.DownloaderConfig,
-	rpcClient *rpc.Client,
+	rpcClient rpc.EthClient,
 	reorgDetector reorg.Detector,
 	syncManager downloader.SyncManager,
 	maintenanceCoordinator db.Maintenance,
@@ -77,14 +118,54 @@ func New(
 		syncManager:            syncManager,
 		maintenanceCoordinator: maintenanceCoordinator,
 		log:                    log,
-		coordinator:            indexer.NewIndexerCoordinator(),
+		coordinator:            indexer.NewIndexerCoordinator(log),
+		progress:               NewEventEmitter(log),
+		progressBus:            progress.NewBus(0),
+		alerts:                 ialert.NewLogManager(log),
 		addresses:              make([]common.Address, 0),
 		topics:                 make([][]common.Hash, 0),
 		addressStartBlocks:     make(map[common.Address]uint64),
+		discovered:             make(map[idx.Indexer]*discoveredContracts),
+		chainID:                chainID,
+	}
+
+	d.coordinator.SetChainID(chainID)
+
+	// Expose the coverage of the log store through the coordinator
+	d.coordinator.SetCoverageDB(syncManager.DB())
+
+	if cfg.Coordinator != nil {
+		d.coordinator.SetMaxConcurrency(cfg.Coordinator.MaxConcurrency)
+	}
+
+	if cfg.PendingMode {
+		pendingClient, ok := rpcClient.(rpc.PendingClient)
+		if !ok {
+			return nil, errors.New("pending mode requires an rpc client that supports pending transactions")
+		}
+
+		d.pendingMonitor = NewPendingBlockMonitor(pendingClient, d.coordinator.ListAll, log)
+	}
+
+	// The log stores of the downloader database share a single batcher, flushed in the background
+	if cfg.WriteBatch != nil {
+		var maintenance db.Maintenance = &db.NoOpMaintenance{}
+		if maintenanceCoordinator != nil {
+			maintenance = maintenanceCoordinator
+		}
+
+		d.logBatcher = store.NewLogBatcher(syncManager.DB(), *cfg.WriteBatch, maintenance, log)
+	}
+
+	// Websocket endpoints push new heads, so live mode does not have to poll for new blocks
+	if cfg.UsesWebSocket() {
+		if headClient, ok := rpcClient.(rpc.HeadClient); ok {
+			d.headWatcher = fetcher.NewHeadWatcher(headClient, log)
+		}
 	}
 
 	// Initialize component health
-	metrics.ComponentHealthSet(internalcommon.ComponentDownloader, true)
+	metrics.ComponentHealthSet(d.chainID, internalcommon.ComponentDownloader, true)
 
 	d.log.Info("downloader initialized")
 
@@ -109,42 +190,16 @@ func (d *Downloader) RegisterIndexer(idx idx.Indexer) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	addressesIndex := make(map[common.Address]int, len(eventsToIndex))
 	for addr, topicSet := range eventsToIndex {
-		// Update the minimum start block for this address
-		if existingStartBlock, exists := d.addressStartBlocks[addr]; !exists || startBlock < existingStartBlock {
-			d.addressStartBlocks[addr] = startBlock
-		}
-
-		// Add address to filter (avoid duplicates)
-		index := d.indexOfAddressLocked(addr)
-		if index == -1 {
-			// Address not found, add it to the downloader's addresses slice
-			// Also initialize corresponding topics slice
-			d.addresses = append(d.addresses, addr)
-			d.topics = append(d.topics, make([]common.Hash, 0))
-			index = len(d.addresses) - 1
-		}
-		addressesIndex[addr] = index
-
-		// Get existing topics for this address
-		addressTopics := make(map[common.Hash]struct{})
-		for _, t := range d.topics[index] {
-			addressTopics[t] = struct{}{}
-		}
-
-		// Add new topics from this indexer's topic set
-		for topic := range topicSet {
-			if _, exists := addressTopics[topic]; !exists {
-				d.topics[index] = append(d.topics[index], topic)
-				allTopics = append(allTopics, topic)
-			}
-		}
+		allTopics = append(allTopics, d.addAddressLocked(addr, topicSet, startBlock)...)
 	}
 
 	// Register with coordinator (outside of lock to avoid potential deadlock)
 	d.coordinator.RegisterIndexer(idx)
 
+	// A running download picks up the new filter before fetching the next chunk
+	d.reloadPending.Store(true)
+
 	d.log.Infow("indexer registered",
 		"indexer", fmt.Sprintf("%T", idx),
 		"start_block", startBlock,
@@ -153,6 +208,46 @@ func (d *Downloader) RegisterIndexer(idx idx.Indexer) {
 	)
 }
 
+// addAddressLocked adds the address and its topics to the filter, lowering the address's start block
+// to the given one. It returns the topics that were not yet in the filter for the address.
+func (d *Downloader) addAddressLocked(
+	addr common.Address,
+	topicSet map[common.Hash]struct{},
+	startBlock uint64,
+) []common.Hash {
+	// Update the minimum start block for this address
+	if existingStartBlock, exists := d.addressStartBlocks[addr]; !exists || startBlock < existingStartBlock {
+		d.addressStartBlocks[addr] = startBlock
+	}
+
+	// Add address to filter (avoid duplicates)
+	index := d.indexOfAddressLocked(addr)
+	if index == -1 {
+		// Address not found, add it to the downloader's addresses slice
+		// Also initialize corresponding topics slice
+		d.addresses = append(d.addresses, addr)
+		d.topics = append(d.topics, make([]common.Hash, 0))
+		index = len(d.addresses) - 1
+	}
+
+	// Get existing topics for this address
+	addressTopics := make(map[common.Hash]struct{})
+	for _, t := range d.topics[index] {
+		addressTopics[t] = struct{}{}
+	}
+
+	// Add new topics from this indexer's topic set
+	newTopics := make([]common.Hash, 0, len(topicSet))
+	for topic := range topicSet {
+		if _, exists := addressTopics[topic]; !exists {
+			d.topics[index] = append(d.topics[index], topic)
+			newTopics = append(newTopics, topic)
+		}
+	}
+
+	return newTopics
+}
+
 func (d *Downloader) getDownloaderStartBlock() uint64 {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
@@ -177,61 +272,242 @@ func (d *Downloader) Coordinator() *indexer.IndexerCoordinator {
 	return d.coordinator
 }
 
+// PingDatabase checks that the downloader's database is reachable by executing a trivial query.
+func (d *Downloader) PingDatabase(ctx context.Context) error {
+	if _, err := d.syncManager.DB().ExecContext(ctx, "SELECT 1"); err != nil {
+		return fmt.Errorf("failed to query database: %w", err)
+	}
+
+	return nil
+}
+
+// PreviewRetention reports what the given retention policy would delete from the log store
+// for the given addresses, without modifying any data.
+func (d *Downloader) PreviewRetention(
+	ctx context.Context,
+	policy config.RetentionPolicyConfig,
+	addresses []common.Address,
+) (*pkgstore.RetentionPreview, error) {
+	logStore := d.newLogStore(d.log, &policy)
+
+	return logStore.PreviewRetention(ctx, addresses)
+}
+
+// SimulateRetention reports what the given retention policy would delete from the whole log store,
+// without modifying any data.
+func (d *Downloader) SimulateRetention(
+	ctx context.Context,
+	policy config.RetentionPolicyConfig,
+) (*pkgstore.RetentionSimulation, error) {
+	return d.newLogStore(d.log, nil).SimulateRetention(ctx, policy)
+}
+
+// Snapshot writes a consistent copy of the downloader database to destPath while indexing continues.
+func (d *Downloader) Snapshot(ctx context.Context, destPath string) error {
+	return d.newLogStore(d.log, nil).Snapshot(ctx, destPath)
+}
+
+// Restore replaces the downloader database with the snapshot at srcPath. The download is paused
+// while the database is replaced: the chunk being indexed is completed first, and a chunk being
+// fetched is abandoned. The indexers then drop the blocks after the last indexed block of the
+// snapshot, and the download resumes from it.
+func (d *Downloader) Restore(ctx context.Context, srcPath string) error {
+	resume := d.pause()
+	defer resume()
+
+	if err := d.newLogStore(d.log, nil).Restore(ctx, srcPath); err != nil {
+		return err
+	}
+
+	state, err := d.syncManager.GetState()
+	if err != nil {
+		return fmt.Errorf("failed to get restored sync state: %w", err)
+	}
+
+	// What was indexed after the snapshot was taken is rolled back, as if it had been reorged
+	fromBlock := state.LastIndexedBlock + 1
+	if err := d.coordinator.HandleReorg(fromBlock); err != nil {
+		return fmt.Errorf("failed to roll back indexers to the restored block %d: %w", state.LastIndexedBlock, err)
+	}
+	if err := d.reorgDetector.HandleReorg(ctx, fromBlock); err != nil {
+		return fmt.Errorf("failed to reset reorg detector: %w", err)
+	}
+	d.rewindDiscovery(fromBlock)
+
+	// The download loop reloads the sync state, and a new fetcher starts over in backfill mode
+	d.restored.Store(true)
+	d.reloadPending.Store(true)
+
+	d.log.Warnf("downloader database restored from %s, resuming from block %d", srcPath, state.LastIndexedBlock)
+
+	return nil
+}
+
+// pause stops the download loop before its next chunk and returns the function resuming it.
+// A chunk being indexed is completed, while a chunk being fetched is abandoned and fetched again.
+func (d *Downloader) pause() (resume func()) {
+	d.mu.Lock()
+	d.pausing = true
+	if d.cancelFetch != nil {
+		d.cancelFetch()
+	}
+	d.mu.Unlock()
+
+	d.chunkMu.Lock()
+
+	return func() {
+		d.mu.Lock()
+		d.pausing = false
+		d.mu.Unlock()
+
+		d.chunkMu.Unlock()
+	}
+}
+
+// fetchContext returns the context the next chunk is fetched with, which pause cancels,
+// and the function releasing it once the fetch returned.
+func (d *Downloader) fetchContext(ctx context.Context) (context.Context, func()) {
+	fetchCtx, cancel := context.WithCancel(ctx)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.pausing {
+		cancel()
+	} else {
+		d.cancelFetch = cancel
+	}
+
+	return fetchCtx, func() {
+		d.mu.Lock()
+		d.cancelFetch = nil
+		d.mu.Unlock()
+
+		cancel()
+	}
+}
+
+// GetReorgHistory returns the reorgs the reorg detector recorded whose first reorged block is
+// within the inclusive block range, oldest first.
+func (d *Downloader) GetReorgHistory(ctx context.Context, fromBlock, toBlock uint64) ([]reorg.ReorgEvent, error) {
+	return d.reorgDetector.GetReorgHistory(ctx, fromBlock, toBlock)
+}
+
+// newLogStore creates a log store of the configured database driver on the sync manager's database connection.
+func (d *Downloader) newLogStore(log *logger.Logger, retentionPolicy *config.RetentionPolicyConfig) *store.LogStore {
+	var logStore *store.LogStore
+	if d.cfg.DB.Driver == config.DBDriverPostgres {
+		logStore = store.NewPostgresLogStore(d.syncManager.DB(), log, d.cfg.DB, retentionPolicy,
+			d.maintenanceCoordinator).LogStore
+	} else {
+		logStore = store.NewLogStore(d.syncManager.DB(), log, d.cfg.DB, retentionPolicy, d.maintenanceCoordinator)
+	}
+
+	logStore.SetChainID(d.chainID)
+	if d.logBatcher != nil {
+		logStore.SetLogBatcher(d.logBatcher)
+	}
+
+	return logStore
+}
+
+// LogStore returns the downloader's log store, for reading the stored logs and their coverage.
+func (d *Downloader) LogStore() pkgstore.LogStore {
+	return d.newLogStore(d.log, nil)
+}
+
+// PendingEvents returns the previewed events of pending transactions for the given indexer.
+// It returns an empty list when pending mode is disabled.
+func (d *Downloader) PendingEvents(indexer string) []downloader.PendingEvent {
+	if d.pendingMonitor == nil {
+		return []downloader.PendingEvent{}
+	}
+
+	return d.pendingMonitor.PendingEvents(indexer)
+}
+
+// SetProgressChannel sets the channel on which progress events are published
+// after each processed block range. Pass nil to stop publishing.
+func (d *Downloader) SetProgressChannel(ch chan<- downloader.ProgressEvent) {
+	d.progress.SetChannel(ch)
+}
+
+// ProgressBus returns the bus on which progress events are published after each processed
+// block range, for any number of subscribers such as the backfill progress endpoint of the API.
+func (d *Downloader) ProgressBus() *progress.Bus {
+	return d.progressBus
+}
+
+// SetAlertManager sets the manager used to send alerts, such as indexer lag alerts.
+// By default alerts are written to the log. It must be called before Download.
+func (d *Downloader) SetAlertManager(alerts alert.Manager) {
+	d.alerts = alerts
+}
+
 // Download starts the download process, streaming logs to registered indexers.
 // It continues until the context is cancelled or an error occurs.
 func (d *Downloader) Download(ctx context.Context, cfg config.Config) error {
 	d.log.Info("starting download process")
 
+	done := make(chan struct{})
+	d.mu.Lock()
+	d.downloadDone = done
+	d.mu.Unlock()
+	defer close(done)
+
+	logStoreLog := logger.NewComponentLoggerFromConfig(internalcommon.ComponentLogStore, cfg.Logging)
+
 	// Start maintenance coordinator if configured
 	if d.maintenanceCoordinator != nil {
+		// Merge the coverage ranges every stored chunk adds, so the coverage tables stay small
+		d.maintenanceCoordinator.RegisterTask("coverage compaction", d.newLogStore(logStoreLog, nil).CompactCoverage)
+
 		if err := d.maintenanceCoordinator.Start(ctx); err != nil {
 			return fmt.Errorf("failed to start maintenance coordinator: %w", err)
 		}
 	}
 
+	// Queued logs are flushed periodically, and once more when the download is cancelled
+	if d.logBatcher != nil {
+		go d.logBatcher.Run(ctx)
+	}
+
+	// Start lag monitoring for indexers with a lag alert configured
+	d.lagMonitor = NewLagMonitor(cfg.Indexers, d.alerts, d.log)
+	if d.lagMonitor.Enabled() {
+		go d.lagMonitor.Run(ctx)
+	}
+
+	// Start previewing the events of pending transactions. Previews are best effort,
+	// so a failing subscription does not stop indexing confirmed blocks
+	if d.pendingMonitor != nil {
+		go func() {
+			if err := d.pendingMonitor.Run(ctx); err != nil {
+				d.log.Errorf("pending block monitor stopped: %v", err)
+			}
+		}()
+	}
+
+	// Live mode falls back to polling while the new heads subscription is down
+	if d.headWatcher != nil {
+		go d.headWatcher.Run(ctx)
+	}
+
 	// Parse finality from config string
 	finality, err := types.ParseBlockFinality(d.cfg.Finality)
 	if err != nil {
 		return fmt.Errorf("invalid finality configuration: %w", err)
 	}
 
-	// Initialize LogFetcher with filter configuration
-	d.mu.RLock()
+	fetcherLog := logger.NewComponentLoggerFromConfig(internalcommon.ComponentLogFetcher, cfg.Logging)
 
-	addresses := make([]common.Address, len(d.addresses))
-	copy(addresses, d.addresses)
-	topics := make([][]common.Hash, len(d.topics))
-	for i, topicSlice := range d.topics {
-		topics[i] = make([]common.Hash, len(topicSlice))
-		copy(topics[i], topicSlice)
-	}
-	// Copy addressStartBlocks map
-	addressStartBlocks := make(map[common.Address]uint64, len(d.addressStartBlocks))
-	maps.Copy(addressStartBlocks, d.addressStartBlocks)
-
-	d.mu.RUnlock()
+	// Filter and settings changes made before the download starts are picked up right away
+	d.reloadPending.Store(false)
+	logStore := d.newLogFetcher(finality, logStoreLog, fetcherLog)
 
-	// Create LogStore using the sync manager's database connection
-	logStore := store.NewLogStore(
-		d.syncManager.DB(),
-		logger.NewComponentLoggerFromConfig(internalcommon.ComponentLogStore, cfg.Logging),
-		d.cfg.DB,
-		d.cfg.RetentionPolicy,
-		d.maintenanceCoordinator,
-	)
-
-	d.logFetcher = fetcher.NewLogFetcher(
-		fetcher.LogFetcherConfig{
-			ChunkSize:          d.cfg.ChunkSize,
-			Finality:           finality,
-			FinalizedLag:       d.cfg.FinalizedLag,
-			Addresses:          addresses,
-			Topics:             topics,
-			AddressStartBlocks: addressStartBlocks,
-		},
-		logger.NewComponentLoggerFromConfig(internalcommon.ComponentLogFetcher, cfg.Logging),
-		d.rpc, d.reorgDetector, logStore,
-	)
+	// Register the fallback indexer last, so logs that no indexer claimed are kept
+	// in the unmatched_logs table instead of being dropped
+	d.coordinator.SetFallbackIndexer(d.newFallbackIndexer())
 
 	// Get current sync state
 	state, err := d.syncManager.GetState()
@@ -252,10 +528,33 @@ func (d *Downloader) Download(ctx context.Context, cfg config.Config) error {
 		d.log.Infof("resuming download from block %d", lastIndexedBlock)
 	}
 
+	// Fill the coverage gaps left by a previous run before indexing new blocks
+	d.chunkMu.Lock()
+	err = d.fillCoverageGaps(ctx, logStore, lastIndexedBlock)
+	d.chunkMu.Unlock()
+	if err != nil {
+		return err
+	}
+
 	d.logFetcher.SetMode(fch.ModeBackfill) // Always start in backfill mode
 
+	recovery := &reorgRecovery{maxRecoveries: d.cfg.MaxAutoReorgRecoveries}
+
+	// Every chunk is processed holding chunkMu, which is released between chunks and when Download returns
+	chunkLocked := false
+	defer func() {
+		if chunkLocked {
+			d.chunkMu.Unlock()
+		}
+	}()
+
 	// Main download loop
 	for {
+		if chunkLocked {
+			d.chunkMu.Unlock()
+			chunkLocked = false
+		}
+
 		select {
 		case <-ctx.Done():
 			d.log.Info("download cancelled")
@@ -263,21 +562,78 @@ func (d *Downloader) Download(ctx context.Context, cfg config.Config) error {
 		default:
 		}
 
+		d.chunkMu.Lock()
+		chunkLocked = true
+
+		// A restored database replaces the sync state the download continues from
+		if d.restored.CompareAndSwap(true, false) {
+			state, err = d.syncManager.GetState()
+			if err != nil {
+				return fmt.Errorf("failed to get sync state after restore: %w", err)
+			}
+
+			lastIndexedBlock = state.LastIndexedBlock
+			if lastIndexedBlock == 0 && downloaderStartBlock > 0 {
+				lastIndexedBlock = downloaderStartBlock - 1
+			}
+		}
+
+		// Contracts discovered by the indexed blocks are added to the filter
+		if err := d.discoverAddresses(ctx, lastIndexedBlock); err != nil {
+			return err
+		}
+
+		// Indexers registered or settings updated while downloading take effect with a new fetcher.
+		// It starts in backfill mode, so new indexers first catch up with the blocks indexed so far
+		if d.reloadPending.CompareAndSwap(true, false) {
+			d.log.Info("filter or settings changed, recreating the log fetcher")
+			d.newLogFetcher(finality, logStoreLog, fetcherLog)
+			downloaderStartBlock = d.getDownloaderStartBlock()
+		}
+
+		// Every chunk is traced from the RPC fetch to the indexers' database writes
+		chunkCtx, span := tracing.Tracer().Start(ctx, "Downloader.ProcessChunk")
+
 		// Fetch next chunk
-		result, err := d.logFetcher.FetchNext(ctx, lastIndexedBlock, downloaderStartBlock)
+		fetchCtx, releaseFetch := d.fetchContext(chunkCtx)
+		result, err := d.logFetcher.FetchNext(fetchCtx, lastIndexedBlock, downloaderStartBlock)
+		abandoned := fetchCtx.Err() != nil && ctx.Err() == nil
+		releaseFetch()
 		if err != nil {
+			tracing.EndSpan(span, err)
+
+			// The fetch was abandoned for a restore, the download continues from the restored sync state
+			if abandoned {
+				continue
+			}
+
 			// Check if this is a reorg error
 			var reorgErr *reorg.ReorgDetectedError
 			if errors.As(err, &reorgErr) {
-				d.log.Warnf("reorg detected, initiating rollback: block=%d, details=%s",
+				if err := d.checkReorgDepth(ctx, reorgErr, lastIndexedBlock); err != nil {
+					d.log.Errorf("reorg not recovered, manual intervention required: %v", err)
+					return err
+				}
+				if err := d.checkAutoRecovery(reorgErr, lastIndexedBlock); err != nil {
+					d.log.Errorf("reorg not recovered: %v", err)
+					return err
+				}
+				if err := recovery.record(reorgErr, lastIndexedBlock); err != nil {
+					d.log.Errorf("reorg not recovered, manual intervention required: %v", err)
+					return err
+				}
+
+				d.log.Warnf("reorg detected, auto-recovering: block=%d, depth=%d, details=%s",
 					reorgErr.FirstReorgBlock,
+					reorgDepth(lastIndexedBlock, reorgErr.FirstReorgBlock),
 					reorgErr.Details,
 				)
-				if err := d.handleReorg(reorgErr.FirstReorgBlock); err != nil {
+				if err := d.handleReorg(ctx, reorgErr.FirstReorgBlock); err != nil {
 					return fmt.Errorf("failed to handle reorg: %w", err)
 				}
-				// Continue from rolled-back position
-				state, err := d.syncManager.GetState()
+				// Continue from rolled-back position. The state is replaced, so the checkpoints
+				// of the re-fetched blocks are saved again
+				state, err = d.syncManager.GetState()
 				if err != nil {
 					return fmt.Errorf("failed to get state after reorg: %w", err)
 				}
@@ -298,11 +654,25 @@ func (d *Downloader) Download(ctx context.Context, cfg config.Config) error {
 				result.ToBlock,
 			)
 
-			metrics.LogsIndexedInc(internalcommon.ComponentDownloader, len(result.Logs))
+			metrics.LogsIndexedInc(d.chainID, internalcommon.ComponentDownloader, len(result.Logs))
+		}
 
-			if err := d.coordinator.HandleLogs(result.Logs, result.FromBlock, result.ToBlock); err != nil {
-				return fmt.Errorf("failed to handle logs: %w", err)
-			}
+		// Logs are routed even for empty ranges, so that logs buffered for indexers
+		// with a confirmation buffer are released as the finalized block advances
+		d.coordinator.SetFinalizedBlock(result.TargetBlock)
+		// A fetched chunk is already in the log store, so it is indexed even when shutdown was requested
+		err = d.flushQueuedLogs(context.WithoutCancel(chunkCtx))
+		if err == nil {
+			err = d.coordinator.HandleLogs(context.WithoutCancel(chunkCtx), result.Logs, result.FromBlock, result.ToBlock)
+		}
+		tracing.EndSpan(span, err)
+		if err != nil {
+			return fmt.Errorf("failed to handle logs: %w", err)
+		}
+
+		// Confirmed logs replace the previews of their transactions
+		if d.pendingMonitor != nil {
+			d.pendingMonitor.Confirm(result.Logs)
 		}
 
 		// Save checkpoint with the last block's hash
@@ -324,8 +694,9 @@ func (d *Downloader) Download(ctx context.Context, cfg config.Config) error {
 			}
 
 			lastIndexedBlock = result.ToBlock
-			metrics.LastIndexedBlockInc(internalcommon.ComponentDownloader, lastIndexedBlock)
-			metrics.BlocksProcessedInc(internalcommon.ComponentDownloader, result.ToBlock-result.FromBlock+1)
+			recovery.advance(lastIndexedBlock)
+			metrics.LastIndexedBlockInc(d.chainID, internalcommon.ComponentDownloader, lastIndexedBlock)
+			metrics.BlocksProcessedInc(d.chainID, internalcommon.ComponentDownloader, result.ToBlock-result.FromBlock+1)
 
 			d.log.Infof("checkpoint saved: from_block=%d, to_block=%d, to_block_hash=%s, mode=%s, logs_processed=%d",
 				result.FromBlock,
@@ -336,6 +707,9 @@ func (d *Downloader) Download(ctx context.Context, cfg config.Config) error {
 			)
 		}
 
+		d.emitProgress(result)
+		d.observeLag(ctx, result)
+
 		// Get new state
 		state, err = d.syncManager.GetState()
 		if err != nil {
@@ -344,9 +718,242 @@ func (d *Downloader) Download(ctx context.Context, cfg config.Config) error {
 	}
 }
 
+// newLogFetcher creates the log fetcher, and the log store it writes to, for the filter of the registered
+// indexers and the current settings. It returns the log store.
+func (d *Downloader) newLogFetcher(
+	finality types.BlockFinality,
+	logStoreLog, fetcherLog *logger.Logger,
+) *store.LogStore {
+	fetcherCfg, retentionPolicy := d.logFetcherConfig(finality)
+
+	// Create LogStore using the sync manager's database connection
+	logStore := d.newLogStore(logStoreLog, retentionPolicy)
+
+	if d.cfg.FetcherPoolSize > 1 {
+		d.logFetcher = fetcher.NewFetcherPool(fetcherCfg, d.cfg.FetcherPoolSize, fetcherLog,
+			d.rpc, d.reorgDetector, logStore)
+	} else {
+		d.logFetcher = fetcher.NewLogFetcher(fetcherCfg, fetcherLog, d.rpc, d.reorgDetector, logStore)
+	}
+
+	return logStore
+}
+
+// logFetcherConfig returns the log fetcher configuration for the filter of the registered indexers
+// and the current settings, with the retention policy of the log store.
+func (d *Downloader) logFetcherConfig(
+	finality types.BlockFinality,
+) (fetcher.LogFetcherConfig, *config.RetentionPolicyConfig) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	addresses := make([]common.Address, len(d.addresses))
+	copy(addresses, d.addresses)
+	topics := make([][]common.Hash, len(d.topics))
+	for i, topicSlice := range d.topics {
+		topics[i] = make([]common.Hash, len(topicSlice))
+		copy(topics[i], topicSlice)
+	}
+	// Copy addressStartBlocks map
+	addressStartBlocks := make(map[common.Address]uint64, len(d.addressStartBlocks))
+	maps.Copy(addressStartBlocks, d.addressStartBlocks)
+
+	return fetcher.LogFetcherConfig{
+		ChunkSize:              d.cfg.ChunkSize,
+		MinChunkSize:           d.cfg.MinChunkSize,
+		MaxChunkSize:           d.cfg.MaxChunkSize,
+		TargetFetchDuration:    d.cfg.TargetFetchDuration.Duration,
+		Finality:               finality,
+		FinalizedLag:           d.cfg.FinalizedLag,
+		Addresses:              addresses,
+		Topics:                 topics,
+		AddressStartBlocks:     addressStartBlocks,
+		BloomPrefilter:         d.cfg.BloomPrefilter,
+		MaxAddressesPerRequest: d.cfg.MaxAddressesPerRequest,
+		PollInterval:           d.cfg.PollInterval.Duration,
+		UsePushMode:            d.headWatcher != nil,
+		Heads:                  d.headWatcher,
+		LogProgressEvery:       d.cfg.LogProgressEvery,
+		ChainID:                d.chainID,
+	}, d.cfg.RetentionPolicy
+}
+
+// newFallbackIndexer creates the indexer storing the logs that no registered indexer claimed.
+func (d *Downloader) newFallbackIndexer() *indexer.FallbackIndexer {
+	fallbackIndexer := indexer.NewFallbackIndexer(d.syncManager.DB(), d.log)
+	if d.cfg.SignatureRegistry != nil {
+		fallbackIndexer.SetSignatureResolver(
+			internalrpc.NewSignatureRegistry(d.cfg.SignatureRegistry, d.syncManager.DB(), d.log))
+	}
+
+	return fallbackIndexer
+}
+
+// UpdateConfig applies the chunk size and retention policy of the given configuration to a running
+// downloader. They take effect before the next chunk is fetched. Other settings require a restart.
+func (d *Downloader) UpdateConfig(cfg config.DownloaderConfig) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.cfg.ChunkSize = cfg.ChunkSize
+	d.cfg.RetentionPolicy = cfg.RetentionPolicy
+	d.reloadPending.Store(true)
+
+	d.log.Infof("downloader settings updated: chunk_size=%d", cfg.ChunkSize)
+}
+
+// flushQueuedLogs commits the logs queued in the log batcher. It is called before logs are routed to
+// the indexers, so the indexers and the checkpoint never get ahead of the logs in the log store:
+// otherwise a crash would lose the queued logs, and fetching them again would deliver them twice.
+func (d *Downloader) flushQueuedLogs(ctx context.Context) error {
+	if d.logBatcher == nil {
+		return nil
+	}
+
+	if err := d.logBatcher.Flush(ctx); err != nil {
+		return fmt.Errorf("failed to flush queued logs: %w", err)
+	}
+
+	return nil
+}
+
+// rangeFetcher fetches a block range for a subset of the configured addresses.
+type rangeFetcher interface {
+	FetchRangeFor(
+		ctx context.Context,
+		fromBlock, toBlock uint64,
+		addresses []common.Address,
+		topics [][]common.Hash,
+	) (*fch.FetchResult, error)
+}
+
+// fillCoverageGaps fetches the blocks up to lastIndexedBlock that the log store has no coverage for,
+// for the registered indexers, and routes their logs to the indexers.
+func (d *Downloader) fillCoverageGaps(ctx context.Context, logStore pkgstore.LogStore, lastIndexedBlock uint64) error {
+	fetcher, ok := d.logFetcher.(rangeFetcher)
+	if !ok || lastIndexedBlock == 0 {
+		return nil
+	}
+
+	handle := func(ctx context.Context, result *fch.FetchResult) error {
+		if len(result.Logs) > 0 {
+			metrics.LogsIndexedInc(d.chainID, internalcommon.ComponentDownloader, len(result.Logs))
+		}
+
+		if err := d.flushQueuedLogs(ctx); err != nil {
+			return err
+		}
+
+		return d.coordinator.HandleLogs(ctx, result.Logs, result.FromBlock, result.ToBlock)
+	}
+
+	d.mu.RLock()
+	chunkSize := d.cfg.ChunkSize
+	d.mu.RUnlock()
+
+	scheduler := NewGapFillScheduler(d.chainID, logStore, fetcher.FetchRangeFor, handle,
+		chunkSize, d.cfg.MaxConcurrentGapFills, d.log)
+	if err := scheduler.Run(ctx, d.coordinator.ListAll(), lastIndexedBlock); err != nil {
+		return fmt.Errorf("failed to fill coverage gaps: %w", err)
+	}
+
+	return nil
+}
+
+// emitProgress publishes a progress event for every registered indexer
+// based on the block range that was just processed.
+func (d *Downloader) emitProgress(result *fch.FetchResult) {
+	if !d.progress.Enabled() && !d.progressBus.HasSubscribers() {
+		return
+	}
+
+	mode := d.logFetcher.GetMode().String()
+	for _, registered := range d.coordinator.ListAll() {
+		event := downloader.NewProgressEvent(
+			mode,
+			registered.GetName(),
+			registered.StartBlock(),
+			result.ToBlock,
+			result.TargetBlock,
+		)
+
+		d.progress.Emit(event)
+		d.progressBus.Publish(event)
+	}
+}
+
+// observeLag records the lag of every registered indexer based on the
+// block range that was just processed.
+func (d *Downloader) observeLag(ctx context.Context, result *fch.FetchResult) {
+	if d.lagMonitor == nil || !d.lagMonitor.Enabled() {
+		return
+	}
+
+	for _, registered := range d.coordinator.ListAll() {
+		d.lagMonitor.Observe(ctx, registered.GetName(), result.ToBlock, result.TargetBlock)
+	}
+}
+
+// checkReorgDepth returns an error wrapping reorgErr and reorg.ErrReorgDepthExceeded if the reorg is
+// deeper than the max reorg depth, and alerts the operators. The limit applies whether or not the reorg
+// would be recovered automatically.
+func (d *Downloader) checkReorgDepth(
+	ctx context.Context, reorgErr *reorg.ReorgDetectedError, lastIndexedBlock uint64,
+) error {
+	depth := reorgDepth(lastIndexedBlock, reorgErr.FirstReorgBlock)
+	if d.cfg.MaxReorgDepth == 0 || depth <= d.cfg.MaxReorgDepth {
+		return nil
+	}
+
+	err := d.alerts.Trigger(ctx, alert.Alert{
+		Type: alert.TypeReorgDepthExceeded,
+		Message: fmt.Sprintf("a reorg of %d blocks from block %d exceeds the max reorg depth of %d, "+
+			"the downloader is halted", depth, reorgErr.FirstReorgBlock, d.cfg.MaxReorgDepth),
+		RecommendedAction: "check the RPC endpoint is following the canonical chain, then roll back the " +
+			"reorged blocks or raise downloader.max_reorg_depth and restart",
+		Details: map[string]any{
+			"first_reorg_block":  reorgErr.FirstReorgBlock,
+			"reorg_depth":        depth,
+			"max_reorg_depth":    d.cfg.MaxReorgDepth,
+			"last_indexed_block": lastIndexedBlock,
+		},
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		d.log.Errorf("failed to trigger reorg depth alert: %v", err)
+	}
+
+	return fmt.Errorf("%w: reorg depth %d exceeds max reorg depth %d: %w",
+		reorg.ErrReorgDepthExceeded, depth, d.cfg.MaxReorgDepth, reorgErr)
+}
+
+// checkAutoRecovery returns an error wrapping reorgErr if the reorg must not be recovered automatically,
+// either because auto recovery is disabled or the reorg is deeper than the configured limit.
+func (d *Downloader) checkAutoRecovery(reorgErr *reorg.ReorgDetectedError, lastIndexedBlock uint64) error {
+	if !d.cfg.AutoRecoveryEnabled() {
+		return fmt.Errorf("auto recovery is disabled: %w", reorgErr)
+	}
+
+	if depth := reorgDepth(lastIndexedBlock, reorgErr.FirstReorgBlock); depth > d.cfg.MaxAutoRecoveryDepth {
+		return fmt.Errorf("%w: reorg depth %d exceeds max auto recovery depth %d: %w",
+			reorg.ErrReorgDepthExceeded, depth, d.cfg.MaxAutoRecoveryDepth, reorgErr)
+	}
+
+	return nil
+}
+
+// reorgDepth returns the number of already indexed blocks affected by a reorg starting at firstReorgBlock.
+func reorgDepth(lastIndexedBlock, firstReorgBlock uint64) uint64 {
+	if firstReorgBlock > lastIndexedBlock {
+		return 0
+	}
+
+	return lastIndexedBlock - firstReorgBlock + 1
+}
+
 // handleReorg handles a blockchain reorganization by rolling back indexers
 // and adjusting the sync state.
-func (d *Downloader) handleReorg(firstReorgBlock uint64) error {
+func (d *Downloader) handleReorg(ctx context.Context, firstReorgBlock uint64) error {
 	d.log.Warnf("handling reorg: first_reorg_block=%d", firstReorgBlock)
 
 	// Notify all indexers to roll back
@@ -354,6 +961,12 @@ func (d *Downloader) handleReorg(firstReorgBlock uint64) error {
 		return fmt.Errorf("failed to notify indexers of reorg: %w", err)
 	}
 
+	// Forget the reorged block hashes, so the re-fetched range is verified against the new chain.
+	// The log store has already dropped its cached logs when the fetcher detected the reorg.
+	if err := d.reorgDetector.HandleReorg(ctx, firstReorgBlock); err != nil {
+		return fmt.Errorf("failed to reset reorg detector: %w", err)
+	}
+
 	// Reset sync state to rollback point
 	rollbackTo := firstReorgBlock - 1
 	if err := d.syncManager.Reset(rollbackTo); err != nil {
@@ -367,17 +980,48 @@ func (d *Downloader) handleReorg(firstReorgBlock uint64) error {
 
 	d.logFetcher.SetMode(fch.ModeBackfill)
 
+	// Contracts discovered in the reorged blocks are searched for again. Those already added stay
+	// in the filter, which at worst fetches logs of contracts that no longer exist
+	d.rewindDiscovery(firstReorgBlock)
+
 	d.log.Infof("reorg handled, resuming from safe block %d", rollbackTo)
 
 	return nil
 }
 
+// WaitForDrain blocks until Download returned after its context was cancelled, or the context of
+// WaitForDrain is done. Once cancelled, Download still stores and indexes the chunk it was fetching,
+// so returning nil means no chunk is left half-written. It returns nil if Download was not started.
+func (d *Downloader) WaitForDrain(ctx context.Context) error {
+	d.mu.RLock()
+	done := d.downloadDone
+	d.mu.RUnlock()
+
+	if done == nil {
+		return nil
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("downloader did not drain: %w", ctx.Err())
+	}
+}
+
 // Close closes the downloader and releases resources.
 func (d *Downloader) Close() error {
 	d.log.Info("closing downloader")
 
 	// Mark component as unhealthy
-	metrics.ComponentHealthSet(internalcommon.ComponentDownloader, false)
+	metrics.ComponentHealthSet(d.chainID, internalcommon.ComponentDownloader, false)
+
+	// Queued logs are committed before the database is closed
+	if d.logBatcher != nil {
+		if err := d.logBatcher.Flush(context.Background()); err != nil {
+			d.log.Errorf("failed to flush queued logs: %v", err)
+		}
+	}
 
 	if d.syncManager != nil {
 		if err := d.syncManager.Close(); err != nil {
@@ -1,17 +1,22 @@
 package downloader
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	alertmocks "github.com/goran-ethernal/ChainIndexor/internal/alert/mocks"
 	"github.com/goran-ethernal/ChainIndexor/internal/db"
 	"github.com/goran-ethernal/ChainIndexor/internal/indexer"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/alert"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
 	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -45,6 +50,10 @@ func (m *mockIndexer) GetName() string {
 	return "mockIndexer"
 }
 
+func (m *mockIndexer) Ping(ctx context.Context) error {
+	return nil
+}
+
 func TestDownloaderCreation(t *testing.T) {
 	log, err := logger.NewLogger("info", true)
 	require.NoError(t, err)
@@ -84,7 +93,7 @@ func TestIndexerRegistration(t *testing.T) {
 		cfg:                cfg,
 		syncManager:        sm,
 		log:                log.WithComponent("downloader"),
-		coordinator:        indexer.NewIndexerCoordinator(),
+		coordinator:        indexer.NewIndexerCoordinator(logger.NewNopLogger()),
 		addresses:          make([]common.Address, 0),
 		topics:             make([][]common.Hash, 0),
 		addressStartBlocks: make(map[common.Address]uint64),
@@ -194,3 +203,180 @@ func TestReorgErrorDetection(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckAutoRecovery(t *testing.T) {
+	t.Parallel()
+
+	reorgErr := &reorg.ReorgDetectedError{FirstReorgBlock: 95, Details: "test"}
+	autoRecovery, noAutoRecovery := true, false
+
+	// A config without auto_recovery recovers reorgs within the default max depth
+	defaults := config.DownloaderConfig{}
+	defaults.ApplyDefaults()
+
+	tests := []struct {
+		name             string
+		cfg              config.DownloaderConfig
+		lastIndexedBlock uint64
+		expectedErr      string
+		depthExceeded    bool
+	}{
+		{
+			name:             "auto recovery disabled",
+			cfg:              config.DownloaderConfig{AutoRecovery: &noAutoRecovery},
+			lastIndexedBlock: 100,
+			expectedErr:      "auto recovery is disabled",
+		},
+		{
+			name:             "auto recovery enabled by default",
+			cfg:              defaults,
+			lastIndexedBlock: 100,
+		},
+		{
+			name:             "reorg within max depth",
+			cfg:              config.DownloaderConfig{AutoRecovery: &autoRecovery, MaxAutoRecoveryDepth: 6},
+			lastIndexedBlock: 100,
+		},
+		{
+			name:             "reorg deeper than max depth",
+			cfg:              config.DownloaderConfig{AutoRecovery: &autoRecovery, MaxAutoRecoveryDepth: 5},
+			lastIndexedBlock: 100,
+			expectedErr:      "reorg depth 6 exceeds max auto recovery depth 5",
+			depthExceeded:    true,
+		},
+		{
+			name:             "101-block reorg deeper than max depth",
+			cfg:              config.DownloaderConfig{AutoRecovery: &autoRecovery, MaxAutoRecoveryDepth: 100},
+			lastIndexedBlock: 195,
+			expectedErr:      "reorg depth 101 exceeds max auto recovery depth 100",
+			depthExceeded:    true,
+		},
+		{
+			name:             "reorg in blocks not indexed yet",
+			cfg:              config.DownloaderConfig{AutoRecovery: &autoRecovery, MaxAutoRecoveryDepth: 1},
+			lastIndexedBlock: 90,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &Downloader{cfg: tt.cfg}
+
+			err := d.checkAutoRecovery(reorgErr, tt.lastIndexedBlock)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, tt.expectedErr)
+			require.Equal(t, tt.depthExceeded, errors.Is(err, reorg.ErrReorgDepthExceeded))
+
+			// The reorg error is preserved for callers deciding how to recover
+			var wrapped *reorg.ReorgDetectedError
+			require.ErrorAs(t, err, &wrapped)
+			require.Equal(t, uint64(95), wrapped.FirstReorgBlock)
+		})
+	}
+}
+
+func TestCheckReorgDepth(t *testing.T) {
+	t.Parallel()
+
+	noAutoRecovery := false
+
+	tests := []struct {
+		name             string
+		cfg              config.DownloaderConfig
+		firstReorgBlock  uint64
+		lastIndexedBlock uint64
+		expectedErr      string
+	}{
+		{
+			name:             "unlimited",
+			cfg:              config.DownloaderConfig{},
+			firstReorgBlock:  95,
+			lastIndexedBlock: 195,
+		},
+		{
+			name:             "reorg within max depth",
+			cfg:              config.DownloaderConfig{MaxReorgDepth: 101},
+			firstReorgBlock:  95,
+			lastIndexedBlock: 195,
+		},
+		{
+			name:             "101-block reorg deeper than max depth",
+			cfg:              config.DownloaderConfig{MaxReorgDepth: 100},
+			firstReorgBlock:  95,
+			lastIndexedBlock: 195,
+			expectedErr:      "reorg depth 101 exceeds max reorg depth 100",
+		},
+		{
+			name:             "limit applies without auto recovery",
+			cfg:              config.DownloaderConfig{MaxReorgDepth: 100, AutoRecovery: &noAutoRecovery},
+			firstReorgBlock:  95,
+			lastIndexedBlock: 195,
+			expectedErr:      "reorg depth 101 exceeds max reorg depth 100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			alerts := alertmocks.NewManager(t)
+			d := &Downloader{cfg: tt.cfg, alerts: alerts, log: logger.NewNopLogger()}
+			reorgErr := &reorg.ReorgDetectedError{FirstReorgBlock: tt.firstReorgBlock, Details: "test"}
+
+			if tt.expectedErr == "" {
+				require.NoError(t, d.checkReorgDepth(ctx, reorgErr, tt.lastIndexedBlock))
+				return
+			}
+
+			// Operators are alerted of the halted downloader
+			alerts.EXPECT().Trigger(ctx, mock.MatchedBy(func(a alert.Alert) bool {
+				return a.Type == alert.TypeReorgDepthExceeded &&
+					a.Details["reorg_depth"] == uint64(101) &&
+					a.RecommendedAction != ""
+			})).Return(nil).Once()
+
+			err := d.checkReorgDepth(ctx, reorgErr, tt.lastIndexedBlock)
+			require.ErrorContains(t, err, tt.expectedErr)
+			require.ErrorIs(t, err, reorg.ErrReorgDepthExceeded)
+
+			var wrapped *reorg.ReorgDetectedError
+			require.ErrorAs(t, err, &wrapped)
+			require.Equal(t, tt.firstReorgBlock, wrapped.FirstReorgBlock)
+		})
+	}
+}
+
+func TestDownloader_WaitForDrain(t *testing.T) {
+	t.Run("not started", func(t *testing.T) {
+		d := &Downloader{}
+		require.NoError(t, d.WaitForDrain(context.Background()))
+	})
+
+	t.Run("still downloading", func(t *testing.T) {
+		d := &Downloader{downloadDone: make(chan struct{})}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, d.WaitForDrain(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("drained", func(t *testing.T) {
+		done := make(chan struct{})
+		d := &Downloader{downloadDone: done}
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			close(done)
+		}()
+
+		require.NoError(t, d.WaitForDrain(context.Background()))
+	})
+}
@@ -0,0 +1,66 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/fetcher"
+	"github.com/goran-ethernal/ChainIndexor/internal/fetcher/store"
+	internaltypes "github.com/goran-ethernal/ChainIndexor/internal/types"
+	fch "github.com/goran-ethernal/ChainIndexor/pkg/fetcher"
+	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
+	"github.com/goran-ethernal/ChainIndexor/pkg/rpc"
+)
+
+// DryRun fetches the logs of the registered indexers in the first block the downloader would index,
+// to check the RPC connection and the indexers' filters without writing to the database.
+// The logs are discarded instead of stored, and the block is not recorded by the reorg detector.
+// The logs are not handed to the indexers.
+func (d *Downloader) DryRun(ctx context.Context) (*fch.FetchResult, error) {
+	finality, err := internaltypes.ParseBlockFinality(d.cfg.Finality)
+	if err != nil {
+		return nil, fmt.Errorf("invalid finality configuration: %w", err)
+	}
+
+	fetcherCfg, _ := d.logFetcherConfig(finality)
+	logFetcher := fetcher.NewLogFetcher(fetcherCfg, d.log, d.rpc, &dryRunDetector{rpc: d.rpc}, store.NewNoopLogStore())
+
+	startBlock := d.getDownloaderStartBlock()
+	d.log.Infof("dry run: fetching block %d", startBlock)
+
+	return logFetcher.FetchRange(ctx, startBlock, startBlock)
+}
+
+var _ reorg.Detector = (*dryRunDetector)(nil)
+
+// dryRunDetector returns the headers of the fetched blocks without verifying or recording them,
+// so a dry run leaves the reorg detector's tables untouched.
+type dryRunDetector struct {
+	rpc rpc.EthClient
+}
+
+func (r *dryRunDetector) VerifyAndRecordBlocks(
+	ctx context.Context,
+	_ []types.Log,
+	fromBlock, toBlock uint64,
+) ([]*types.Header, error) {
+	blockNums := make([]uint64, 0, toBlock-fromBlock+1)
+	for blockNum := fromBlock; blockNum <= toBlock; blockNum++ {
+		blockNums = append(blockNums, blockNum)
+	}
+
+	return r.rpc.BatchGetBlockHeaders(ctx, blockNums)
+}
+
+func (r *dryRunDetector) HandleReorg(context.Context, uint64) error {
+	return nil
+}
+
+func (r *dryRunDetector) GetReorgHistory(context.Context, uint64, uint64) ([]reorg.ReorgEvent, error) {
+	return []reorg.ReorgEvent{}, nil
+}
+
+func (r *dryRunDetector) Close() error {
+	return nil
+}
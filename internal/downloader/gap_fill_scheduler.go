@@ -0,0 +1,263 @@
+package downloader
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	fch "github.com/goran-ethernal/ChainIndexor/pkg/fetcher"
+	pkgstore "github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
+	idx "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"golang.org/x/sync/errgroup"
+)
+
+// CoverageGap is a block range of an address that the log store has not fetched logs for,
+// for at least one of the given topics.
+type CoverageGap struct {
+	Address   common.Address
+	Topics    []common.Hash
+	FromBlock uint64
+	ToBlock   uint64
+}
+
+// Size returns the number of blocks in the gap.
+func (g CoverageGap) Size() uint64 {
+	return g.ToBlock - g.FromBlock + 1
+}
+
+// GapFetchFunc fetches and stores the logs of the given addresses and topics in a block range.
+type GapFetchFunc func(
+	ctx context.Context,
+	fromBlock, toBlock uint64,
+	addresses []common.Address,
+	topics [][]common.Hash,
+) (*fch.FetchResult, error)
+
+// GapHandleFunc processes the logs fetched for a chunk of a coverage gap.
+type GapHandleFunc func(ctx context.Context, result *fch.FetchResult) error
+
+// GapFillScheduler fills the coverage gaps of the registered indexers at startup, before the
+// downloader resumes indexing new blocks. Gaps appear when a process stops between fetching
+// and recording coverage, or when the indexer configuration changes between runs.
+// Gaps are filled largest first, with a bounded number of gaps filled concurrently.
+type GapFillScheduler struct {
+	logStore      pkgstore.LogStore
+	fetch         GapFetchFunc
+	handle        GapHandleFunc
+	chunkSize     uint64
+	maxConcurrent int
+	chainID       uint64
+	log           *logger.Logger
+}
+
+// NewGapFillScheduler creates a GapFillScheduler that fetches gaps in chunks of chunkSize blocks,
+// filling up to maxConcurrent gaps at a time. Its metrics are labelled with chainID.
+func NewGapFillScheduler(
+	chainID uint64,
+	logStore pkgstore.LogStore,
+	fetch GapFetchFunc,
+	handle GapHandleFunc,
+	chunkSize uint64,
+	maxConcurrent int,
+	log *logger.Logger,
+) *GapFillScheduler {
+	return &GapFillScheduler{
+		logStore:      logStore,
+		fetch:         fetch,
+		handle:        handle,
+		chunkSize:     max(chunkSize, 1),
+		maxConcurrent: max(maxConcurrent, 1),
+		chainID:       chainID,
+		log:           log,
+	}
+}
+
+// FindGaps returns the coverage gaps of the given indexers up to upToBlock, largest first.
+// Every disjoint block range a topic is missing is a gap of its own, so only the holes in the
+// coverage are fetched. Overlapping gaps of an address, including those of indexers sharing it,
+// are merged, so every block of an address is fetched once.
+// Blocks before an indexer's start block or pruned by the retention policy are not gaps.
+func (s *GapFillScheduler) FindGaps(
+	ctx context.Context,
+	indexers []idx.Indexer,
+	upToBlock uint64,
+) ([]CoverageGap, error) {
+	gaps := make(map[common.Address][]CoverageGap)
+
+	for _, indexer := range indexers {
+		addresses, topics := indexerFilter(indexer)
+		if len(addresses) == 0 {
+			continue
+		}
+
+		unsynced, err := s.logStore.GetUnsyncedTopics(ctx, addresses, topics, upToBlock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get unsynced topics of indexer %s: %w", indexer.GetName(), err)
+		}
+
+		for address, topicRanges := range unsynced.MissingRanges(upToBlock) {
+			for topic, ranges := range topicRanges {
+				for _, r := range ranges {
+					fromBlock := max(r.FromBlock, indexer.StartBlock())
+					if fromBlock > r.ToBlock {
+						continue
+					}
+
+					gaps[address] = append(gaps[address], CoverageGap{
+						Address:   address,
+						Topics:    []common.Hash{topic},
+						FromBlock: fromBlock,
+						ToBlock:   r.ToBlock,
+					})
+				}
+			}
+		}
+	}
+
+	result := make([]CoverageGap, 0, len(gaps))
+	for _, addressGaps := range gaps {
+		result = append(result, mergeGaps(addressGaps)...)
+	}
+
+	slices.SortFunc(result, func(a, b CoverageGap) int {
+		if c := cmp.Compare(b.Size(), a.Size()); c != 0 {
+			return c
+		}
+		if c := a.Address.Cmp(b.Address); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.FromBlock, b.FromBlock)
+	})
+
+	return result, nil
+}
+
+// mergeGaps merges the overlapping gaps of an address into one gap of all their topics.
+func mergeGaps(gaps []CoverageGap) []CoverageGap {
+	slices.SortFunc(gaps, func(a, b CoverageGap) int {
+		return cmp.Compare(a.FromBlock, b.FromBlock)
+	})
+
+	merged := make([]CoverageGap, 0, len(gaps))
+	for _, gap := range gaps {
+		if len(merged) == 0 || gap.FromBlock > merged[len(merged)-1].ToBlock {
+			merged = append(merged, gap)
+			continue
+		}
+
+		last := &merged[len(merged)-1]
+		last.ToBlock = max(last.ToBlock, gap.ToBlock)
+		for _, topic := range gap.Topics {
+			if !slices.Contains(last.Topics, topic) {
+				last.Topics = append(last.Topics, topic)
+			}
+		}
+	}
+
+	for i := range merged {
+		slices.SortFunc(merged[i].Topics, common.Hash.Cmp)
+	}
+
+	return merged
+}
+
+// Run fills the coverage gaps of the given indexers up to upToBlock. It returns once every
+// gap is filled, or with the first error, including cancellation of ctx.
+func (s *GapFillScheduler) Run(ctx context.Context, indexers []idx.Indexer, upToBlock uint64) error {
+	gaps, err := s.FindGaps(ctx, indexers, upToBlock)
+	if err != nil {
+		return err
+	}
+
+	CoverageGapsRemainingSet(s.chainID, len(gaps))
+	if len(gaps) == 0 {
+		return nil
+	}
+
+	s.log.Infof("found %d coverage gaps up to block %d, filling them before indexing new blocks",
+		len(gaps), upToBlock)
+
+	var remaining atomic.Int64
+	remaining.Store(int64(len(gaps)))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.maxConcurrent)
+
+	for _, gap := range gaps {
+		g.Go(func() error {
+			if err := s.fillGap(ctx, gap); err != nil {
+				return fmt.Errorf("failed to fill coverage gap of %s from block %d to %d: %w",
+					gap.Address.Hex(), gap.FromBlock, gap.ToBlock, err)
+			}
+
+			CoverageGapsRemainingSet(s.chainID, int(remaining.Add(-1)))
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	s.log.Infof("filled %d coverage gaps", len(gaps))
+
+	return nil
+}
+
+// fillGap fetches a gap chunk by chunk and hands every fetched chunk to the handler.
+func (s *GapFillScheduler) fillGap(ctx context.Context, gap CoverageGap) error {
+	s.log.Debugf("filling coverage gap: address=%s, from_block=%d, to_block=%d, topics=%d",
+		gap.Address.Hex(), gap.FromBlock, gap.ToBlock, len(gap.Topics))
+
+	addresses := []common.Address{gap.Address}
+	topics := [][]common.Hash{gap.Topics}
+
+	for fromBlock := gap.FromBlock; fromBlock <= gap.ToBlock; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		toBlock := min(fromBlock+s.chunkSize-1, gap.ToBlock)
+		result, err := s.fetch(ctx, fromBlock, toBlock, addresses, topics)
+		if err != nil {
+			return err
+		}
+
+		if err := s.handle(ctx, result); err != nil {
+			return err
+		}
+
+		// The fetcher may narrow the range if the RPC limits the number of results
+		fromBlock = result.ToBlock + 1
+	}
+
+	return nil
+}
+
+// indexerFilter returns the addresses and topics an indexer is interested in, in a stable order.
+// Addresses the indexer wants all events of are skipped, as their coverage is not tracked by topic.
+func indexerFilter(indexer idx.Indexer) ([]common.Address, [][]common.Hash) {
+	eventsToIndex := indexer.EventsToIndex()
+
+	addresses := make([]common.Address, 0, len(eventsToIndex))
+	for address, topicSet := range eventsToIndex {
+		if len(topicSet) > 0 {
+			addresses = append(addresses, address)
+		}
+	}
+	slices.SortFunc(addresses, common.Address.Cmp)
+
+	topics := make([][]common.Hash, len(addresses))
+	for i, address := range addresses {
+		topics[i] = slices.SortedFunc(maps.Keys(eventsToIndex[address]), common.Hash.Cmp)
+	}
+
+	return addresses, topics
+}
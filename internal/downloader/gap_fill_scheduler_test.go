@@ -0,0 +1,229 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/fetcher/store"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/internal/migrations"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	fch "github.com/goran-ethernal/ChainIndexor/pkg/fetcher"
+	idx "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	indexermocks "github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	gapAddressA = common.HexToAddress("0xaaaa")
+	gapAddressB = common.HexToAddress("0xbbbb")
+	gapAddressC = common.HexToAddress("0xcccc")
+
+	gapTopic1 = common.HexToHash("0x01")
+	gapTopic2 = common.HexToHash("0x02")
+	gapTopic3 = common.HexToHash("0x03")
+	gapTopic4 = common.HexToHash("0x04")
+)
+
+// newGapTestStore creates a log store covering topic 1 of address A up to block 99,
+// topic 2 of address B up to block 199 and topic 4 of address C up to block 149.
+func newGapTestStore(t *testing.T) *store.LogStore {
+	t.Helper()
+
+	dbConfig := config.DatabaseConfig{Path: path.Join(t.TempDir(), "downloader.db")}
+	dbConfig.ApplyDefaults()
+	require.NoError(t, migrations.RunMigrations(dbConfig))
+
+	database, err := db.NewSQLiteDBFromConfig(dbConfig)
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	logStore := store.NewLogStore(database, logger.NewNopLogger(), dbConfig, nil, &db.NoOpMaintenance{})
+
+	for _, coverage := range []struct {
+		address common.Address
+		topic   common.Hash
+		toBlock uint64
+	}{
+		{gapAddressA, gapTopic1, 99},
+		{gapAddressB, gapTopic2, 199},
+		{gapAddressC, gapTopic4, 149},
+	} {
+		require.NoError(t, logStore.StoreLogs(t.Context(),
+			[]common.Address{coverage.address}, [][]common.Hash{{coverage.topic}}, nil, 0, coverage.toBlock))
+	}
+
+	return logStore
+}
+
+func newGapTestIndexer(
+	t *testing.T,
+	name string,
+	startBlock uint64,
+	events map[common.Address]map[common.Hash]struct{},
+) idx.Indexer {
+	t.Helper()
+
+	indexer := indexermocks.NewIndexer(t)
+	indexer.EXPECT().GetName().Return(name).Maybe()
+	indexer.EXPECT().StartBlock().Return(startBlock).Maybe()
+	indexer.EXPECT().EventsToIndex().Return(events).Maybe()
+
+	return indexer
+}
+
+func newGapTestIndexers(t *testing.T) []idx.Indexer {
+	t.Helper()
+
+	return []idx.Indexer{
+		newGapTestIndexer(t, "first", 0, map[common.Address]map[common.Hash]struct{}{
+			gapAddressA: {gapTopic1: {}},
+		}),
+		newGapTestIndexer(t, "second", 50, map[common.Address]map[common.Hash]struct{}{
+			gapAddressA: {gapTopic1: {}, gapTopic3: {}},
+			gapAddressB: {gapTopic2: {}},
+			gapAddressC: {gapTopic4: {}},
+		}),
+		// Indexers of all events of an address are not tracked by topic
+		newGapTestIndexer(t, "all-events", 0, map[common.Address]map[common.Hash]struct{}{
+			gapAddressB: {},
+		}),
+	}
+}
+
+func TestGapFillScheduler_FindGaps(t *testing.T) {
+	t.Parallel()
+
+	scheduler := NewGapFillScheduler(0, newGapTestStore(t), nil, nil, 100, 2, logger.NewNopLogger())
+
+	gaps, err := scheduler.FindGaps(t.Context(), newGapTestIndexers(t), 199)
+	require.NoError(t, err)
+	require.Equal(t, []CoverageGap{
+		// The second indexer has no coverage of topic 3 since its start block
+		{Address: gapAddressA, Topics: []common.Hash{gapTopic1, gapTopic3}, FromBlock: 50, ToBlock: 199},
+		{Address: gapAddressC, Topics: []common.Hash{gapTopic4}, FromBlock: 150, ToBlock: 199},
+	}, gaps)
+}
+
+func TestGapFillScheduler_FindGapsHoles(t *testing.T) {
+	t.Parallel()
+
+	logStore := newGapTestStore(t)
+
+	// Topic 1 of address A also covers 150-159 and 180-189, leaving holes in between
+	for _, r := range [][2]uint64{{150, 159}, {180, 189}} {
+		require.NoError(t, logStore.StoreLogs(t.Context(),
+			[]common.Address{gapAddressA}, [][]common.Hash{{gapTopic1}}, nil, r[0], r[1]))
+	}
+
+	indexers := []idx.Indexer{
+		newGapTestIndexer(t, "first", 0, map[common.Address]map[common.Hash]struct{}{
+			gapAddressA: {gapTopic1: {}},
+		}),
+		// Topic 3 is missing from block 170, overlapping the last two holes of topic 1
+		newGapTestIndexer(t, "second", 170, map[common.Address]map[common.Hash]struct{}{
+			gapAddressA: {gapTopic3: {}},
+		}),
+	}
+
+	scheduler := NewGapFillScheduler(0, logStore, nil, nil, 100, 2, logger.NewNopLogger())
+
+	gaps, err := scheduler.FindGaps(t.Context(), indexers, 199)
+	require.NoError(t, err)
+	require.Equal(t, []CoverageGap{
+		{Address: gapAddressA, Topics: []common.Hash{gapTopic1}, FromBlock: 100, ToBlock: 149},
+		{Address: gapAddressA, Topics: []common.Hash{gapTopic1, gapTopic3}, FromBlock: 160, ToBlock: 199},
+	}, gaps)
+}
+
+func TestGapFillScheduler_Run(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		fetched = make(map[common.Address][][2]uint64)
+		handled int
+	)
+
+	fetch := func(
+		_ context.Context,
+		fromBlock, toBlock uint64,
+		addresses []common.Address,
+		_ [][]common.Hash,
+	) (*fch.FetchResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		require.Len(t, addresses, 1)
+
+		// The RPC limits the results of the first request, narrowing its range
+		if len(fetched) == 0 {
+			toBlock = fromBlock + 9
+		}
+		fetched[addresses[0]] = append(fetched[addresses[0]], [2]uint64{fromBlock, toBlock})
+
+		return &fch.FetchResult{FromBlock: fromBlock, ToBlock: toBlock}, nil
+	}
+
+	handle := func(_ context.Context, _ *fch.FetchResult) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		handled++
+
+		return nil
+	}
+
+	scheduler := NewGapFillScheduler(0, newGapTestStore(t), fetch, handle, 60, 1, logger.NewNopLogger())
+	require.NoError(t, scheduler.Run(t.Context(), newGapTestIndexers(t), 199))
+
+	// Gaps are filled largest first, chunk by chunk
+	require.Equal(t, map[common.Address][][2]uint64{
+		gapAddressA: {{50, 59}, {60, 119}, {120, 179}, {180, 199}},
+		gapAddressC: {{150, 199}},
+	}, fetched)
+	require.Equal(t, 5, handled)
+}
+
+func TestGapFillScheduler_RunErrors(t *testing.T) {
+	t.Parallel()
+
+	handle := func(_ context.Context, _ *fch.FetchResult) error { return nil }
+
+	t.Run("fetch error", func(t *testing.T) {
+		t.Parallel()
+
+		fetchErr := errors.New("rpc unavailable")
+		fetch := func(context.Context, uint64, uint64, []common.Address, [][]common.Hash) (*fch.FetchResult, error) {
+			return nil, fetchErr
+		}
+
+		scheduler := NewGapFillScheduler(0, newGapTestStore(t), fetch, handle, 60, 2, logger.NewNopLogger())
+		err := scheduler.Run(t.Context(), newGapTestIndexers(t), 199)
+		require.ErrorIs(t, err, fetchErr)
+		require.ErrorContains(t, err, "failed to fill coverage gap")
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		t.Parallel()
+
+		var fetches atomic.Int32
+		fetch := func(context.Context, uint64, uint64, []common.Address, [][]common.Hash) (*fch.FetchResult, error) {
+			fetches.Add(1)
+			return &fch.FetchResult{}, nil
+		}
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		scheduler := NewGapFillScheduler(0, newGapTestStore(t), fetch, handle, 60, 2, logger.NewNopLogger())
+		require.ErrorIs(t, scheduler.Run(ctx, newGapTestIndexers(t), 199), context.Canceled)
+		require.Zero(t, fetches.Load())
+	})
+}
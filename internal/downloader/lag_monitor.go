@@ -0,0 +1,150 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/alert"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+)
+
+// lagCheckInterval is how often lag is re-evaluated between observations,
+// so that an indexer that stops making progress still triggers an alert.
+const lagCheckInterval = 10 * time.Second
+
+// lagState tracks the lag of a single indexer.
+type lagState struct {
+	cfg           *config.LagAlertConfig
+	currentBlock  uint64
+	targetBlock   uint64
+	exceededSince time.Time
+	alerted       bool
+}
+
+// LagMonitor triggers an alert when an indexer's lag behind the target block
+// exceeds its configured threshold for longer than the sustained duration.
+type LagMonitor struct {
+	mu     sync.Mutex
+	states map[string]*lagState
+	alerts alert.Manager
+	log    *logger.Logger
+	now    func() time.Time
+}
+
+// NewLagMonitor creates a LagMonitor for all indexers with a lag alert configured.
+func NewLagMonitor(indexers []config.IndexerConfig, alerts alert.Manager, log *logger.Logger) *LagMonitor {
+	states := make(map[string]*lagState)
+	for _, idx := range indexers {
+		if idx.LagAlert != nil {
+			states[idx.Name] = &lagState{cfg: idx.LagAlert}
+		}
+	}
+
+	return &LagMonitor{
+		states: states,
+		alerts: alerts,
+		log:    log,
+		now:    time.Now,
+	}
+}
+
+// Enabled reports whether any indexer has a lag alert configured.
+func (m *LagMonitor) Enabled() bool {
+	return len(m.states) > 0
+}
+
+// Observe records the current and target block of an indexer and evaluates its lag.
+// Indexers without a lag alert configured are ignored.
+func (m *LagMonitor) Observe(ctx context.Context, indexer string, currentBlock, targetBlock uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[indexer]
+	if !ok {
+		return
+	}
+
+	state.currentBlock = currentBlock
+	state.targetBlock = targetBlock
+	m.evaluateLocked(ctx, indexer, state)
+}
+
+// Run periodically re-evaluates the lag of all monitored indexers until the context is cancelled.
+func (m *LagMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(lagCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+func (m *LagMonitor) check(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for indexer, state := range m.states {
+		m.evaluateLocked(ctx, indexer, state)
+	}
+}
+
+func (m *LagMonitor) evaluateLocked(ctx context.Context, indexer string, state *lagState) {
+	var lag uint64
+	if state.targetBlock > state.currentBlock {
+		lag = state.targetBlock - state.currentBlock
+	}
+
+	now := m.now()
+
+	if lag <= state.cfg.MaxLagBlocks {
+		if state.alerted {
+			m.log.Infof("indexer %s recovered from lag: lag=%d blocks, max_lag=%d blocks",
+				indexer, lag, state.cfg.MaxLagBlocks)
+		}
+
+		state.exceededSince = time.Time{}
+		state.alerted = false
+
+		return
+	}
+
+	if state.exceededSince.IsZero() {
+		state.exceededSince = now
+	}
+
+	if state.alerted || now.Sub(state.exceededSince) < state.cfg.SustainedDuration.Duration {
+		return
+	}
+
+	err := m.alerts.Trigger(ctx, alert.Alert{
+		Type:    alert.TypeIndexerLag,
+		Indexer: indexer,
+		Message: fmt.Sprintf("indexer %s is %d blocks behind the chain (max %d) for more than %s",
+			indexer, lag, state.cfg.MaxLagBlocks, state.cfg.SustainedDuration.Duration),
+		RecommendedAction: "check RPC endpoint health and rate limits, look for errors in the indexer logs, " +
+			"and consider increasing downloader.chunk_size if the indexer is only syncing slowly",
+		Details: map[string]any{
+			"lag_blocks":     lag,
+			"max_lag_blocks": state.cfg.MaxLagBlocks,
+			"current_block":  state.currentBlock,
+			"target_block":   state.targetBlock,
+			"lagging_since":  state.exceededSince,
+		},
+		Timestamp: now,
+	})
+	if err != nil {
+		// Leave alerted unset so the alert is retried on the next evaluation
+		m.log.Errorf("failed to trigger lag alert for indexer %s: %v", indexer, err)
+		return
+	}
+
+	state.alerted = true
+}
@@ -0,0 +1,117 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	alertmocks "github.com/goran-ethernal/ChainIndexor/internal/alert/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/alert"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLagMonitor(t *testing.T, alerts alert.Manager) (*LagMonitor, *time.Time) {
+	t.Helper()
+
+	indexers := []config.IndexerConfig{
+		{
+			Name: "lagging",
+			LagAlert: &config.LagAlertConfig{
+				MaxLagBlocks:      100,
+				SustainedDuration: common.NewDuration(time.Minute),
+			},
+		},
+		{Name: "unmonitored"},
+	}
+
+	now := time.Unix(1_700_000_000, 0)
+	monitor := NewLagMonitor(indexers, alerts, logger.NewNopLogger())
+	monitor.now = func() time.Time { return now }
+
+	return monitor, &now
+}
+
+func TestLagMonitor_AlertsAfterSustainedLag(t *testing.T) {
+	t.Parallel()
+
+	alerts := alertmocks.NewManager(t)
+	monitor, now := newTestLagMonitor(t, alerts)
+	ctx := context.Background()
+
+	require.True(t, monitor.Enabled())
+
+	// Within threshold
+	monitor.Observe(ctx, "lagging", 1000, 1100)
+
+	// Lag exceeded, but not for long enough
+	monitor.Observe(ctx, "lagging", 1000, 2000)
+	*now = now.Add(30 * time.Second)
+	monitor.check(ctx)
+
+	// Sustained lag triggers a single alert
+	alerts.EXPECT().Trigger(ctx, mock.MatchedBy(func(a alert.Alert) bool {
+		return a.Type == alert.TypeIndexerLag &&
+			a.Indexer == "lagging" &&
+			a.Details["lag_blocks"] == uint64(1500) &&
+			a.RecommendedAction != ""
+	})).Return(nil).Once()
+
+	*now = now.Add(30 * time.Second)
+	monitor.Observe(ctx, "lagging", 1000, 2500)
+	*now = now.Add(time.Minute)
+	monitor.check(ctx)
+
+	// Unmonitored indexers are ignored
+	monitor.Observe(ctx, "unmonitored", 0, 1_000_000)
+}
+
+func TestLagMonitor_RecoveryResetsAlert(t *testing.T) {
+	t.Parallel()
+
+	alerts := alertmocks.NewManager(t)
+	monitor, now := newTestLagMonitor(t, alerts)
+	ctx := context.Background()
+
+	alerts.EXPECT().Trigger(ctx, mock.Anything).Return(nil).Twice()
+
+	monitor.Observe(ctx, "lagging", 0, 1000)
+	*now = now.Add(time.Minute)
+	monitor.check(ctx)
+
+	// Recovering resets the state, so a new sustained lag alerts again
+	monitor.Observe(ctx, "lagging", 1000, 1000)
+	monitor.Observe(ctx, "lagging", 1000, 3000)
+	*now = now.Add(59 * time.Second)
+	monitor.check(ctx)
+	*now = now.Add(time.Second)
+	monitor.check(ctx)
+}
+
+func TestLagMonitor_RetriesFailedAlert(t *testing.T) {
+	t.Parallel()
+
+	alerts := alertmocks.NewManager(t)
+	monitor, now := newTestLagMonitor(t, alerts)
+	ctx := context.Background()
+
+	alerts.EXPECT().Trigger(ctx, mock.Anything).Return(errors.New("webhook unavailable")).Once()
+	alerts.EXPECT().Trigger(ctx, mock.Anything).Return(nil).Once()
+
+	monitor.Observe(ctx, "lagging", 0, 1000)
+	*now = now.Add(time.Minute)
+	monitor.check(ctx)
+	monitor.check(ctx)
+	monitor.check(ctx)
+}
+
+func TestLagMonitor_Disabled(t *testing.T) {
+	t.Parallel()
+
+	monitor := NewLagMonitor([]config.IndexerConfig{{Name: "test"}}, nil, logger.NewNopLogger())
+	require.False(t, monitor.Enabled())
+}
@@ -0,0 +1,130 @@
+package downloader
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/fetcher/store"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/internal/migrations"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+)
+
+// mergeTable is a downloader table copied by MergeDatabases.
+type mergeTable struct {
+	name string
+
+	// columns are the columns copied from the source database. Autoincrement ids are
+	// left out, so the target assigns new ids instead of ignoring rows with clashing ids
+	columns []string
+}
+
+// mergeTables are the downloader tables that hold indexed data, in copy order.
+var mergeTables = []mergeTable{
+	{
+		name: "event_logs",
+		columns: []string{
+			"address", "block_number", "block_hash", "tx_hash", "tx_index", "log_index",
+			"topic0", "topic1", "topic2", "topic3", "data", "timestamp", "created_at",
+		},
+	},
+	{name: "log_coverage", columns: []string{"address", "from_block", "to_block", "created_at"}},
+	{name: "topic_coverage", columns: []string{"address", "topic0", "from_block", "to_block", "created_at"}},
+	{name: "block_hashes", columns: []string{"block_number", "block_hash", "parent_hash", "created_at"}},
+}
+
+// MergeDatabases copies the logs, coverage ranges and block hashes of the downloader database
+// at sourcePath into the target downloader database, e.g. to combine the results of backfill
+// nodes that indexed separate block ranges. Rows already present in the target are kept.
+// The coverage of the target is compacted afterwards. The sync state of the target is not changed.
+func MergeDatabases(ctx context.Context, sourcePath string, target config.DatabaseConfig, log *logger.Logger) error {
+	// The source database is attached to the target, which only SQLite supports
+	if target.Driver == config.DBDriverPostgres {
+		return fmt.Errorf("merging requires a sqlite target database, got driver %q", target.Driver)
+	}
+
+	if _, err := os.Stat(sourcePath); err != nil {
+		return fmt.Errorf("failed to open source database: %w", err)
+	}
+
+	if _, err := os.Stat(target.Path); err != nil {
+		return fmt.Errorf("failed to open target database: %w", err)
+	}
+
+	// The source database is only read, so it must already contain the merged tables
+	if err := migrations.RunMigrations(target); err != nil {
+		return fmt.Errorf("failed to run target database migrations: %w", err)
+	}
+
+	database, err := db.NewSQLiteDBFromConfig(target)
+	if err != nil {
+		return fmt.Errorf("failed to open target database: %w", err)
+	}
+	defer database.Close()
+
+	if err := copyTables(ctx, database, sourcePath, log); err != nil {
+		return err
+	}
+
+	logStore := store.NewLogStore(database, log, target, nil, &db.NoOpMaintenance{})
+	if err := logStore.CompactCoverage(ctx); err != nil {
+		return fmt.Errorf("failed to compact coverage: %w", err)
+	}
+
+	return nil
+}
+
+// copyTables attaches the source database to a connection of the target database
+// and copies the merge tables in a single transaction.
+func copyTables(ctx context.Context, database *sql.DB, sourcePath string, log *logger.Logger) (err error) {
+	// ATTACH only applies to the connection it runs on, so pin one for the whole copy
+	conn, err := database.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to target database: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS source", sourcePath); err != nil {
+		return fmt.Errorf("failed to attach source database: %w", err)
+	}
+	defer func() {
+		if _, detachErr := conn.ExecContext(context.WithoutCancel(ctx), "DETACH DATABASE source"); detachErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to detach source database: %w", detachErr))
+		}
+	}()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Errorf("failed to rollback transaction: %v", err)
+		}
+	}()
+
+	for _, table := range mergeTables {
+		columns := strings.Join(table.columns, ", ")
+
+		result, err := tx.ExecContext(ctx, fmt.Sprintf(
+			"INSERT OR IGNORE INTO main.%s (%s) SELECT %s FROM source.%s",
+			table.name, columns, columns, table.name))
+		if err != nil {
+			return fmt.Errorf("failed to copy %s: %w", table.name, err)
+		}
+
+		rowsCopied, _ := result.RowsAffected()
+		log.Infof("Copied %d rows into %s", rowsCopied, table.name)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
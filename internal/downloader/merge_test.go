@@ -0,0 +1,116 @@
+package downloader
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/fetcher/store"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/internal/migrations"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	pkgstore "github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
+	"github.com/stretchr/testify/require"
+)
+
+// createBackfillDB creates a downloader database with the logs of a backfill of [fromBlock, toBlock]
+// for a single address and topic.
+func createBackfillDB(t *testing.T, name string, address common.Address, topic common.Hash,
+	fromBlock, toBlock uint64, logs []types.Log) config.DatabaseConfig {
+	t.Helper()
+
+	dbConfig := config.DatabaseConfig{Path: path.Join(t.TempDir(), name)}
+	dbConfig.ApplyDefaults()
+	require.NoError(t, migrations.RunMigrations(dbConfig))
+
+	database, err := db.NewSQLiteDBFromConfig(dbConfig)
+	require.NoError(t, err)
+	defer database.Close()
+
+	logStore := store.NewLogStore(database, logger.NewNopLogger(), dbConfig, nil, &db.NoOpMaintenance{})
+
+	// Backfills store their range chunk by chunk
+	chunkSize := (toBlock - fromBlock + 1) / 2
+	for start := fromBlock; start <= toBlock; start += chunkSize {
+		end := min(start+chunkSize-1, toBlock)
+
+		var chunkLogs []types.Log
+		for _, log := range logs {
+			if log.BlockNumber >= start && log.BlockNumber <= end {
+				chunkLogs = append(chunkLogs, log)
+			}
+		}
+
+		require.NoError(t, logStore.StoreLogs(context.Background(),
+			[]common.Address{address}, [][]common.Hash{{topic}}, chunkLogs, start, end))
+	}
+
+	_, err = database.Exec("INSERT INTO block_hashes (block_number, block_hash, parent_hash) VALUES (?, ?, ?)",
+		toBlock, common.BigToHash(common.Big1).Hex(), common.Hash{}.Hex())
+	require.NoError(t, err)
+
+	return dbConfig
+}
+
+func TestMergeDatabases(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	address := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	topic := common.HexToHash("0x1234")
+
+	newLog := func(blockNum uint64, txHash string) types.Log {
+		return types.Log{
+			Address:     address,
+			Topics:      []common.Hash{topic},
+			BlockNumber: blockNum,
+			TxHash:      common.HexToHash(txHash),
+		}
+	}
+
+	target := createBackfillDB(t, "target.db", address, topic, 0, 99,
+		[]types.Log{newLog(10, "0x01"), newLog(60, "0x02")})
+	source := createBackfillDB(t, "source.db", address, topic, 100, 199,
+		[]types.Log{newLog(120, "0x03"), newLog(180, "0x04")})
+
+	require.NoError(t, MergeDatabases(ctx, source.Path, target, logger.NewNopLogger()))
+
+	// Merging the same source again does not duplicate rows
+	require.NoError(t, MergeDatabases(ctx, source.Path, target, logger.NewNopLogger()))
+
+	database, err := db.NewSQLiteDBFromConfig(target)
+	require.NoError(t, err)
+	defer database.Close()
+
+	logStore := store.NewLogStore(database, logger.NewNopLogger(), target, nil, &db.NoOpMaintenance{})
+
+	logs, coverage, err := logStore.GetLogs(ctx, address, 0, 199, nil)
+	require.NoError(t, err)
+	require.Len(t, logs, 4)
+	require.Equal(t, []pkgstore.CoverageRange{{FromBlock: 0, ToBlock: 199}}, coverage)
+
+	unsynced, err := logStore.GetUnsyncedTopics(ctx, []common.Address{address}, [][]common.Hash{{topic}}, 199)
+	require.NoError(t, err)
+	require.True(t, unsynced.IsEmpty())
+
+	var blockHashes int
+	require.NoError(t, database.QueryRow("SELECT COUNT(*) FROM block_hashes").Scan(&blockHashes))
+	require.Equal(t, 2, blockHashes)
+}
+
+func TestMergeDatabases_MissingDatabase(t *testing.T) {
+	t.Parallel()
+
+	target := config.DatabaseConfig{Path: path.Join(t.TempDir(), "target.db")}
+	target.ApplyDefaults()
+
+	err := MergeDatabases(context.Background(), "missing.db", target, logger.NewNopLogger())
+	require.ErrorContains(t, err, "failed to open source database")
+
+	source := createBackfillDB(t, "source.db", common.Address{}, common.Hash{}, 0, 9, nil)
+	err = MergeDatabases(context.Background(), source.Path, target, logger.NewNopLogger())
+	require.ErrorContains(t, err, "failed to open target database")
+}
@@ -0,0 +1,19 @@
+package downloader
+
+import (
+	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var coverageGapsRemaining = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "chainindexor_coverage_gaps_remaining",
+		Help: "Number of coverage gaps found at startup that are not filled yet",
+	},
+	[]string{"chain_id"},
+)
+
+func CoverageGapsRemainingSet(chainID uint64, gaps int) {
+	coverageGapsRemaining.WithLabelValues(metrics.ChainLabel(chainID)).Set(float64(gaps))
+}
@@ -0,0 +1,274 @@
+package downloader
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/downloader"
+	idx "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/goran-ethernal/ChainIndexor/pkg/rpc"
+)
+
+const (
+	// pendingEventTTL is how long the events of a pending transaction are kept when its logs
+	// are never indexed, e.g. because it was dropped from the mempool, replaced or reverted.
+	// It must exceed the time a transaction takes to reach the configured finality
+	pendingEventTTL = 30 * time.Minute
+
+	// pendingExpiryInterval is how often expired pending events are removed.
+	pendingExpiryInterval = time.Minute
+
+	// pendingTxBufferSize is the number of subscribed transactions buffered while
+	// earlier transactions are being simulated.
+	pendingTxBufferSize = 1024
+
+	// selectorSize is the size of the function selector that prefixes transaction calldata.
+	selectorSize = 4
+)
+
+// pendingTx holds the previewed events of a pending transaction.
+type pendingTx struct {
+	events []downloader.PendingEvent
+	seenAt time.Time
+}
+
+// PendingBlockMonitor previews the events of transactions in the node's mempool before they are
+// confirmed. Transactions that call, or pass in their calldata, an indexed contract are simulated
+// against the latest block, and the simulated logs that match an indexer are kept as pending
+// events until the transaction's confirmed logs are indexed.
+type PendingBlockMonitor struct {
+	client   rpc.PendingClient
+	indexers func() []idx.Indexer
+	log      *logger.Logger
+	now      func() time.Time
+
+	mu sync.RWMutex
+	// watched maps the indexed contracts and their topic0s to the names of the indexers of the event
+	watched map[common.Address]map[common.Hash][]string
+	txs     map[common.Hash]*pendingTx
+}
+
+// NewPendingBlockMonitor creates a PendingBlockMonitor for the events of the given indexers.
+// The indexers are read when Run starts, so indexers must be registered before then.
+func NewPendingBlockMonitor(
+	client rpc.PendingClient,
+	indexers func() []idx.Indexer,
+	log *logger.Logger,
+) *PendingBlockMonitor {
+	return &PendingBlockMonitor{
+		client:   client,
+		indexers: indexers,
+		log:      log,
+		now:      time.Now,
+		watched:  make(map[common.Address]map[common.Hash][]string),
+		txs:      make(map[common.Hash]*pendingTx),
+	}
+}
+
+// Run subscribes to pending transactions and previews their events until the context is
+// cancelled or the subscription fails.
+func (m *PendingBlockMonitor) Run(ctx context.Context) error {
+	m.watch(m.indexers())
+
+	ch := make(chan *types.Transaction, pendingTxBufferSize)
+	sub, err := m.client.SubscribePendingTransactions(ctx, ch)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to pending transactions: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	ticker := time.NewTicker(pendingExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			return fmt.Errorf("pending transaction subscription failed: %w", err)
+		case tx := <-ch:
+			m.processTransaction(ctx, tx)
+		case <-ticker.C:
+			m.expire()
+		}
+	}
+}
+
+// watch sets the contracts and events whose pending events are previewed.
+func (m *PendingBlockMonitor) watch(indexers []idx.Indexer) {
+	watched := make(map[common.Address]map[common.Hash][]string)
+	for _, indexer := range indexers {
+		for address, topics := range indexer.EventsToIndex() {
+			if _, ok := watched[address]; !ok {
+				watched[address] = make(map[common.Hash][]string)
+			}
+
+			for topic := range topics {
+				watched[address][topic] = append(watched[address][topic], indexer.GetName())
+			}
+		}
+	}
+
+	m.mu.Lock()
+	m.watched = watched
+	m.mu.Unlock()
+}
+
+// processTransaction simulates a pending transaction that may emit indexed events
+// and stores the indexed events it emits.
+func (m *PendingBlockMonitor) processTransaction(ctx context.Context, tx *types.Transaction) {
+	if tx == nil || !m.touchesWatchedContract(tx) {
+		return
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		m.log.Debugf("skipping pending transaction %s: failed to recover sender: %v", tx.Hash().Hex(), err)
+		return
+	}
+
+	logs, err := m.client.SimulateTransaction(ctx, from, tx)
+	if err != nil {
+		m.log.Debugf("failed to simulate pending transaction %s: %v", tx.Hash().Hex(), err)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seenAt := m.now()
+
+	var events []downloader.PendingEvent
+	for i, log := range logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+
+		for _, indexer := range m.watched[log.Address][log.Topics[0]] {
+			events = append(events, downloader.PendingEvent{
+				Indexer:  indexer,
+				Address:  log.Address,
+				Topics:   log.Topics,
+				Data:     log.Data,
+				TxHash:   tx.Hash(),
+				LogIndex: uint(i),
+				Pending:  true,
+				SeenAt:   seenAt,
+			})
+		}
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	m.txs[tx.Hash()] = &pendingTx{events: events, seenAt: seenAt}
+	m.log.Debugf("previewed %d pending events of transaction %s", len(events), tx.Hash().Hex())
+}
+
+// touchesWatchedContract decodes the calldata of the transaction and reports whether it calls an
+// indexed contract or passes one as an argument, e.g. a token swapped through a router.
+// Other transactions are not simulated, since they are unlikely to emit indexed events.
+func (m *PendingBlockMonitor) touchesWatchedContract(tx *types.Transaction) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if to := tx.To(); to != nil {
+		if _, ok := m.watched[*to]; ok {
+			return true
+		}
+	}
+
+	data := tx.Data()
+	if len(data) <= selectorSize {
+		return false
+	}
+
+	// ABI encoded addresses are left-padded to 32 bytes
+	args := data[selectorSize:]
+	for offset := 0; offset+common.HashLength <= len(args); offset += common.HashLength {
+		word := args[offset : offset+common.HashLength]
+		if !isZero(word[:common.HashLength-common.AddressLength]) {
+			continue
+		}
+
+		if _, ok := m.watched[common.BytesToAddress(word)]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Confirm removes the pending events of the transactions of the given confirmed logs,
+// which the indexers have stored by now.
+func (m *PendingBlockMonitor) Confirm(logs []types.Log) {
+	if len(logs) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, log := range logs {
+		delete(m.txs, log.TxHash)
+	}
+}
+
+// expire removes the pending events of transactions that were not confirmed within pendingEventTTL.
+func (m *PendingBlockMonitor) expire() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for hash, tx := range m.txs {
+		if now.Sub(tx.seenAt) > pendingEventTTL {
+			delete(m.txs, hash)
+		}
+	}
+}
+
+// PendingEvents returns the pending events of the given indexer, oldest first.
+func (m *PendingBlockMonitor) PendingEvents(indexer string) []downloader.PendingEvent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	events := make([]downloader.PendingEvent, 0)
+	for _, tx := range m.txs {
+		for _, event := range tx.events {
+			if event.Indexer == indexer {
+				events = append(events, event)
+			}
+		}
+	}
+
+	slices.SortFunc(events, func(a, b downloader.PendingEvent) int {
+		if c := a.SeenAt.Compare(b.SeenAt); c != 0 {
+			return c
+		}
+		if c := a.TxHash.Cmp(b.TxHash); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.LogIndex, b.LogIndex)
+	})
+
+	return events
+}
+
+// isZero reports whether all bytes are zero.
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+
+	return true
+}
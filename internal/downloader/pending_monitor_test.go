@@ -0,0 +1,156 @@
+package downloader
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	idx "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	indexermocks "github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingBlockMonitor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	token := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	router := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	other := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	transferTopic := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	approvalTopic := crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	newTx := func(nonce uint64, to common.Address, data []byte) *types.Transaction {
+		tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+			ChainID: big.NewInt(1),
+			Nonce:   nonce,
+			To:      &to,
+			Gas:     100_000,
+			Data:    data,
+		})
+		require.NoError(t, err)
+
+		return tx
+	}
+
+	selector := []byte{0xa9, 0x05, 0x9c, 0xbb}
+	tokenCall := newTx(0, token, selector)
+	routerCall := newTx(1, router, append(append(selector, common.LeftPadBytes(token.Bytes(), 32)...),
+		common.LeftPadBytes(big.NewInt(1000).Bytes(), 32)...))
+	unrelatedCall := newTx(2, other, append(selector, common.LeftPadBytes(other.Bytes(), 32)...))
+
+	indexer := indexermocks.NewIndexer(t)
+	indexer.EXPECT().GetName().Return("tokens")
+	indexer.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
+		token: {transferTopic: {}},
+	})
+
+	client := rpcmocks.NewPendingClient(t)
+	client.EXPECT().SimulateTransaction(mock.Anything, sender, tokenCall).Return([]types.Log{
+		{Address: token, Topics: []common.Hash{transferTopic}, Data: []byte{0x01}},
+		{Address: token, Topics: []common.Hash{approvalTopic}},
+	}, nil).Once()
+	client.EXPECT().SimulateTransaction(mock.Anything, sender, routerCall).Return([]types.Log{
+		{Address: other, Topics: []common.Hash{transferTopic}},
+		{Address: token, Topics: []common.Hash{transferTopic}, Data: []byte{0x02}},
+	}, nil).Once()
+
+	monitor := NewPendingBlockMonitor(client, func() []idx.Indexer { return []idx.Indexer{indexer} },
+		logger.NewNopLogger())
+	now := time.Unix(1_700_000_000, 0)
+	monitor.now = func() time.Time { return now }
+
+	monitor.watch(monitor.indexers())
+	for _, tx := range []*types.Transaction{tokenCall, unrelatedCall, routerCall} {
+		monitor.processTransaction(ctx, tx)
+	}
+
+	// Only the indexed event of the token and the router call is kept
+	events := monitor.PendingEvents("tokens")
+	require.Len(t, events, 2)
+	for _, pending := range events {
+		require.True(t, pending.Pending)
+		require.Equal(t, token, pending.Address)
+		require.Equal(t, []common.Hash{transferTopic}, pending.Topics)
+	}
+	require.ElementsMatch(t, []common.Hash{tokenCall.Hash(), routerCall.Hash()},
+		[]common.Hash{events[0].TxHash, events[1].TxHash})
+	require.Empty(t, monitor.PendingEvents("other"))
+
+	// Confirmed logs replace the previews of their transactions
+	monitor.Confirm([]types.Log{{Address: token, TxHash: tokenCall.Hash()}})
+	events = monitor.PendingEvents("tokens")
+	require.Len(t, events, 1)
+	require.Equal(t, routerCall.Hash(), events[0].TxHash)
+	require.Equal(t, uint(1), events[0].LogIndex)
+
+	// Transactions that are never confirmed expire
+	now = now.Add(pendingEventTTL + time.Second)
+	monitor.expire()
+	require.Empty(t, monitor.PendingEvents("tokens"))
+}
+
+func TestPendingBlockMonitor_Run(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	token := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	transferTopic := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(1),
+		To:      &token,
+		Gas:     100_000,
+	})
+	require.NoError(t, err)
+
+	indexer := indexermocks.NewIndexer(t)
+	indexer.EXPECT().GetName().Return("tokens")
+	indexer.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
+		token: {transferTopic: {}},
+	})
+
+	client := rpcmocks.NewPendingClient(t)
+	client.EXPECT().SubscribePendingTransactions(mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, ch chan<- *types.Transaction) (ethereum.Subscription, error) {
+			ch <- tx
+			return event.NewSubscription(func(quit <-chan struct{}) error {
+				<-quit
+				return nil
+			}), nil
+		})
+	client.EXPECT().SimulateTransaction(mock.Anything, crypto.PubkeyToAddress(key.PublicKey), tx).
+		Return([]types.Log{{Address: token, Topics: []common.Hash{transferTopic}}}, nil)
+
+	monitor := NewPendingBlockMonitor(client, func() []idx.Indexer { return []idx.Indexer{indexer} },
+		logger.NewNopLogger())
+
+	done := make(chan error, 1)
+	go func() { done <- monitor.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		return len(monitor.PendingEvents("tokens")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,54 @@
+package downloader
+
+import (
+	"sync"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/downloader"
+)
+
+// EventEmitter publishes sync progress events to an optional subscriber channel.
+// Sends never block, so a slow consumer cannot stall the download loop.
+type EventEmitter struct {
+	mu  sync.RWMutex
+	ch  chan<- downloader.ProgressEvent
+	log *logger.Logger
+}
+
+// NewEventEmitter creates a new EventEmitter with no subscriber.
+func NewEventEmitter(log *logger.Logger) *EventEmitter {
+	return &EventEmitter{log: log}
+}
+
+// SetChannel sets the subscriber channel. Passing nil disables publishing.
+func (e *EventEmitter) SetChannel(ch chan<- downloader.ProgressEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.ch = ch
+}
+
+// Enabled reports whether a subscriber channel is set.
+func (e *EventEmitter) Enabled() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.ch != nil
+}
+
+// Emit sends the event to the subscriber channel, dropping it if the channel is full.
+func (e *EventEmitter) Emit(event downloader.ProgressEvent) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.ch == nil {
+		return
+	}
+
+	select {
+	case e.ch <- event:
+	default:
+		e.log.Debugf("progress channel full, dropping progress event: indexer=%s, current_block=%d",
+			event.Indexer, event.CurrentBlock)
+	}
+}
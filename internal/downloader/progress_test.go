@@ -0,0 +1,65 @@
+package downloader
+
+import (
+	"testing"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/downloader"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProgressEvent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		startBlock   uint64
+		currentBlock uint64
+		targetBlock  uint64
+		expected     float64
+	}{
+		{name: "halfway", startBlock: 100, currentBlock: 150, targetBlock: 200, expected: 50},
+		{name: "at start block", startBlock: 100, currentBlock: 100, targetBlock: 200, expected: 0},
+		{name: "before start block", startBlock: 100, currentBlock: 50, targetBlock: 200, expected: 0},
+		{name: "caught up", startBlock: 100, currentBlock: 200, targetBlock: 200, expected: 100},
+		{name: "target before start block", startBlock: 300, currentBlock: 200, targetBlock: 200, expected: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			event := downloader.NewProgressEvent("backfill", "test", tt.startBlock, tt.currentBlock, tt.targetBlock)
+			require.Equal(t, "backfill", event.Type)
+			require.Equal(t, "test", event.Indexer)
+			require.Equal(t, tt.currentBlock, event.CurrentBlock)
+			require.Equal(t, tt.targetBlock, event.TargetBlock)
+			require.InDelta(t, tt.expected, event.Percentage, 0.001)
+		})
+	}
+}
+
+func TestEventEmitter(t *testing.T) {
+	t.Parallel()
+
+	emitter := NewEventEmitter(logger.NewNopLogger())
+	require.False(t, emitter.Enabled())
+
+	// Emitting without a channel is a no-op
+	emitter.Emit(downloader.ProgressEvent{Indexer: "test"})
+
+	ch := make(chan downloader.ProgressEvent, 1)
+	emitter.SetChannel(ch)
+	require.True(t, emitter.Enabled())
+
+	emitter.Emit(downloader.ProgressEvent{Indexer: "first", CurrentBlock: 1})
+	// Channel is full, so this event is dropped instead of blocking
+	emitter.Emit(downloader.ProgressEvent{Indexer: "second", CurrentBlock: 2})
+
+	event := <-ch
+	require.Equal(t, "first", event.Indexer)
+	require.Empty(t, ch)
+
+	emitter.SetChannel(nil)
+	require.False(t, emitter.Enabled())
+}
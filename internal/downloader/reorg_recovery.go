@@ -0,0 +1,42 @@
+package downloader
+
+import (
+	"fmt"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
+)
+
+// reorgRecovery limits the reorgs recovered automatically within a block window. The window starts with
+// the first recovered reorg and ends at the last block indexed when it was detected. Reorgs that keep
+// hitting the window before the downloader indexes past it point to an unstable node, which re-indexing
+// again does not fix.
+type reorgRecovery struct {
+	maxRecoveries int // 0 recovers any number of reorgs
+	recoveries    int
+	windowEnd     uint64
+}
+
+// record counts a recovery of reorgErr detected with lastIndexedBlock indexed. It returns an error
+// wrapping reorgErr if the window has already been recovered maxRecoveries times.
+func (r *reorgRecovery) record(reorgErr *reorg.ReorgDetectedError, lastIndexedBlock uint64) error {
+	if r.recoveries == 0 {
+		r.windowEnd = lastIndexedBlock
+	}
+	r.windowEnd = max(r.windowEnd, lastIndexedBlock)
+
+	if r.maxRecoveries > 0 && r.recoveries >= r.maxRecoveries {
+		return fmt.Errorf("%d reorgs recovered before indexing past block %d, exceeding max auto reorg recoveries %d: %w",
+			r.recoveries, r.windowEnd, r.maxRecoveries, reorgErr)
+	}
+
+	r.recoveries++
+
+	return nil
+}
+
+// advance closes the window once the downloader indexed past it, so later reorgs are counted in a new one.
+func (r *reorgRecovery) advance(lastIndexedBlock uint64) {
+	if r.recoveries > 0 && lastIndexedBlock > r.windowEnd {
+		r.recoveries = 0
+	}
+}
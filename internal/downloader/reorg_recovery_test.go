@@ -0,0 +1,65 @@
+package downloader
+
+import (
+	"testing"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorgRecovery(t *testing.T) {
+	t.Parallel()
+
+	reorgErr := &reorg.ReorgDetectedError{FirstReorgBlock: 95, Details: "test"}
+
+	t.Run("reorgs of the same window are limited", func(t *testing.T) {
+		t.Parallel()
+
+		recovery := &reorgRecovery{maxRecoveries: 3}
+		for range 3 {
+			require.NoError(t, recovery.record(reorgErr, 100))
+			// Re-indexing the rolled back blocks does not close the window
+			recovery.advance(98)
+		}
+
+		err := recovery.record(reorgErr, 99)
+		require.ErrorContains(t, err, "3 reorgs recovered before indexing past block 100")
+
+		var wrapped *reorg.ReorgDetectedError
+		require.ErrorAs(t, err, &wrapped)
+		require.Equal(t, uint64(95), wrapped.FirstReorgBlock)
+	})
+
+	t.Run("indexing past the window starts a new one", func(t *testing.T) {
+		t.Parallel()
+
+		recovery := &reorgRecovery{maxRecoveries: 2}
+		require.NoError(t, recovery.record(reorgErr, 100))
+		require.NoError(t, recovery.record(reorgErr, 100))
+
+		recovery.advance(101)
+		require.NoError(t, recovery.record(&reorg.ReorgDetectedError{FirstReorgBlock: 101}, 105))
+		require.NoError(t, recovery.record(&reorg.ReorgDetectedError{FirstReorgBlock: 101}, 105))
+		require.Error(t, recovery.record(&reorg.ReorgDetectedError{FirstReorgBlock: 101}, 105))
+	})
+
+	t.Run("the window grows with the indexed blocks", func(t *testing.T) {
+		t.Parallel()
+
+		recovery := &reorgRecovery{maxRecoveries: 2}
+		require.NoError(t, recovery.record(reorgErr, 100))
+		require.NoError(t, recovery.record(reorgErr, 110))
+
+		recovery.advance(105)
+		require.ErrorContains(t, recovery.record(reorgErr, 105), "before indexing past block 110")
+	})
+
+	t.Run("unlimited recoveries", func(t *testing.T) {
+		t.Parallel()
+
+		recovery := &reorgRecovery{}
+		for range 10 {
+			require.NoError(t, recovery.record(reorgErr, 100))
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	internalcommon "github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/internal/types"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	fch "github.com/goran-ethernal/ChainIndexor/pkg/fetcher"
+	idx "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+)
+
+// ReorgSimulation is the result of a simulated reorg.
+type ReorgSimulation struct {
+	// FromBlock and ToBlock are the first and last block of the simulated reorg
+	FromBlock uint64
+	ToBlock   uint64
+
+	// BlocksReindexed is the number of blocks fetched again and handed to the indexers
+	BlocksReindexed uint64
+
+	// EventsDeleted is the number of events of the reorged blocks the indexers rolled back
+	EventsDeleted int
+
+	// EventsReadded is the number of events of the reorged blocks the indexers stored again
+	EventsReadded int
+
+	// ResumeBlock is the last indexed block after the simulation, from which the next run continues
+	ResumeBlock uint64
+
+	// BlocksToCatchUp is the number of blocks after ToBlock that were indexed before the simulation.
+	// The indexers rolled them back with the reorged blocks, and the next run indexes them again
+	BlocksToCatchUp uint64
+
+	// Duration is the time the simulation took
+	Duration time.Duration
+}
+
+// SimulateReorg rolls back the indexed blocks [fromBlock, fromBlock+depth) as if they had been
+// reorged, and indexes them again from the RPC, going through the same recovery as a detected reorg:
+// the log store, the indexers and the reorg detector drop the blocks from fromBlock on, and the sync
+// state is reset to the block before. The blocks must already be indexed, and the download must not
+// be running. Only events of indexers that support querying are counted.
+func (d *Downloader) SimulateReorg(
+	ctx context.Context,
+	cfg config.Config,
+	fromBlock, depth uint64,
+) (*ReorgSimulation, error) {
+	start := time.Now()
+
+	if fromBlock == 0 {
+		return nil, errors.New("the reorg cannot start at the genesis block")
+	}
+	if depth == 0 {
+		return nil, errors.New("the reorg depth must be at least 1")
+	}
+
+	lastIndexedBlock, err := d.syncManager.GetLastIndexedBlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last indexed block: %w", err)
+	}
+
+	toBlock := fromBlock + depth - 1
+	if toBlock > lastIndexedBlock {
+		return nil, fmt.Errorf("blocks %d-%d are not indexed yet, the last indexed block is %d",
+			fromBlock, toBlock, lastIndexedBlock)
+	}
+
+	finality, err := types.ParseBlockFinality(d.cfg.Finality)
+	if err != nil {
+		return nil, fmt.Errorf("invalid finality configuration: %w", err)
+	}
+
+	logStore := d.newLogFetcher(finality,
+		logger.NewComponentLoggerFromConfig(internalcommon.ComponentLogStore, cfg.Logging),
+		logger.NewComponentLoggerFromConfig(internalcommon.ComponentLogFetcher, cfg.Logging))
+	d.coordinator.SetFallbackIndexer(d.newFallbackIndexer())
+
+	eventsDeleted, err := d.countEvents(ctx, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	d.log.Warnf("simulating reorg: from_block=%d, depth=%d", fromBlock, depth)
+
+	// The fetcher drops the logs of a detected reorg from the log store before the downloader handles it
+	if err := logStore.HandleReorg(ctx, fromBlock); err != nil {
+		return nil, fmt.Errorf("failed to roll back log store: %w", err)
+	}
+	if err := d.handleReorg(ctx, fromBlock); err != nil {
+		return nil, fmt.Errorf("failed to handle reorg: %w", err)
+	}
+
+	d.mu.RLock()
+	chunkSize := d.cfg.ChunkSize
+	d.mu.RUnlock()
+
+	for from := fromBlock; from <= toBlock; {
+		result, err := d.logFetcher.FetchRange(ctx, from, min(from+chunkSize-1, toBlock))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch blocks %d-%d: %w", from, toBlock, err)
+		}
+
+		if err := d.flushQueuedLogs(ctx); err != nil {
+			return nil, err
+		}
+
+		if err := d.coordinator.HandleLogs(ctx, result.Logs, result.FromBlock, result.ToBlock); err != nil {
+			return nil, fmt.Errorf("failed to handle logs: %w", err)
+		}
+
+		blockHash := common.Hash{}
+		if len(result.Headers) > 0 {
+			blockHash = result.Headers[len(result.Headers)-1].Hash()
+		}
+		if err := d.syncManager.SaveCheckpoint(result.ToBlock, blockHash, fch.ModeBackfill); err != nil {
+			return nil, fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+
+		from = result.ToBlock + 1
+	}
+
+	eventsReadded, err := d.countEvents(ctx, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	simulation := &ReorgSimulation{
+		FromBlock:       fromBlock,
+		ToBlock:         toBlock,
+		BlocksReindexed: depth,
+		EventsDeleted:   eventsDeleted,
+		EventsReadded:   eventsReadded,
+		ResumeBlock:     toBlock,
+		BlocksToCatchUp: lastIndexedBlock - toBlock,
+		Duration:        time.Since(start),
+	}
+
+	d.log.Infof("reorg simulated: from_block=%d, to_block=%d, events_deleted=%d, events_readded=%d",
+		fromBlock, toBlock, eventsDeleted, eventsReadded)
+
+	return simulation, nil
+}
+
+// countEvents returns the number of events the registered indexers stored for the block range.
+// Indexers that do not support querying are not counted.
+func (d *Downloader) countEvents(ctx context.Context, fromBlock, toBlock uint64) (int, error) {
+	var count int
+
+	for _, registered := range d.coordinator.ListAll() {
+		queryable, ok := registered.(idx.Queryable)
+		if !ok {
+			continue
+		}
+
+		for _, eventType := range queryable.GetEventTypes() {
+			_, total, err := queryable.QueryEvents(ctx, idx.QueryParams{
+				EventType: eventType,
+				FromBlock: &fromBlock,
+				ToBlock:   &toBlock,
+				Limit:     1,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to count %s events of indexer %s: %w", eventType, registered.GetName(), err)
+			}
+
+			count += total
+		}
+	}
+
+	return count, nil
+}
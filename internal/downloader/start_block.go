@@ -0,0 +1,187 @@
+package downloader
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	internalcommon "github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/rpc"
+)
+
+// startBlockMetaKeyPrefix prefixes the downloader_meta keys of the detected deployment blocks of contracts,
+// which are followed by the checksummed address of the contract.
+const startBlockMetaKeyPrefix = "start_block_"
+
+// ErrContractNotDeployed is returned when no contract code is found at an address in the latest block.
+var ErrContractNotDeployed = errors.New("contract not deployed")
+
+// StartBlockResolver detects the blocks contracts were deployed in, so indexers of contracts with
+// auto_detect_start_block do not backfill the blocks before them. A detected block is stored in the
+// downloader_meta table and read from there on later starts.
+type StartBlockResolver struct {
+	rpc rpc.EthClient
+	db  *sql.DB
+	cfg config.StartBlockDetectionConfig
+	log *logger.Logger
+}
+
+// NewStartBlockResolver creates a resolver detecting deployment blocks through the given client and storing
+// them in the given downloader database. Defaults are used for the detection limits when cfg is nil.
+func NewStartBlockResolver(
+	client rpc.EthClient,
+	database *sql.DB,
+	cfg *config.StartBlockDetectionConfig,
+	log *logger.Logger,
+) *StartBlockResolver {
+	var detectionCfg config.StartBlockDetectionConfig
+	if cfg != nil {
+		detectionCfg = *cfg
+	}
+	detectionCfg.ApplyDefaults()
+
+	return &StartBlockResolver{
+		rpc: client,
+		db:  database,
+		cfg: detectionCfg,
+		log: log,
+	}
+}
+
+// ResolveIndexerStartBlock returns the block the indexer starts from. When every contract of the indexer
+// has auto_detect_start_block, it is the earliest deployment block of its contracts, but not before the
+// configured start block. Otherwise, the configured start block is returned without any RPC call.
+func (r *StartBlockResolver) ResolveIndexerStartBlock(ctx context.Context, cfg config.IndexerConfig) (uint64, error) {
+	if len(cfg.Contracts) == 0 {
+		return cfg.StartBlock, nil
+	}
+
+	for _, contract := range cfg.Contracts {
+		if !contract.AutoDetectStartBlock {
+			return cfg.StartBlock, nil
+		}
+	}
+
+	startBlock := uint64(math.MaxUint64)
+	for _, contract := range cfg.Contracts {
+		address, err := internalcommon.NormalizeAddress(contract.Address)
+		if err != nil {
+			return 0, err
+		}
+
+		deploymentBlock, err := r.DeploymentBlock(ctx, address)
+		if err != nil {
+			return 0, fmt.Errorf("failed to detect the deployment block of contract %s: %w", address.Hex(), err)
+		}
+
+		startBlock = min(startBlock, deploymentBlock)
+	}
+
+	return max(startBlock, cfg.StartBlock), nil
+}
+
+// DeploymentBlock returns the block the contract at the given address was deployed in.
+// It is read from the downloader database if it was detected before, and detected and stored otherwise.
+func (r *StartBlockResolver) DeploymentBlock(ctx context.Context, address common.Address) (uint64, error) {
+	key := startBlockMetaKeyPrefix + address.Hex()
+
+	var stored string
+	err := r.db.QueryRowContext(ctx, `SELECT value FROM downloader_meta WHERE key = ?`, key).Scan(&stored)
+	switch {
+	case err == nil:
+		block, err := strconv.ParseUint(stored, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid stored deployment block %q: %w", stored, err)
+		}
+
+		return block, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return 0, fmt.Errorf("failed to get stored deployment block: %w", err)
+	}
+
+	block, err := r.detectDeploymentBlock(ctx, address)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = r.db.ExecContext(ctx, `INSERT INTO downloader_meta (key, value) VALUES (?, ?) ON CONFLICT(key) DO NOTHING`,
+		key, strconv.FormatUint(block, 10))
+	if err != nil {
+		return 0, fmt.Errorf("failed to store deployment block: %w", err)
+	}
+
+	r.log.Infof("Detected deployment of contract %s in block %d", address.Hex(), block)
+
+	return block, nil
+}
+
+// detectDeploymentBlock finds the first block the contract at the given address has code in.
+// If the contract has no code in the genesis block, it binary searches the blocks up to the latest one,
+// which has to have code. The search is bounded by the timeout and the maximum number of attempts.
+func (r *StartBlockResolver) detectDeploymentBlock(ctx context.Context, address common.Address) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Timeout.Duration)
+	defer cancel()
+
+	attempts := 0
+	hasCode := func(block uint64) (bool, error) {
+		if attempts >= r.cfg.MaxAttempts {
+			return false, fmt.Errorf("deployment block not found within %d attempts", r.cfg.MaxAttempts)
+		}
+		attempts++
+
+		code, err := r.rpc.GetContractCode(ctx, address, new(big.Int).SetUint64(block))
+		if err != nil {
+			return false, fmt.Errorf("failed to get the code in block %d: %w", block, err)
+		}
+
+		return len(code) > 0, nil
+	}
+
+	deployed, err := hasCode(0)
+	if err != nil {
+		return 0, err
+	}
+	if deployed {
+		return 0, nil
+	}
+
+	header, err := r.rpc.GetLatestBlockHeader(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	latest := header.Number.Uint64()
+
+	deployed, err = hasCode(latest)
+	if err != nil {
+		return 0, err
+	}
+	if !deployed {
+		return 0, fmt.Errorf("%w: no code at %s in the latest block %d", ErrContractNotDeployed, address.Hex(), latest)
+	}
+
+	// The contract has no code in block low and has code in block high
+	low, high := uint64(0), latest
+	for high-low > 1 {
+		mid := low + (high-low)/2
+
+		deployed, err := hasCode(mid)
+		if err != nil {
+			return 0, err
+		}
+
+		if deployed {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+
+	return high, nil
+}
@@ -0,0 +1,238 @@
+package downloader
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	internalcommon "github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testLatestBlock = 20_000_000
+
+// expectDeployedAt makes the client return code for the address from the given block on,
+// up to the latest test block.
+func expectDeployedAt(client *rpcmocks.EthClient, address common.Address, deploymentBlock uint64) {
+	client.EXPECT().GetContractCode(mock.Anything, address, mock.Anything).RunAndReturn(
+		func(_ context.Context, _ common.Address, block *big.Int) ([]byte, error) {
+			if block.Uint64() >= deploymentBlock {
+				return []byte{0x60, 0x80}, nil
+			}
+
+			return nil, nil
+		}).Maybe()
+
+	client.EXPECT().GetLatestBlockHeader(mock.Anything).
+		Return(&types.Header{Number: big.NewInt(testLatestBlock)}, nil).Maybe()
+}
+
+func storedDeploymentBlock(t *testing.T, database *sql.DB, address common.Address) string {
+	t.Helper()
+
+	var value string
+	err := database.QueryRow(`SELECT value FROM downloader_meta WHERE key = ?`,
+		startBlockMetaKeyPrefix+address.Hex()).Scan(&value)
+	require.NoError(t, err)
+
+	return value
+}
+
+func TestStartBlockResolver_DeploymentBlock(t *testing.T) {
+	t.Parallel()
+
+	database := setupTestDB(t)
+	defer database.Close()
+
+	address := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	client := rpcmocks.NewEthClient(t)
+	expectDeployedAt(client, address, 1_234_567)
+
+	resolver := NewStartBlockResolver(client, database, nil, logger.NewNopLogger())
+
+	block, err := resolver.DeploymentBlock(context.Background(), address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1_234_567), block)
+	require.Equal(t, "1234567", storedDeploymentBlock(t, database, address))
+
+	// The stored block is used without querying the node again
+	calls := len(client.Calls)
+	block, err = resolver.DeploymentBlock(context.Background(), address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1_234_567), block)
+	require.Len(t, client.Calls, calls)
+}
+
+func TestStartBlockResolver_DeployedAtGenesis(t *testing.T) {
+	t.Parallel()
+
+	database := setupTestDB(t)
+	defer database.Close()
+
+	address := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	client := rpcmocks.NewEthClient(t)
+	client.EXPECT().GetContractCode(mock.Anything, address, big.NewInt(0)).Return([]byte{0x60}, nil).Once()
+
+	resolver := NewStartBlockResolver(client, database, nil, logger.NewNopLogger())
+
+	block, err := resolver.DeploymentBlock(context.Background(), address)
+	require.NoError(t, err)
+	require.Zero(t, block)
+	require.Equal(t, "0", storedDeploymentBlock(t, database, address))
+}
+
+func TestStartBlockResolver_Errors(t *testing.T) {
+	t.Parallel()
+
+	address := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	t.Run("not deployed", func(t *testing.T) {
+		t.Parallel()
+
+		database := setupTestDB(t)
+		defer database.Close()
+
+		client := rpcmocks.NewEthClient(t)
+		expectDeployedAt(client, address, testLatestBlock+1)
+
+		resolver := NewStartBlockResolver(client, database, nil, logger.NewNopLogger())
+
+		_, err := resolver.DeploymentBlock(context.Background(), address)
+		require.ErrorIs(t, err, ErrContractNotDeployed)
+
+		err = database.QueryRow(`SELECT value FROM downloader_meta WHERE key = ?`,
+			startBlockMetaKeyPrefix+address.Hex()).Scan(new(string))
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("max attempts exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		database := setupTestDB(t)
+		defer database.Close()
+
+		client := rpcmocks.NewEthClient(t)
+		expectDeployedAt(client, address, 1_234_567)
+
+		resolver := NewStartBlockResolver(client, database, &config.StartBlockDetectionConfig{
+			Timeout:     internalcommon.NewDuration(time.Minute),
+			MaxAttempts: 10,
+		}, logger.NewNopLogger())
+
+		_, err := resolver.DeploymentBlock(context.Background(), address)
+		require.ErrorContains(t, err, "deployment block not found within 10 attempts")
+	})
+
+	t.Run("rpc error", func(t *testing.T) {
+		t.Parallel()
+
+		database := setupTestDB(t)
+		defer database.Close()
+
+		client := rpcmocks.NewEthClient(t)
+		client.EXPECT().GetContractCode(mock.Anything, address, mock.Anything).
+			Return(nil, errors.New("missing trie node")).Once()
+
+		resolver := NewStartBlockResolver(client, database, nil, logger.NewNopLogger())
+
+		_, err := resolver.DeploymentBlock(context.Background(), address)
+		require.ErrorContains(t, err, "missing trie node")
+	})
+}
+
+func TestStartBlockResolver_ResolveIndexerStartBlock(t *testing.T) {
+	t.Parallel()
+
+	first := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	second := common.HexToAddress("0x5555555555555555555555555555555555555555")
+
+	tests := []struct {
+		name       string
+		cfg        config.IndexerConfig
+		startBlock uint64
+	}{
+		{
+			name:       "no contracts",
+			cfg:        config.IndexerConfig{StartBlock: 100},
+			startBlock: 100,
+		},
+		{
+			name: "detection disabled for a contract",
+			cfg: config.IndexerConfig{
+				StartBlock: 100,
+				Contracts: []config.ContractConfig{
+					{Address: first.Hex(), AutoDetectStartBlock: true},
+					{Address: second.Hex()},
+				},
+			},
+			startBlock: 100,
+		},
+		{
+			name: "earliest deployment",
+			cfg: config.IndexerConfig{
+				StartBlock: 100,
+				Contracts: []config.ContractConfig{
+					{Address: first.Hex(), AutoDetectStartBlock: true},
+					{Address: second.Hex(), AutoDetectStartBlock: true},
+				},
+			},
+			startBlock: 1_000_000,
+		},
+		{
+			name: "start block after the deployments",
+			cfg: config.IndexerConfig{
+				StartBlock: 3_000_000,
+				Contracts: []config.ContractConfig{
+					{Address: first.Hex(), AutoDetectStartBlock: true},
+					{Address: second.Hex(), AutoDetectStartBlock: true},
+				},
+			},
+			startBlock: 3_000_000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			database := setupTestDB(t)
+			defer database.Close()
+
+			// Without mocked calls, the client fails the test if the node is queried
+			client := rpcmocks.NewEthClient(t)
+			if len(tt.cfg.Contracts) > 0 && tt.cfg.Contracts[1].AutoDetectStartBlock {
+				client.EXPECT().GetContractCode(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+					func(_ context.Context, address common.Address, block *big.Int) ([]byte, error) {
+						deploymentBlock := uint64(2_000_000)
+						if address == first {
+							deploymentBlock = 1_000_000
+						}
+						if block.Uint64() >= deploymentBlock {
+							return []byte{0x60}, nil
+						}
+
+						return nil, nil
+					})
+				client.EXPECT().GetLatestBlockHeader(mock.Anything).
+					Return(&types.Header{Number: big.NewInt(testLatestBlock)}, nil)
+			}
+
+			resolver := NewStartBlockResolver(client, database, nil, logger.NewNopLogger())
+
+			startBlock, err := resolver.ResolveIndexerStartBlock(context.Background(), tt.cfg)
+			require.NoError(t, err)
+			require.Equal(t, tt.startBlock, startBlock)
+		})
+	}
+}
@@ -2,7 +2,9 @@ package downloader
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -13,6 +15,12 @@ import (
 	"github.com/russross/meddler"
 )
 
+// ErrChainIDMismatch is returned when the downloader database was created for another chain than the RPC endpoint serves.
+var ErrChainIDMismatch = errors.New("downloader database belongs to another chain")
+
+// chainIDMetaKey is the downloader_meta key of the ID of the chain the database was created for.
+const chainIDMetaKey = "chain_id"
+
 // Compile-time check to ensure SyncManager implements pkgdownloader.SyncManager interface.
 var _ pkgdownloader.SyncManager = (*SyncManager)(nil)
 
@@ -95,7 +103,7 @@ func (sm *SyncManager) SaveCheckpoint(blockNum uint64, blockHash common.Hash, mo
 		Mode:                 string(mode),
 	}
 
-	err := meddler.Update(sm.db, "sync_state", &state)
+	err := db.Meddler(sm.db).Update(sm.db, "sync_state", &state)
 	if err != nil {
 		return fmt.Errorf("failed to save checkpoint: %w", err)
 	}
@@ -127,7 +135,7 @@ func (sm *SyncManager) SetMode(mode fetcher.FetchMode) error {
 	state.ID = 1
 	state.Mode = string(mode)
 
-	err = meddler.Update(sm.db, "sync_state", state)
+	err = db.Meddler(sm.db).Update(sm.db, "sync_state", state)
 	if err != nil {
 		return fmt.Errorf("failed to set mode: %w", err)
 	}
@@ -154,7 +162,7 @@ func (sm *SyncManager) Reset(startBlock uint64) error {
 		Mode:                 string(fetcher.ModeBackfill),
 	}
 
-	err := meddler.Update(sm.db, "sync_state", &state)
+	err := db.Meddler(sm.db).Update(sm.db, "sync_state", &state)
 	if err != nil {
 		return fmt.Errorf("failed to reset sync state: %w", err)
 	}
@@ -167,6 +175,29 @@ func (sm *SyncManager) Reset(startBlock uint64) error {
 	return nil
 }
 
+// VerifyChainID checks that the database was created for the given chain. The chain ID is stored
+// on the first run, and later runs fail with ErrChainIDMismatch if it differs, as the indexed data
+// belongs to another chain. Such a database has to be removed or pointed at the right RPC endpoint manually.
+func (sm *SyncManager) VerifyChainID(chainID uint64) error {
+	_, err := sm.db.Exec(`INSERT INTO downloader_meta (key, value) VALUES (?, ?) ON CONFLICT(key) DO NOTHING`,
+		chainIDMetaKey, strconv.FormatUint(chainID, 10))
+	if err != nil {
+		return fmt.Errorf("failed to store chain ID: %w", err)
+	}
+
+	var stored string
+	if err := sm.db.QueryRow(`SELECT value FROM downloader_meta WHERE key = ?`, chainIDMetaKey).Scan(&stored); err != nil {
+		return fmt.Errorf("failed to get stored chain ID: %w", err)
+	}
+
+	if stored != strconv.FormatUint(chainID, 10) {
+		return fmt.Errorf("%w: database was created for chain %s, RPC endpoint serves chain %d",
+			ErrChainIDMismatch, stored, chainID)
+	}
+
+	return nil
+}
+
 // Close closes the database connection.
 func (sm *SyncManager) Close() error {
 	return sm.db.Close()
@@ -133,3 +133,21 @@ func TestSyncManagerPersistence(t *testing.T) {
 	require.Equal(t, persistHash, state.LastIndexedBlockHash)
 	require.Equal(t, fetcher.ModeLive, state.GetMode())
 }
+
+func TestSyncManager_VerifyChainID(t *testing.T) {
+	t.Parallel()
+
+	database := setupTestDB(t)
+	defer database.Close()
+
+	sm, err := NewSyncManager(database, logger.NewNopLogger(), &db.NoOpMaintenance{})
+	require.NoError(t, err)
+
+	// The first run stores the chain ID, later runs on the same chain pass
+	require.NoError(t, sm.VerifyChainID(11155111))
+	require.NoError(t, sm.VerifyChainID(11155111))
+
+	err = sm.VerifyChainID(1)
+	require.ErrorIs(t, err, ErrChainIDMismatch)
+	require.ErrorContains(t, err, "database was created for chain 11155111, RPC endpoint serves chain 1")
+}
@@ -0,0 +1,123 @@
+package fetcher
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+)
+
+// chunkSizerWindow is the number of recent fetches an AdaptiveChunkSizer keeps track of.
+const chunkSizerWindow = 10
+
+// fetchSample is the outcome of a single fetch observed by an AdaptiveChunkSizer.
+type fetchSample struct {
+	duration time.Duration
+	logs     int
+}
+
+// AdaptiveChunkSizer adjusts the number of blocks fetched per request to the latency of the RPC node.
+// A fetch slower than the target duration halves the chunk size, while a fetch taking less than
+// half the target grows it by 25%, always within [minChunkSize, maxChunkSize].
+// It is safe for concurrent use.
+type AdaptiveChunkSizer struct {
+	mu sync.Mutex
+
+	chunkSize    uint64
+	minChunkSize uint64
+	maxChunkSize uint64
+	target       time.Duration
+
+	// chainID labels the chunk size metric
+	chainID uint64
+
+	// samples holds the last chunkSizerWindow fetches, next is the slot of the next one
+	samples []fetchSample
+	next    int
+
+	log *logger.Logger
+}
+
+// NewAdaptiveChunkSizer creates an AdaptiveChunkSizer starting at initial, clamped to [minChunkSize, maxChunkSize].
+// Its chunk size metric is labelled with chainID.
+func NewAdaptiveChunkSizer(
+	chainID uint64,
+	initial, minChunkSize, maxChunkSize uint64,
+	target time.Duration,
+	log *logger.Logger,
+) *AdaptiveChunkSizer {
+	// A chunk always contains at least one block
+	minChunkSize = max(minChunkSize, 1)
+	maxChunkSize = max(maxChunkSize, minChunkSize)
+
+	s := &AdaptiveChunkSizer{
+		chunkSize:    min(max(initial, minChunkSize), maxChunkSize),
+		minChunkSize: minChunkSize,
+		maxChunkSize: maxChunkSize,
+		target:       target,
+		chainID:      chainID,
+		samples:      make([]fetchSample, 0, chunkSizerWindow),
+		log:          log,
+	}
+	FetcherChunkSizeSet(s.chainID, s.chunkSize)
+
+	return s
+}
+
+// ChunkSize returns the number of blocks to fetch in the next request.
+func (s *AdaptiveChunkSizer) ChunkSize() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.chunkSize
+}
+
+// Observe records a fetch that took duration and returned logCount logs, and adjusts the chunk size.
+func (s *AdaptiveChunkSizer) Observe(duration time.Duration, logCount int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sample := fetchSample{duration: duration, logs: logCount}
+	if len(s.samples) < chunkSizerWindow {
+		s.samples = append(s.samples, sample)
+	} else {
+		s.samples[s.next] = sample
+	}
+	s.next = (s.next + 1) % chunkSizerWindow
+
+	previous := s.chunkSize
+
+	switch {
+	case duration > s.target:
+		s.chunkSize = max(s.chunkSize/2, s.minChunkSize) //nolint:mnd
+	case duration < s.target/2:
+		s.chunkSize = min(s.chunkSize+max(s.chunkSize/4, 1), s.maxChunkSize) //nolint:mnd
+	}
+
+	if s.chunkSize == previous {
+		return
+	}
+
+	FetcherChunkSizeSet(s.chainID, s.chunkSize)
+
+	avgDuration, avgLogs := s.averages()
+	s.log.Debugf("chunk size changed from %d to %d, last fetch took %v with %d logs "+
+		"(average of last %d fetches: %v with %d logs)",
+		previous, s.chunkSize, duration, logCount, len(s.samples), avgDuration, avgLogs)
+}
+
+// averages returns the average duration and log count of the tracked fetches.
+// The caller must hold the lock.
+func (s *AdaptiveChunkSizer) averages() (time.Duration, int) {
+	var (
+		totalDuration time.Duration
+		totalLogs     int
+	)
+
+	for _, sample := range s.samples {
+		totalDuration += sample.duration
+		totalLogs += sample.logs
+	}
+
+	return totalDuration / time.Duration(len(s.samples)), totalLogs / len(s.samples)
+}
@@ -0,0 +1,116 @@
+package fetcher
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptiveChunkSizer(t *testing.T) {
+	t.Parallel()
+
+	const target = 3 * time.Second
+
+	tests := []struct {
+		name      string
+		initial   uint64
+		durations []time.Duration
+		expected  uint64
+	}{
+		{
+			name:     "initial size is clamped to the bounds",
+			initial:  50_000,
+			expected: 10_000,
+		},
+		{
+			name:      "slow fetch halves the chunk size",
+			initial:   1000,
+			durations: []time.Duration{4 * time.Second},
+			expected:  500,
+		},
+		{
+			name:      "fast fetch grows the chunk size by a quarter",
+			initial:   1000,
+			durations: []time.Duration{time.Second},
+			expected:  1250,
+		},
+		{
+			name:      "fetch close to the target keeps the chunk size",
+			initial:   1000,
+			durations: []time.Duration{2 * time.Second, target},
+			expected:  1000,
+		},
+		{
+			name:      "chunk size does not shrink below the minimum",
+			initial:   150,
+			durations: []time.Duration{5 * time.Second, 5 * time.Second, 5 * time.Second},
+			expected:  100,
+		},
+		{
+			name:      "chunk size does not grow above the maximum",
+			initial:   9000,
+			durations: []time.Duration{time.Millisecond, time.Millisecond},
+			expected:  10_000,
+		},
+		{
+			name:      "slow fetch after fast ones",
+			initial:   1000,
+			durations: []time.Duration{time.Second, time.Second, 10 * time.Second},
+			expected:  781,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sizer := NewAdaptiveChunkSizer(0, tt.initial, 100, 10_000, target, logger.NewNopLogger())
+			for _, duration := range tt.durations {
+				sizer.Observe(duration, 10)
+			}
+
+			require.Equal(t, tt.expected, sizer.ChunkSize())
+			require.LessOrEqual(t, len(sizer.samples), chunkSizerWindow)
+		})
+	}
+}
+
+func TestAdaptiveChunkSizer_TracksLastFetches(t *testing.T) {
+	t.Parallel()
+
+	sizer := NewAdaptiveChunkSizer(0, 1000, 1, 10_000, time.Hour, logger.NewNopLogger())
+	for i := range chunkSizerWindow + 2 {
+		sizer.Observe(time.Duration(i)*time.Minute, i)
+	}
+
+	// The two oldest fetches are replaced by the newest ones
+	require.Len(t, sizer.samples, chunkSizerWindow)
+	require.Equal(t, fetchSample{duration: 10 * time.Minute, logs: 10}, sizer.samples[0])
+	require.Equal(t, fetchSample{duration: 11 * time.Minute, logs: 11}, sizer.samples[1])
+}
+
+func TestAdaptiveChunkSizer_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	sizer := NewAdaptiveChunkSizer(0, 1000, 10, 5000, 3*time.Second, logger.NewNopLogger())
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Go(func() {
+			for range 100 {
+				if i%2 == 0 {
+					sizer.Observe(5*time.Second, 1)
+				} else {
+					sizer.Observe(time.Second, 1)
+				}
+				size := sizer.ChunkSize()
+				require.GreaterOrEqual(t, size, uint64(10))
+				require.LessOrEqual(t, size, uint64(5000))
+			}
+		})
+	}
+	wg.Wait()
+}
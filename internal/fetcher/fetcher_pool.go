@@ -0,0 +1,194 @@
+package fetcher
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher"
+	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
+	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
+	"github.com/goran-ethernal/ChainIndexor/pkg/rpc"
+	"golang.org/x/sync/errgroup"
+)
+
+// Compile-time check to ensure FetcherPool implements fetcher.LogFetcher interface.
+var _ fetcher.LogFetcher = (*FetcherPool)(nil)
+
+// FetcherPool fetches logs for a block range in parallel. The configured contract addresses
+// are partitioned across Size workers, each owning a subset of the (address, topics) pairs.
+// The pool coordinates chunking, mode switching and reorg verification like a single
+// LogFetcher, while every chunk is fetched and stored by the workers concurrently.
+type FetcherPool struct {
+	*LogFetcher
+
+	// Size is the number of workers in the pool
+	Size int
+
+	workers []*LogFetcher
+	owner   map[ethcommon.Address]int
+}
+
+// NewFetcherPool creates a FetcherPool with up to size workers.
+// The pool never has more workers than configured addresses.
+func NewFetcherPool(
+	cfg LogFetcherConfig,
+	size int,
+	log *logger.Logger,
+	rpcClient rpc.EthClient,
+	reorgDetector reorg.Detector,
+	logStore store.LogStore,
+) *FetcherPool {
+	size = max(min(size, len(cfg.Addresses)), 1)
+
+	pool := &FetcherPool{
+		LogFetcher: NewLogFetcher(cfg, log, rpcClient, reorgDetector, logStore),
+		Size:       size,
+		workers:    make([]*LogFetcher, size),
+		owner:      make(map[ethcommon.Address]int, len(cfg.Addresses)),
+	}
+	pool.source = pool
+
+	workerConfigs := make([]LogFetcherConfig, size)
+	for i := range workerConfigs {
+		workerConfigs[i] = cfg
+		workerConfigs[i].Addresses = nil
+		workerConfigs[i].Topics = nil
+		// The pool chunks the block ranges, so only the pool adapts the chunk size
+		workerConfigs[i].MaxChunkSize = 0
+	}
+
+	// Distribute addresses round-robin, so each worker owns a similar number of contracts
+	for i, addr := range cfg.Addresses {
+		w := i % size
+		pool.owner[addr] = w
+		workerConfigs[w].Addresses = append(workerConfigs[w].Addresses, addr)
+		workerConfigs[w].Topics = append(workerConfigs[w].Topics, cfg.Topics[i])
+	}
+
+	for i := range pool.workers {
+		pool.workers[i] = NewLogFetcher(workerConfigs[i], log, rpcClient, reorgDetector, logStore)
+	}
+
+	log.Infof("created fetcher pool with %d workers for %d addresses", size, len(cfg.Addresses))
+
+	return pool
+}
+
+// fetchAndStore splits the addresses by the worker owning them and fetches their logs concurrently.
+// Workers may narrow the range when the RPC limits results, so the returned logs are trimmed to
+// the block range covered by every worker and sorted in chain order.
+func (p *FetcherPool) fetchAndStore(
+	ctx context.Context,
+	fromBlock, toBlock uint64,
+	addresses []ethcommon.Address,
+	topics [][]ethcommon.Hash,
+) ([]types.Log, uint64, uint64, error) {
+	workerAddresses := make([][]ethcommon.Address, len(p.workers))
+	workerTopics := make([][][]ethcommon.Hash, len(p.workers))
+
+	for i, addr := range addresses {
+		w, exists := p.owner[addr]
+		if !exists {
+			return nil, 0, 0, fmt.Errorf("address %s is not assigned to a fetcher pool worker", addr.Hex())
+		}
+
+		workerAddresses[w] = append(workerAddresses[w], addr)
+		workerTopics[w] = append(workerTopics[w], topics[i])
+	}
+
+	results := make([]*rangeLogs, len(p.workers))
+
+	g, errCtx := errgroup.WithContext(ctx)
+
+	for i, worker := range p.workers {
+		if len(workerAddresses[i]) == 0 {
+			continue
+		}
+
+		g.Go(func() error {
+			logs, newFrom, newTo, err := worker.fetchAndStore(errCtx, fromBlock, toBlock,
+				workerAddresses[i], workerTopics[i])
+			if err != nil {
+				return fmt.Errorf("fetcher pool worker %d: %w", i, err)
+			}
+
+			results[i] = &rangeLogs{logs: logs, newFrom: newFrom, newTo: newTo}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, 0, 0, err
+	}
+
+	logs, newFrom, newTo := mergeRangeLogs(results, fromBlock, toBlock)
+
+	return logs, newFrom, newTo, nil
+}
+
+// rangeLogs are the logs of a block range fetched for a subset of the addresses,
+// and the range they cover, which may be narrower than requested.
+type rangeLogs struct {
+	logs           []types.Log
+	newFrom, newTo uint64
+}
+
+// mergeRangeLogs merges the logs fetched concurrently for disjoint subsets of the addresses.
+// The subsets may have been narrowed to different ranges when the RPC limits results, so the logs
+// are trimmed to the block range covered by every subset. Nil results are skipped. The merged logs
+// are de-duplicated and sorted in chain order.
+func mergeRangeLogs(results []*rangeLogs, fromBlock, toBlock uint64) ([]types.Log, uint64, uint64) {
+	newFrom, newTo := fromBlock, toBlock
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		newFrom = max(newFrom, result.newFrom)
+		newTo = min(newTo, result.newTo)
+	}
+	// Keep the range valid when the subsets were narrowed to disjoint sub-ranges
+	newFrom = min(newFrom, newTo)
+
+	type logKey struct {
+		blockNumber uint64
+		index       uint
+	}
+
+	logs := make([]types.Log, 0)
+	seen := make(map[logKey]struct{})
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		for _, log := range result.logs {
+			if log.BlockNumber < newFrom || log.BlockNumber > newTo {
+				continue
+			}
+
+			key := logKey{blockNumber: log.BlockNumber, index: log.Index}
+			if _, exists := seen[key]; exists {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			logs = append(logs, log)
+		}
+	}
+
+	slices.SortFunc(logs, func(a, b types.Log) int {
+		if a.BlockNumber != b.BlockNumber {
+			return cmp.Compare(a.BlockNumber, b.BlockNumber)
+		}
+		return cmp.Compare(a.Index, b.Index)
+	})
+
+	return logs, newFrom, newTo
+}
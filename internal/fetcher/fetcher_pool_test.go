@@ -0,0 +1,127 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	storemocks "github.com/goran-ethernal/ChainIndexor/internal/fetcher/store/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	reorgmocks "github.com/goran-ethernal/ChainIndexor/internal/reorg/mocks"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	itypes "github.com/goran-ethernal/ChainIndexor/internal/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	poolAddr1  = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	poolAddr2  = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	poolAddr3  = common.HexToAddress("0x3333333333333333333333333333333333333333")
+	poolTopic1 = common.HexToHash("0xaaaa")
+	poolTopic2 = common.HexToHash("0xbbbb")
+	poolTopic3 = common.HexToHash("0xcccc")
+)
+
+func setupTestFetcherPool(t *testing.T, size int) (
+	*FetcherPool, *rpcmocks.EthClient, *reorgmocks.Detector, *storemocks.LogStore) {
+	t.Helper()
+
+	mockRPC := rpcmocks.NewEthClient(t)
+	mockReorg := reorgmocks.NewDetector(t)
+	mockStore := storemocks.NewLogStore(t)
+
+	log, err := logger.NewLogger("error", true)
+	require.NoError(t, err)
+
+	cfg := LogFetcherConfig{
+		ChunkSize:          100,
+		Finality:           itypes.FinalityFinalized,
+		Addresses:          []common.Address{poolAddr1, poolAddr2, poolAddr3},
+		Topics:             [][]common.Hash{{poolTopic1}, {poolTopic2}, {poolTopic3}},
+		AddressStartBlocks: map[common.Address]uint64{poolAddr1: 0, poolAddr2: 0, poolAddr3: 0},
+	}
+
+	pool := NewFetcherPool(cfg, size, log, mockRPC, mockReorg, mockStore)
+
+	return pool, mockRPC, mockReorg, mockStore
+}
+
+// queryFor matches eth_getLogs queries for exactly the given addresses.
+func queryFor(addresses ...common.Address) any {
+	return mock.MatchedBy(func(q ethereum.FilterQuery) bool {
+		return len(q.Addresses) == len(addresses) && q.Addresses[0] == addresses[0]
+	})
+}
+
+func TestNewFetcherPool(t *testing.T) {
+	t.Run("partitions addresses round-robin", func(t *testing.T) {
+		pool, _, _, _ := setupTestFetcherPool(t, 2) //nolint:dogsled
+
+		require.Equal(t, 2, pool.Size)
+		require.Len(t, pool.workers, 2)
+		require.Equal(t, []common.Address{poolAddr1, poolAddr3}, pool.workers[0].cfg.Addresses)
+		require.Equal(t, [][]common.Hash{{poolTopic1}, {poolTopic3}}, pool.workers[0].cfg.Topics)
+		require.Equal(t, []common.Address{poolAddr2}, pool.workers[1].cfg.Addresses)
+		require.Equal(t, [][]common.Hash{{poolTopic2}}, pool.workers[1].cfg.Topics)
+	})
+
+	t.Run("limits workers to the number of addresses", func(t *testing.T) {
+		pool, _, _, _ := setupTestFetcherPool(t, 10) //nolint:dogsled
+
+		require.Equal(t, 3, pool.Size)
+		require.Len(t, pool.workers, 3)
+	})
+}
+
+func TestFetcherPool_FetchRange_Success(t *testing.T) {
+	pool, mockRPC, mockReorg, mockStore := setupTestFetcherPool(t, 2)
+	ctx := context.Background()
+
+	log1 := types.Log{BlockNumber: 101, Index: 3, Address: poolAddr1, Topics: []common.Hash{poolTopic1}}
+	log2 := types.Log{BlockNumber: 100, Index: 0, Address: poolAddr2, Topics: []common.Hash{poolTopic2}}
+	log3 := types.Log{BlockNumber: 101, Index: 1, Address: poolAddr3, Topics: []common.Hash{poolTopic3}}
+
+	mockRPC.EXPECT().GetLogs(mock.Anything, queryFor(poolAddr1, poolAddr3)).
+		Return([]types.Log{log3, log1}, nil).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, queryFor(poolAddr2)).
+		Return([]types.Log{log2}, nil).Once()
+
+	// Every worker stores the logs of its own addresses
+	mockStore.EXPECT().StoreLogs(mock.Anything,
+		[]common.Address{poolAddr1, poolAddr3}, [][]common.Hash{{poolTopic1}, {poolTopic3}},
+		[]types.Log{log3, log1}, uint64(100), uint64(102)).Return(nil).Once()
+	mockStore.EXPECT().StoreLogs(mock.Anything,
+		[]common.Address{poolAddr2}, [][]common.Hash{{poolTopic2}},
+		[]types.Log{log2}, uint64(100), uint64(102)).Return(nil).Once()
+
+	// Logs of all workers are verified together in chain order
+	expectedLogs := []types.Log{log2, log3, log1}
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, expectedLogs, uint64(100), uint64(102)).
+		Return([]*types.Header{}, nil).Once()
+
+	result, err := pool.FetchRange(ctx, 100, 102)
+	require.NoError(t, err)
+	require.Equal(t, expectedLogs, result.Logs)
+	require.Equal(t, uint64(100), result.FromBlock)
+	require.Equal(t, uint64(102), result.ToBlock)
+}
+
+func TestFetcherPool_FetchRange_WorkerError(t *testing.T) {
+	pool, mockRPC, _, mockStore := setupTestFetcherPool(t, 2)
+	ctx := context.Background()
+
+	mockRPC.EXPECT().GetLogs(mock.Anything, queryFor(poolAddr1, poolAddr3)).
+		Return(nil, errors.New("log fetch error")).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, queryFor(poolAddr2)).
+		Return([]types.Log{}, nil).Maybe()
+	mockStore.EXPECT().StoreLogs(mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		uint64(100), uint64(102)).Return(nil).Maybe()
+
+	result, err := pool.FetchRange(ctx, 100, 102)
+	require.ErrorContains(t, err, "failed to fetch logs")
+	require.Nil(t, result)
+}
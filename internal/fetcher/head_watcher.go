@@ -0,0 +1,137 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/rpc"
+)
+
+const (
+	// headInitialBackoff is how long to wait before resubscribing after the first failure
+	headInitialBackoff = time.Second
+
+	// headMaxBackoff caps the wait between resubscriptions while the subscription keeps failing
+	headMaxBackoff = time.Minute
+
+	// headBufferSize is the number of pushed headers buffered while the previous one is handled
+	headBufferSize = 16
+)
+
+// errSubscriptionClosed is returned when the node closes the new heads subscription without an error.
+var errSubscriptionClosed = errors.New("subscription closed")
+
+// HeadWatcher subscribes to the headers of new blocks, so the log fetcher checks for new blocks
+// as soon as one is added to the chain instead of polling for them. A failed subscription is
+// renewed with an exponential backoff, and the log fetcher polls until it is renewed.
+type HeadWatcher struct {
+	client rpc.HeadClient
+	log    *logger.Logger
+
+	// heads is signalled when a new head arrives and when the subscription fails,
+	// so a waiting log fetcher falls back to polling
+	heads      chan struct{}
+	subscribed atomic.Bool
+
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// NewHeadWatcher creates a HeadWatcher subscribing to new heads through the given client.
+func NewHeadWatcher(client rpc.HeadClient, log *logger.Logger) *HeadWatcher {
+	return &HeadWatcher{
+		client:         client,
+		log:            log,
+		heads:          make(chan struct{}, 1),
+		initialBackoff: headInitialBackoff,
+		maxBackoff:     headMaxBackoff,
+	}
+}
+
+// Run subscribes to new heads and renews the subscription whenever it fails,
+// until the context is cancelled.
+func (w *HeadWatcher) Run(ctx context.Context) {
+	backoff := w.initialBackoff
+	for {
+		subscribed, err := w.watch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A subscription that was established starts a new series of failures
+		if subscribed {
+			backoff = w.initialBackoff
+		}
+
+		w.log.Warnf("new heads subscription failed, polling for new blocks and resubscribing in %v: %v",
+			backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(2*backoff, w.maxBackoff)
+	}
+}
+
+// watch subscribes to new heads and signals them until the subscription fails or the context
+// is cancelled. It reports whether the subscription was established.
+func (w *HeadWatcher) watch(ctx context.Context) (bool, error) {
+	ch := make(chan *types.Header, headBufferSize)
+	sub, err := w.client.SubscribeNewHeads(ctx, ch)
+	if err != nil {
+		return false, err
+	}
+	defer sub.Unsubscribe()
+
+	w.subscribed.Store(true)
+	defer func() {
+		w.subscribed.Store(false)
+		w.notify()
+	}()
+
+	w.log.Info("subscribed to new heads, waiting for new blocks without polling")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case err, ok := <-sub.Err():
+			if !ok || err == nil {
+				err = errSubscriptionClosed
+			}
+
+			return true, err
+		case header := <-ch:
+			if header != nil && header.Number != nil {
+				w.log.Debugf("new head %d", header.Number.Uint64())
+			}
+			w.notify()
+		}
+	}
+}
+
+// notify signals the heads channel without blocking. Pending signals are not stacked,
+// since a single check for new blocks covers all heads that arrived before it.
+func (w *HeadWatcher) notify() {
+	select {
+	case w.heads <- struct{}{}:
+	default:
+	}
+}
+
+// Subscribed reports whether the new heads subscription is currently established.
+func (w *HeadWatcher) Subscribed() bool {
+	return w.subscribed.Load()
+}
+
+// Heads returns the channel signalled when a new head arrives or the subscription fails.
+func (w *HeadWatcher) Heads() <-chan struct{} {
+	return w.heads
+}
@@ -0,0 +1,68 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSubscription returns a subscription that fails with the error sent on failures,
+// or ends when it is unsubscribed.
+func newTestSubscription(failures <-chan error) ethereum.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		select {
+		case err := <-failures:
+			return err
+		case <-quit:
+			return nil
+		}
+	})
+}
+
+func TestHeadWatcher_Resubscribes(t *testing.T) {
+	t.Parallel()
+
+	failures := make(chan error)
+	client := rpcmocks.NewHeadClient(t)
+
+	// The first attempt fails, the second subscription pushes a head and drops,
+	// and the third one stays established
+	client.EXPECT().SubscribeNewHeads(mock.Anything, mock.Anything).
+		Return(nil, errors.New("connection refused")).Once()
+	client.EXPECT().SubscribeNewHeads(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+			ch <- &types.Header{Number: big.NewInt(1)}
+			return newTestSubscription(failures), nil
+		}).Once()
+	client.EXPECT().SubscribeNewHeads(mock.Anything, mock.Anything).
+		Return(newTestSubscription(nil), nil).Once()
+
+	watcher := NewHeadWatcher(client, logger.NewNopLogger())
+	watcher.initialBackoff = time.Millisecond
+	watcher.maxBackoff = 4 * time.Millisecond
+	require.False(t, watcher.Subscribed())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go watcher.Run(ctx)
+
+	// The pushed head is signalled
+	<-watcher.Heads()
+	require.True(t, watcher.Subscribed())
+
+	// A dropped subscription is signalled too, so waiting fetchers fall back to polling
+	failures <- errors.New("connection reset")
+	<-watcher.Heads()
+
+	require.Eventually(t, watcher.Subscribed, 5*time.Second, time.Millisecond)
+}
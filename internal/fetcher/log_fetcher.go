@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"slices"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -12,23 +13,42 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	irpc "github.com/goran-ethernal/ChainIndexor/internal/rpc"
+	"github.com/goran-ethernal/ChainIndexor/internal/tracing"
 	itypes "github.com/goran-ethernal/ChainIndexor/internal/types"
 	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher"
 	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
 	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
 	"github.com/goran-ethernal/ChainIndexor/pkg/rpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 )
 
 // Compile-time check to ensure LogFetcher implements fetcher.LogFetcher interface.
 var _ fetcher.LogFetcher = (*LogFetcher)(nil)
 
-const ethereumBlockTime = 12 * time.Second
+const (
+	ethereumBlockTime = 12 * time.Second
+
+	// maxConcurrentAddressGroups is the number of address groups whose logs are fetched concurrently
+	// when the addresses exceed MaxAddressesPerRequest
+	maxConcurrentAddressGroups = 4
+)
 
 // LogFetcherConfig contains configuration for the LogFetcher.
 type LogFetcherConfig struct {
-	// ChunkSize is the number of blocks to fetch per request
+	// ChunkSize is the number of blocks to fetch per request.
+	// With adaptive chunk sizing, it is the chunk size to start with
 	ChunkSize uint64
 
+	// MinChunkSize and MaxChunkSize bound the chunk size when it is adjusted to the RPC latency.
+	// Adaptive chunk sizing is enabled when MaxChunkSize is set
+	MinChunkSize uint64
+	MaxChunkSize uint64
+
+	// TargetFetchDuration is the fetch duration adaptive chunk sizing aims for
+	TargetFetchDuration time.Duration
+
 	// Finality specifies the finality mode
 	Finality itypes.BlockFinality
 
@@ -43,16 +63,60 @@ type LogFetcherConfig struct {
 
 	// AddressStartBlocks maps each address to its minimum start block
 	AddressStartBlocks map[ethcommon.Address]uint64
+
+	// BloomPrefilter enables checking header bloom filters before calling eth_getLogs
+	BloomPrefilter bool
+
+	// MaxAddressesPerRequest is the maximum number of addresses per eth_getLogs call. More addresses
+	// are split into groups fetched concurrently. 0 puts all addresses in a single call
+	MaxAddressesPerRequest int
+
+	// PollInterval is how long to wait for new blocks in live mode, defaults to the Ethereum block time
+	PollInterval time.Duration
+
+	// UsePushMode makes live mode wait for the new heads signalled by Heads instead of polling
+	// every PollInterval. It falls back to polling while the subscription of Heads is down
+	UsePushMode bool
+
+	// Heads signals new heads in push mode
+	Heads *HeadWatcher
+
+	// LogProgressEvery is the number of blocks between backfill progress logs, 0 disables them
+	LogProgressEvery uint64
+
+	// ChainID labels the fetcher metrics
+	ChainID uint64
+}
+
+// logSource fetches the logs of a block range and stores them in the log store.
+// It returns the logs and the block range they cover, which may be narrower than requested.
+type logSource interface {
+	fetchAndStore(
+		ctx context.Context,
+		fromBlock, toBlock uint64,
+		addresses []ethcommon.Address,
+		topics [][]ethcommon.Hash,
+	) ([]types.Log, uint64, uint64, error)
 }
 
 // LogFetcher handles fetching logs and block headers from the blockchain.
 type LogFetcher struct {
 	cfg           LogFetcherConfig
+	source        logSource
 	rpc           rpc.EthClient
 	reorgDetector reorg.Detector
 	logStore      store.LogStore
 	log           *logger.Logger
 	mode          fetcher.FetchMode
+
+	// chunkSizer adjusts the chunk size to the RPC latency, nil when the chunk size is static
+	chunkSizer *AdaptiveChunkSizer
+
+	// progress tracks the rate and ETA of the backfill
+	progress *SyncProgress
+
+	// now returns the current time, replaced in tests to control fetch durations
+	now func() time.Time
 }
 
 // NewLogFetcher creates a new LogFetcher instance.
@@ -63,14 +127,24 @@ func NewLogFetcher(
 	reorgDetector reorg.Detector,
 	logStore store.LogStore,
 ) *LogFetcher {
-	return &LogFetcher{
+	lf := &LogFetcher{
 		cfg:           cfg,
 		rpc:           rpcClient,
 		reorgDetector: reorgDetector,
 		logStore:      logStore,
 		log:           log,
 		mode:          fetcher.ModeBackfill,
+		progress:      NewSyncProgress(cfg.LogProgressEvery),
+		now:           time.Now,
 	}
+	lf.source = lf
+
+	if cfg.MaxChunkSize > 0 {
+		lf.chunkSizer = NewAdaptiveChunkSizer(cfg.ChainID, cfg.ChunkSize, cfg.MinChunkSize, cfg.MaxChunkSize,
+			cfg.TargetFetchDuration, log)
+	}
+
+	return lf
 }
 
 // SetMode changes the fetcher's operating mode.
@@ -84,22 +158,103 @@ func (lf *LogFetcher) GetMode() fetcher.FetchMode {
 	return lf.mode
 }
 
+// SyncProgress returns the progress, rate and estimated time remaining of the backfill.
+func (lf *LogFetcher) SyncProgress() SyncProgressStats {
+	return lf.progress.Stats()
+}
+
 // FetchRange fetches logs and headers for a specific block range.
 // It verifies consistency using the ReorgDetector and returns an error if a reorg is detected.
 func (lf *LogFetcher) FetchRange(ctx context.Context, fromBlock, toBlock uint64) (*fetcher.FetchResult, error) {
 	return lf.fetchRange(ctx, fromBlock, toBlock, lf.cfg.Addresses, lf.cfg.Topics)
 }
 
-func (lf *LogFetcher) fetchRange(
+// FetchRangeFor fetches logs and headers for a specific block range like FetchRange,
+// but only for the given addresses and their topics.
+// The returned range may end before toBlock if the RPC limits the number of results.
+func (lf *LogFetcher) FetchRangeFor(
 	ctx context.Context,
 	fromBlock, toBlock uint64,
 	addresses []ethcommon.Address,
 	topics [][]ethcommon.Hash,
 ) (*fetcher.FetchResult, error) {
+	return lf.fetchRange(ctx, fromBlock, toBlock, addresses, topics)
+}
+
+func (lf *LogFetcher) fetchRange(
+	ctx context.Context,
+	fromBlock, toBlock uint64,
+	addresses []ethcommon.Address,
+	topics [][]ethcommon.Hash,
+) (result *fetcher.FetchResult, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "LogFetcher.FetchRange", trace.WithAttributes(
+		attribute.Int64("from_block", int64(fromBlock)),
+		attribute.Int64("to_block", int64(toBlock)),
+		attribute.String("mode", lf.mode.String()),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	lf.log.Debugf("fetching range from %d to %d in mode %v",
 		fromBlock, toBlock, lf.mode,
 	)
 
+	start := lf.now()
+	logs, newFrom, newTo, err := lf.source.fetchAndStore(ctx, fromBlock, toBlock, addresses, topics)
+	if err != nil {
+		return nil, err
+	}
+
+	// The logs are stored, so the blocks are verified even when shutdown was requested,
+	// keeping the log store and the reorg detector consistent
+	ctx = context.WithoutCancel(ctx)
+
+	// Verify consistency and record blocks
+	// The reorg detector will verify headers and detect any reorgs
+	headers, err := lf.reorgDetector.VerifyAndRecordBlocks(ctx, logs, fromBlock, toBlock)
+	if err != nil {
+		// If reorg detected, invalidate cache
+		var reorgErr *reorg.ReorgDetectedError
+		if errors.As(err, &reorgErr) {
+			lf.log.Warnf("reorg detected, invalidating cache from block %d",
+				reorgErr.FirstReorgBlock,
+			)
+			if storeErr := lf.logStore.HandleReorg(ctx, reorgErr.FirstReorgBlock); storeErr != nil {
+				lf.log.Errorf("failed to handle reorg in log store: %v",
+					storeErr,
+				)
+			}
+		}
+		return nil, fmt.Errorf("reorg detected: %w", err)
+	}
+
+	if lf.chunkSizer != nil {
+		lf.chunkSizer.Observe(lf.now().Sub(start), len(logs))
+	}
+
+	lf.log.Infof("fetched range from %d to %d with %d logs",
+		fromBlock,
+		toBlock,
+		len(logs),
+	)
+	span.SetAttributes(attribute.Int("logs", len(logs)))
+
+	return &fetcher.FetchResult{
+		Logs:        logs,
+		Headers:     headers,
+		FromBlock:   newFrom,
+		ToBlock:     newTo,
+		TargetBlock: newTo,
+	}, nil
+}
+
+// fetchAndStore fetches the logs of the given addresses and topics in the block range and
+// stores them in the log store. Addresses whose start block is after the range are skipped.
+func (lf *LogFetcher) fetchAndStore(
+	ctx context.Context,
+	fromBlock, toBlock uint64,
+	addresses []ethcommon.Address,
+	topics [][]ethcommon.Hash,
+) ([]types.Log, uint64, uint64, error) {
 	// Build dynamic filter with only addresses that have reached their start block
 	activeAddresses := make([]ethcommon.Address, 0, len(addresses))
 	activeTopics := make([][]ethcommon.Hash, 0, len(topics))
@@ -108,10 +263,11 @@ func (lf *LogFetcher) fetchRange(
 		startBlock, exists := lf.cfg.AddressStartBlocks[addr]
 		// Include address if:
 		// 1. No start block is configured (shouldn't happen but be safe), OR
-		// 2. We've reached or passed the start block
-		if !exists || fromBlock >= startBlock {
+		// 2. The start block is within or before the range. The logs before it are
+		//    filtered out by the indexer coordinator
+		if !exists || toBlock >= startBlock {
 			activeAddresses = append(activeAddresses, addr)
-			activeTopics = append(activeTopics, lf.cfg.Topics[i])
+			activeTopics = append(activeTopics, topics[i])
 		}
 	}
 
@@ -124,16 +280,16 @@ func (lf *LogFetcher) fetchRange(
 	// Only fetch logs if we have active addresses
 	if len(activeAddresses) > 0 {
 		// Fetch logs with automatic retry on "too many results" error
-		logs, newFrom, newTo, err = lf.fetchLogsWithRetry(ctx, fromBlock, toBlock, activeAddresses, activeTopics)
+		logs, newFrom, newTo, err = lf.fetchLogs(ctx, fromBlock, toBlock, activeAddresses, topicFilter(activeTopics))
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch logs: %w", err)
+			return nil, 0, 0, fmt.Errorf("failed to fetch logs: %w", err)
 		}
 
 		lf.log.Debugf("fetched logs from %d to %d with %d active addresses (total %d addresses), logs count: %d",
 			fromBlock,
 			toBlock,
 			len(activeAddresses),
-			len(lf.cfg.Addresses),
+			len(addresses),
 			len(logs),
 		)
 	} else {
@@ -145,49 +301,58 @@ func (lf *LogFetcher) fetchRange(
 		)
 	}
 
-	// Store fetched logs
-	if err := lf.logStore.StoreLogs(ctx,
+	// Store fetched logs. The transaction is committed even when shutdown was requested meanwhile,
+	// the shutdown timeout bounds how long the process waits for it
+	if err := lf.logStore.StoreLogs(context.WithoutCancel(ctx),
 		activeAddresses, activeTopics, logs,
 		fromBlock, toBlock); err != nil {
-		return nil, fmt.Errorf("failed to store logs: %w", err)
+		return nil, 0, 0, fmt.Errorf("failed to store logs: %w", err)
 	}
 
-	// Verify consistency and record blocks
-	// The reorg detector will verify headers and detect any reorgs
-	headers, err := lf.reorgDetector.VerifyAndRecordBlocks(ctx, logs, fromBlock, toBlock)
-	if err != nil {
-		// If reorg detected, invalidate cache
-		var reorgErr *reorg.ReorgDetectedError
-		if errors.As(err, &reorgErr) {
-			lf.log.Warnf("reorg detected, invalidating cache from block %d",
-				reorgErr.FirstReorgBlock,
-			)
-			if storeErr := lf.logStore.HandleReorg(ctx, reorgErr.FirstReorgBlock); storeErr != nil {
-				lf.log.Errorf("failed to handle reorg in log store: %v",
-					storeErr,
-				)
+	return logs, newFrom, newTo, nil
+}
+
+// topicFilter returns the eth_getLogs topic filter matching the events of every address.
+// Filter topics are matched by position, so the event signatures of all addresses are combined
+// into the first position rather than passed one position per address.
+func topicFilter(topics [][]ethcommon.Hash) [][]ethcommon.Hash {
+	signatures := make([]ethcommon.Hash, 0, len(topics))
+	seen := make(map[ethcommon.Hash]struct{})
+
+	for _, addressTopics := range topics {
+		// An address without topics is indexed for all of its events
+		if len(addressTopics) == 0 {
+			return nil
+		}
+
+		for _, topic := range addressTopics {
+			if _, exists := seen[topic]; !exists {
+				seen[topic] = struct{}{}
+				signatures = append(signatures, topic)
 			}
 		}
-		return nil, fmt.Errorf("reorg detected: %w", err)
 	}
 
-	lf.log.Infof("fetched range from %d to %d with %d logs",
-		fromBlock,
-		toBlock,
-		len(logs),
-	)
+	if len(signatures) == 0 {
+		return nil
+	}
 
-	return &fetcher.FetchResult{
-		Logs:      logs,
-		Headers:   headers,
-		FromBlock: newFrom,
-		ToBlock:   newTo,
-	}, nil
+	return [][]ethcommon.Hash{signatures}
+}
+
+// chunkSize returns the number of blocks to fetch in the next request.
+func (lf *LogFetcher) chunkSize() uint64 {
+	if lf.chunkSizer != nil {
+		return lf.chunkSizer.ChunkSize()
+	}
+
+	return lf.cfg.ChunkSize
 }
 
 // FetchNext fetches the next chunk of logs based on the current mode.
 // For backfill mode, it fetches from the given block up to chunk_size.
 // For live mode, it fetches new blocks since the last checkpoint.
+// With adaptive chunk sizing, the duration of each fetched chunk adjusts the size of the next one.
 func (lf *LogFetcher) FetchNext(
 	ctx context.Context,
 	lastIndexedBlock uint64,
@@ -215,20 +380,28 @@ func (lf *LogFetcher) fetchBackfill(
 		return nil, fmt.Errorf("failed to get unsynced topics: %w", err)
 	}
 
-	if !nonSyncedLogs.IsEmpty() && nonSyncedLogs.ShouldCatchUp(lastIndexedBlock, downloaderStartBlock) {
-		lf.log.Info("found unsynced logs, syncing them first")
+	// Missing logs are caught up largest gap first. Addresses starting after the last indexed block
+	// are synced along with the next blocks
+	if gap, ok := lf.largestCatchUpGap(nonSyncedLogs, lastIndexedBlock, downloaderStartBlock); ok {
+		lf.log.Infof("found unsynced logs of %s from block %d to %d, syncing them first",
+			gap.address.Hex(), gap.fromBlock, gap.toBlock)
 
-		unsyncedAddresses, unsyncedTopics, lastCoveredBlock := nonSyncedLogs.GetAddressesAndTopics()
-		// if we already synced past downloaderStartBlock, start from lastIndexedBlock+1
-		fromBlock := max(downloaderStartBlock, lastCoveredBlock+1)
-		toBlock := min(fromBlock+lf.cfg.ChunkSize-1, lastIndexedBlock) // Don't fetch beyond last indexed block
-		return lf.fetchRange(
+		toBlock := min(gap.fromBlock+lf.chunkSize()-1, gap.toBlock)
+		result, err := lf.fetchRange(
 			ctx,
-			fromBlock,
+			gap.fromBlock,
 			toBlock,
-			unsyncedAddresses,
-			unsyncedTopics,
+			[]ethcommon.Address{gap.address},
+			[][]ethcommon.Hash{gap.topics},
 		)
+		if err != nil {
+			return nil, err
+		}
+
+		// Catching up only goes as far as the already indexed blocks
+		result.TargetBlock = lastIndexedBlock
+
+		return result, nil
 	}
 
 	// Get the current finalized block
@@ -239,16 +412,124 @@ func (lf *LogFetcher) fetchBackfill(
 
 	finalizedBlockNum := finalizedBlock.Number.Uint64()
 	fromBlock := lastIndexedBlock + 1
-	toBlock := min(fromBlock+lf.cfg.ChunkSize-1, finalizedBlockNum)
+	toBlock := min(fromBlock+lf.chunkSize()-1, finalizedBlockNum)
 
 	// Check if we've caught up
 	if fromBlock >= finalizedBlockNum {
 		lf.log.Info("backfill complete, switching to live mode")
 		lf.mode = fetcher.ModeLive
+		BackfillProgressSet(lf.cfg.ChainID, 0, 0)
 		return lf.fetchLive(ctx, lastIndexedBlock)
 	}
 
-	return lf.FetchRange(ctx, fromBlock, toBlock)
+	fetchStart := lf.now()
+	result, err := lf.fetchRangeTowards(ctx, fromBlock, toBlock, finalizedBlockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	lf.reportProgress(result, fetchStart)
+
+	return result, nil
+}
+
+// catchUpGap is a block range an unsynced address is missing the logs of some of its topics in.
+type catchUpGap struct {
+	address   ethcommon.Address
+	topics    []ethcommon.Hash
+	fromBlock uint64
+	toBlock   uint64
+}
+
+// largestCatchUpGap returns the largest block range an unsynced address is missing logs in, up to the
+// last indexed block, along with the topics missing its first block. Blocks before the downloader's
+// start block or the address's start block are not missing. It returns false if there is nothing to catch up.
+func (lf *LogFetcher) largestCatchUpGap(
+	unsynced *store.UnsyncedTopics,
+	lastIndexedBlock, downloaderStartBlock uint64,
+) (catchUpGap, bool) {
+	if lastIndexedBlock <= downloaderStartBlock {
+		return catchUpGap{}, false
+	}
+
+	missing := unsynced.MissingRanges(lastIndexedBlock)
+
+	var (
+		largest catchUpGap
+		found   bool
+	)
+	for address, topicRanges := range missing {
+		startBlock := max(downloaderStartBlock, lf.cfg.AddressStartBlocks[address])
+
+		for _, ranges := range topicRanges {
+			for _, r := range ranges {
+				gap := catchUpGap{address: address, fromBlock: max(r.FromBlock, startBlock), toBlock: r.ToBlock}
+				if gap.fromBlock > gap.toBlock {
+					continue
+				}
+
+				if !found || isLargerGap(gap, largest) {
+					largest, found = gap, true
+				}
+			}
+		}
+	}
+
+	if !found {
+		return catchUpGap{}, false
+	}
+
+	// Every topic missing the first block of the gap is fetched along
+	for topic, ranges := range missing[largest.address] {
+		if slices.ContainsFunc(ranges, func(r store.CoverageRange) bool {
+			return r.FromBlock <= largest.fromBlock && largest.fromBlock <= r.ToBlock
+		}) {
+			largest.topics = append(largest.topics, topic)
+		}
+	}
+	slices.SortFunc(largest.topics, ethcommon.Hash.Cmp)
+
+	return largest, true
+}
+
+// isLargerGap reports whether gap a spans more blocks than gap b, breaking ties by address and
+// from block so the choice does not depend on map iteration order.
+func isLargerGap(a, b catchUpGap) bool {
+	if sizeA, sizeB := a.toBlock-a.fromBlock, b.toBlock-b.fromBlock; sizeA != sizeB {
+		return sizeA > sizeB
+	}
+	if c := a.address.Cmp(b.address); c != 0 {
+		return c < 0
+	}
+
+	return a.fromBlock < b.fromBlock
+}
+
+// reportProgress records a fetched backfill chunk, updates the backfill metrics
+// and logs the progress every LogProgressEvery blocks.
+func (lf *LogFetcher) reportProgress(result *fetcher.FetchResult, fetchStart time.Time) {
+	shouldLog := lf.progress.Update(result.FromBlock, result.ToBlock, result.TargetBlock, fetchStart, lf.now())
+	stats := lf.progress.Stats()
+
+	BackfillProgressSet(lf.cfg.ChainID, stats.BlocksRemaining, stats.BlocksPerSecond)
+
+	if !shouldLog {
+		return
+	}
+
+	eta := "unknown"
+	if stats.BlocksPerSecond > 0 {
+		eta = (time.Duration(stats.ETASeconds) * time.Second).String()
+	}
+
+	lf.log.Infof("backfill progress: current_block=%d target_block=%d blocks_remaining=%d "+
+		"blocks_per_second=%.1f eta=%s",
+		stats.CurrentBlock,
+		stats.TargetBlock,
+		stats.BlocksRemaining,
+		stats.BlocksPerSecond,
+		eta,
+	)
 }
 
 // fetchLive tails new blocks as they become finalized.
@@ -269,23 +550,67 @@ func (lf *LogFetcher) fetchLive(ctx context.Context, lastIndexedBlock uint64) (*
 			finalizedBlockNum,
 		)
 
-		// Wait for a short period before checking again
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(ethereumBlockTime):
-			return lf.fetchLive(ctx, lastIndexedBlock)
+		if err := lf.waitForBlocks(ctx); err != nil {
+			return nil, err
 		}
+
+		return lf.fetchLive(ctx, lastIndexedBlock)
 	}
 
 	toBlock := finalizedBlockNum
 
 	// In live mode, we still chunk to avoid huge fetches if we fall behind
-	if toBlock-fromBlock+1 > lf.cfg.ChunkSize {
-		toBlock = fromBlock + lf.cfg.ChunkSize - 1
+	if chunkSize := lf.chunkSize(); toBlock-fromBlock+1 > chunkSize {
+		toBlock = fromBlock + chunkSize - 1
+	}
+
+	return lf.fetchRangeTowards(ctx, fromBlock, toBlock, finalizedBlockNum)
+}
+
+// waitForBlocks waits until new blocks may be available: for the next head in push mode while
+// the new heads subscription is established, or for the poll interval otherwise.
+func (lf *LogFetcher) waitForBlocks(ctx context.Context) error {
+	if lf.cfg.UsePushMode && lf.cfg.Heads != nil && lf.cfg.Heads.Subscribed() {
+		// The heads channel is also signalled when the subscription fails,
+		// so the next wait falls back to polling
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-lf.cfg.Heads.Heads():
+			return nil
+		}
 	}
 
-	return lf.FetchRange(ctx, fromBlock, toBlock)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(lf.pollInterval()):
+		return nil
+	}
+}
+
+// pollInterval returns how long to wait before checking for new blocks again.
+func (lf *LogFetcher) pollInterval() time.Duration {
+	if lf.cfg.PollInterval > 0 {
+		return lf.cfg.PollInterval
+	}
+
+	return ethereumBlockTime
+}
+
+// fetchRangeTowards fetches the given range and marks targetBlock as the block being synced towards.
+func (lf *LogFetcher) fetchRangeTowards(
+	ctx context.Context,
+	fromBlock, toBlock, targetBlock uint64,
+) (*fetcher.FetchResult, error) {
+	result, err := lf.FetchRange(ctx, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	result.TargetBlock = targetBlock
+
+	return result, nil
 }
 
 // getFinalizedBlock gets the block number considered finalized based on config.
@@ -318,11 +643,194 @@ func (lf *LogFetcher) getFinalizedBlock(ctx context.Context) (*types.Header, err
 		return nil, err
 	}
 
-	FinalizedBlockLogSet(header.Number.Uint64())
+	FinalizedBlockLogSet(lf.cfg.ChainID, header.Number.Uint64())
 
 	return header, nil
 }
 
+// fetchLogs fetches logs for the given range. When the bloom prefilter is enabled, the block
+// header blooms are checked first and the eth_getLogs query is narrowed to the blocks that
+// may contain matching events, or skipped entirely if there are none.
+func (lf *LogFetcher) fetchLogs(
+	ctx context.Context,
+	fromBlock, toBlock uint64,
+	addresses []ethcommon.Address,
+	topics [][]ethcommon.Hash,
+) ([]types.Log, uint64, uint64, error) {
+	if !lf.cfg.BloomPrefilter {
+		return lf.fetchAddressGroups(ctx, fromBlock, toBlock, addresses, topics)
+	}
+
+	candidateFrom, candidateTo, found, err := lf.bloomCandidateRange(ctx, fromBlock, toBlock, addresses, topics)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to apply bloom prefilter: %w", err)
+	}
+
+	if !found {
+		BloomPrefilterSkippedInc(lf.cfg.ChainID)
+		lf.log.Debugf("bloom prefilter found no candidate blocks from %d to %d, skipping eth_getLogs",
+			fromBlock, toBlock)
+
+		return []types.Log{}, fromBlock, toBlock, nil
+	}
+
+	logs, newFrom, newTo, err := lf.fetchAddressGroups(ctx, candidateFrom, candidateTo, addresses, topics)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// Blocks outside of the candidate range have no matching logs, so they are covered as well,
+	// unless the query had to be narrowed further because of too many results
+	if newFrom == candidateFrom {
+		newFrom = fromBlock
+	}
+	if newTo == candidateTo {
+		newTo = toBlock
+	}
+
+	return logs, newFrom, newTo, nil
+}
+
+// buildFilterQueries returns the eth_getLogs queries for the logs of the addresses in the block range.
+// Addresses exceeding MaxAddressesPerRequest are split into groups of at most that many addresses,
+// one query per group. Every query keeps the full topic filter, so the logs of all queries together
+// are the logs of a single query for all addresses.
+func (lf *LogFetcher) buildFilterQueries(
+	fromBlock, toBlock uint64,
+	addresses []ethcommon.Address,
+	topics [][]ethcommon.Hash,
+) []ethereum.FilterQuery {
+	groupSize := lf.cfg.MaxAddressesPerRequest
+	if groupSize <= 0 || len(addresses) <= groupSize {
+		groupSize = max(len(addresses), 1)
+	}
+
+	queries := make([]ethereum.FilterQuery, 0, (len(addresses)+groupSize-1)/groupSize)
+	for group := range slices.Chunk(addresses, groupSize) {
+		queries = append(queries, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(fromBlock),
+			ToBlock:   new(big.Int).SetUint64(toBlock),
+			Addresses: group,
+			Topics:    topics,
+		})
+	}
+
+	return queries
+}
+
+// fetchAddressGroups fetches the logs of the block range with the queries of buildFilterQueries.
+// Several queries are fetched concurrently, and their logs merged as if they came from a single query.
+func (lf *LogFetcher) fetchAddressGroups(
+	ctx context.Context,
+	fromBlock, toBlock uint64,
+	addresses []ethcommon.Address,
+	topics [][]ethcommon.Hash,
+) ([]types.Log, uint64, uint64, error) {
+	queries := lf.buildFilterQueries(fromBlock, toBlock, addresses, topics)
+	if len(queries) <= 1 {
+		return lf.fetchLogsWithRetry(ctx, fromBlock, toBlock, addresses, topics)
+	}
+
+	lf.log.Debugf("fetching logs from %d to %d for %d addresses in %d groups",
+		fromBlock, toBlock, len(addresses), len(queries))
+
+	results := make([]*rangeLogs, len(queries))
+
+	g, errCtx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentAddressGroups)
+
+	for i, query := range queries {
+		g.Go(func() error {
+			logs, newFrom, newTo, err := lf.fetchLogsWithRetry(errCtx, fromBlock, toBlock,
+				query.Addresses, query.Topics)
+			if err != nil {
+				return fmt.Errorf("address group %d: %w", i, err)
+			}
+
+			results[i] = &rangeLogs{logs: logs, newFrom: newFrom, newTo: newTo}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, 0, 0, err
+	}
+
+	logs, newFrom, newTo := mergeRangeLogs(results, fromBlock, toBlock)
+
+	return logs, newFrom, newTo, nil
+}
+
+// bloomCandidateRange fetches the headers of the given range and returns the smallest
+// sub-range containing every block whose bloom filter may include a matching event.
+// found is false if no block in the range can contain a matching event.
+func (lf *LogFetcher) bloomCandidateRange(
+	ctx context.Context,
+	fromBlock, toBlock uint64,
+	addresses []ethcommon.Address,
+	topics [][]ethcommon.Hash,
+) (candidateFrom, candidateTo uint64, found bool, err error) {
+	blockNums := make([]uint64, 0, toBlock-fromBlock+1)
+	for blockNum := fromBlock; blockNum <= toBlock; blockNum++ {
+		blockNums = append(blockNums, blockNum)
+	}
+
+	headers, err := lf.rpc.BatchGetBlockHeaders(ctx, blockNums)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to fetch block headers: %w", err)
+	}
+
+	for i, header := range headers {
+		// Treat missing headers as candidates so that no logs are ever skipped
+		if header != nil && !bloomMatches(header.Bloom, addresses, topics) {
+			continue
+		}
+
+		if !found {
+			candidateFrom = blockNums[i]
+			found = true
+		}
+		candidateTo = blockNums[i]
+	}
+
+	return candidateFrom, candidateTo, found, nil
+}
+
+// bloomMatches reports whether the bloom filter may contain a log matched by an eth_getLogs
+// query with the given addresses and topics. Bloom filters can yield false positives,
+// but never false negatives.
+func bloomMatches(bloom types.Bloom, addresses []ethcommon.Address, topics [][]ethcommon.Hash) bool {
+	if len(addresses) > 0 {
+		included := false
+		for _, addr := range addresses {
+			if types.BloomLookup(bloom, addr) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	for _, sub := range topics {
+		// An empty position matches any topic
+		included := len(sub) == 0
+		for _, topic := range sub {
+			if types.BloomLookup(bloom, topic) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	return true
+}
+
 // fetchLogsWithRetry fetches logs and automatically retries with a smaller range if too many results are returned.
 // This function recursively splits the block range until a successful query is achieved.
 func (lf *LogFetcher) fetchLogsWithRetry(
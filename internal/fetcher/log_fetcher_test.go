@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"slices"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	storemocks "github.com/goran-ethernal/ChainIndexor/internal/fetcher/store/mocks"
@@ -111,9 +114,9 @@ func TestLogFetcher_FetchRange_Success(t *testing.T) {
 		},
 	}
 
-	mockRPC.EXPECT().GetLogs(ctx, mock.Anything).Return(testLogs, nil).Once()
-	mockStore.EXPECT().StoreLogs(ctx, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(100), uint64(102)).Return(nil).Once()
-	mockReorg.EXPECT().VerifyAndRecordBlocks(ctx, testLogs, uint64(100), uint64(102)).Return(
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(testLogs, nil).Once()
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(100), uint64(102)).Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, testLogs, uint64(100), uint64(102)).Return(
 		[]*types.Header{header100, header101, header102}, nil).Once()
 
 	result, err := lf.FetchRange(ctx, 100, 102)
@@ -125,11 +128,46 @@ func TestLogFetcher_FetchRange_Success(t *testing.T) {
 	require.Len(t, result.Headers, 3)
 }
 
+func TestLogFetcher_FetchRange_ShutdownDuringStoreLogs(t *testing.T) {
+	lf, mockRPC, mockReorg, mockStore := setupTestLogFetcher(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testLogs := []types.Log{{BlockNumber: 100, Address: lf.cfg.Addresses[0]}}
+	committed := false
+
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(testLogs, nil).Once()
+	// Shutdown is requested while the logs are being stored
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(100), uint64(102)).
+		RunAndReturn(func(storeCtx context.Context, _ []common.Address, _ [][]common.Hash, _ []types.Log,
+			_, _ uint64) error {
+			cancel()
+
+			// A transaction bound to a cancelled context is rolled back
+			if err := storeCtx.Err(); err != nil {
+				return err
+			}
+			committed = true
+
+			return nil
+		}).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, testLogs, uint64(100), uint64(102)).
+		RunAndReturn(func(verifyCtx context.Context, _ []types.Log, _, _ uint64) ([]*types.Header, error) {
+			return []*types.Header{}, verifyCtx.Err()
+		}).Once()
+
+	result, err := lf.FetchRange(ctx, 100, 102)
+	require.NoError(t, err)
+	require.True(t, committed)
+	require.Equal(t, testLogs, result.Logs)
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
 func TestLogFetcher_FetchRange_LogFetchError(t *testing.T) {
 	lf, mockRPC, _, _ := setupTestLogFetcher(t)
 	ctx := context.Background()
 
-	mockRPC.EXPECT().GetLogs(ctx, mock.Anything).Return(nil, errors.New("log fetch error")).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(nil, errors.New("log fetch error")).Once()
 
 	result, err := lf.FetchRange(ctx, 100, 102)
 	require.Error(t, err)
@@ -147,17 +185,17 @@ func TestLogFetcher_FetchRange_ReorgDetected(t *testing.T) {
 		{BlockNumber: 100, BlockHash: header100.Hash()},
 	}
 
-	mockRPC.EXPECT().GetLogs(ctx, mock.Anything).Return(testLogs, nil).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(testLogs, nil).Once()
 
 	reorgErr := &reorg.ReorgDetectedError{
 		FirstReorgBlock: 101,
 		Details:         "test reorg",
 	}
 
-	mockStore.EXPECT().StoreLogs(ctx, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(100), uint64(102)).Return(nil).Once()
-	mockReorg.EXPECT().VerifyAndRecordBlocks(ctx, testLogs, uint64(100), uint64(102)).
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(100), uint64(102)).Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, testLogs, uint64(100), uint64(102)).
 		Return(nil, reorgErr).Once()
-	mockStore.EXPECT().HandleReorg(ctx, uint64(101)).Return(nil).Once()
+	mockStore.EXPECT().HandleReorg(mock.Anything, uint64(101)).Return(nil).Once()
 
 	result, err := lf.FetchRange(ctx, 100, 102)
 	require.Error(t, err)
@@ -177,8 +215,8 @@ func TestLogFetcher_FetchRange_NoActiveAddresses(t *testing.T) {
 
 	// No GetLogs call should be made since no addresses are active
 	emptyLogs := []types.Log{}
-	mockStore.EXPECT().StoreLogs(ctx, []common.Address{}, [][]common.Hash{}, emptyLogs, uint64(100), uint64(101)).Return(nil).Once()
-	mockReorg.EXPECT().VerifyAndRecordBlocks(ctx, emptyLogs, uint64(100), uint64(101)).
+	mockStore.EXPECT().StoreLogs(mock.Anything, []common.Address{}, [][]common.Hash{}, emptyLogs, uint64(100), uint64(101)).Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, emptyLogs, uint64(100), uint64(101)).
 		Return([]*types.Header{header100, header101}, nil).Once()
 
 	result, err := lf.FetchRange(ctx, 100, 101)
@@ -193,12 +231,12 @@ func TestLogFetcher_FetchBackfill_Success(t *testing.T) {
 	ctx := context.Background()
 
 	// Mock unsynced topics - empty
-	mockStore.EXPECT().GetUnsyncedTopics(ctx, lf.cfg.Addresses, lf.cfg.Topics, uint64(50)).
+	mockStore.EXPECT().GetUnsyncedTopics(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, uint64(50)).
 		Return(store.NewUnsyncedTopics(), nil).Once()
 
 	// Mock finalized block at 150
 	finalizedHeader := createTestHeader(150, common.HexToHash("0x149"))
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
 
 	// Mock headers for range 51-150 (capped by chunk size to 51-150)
 	headers := make([]*types.Header, 100)
@@ -211,15 +249,16 @@ func TestLogFetcher_FetchBackfill_Success(t *testing.T) {
 	}
 
 	testLogs := []types.Log{{BlockNumber: 51}}
-	mockRPC.EXPECT().GetLogs(ctx, mock.Anything).Return(testLogs, nil).Once()
-	mockStore.EXPECT().StoreLogs(ctx, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(51), uint64(150)).Return(nil).Once()
-	mockReorg.EXPECT().VerifyAndRecordBlocks(ctx, testLogs, uint64(51), uint64(150)).Return(headers, nil).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(testLogs, nil).Once()
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(51), uint64(150)).Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, testLogs, uint64(51), uint64(150)).Return(headers, nil).Once()
 
 	result, err := lf.FetchNext(ctx, 50, 0)
 	require.NoError(t, err)
 	require.NotNil(t, result)
 	require.Equal(t, uint64(51), result.FromBlock)
 	require.Equal(t, uint64(150), result.ToBlock)
+	require.Equal(t, uint64(150), result.TargetBlock)
 }
 
 func TestLogFetcher_FetchBackfill_WithUnsyncedTopics(t *testing.T) {
@@ -233,7 +272,7 @@ func TestLogFetcher_FetchBackfill_WithUnsyncedTopics(t *testing.T) {
 		ToBlock:   25,
 	})
 
-	mockStore.EXPECT().GetUnsyncedTopics(ctx, lf.cfg.Addresses, lf.cfg.Topics, uint64(50)).
+	mockStore.EXPECT().GetUnsyncedTopics(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, uint64(50)).
 		Return(unsyncedTopics, nil).Once()
 
 	// Should fetch from lastCoveredBlock+1 (26) to min(26+chunkSize-1, lastIndexedBlock) = min(125, 50) = 50
@@ -245,9 +284,9 @@ func TestLogFetcher_FetchBackfill_WithUnsyncedTopics(t *testing.T) {
 	}
 
 	testLogs := []types.Log{{BlockNumber: 26}}
-	mockRPC.EXPECT().GetLogs(ctx, mock.Anything).Return(testLogs, nil).Once()
-	mockStore.EXPECT().StoreLogs(ctx, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(26), uint64(50)).Return(nil).Once()
-	mockReorg.EXPECT().VerifyAndRecordBlocks(ctx, testLogs, uint64(26), uint64(50)).Return(headers, nil).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(testLogs, nil).Once()
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(26), uint64(50)).Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, testLogs, uint64(26), uint64(50)).Return(headers, nil).Once()
 
 	result, err := lf.FetchNext(ctx, 50, 0)
 	require.NoError(t, err)
@@ -256,6 +295,135 @@ func TestLogFetcher_FetchBackfill_WithUnsyncedTopics(t *testing.T) {
 	require.Equal(t, uint64(50), result.ToBlock)
 }
 
+func TestLogFetcher_FetchBackfill_UnsyncedAddressStartBlock(t *testing.T) {
+	lf, mockRPC, mockReorg, mockStore := setupTestLogFetcher(t)
+	ctx := context.Background()
+
+	// An address discovered at block 30 has no coverage yet
+	discovered := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	lf.cfg.AddressStartBlocks[discovered] = 30
+
+	unsyncedTopics := store.NewUnsyncedTopics()
+	unsyncedTopics.AddTopic(discovered, lf.cfg.Topics[0][0], store.CoverageRange{})
+
+	mockStore.EXPECT().GetUnsyncedTopics(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, uint64(50)).
+		Return(unsyncedTopics, nil).Once()
+
+	// Catching up starts at the address's start block rather than the downloader's start block
+	addresses := []common.Address{discovered}
+	topics := [][]common.Hash{{lf.cfg.Topics[0][0]}}
+	testLogs := []types.Log{{BlockNumber: 31, Address: discovered}}
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(testLogs, nil).Once()
+	mockStore.EXPECT().StoreLogs(mock.Anything, addresses, topics, testLogs, uint64(30), uint64(50)).Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, testLogs, uint64(30), uint64(50)).Return(nil, nil).Once()
+
+	result, err := lf.FetchNext(ctx, 50, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(30), result.FromBlock)
+	require.Equal(t, uint64(50), result.ToBlock)
+	require.Equal(t, uint64(50), result.TargetBlock)
+}
+
+func TestLogFetcher_FetchBackfill_LargestUnsyncedGapFirst(t *testing.T) {
+	lf, mockRPC, mockReorg, mockStore := setupTestLogFetcher(t)
+	ctx := context.Background()
+
+	addr2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	topic1 := lf.cfg.Topics[0][0]
+	topic2 := common.HexToHash("0x5678")
+
+	// The first address has a small hole, the second address has a large hole of one topic
+	// within the coverage of the other, so catching up from the lowest covered block would refetch blocks
+	unsyncedTopics := store.NewUnsyncedTopics()
+	unsyncedTopics.AddTopicRanges(lf.cfg.Addresses[0], topic1, 0, []store.CoverageRange{
+		{FromBlock: 0, ToBlock: 9},
+		{FromBlock: 15, ToBlock: 50},
+	})
+	unsyncedTopics.AddTopicRanges(addr2, topic1, 0, []store.CoverageRange{{FromBlock: 0, ToBlock: 50}})
+	unsyncedTopics.AddTopicRanges(addr2, topic2, 0, []store.CoverageRange{
+		{FromBlock: 0, ToBlock: 19},
+		{FromBlock: 41, ToBlock: 50},
+	})
+
+	mockStore.EXPECT().GetUnsyncedTopics(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, uint64(50)).
+		Return(unsyncedTopics, nil).Once()
+
+	// Only the missing topic of the largest hole is fetched
+	addresses := []common.Address{addr2}
+	topics := [][]common.Hash{{topic2}}
+	testLogs := []types.Log{{BlockNumber: 25, Address: addr2}}
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(testLogs, nil).Once()
+	mockStore.EXPECT().StoreLogs(mock.Anything, addresses, topics, testLogs, uint64(20), uint64(40)).Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, testLogs, uint64(20), uint64(40)).Return(nil, nil).Once()
+
+	result, err := lf.FetchNext(ctx, 50, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), result.FromBlock)
+	require.Equal(t, uint64(40), result.ToBlock)
+	require.Equal(t, uint64(50), result.TargetBlock)
+}
+
+func TestLogFetcher_FetchRange_AddressStartingWithinRange(t *testing.T) {
+	lf, mockRPC, mockReorg, mockStore := setupTestLogFetcher(t)
+	ctx := context.Background()
+
+	// The address starts within the range, so the whole range is fetched for it
+	lf.cfg.AddressStartBlocks[lf.cfg.Addresses[0]] = 101
+
+	testLogs := []types.Log{{BlockNumber: 101, Address: lf.cfg.Addresses[0]}}
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(testLogs, nil).Once()
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(100), uint64(102)).
+		Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, testLogs, uint64(100), uint64(102)).Return(nil, nil).Once()
+
+	result, err := lf.FetchRange(ctx, 100, 102)
+	require.NoError(t, err)
+	require.Equal(t, testLogs, result.Logs)
+}
+
+func TestTopicFilter(t *testing.T) {
+	t.Parallel()
+
+	transfer := common.HexToHash("0xaa")
+	approval := common.HexToHash("0xbb")
+	swap := common.HexToHash("0xcc")
+
+	tests := []struct {
+		name     string
+		topics   [][]common.Hash
+		expected [][]common.Hash
+	}{
+		{
+			name:     "single address",
+			topics:   [][]common.Hash{{transfer, approval}},
+			expected: [][]common.Hash{{transfer, approval}},
+		},
+		{
+			name:     "signatures of all addresses are matched in the first position",
+			topics:   [][]common.Hash{{transfer}, {swap, transfer}, {approval}},
+			expected: [][]common.Hash{{transfer, swap, approval}},
+		},
+		{
+			name:     "address indexed for all events",
+			topics:   [][]common.Hash{{transfer}, {}},
+			expected: nil,
+		},
+		{
+			name:     "no addresses",
+			topics:   nil,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, topicFilter(tt.topics))
+		})
+	}
+}
+
 func TestLogFetcher_FetchBackfill_SwitchesToLive(t *testing.T) {
 	lf, mockRPC, _, mockStore := setupTestLogFetcher(t)
 	ctx := context.Background()
@@ -285,7 +453,7 @@ func TestLogFetcher_FetchLive_NewBlocks(t *testing.T) {
 
 	// Finalized block is 105, last indexed is 100
 	finalizedHeader := createTestHeader(105, common.HexToHash("0x104"))
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
 
 	headers := make([]*types.Header, 5)
 	blockNums := make([]uint64, 5)
@@ -295,9 +463,9 @@ func TestLogFetcher_FetchLive_NewBlocks(t *testing.T) {
 	}
 
 	testLogs := []types.Log{{BlockNumber: 101}}
-	mockRPC.EXPECT().GetLogs(ctx, mock.Anything).Return(testLogs, nil).Once()
-	mockStore.EXPECT().StoreLogs(ctx, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(101), uint64(105)).Return(nil).Once()
-	mockReorg.EXPECT().VerifyAndRecordBlocks(ctx, testLogs, uint64(101), uint64(105)).Return(headers, nil).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(testLogs, nil).Once()
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(101), uint64(105)).Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, testLogs, uint64(101), uint64(105)).Return(headers, nil).Once()
 
 	result, err := lf.FetchNext(ctx, 100, 0)
 	require.NoError(t, err)
@@ -306,6 +474,80 @@ func TestLogFetcher_FetchLive_NewBlocks(t *testing.T) {
 	require.Equal(t, uint64(105), result.ToBlock)
 }
 
+func TestLogFetcher_FetchLive_PushMode(t *testing.T) {
+	lf, mockRPC, mockReorg, mockStore := setupTestLogFetcher(t)
+	lf.SetMode(fetcher.ModeLive)
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	// Polling alone would not reach the new block before the test times out
+	lf.cfg.PollInterval = time.Hour
+
+	subscribed := make(chan chan<- *types.Header, 1)
+	headClient := rpcmocks.NewHeadClient(t)
+	headClient.EXPECT().SubscribeNewHeads(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+			subscribed <- ch
+			return newTestSubscription(nil), nil
+		}).Once()
+
+	watcher := NewHeadWatcher(headClient, logger.NewNopLogger())
+	go watcher.Run(ctx)
+	heads := <-subscribed
+	require.Eventually(t, watcher.Subscribed, 5*time.Second, time.Millisecond)
+
+	lf.cfg.UsePushMode = true
+	lf.cfg.Heads = watcher
+
+	// The finalized block is only checked again when a new head is pushed
+	pushHead := func(context.Context) {
+		heads <- createTestHeader(200, common.Hash{})
+	}
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).
+		Return(createTestHeader(100, common.Hash{}), nil).Run(pushHead).Twice()
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).
+		Return(createTestHeader(101, common.Hash{}), nil).Once()
+
+	testLogs := []types.Log{{BlockNumber: 101}}
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(testLogs, nil).Once()
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(101), uint64(101)).
+		Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, testLogs, uint64(101), uint64(101)).
+		Return([]*types.Header{createTestHeader(101, common.Hash{})}, nil).Once()
+
+	result, err := lf.FetchNext(ctx, 100, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(101), result.FromBlock)
+	require.Equal(t, uint64(101), result.ToBlock)
+}
+
+func TestLogFetcher_FetchLive_PushModeFallsBackToPolling(t *testing.T) {
+	lf, mockRPC, mockReorg, mockStore := setupTestLogFetcher(t)
+	lf.SetMode(fetcher.ModeLive)
+	lf.cfg.PollInterval = time.Millisecond
+
+	// Without an established subscription, the finalized block is polled
+	headClient := rpcmocks.NewHeadClient(t)
+	lf.cfg.UsePushMode = true
+	lf.cfg.Heads = NewHeadWatcher(headClient, logger.NewNopLogger())
+
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).
+		Return(createTestHeader(100, common.Hash{}), nil).Once()
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).
+		Return(createTestHeader(101, common.Hash{}), nil).Once()
+
+	testLogs := []types.Log{{BlockNumber: 101}}
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(testLogs, nil).Once()
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(101), uint64(101)).
+		Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, testLogs, uint64(101), uint64(101)).
+		Return([]*types.Header{createTestHeader(101, common.Hash{})}, nil).Once()
+
+	result, err := lf.FetchNext(t.Context(), 100, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(101), result.ToBlock)
+}
+
 func TestLogFetcher_FetchLive_ChunksLargeRanges(t *testing.T) {
 	lf, mockRPC, mockReorg, mockStore := setupTestLogFetcher(t)
 	lf.SetMode(fetcher.ModeLive)
@@ -314,7 +556,7 @@ func TestLogFetcher_FetchLive_ChunksLargeRanges(t *testing.T) {
 
 	// Finalized block is 200, last indexed is 100, should chunk
 	finalizedHeader := createTestHeader(200, common.HexToHash("0x199"))
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
 
 	headers := make([]*types.Header, 10)
 	blockNums := make([]uint64, 10)
@@ -324,9 +566,9 @@ func TestLogFetcher_FetchLive_ChunksLargeRanges(t *testing.T) {
 	}
 
 	testLogs := []types.Log{{BlockNumber: 101}}
-	mockRPC.EXPECT().GetLogs(ctx, mock.Anything).Return(testLogs, nil).Once()
-	mockStore.EXPECT().StoreLogs(ctx, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(101), uint64(110)).Return(nil).Once()
-	mockReorg.EXPECT().VerifyAndRecordBlocks(ctx, testLogs, uint64(101), uint64(110)).Return(headers, nil).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(testLogs, nil).Once()
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(101), uint64(110)).Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, testLogs, uint64(101), uint64(110)).Return(headers, nil).Once()
 
 	result, err := lf.FetchNext(ctx, 100, 0)
 	require.NoError(t, err)
@@ -335,13 +577,54 @@ func TestLogFetcher_FetchLive_ChunksLargeRanges(t *testing.T) {
 	require.Equal(t, uint64(110), result.ToBlock) // Chunked to 10 blocks
 }
 
+func TestLogFetcher_FetchNext_AdaptiveChunkSize(t *testing.T) {
+	lf, mockRPC, mockReorg, mockStore := setupTestLogFetcher(t)
+	lf.SetMode(fetcher.ModeLive)
+	lf.chunkSizer = NewAdaptiveChunkSizer(0, 100, 10, 1000, 3*time.Second, lf.log)
+	ctx := context.Background()
+
+	// Every fetch takes as long as the next duration
+	now := time.Unix(1_700_000_000, 0)
+	fetchDurations := []time.Duration{5 * time.Second, time.Second}
+	calls := 0
+	lf.now = func() time.Time {
+		if calls%2 == 1 {
+			now = now.Add(fetchDurations[calls/2])
+		}
+		calls++
+		return now
+	}
+
+	finalizedHeader := createTestHeader(1000, common.HexToHash("0x999"))
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Twice()
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return(nil, nil).Twice()
+	mockStore.EXPECT().StoreLogs(mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything).Return(nil).Twice()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, nil).Twice()
+
+	// The first fetch is slower than the target, so the next chunk is halved
+	result, err := lf.FetchNext(ctx, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), result.FromBlock)
+	require.Equal(t, uint64(100), result.ToBlock)
+	require.Equal(t, uint64(50), lf.chunkSize())
+
+	// The second fetch is faster than half the target, so the next chunk grows by a quarter
+	result, err = lf.FetchNext(ctx, 100, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(101), result.FromBlock)
+	require.Equal(t, uint64(150), result.ToBlock)
+	require.Equal(t, uint64(62), lf.chunkSize())
+}
+
 func TestLogFetcher_GetFinalizedBlock_Finalized(t *testing.T) {
 	lf, mockRPC, _, _ := setupTestLogFetcher(t)
 	lf.cfg.Finality = itypes.FinalityFinalized
 	ctx := context.Background()
 
 	header := createTestHeader(100, common.HexToHash("0x99"))
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(header, nil).Once()
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(header, nil).Once()
 
 	finalizedBlock, err := lf.getFinalizedBlock(ctx)
 	require.NoError(t, err)
@@ -354,7 +637,7 @@ func TestLogFetcher_GetFinalizedBlock_Safe(t *testing.T) {
 	ctx := context.Background()
 
 	header := createTestHeader(98, common.HexToHash("0x97"))
-	mockRPC.EXPECT().GetSafeBlockHeader(ctx).Return(header, nil).Once()
+	mockRPC.EXPECT().GetSafeBlockHeader(mock.Anything).Return(header, nil).Once()
 
 	finalizedBlock, err := lf.getFinalizedBlock(ctx)
 	require.NoError(t, err)
@@ -368,9 +651,9 @@ func TestLogFetcher_GetFinalizedBlock_LatestWithLag(t *testing.T) {
 	ctx := context.Background()
 
 	header := createTestHeader(100, common.HexToHash("0x99"))
-	mockRPC.EXPECT().GetLatestBlockHeader(ctx).Return(header, nil).Once()
+	mockRPC.EXPECT().GetLatestBlockHeader(mock.Anything).Return(header, nil).Once()
 	blockWithLag := createTestHeader(90, common.HexToHash("0x89"))
-	mockRPC.EXPECT().GetBlockHeader(ctx, uint64(90)).Return(blockWithLag, nil).Once()
+	mockRPC.EXPECT().GetBlockHeader(mock.Anything, uint64(90)).Return(blockWithLag, nil).Once()
 
 	finalizedBlock, err := lf.getFinalizedBlock(ctx)
 	require.NoError(t, err)
@@ -384,9 +667,9 @@ func TestLogFetcher_GetFinalizedBlock_LatestWithLagBelowZero(t *testing.T) {
 	ctx := context.Background()
 
 	header := createTestHeader(100, common.HexToHash("0x99"))
-	mockRPC.EXPECT().GetLatestBlockHeader(ctx).Return(header, nil).Once()
+	mockRPC.EXPECT().GetLatestBlockHeader(mock.Anything).Return(header, nil).Once()
 	genesisBlock := createTestHeader(0, common.Hash{})
-	mockRPC.EXPECT().GetBlockHeader(ctx, uint64(0)).Return(genesisBlock, nil).Once()
+	mockRPC.EXPECT().GetBlockHeader(mock.Anything, uint64(0)).Return(genesisBlock, nil).Once()
 
 	finalizedBlock, err := lf.getFinalizedBlock(ctx)
 	require.NoError(t, err)
@@ -403,3 +686,392 @@ func TestLogFetcher_GetFinalizedBlock_InvalidMode(t *testing.T) {
 	require.Nil(t, finalizedBlock)
 	require.Contains(t, err.Error(), "invalid finality mode")
 }
+
+func createBloomHeader(blockNum uint64, addr common.Address, topics ...common.Hash) *types.Header {
+	header := createTestHeader(blockNum, common.Hash{})
+
+	var bloom types.Bloom
+	bloom.Add(addr.Bytes())
+	for _, topic := range topics {
+		bloom.Add(topic.Bytes())
+	}
+	header.Bloom = bloom
+
+	return header
+}
+
+func TestBloomMatches(t *testing.T) {
+	t.Parallel()
+
+	addr := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	otherAddr := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	topic := common.HexToHash("0xaaaa")
+	otherTopic := common.HexToHash("0xbbbb")
+
+	bloom := createBloomHeader(1, addr, topic).Bloom
+
+	tests := []struct {
+		name      string
+		addresses []common.Address
+		topics    [][]common.Hash
+		expected  bool
+	}{
+		{
+			name:      "address and topic match",
+			addresses: []common.Address{addr},
+			topics:    [][]common.Hash{{topic}},
+			expected:  true,
+		},
+		{
+			name:      "one of the addresses matches",
+			addresses: []common.Address{otherAddr, addr},
+			topics:    [][]common.Hash{{otherTopic, topic}},
+			expected:  true,
+		},
+		{
+			name:      "empty topic position matches any topic",
+			addresses: []common.Address{addr},
+			topics:    [][]common.Hash{{}},
+			expected:  true,
+		},
+		{
+			name:      "address does not match",
+			addresses: []common.Address{otherAddr},
+			topics:    [][]common.Hash{{topic}},
+			expected:  false,
+		},
+		{
+			name:      "topic does not match",
+			addresses: []common.Address{addr},
+			topics:    [][]common.Hash{{otherTopic}},
+			expected:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, bloomMatches(bloom, tt.addresses, tt.topics))
+		})
+	}
+}
+
+func TestLogFetcher_FetchRange_BloomPrefilterSkipsRange(t *testing.T) {
+	lf, mockRPC, mockReorg, mockStore := setupTestLogFetcher(t)
+	lf.cfg.BloomPrefilter = true
+	ctx := context.Background()
+
+	otherAddr := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	headers := []*types.Header{
+		createBloomHeader(100, otherAddr, lf.cfg.Topics[0][0]),
+		createBloomHeader(101, otherAddr, lf.cfg.Topics[0][0]),
+		createBloomHeader(102, otherAddr, lf.cfg.Topics[0][0]),
+	}
+
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101, 102}).Return(headers, nil).Once()
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, []types.Log{}, uint64(100), uint64(102)).
+		Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, []types.Log{}, uint64(100), uint64(102)).Return(headers, nil).Once()
+
+	result, err := lf.FetchRange(ctx, 100, 102)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), result.FromBlock)
+	require.Equal(t, uint64(102), result.ToBlock)
+	require.Empty(t, result.Logs)
+	mockRPC.AssertNotCalled(t, "GetLogs", mock.Anything, mock.Anything)
+}
+
+func TestLogFetcher_FetchRange_BloomPrefilterNarrowsRange(t *testing.T) {
+	lf, mockRPC, mockReorg, mockStore := setupTestLogFetcher(t)
+	lf.cfg.BloomPrefilter = true
+	ctx := context.Background()
+
+	addr := lf.cfg.Addresses[0]
+	otherTopic := common.HexToHash("0xbbbb")
+	headers := []*types.Header{
+		createBloomHeader(100, addr, otherTopic),
+		createBloomHeader(101, addr, lf.cfg.Topics[0][0]),
+		createBloomHeader(102, addr, lf.cfg.Topics[0][0]),
+		createBloomHeader(103, addr, otherTopic),
+	}
+	testLogs := []types.Log{
+		{BlockNumber: 101, Address: addr, Topics: []common.Hash{lf.cfg.Topics[0][0]}},
+		{BlockNumber: 102, Address: addr, Topics: []common.Hash{lf.cfg.Topics[0][0]}},
+	}
+
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101, 102, 103}).Return(headers, nil).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.MatchedBy(func(query ethereum.FilterQuery) bool {
+		return query.FromBlock.Uint64() == 101 && query.ToBlock.Uint64() == 102
+	})).Return(testLogs, nil).Once()
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, testLogs, uint64(100), uint64(103)).
+		Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, testLogs, uint64(100), uint64(103)).Return(headers, nil).Once()
+
+	result, err := lf.FetchRange(ctx, 100, 103)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), result.FromBlock)
+	require.Equal(t, uint64(103), result.ToBlock)
+	require.Len(t, result.Logs, 2)
+}
+
+func TestLogFetcher_FetchRange_BloomPrefilterHeaderError(t *testing.T) {
+	lf, mockRPC, _, _ := setupTestLogFetcher(t)
+	lf.cfg.BloomPrefilter = true
+	ctx := context.Background()
+
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101}).Return(nil, errors.New("rpc error")).Once()
+
+	result, err := lf.FetchRange(ctx, 100, 101)
+	require.ErrorContains(t, err, "failed to apply bloom prefilter")
+	require.Nil(t, result)
+}
+
+// BenchmarkLogFetcher_BloomPrefilter reports the number of eth_getLogs calls needed to scan
+// a range where only a few chunks contain matching events, with and without the prefilter.
+// setupGroupedLogFetcher creates a LogFetcher for five addresses with at most two addresses per request.
+func setupGroupedLogFetcher(t *testing.T) (*LogFetcher, *rpcmocks.EthClient, *reorgmocks.Detector,
+	*storemocks.LogStore) {
+	t.Helper()
+
+	lf, mockRPC, mockReorg, mockStore := setupTestLogFetcher(t)
+
+	lf.cfg.MaxAddressesPerRequest = 2
+	lf.cfg.Addresses = nil
+	lf.cfg.Topics = nil
+	for i := range 5 {
+		addr := common.BigToAddress(big.NewInt(int64(i + 1)))
+		lf.cfg.Addresses = append(lf.cfg.Addresses, addr)
+		lf.cfg.Topics = append(lf.cfg.Topics, []common.Hash{common.BigToHash(big.NewInt(int64(0xa0 + i)))})
+		lf.cfg.AddressStartBlocks[addr] = 0
+	}
+
+	return lf, mockRPC, mockReorg, mockStore
+}
+
+// tooManyResultsError is the error of an RPC limiting the results of eth_getLogs.
+type tooManyResultsError struct{}
+
+func (tooManyResultsError) Error() string  { return "query returned too many results" }
+func (tooManyResultsError) ErrorCode() int { return -32005 }
+func (tooManyResultsError) ErrorData() any { return "Query returned more than 10000 results" }
+
+// addressGroup matches eth_getLogs queries for exactly the given addresses.
+func addressGroup(addresses ...common.Address) any {
+	return mock.MatchedBy(func(q ethereum.FilterQuery) bool {
+		return slices.Equal(q.Addresses, addresses)
+	})
+}
+
+func TestLogFetcher_BuildFilterQueries(t *testing.T) {
+	addresses := []common.Address{
+		common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03"),
+		common.HexToAddress("0x04"), common.HexToAddress("0x05"),
+	}
+	topics := [][]common.Hash{{common.HexToHash("0xaa"), common.HexToHash("0xbb")}}
+
+	tests := []struct {
+		name       string
+		maxPerCall int
+		expected   [][]common.Address
+	}{
+		{
+			name:       "unlimited",
+			maxPerCall: 0,
+			expected:   [][]common.Address{addresses},
+		},
+		{
+			name:       "limit above address count",
+			maxPerCall: 10,
+			expected:   [][]common.Address{addresses},
+		},
+		{
+			name:       "limit equal to address count",
+			maxPerCall: 5,
+			expected:   [][]common.Address{addresses},
+		},
+		{
+			name:       "uneven groups",
+			maxPerCall: 2,
+			expected:   [][]common.Address{addresses[0:2], addresses[2:4], addresses[4:5]},
+		},
+		{
+			name:       "one address per group",
+			maxPerCall: 1,
+			expected: [][]common.Address{
+				addresses[0:1], addresses[1:2], addresses[2:3], addresses[3:4], addresses[4:5],
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lf, _, _, _ := setupTestLogFetcher(t) //nolint:dogsled
+			lf.cfg.MaxAddressesPerRequest = tt.maxPerCall
+
+			queries := lf.buildFilterQueries(100, 199, addresses, topics)
+			require.Len(t, queries, len(tt.expected))
+
+			for i, query := range queries {
+				require.Equal(t, tt.expected[i], query.Addresses)
+				// Every group keeps the full topic filter, so the groups return what a single query would
+				require.Equal(t, topics, query.Topics)
+				require.Equal(t, big.NewInt(100), query.FromBlock)
+				require.Equal(t, big.NewInt(199), query.ToBlock)
+			}
+		})
+	}
+}
+
+func TestLogFetcher_FetchRange_AddressGroups(t *testing.T) {
+	lf, mockRPC, mockReorg, mockStore := setupGroupedLogFetcher(t)
+	ctx := context.Background()
+
+	addrs := lf.cfg.Addresses
+	logAt := func(addr common.Address, blockNum uint64, index uint) types.Log {
+		return types.Log{BlockNumber: blockNum, Index: index, Address: addr}
+	}
+
+	mockRPC.EXPECT().GetLogs(mock.Anything, addressGroup(addrs[0], addrs[1])).
+		Return([]types.Log{logAt(addrs[1], 102, 4), logAt(addrs[0], 100, 1)}, nil).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, addressGroup(addrs[2], addrs[3])).
+		Return([]types.Log{logAt(addrs[2], 101, 0), logAt(addrs[3], 102, 2)}, nil).Once()
+	// A log returned twice, e.g. by a load-balanced RPC, is kept once
+	mockRPC.EXPECT().GetLogs(mock.Anything, addressGroup(addrs[4])).
+		Return([]types.Log{logAt(addrs[4], 100, 0), logAt(addrs[4], 100, 0)}, nil).Once()
+
+	// The logs are the logs a single query for all addresses returns, in chain order
+	expectedLogs := []types.Log{
+		logAt(addrs[4], 100, 0),
+		logAt(addrs[0], 100, 1),
+		logAt(addrs[2], 101, 0),
+		logAt(addrs[3], 102, 2),
+		logAt(addrs[1], 102, 4),
+	}
+
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, expectedLogs,
+		uint64(100), uint64(102)).Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, expectedLogs, uint64(100), uint64(102)).
+		Return([]*types.Header{}, nil).Once()
+
+	result, err := lf.FetchRange(ctx, 100, 102)
+	require.NoError(t, err)
+	require.Equal(t, expectedLogs, result.Logs)
+	require.Equal(t, uint64(100), result.FromBlock)
+	require.Equal(t, uint64(102), result.ToBlock)
+}
+
+func TestLogFetcher_FetchRange_AddressGroupNarrowed(t *testing.T) {
+	lf, mockRPC, mockReorg, mockStore := setupGroupedLogFetcher(t)
+	ctx := context.Background()
+
+	addrs := lf.cfg.Addresses
+
+	// The second group has too many logs for the range and is split in half
+	mockRPC.EXPECT().GetLogs(mock.Anything, addressGroup(addrs[0], addrs[1])).
+		Return([]types.Log{{BlockNumber: 100, Address: addrs[0]}, {BlockNumber: 103, Address: addrs[1]}}, nil).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.MatchedBy(func(q ethereum.FilterQuery) bool {
+		return slices.Equal(q.Addresses, addrs[2:4]) && q.ToBlock.Uint64() == 103
+	})).Return(nil, tooManyResultsError{}).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.MatchedBy(func(q ethereum.FilterQuery) bool {
+		return slices.Equal(q.Addresses, addrs[2:4]) && q.ToBlock.Uint64() == 101
+	})).Return([]types.Log{{BlockNumber: 101, Address: addrs[2]}}, nil).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, addressGroup(addrs[4])).
+		Return([]types.Log{}, nil).Once()
+
+	// Logs after the range covered by every group are dropped, they are fetched again with the next chunk
+	expectedLogs := []types.Log{{BlockNumber: 100, Address: addrs[0]}, {BlockNumber: 101, Address: addrs[2]}}
+
+	mockStore.EXPECT().StoreLogs(mock.Anything, lf.cfg.Addresses, lf.cfg.Topics, expectedLogs,
+		uint64(100), uint64(103)).Return(nil).Once()
+	mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, expectedLogs, uint64(100), uint64(103)).
+		Return([]*types.Header{}, nil).Once()
+
+	result, err := lf.FetchRange(ctx, 100, 103)
+	require.NoError(t, err)
+	require.Equal(t, expectedLogs, result.Logs)
+	require.Equal(t, uint64(100), result.FromBlock)
+	require.Equal(t, uint64(101), result.ToBlock)
+}
+
+func TestLogFetcher_FetchRange_AddressGroupError(t *testing.T) {
+	lf, mockRPC, _, _ := setupGroupedLogFetcher(t)
+	ctx := context.Background()
+
+	addrs := lf.cfg.Addresses
+
+	mockRPC.EXPECT().GetLogs(mock.Anything, addressGroup(addrs[2], addrs[3])).
+		Return(nil, errors.New("log fetch error")).Once()
+	mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).Return([]types.Log{}, nil).Maybe()
+
+	result, err := lf.FetchRange(ctx, 100, 102)
+	require.ErrorContains(t, err, "address group 1: log fetch error")
+	require.Nil(t, result)
+}
+
+func BenchmarkLogFetcher_BloomPrefilter(b *testing.B) {
+	const (
+		chunkSize      = 100
+		chunks         = 50
+		matchingPeriod = 10 // every 10th chunk contains a matching event
+	)
+
+	for _, prefilter := range []bool{false, true} {
+		name := "without_prefilter"
+		if prefilter {
+			name = "with_prefilter"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			mockRPC := rpcmocks.NewEthClient(b)
+			mockReorg := reorgmocks.NewDetector(b)
+			mockStore := storemocks.NewLogStore(b)
+
+			addr := common.HexToAddress("0x1111111111111111111111111111111111111111")
+			topic := common.HexToHash("0xaaaa")
+			otherAddr := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+			lf := NewLogFetcher(LogFetcherConfig{
+				ChunkSize:          chunkSize,
+				Finality:           itypes.FinalityFinalized,
+				Addresses:          []common.Address{addr},
+				Topics:             [][]common.Hash{{topic}},
+				AddressStartBlocks: map[common.Address]uint64{addr: 0},
+				BloomPrefilter:     prefilter,
+			}, logger.NewNopLogger(), mockRPC, mockReorg, mockStore)
+
+			getLogsCalls := 0
+			mockRPC.EXPECT().GetLogs(mock.Anything, mock.Anything).
+				Run(func(context.Context, ethereum.FilterQuery) { getLogsCalls++ }).
+				Return([]types.Log{}, nil).Maybe()
+			mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, mock.Anything).
+				RunAndReturn(func(_ context.Context, blockNums []uint64) ([]*types.Header, error) {
+					headers := make([]*types.Header, len(blockNums))
+					for i, blockNum := range blockNums {
+						headers[i] = createBloomHeader(blockNum, otherAddr, topic)
+						if (blockNum/chunkSize)%matchingPeriod == 0 && blockNum%chunkSize == chunkSize/2 {
+							headers[i] = createBloomHeader(blockNum, addr, topic)
+						}
+					}
+					return headers, nil
+				}).Maybe()
+			mockStore.EXPECT().StoreLogs(mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+				mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockReorg.EXPECT().VerifyAndRecordBlocks(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(nil, nil).Maybe()
+
+			ctx := context.Background()
+
+			for b.Loop() {
+				for chunk := range uint64(chunks) {
+					from := chunk * chunkSize
+					if _, err := lf.FetchRange(ctx, from, from+chunkSize-1); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+
+			b.ReportMetric(float64(getLogsCalls)/float64(b.N), "getLogs/op")
+		})
+	}
+}
@@ -1,19 +1,67 @@
 package fetcher
 
 import (
+	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 var (
-	finalizedBlock = promauto.NewGauge(
+	finalizedBlock = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "chainindexor_finalized_block",
 			Help: "The current finalized block number from RPC",
 		},
+		[]string{"chain_id"},
+	)
+
+	fetcherChunkSize = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "chainindexor_fetcher_chunk_size",
+			Help: "The number of blocks fetched per request, as adjusted by the adaptive chunk sizer",
+		},
+		[]string{"chain_id"},
+	)
+
+	backfillBlocksRemaining = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "chainindexor_backfill_blocks_remaining",
+			Help: "The number of blocks left to fetch before the backfill reaches the finalized block",
+		},
+		[]string{"chain_id"},
+	)
+
+	backfillBlocksPerSecond = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "chainindexor_backfill_blocks_per_second",
+			Help: "The average number of blocks fetched per second since the backfill started",
+		},
+		[]string{"chain_id"},
+	)
+
+	bloomPrefilterSkipped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "chainindexor_bloom_prefilter_skipped_total",
+			Help: "Number of eth_getLogs calls skipped because no block bloom filter matched",
+		},
+		[]string{"chain_id"},
 	)
 )
 
-func FinalizedBlockLogSet(blockNum uint64) {
-	finalizedBlock.Set(float64(blockNum))
+func FinalizedBlockLogSet(chainID, blockNum uint64) {
+	finalizedBlock.WithLabelValues(metrics.ChainLabel(chainID)).Set(float64(blockNum))
+}
+
+func FetcherChunkSizeSet(chainID, chunkSize uint64) {
+	fetcherChunkSize.WithLabelValues(metrics.ChainLabel(chainID)).Set(float64(chunkSize))
+}
+
+func BackfillProgressSet(chainID, blocksRemaining uint64, blocksPerSecond float64) {
+	chain := metrics.ChainLabel(chainID)
+	backfillBlocksRemaining.WithLabelValues(chain).Set(float64(blocksRemaining))
+	backfillBlocksPerSecond.WithLabelValues(chain).Set(blocksPerSecond)
+}
+
+func BloomPrefilterSkippedInc(chainID uint64) {
+	bloomPrefilterSkipped.WithLabelValues(metrics.ChainLabel(chainID)).Inc()
 }
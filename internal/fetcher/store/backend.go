@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/russross/meddler"
+)
+
+// Backend covers what differs between the databases a LogStore can run on.
+// The rest of the LogStore SQL is shared, written with ? placeholders.
+type Backend interface {
+	// Name returns the name of the database driver.
+	Name() string
+
+	// InsertLog inserts log within tx. Inserting a log that is already stored
+	// returns an error on SQLite and is a no-op on PostgreSQL, and either way
+	// the transaction can still be used afterwards.
+	InsertLog(ctx context.Context, tx *sql.Tx, log *dbLog) error
+
+	// DatabaseSize returns the size of the database in bytes.
+	DatabaseSize(ctx context.Context, database *sql.DB) (int64, error)
+}
+
+var (
+	_ Backend = (*sqliteBackend)(nil)
+	_ Backend = (*postgresBackend)(nil)
+)
+
+// sqliteBackend is the Backend of a SQLite database stored at path.
+type sqliteBackend struct {
+	path string
+}
+
+func (b *sqliteBackend) Name() string {
+	return "sqlite"
+}
+
+func (b *sqliteBackend) InsertLog(_ context.Context, tx *sql.Tx, log *dbLog) error {
+	return meddler.Insert(tx, "event_logs", log)
+}
+
+func (b *sqliteBackend) DatabaseSize(context.Context, *sql.DB) (int64, error) {
+	return db.DBTotalSize(b.path)
+}
+
+// postgresBackend is the Backend of a PostgreSQL database.
+type postgresBackend struct{}
+
+func (b *postgresBackend) Name() string {
+	return "postgres"
+}
+
+// InsertLog upserts the log. A failed statement aborts a PostgreSQL transaction,
+// so duplicates are skipped by the database instead of failing the insert.
+func (b *postgresBackend) InsertLog(ctx context.Context, tx *sql.Tx, log *dbLog) error {
+	columns, err := meddler.PostgreSQL.ColumnsQuoted(log, false)
+	if err != nil {
+		return err
+	}
+
+	placeholders, err := meddler.PostgreSQL.PlaceholdersString(log, false)
+	if err != nil {
+		return err
+	}
+
+	values, err := meddler.PostgreSQL.Values(log, false)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO event_logs (%s) VALUES (%s)
+		ON CONFLICT (address, block_number, tx_hash, log_index) DO NOTHING`, columns, placeholders)
+
+	_, err = tx.ExecContext(ctx, query, values...)
+
+	return err
+}
+
+func (b *postgresBackend) DatabaseSize(ctx context.Context, database *sql.DB) (int64, error) {
+	var size int64
+	if err := database.QueryRowContext(ctx, "SELECT pg_database_size(current_database())").Scan(&size); err != nil {
+		return 0, fmt.Errorf("failed to get database size: %w", err)
+	}
+
+	return size, nil
+}
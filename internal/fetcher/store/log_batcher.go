@@ -0,0 +1,128 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+)
+
+// queuedCoverage is the coverage recorded by logs queued in a LogBatcher.
+type queuedCoverage struct {
+	addresses []ethcommon.Address
+	topics    [][]ethcommon.Hash
+	fromBlock uint64
+	toBlock   uint64
+}
+
+// LogBatcher batches the logs stored by the log stores of a database into few large transactions.
+// It keeps the coverage of the queued logs, so the log stores do not report it as unsynced before
+// it is flushed. Log stores sharing a database share its batcher, see LogStore.SetLogBatcher.
+type LogBatcher struct {
+	batcher *db.WriteBatcher
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]queuedCoverage
+}
+
+// NewLogBatcher creates a log batcher committing to the given database.
+func NewLogBatcher(
+	database *sql.DB,
+	cfg config.WriteBatchConfig,
+	maintenance db.Maintenance,
+	log *logger.Logger,
+) *LogBatcher {
+	return &LogBatcher{
+		batcher: db.NewWriteBatcher(database, cfg, maintenance, log),
+		pending: make(map[uint64]queuedCoverage),
+	}
+}
+
+// Run flushes the queued logs periodically until the context is cancelled, then flushes them once more.
+func (b *LogBatcher) Run(ctx context.Context) {
+	b.batcher.Run(ctx)
+}
+
+// Flush commits the queued logs.
+func (b *LogBatcher) Flush(ctx context.Context) error {
+	return b.batcher.Flush(ctx)
+}
+
+// queue queues the write of logs with the given coverage. Once the batch holding it is flushed,
+// or dropped because the flush failed, its coverage is forgotten and read from the database again.
+func (b *LogBatcher) queue(
+	ctx context.Context,
+	rows int,
+	coverage queuedCoverage,
+	write func(ctx context.Context, tx *sql.Tx) error,
+) error {
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.pending[id] = coverage
+	b.mu.Unlock()
+
+	return b.batcher.Add(ctx, db.BatchedWrite{
+		Rows:  rows,
+		Write: write,
+		Flushed: func(error) {
+			b.mu.Lock()
+			delete(b.pending, id)
+			b.mu.Unlock()
+		},
+	})
+}
+
+// topicCoverage returns the queued coverage of the address and topic.
+func (b *LogBatcher) topicCoverage(address ethcommon.Address, topic ethcommon.Hash) []*dbTopicCoverage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var coverage []*dbTopicCoverage
+	for _, queued := range b.pending {
+		for i, queuedAddress := range queued.addresses {
+			if queuedAddress != address {
+				continue
+			}
+
+			for _, queuedTopic := range queued.topics[i] {
+				if queuedTopic == topic {
+					coverage = append(coverage, &dbTopicCoverage{
+						Address:   address,
+						Topic0:    topic,
+						FromBlock: queued.fromBlock,
+						ToBlock:   queued.toBlock,
+					})
+				}
+			}
+		}
+	}
+
+	return coverage
+}
+
+// oldestBlock returns the first block of the queued topic coverage of the address,
+// and false if none is queued.
+func (b *LogBatcher) oldestBlock(address ethcommon.Address) (uint64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var (
+		oldest uint64
+		found  bool
+	)
+	for _, queued := range b.pending {
+		for i, queuedAddress := range queued.addresses {
+			if queuedAddress == address && len(queued.topics[i]) > 0 && (!found || queued.fromBlock < oldest) {
+				oldest, found = queued.fromBlock, true
+			}
+		}
+	}
+
+	return oldest, found
+}
@@ -1,10 +1,13 @@
 package store
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
@@ -13,23 +16,40 @@ import (
 	"github.com/goran-ethernal/ChainIndexor/internal/db"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
+	"github.com/goran-ethernal/ChainIndexor/internal/tracing"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
 	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
 	"github.com/russross/meddler"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 )
 
 const maxConcurrency = 10
 
+// maxTopics is the number of topics a log can have, stored in the topic0 to topic3 columns.
+const maxTopics = 4
+
+// Rough relative row sizes used to estimate the space taken by each table.
+// event_logs are typically larger (addresses, hashes, data), while
+// coverage tables are smaller (just addresses and block numbers).
+const (
+	eventLogWeight = 3
+	coverageWeight = 1
+)
+
 var _ store.LogStore = (*LogStore)(nil)
 
 // LogStore implements LogStore interface using SQLite as the backend.
 type LogStore struct {
 	dbConfig               config.DatabaseConfig
 	db                     *sql.DB
+	backend                Backend
 	log                    *logger.Logger
 	retentionPolicy        *config.RetentionPolicyConfig
 	maintenanceCoordinator db.Maintenance
+	batcher                *LogBatcher
+	chainID                uint64
 }
 
 // NewLogStore creates a new SQLite-backed LogStore.
@@ -39,9 +59,22 @@ func NewLogStore(
 	dbConfig config.DatabaseConfig,
 	retentionPolicy *config.RetentionPolicyConfig,
 	maintenanceCoordinator db.Maintenance,
+) *LogStore {
+	return newLogStore(database, &sqliteBackend{path: dbConfig.Path}, log, dbConfig,
+		retentionPolicy, maintenanceCoordinator)
+}
+
+func newLogStore(
+	database *sql.DB,
+	backend Backend,
+	log *logger.Logger,
+	dbConfig config.DatabaseConfig,
+	retentionPolicy *config.RetentionPolicyConfig,
+	maintenanceCoordinator db.Maintenance,
 ) *LogStore {
 	return &LogStore{
 		db:                     database,
+		backend:                backend,
 		log:                    log,
 		dbConfig:               dbConfig,
 		retentionPolicy:        retentionPolicy,
@@ -49,15 +82,47 @@ func NewLogStore(
 	}
 }
 
-// GetLogs retrieves logs for the given address and block range.
+// SetLogBatcher makes StoreLogs queue the logs in the given batcher instead of committing them right away.
+// The log stores of a database share a single batcher.
+func (s *LogStore) SetLogBatcher(batcher *LogBatcher) {
+	s.batcher = batcher
+}
+
+// SetChainID sets the chain ID that labels the log store metrics.
+func (s *LogStore) SetChainID(chainID uint64) {
+	s.chainID = chainID
+}
+
+// flushQueuedLogs commits the logs queued in the log batcher, so they can be read or rolled back.
+// It is called before taking the operation lock, which the flush takes itself.
+func (s *LogStore) flushQueuedLogs(ctx context.Context) error {
+	if s.batcher == nil {
+		return nil
+	}
+
+	if err := s.batcher.Flush(ctx); err != nil {
+		metrics.DBErrorsInc(s.chainID, s.dbConfig.Path, "insert_error")
+		return fmt.Errorf("failed to flush queued logs: %w", err)
+	}
+
+	return nil
+}
+
+// GetLogs retrieves logs for the given address and block range, optionally filtered by their topics.
 func (s *LogStore) GetLogs(
 	ctx context.Context,
 	address ethcommon.Address,
 	fromBlock, toBlock uint64,
+	topics []*ethcommon.Hash,
 ) ([]types.Log, []store.CoverageRange, error) {
+	if err := s.flushQueuedLogs(ctx); err != nil {
+		return nil, nil, err
+	}
+
 	// Acquire operation lock if maintenance coordinator is available
 	unlock := s.maintenanceCoordinator.AcquireOperationLock()
 	defer unlock()
+	defer s.observeOperation("get_logs", time.Now())
 
 	// Get coverage information
 	const coverageQuery = `
@@ -65,15 +130,12 @@ func (s *LogStore) GetLogs(
 		WHERE address = ? AND from_block <= ? AND to_block >= ?
 		ORDER BY from_block ASC
 	`
-	start := time.Now()
-	metrics.DBQueryInc(s.dbConfig.Path, "select")
 	var dbCoverages []*dbCoverage
 	err := meddler.QueryAll(s.db, &dbCoverages, coverageQuery, address.Hex(), toBlock, fromBlock)
 	if err != nil {
-		metrics.DBErrorsInc(s.dbConfig.Path, "query_error")
+		metrics.DBErrorsInc(s.chainID, s.dbConfig.Path, "query_error")
 		return nil, nil, fmt.Errorf("failed to query coverage: %w", err)
 	}
-	metrics.DBQueryDuration(s.dbConfig.Path, "select", time.Since(start))
 
 	coverage := make([]store.CoverageRange, len(dbCoverages))
 	for i, c := range dbCoverages {
@@ -84,20 +146,24 @@ func (s *LogStore) GetLogs(
 	}
 
 	// Get logs for the requested range
-	const logsQuery = `
+	logsQuery := `
 		SELECT * FROM event_logs
-		WHERE address = ? AND block_number >= ? AND block_number <= ?
-		ORDER BY block_number ASC, log_index ASC
-	`
-	start = time.Now()
-	metrics.DBQueryInc(s.dbConfig.Path, "select")
+		WHERE address = ? AND block_number >= ? AND block_number <= ?`
+	args := []any{address.Hex(), fromBlock, toBlock}
+
+	topicFilter, topicArgs, err := topicsFilter(topics)
+	if err != nil {
+		return nil, nil, err
+	}
+	logsQuery += topicFilter + " ORDER BY block_number ASC, log_index ASC"
+	args = append(args, topicArgs...)
+
 	var dbLogs []*dbLog
-	err = meddler.QueryAll(s.db, &dbLogs, logsQuery, address.Hex(), fromBlock, toBlock)
+	err = meddler.QueryAll(s.db, &dbLogs, logsQuery, args...)
 	if err != nil {
-		metrics.DBErrorsInc(s.dbConfig.Path, "query_error")
+		metrics.DBErrorsInc(s.chainID, s.dbConfig.Path, "query_error")
 		return nil, nil, fmt.Errorf("failed to query logs: %w", err)
 	}
-	metrics.DBQueryDuration(s.dbConfig.Path, "select", time.Since(start))
 
 	logs := make([]types.Log, len(dbLogs))
 	for i, dl := range dbLogs {
@@ -107,8 +173,110 @@ func (s *LogStore) GetLogs(
 	return logs, coverage, nil
 }
 
+// GetLogsBatch retrieves logs for the given addresses and block range, optionally filtered by their topics.
+// Unlike calling GetLogs for each address, it reads the logs and the coverage of all addresses
+// with a single query each. Every address has an entry in the returned maps.
+func (s *LogStore) GetLogsBatch(
+	ctx context.Context,
+	addresses []ethcommon.Address,
+	fromBlock, toBlock uint64,
+	topics []*ethcommon.Hash,
+) (map[ethcommon.Address][]types.Log, map[ethcommon.Address][]store.CoverageRange, error) {
+	logs := make(map[ethcommon.Address][]types.Log, len(addresses))
+	coverage := make(map[ethcommon.Address][]store.CoverageRange, len(addresses))
+	for _, address := range addresses {
+		logs[address] = []types.Log{}
+		coverage[address] = []store.CoverageRange{}
+	}
+
+	if len(addresses) == 0 {
+		return logs, coverage, nil
+	}
+
+	topicFilter, topicArgs, err := topicsFilter(topics)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.flushQueuedLogs(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	// Acquire operation lock if maintenance coordinator is available
+	unlock := s.maintenanceCoordinator.AcquireOperationLock()
+	defer unlock()
+	defer s.observeOperation("get_logs_batch", time.Now())
+
+	addressFilter := "address IN (?" + strings.Repeat(", ?", len(addresses)-1) + ")"
+	addressArgs := make([]any, 0, len(addresses))
+	for _, address := range addresses {
+		addressArgs = append(addressArgs, address.Hex())
+	}
+
+	// Get coverage information
+	coverageQuery := `
+		SELECT * FROM log_coverage
+		WHERE ` + addressFilter + ` AND from_block <= ? AND to_block >= ?
+		ORDER BY address, from_block ASC`
+	var dbCoverages []*dbCoverage
+	err = meddler.QueryAll(s.db, &dbCoverages, coverageQuery, slices.Concat(addressArgs, []any{toBlock, fromBlock})...)
+	if err != nil {
+		metrics.DBErrorsInc(s.chainID, s.dbConfig.Path, "query_error")
+		return nil, nil, fmt.Errorf("failed to query coverage: %w", err)
+	}
+
+	for _, c := range dbCoverages {
+		coverage[c.Address] = append(coverage[c.Address], store.CoverageRange{
+			FromBlock: c.FromBlock,
+			ToBlock:   c.ToBlock,
+		})
+	}
+
+	// Get logs for the requested range
+	logsQuery := `
+		SELECT * FROM event_logs
+		WHERE ` + addressFilter + ` AND block_number >= ? AND block_number <= ?` +
+		topicFilter + " ORDER BY address, block_number ASC, log_index ASC"
+	args := slices.Concat(addressArgs, []any{fromBlock, toBlock}, topicArgs)
+
+	var dbLogs []*dbLog
+	err = meddler.QueryAll(s.db, &dbLogs, logsQuery, args...)
+	if err != nil {
+		metrics.DBErrorsInc(s.chainID, s.dbConfig.Path, "query_error")
+		return nil, nil, fmt.Errorf("failed to query logs: %w", err)
+	}
+
+	for _, dl := range dbLogs {
+		logs[dl.Address] = append(logs[dl.Address], s.dbLogToEthLog(dl))
+	}
+
+	return logs, coverage, nil
+}
+
+// topicsFilter builds the conditions of a logs query matching the set topics.
+// When topics[i] is set, only logs whose topic i equals it match; nil entries match any topic.
+func topicsFilter(topics []*ethcommon.Hash) (string, []any, error) {
+	var filter strings.Builder
+	args := make([]any, 0, len(topics))
+
+	for i, topic := range topics {
+		if topic == nil {
+			continue
+		}
+		if i >= maxTopics {
+			return "", nil, fmt.Errorf("logs have at most %d topics, got a filter for topic%d", maxTopics, i)
+		}
+
+		fmt.Fprintf(&filter, " AND topic%d = ?", i)
+		args = append(args, topic.Hex())
+	}
+
+	return filter.String(), args, nil
+}
+
 // GetUnsyncedTopics checks which address-topic combinations have not been fully synced up to the given block.
 // For each address, it returns the list of topics that are missing coverage up to upToBlock.
+// The coverage of logs queued in the log batcher counts as synced.
 func (s *LogStore) GetUnsyncedTopics(
 	ctx context.Context,
 	addresses []ethcommon.Address,
@@ -125,6 +293,10 @@ func (s *LogStore) GetUnsyncedTopics(
 	for i, address := range addresses {
 		addressTopics := topics[i]
 
+		// The queued coverage is read before the stored one, so coverage flushed meanwhile is read
+		// from the database
+		queuedOldest, queued := s.queuedOldestBlock(address)
+
 		// Get the oldest block still in database for this address-topic combination
 		// This accounts for retention policy pruning - we don't want to re-sync pruned data
 		var oldestBlock sql.NullInt64
@@ -142,8 +314,13 @@ func (s *LogStore) GetUnsyncedTopics(
 		if oldestBlock.Valid && oldestBlock.Int64 > 0 {
 			startBlock = uint64(oldestBlock.Int64)
 		}
+		if queued && (!oldestBlock.Valid || queuedOldest < startBlock) {
+			startBlock = queuedOldest
+		}
 
 		for _, topic := range addressTopics {
+			queuedCoverage := s.queuedTopicCoverage(address, topic)
+
 			// Query topic coverage for this address-topic combination
 			const topicCoverageQuery = `
 				SELECT from_block, to_block FROM topic_coverage
@@ -158,18 +335,26 @@ func (s *LogStore) GetUnsyncedTopics(
 				return nil, fmt.Errorf("failed to query topic coverage: %w", err)
 			}
 
+			for _, c := range queuedCoverage {
+				if c.ToBlock >= startBlock && c.FromBlock <= upToBlock {
+					dbCoverages = append(dbCoverages, c)
+				}
+			}
+			if len(queuedCoverage) > 0 {
+				slices.SortFunc(dbCoverages, func(a, b *dbTopicCoverage) int {
+					return cmp.Compare(a.FromBlock, b.FromBlock)
+				})
+			}
+
 			// Check if there's a gap in coverage from startBlock to upToBlock
 			// We need continuous coverage from startBlock (accounting for pruning) to upToBlock
 			if !s.hasCompleteCoverage(dbCoverages, startBlock, upToBlock) {
-				var coverage store.CoverageRange
-				if len(dbCoverages) > 0 {
-					coverage = store.CoverageRange{
-						FromBlock: dbCoverages[0].FromBlock,
-						ToBlock:   dbCoverages[len(dbCoverages)-1].ToBlock,
-					}
+				coverage := make([]store.CoverageRange, len(dbCoverages))
+				for i, c := range dbCoverages {
+					coverage[i] = store.CoverageRange{FromBlock: c.FromBlock, ToBlock: c.ToBlock}
 				}
 
-				result.AddTopic(address, topic, coverage)
+				result.AddTopicRanges(address, topic, startBlock, coverage)
 			}
 		}
 	}
@@ -177,6 +362,24 @@ func (s *LogStore) GetUnsyncedTopics(
 	return result, nil
 }
 
+// queuedTopicCoverage returns the coverage of the address and topic queued in the log batcher.
+func (s *LogStore) queuedTopicCoverage(address ethcommon.Address, topic ethcommon.Hash) []*dbTopicCoverage {
+	if s.batcher == nil {
+		return nil
+	}
+
+	return s.batcher.topicCoverage(address, topic)
+}
+
+// queuedOldestBlock returns the first block of the topic coverage of the address queued in the log batcher.
+func (s *LogStore) queuedOldestBlock(address ethcommon.Address) (uint64, bool) {
+	if s.batcher == nil {
+		return 0, false
+	}
+
+	return s.batcher.oldestBlock(address)
+}
+
 // hasCompleteCoverage checks if the coverage ranges fully cover [fromBlock, toBlock]
 func (s *LogStore) hasCompleteCoverage(coverages []*dbTopicCoverage, fromBlock, toBlock uint64) bool {
 	if len(coverages) == 0 {
@@ -208,16 +411,22 @@ func (s *LogStore) hasCompleteCoverage(coverages []*dbTopicCoverage, fromBlock,
 }
 
 // StoreLogs saves logs to the store for the given address and block range.
+// With a log batcher set, the logs are queued and committed by the next flush of the batcher, so they are
+// not durable when StoreLogs returns: the caller flushes the batcher before acting on them.
 func (s *LogStore) StoreLogs(
 	ctx context.Context,
 	addresses []ethcommon.Address,
 	topics [][]ethcommon.Hash,
 	logs []types.Log,
 	fromBlock, toBlock uint64,
-) error {
-	// Acquire operation lock if maintenance coordinator is available
-	unlock := s.maintenanceCoordinator.AcquireOperationLock()
-	defer unlock()
+) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "LogStore.StoreLogs", trace.WithAttributes(
+		attribute.Int64("from_block", int64(fromBlock)),
+		attribute.Int64("to_block", int64(toBlock)),
+		attribute.Int("addresses", len(addresses)),
+		attribute.Int("logs", len(logs)),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
 
 	if len(addresses) != len(topics) {
 		return fmt.Errorf("addresses and topics length mismatch: %d vs %d", len(addresses), len(topics))
@@ -228,13 +437,23 @@ func (s *LogStore) StoreLogs(
 		return nil
 	}
 
+	// The retention applied below is recorded as an operation of its own
 	start := time.Now()
-	metrics.DBQueryInc(s.dbConfig.Path, "insert")
-	if err := s.storeLogsInternal(ctx, addresses, topics, logs, fromBlock, toBlock); err != nil {
-		metrics.DBErrorsInc(s.dbConfig.Path, "insert_error")
+	if s.batcher != nil {
+		// Queued before taking the operation lock, which the flush of a full batch takes
+		err = s.queueLogs(ctx, addresses, topics, logs, fromBlock, toBlock)
+	} else {
+		err = s.storeLogsInternal(ctx, addresses, topics, logs, fromBlock, toBlock)
+	}
+	s.observeOperation("store_logs", start)
+	if err != nil {
+		metrics.DBErrorsInc(s.chainID, s.dbConfig.Path, "insert_error")
 		return err
 	}
-	metrics.DBQueryDuration(s.dbConfig.Path, "insert", time.Since(start))
+
+	// Acquire operation lock if maintenance coordinator is available
+	unlock := s.maintenanceCoordinator.AcquireOperationLock()
+	defer unlock()
 
 	// Apply retention policy if enabled
 	if err := s.applyRetentionIfNeeded(ctx); err != nil {
@@ -245,6 +464,26 @@ func (s *LogStore) StoreLogs(
 	return nil
 }
 
+// queueLogs queues the logs and their coverage in the log batcher.
+func (s *LogStore) queueLogs(
+	ctx context.Context,
+	addresses []ethcommon.Address,
+	topics [][]ethcommon.Hash,
+	logs []types.Log,
+	fromBlock, toBlock uint64,
+) error {
+	rows := len(logs) + len(addresses)
+	for _, addressTopics := range topics {
+		rows += len(addressTopics)
+	}
+
+	coverage := queuedCoverage{addresses: addresses, topics: topics, fromBlock: fromBlock, toBlock: toBlock}
+
+	return s.batcher.queue(ctx, rows, coverage, func(ctx context.Context, tx *sql.Tx) error {
+		return s.writeLogs(ctx, tx, addresses, topics, logs, fromBlock, toBlock)
+	})
+}
+
 // storeLogsInternal handles the actual log storage
 func (s *LogStore) storeLogsInternal(
 	ctx context.Context,
@@ -253,6 +492,10 @@ func (s *LogStore) storeLogsInternal(
 	logs []types.Log,
 	fromBlock, toBlock uint64,
 ) error {
+	// Acquire operation lock if maintenance coordinator is available
+	unlock := s.maintenanceCoordinator.AcquireOperationLock()
+	defer unlock()
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -263,6 +506,29 @@ func (s *LogStore) storeLogsInternal(
 		}
 	}()
 
+	if err := s.writeLogs(ctx, tx, addresses, topics, logs, fromBlock, toBlock); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.log.Debugf("Stored %d logs for %d addresses, blocks %d-%d",
+		len(logs), len(addresses), fromBlock, toBlock)
+
+	return nil
+}
+
+// writeLogs inserts the logs and records their coverage within the transaction.
+func (s *LogStore) writeLogs(
+	ctx context.Context,
+	tx *sql.Tx,
+	addresses []ethcommon.Address,
+	topics [][]ethcommon.Hash,
+	logs []types.Log,
+	fromBlock, toBlock uint64,
+) error {
 	g, errCtx := errgroup.WithContext(ctx)
 	g.SetLimit(maxConcurrency)
 
@@ -271,7 +537,7 @@ func (s *LogStore) storeLogsInternal(
 		for _, log := range logs {
 			dbLog := s.ethLogToDbLog(&log)
 
-			err := meddler.Insert(tx, "event_logs", dbLog)
+			err := s.backend.InsertLog(errCtx, tx, dbLog)
 			if err != nil {
 				// If insert fails due to unique constraint, ignore (log already exists)
 				// This can happen when re-indexing the same range
@@ -318,25 +584,20 @@ func (s *LogStore) storeLogsInternal(
 		})
 	}
 
-	if err := g.Wait(); err != nil {
-		return err
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	s.log.Debugf("Stored %d logs for %d addresses, blocks %d-%d",
-		len(logs), len(addresses), fromBlock, toBlock)
-
-	return nil
+	return g.Wait()
 }
 
 // HandleReorg marks logs as removed starting from the given block number.
+// Queued logs are flushed first, so the reorged ones are removed with the stored ones.
 func (s *LogStore) HandleReorg(ctx context.Context, fromBlock uint64) error {
+	if err := s.flushQueuedLogs(ctx); err != nil {
+		return err
+	}
+
 	// Acquire operation lock if maintenance coordinator is available
 	unlock := s.maintenanceCoordinator.AcquireOperationLock()
 	defer unlock()
+	defer s.observeOperation("handle_reorg", time.Now())
 
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -419,6 +680,8 @@ func (s *LogStore) HandleReorg(ctx context.Context, fromBlock uint64) error {
 }
 
 func (s *LogStore) pruneLogsBeforeBlock(ctx context.Context, beforeBlock uint64) (uint64, error) {
+	defer s.observeOperation("prune_logs", time.Now())
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, fmt.Errorf("failed to begin transaction: %w", err)
@@ -478,12 +741,192 @@ func (s *LogStore) pruneLogsBeforeBlock(ctx context.Context, beforeBlock uint64)
 
 	s.log.Infof("Pruned %d logs before block %d", rowsAffected, beforeBlock)
 
-	RetentionBlocksPrunedInc("downloader-log-store", blockCount)
-	RetentionLogsPrunedInc("downloader-log-store", uint64(rowsAffected))
+	RetentionBlocksPrunedInc(s.chainID, "downloader-log-store", blockCount)
+	RetentionLogsPrunedInc(s.chainID, "downloader-log-store", uint64(rowsAffected))
 
 	return blockCount, nil
 }
 
+// observeOperation records a log store operation that started at start in the database query metrics.
+// Operations are recorded as a whole, as "get_logs", "store_logs", "handle_reorg" and "prune_logs".
+func (s *LogStore) observeOperation(operation string, start time.Time) {
+	metrics.DBQueryInc(s.chainID, s.dbConfig.Path, operation)
+	metrics.DBQueryDuration(s.chainID, s.dbConfig.Path, operation, time.Since(start))
+}
+
+// CompactCoverage merges overlapping and adjacent coverage ranges of the same address
+// (and topic, for topic coverage) into a single range. Every stored chunk records its
+// own range, so compacting keeps the coverage tables small after long syncs or merges.
+func (s *LogStore) CompactCoverage(ctx context.Context) error {
+	unlock := s.maintenanceCoordinator.AcquireOperationLock()
+	defer unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			s.log.Errorf("failed to rollback transaction: %v", err)
+		}
+	}()
+
+	removedRanges, err := compactCoverageTable(ctx, tx, "log_coverage", "address")
+	if err != nil {
+		return err
+	}
+
+	removedTopicRanges, err := compactCoverageTable(ctx, tx, "topic_coverage", "address", "topic0")
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	CoverageRangesCompactedInc(s.chainID, "log_coverage", removedRanges)
+	CoverageRangesCompactedInc(s.chainID, "topic_coverage", removedTopicRanges)
+
+	s.log.Infof("Compacted coverage, merged %d log coverage and %d topic coverage ranges",
+		removedRanges, removedTopicRanges)
+
+	return nil
+}
+
+// coverageRow is a range of a coverage table, identified by the values of its key columns.
+type coverageRow struct {
+	key       []string
+	fromBlock uint64
+	toBlock   uint64
+}
+
+// compactCoverageTable rewrites the ranges of a coverage table, merging the ranges
+// that overlap or touch for the same key columns. It returns the number of ranges removed.
+func compactCoverageTable(ctx context.Context, tx *sql.Tx, table string, keyColumns ...string) (int, error) {
+	keys := strings.Join(keyColumns, ", ")
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(
+		"SELECT %s, from_block, to_block FROM %s ORDER BY %s, from_block", keys, table, keys))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var (
+		total  int
+		merged []coverageRow
+	)
+
+	for rows.Next() {
+		row := coverageRow{key: make([]string, len(keyColumns))}
+		dest := make([]any, 0, len(keyColumns)+2) //nolint:mnd
+		for i := range row.key {
+			dest = append(dest, &row.key[i])
+		}
+		dest = append(dest, &row.fromBlock, &row.toBlock)
+
+		if err := rows.Scan(dest...); err != nil {
+			return 0, fmt.Errorf("failed to scan %s: %w", table, err)
+		}
+		total++
+
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			if slices.Equal(last.key, row.key) && row.fromBlock <= last.toBlock+1 {
+				last.toBlock = max(last.toBlock, row.toBlock)
+				continue
+			}
+		}
+
+		merged = append(merged, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate %s: %w", table, err)
+	}
+
+	if len(merged) == total {
+		return 0, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+		return 0, fmt.Errorf("failed to clear %s: %w", table, err)
+	}
+
+	insertQuery := fmt.Sprintf("INSERT INTO %s (%s, from_block, to_block) VALUES (%s?, ?)",
+		table, keys, strings.Repeat("?, ", len(keyColumns)))
+
+	for _, row := range merged {
+		args := make([]any, 0, len(row.key)+2) //nolint:mnd
+		for _, key := range row.key {
+			args = append(args, key)
+		}
+		args = append(args, row.fromBlock, row.toBlock)
+
+		if _, err := tx.ExecContext(ctx, insertQuery, args...); err != nil {
+			return 0, fmt.Errorf("failed to insert %s: %w", table, err)
+		}
+	}
+
+	return total - len(merged), nil
+}
+
+// errSnapshotNotSupported is returned by the snapshot operations of a PostgreSQL log store,
+// which is backed up with the PostgreSQL tools instead.
+var errSnapshotNotSupported = errors.New("snapshots are only supported by the sqlite driver")
+
+// Snapshot writes a consistent copy of the log store database to destPath, which must not exist,
+// using the SQLite online backup API. Logs keep being stored while the snapshot is taken.
+func (s *LogStore) Snapshot(ctx context.Context, destPath string) error {
+	if _, ok := s.backend.(*sqliteBackend); !ok {
+		return errSnapshotNotSupported
+	}
+
+	if err := s.flushQueuedLogs(ctx); err != nil {
+		return err
+	}
+
+	unlock := s.maintenanceCoordinator.AcquireOperationLock()
+	defer unlock()
+
+	defer s.observeOperation("snapshot", time.Now())
+
+	if err := db.ExportSnapshot(ctx, s.db, s.dbConfig, destPath); err != nil {
+		return err
+	}
+
+	s.log.Infof("log store snapshot written to %s", destPath)
+
+	return nil
+}
+
+// Restore replaces the log store database with the snapshot at srcPath, taken by Snapshot.
+// The database is replaced in place, so the connections open on it stay usable and read the
+// restored logs, coverage and sync state.
+func (s *LogStore) Restore(ctx context.Context, srcPath string) error {
+	if _, ok := s.backend.(*sqliteBackend); !ok {
+		return errSnapshotNotSupported
+	}
+
+	if err := s.flushQueuedLogs(ctx); err != nil {
+		return err
+	}
+
+	unlock := s.maintenanceCoordinator.AcquireOperationLock()
+	defer unlock()
+
+	defer s.observeOperation("restore", time.Now())
+
+	if err := db.RestoreSnapshot(ctx, s.db, s.dbConfig, srcPath); err != nil {
+		return err
+	}
+
+	s.log.Infof("log store restored from snapshot %s", srcPath)
+
+	return nil
+}
+
 // Close closes the log store.
 func (s *LogStore) Close() error {
 	// The database connection is managed externally, so we don't close it here
@@ -500,6 +943,7 @@ func (s *LogStore) ethLogToDbLog(log *types.Log) *dbLog {
 		TxIndex:     log.TxIndex,
 		LogIndex:    log.Index,
 		Data:        log.Data,
+		Timestamp:   log.BlockTimestamp,
 	}
 
 	// Convert topics
@@ -527,13 +971,14 @@ func (s *LogStore) ethLogToDbLog(log *types.Log) *dbLog {
 // dbLogToEthLog converts a database log to an Ethereum log.
 func (s *LogStore) dbLogToEthLog(dbLog *dbLog) types.Log {
 	log := types.Log{
-		Address:     dbLog.Address,
-		BlockNumber: dbLog.BlockNumber,
-		BlockHash:   dbLog.BlockHash,
-		TxHash:      dbLog.TxHash,
-		TxIndex:     dbLog.TxIndex,
-		Index:       dbLog.LogIndex,
-		Data:        dbLog.Data,
+		Address:        dbLog.Address,
+		BlockNumber:    dbLog.BlockNumber,
+		BlockHash:      dbLog.BlockHash,
+		TxHash:         dbLog.TxHash,
+		TxIndex:        dbLog.TxIndex,
+		Index:          dbLog.LogIndex,
+		Data:           dbLog.Data,
+		BlockTimestamp: dbLog.Timestamp,
 	}
 
 	// Convert topics
@@ -561,6 +1006,32 @@ func (s *LogStore) applyRetentionIfNeeded(ctx context.Context) error {
 		return nil
 	}
 
+	pruneBeforeBlock, err := s.retentionThreshold(ctx)
+	if err != nil {
+		return err
+	}
+
+	if pruneBeforeBlock == 0 {
+		return nil
+	}
+
+	// Prune logs before the threshold
+	blocksPruned, err := s.pruneLogsBeforeBlock(ctx, pruneBeforeBlock)
+	if err != nil {
+		return err
+	}
+
+	if blocksPruned > 0 {
+		s.log.Infof("Applied retention policy: pruned %d blocks (before block %d)",
+			blocksPruned, pruneBeforeBlock)
+	}
+
+	return nil
+}
+
+// retentionThreshold returns the block before which the retention policy prunes logs,
+// or 0 if nothing needs to be pruned.
+func (s *LogStore) retentionThreshold(ctx context.Context) (uint64, error) {
 	var pruneBeforeBlock uint64
 
 	// Calculate prune threshold based on block age
@@ -572,7 +1043,7 @@ func (s *LogStore) applyRetentionIfNeeded(ctx context.Context) error {
 			"SELECT MIN(from_block), MAX(to_block) FROM log_coverage").
 			Scan(&oldestBlock, &newestBlock)
 		if err != nil && !errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("failed to get block range: %w", err)
+			return 0, fmt.Errorf("failed to get block range: %w", err)
 		}
 
 		if newestBlock > oldestBlock && newestBlock-oldestBlock > s.retentionPolicy.MaxBlocks {
@@ -582,9 +1053,9 @@ func (s *LogStore) applyRetentionIfNeeded(ctx context.Context) error {
 
 	// Check database size and adjust if needed
 	if s.retentionPolicy.MaxDBSizeMB > 0 {
-		dbSize, err := s.getDatabaseSizeMB()
+		dbSize, err := s.getDatabaseSizeMB(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to get database size: %w", err)
+			return 0, fmt.Errorf("failed to get database size: %w", err)
 		}
 
 		if dbSize >= s.retentionPolicy.MaxDBSizeMB {
@@ -594,7 +1065,7 @@ func (s *LogStore) applyRetentionIfNeeded(ctx context.Context) error {
 			// Calculate how many blocks to prune based on size
 			blockToPrune, err := s.calculateBlocksToFreeSpace(ctx, dbSize, s.retentionPolicy.MaxDBSizeMB)
 			if err != nil {
-				return fmt.Errorf("failed to calculate blocks to prune: %w", err)
+				return 0, fmt.Errorf("failed to calculate blocks to prune: %w", err)
 			}
 
 			// Use the more aggressive threshold
@@ -604,27 +1075,166 @@ func (s *LogStore) applyRetentionIfNeeded(ctx context.Context) error {
 		}
 	}
 
+	return pruneBeforeBlock, nil
+}
+
+// PreviewRetention reports what applying the retention policy would delete for the given
+// addresses, without modifying any data. If no addresses are given, the whole store is considered.
+// The freed space is estimated from the share of (weighted) rows that would be deleted.
+func (s *LogStore) PreviewRetention(
+	ctx context.Context,
+	addresses []ethcommon.Address,
+) (*store.RetentionPreview, error) {
+	preview := &store.RetentionPreview{}
+	if !s.retentionPolicy.IsEnabled() {
+		return preview, nil
+	}
+
+	pruneBeforeBlock, err := s.retentionThreshold(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	if pruneBeforeBlock == 0 {
-		return nil
+		return preview, nil
 	}
 
-	// Prune logs before the threshold
-	blocksPruned, err := s.pruneLogsBeforeBlock(ctx, pruneBeforeBlock)
+	preview.PruneBeforeBlock = pruneBeforeBlock
+
+	estimate, err := s.estimateRetention(ctx, pruneBeforeBlock, addresses)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if blocksPruned > 0 {
-		s.log.Infof("Applied retention policy: pruned %d blocks (before block %d)",
-			blocksPruned, pruneBeforeBlock)
+	preview.EstimatedRowsDeleted = uint64(estimate.logsDeleted + estimate.coverageDeleted)
+	preview.EstimatedSpaceFreedMB = uint64(estimate.mbFreed)
+
+	return preview, nil
+}
+
+// SimulateRetention reports what applying the given retention policy would delete from the whole
+// store, without modifying any data. The prune threshold is calculated exactly as when the policy
+// is applied, the freed space is estimated from the share of (weighted) rows that would be deleted.
+func (s *LogStore) SimulateRetention(
+	ctx context.Context,
+	policy config.RetentionPolicyConfig,
+) (*store.RetentionSimulation, error) {
+	// The threshold is calculated by a copy of the store with the simulated policy,
+	// which does not log the warnings of an actual pruning
+	simulated := *s
+	simulated.retentionPolicy = &policy
+	simulated.log = logger.NewNopLogger()
+
+	simulation := &store.RetentionSimulation{}
+	if policy.IsEnabled() {
+		pruneBeforeBlock, err := simulated.retentionThreshold(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if pruneBeforeBlock > 0 {
+			estimate, err := s.estimateRetention(ctx, pruneBeforeBlock, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			simulation.PruneBeforeBlock = pruneBeforeBlock
+			simulation.EstimatedLogsDeleted = estimate.logsDeleted
+			simulation.EstimatedMBFreed = estimate.mbFreed
+		}
 	}
 
-	return nil
+	// Pruning deletes the coverage ranges ending before the threshold and keeps the rest whole
+	err := s.db.QueryRowContext(ctx,
+		"SELECT COALESCE(MIN(from_block), 0), COALESCE(MAX(to_block), 0) FROM log_coverage WHERE to_block >= ?",
+		simulation.PruneBeforeBlock).
+		Scan(&simulation.BlockRangeRetained[0], &simulation.BlockRangeRetained[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retained block range: %w", err)
+	}
+
+	return simulation, nil
+}
+
+// retentionEstimate is what pruning the logs before a block would delete.
+type retentionEstimate struct {
+	logsDeleted     int64
+	coverageDeleted int64
+	mbFreed         float64
+}
+
+// estimateRetention counts the rows pruning the logs before pruneBeforeBlock would delete for the
+// given addresses, or for the whole store if no addresses are given, and estimates the space they take up.
+func (s *LogStore) estimateRetention(
+	ctx context.Context,
+	pruneBeforeBlock uint64,
+	addresses []ethcommon.Address,
+) (*retentionEstimate, error) {
+	addressFilter, args := "", []any{}
+	if len(addresses) > 0 {
+		addressFilter = " AND address IN (?" + strings.Repeat(", ?", len(addresses)-1) + ")"
+		for _, address := range addresses {
+			args = append(args, address.Hex())
+		}
+	}
+
+	count := func(query string, args ...any) (int64, error) {
+		var n int64
+		err := s.db.QueryRowContext(ctx, query, args...).Scan(&n)
+		return n, err
+	}
+
+	deletedArgs := append([]any{pruneBeforeBlock}, args...)
+
+	eventLogsDeleted, err := count("SELECT COUNT(*) FROM event_logs WHERE block_number < ?"+addressFilter,
+		deletedArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count event_logs to prune: %w", err)
+	}
+
+	coverageDeleted, err := count("SELECT COUNT(*) FROM log_coverage WHERE to_block < ?"+addressFilter,
+		deletedArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count log_coverage to prune: %w", err)
+	}
+
+	topicCoverageDeleted, err := count("SELECT COUNT(*) FROM topic_coverage WHERE to_block < ?"+addressFilter,
+		deletedArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count topic_coverage to prune: %w", err)
+	}
+
+	estimate := &retentionEstimate{
+		logsDeleted:     eventLogsDeleted,
+		coverageDeleted: coverageDeleted + topicCoverageDeleted,
+	}
+
+	var totalWeightedRows int64
+	err = s.db.QueryRowContext(ctx, `
+		SELECT (SELECT COUNT(*) FROM event_logs) * ? +
+		       (SELECT COUNT(*) FROM log_coverage) * ? +
+		       (SELECT COUNT(*) FROM topic_coverage) * ?`,
+		eventLogWeight, coverageWeight, coverageWeight).Scan(&totalWeightedRows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count rows: %w", err)
+	}
+
+	if totalWeightedRows > 0 {
+		dbSize, err := s.getDatabaseSizeMB(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get database size: %w", err)
+		}
+
+		deletedWeightedRows := eventLogsDeleted*eventLogWeight + estimate.coverageDeleted*coverageWeight
+		estimate.mbFreed = float64(dbSize) * float64(deletedWeightedRows) / float64(totalWeightedRows)
+	}
+
+	return estimate, nil
 }
 
 // getDatabaseSizeMB returns the current database size in megabytes
-func (s *LogStore) getDatabaseSizeMB() (uint64, error) {
-	sizeBytes, err := db.DBTotalSize(s.dbConfig.Path)
+func (s *LogStore) getDatabaseSizeMB(ctx context.Context) (uint64, error) {
+	sizeBytes, err := s.backend.DatabaseSize(ctx, s.db)
 	if err != nil {
 		return 0, err
 	}
@@ -673,12 +1283,6 @@ func (s *LogStore) calculateBlocksToFreeSpace(ctx context.Context, currentMB, ma
 	}
 
 	// Estimate average bytes per row (weighted by table)
-	// event_logs are typically larger (addresses, hashes, data)
-	// coverage tables are smaller (just addresses and block numbers)
-	// Use a rough weight: event_logs ~= 3x coverage row size
-	const eventLogWeight = 3
-	const coverageWeight = 1
-
 	totalWeightedRows := (eventLogCount * eventLogWeight) +
 		(logCoverageCount * coverageWeight) +
 		(topicCoverageCount * coverageWeight)
@@ -2,49 +2,77 @@ package store
 
 import (
 	"context"
+	"fmt"
+	"io/fs"
 	"math/big"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	internalcommon "github.com/goran-ethernal/ChainIndexor/internal/common"
 	"github.com/goran-ethernal/ChainIndexor/internal/db"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	"github.com/goran-ethernal/ChainIndexor/internal/migrations"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
 	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
 )
 
-func setupTestLogStore(t *testing.T) (*LogStore, func()) {
+func setupTestLogStore(t testing.TB) (*LogStore, func()) {
 	t.Helper()
 	return setupTestLogStoreWithRetention(t, nil, nil)
 }
 
-func setupTestLogStoreWithRetention(t *testing.T,
-	retentionPolicy *config.RetentionPolicyConfig,
-	maintenanceCoordinatorCfg *config.MaintenanceConfig) (*LogStore, func()) {
+// newTestDBConfig returns the configuration of an empty database for a single test.
+// The tests run on SQLite, and on PostgreSQL when built with the postgres tag.
+var newTestDBConfig = func(t testing.TB) config.DatabaseConfig {
 	t.Helper()
 
 	// Create temporary database
-	dbPath := path.Join(t.TempDir(), "logstore_test.db")
-
-	dbConfig := config.DatabaseConfig{Path: dbPath}
+	dbConfig := config.DatabaseConfig{Path: path.Join(t.TempDir(), "logstore_test.db")}
 	dbConfig.ApplyDefaults()
 
+	return dbConfig
+}
+
+// requireSQLite skips tests that measure or vacuum the SQLite database files.
+func requireSQLite(t *testing.T, store *LogStore) {
+	t.Helper()
+
+	if store.dbConfig.Driver != config.DBDriverSQLite {
+		t.Skipf("test requires the sqlite driver, running on %s", store.backend.Name())
+	}
+}
+
+func setupTestLogStoreWithRetention(t testing.TB,
+	retentionPolicy *config.RetentionPolicyConfig,
+	maintenanceCoordinatorCfg *config.MaintenanceConfig) (*LogStore, func()) {
+	t.Helper()
+
+	dbConfig := newTestDBConfig(t)
+
 	// Create database
-	sqlDB, err := db.NewSQLiteDBFromConfig(dbConfig)
+	sqlDB, err := db.NewDBFromConfig(dbConfig)
 	require.NoError(t, err)
 
 	// Run migrations
 	err = migrations.RunMigrations(dbConfig)
 	require.NoError(t, err)
 
-	maintenanceCoordinator := db.NewMaintenanceCoordinator(dbPath, sqlDB,
-		maintenanceCoordinatorCfg, logger.GetDefaultLogger())
-
 	// Create log store with proper dbConfig
-	store := NewLogStore(sqlDB, logger.GetDefaultLogger(), dbConfig, retentionPolicy, maintenanceCoordinator)
+	var store *LogStore
+	if dbConfig.Driver == config.DBDriverPostgres {
+		store = NewPostgresLogStore(sqlDB, logger.GetDefaultLogger(), dbConfig, retentionPolicy,
+			&db.NoOpMaintenance{}).LogStore
+	} else {
+		maintenanceCoordinator := db.NewMaintenanceCoordinator(0, dbConfig.Path, sqlDB,
+			maintenanceCoordinatorCfg, logger.GetDefaultLogger())
+		store = NewLogStore(sqlDB, logger.GetDefaultLogger(), dbConfig, retentionPolicy, maintenanceCoordinator)
+	}
 
 	cleanup := func() {
 		sqlDB.Close()
@@ -86,7 +114,7 @@ func TestLogStore_StoreLogs(t *testing.T) {
 	require.NoError(t, err)
 
 	// Retrieve logs
-	retrievedLogs, coverage, err := store.GetLogs(ctx, address, 100, 102)
+	retrievedLogs, coverage, err := store.GetLogs(ctx, address, 100, 102, nil)
 	require.NoError(t, err)
 	require.Len(t, retrievedLogs, 3)
 	require.Len(t, coverage, 1)
@@ -100,6 +128,99 @@ func TestLogStore_StoreLogs(t *testing.T) {
 	require.Equal(t, logs[0].Data, retrievedLogs[0].Data)
 }
 
+func TestLogStore_GetLogs_TopicFilters(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := setupTestLogStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	address := common.HexToAddress("0x1234567890123456789012345678901234567890")
+
+	transfer := common.HexToHash("0x1234")
+	approval := common.HexToHash("0x9999")
+	alice := common.HexToHash("0xa11ce")
+	bob := common.HexToHash("0xb0b")
+
+	withTopics := func(log types.Log, topics ...common.Hash) types.Log {
+		log.Topics = topics
+		return log
+	}
+
+	logs := []types.Log{
+		withTopics(createTestLog(address, 100, common.HexToHash("0xaaa"), 0), transfer, alice, bob),
+		withTopics(createTestLog(address, 101, common.HexToHash("0xbbb"), 0), transfer, bob, alice),
+		withTopics(createTestLog(address, 102, common.HexToHash("0xccc"), 0), approval, alice, bob),
+		withTopics(createTestLog(address, 103, common.HexToHash("0xddd"), 0), transfer),
+	}
+	topics := []common.Hash{transfer, approval}
+	require.NoError(t, store.StoreLogs(ctx, []common.Address{address}, [][]common.Hash{topics}, logs, 100, 103))
+
+	blocks := func(topics ...*common.Hash) []uint64 {
+		t.Helper()
+
+		retrievedLogs, coverage, err := store.GetLogs(ctx, address, 100, 103, topics)
+		require.NoError(t, err)
+		require.Len(t, coverage, 1)
+
+		numbers := make([]uint64, len(retrievedLogs))
+		for i, log := range retrievedLogs {
+			numbers[i] = log.BlockNumber
+		}
+
+		return numbers
+	}
+
+	// Single topic filters
+	require.Equal(t, []uint64{100, 101, 103}, blocks(&transfer))
+	require.Equal(t, []uint64{101}, blocks(nil, &bob))
+	require.Equal(t, []uint64{100, 102}, blocks(nil, nil, &bob))
+
+	// Multi-topic filters match logs with all of the topics
+	require.Equal(t, []uint64{100}, blocks(&transfer, &alice, &bob))
+	require.Equal(t, []uint64{102}, blocks(&approval, &alice))
+	require.Empty(t, blocks(&approval, &bob))
+
+	// Nil filters match any topic
+	require.Equal(t, []uint64{100, 101, 102, 103}, blocks(nil, nil, nil, nil))
+
+	_, _, err := store.GetLogs(ctx, address, 100, 103, []*common.Hash{nil, nil, nil, nil, &transfer})
+	require.Error(t, err)
+}
+
+func TestLogStore_StoreLogs_BlockTimestamp(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := setupTestLogStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	address := common.HexToAddress("0x1234567890123456789012345678901234567890")
+
+	logs := []types.Log{
+		createTestLog(address, 100, common.HexToHash("0xaaa"), 0),
+		createTestLog(address, 101, common.HexToHash("0xbbb"), 0),
+	}
+	logs[0].BlockTimestamp = 1700000000
+	logs[1].BlockTimestamp = 1700000012
+
+	topics := []common.Hash{common.HexToHash("0x1234")}
+	require.NoError(t, store.StoreLogs(ctx, []common.Address{address}, [][]common.Hash{topics}, logs, 100, 101))
+
+	retrievedLogs, _, err := store.GetLogs(ctx, address, 100, 101, nil)
+	require.NoError(t, err)
+	require.Len(t, retrievedLogs, 2)
+	require.Equal(t, uint64(1700000000), retrievedLogs[0].BlockTimestamp)
+	require.Equal(t, uint64(1700000012), retrievedLogs[1].BlockTimestamp)
+
+	// The stored timestamps can be filtered on
+	var count int
+	err = store.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM event_logs WHERE timestamp >= ? AND timestamp <= ?",
+		1700000010, 1700000020).Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
 func TestLogStore_GetLogs_PartialCoverage(t *testing.T) {
 	t.Parallel()
 
@@ -129,7 +250,7 @@ func TestLogStore_GetLogs_PartialCoverage(t *testing.T) {
 	require.NoError(t, err)
 
 	// Query range 100-107
-	retrievedLogs, coverage, err := store.GetLogs(ctx, address, 100, 107)
+	retrievedLogs, coverage, err := store.GetLogs(ctx, address, 100, 107, nil)
 	require.NoError(t, err)
 	require.Len(t, retrievedLogs, 6)
 	require.Len(t, coverage, 2)
@@ -168,7 +289,7 @@ func TestLogStore_HandleReorg(t *testing.T) {
 	require.NoError(t, err)
 
 	// Retrieve logs - should only get blocks 100-102 (103+ are removed)
-	retrievedLogs, coverage, err := store.GetLogs(ctx, address, 100, 105)
+	retrievedLogs, coverage, err := store.GetLogs(ctx, address, 100, 105, nil)
 	require.NoError(t, err)
 	require.Len(t, retrievedLogs, 3, "should only have logs for blocks 100-102")
 	require.Equal(t, uint64(100), retrievedLogs[0].BlockNumber)
@@ -181,6 +302,66 @@ func TestLogStore_HandleReorg(t *testing.T) {
 	require.Equal(t, uint64(102), coverage[0].ToBlock)
 }
 
+// operationCount returns the number of log store operations recorded in the database query duration metric.
+func operationCount(t *testing.T, store *LogStore, operation string) uint64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "chainindexor_db_query_duration_seconds" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+
+			if labels["db"] == store.dbConfig.Path && labels["operation"] == operation {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	return 0
+}
+
+// TestLogStore_OperationMetrics is not parallel, so no other test records operations while it runs.
+func TestLogStore_OperationMetrics(t *testing.T) {
+	store, cleanup := setupTestLogStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	address := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	topics := [][]common.Hash{{common.HexToHash("0x1234")}}
+
+	before := make(map[string]uint64)
+	for _, operation := range []string{"store_logs", "get_logs", "handle_reorg", "prune_logs"} {
+		before[operation] = operationCount(t, store, operation)
+	}
+
+	logs := []types.Log{
+		createTestLog(address, 100, common.HexToHash("0xaaa"), 0),
+		createTestLog(address, 101, common.HexToHash("0xbbb"), 0),
+	}
+	require.NoError(t, store.StoreLogs(ctx, []common.Address{address}, topics, logs, 100, 101))
+
+	_, _, err := store.GetLogs(ctx, address, 100, 101, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, store.HandleReorg(ctx, 101))
+
+	_, err = store.pruneLogsBeforeBlock(ctx, 101)
+	require.NoError(t, err)
+
+	for operation, count := range before {
+		require.Equal(t, count+1, operationCount(t, store, operation), operation)
+	}
+}
+
 func TestLogStore_MultipleAddresses(t *testing.T) {
 	t.Parallel()
 
@@ -209,18 +390,113 @@ func TestLogStore_MultipleAddresses(t *testing.T) {
 	require.NoError(t, err)
 
 	// Retrieve logs for address1
-	retrievedLogs1, _, err := store.GetLogs(ctx, address1, 100, 101)
+	retrievedLogs1, _, err := store.GetLogs(ctx, address1, 100, 101, nil)
 	require.NoError(t, err)
 	require.Len(t, retrievedLogs1, 2)
 	require.Equal(t, address1, retrievedLogs1[0].Address)
 
 	// Retrieve logs for address2
-	retrievedLogs2, _, err := store.GetLogs(ctx, address2, 100, 101)
+	retrievedLogs2, _, err := store.GetLogs(ctx, address2, 100, 101, nil)
 	require.NoError(t, err)
 	require.Len(t, retrievedLogs2, 2)
 	require.Equal(t, address2, retrievedLogs2[0].Address)
 }
 
+func TestLogStore_GetLogsBatch(t *testing.T) {
+	t.Parallel()
+
+	logStore, cleanup := setupTestLogStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	address1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	address2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	address3 := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	topics := []common.Hash{common.HexToHash("0x1234")}
+
+	err := logStore.StoreLogs(ctx, []common.Address{address1}, [][]common.Hash{topics}, []types.Log{
+		createTestLog(address1, 100, common.HexToHash("0xaaa"), 0),
+		createTestLog(address1, 105, common.HexToHash("0xbbb"), 1),
+	}, 100, 110)
+	require.NoError(t, err)
+
+	err = logStore.StoreLogs(ctx, []common.Address{address2}, [][]common.Hash{topics}, []types.Log{
+		createTestLog(address2, 103, common.HexToHash("0xccc"), 0),
+		createTestLog(address2, 120, common.HexToHash("0xddd"), 0),
+	}, 100, 120)
+	require.NoError(t, err)
+
+	logs, coverage, err := logStore.GetLogsBatch(ctx, []common.Address{address1, address2, address3}, 100, 110, nil)
+	require.NoError(t, err)
+
+	// Every address has an entry, with the same logs and coverage GetLogs returns for it
+	require.Len(t, logs, 3)
+	require.Len(t, coverage, 3)
+	require.Empty(t, logs[address3])
+	require.Empty(t, coverage[address3])
+
+	for _, address := range []common.Address{address1, address2} {
+		expectedLogs, expectedCoverage, err := logStore.GetLogs(ctx, address, 100, 110, nil)
+		require.NoError(t, err)
+		require.Equal(t, expectedLogs, logs[address])
+		require.Equal(t, expectedCoverage, coverage[address])
+	}
+	require.Len(t, logs[address1], 2)
+	require.Len(t, logs[address2], 1)
+
+	// Topic filters apply to the logs of every address
+	noTopic := common.Hash{}
+	logs, coverage, err = logStore.GetLogsBatch(ctx, []common.Address{address1, address2}, 100, 110, []*common.Hash{&noTopic})
+	require.NoError(t, err)
+	require.Empty(t, logs[address1])
+	require.Empty(t, logs[address2])
+	require.Equal(t, []store.CoverageRange{{FromBlock: 100, ToBlock: 110}}, coverage[address1])
+	require.Equal(t, []store.CoverageRange{{FromBlock: 100, ToBlock: 120}}, coverage[address2])
+
+	// No addresses need no queries
+	logs, coverage, err = logStore.GetLogsBatch(ctx, nil, 100, 110, nil)
+	require.NoError(t, err)
+	require.Empty(t, logs)
+	require.Empty(t, coverage)
+}
+
+func TestLogStore_StoreLogs_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := setupTestLogStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	topics := []common.Hash{common.HexToHash("0x1234")}
+
+	const writers = 4
+
+	addresses := make([]common.Address, writers)
+	for i := range addresses {
+		addresses[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+	}
+
+	// Fetcher pool workers store the logs of their addresses concurrently
+	g, errCtx := errgroup.WithContext(ctx)
+	for i, address := range addresses {
+		g.Go(func() error {
+			logs := []types.Log{
+				createTestLog(address, 100, common.BigToHash(big.NewInt(int64(i*10+1))), 0),
+				createTestLog(address, 101, common.BigToHash(big.NewInt(int64(i*10+2))), 0),
+			}
+			return store.StoreLogs(errCtx, []common.Address{address}, [][]common.Hash{topics}, logs, 100, 101)
+		})
+	}
+	require.NoError(t, g.Wait())
+
+	for _, address := range addresses {
+		retrievedLogs, coverage, err := store.GetLogs(ctx, address, 100, 101, nil)
+		require.NoError(t, err)
+		require.Len(t, retrievedLogs, 2)
+		require.Len(t, coverage, 1)
+	}
+}
+
 func TestLogStore_GetUnsyncedTopics(t *testing.T) {
 	t.Parallel()
 
@@ -358,7 +634,7 @@ func TestLogStore_HandleReorg_TruncatesSpanningRanges(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify we have two coverage ranges
-	_, coverage, err := store.GetLogs(ctx, address, 0, 200)
+	_, coverage, err := store.GetLogs(ctx, address, 0, 200, nil)
 	require.NoError(t, err)
 	require.Len(t, coverage, 2)
 	require.Equal(t, uint64(0), coverage[0].FromBlock)
@@ -373,7 +649,7 @@ func TestLogStore_HandleReorg_TruncatesSpanningRanges(t *testing.T) {
 	// After reorg, coverage should be:
 	// - 0-100 (unchanged)
 	// - 101-149 (truncated from 101-200)
-	_, coverage, err = store.GetLogs(ctx, address, 0, 200)
+	_, coverage, err = store.GetLogs(ctx, address, 0, 200, nil)
 	require.NoError(t, err)
 	require.Len(t, coverage, 2, "should have two coverage ranges")
 	require.Equal(t, uint64(0), coverage[0].FromBlock)
@@ -397,7 +673,7 @@ func TestLogStore_HandleReorg_TruncatesSpanningRanges(t *testing.T) {
 	require.NoError(t, err)
 
 	// Now we should have three coverage ranges: 0-100, 101-149, 150-200
-	_, coverage, err = store.GetLogs(ctx, address, 0, 200)
+	_, coverage, err = store.GetLogs(ctx, address, 0, 200, nil)
 	require.NoError(t, err)
 	require.Len(t, coverage, 3, "should have three coverage ranges after re-fetch")
 	require.Equal(t, uint64(0), coverage[0].FromBlock)
@@ -541,6 +817,105 @@ func TestGetMissingRanges(t *testing.T) {
 	}
 }
 
+func TestUnsyncedTopics_MissingRanges(t *testing.T) {
+	t.Parallel()
+
+	address1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	address2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	topic1 := common.HexToHash("0x01")
+	topic2 := common.HexToHash("0x02")
+	topic3 := common.HexToHash("0x03")
+
+	unsynced := store.NewUnsyncedTopics()
+
+	// Several holes, including overlapping ranges and a hole at the end
+	unsynced.AddTopicRanges(address1, topic1, 100, []store.CoverageRange{
+		{FromBlock: 100, ToBlock: 149},
+		{FromBlock: 140, ToBlock: 199},
+		{FromBlock: 250, ToBlock: 299},
+		{FromBlock: 301, ToBlock: 400},
+		{FromBlock: 450, ToBlock: 460},
+	})
+	// Holes before the first and between adjacent-but-not-touching ranges
+	unsynced.AddTopicRanges(address1, topic2, 100, []store.CoverageRange{
+		{FromBlock: 120, ToBlock: 300},
+		{FromBlock: 302, ToBlock: 500},
+	})
+	// No coverage at all
+	unsynced.AddTopicRanges(address2, topic3, 50, nil)
+	// Coverage before upToBlock is complete, the topic is only missing later blocks
+	unsynced.AddTopic(address2, topic1, store.CoverageRange{FromBlock: 0, ToBlock: 600})
+	// Coverage has to start after upToBlock
+	unsynced.AddTopicRanges(address2, topic2, 700, nil)
+
+	require.Equal(t, map[common.Address]map[common.Hash][]store.CoverageRange{
+		address1: {
+			topic1: {
+				{FromBlock: 200, ToBlock: 249},
+				{FromBlock: 300, ToBlock: 300},
+				{FromBlock: 401, ToBlock: 449},
+				{FromBlock: 461, ToBlock: 500},
+			},
+			topic2: {
+				{FromBlock: 100, ToBlock: 119},
+				{FromBlock: 301, ToBlock: 301},
+			},
+		},
+		address2: {
+			topic3: {{FromBlock: 50, ToBlock: 500}},
+		},
+	}, unsynced.MissingRanges(500))
+
+	// The holes of a lower block only reach up to it
+	require.Equal(t, map[common.Address]map[common.Hash][]store.CoverageRange{
+		address1: {
+			topic1: {{FromBlock: 200, ToBlock: 220}},
+			topic2: {{FromBlock: 100, ToBlock: 119}},
+		},
+		address2: {
+			topic3: {{FromBlock: 50, ToBlock: 220}},
+		},
+	}, unsynced.MissingRanges(220))
+
+	require.Empty(t, store.NewUnsyncedTopics().MissingRanges(500))
+}
+
+func TestLogStore_GetUnsyncedTopics_MissingRanges(t *testing.T) {
+	t.Parallel()
+
+	logStore, cleanup := setupTestLogStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	address := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	topic1 := common.HexToHash("0x01")
+	topic2 := common.HexToHash("0x02")
+
+	// Topic 1 is stored with holes at 150-199 and 300-349, topic 2 only from block 200
+	for _, r := range []store.CoverageRange{
+		{FromBlock: 100, ToBlock: 149},
+		{FromBlock: 200, ToBlock: 299},
+		{FromBlock: 350, ToBlock: 400},
+	} {
+		err := logStore.StoreLogs(ctx, []common.Address{address}, [][]common.Hash{{topic1}}, nil, r.FromBlock, r.ToBlock)
+		require.NoError(t, err)
+	}
+	err := logStore.StoreLogs(ctx, []common.Address{address}, [][]common.Hash{{topic2}}, nil, 200, 450)
+	require.NoError(t, err)
+
+	unsynced, err := logStore.GetUnsyncedTopics(ctx,
+		[]common.Address{address}, [][]common.Hash{{topic1, topic2}}, 450)
+	require.NoError(t, err)
+
+	// The coverage of the address starts at block 100, so topic 2 is missing blocks 100-199
+	require.Equal(t, map[common.Address]map[common.Hash][]store.CoverageRange{
+		address: {
+			topic1: {{FromBlock: 150, ToBlock: 199}, {FromBlock: 300, ToBlock: 349}, {FromBlock: 401, ToBlock: 450}},
+			topic2: {{FromBlock: 100, ToBlock: 199}},
+		},
+	}, unsynced.MissingRanges(450))
+}
+
 func TestLogStore_Close(t *testing.T) {
 	store, cleanup := setupTestLogStore(t)
 	defer cleanup()
@@ -550,6 +925,74 @@ func TestLogStore_Close(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestLogStore_SnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	logStore, cleanup := setupTestLogStore(t)
+	defer cleanup()
+	requireSQLite(t, logStore)
+
+	ctx := context.Background()
+	address := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	topics := [][]common.Hash{{common.HexToHash("0x1234")}}
+
+	logs := []types.Log{
+		createTestLog(address, 100, common.HexToHash("0xaaa"), 0),
+		createTestLog(address, 101, common.HexToHash("0xbbb"), 0),
+		createTestLog(address, 102, common.HexToHash("0xccc"), 1),
+	}
+	require.NoError(t, logStore.StoreLogs(ctx, []common.Address{address}, topics, logs, 100, 102))
+
+	snapshotPath := path.Join(t.TempDir(), "snapshot.db")
+	require.NoError(t, logStore.Snapshot(ctx, snapshotPath))
+	require.NoFileExists(t, snapshotPath+".tmp")
+
+	// A snapshot never overwrites a file
+	require.ErrorIs(t, logStore.Snapshot(ctx, snapshotPath), fs.ErrExist)
+
+	// Logs stored after the snapshot are not restored
+	require.NoError(t, logStore.StoreLogs(ctx, []common.Address{address}, topics,
+		[]types.Log{createTestLog(address, 103, common.HexToHash("0xddd"), 0)}, 103, 103))
+
+	for _, table := range []string{"event_logs", "log_coverage", "topic_coverage"} {
+		_, err := logStore.db.ExecContext(ctx, "DELETE FROM "+table)
+		require.NoError(t, err)
+	}
+
+	restored, coverage, err := logStore.GetLogs(ctx, address, 100, 103, nil)
+	require.NoError(t, err)
+	require.Empty(t, restored)
+	require.Empty(t, coverage)
+
+	require.NoError(t, logStore.Restore(ctx, snapshotPath))
+
+	restored, coverage, err = logStore.GetLogs(ctx, address, 100, 103, nil)
+	require.NoError(t, err)
+	require.Equal(t, []store.CoverageRange{{FromBlock: 100, ToBlock: 102}}, coverage)
+	require.Len(t, restored, len(logs))
+	for i, log := range logs {
+		require.Equal(t, log.BlockNumber, restored[i].BlockNumber)
+		require.Equal(t, log.TxHash, restored[i].TxHash)
+		require.Equal(t, log.Index, restored[i].Index)
+		require.Equal(t, log.Topics, restored[i].Topics)
+		require.Equal(t, log.Data, restored[i].Data)
+	}
+
+	unsynced, err := logStore.GetUnsyncedTopics(ctx, []common.Address{address}, topics, 102)
+	require.NoError(t, err)
+	require.True(t, unsynced.IsEmpty())
+
+	var result string
+	require.NoError(t, logStore.db.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result))
+	require.Equal(t, "ok", result)
+
+	// The restored store keeps storing logs
+	require.NoError(t, logStore.StoreLogs(ctx, []common.Address{address}, topics,
+		[]types.Log{createTestLog(address, 103, common.HexToHash("0xddd"), 0)}, 103, 103))
+
+	require.ErrorIs(t, logStore.Restore(ctx, path.Join(t.TempDir(), "missing.db")), fs.ErrNotExist)
+}
+
 func TestLogStore_TopicConversion(t *testing.T) {
 	store, cleanup := setupTestLogStore(t)
 	defer cleanup()
@@ -619,7 +1062,7 @@ func TestLogStore_TopicConversion(t *testing.T) {
 			require.NoError(t, err)
 
 			// Retrieve and verify topics are preserved correctly
-			retrievedLogs, _, err := store.GetLogs(ctx, address, log.BlockNumber, log.BlockNumber)
+			retrievedLogs, _, err := store.GetLogs(ctx, address, log.BlockNumber, log.BlockNumber, nil)
 			require.NoError(t, err)
 			require.Len(t, retrievedLogs, 1)
 			require.Equal(t, tt.topics, retrievedLogs[0].Topics, "topics should be preserved")
@@ -642,7 +1085,7 @@ func TestLogStore_StoreLogs_EmptyLogs(t *testing.T) {
 	require.NoError(t, err)
 
 	// Coverage should still be recorded
-	_, coverage, err := store.GetLogs(ctx, address, 100, 105)
+	_, coverage, err := store.GetLogs(ctx, address, 100, 105, nil)
 	require.NoError(t, err)
 	require.Len(t, coverage, 1)
 	require.Equal(t, uint64(100), coverage[0].FromBlock)
@@ -673,7 +1116,7 @@ func TestLogStore_StoreLogs_DuplicateLogs(t *testing.T) {
 	require.NoError(t, err)
 
 	// Should still only have 2 logs
-	retrievedLogs, _, err := store.GetLogs(ctx, address, 100, 101)
+	retrievedLogs, _, err := store.GetLogs(ctx, address, 100, 101, nil)
 	require.NoError(t, err)
 	require.Len(t, retrievedLogs, 2)
 }
@@ -719,7 +1162,7 @@ func TestLogStore_GetLogs_NoCoverage(t *testing.T) {
 	address := common.HexToAddress("0x1234567890123456789012345678901234567890")
 
 	// Query without storing anything
-	logs, coverage, err := store.GetLogs(ctx, address, 100, 110)
+	logs, coverage, err := store.GetLogs(ctx, address, 100, 110, nil)
 	require.NoError(t, err)
 	require.Len(t, logs, 0)
 	require.Len(t, coverage, 0)
@@ -730,6 +1173,7 @@ func TestLogStore_CalculateBlocksToFreeSpace(t *testing.T) {
 
 	store, cleanup := setupTestLogStore(t)
 	defer cleanup()
+	requireSQLite(t, store)
 
 	ctx := context.Background()
 	address1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
@@ -1049,6 +1493,7 @@ func TestLogStore_RetentionPolicy(t *testing.T) {
 
 		store, cleanup := setupTestLogStoreWithRetention(t, retentionPolicy, dbMaintenanceCfg)
 		defer cleanup()
+		requireSQLite(t, store)
 
 		ctx := context.Background()
 		address := common.HexToAddress("0x1111111111111111111111111111111111111111")
@@ -1088,7 +1533,7 @@ func TestLogStore_RetentionPolicy(t *testing.T) {
 		}
 
 		// Get initial size
-		sizeBefore, err := store.getDatabaseSizeMB()
+		sizeBefore, err := store.getDatabaseSizeMB(ctx)
 		require.NoError(t, err)
 		t.Logf("Initial database size: %d MB", sizeBefore)
 
@@ -1102,7 +1547,7 @@ func TestLogStore_RetentionPolicy(t *testing.T) {
 		require.NoError(t, store.maintenanceCoordinator.RunMaintenance(ctx))
 
 		// Get size after pruning
-		sizeAfter, err := store.getDatabaseSizeMB()
+		sizeAfter, err := store.getDatabaseSizeMB(ctx)
 		require.NoError(t, err)
 		t.Logf("After retention - database size: %d MB (before: %d MB)", sizeAfter, sizeBefore)
 
@@ -1130,6 +1575,7 @@ func TestLogStore_RetentionPolicy(t *testing.T) {
 		dbMaintenanceCfg.ApplyDefaults()
 		store, cleanup := setupTestLogStoreWithRetention(t, retentionPolicy, dbMaintenanceCfg)
 		defer cleanup()
+		requireSQLite(t, store)
 
 		ctx := context.Background()
 		address := common.HexToAddress("0x1111111111111111111111111111111111111111")
@@ -1163,7 +1609,7 @@ func TestLogStore_RetentionPolicy(t *testing.T) {
 			require.NoError(t, err)
 		}
 
-		sizeBefore, err := store.getDatabaseSizeMB()
+		sizeBefore, err := store.getDatabaseSizeMB(ctx)
 		require.NoError(t, err)
 		t.Logf("Initial database size: %d MB", sizeBefore)
 
@@ -1176,7 +1622,7 @@ func TestLogStore_RetentionPolicy(t *testing.T) {
 		require.NoError(t, err)
 
 		require.NoError(t, store.maintenanceCoordinator.RunMaintenance(ctx))
-		sizeAfter, err := store.getDatabaseSizeMB()
+		sizeAfter, err := store.getDatabaseSizeMB(ctx)
 		require.NoError(t, err)
 
 		t.Logf("After retention - size: %d MB, logs: %d, min block: %d",
@@ -1191,3 +1637,426 @@ func TestLogStore_RetentionPolicy(t *testing.T) {
 		require.Greater(t, minBlock, int64(1000), "should have pruned old blocks")
 	})
 }
+
+func TestLogStore_PreviewRetention(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := setupTestLogStoreWithRetention(t, &config.RetentionPolicyConfig{MaxBlocks: 100}, nil)
+	defer cleanup()
+
+	ctx := context.Background()
+	address1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	address2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	topic1 := common.HexToHash("0xaaaa")
+	topic2 := common.HexToHash("0xbbbb")
+
+	// Store blocks 1000-1499 in chunks of 100 blocks, 2 logs per address per block
+	for from := uint64(1000); from < 1500; from += 100 {
+		var logs []types.Log
+		for block := from; block < from+100; block++ {
+			logs = append(logs,
+				createTestLog(address1, block, common.BytesToHash([]byte{byte(block), 0x01}), 0),
+				createTestLog(address1, block, common.BytesToHash([]byte{byte(block), 0x02}), 1),
+				createTestLog(address2, block, common.BytesToHash([]byte{byte(block), 0x03}), 2),
+				createTestLog(address2, block, common.BytesToHash([]byte{byte(block), 0x04}), 3),
+			)
+		}
+
+		err := store.storeLogsInternal(ctx,
+			[]common.Address{address1, address2}, [][]common.Hash{{topic1}, {topic2}}, logs, from, from+99)
+		require.NoError(t, err)
+	}
+
+	// Keeping 100 blocks prunes everything before block 1399: 399 blocks of logs
+	// and the 3 coverage chunks ending before it, per address
+	preview, err := store.PreviewRetention(ctx, []common.Address{address1})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1399), preview.PruneBeforeBlock)
+	require.Equal(t, uint64(399*2+3+3), preview.EstimatedRowsDeleted)
+
+	preview, err = store.PreviewRetention(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1399), preview.PruneBeforeBlock)
+	require.Equal(t, uint64(399*4+6+6), preview.EstimatedRowsDeleted)
+
+	// Nothing was deleted
+	var totalLogs int64
+	require.NoError(t, store.db.QueryRow("SELECT COUNT(*) FROM event_logs").Scan(&totalLogs))
+	require.Equal(t, int64(2000), totalLogs)
+
+	// Nothing is pruned without a policy
+	store.retentionPolicy = nil
+	preview, err = store.PreviewRetention(ctx, nil)
+	require.NoError(t, err)
+	require.Zero(t, *preview)
+}
+
+func TestLogStore_SimulateRetention(t *testing.T) {
+	t.Parallel()
+
+	logStore, cleanup := setupTestLogStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	address := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	topic := common.HexToHash("0xaaaa")
+
+	// Store blocks 1000-1499 in chunks of 100 blocks, 2 logs per block
+	for from := uint64(1000); from < 1500; from += 100 {
+		var logs []types.Log
+		for block := from; block < from+100; block++ {
+			logs = append(logs,
+				createTestLog(address, block, common.BytesToHash([]byte{byte(block), 0x01}), 0),
+				createTestLog(address, block, common.BytesToHash([]byte{byte(block), 0x02}), 1),
+			)
+		}
+
+		err := logStore.storeLogsInternal(ctx, []common.Address{address}, [][]common.Hash{{topic}}, logs, from, from+99)
+		require.NoError(t, err)
+	}
+
+	countLogs := func() int64 {
+		var n int64
+		require.NoError(t, logStore.db.QueryRow("SELECT COUNT(*) FROM event_logs").Scan(&n))
+		return n
+	}
+
+	// Nothing is pruned by a disabled policy
+	simulation, err := logStore.SimulateRetention(ctx, config.RetentionPolicyConfig{})
+	require.NoError(t, err)
+	require.Equal(t, store.RetentionSimulation{BlockRangeRetained: [2]uint64{1000, 1499}}, *simulation)
+
+	policy := config.RetentionPolicyConfig{MaxBlocks: 100}
+	simulation, err = logStore.SimulateRetention(ctx, policy)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1399), simulation.PruneBeforeBlock)
+	require.Equal(t, int64(399*2), simulation.EstimatedLogsDeleted)
+	require.Equal(t, [2]uint64{1300, 1499}, simulation.BlockRangeRetained)
+
+	// The simulation did not delete anything, applying the policy deletes what it predicted
+	logsBefore := countLogs()
+	require.Equal(t, int64(1000), logsBefore)
+
+	logStore.retentionPolicy = &policy
+	require.NoError(t, logStore.applyRetentionIfNeeded(ctx))
+	require.Equal(t, simulation.EstimatedLogsDeleted, logsBefore-countLogs())
+
+	var retained [2]uint64
+	require.NoError(t, logStore.db.QueryRow("SELECT MIN(from_block), MAX(to_block) FROM log_coverage").
+		Scan(&retained[0], &retained[1]))
+	require.Equal(t, simulation.BlockRangeRetained, retained)
+}
+
+func TestLogStore_CompactCoverage(t *testing.T) {
+	t.Parallel()
+
+	logStore, cleanup := setupTestLogStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	address1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	address2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	topic1 := common.HexToHash("0x1234")
+	topic2 := common.HexToHash("0x5678")
+
+	// Adjacent and overlapping ranges for address1, a gap before 301-400
+	for _, r := range [][2]uint64{{0, 100}, {101, 200}, {150, 250}, {301, 400}} {
+		err := logStore.StoreLogs(ctx, []common.Address{address1}, [][]common.Hash{{topic1, topic2}}, nil, r[0], r[1])
+		require.NoError(t, err)
+	}
+
+	// Ranges of different addresses are never merged
+	err := logStore.StoreLogs(ctx, []common.Address{address2}, [][]common.Hash{{topic1}}, nil, 251, 300)
+	require.NoError(t, err)
+
+	require.NoError(t, logStore.CompactCoverage(ctx))
+
+	_, coverage, err := logStore.GetLogs(ctx, address1, 0, 1000, nil)
+	require.NoError(t, err)
+	require.Equal(t, []store.CoverageRange{{FromBlock: 0, ToBlock: 250}, {FromBlock: 301, ToBlock: 400}}, coverage)
+
+	_, coverage, err = logStore.GetLogs(ctx, address2, 0, 1000, nil)
+	require.NoError(t, err)
+	require.Equal(t, []store.CoverageRange{{FromBlock: 251, ToBlock: 300}}, coverage)
+
+	var topicRanges int
+	require.NoError(t, logStore.db.QueryRow("SELECT COUNT(*) FROM topic_coverage").Scan(&topicRanges))
+	require.Equal(t, 5, topicRanges, "two ranges per topic of address1 and one of address2")
+
+	unsynced, err := logStore.GetUnsyncedTopics(ctx, []common.Address{address1}, [][]common.Hash{{topic1, topic2}}, 250)
+	require.NoError(t, err)
+	require.True(t, unsynced.IsEmpty())
+
+	// Compacting again is a no-op
+	require.NoError(t, logStore.CompactCoverage(ctx))
+	require.NoError(t, logStore.db.QueryRow("SELECT COUNT(*) FROM topic_coverage").Scan(&topicRanges))
+	require.Equal(t, 5, topicRanges)
+}
+
+func TestLogStore_CompactCoverageFragmentedRanges(t *testing.T) {
+	t.Parallel()
+
+	logStore, cleanup := setupTestLogStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	address := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	topic := common.HexToHash("0x9abc")
+
+	// Incremental syncs store one small range per chunk
+	for from := uint64(100); from < 200; from += 3 {
+		err := logStore.StoreLogs(ctx, []common.Address{address}, [][]common.Hash{{topic}}, nil, from, from+2)
+		require.NoError(t, err)
+	}
+
+	countRanges := func() (int, int) {
+		var logRanges, topicRanges int
+		require.NoError(t, logStore.db.QueryRow("SELECT COUNT(*) FROM log_coverage").Scan(&logRanges))
+		require.NoError(t, logStore.db.QueryRow("SELECT COUNT(*) FROM topic_coverage").Scan(&topicRanges))
+		return logRanges, topicRanges
+	}
+
+	logRanges, topicRanges := countRanges()
+	require.Equal(t, 34, logRanges)
+	require.Equal(t, 34, topicRanges)
+
+	for range 2 {
+		require.NoError(t, logStore.CompactCoverage(ctx))
+
+		logRanges, topicRanges = countRanges()
+		require.Equal(t, 1, logRanges)
+		require.Equal(t, 1, topicRanges)
+
+		_, coverage, err := logStore.GetLogs(ctx, address, 0, 1000, nil)
+		require.NoError(t, err)
+		require.Equal(t, []store.CoverageRange{{FromBlock: 100, ToBlock: 201}}, coverage)
+	}
+}
+
+// benchmarkAddresses stores logs of the given number of addresses, and returns the addresses.
+func benchmarkAddresses(b *testing.B, logStore *LogStore, count int) []common.Address {
+	b.Helper()
+
+	const (
+		blocks       = 10
+		logsPerBlock = 2
+	)
+
+	ctx := context.Background()
+	topics := []common.Hash{common.HexToHash("0x1234")}
+
+	addresses := make([]common.Address, count)
+	for i := range addresses {
+		addresses[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+
+		logs := make([]types.Log, 0, blocks*logsPerBlock)
+		for block := range uint64(blocks) {
+			for index := range uint(logsPerBlock) {
+				txHash := common.BigToHash(big.NewInt(int64(i*blocks*logsPerBlock) + int64(block*logsPerBlock) + int64(index)))
+				logs = append(logs, createTestLog(addresses[i], block, txHash, index))
+			}
+		}
+
+		err := logStore.StoreLogs(ctx, []common.Address{addresses[i]}, [][]common.Hash{topics}, logs, 0, blocks-1)
+		require.NoError(b, err)
+	}
+
+	return addresses
+}
+
+// BenchmarkLogStore_GetLogsBatch compares reading the logs of many addresses with a GetLogs call
+// per address and with a single GetLogsBatch call. GetLogsBatch saves the per-query overhead, so the
+// speedup shrinks as the number of logs read per address grows and decoding the rows dominates.
+func BenchmarkLogStore_GetLogsBatch(b *testing.B) {
+	for _, count := range []int{10, 100} {
+		logStore, cleanup := setupTestLogStore(b)
+		addresses := benchmarkAddresses(b, logStore, count)
+		ctx := context.Background()
+
+		b.Run(fmt.Sprintf("GetLogs/%d_addresses", count), func(b *testing.B) {
+			for b.Loop() {
+				for _, address := range addresses {
+					_, _, err := logStore.GetLogs(ctx, address, 0, 9, nil)
+					require.NoError(b, err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("GetLogsBatch/%d_addresses", count), func(b *testing.B) {
+			for b.Loop() {
+				_, _, err := logStore.GetLogsBatch(ctx, addresses, 0, 9, nil)
+				require.NoError(b, err)
+			}
+		})
+
+		cleanup()
+	}
+}
+
+// setBatcher makes the log store queue its logs in a batcher that is only flushed when full or explicitly.
+func setBatcher(t testing.TB, logStore *LogStore, batchSize int) *LogBatcher {
+	t.Helper()
+
+	batcher := NewLogBatcher(logStore.db, config.WriteBatchConfig{
+		BatchSize:     batchSize,
+		FlushInterval: internalcommon.NewDuration(time.Hour),
+	}, logStore.maintenanceCoordinator, logger.NewNopLogger())
+	logStore.SetLogBatcher(batcher)
+
+	return batcher
+}
+
+func countStoredLogs(t *testing.T, logStore *LogStore) int {
+	t.Helper()
+
+	var count int
+	require.NoError(t, logStore.db.QueryRow(`SELECT COUNT(*) FROM event_logs`).Scan(&count))
+
+	return count
+}
+
+func TestLogStore_WriteBatch(t *testing.T) {
+	t.Parallel()
+
+	logStore, cleanup := setupTestLogStore(t)
+	defer cleanup()
+	setBatcher(t, logStore, 1000)
+
+	ctx := context.Background()
+	address := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	topics := []common.Hash{common.HexToHash("0x1234")}
+
+	logs := []types.Log{
+		createTestLog(address, 100, common.HexToHash("0xaaa"), 0),
+		createTestLog(address, 105, common.HexToHash("0xbbb"), 0),
+	}
+	err := logStore.StoreLogs(ctx, []common.Address{address}, [][]common.Hash{topics}, logs, 100, 109)
+	require.NoError(t, err)
+
+	// The logs are queued, but their coverage already counts as synced
+	require.Zero(t, countStoredLogs(t, logStore))
+
+	unsynced, err := logStore.GetUnsyncedTopics(ctx, []common.Address{address}, [][]common.Hash{topics}, 109)
+	require.NoError(t, err)
+	require.True(t, unsynced.IsEmpty())
+
+	unsynced, err = logStore.GetUnsyncedTopics(ctx, []common.Address{address}, [][]common.Hash{topics}, 110)
+	require.NoError(t, err)
+	require.True(t, unsynced.ContainsTopic(address, topics[0]))
+
+	// Reading the logs flushes them
+	retrievedLogs, coverage, err := logStore.GetLogs(ctx, address, 100, 109, nil)
+	require.NoError(t, err)
+	require.Len(t, retrievedLogs, 2)
+	require.Equal(t, []store.CoverageRange{{FromBlock: 100, ToBlock: 109}}, coverage)
+	require.Equal(t, 2, countStoredLogs(t, logStore))
+
+	unsynced, err = logStore.GetUnsyncedTopics(ctx, []common.Address{address}, [][]common.Hash{topics}, 109)
+	require.NoError(t, err)
+	require.True(t, unsynced.IsEmpty())
+}
+
+func TestLogStore_WriteBatch_HandleReorg(t *testing.T) {
+	t.Parallel()
+
+	logStore, cleanup := setupTestLogStore(t)
+	defer cleanup()
+	setBatcher(t, logStore, 1000)
+
+	ctx := context.Background()
+	address := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	topics := []common.Hash{common.HexToHash("0x1234")}
+
+	logs := []types.Log{
+		createTestLog(address, 100, common.HexToHash("0xaaa"), 0),
+		createTestLog(address, 103, common.HexToHash("0xbbb"), 0),
+		createTestLog(address, 105, common.HexToHash("0xccc"), 0),
+	}
+	err := logStore.StoreLogs(ctx, []common.Address{address}, [][]common.Hash{topics}, logs, 100, 105)
+	require.NoError(t, err)
+
+	// The queued logs are flushed before the reorged ones are removed
+	require.NoError(t, logStore.HandleReorg(ctx, 103))
+
+	retrievedLogs, coverage, err := logStore.GetLogs(ctx, address, 100, 105, nil)
+	require.NoError(t, err)
+	require.Len(t, retrievedLogs, 1)
+	require.Equal(t, uint64(100), retrievedLogs[0].BlockNumber)
+	require.Equal(t, []store.CoverageRange{{FromBlock: 100, ToBlock: 102}}, coverage)
+}
+
+func TestLogStore_WriteBatch_FlushesFullBatch(t *testing.T) {
+	t.Parallel()
+
+	logStore, cleanup := setupTestLogStore(t)
+	defer cleanup()
+
+	// Two logs and the log and topic coverage of the address fill the batch
+	setBatcher(t, logStore, 4)
+
+	ctx := context.Background()
+	address := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	topics := []common.Hash{common.HexToHash("0x1234")}
+
+	err := logStore.StoreLogs(ctx, []common.Address{address}, [][]common.Hash{topics},
+		[]types.Log{createTestLog(address, 100, common.HexToHash("0xaaa"), 0)}, 100, 100)
+	require.NoError(t, err)
+	require.Zero(t, countStoredLogs(t, logStore))
+
+	err = logStore.StoreLogs(ctx, []common.Address{address}, [][]common.Hash{topics},
+		[]types.Log{createTestLog(address, 101, common.HexToHash("0xbbb"), 0)}, 101, 101)
+	require.NoError(t, err)
+	require.Equal(t, 2, countStoredLogs(t, logStore))
+}
+
+// BenchmarkLogStore_StoreLogs compares ingesting 100,000 logs, stored in chunks of 100 logs as the log
+// fetcher does, with a transaction per chunk and with the chunks batched into few large transactions.
+func BenchmarkLogStore_StoreLogs(b *testing.B) {
+	const (
+		totalLogs    = 100_000
+		logsPerChunk = 100
+	)
+
+	address := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	topics := [][]common.Hash{{common.HexToHash("0x1234")}}
+	ctx := context.Background()
+
+	for _, batched := range []bool{false, true} {
+		name := "unbatched"
+		if batched {
+			name = "batched"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			logStore, cleanup := setupTestLogStore(b)
+			defer cleanup()
+
+			var batcher *LogBatcher
+			if batched {
+				batcher = setBatcher(b, logStore, 10_000)
+			}
+
+			// Every iteration stores new blocks, one log per block
+			var nextBlock uint64
+			for b.Loop() {
+				for range totalLogs / logsPerChunk {
+					fromBlock := nextBlock
+					logs := make([]types.Log, logsPerChunk)
+					for i := range logs {
+						logs[i] = createTestLog(address, nextBlock, common.BigToHash(new(big.Int).SetUint64(nextBlock)), 0)
+						nextBlock++
+					}
+
+					err := logStore.StoreLogs(ctx, []common.Address{address}, topics, logs, fromBlock, nextBlock-1)
+					require.NoError(b, err)
+				}
+
+				if batcher != nil {
+					require.NoError(b, batcher.Flush(ctx))
+				}
+			}
+
+			b.ReportMetric(float64(totalLogs*b.N)/b.Elapsed().Seconds(), "logs/s")
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -12,7 +13,7 @@ var (
 			Name: "chainindexor_retention_blocks_pruned_total",
 			Help: "Total number of blocks pruned by retention policy",
 		},
-		[]string{"db"},
+		[]string{"chain_id", "db"},
 	)
 
 	retentionLogsPruned = promauto.NewCounterVec(
@@ -20,14 +21,27 @@ var (
 			Name: "chainindexor_retention_logs_pruned_total",
 			Help: "Total number of logs pruned by retention policy",
 		},
-		[]string{"db"},
+		[]string{"chain_id", "db"},
+	)
+
+	// Coverage metrics
+	coverageRangesCompacted = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "chainindexor_coverage_ranges_compacted_total",
+			Help: "Total number of coverage ranges removed by merging them into adjacent or overlapping ranges",
+		},
+		[]string{"chain_id", "table"},
 	)
 )
 
-func RetentionBlocksPrunedInc(db string, count uint64) {
-	retentionBlocksPruned.WithLabelValues(db).Add(float64(count))
+func RetentionBlocksPrunedInc(chainID uint64, db string, count uint64) {
+	retentionBlocksPruned.WithLabelValues(metrics.ChainLabel(chainID), db).Add(float64(count))
+}
+
+func RetentionLogsPrunedInc(chainID uint64, db string, count uint64) {
+	retentionLogsPruned.WithLabelValues(metrics.ChainLabel(chainID), db).Add(float64(count))
 }
 
-func RetentionLogsPrunedInc(db string, count uint64) {
-	retentionLogsPruned.WithLabelValues(db).Add(float64(count))
+func CoverageRangesCompactedInc(chainID uint64, table string, count int) {
+	coverageRangesCompacted.WithLabelValues(metrics.ChainLabel(chainID), table).Add(float64(count))
 }
@@ -72,9 +72,9 @@ func (_c *LogStore_Close_Call) RunAndReturn(run func() error) *LogStore_Close_Ca
 	return _c
 }
 
-// GetLogs provides a mock function with given fields: ctx, address, fromBlock, toBlock
-func (_m *LogStore) GetLogs(ctx context.Context, address common.Address, fromBlock uint64, toBlock uint64) ([]types.Log, []store.CoverageRange, error) {
-	ret := _m.Called(ctx, address, fromBlock, toBlock)
+// GetLogs provides a mock function with given fields: ctx, address, fromBlock, toBlock, topics
+func (_m *LogStore) GetLogs(ctx context.Context, address common.Address, fromBlock uint64, toBlock uint64, topics []*common.Hash) ([]types.Log, []store.CoverageRange, error) {
+	ret := _m.Called(ctx, address, fromBlock, toBlock, topics)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetLogs")
@@ -83,27 +83,27 @@ func (_m *LogStore) GetLogs(ctx context.Context, address common.Address, fromBlo
 	var r0 []types.Log
 	var r1 []store.CoverageRange
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, uint64) ([]types.Log, []store.CoverageRange, error)); ok {
-		return rf(ctx, address, fromBlock, toBlock)
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, uint64, []*common.Hash) ([]types.Log, []store.CoverageRange, error)); ok {
+		return rf(ctx, address, fromBlock, toBlock, topics)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, uint64) []types.Log); ok {
-		r0 = rf(ctx, address, fromBlock, toBlock)
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, uint64, []*common.Hash) []types.Log); ok {
+		r0 = rf(ctx, address, fromBlock, toBlock, topics)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]types.Log)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, common.Address, uint64, uint64) []store.CoverageRange); ok {
-		r1 = rf(ctx, address, fromBlock, toBlock)
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, uint64, uint64, []*common.Hash) []store.CoverageRange); ok {
+		r1 = rf(ctx, address, fromBlock, toBlock, topics)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).([]store.CoverageRange)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, common.Address, uint64, uint64) error); ok {
-		r2 = rf(ctx, address, fromBlock, toBlock)
+	if rf, ok := ret.Get(2).(func(context.Context, common.Address, uint64, uint64, []*common.Hash) error); ok {
+		r2 = rf(ctx, address, fromBlock, toBlock, topics)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -121,13 +121,14 @@ type LogStore_GetLogs_Call struct {
 //   - address common.Address
 //   - fromBlock uint64
 //   - toBlock uint64
-func (_e *LogStore_Expecter) GetLogs(ctx interface{}, address interface{}, fromBlock interface{}, toBlock interface{}) *LogStore_GetLogs_Call {
-	return &LogStore_GetLogs_Call{Call: _e.mock.On("GetLogs", ctx, address, fromBlock, toBlock)}
+//   - topics []*common.Hash
+func (_e *LogStore_Expecter) GetLogs(ctx interface{}, address interface{}, fromBlock interface{}, toBlock interface{}, topics interface{}) *LogStore_GetLogs_Call {
+	return &LogStore_GetLogs_Call{Call: _e.mock.On("GetLogs", ctx, address, fromBlock, toBlock, topics)}
 }
 
-func (_c *LogStore_GetLogs_Call) Run(run func(ctx context.Context, address common.Address, fromBlock uint64, toBlock uint64)) *LogStore_GetLogs_Call {
+func (_c *LogStore_GetLogs_Call) Run(run func(ctx context.Context, address common.Address, fromBlock uint64, toBlock uint64, topics []*common.Hash)) *LogStore_GetLogs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(common.Address), args[2].(uint64), args[3].(uint64))
+		run(args[0].(context.Context), args[1].(common.Address), args[2].(uint64), args[3].(uint64), args[4].([]*common.Hash))
 	})
 	return _c
 }
@@ -137,7 +138,78 @@ func (_c *LogStore_GetLogs_Call) Return(logs []types.Log, coverage []store.Cover
 	return _c
 }
 
-func (_c *LogStore_GetLogs_Call) RunAndReturn(run func(context.Context, common.Address, uint64, uint64) ([]types.Log, []store.CoverageRange, error)) *LogStore_GetLogs_Call {
+func (_c *LogStore_GetLogs_Call) RunAndReturn(run func(context.Context, common.Address, uint64, uint64, []*common.Hash) ([]types.Log, []store.CoverageRange, error)) *LogStore_GetLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLogsBatch provides a mock function with given fields: ctx, addresses, fromBlock, toBlock, topics
+func (_m *LogStore) GetLogsBatch(ctx context.Context, addresses []common.Address, fromBlock uint64, toBlock uint64, topics []*common.Hash) (map[common.Address][]types.Log, map[common.Address][]store.CoverageRange, error) {
+	ret := _m.Called(ctx, addresses, fromBlock, toBlock, topics)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLogsBatch")
+	}
+
+	var r0 map[common.Address][]types.Log
+	var r1 map[common.Address][]store.CoverageRange
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []common.Address, uint64, uint64, []*common.Hash) (map[common.Address][]types.Log, map[common.Address][]store.CoverageRange, error)); ok {
+		return rf(ctx, addresses, fromBlock, toBlock, topics)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []common.Address, uint64, uint64, []*common.Hash) map[common.Address][]types.Log); ok {
+		r0 = rf(ctx, addresses, fromBlock, toBlock, topics)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[common.Address][]types.Log)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []common.Address, uint64, uint64, []*common.Hash) map[common.Address][]store.CoverageRange); ok {
+		r1 = rf(ctx, addresses, fromBlock, toBlock, topics)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(map[common.Address][]store.CoverageRange)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []common.Address, uint64, uint64, []*common.Hash) error); ok {
+		r2 = rf(ctx, addresses, fromBlock, toBlock, topics)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// LogStore_GetLogsBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLogsBatch'
+type LogStore_GetLogsBatch_Call struct {
+	*mock.Call
+}
+
+// GetLogsBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - addresses []common.Address
+//   - fromBlock uint64
+//   - toBlock uint64
+//   - topics []*common.Hash
+func (_e *LogStore_Expecter) GetLogsBatch(ctx interface{}, addresses interface{}, fromBlock interface{}, toBlock interface{}, topics interface{}) *LogStore_GetLogsBatch_Call {
+	return &LogStore_GetLogsBatch_Call{Call: _e.mock.On("GetLogsBatch", ctx, addresses, fromBlock, toBlock, topics)}
+}
+
+func (_c *LogStore_GetLogsBatch_Call) Run(run func(ctx context.Context, addresses []common.Address, fromBlock uint64, toBlock uint64, topics []*common.Hash)) *LogStore_GetLogsBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]common.Address), args[2].(uint64), args[3].(uint64), args[4].([]*common.Hash))
+	})
+	return _c
+}
+
+func (_c *LogStore_GetLogsBatch_Call) Return(logs map[common.Address][]types.Log, coverage map[common.Address][]store.CoverageRange, err error) *LogStore_GetLogsBatch_Call {
+	_c.Call.Return(logs, coverage, err)
+	return _c
+}
+
+func (_c *LogStore_GetLogsBatch_Call) RunAndReturn(run func(context.Context, []common.Address, uint64, uint64, []*common.Hash) (map[common.Address][]types.Log, map[common.Address][]store.CoverageRange, error)) *LogStore_GetLogsBatch_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
+)
+
+var _ store.LogStore = (*NoopLogStore)(nil)
+
+// NoopLogStore is a log store that discards the logs it is given and holds no logs or coverage.
+// It is used by dry runs, which fetch logs without writing them to the database.
+type NoopLogStore struct{}
+
+// NewNoopLogStore creates a new NoopLogStore.
+func NewNoopLogStore() *NoopLogStore {
+	return &NoopLogStore{}
+}
+
+// GetLogs returns no logs and no coverage.
+func (s *NoopLogStore) GetLogs(
+	_ context.Context,
+	_ common.Address,
+	_, _ uint64,
+	_ []*common.Hash,
+) ([]types.Log, []store.CoverageRange, error) {
+	return []types.Log{}, []store.CoverageRange{}, nil
+}
+
+// GetLogsBatch returns an empty entry of logs and coverage for every address.
+func (s *NoopLogStore) GetLogsBatch(
+	_ context.Context,
+	addresses []common.Address,
+	_, _ uint64,
+	_ []*common.Hash,
+) (map[common.Address][]types.Log, map[common.Address][]store.CoverageRange, error) {
+	logs := make(map[common.Address][]types.Log, len(addresses))
+	coverage := make(map[common.Address][]store.CoverageRange, len(addresses))
+	for _, address := range addresses {
+		logs[address] = []types.Log{}
+		coverage[address] = []store.CoverageRange{}
+	}
+
+	return logs, coverage, nil
+}
+
+// StoreLogs discards the logs.
+func (s *NoopLogStore) StoreLogs(
+	_ context.Context,
+	_ []common.Address,
+	_ [][]common.Hash,
+	_ []types.Log,
+	_, _ uint64,
+) error {
+	return nil
+}
+
+// HandleReorg does nothing, as no logs are stored.
+func (s *NoopLogStore) HandleReorg(_ context.Context, _ uint64) error {
+	return nil
+}
+
+// GetUnsyncedTopics reports no unsynced topics.
+func (s *NoopLogStore) GetUnsyncedTopics(
+	_ context.Context,
+	_ []common.Address,
+	_ [][]common.Hash,
+	_ uint64,
+) (*store.UnsyncedTopics, error) {
+	return store.NewUnsyncedTopics(), nil
+}
+
+// Close does nothing.
+func (s *NoopLogStore) Close() error {
+	return nil
+}
@@ -0,0 +1,32 @@
+package store
+
+import (
+	"database/sql"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
+)
+
+var _ store.LogStore = (*PostgresLogStore)(nil)
+
+// PostgresLogStore implements LogStore interface using PostgreSQL as the backend.
+// The database must be opened with db.NewPostgresDBFromConfig, which accepts the ? placeholders
+// of the queries shared with the SQLite LogStore.
+type PostgresLogStore struct {
+	*LogStore
+}
+
+// NewPostgresLogStore creates a new PostgreSQL-backed LogStore.
+func NewPostgresLogStore(
+	database *sql.DB,
+	log *logger.Logger,
+	dbConfig config.DatabaseConfig,
+	retentionPolicy *config.RetentionPolicyConfig,
+	maintenanceCoordinator db.Maintenance,
+) *PostgresLogStore {
+	return &PostgresLogStore{
+		LogStore: newLogStore(database, &postgresBackend{}, log, dbConfig, retentionPolicy, maintenanceCoordinator),
+	}
+}
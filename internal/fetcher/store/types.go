@@ -16,6 +16,7 @@ type dbLog struct {
 	Topic2      *common.Hash   `meddler:"topic2,hash"`
 	Topic3      *common.Hash   `meddler:"topic3,hash"`
 	Data        []byte         `meddler:"data"`
+	Timestamp   uint64         `meddler:"timestamp"`
 	CreatedAt   string         `meddler:"created_at"`
 }
 
@@ -0,0 +1,99 @@
+package fetcher
+
+import (
+	"sync"
+	"time"
+)
+
+// SyncProgressStats is a snapshot of the progress of a backfill.
+type SyncProgressStats struct {
+	// StartBlock and StartTime are where and when the current backfill run started
+	StartBlock uint64
+	StartTime  time.Time
+
+	// CurrentBlock is the last fetched block and TargetBlock the finalized block being synced towards
+	CurrentBlock uint64
+	TargetBlock  uint64
+
+	BlocksRemaining uint64
+	BlocksPerSecond float64
+
+	// ETASeconds is the estimated time to reach the target block, 0 if the rate is not known yet
+	ETASeconds float64
+}
+
+// SyncProgress tracks the rate of a backfill and estimates when it reaches the finalized block.
+// The rate is averaged since the start of the run, which restarts when a chunk does not follow
+// the previous one, e.g. after a reorg rewinds the fetcher. It is safe for concurrent use.
+type SyncProgress struct {
+	mu sync.Mutex
+
+	// logEvery is the number of blocks between progress logs, 0 disables them
+	logEvery uint64
+
+	startTime    time.Time
+	startBlock   uint64
+	currentBlock uint64
+	targetBlock  uint64
+	lastUpdate   time.Time
+	lastLogged   uint64
+}
+
+// NewSyncProgress creates a SyncProgress that asks for progress to be logged every logEvery blocks.
+func NewSyncProgress(logEvery uint64) *SyncProgress {
+	return &SyncProgress{logEvery: logEvery}
+}
+
+// Update records that the blocks from fromBlock to toBlock were fetched between fetchStart and fetchEnd,
+// while syncing towards targetBlock. It reports whether the progress should be logged.
+func (p *SyncProgress) Update(fromBlock, toBlock, targetBlock uint64, fetchStart, fetchEnd time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.startTime.IsZero() || fromBlock != p.currentBlock+1 {
+		p.startTime = fetchStart
+		p.startBlock = fromBlock
+		p.lastLogged = fromBlock
+	}
+
+	p.currentBlock = toBlock
+	p.targetBlock = targetBlock
+	p.lastUpdate = fetchEnd
+
+	if p.logEvery == 0 || toBlock < p.lastLogged+p.logEvery {
+		return false
+	}
+	p.lastLogged = toBlock
+
+	return true
+}
+
+// Stats returns the current progress, rate and estimated time remaining.
+func (p *SyncProgress) Stats() SyncProgressStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := SyncProgressStats{
+		StartBlock:   p.startBlock,
+		StartTime:    p.startTime,
+		CurrentBlock: p.currentBlock,
+		TargetBlock:  p.targetBlock,
+	}
+	if p.startTime.IsZero() {
+		return stats
+	}
+
+	if p.targetBlock > p.currentBlock {
+		stats.BlocksRemaining = p.targetBlock - p.currentBlock
+	}
+
+	if elapsed := p.lastUpdate.Sub(p.startTime).Seconds(); elapsed > 0 {
+		stats.BlocksPerSecond = float64(p.currentBlock-p.startBlock+1) / elapsed
+	}
+
+	if stats.BlocksPerSecond > 0 {
+		stats.ETASeconds = float64(stats.BlocksRemaining) / stats.BlocksPerSecond
+	}
+
+	return stats
+}
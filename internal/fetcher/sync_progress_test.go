@@ -0,0 +1,147 @@
+package fetcher
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncProgress_ETA(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name               string
+		chunks             [][2]uint64
+		elapsed            time.Duration
+		target             uint64
+		expectedRemaining  uint64
+		expectedRate       float64
+		expectedETASeconds float64
+	}{
+		{
+			name:               "100 blocks per second",
+			chunks:             [][2]uint64{{1, 500}, {501, 1000}},
+			elapsed:            10 * time.Second,
+			target:             10_000,
+			expectedRemaining:  9000,
+			expectedRate:       100,
+			expectedETASeconds: 90,
+		},
+		{
+			name:               "2000 blocks per second",
+			chunks:             [][2]uint64{{100, 4099}},
+			elapsed:            2 * time.Second,
+			target:             1_004_099,
+			expectedRemaining:  1_000_000,
+			expectedRate:       2000,
+			expectedETASeconds: 500,
+		},
+		{
+			name:               "target reached",
+			chunks:             [][2]uint64{{1, 50}},
+			elapsed:            time.Second,
+			target:             50,
+			expectedRemaining:  0,
+			expectedRate:       50,
+			expectedETASeconds: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			progress := NewSyncProgress(0)
+
+			// Spread the elapsed time evenly over the chunks
+			step := tt.elapsed / time.Duration(len(tt.chunks))
+			for i, chunk := range tt.chunks {
+				fetchStart := start.Add(time.Duration(i) * step)
+				require.False(t, progress.Update(chunk[0], chunk[1], tt.target, fetchStart, fetchStart.Add(step)))
+			}
+
+			stats := progress.Stats()
+			require.Equal(t, tt.chunks[0][0], stats.StartBlock)
+			require.Equal(t, tt.chunks[len(tt.chunks)-1][1], stats.CurrentBlock)
+			require.Equal(t, tt.target, stats.TargetBlock)
+			require.Equal(t, tt.expectedRemaining, stats.BlocksRemaining)
+			require.InDelta(t, tt.expectedRate, stats.BlocksPerSecond, 1e-9)
+			require.InDelta(t, tt.expectedETASeconds, stats.ETASeconds, 1e-9)
+		})
+	}
+}
+
+func TestSyncProgress_NotStarted(t *testing.T) {
+	t.Parallel()
+
+	stats := NewSyncProgress(100).Stats()
+	require.Zero(t, stats.BlocksRemaining)
+	require.Zero(t, stats.BlocksPerSecond)
+	require.Zero(t, stats.ETASeconds)
+}
+
+func TestSyncProgress_RestartsAfterRewind(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_700_000_000, 0)
+	progress := NewSyncProgress(0)
+
+	progress.Update(1, 1000, 5000, start, start.Add(time.Second))
+
+	// A reorg rewinds the fetcher, so the rate is measured from the new start
+	restart := start.Add(time.Minute)
+	progress.Update(901, 1100, 5000, restart, restart.Add(4*time.Second))
+
+	stats := progress.Stats()
+	require.Equal(t, uint64(901), stats.StartBlock)
+	require.Equal(t, restart, stats.StartTime)
+	require.InDelta(t, 50, stats.BlocksPerSecond, 1e-9)
+	require.InDelta(t, 78, stats.ETASeconds, 1e-9)
+}
+
+func TestSyncProgress_LogInterval(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_700_000_000, 0)
+	progress := NewSyncProgress(1000)
+
+	var logged []uint64
+	for from := uint64(1); from < 5000; from += 400 {
+		to := from + 399
+		if progress.Update(from, to, 10_000, start, start.Add(time.Second)) {
+			logged = append(logged, to)
+		}
+	}
+
+	require.Equal(t, []uint64{1200, 2400, 3600, 4800}, logged)
+}
+
+func TestSyncProgress_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_700_000_000, 0)
+	progress := NewSyncProgress(100)
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := range uint64(100) {
+			progress.Update(i*10+1, i*10+10, 1000, start, start.Add(time.Duration(i+1)*time.Second))
+		}
+	})
+	wg.Go(func() {
+		for range 100 {
+			stats := progress.Stats()
+			require.LessOrEqual(t, stats.CurrentBlock, stats.TargetBlock)
+		}
+	})
+	wg.Wait()
+
+	stats := progress.Stats()
+	require.Equal(t, uint64(1000), stats.CurrentBlock)
+	require.Zero(t, stats.BlocksRemaining)
+	require.InDelta(t, 10, stats.BlocksPerSecond, 1e-9)
+}
@@ -0,0 +1,183 @@
+package indexer
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"unicode/utf8"
+)
+
+var bigIntType = reflect.TypeFor[*big.Int]()
+
+// ABIValue converts the value of an ABI-decoded event parameter to the Go type of its model field.
+// values is the map filled by abi.Arguments.UnpackIntoMap. Integers wider than 64 bits are
+// converted to decimal strings, fixed-size byte arrays to byte slices or hashes, and arrays
+// element by element, e.g. a uint256[] decoded as []*big.Int is converted to []string.
+// The fields select a field of a tuple parameter, descending into nested tuples,
+// e.g. ABIValue[string](values, "order", "amount") returns the amount of the order tuple.
+func ABIValue[T any](values map[string]any, name string, fields ...string) (T, error) {
+	var result T
+
+	value, ok := values[name]
+	if !ok {
+		return result, fmt.Errorf("parameter %s not found in decoded data", name)
+	}
+
+	decoded := reflect.ValueOf(value)
+	for _, field := range fields {
+		decoded, ok = abiTupleField(decoded, field)
+		if !ok {
+			return result, fmt.Errorf("parameter %s: tuple field %s not found in decoded data", name, field)
+		}
+		name += "." + field
+	}
+
+	converted, err := convertABIValue(decoded, reflect.TypeFor[T]())
+	if err != nil {
+		return result, fmt.Errorf("parameter %s: %w", name, err)
+	}
+
+	result, ok = converted.Interface().(T)
+	if !ok {
+		return result, fmt.Errorf("parameter %s: cannot convert %s to %T", name, converted.Type(), result)
+	}
+
+	return result, nil
+}
+
+// TruncateString caps a decoded string parameter to maxLength bytes, cutting it at the last UTF-8
+// character that fits. It reports whether the string was truncated.
+func TruncateString(value string, maxLength int) (string, bool) {
+	if len(value) <= maxLength {
+		return value, false
+	}
+
+	end := maxLength
+	for end > 0 && !utf8.RuneStart(value[end]) {
+		end--
+	}
+
+	return value[:end], true
+}
+
+// abiTupleField returns the named field of a decoded tuple. go-ethereum decodes tuples into structs
+// whose fields carry the component names in their json tags.
+func abiTupleField(tuple reflect.Value, name string) (reflect.Value, bool) {
+	for tuple.Kind() == reflect.Pointer || tuple.Kind() == reflect.Interface {
+		if tuple.IsNil() {
+			return reflect.Value{}, false
+		}
+		tuple = tuple.Elem()
+	}
+
+	if tuple.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	for i := range tuple.NumField() {
+		if tuple.Type().Field(i).Tag.Get("json") == name {
+			return tuple.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// convertABIValue converts a value decoded by go-ethereum's ABI package to the target type.
+func convertABIValue(value reflect.Value, target reflect.Type) (reflect.Value, error) {
+	if !value.IsValid() {
+		return reflect.Value{}, fmt.Errorf("cannot convert nil to %s", target)
+	}
+
+	source := value.Type()
+	if source.AssignableTo(target) {
+		result := reflect.New(target).Elem()
+		result.Set(value)
+		return result, nil
+	}
+
+	switch {
+	case source == bigIntType:
+		number, _ := value.Interface().(*big.Int)
+		return convertABIBigInt(number, target)
+
+	case isABIInteger(source.Kind()) && isABIInteger(target.Kind()):
+		return convertABIInteger(value, target)
+
+	case source.Kind() == reflect.Array && source.Elem().Kind() == reflect.Uint8:
+		// Fixed-size bytes, e.g. bytes32 converted to common.Hash or bytes4 to []byte
+		if source.ConvertibleTo(target) && target.Kind() == reflect.Array {
+			return value.Convert(target), nil
+		}
+		if target.Kind() == reflect.Slice && target.Elem().Kind() == reflect.Uint8 {
+			bytes := reflect.MakeSlice(target, source.Len(), source.Len())
+			reflect.Copy(bytes, value)
+			return bytes, nil
+		}
+
+	case (source.Kind() == reflect.Slice || source.Kind() == reflect.Array) && target.Kind() == reflect.Slice:
+		result := reflect.MakeSlice(target, value.Len(), value.Len())
+		for i := range value.Len() {
+			element, err := convertABIValue(value.Index(i), target.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %w", i, err)
+			}
+			result.Index(i).Set(element)
+		}
+		return result, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot convert %s to %s", source, target)
+}
+
+// convertABIBigInt converts an integer wider than 64 bits to a decimal string or, when it fits, a Go integer.
+func convertABIBigInt(value *big.Int, target reflect.Type) (reflect.Value, error) {
+	result := reflect.New(target).Elem()
+
+	switch {
+	case target.Kind() == reflect.String:
+		result.SetString(value.String())
+	case isABIUnsigned(target.Kind()) && value.IsUint64() && !result.OverflowUint(value.Uint64()):
+		result.SetUint(value.Uint64())
+	case isABISigned(target.Kind()) && value.IsInt64() && !result.OverflowInt(value.Int64()):
+		result.SetInt(value.Int64())
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot convert %s to %s", value, target)
+	}
+
+	return result, nil
+}
+
+// convertABIInteger converts a Go integer, as decoded for integers of up to 64 bits, to another integer type.
+func convertABIInteger(value reflect.Value, target reflect.Type) (reflect.Value, error) {
+	var number *big.Int
+	if isABIUnsigned(value.Kind()) {
+		number = new(big.Int).SetUint64(value.Uint())
+	} else {
+		number = big.NewInt(value.Int())
+	}
+
+	return convertABIBigInt(number, target)
+}
+
+func isABIInteger(kind reflect.Kind) bool {
+	return isABIUnsigned(kind) || isABISigned(kind)
+}
+
+func isABIUnsigned(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isABISigned(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
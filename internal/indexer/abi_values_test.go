@@ -0,0 +1,168 @@
+package indexer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestABIValue(t *testing.T) {
+	t.Parallel()
+
+	maxUint256, ok := new(big.Int).SetString(
+		"115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	require.True(t, ok)
+
+	values := map[string]any{
+		"value":    maxUint256,
+		"amount":   uint32(42),
+		"delta":    int8(-3),
+		"small":    big.NewInt(7),
+		"holder":   common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+		"hash":     [32]byte{1, 2, 3},
+		"selector": [4]byte{0xde, 0xad, 0xbe, 0xef},
+		"ids":      []*big.Int{big.NewInt(1), maxUint256},
+		"holders":  []common.Address{common.HexToAddress("0x01")},
+		"amounts":  [2]uint64{5, 6},
+		"enabled":  true,
+	}
+
+	t.Run("integers wider than 64 bits are decimal strings", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := ABIValue[string](values, "value")
+		require.NoError(t, err)
+		require.Equal(t, maxUint256.String(), value)
+	})
+
+	t.Run("integers are widened to 64 bits", func(t *testing.T) {
+		t.Parallel()
+
+		amount, err := ABIValue[uint64](values, "amount")
+		require.NoError(t, err)
+		require.Equal(t, uint64(42), amount)
+
+		delta, err := ABIValue[int64](values, "delta")
+		require.NoError(t, err)
+		require.Equal(t, int64(-3), delta)
+
+		small, err := ABIValue[uint64](values, "small")
+		require.NoError(t, err)
+		require.Equal(t, uint64(7), small)
+	})
+
+	t.Run("fixed-size bytes", func(t *testing.T) {
+		t.Parallel()
+
+		hash, err := ABIValue[common.Hash](values, "hash")
+		require.NoError(t, err)
+		require.Equal(t, common.Hash{1, 2, 3}, hash)
+
+		selector, err := ABIValue[[]byte](values, "selector")
+		require.NoError(t, err)
+		require.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, selector)
+	})
+
+	t.Run("arrays are converted element by element", func(t *testing.T) {
+		t.Parallel()
+
+		ids, err := ABIValue[[]string](values, "ids")
+		require.NoError(t, err)
+		require.Equal(t, []string{"1", maxUint256.String()}, ids)
+
+		holders, err := ABIValue[[]common.Address](values, "holders")
+		require.NoError(t, err)
+		require.Equal(t, []common.Address{common.HexToAddress("0x01")}, holders)
+
+		amounts, err := ABIValue[[]uint64](values, "amounts")
+		require.NoError(t, err)
+		require.Equal(t, []uint64{5, 6}, amounts)
+	})
+
+	t.Run("values of the same type are returned as is", func(t *testing.T) {
+		t.Parallel()
+
+		enabled, err := ABIValue[bool](values, "enabled")
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("tuple fields", func(t *testing.T) {
+		t.Parallel()
+
+		// go-ethereum decodes tuples into structs tagged with the component names
+		type fill struct {
+			Amount *big.Int `json:"amount"`
+		}
+		tuples := map[string]any{
+			"order": struct {
+				Maker common.Address `json:"maker"`
+				Fill  fill           `json:"fill"`
+			}{
+				Maker: common.HexToAddress("0x02"),
+				Fill:  fill{Amount: maxUint256},
+			},
+		}
+
+		maker, err := ABIValue[common.Address](tuples, "order", "maker")
+		require.NoError(t, err)
+		require.Equal(t, common.HexToAddress("0x02"), maker)
+
+		amount, err := ABIValue[string](tuples, "order", "fill", "amount")
+		require.NoError(t, err)
+		require.Equal(t, maxUint256.String(), amount)
+
+		_, err = ABIValue[string](tuples, "order", "taker")
+		require.ErrorContains(t, err, "tuple field taker not found")
+
+		_, err = ABIValue[string](values, "value", "amount")
+		require.ErrorContains(t, err, "tuple field amount not found")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ABIValue[string](values, "missing")
+		require.ErrorContains(t, err, "parameter missing not found")
+
+		_, err = ABIValue[uint64](values, "value")
+		require.ErrorContains(t, err, "cannot convert")
+
+		_, err = ABIValue[int64](map[string]any{"amount": uint64(1 << 63)}, "amount")
+		require.ErrorContains(t, err, "cannot convert")
+
+		_, err = ABIValue[bool](values, "holder")
+		require.ErrorContains(t, err, "cannot convert")
+	})
+}
+
+func TestTruncateString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		value     string
+		maxLength int
+		expected  string
+		truncated bool
+	}{
+		{name: "shorter", value: "for", maxLength: 4, expected: "for"},
+		{name: "exact", value: "four", maxLength: 4, expected: "four"},
+		{name: "longer", value: "against", maxLength: 4, expected: "agai", truncated: true},
+		// "é" is encoded in two bytes, which are not split
+		{name: "multi-byte character", value: "café", maxLength: 4, expected: "caf", truncated: true},
+		{name: "empty", value: "abstain", maxLength: 0, expected: "", truncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			value, truncated := TruncateString(tt.value, tt.maxLength)
+			require.Equal(t, tt.expected, value)
+			require.Equal(t, tt.truncated, truncated)
+		})
+	}
+}
@@ -5,11 +5,18 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
 	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
 	"github.com/russross/meddler"
@@ -21,15 +28,30 @@ type BaseIndexer struct {
 	log *logger.Logger
 	cfg config.IndexerConfig
 
+	// cache holds the results of recent event queries, nil when the query cache is disabled
+	cache *QueryCache
+
+	// readOnly is the read-only connection exports are read from, opened on first use
+	readOnly   *sql.DB
+	readOnlyMu sync.Mutex
+
 	DB *sql.DB
 }
 
 func NewBaseIndexer(db *sql.DB, log *logger.Logger, cfg config.IndexerConfig) *BaseIndexer {
-	return &BaseIndexer{
+	b := &BaseIndexer{
 		DB:  db,
 		log: log,
 		cfg: cfg,
 	}
+
+	if cfg.Cache != nil && cfg.Cache.Enabled {
+		cacheCfg := *cfg.Cache
+		cacheCfg.ApplyDefaults()
+		b.cache = NewQueryCache(cfg.Name, cfg.Type, cacheCfg)
+	}
+
+	return b
 }
 
 // MetadataProvider defines the interface for indexers to provide event metadata.
@@ -61,18 +83,302 @@ func (b *BaseIndexer) GetEventTypes(provider MetadataProvider) []string {
 	return types
 }
 
+// GetEventSchema returns the field schema of every event this indexer handles, sorted by event name.
+// Fields are read from the `abi:"name,type[,indexed]"` struct tags of the event types, and their
+// columns from the meddler tags; fields without an abi tag (e.g. block metadata) are not part of the schema.
+func (b *BaseIndexer) GetEventSchema(provider MetadataProvider) []indexer.EventSchema {
+	metadata := provider.InitEventMetadata()
+	schemas := make([]indexer.EventSchema, 0, len(metadata))
+
+	for _, meta := range metadata {
+		eventType := meta.EventType
+		if eventType.Kind() == reflect.Ptr {
+			eventType = eventType.Elem()
+		}
+
+		fields := make([]indexer.EventFieldSchema, 0, eventType.NumField())
+		for i := range eventType.NumField() {
+			field := eventType.Field(i)
+			tag, ok := field.Tag.Lookup("abi")
+			if !ok {
+				continue
+			}
+
+			parts := strings.Split(tag, ",")
+			if len(parts) < 2 { //nolint:mnd
+				continue
+			}
+
+			column, _, _ := strings.Cut(field.Tag.Get("meddler"), ",")
+			fields = append(fields, indexer.EventFieldSchema{
+				Name:    parts[0],
+				Type:    parts[1],
+				Indexed: len(parts) > 2 && parts[2] == "indexed", //nolint:mnd
+				Column:  column,
+			})
+		}
+
+		schemas = append(schemas, indexer.EventSchema{Name: meta.Name, Fields: fields})
+	}
+
+	sort.Slice(schemas, func(i, j int) bool { return schemas[i].Name < schemas[j].Name })
+
+	return schemas
+}
+
 // QueryEvents retrieves events based on the provided query parameters.
+// With the query cache enabled, recent results of the same query are served from the cache.
 func (b *BaseIndexer) QueryEvents(
 	ctx context.Context,
 	provider MetadataProvider,
 	qp indexer.QueryParams,
+) (interface{}, int, error) {
+	if err := b.checkOffset(qp); err != nil {
+		return nil, 0, err
+	}
+
+	if b.cache == nil {
+		return b.queryEvents(ctx, provider, qp)
+	}
+
+	return b.cache.Query(qp, func() (any, int, error) {
+		return b.queryEvents(ctx, provider, qp)
+	})
+}
+
+// checkOffset rejects queries skipping more events than the configured maximum offset, and warns about
+// queries getting close to it. The offset is ignored by cursor-paginated queries and aggregations.
+func (b *BaseIndexer) checkOffset(qp indexer.QueryParams) error {
+	if b.cfg.MaxOffset <= 0 || qp.Cursor != nil || qp.After != nil || qp.Aggregation != nil {
+		return nil
+	}
+
+	if qp.Offset > b.cfg.MaxOffset {
+		return fmt.Errorf("%w: offset %d exceeds the maximum of %d", indexer.ErrOffsetTooLarge, qp.Offset, b.cfg.MaxOffset)
+	}
+
+	if qp.Offset > b.cfg.MaxOffset/2 {
+		b.log.Warnf("Slow query of %s events at offset %d, the maximum offset is %d; use cursor-based pagination instead",
+			qp.EventType, qp.Offset, b.cfg.MaxOffset)
+	}
+
+	return nil
+}
+
+// queryEvents retrieves events based on the provided query parameters from the database.
+func (b *BaseIndexer) queryEvents(
+	ctx context.Context,
+	provider MetadataProvider,
+	qp indexer.QueryParams,
 ) (interface{}, int, error) {
 	meta, err := b.getEventMetadata(provider, qp.EventType)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Build query
+	if qp.Aggregation != nil {
+		return b.aggregateEvents(ctx, meta, qp)
+	}
+
+	if qp.Cursor != nil {
+		after, err := indexer.DecodeCursor(*qp.Cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+		qp.After = after
+	}
+
+	if err := validateFields(meta, qp.Fields); err != nil {
+		return nil, 0, err
+	}
+
+	query, args, conditions, err := eventsQuery(meta, qp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Get total count
+	countQuery := strings.Replace(query, "SELECT *", "SELECT COUNT(*)", 1)
+	var total int
+	if err := b.DB.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+	}
+
+	// Events whose data is decoded are returned as maps, along with the decoded parameters
+	event := dataEvent(provider, meta, qp)
+	fields := qp.Fields
+	if event != nil {
+		fields = dataFields(fields)
+	}
+
+	query, args = eventsPageQuery(query, args, conditions, qp)
+	if len(fields) > 0 {
+		// The fields were checked against the columns of the event type, so they are safe to interpolate
+		query = strings.Replace(query, "SELECT *", "SELECT "+strings.Join(fields, ", "), 1)
+	}
+
+	rows, err := b.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query %s events: %w", meta.Name, err)
+	}
+	defer rows.Close()
+
+	if event != nil {
+		eventMaps, err := scanEventMaps(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan %s events: %w", meta.Name, err)
+		}
+
+		keepData := len(qp.Fields) == 0 || slices.Contains(qp.Fields, dataColumn)
+		if err := decodeEventData(eventMaps, event, keepData); err != nil {
+			return nil, 0, err
+		}
+
+		return eventMaps, total, nil
+	}
+
+	var events interface{}
+	if len(qp.Fields) > 0 {
+		events, err = scanEventMaps(rows)
+	} else {
+		events, err = scanEvents(rows, meta.EventType)
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to scan %s events: %w", meta.Name, err)
+	}
+
+	return events, total, nil
+}
+
+// aggregateEvents aggregates the events of meta matching the filters of qp, and returns the result
+// as map[string]interface{}{"result": value} with a total of 1. Counts are computed by the database.
+// Other aggregations read the values of the field and are computed exactly with big.Int, as integers
+// wider than 64 bits are stored as decimal text, and their results are decimal strings.
+// Sums of no events are "0", while their average, minimum and maximum are nil.
+func (b *BaseIndexer) aggregateEvents(
+	ctx context.Context,
+	meta *EventMetadata,
+	qp indexer.QueryParams,
+) (interface{}, int, error) {
+	function, err := aggregationFunction(meta, *qp.Aggregation)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args, _, err := eventsQuery(meta, qp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	field := qp.Aggregation.Field
+
+	if function == indexer.AggregationCount {
+		expr := "COUNT(*)"
+		if field != "" {
+			expr = "COUNT(" + field + ")"
+		}
+		query = strings.Replace(query, "SELECT *", "SELECT "+expr, 1)
+
+		var count int64
+		if err := b.DB.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+			return nil, 0, fmt.Errorf("failed to aggregate %s events: %w", meta.Name, err)
+		}
+
+		return map[string]interface{}{"result": count}, 1, nil
+	}
+
+	query = strings.Replace(query, "SELECT *", "SELECT "+field, 1)
+
+	rows, err := b.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to aggregate %s events: %w", meta.Name, err)
+	}
+	defer rows.Close()
+
+	var (
+		count           int64
+		sum             = new(big.Int)
+		lowest, highest *big.Int
+	)
+
+	for rows.Next() {
+		var raw sql.NullString
+		if err := rows.Scan(&raw); err != nil {
+			return nil, 0, fmt.Errorf("failed to aggregate %s events: %w", meta.Name, err)
+		}
+		if !raw.Valid {
+			continue
+		}
+
+		value, ok := new(big.Int).SetString(raw.String, 10) //nolint:mnd
+		if !ok {
+			return nil, 0, fmt.Errorf("failed to aggregate %s events: %s value %q is not an integer",
+				meta.Name, field, raw.String)
+		}
+
+		count++
+		sum.Add(sum, value)
+		if lowest == nil || value.Cmp(lowest) < 0 {
+			lowest = value
+		}
+		if highest == nil || value.Cmp(highest) > 0 {
+			highest = value
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to aggregate %s events: %w", meta.Name, err)
+	}
+
+	var result interface{}
+
+	switch {
+	case function == indexer.AggregationSum:
+		result = sum.String()
+	case count == 0:
+	case function == indexer.AggregationAvg:
+		result = indexer.FormatAverage(sum, count)
+	case function == indexer.AggregationMin:
+		result = lowest.String()
+	default:
+		result = highest.String()
+	}
+
+	return map[string]interface{}{"result": result}, 1, nil
+}
+
+// aggregationFunction validates the aggregation over the events of meta and returns its lower case
+// function. Only numeric columns are aggregated, so the field is safe to interpolate.
+func aggregationFunction(meta *EventMetadata, aggregation indexer.Aggregation) (string, error) {
+	function := strings.ToLower(aggregation.Function)
+
+	switch function {
+	case indexer.AggregationSum, indexer.AggregationCount, indexer.AggregationAvg,
+		indexer.AggregationMin, indexer.AggregationMax:
+	default:
+		return "", fmt.Errorf("%w: unknown function '%s' (valid functions: sum, count, avg, min, max)",
+			indexer.ErrInvalidAggregation, aggregation.Function)
+	}
+
+	if aggregation.Field == "" {
+		if function != indexer.AggregationCount {
+			return "", fmt.Errorf("%w: a field is required for the %s aggregation",
+				indexer.ErrInvalidAggregation, function)
+		}
+
+		return function, nil
+	}
+
+	if !slices.Contains(meta.NumericColumns, aggregation.Field) {
+		return "", fmt.Errorf("%w: %s is not a numeric field of %s events (numeric fields: %s)",
+			indexer.ErrInvalidAggregation, aggregation.Field, meta.Name, strings.Join(meta.NumericColumns, ", "))
+	}
+
+	return function, nil
+}
+
+// eventsQuery builds the query selecting the events of meta matching the filters of qp,
+// without ordering or pagination. It also returns the arguments and WHERE conditions of the query.
+func eventsQuery(meta *EventMetadata, qp indexer.QueryParams) (string, []interface{}, []string, error) {
 	//nolint:gosec // Table name comes from trusted metadata, not user input
 	query := "SELECT * FROM " + meta.Table
 	args := []interface{}{}
@@ -86,6 +392,20 @@ func (b *BaseIndexer) QueryEvents(
 		conditions = append(conditions, "block_number <= ?")
 		args = append(args, *qp.ToBlock)
 	}
+	if qp.FromTimestamp != nil || qp.ToTimestamp != nil {
+		if !hasColumn(meta.EventType, "timestamp") {
+			return "", nil, nil, fmt.Errorf("%w: %s events have no timestamp",
+				indexer.ErrTimestampFilterUnsupported, meta.Name)
+		}
+		if qp.FromTimestamp != nil {
+			conditions = append(conditions, "timestamp >= ?")
+			args = append(args, *qp.FromTimestamp)
+		}
+		if qp.ToTimestamp != nil {
+			conditions = append(conditions, "timestamp <= ?")
+			args = append(args, *qp.ToTimestamp)
+		}
+	}
 	if qp.Address != "" && len(meta.AddressColumns) > 0 {
 		addrConditions := make([]string, len(meta.AddressColumns))
 		lowerAddress := strings.ToLower(qp.Address)
@@ -96,54 +416,428 @@ func (b *BaseIndexer) QueryEvents(
 		conditions = append(conditions, "("+strings.Join(addrConditions, " OR ")+")")
 	}
 
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	if qp.TxHash != nil {
+		if !hasColumn(meta.EventType, "tx_hash") {
+			return "", nil, nil, fmt.Errorf("%w: %s events have no transaction hash",
+				indexer.ErrTxHashFilterUnsupported, meta.Name)
+		}
+		// Hashes are stored in lowercase hex, so the tx_hash index can be used
+		conditions = append(conditions, "tx_hash = ?")
+		args = append(args, qp.TxHash.Hex())
 	}
 
-	// Get total count
-	countQuery := strings.Replace(query, "SELECT *", "SELECT COUNT(*)", 1)
-	var total int
-	if err := b.DB.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+	topicConds, topicArgs, err := topicConditions(meta, qp)
+	if err != nil {
+		return "", nil, nil, err
 	}
+	conditions = append(conditions, topicConds...)
+	args = append(args, topicArgs...)
 
-	// Apply sorting with whitelist to prevent SQL injection
-	allowedSortColumns := map[string]bool{
-		"block_number": true,
-		"tx_index":     true,
-		"log_index":    true,
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	sortBy := "block_number" // default
-	if qp.SortBy != "" && allowedSortColumns[qp.SortBy] {
-		sortBy = qp.SortBy
-	}
+	return query, args, conditions, nil
+}
 
+// eventsPageQuery extends the filtered events query with the ordering and pagination of qp.
+// With a cursor, it seeks past the cursor on the (block_number, log_index, id) key instead of
+// skipping rows with OFFSET, so the cost of a page does not grow with its position. The id tells
+// apart the events stored for a single log, like the transfers of an ERC-1155 TransferBatch.
+func eventsPageQuery(
+	query string,
+	args []interface{},
+	conditions []string,
+	qp indexer.QueryParams,
+) (string, []interface{}) {
 	sortOrder := "DESC" // default
 	if strings.ToLower(qp.SortOrder) == "asc" {
 		sortOrder = "ASC"
 	}
 
-	query += fmt.Sprintf(" ORDER BY %s %s LIMIT ? OFFSET ?", sortBy, sortOrder)
+	args = slices.Clone(args)
+
+	if qp.After != nil {
+		op := "<"
+		if sortOrder == "ASC" {
+			op = ">"
+		}
+
+		// A cursor without an id is positioned after all events of its log in either order
+		id := qp.After.ID
+		if id == 0 && sortOrder == "ASC" {
+			id = math.MaxInt64
+		}
+
+		cursorCondition := fmt.Sprintf("(block_number, log_index, id) %s (?, ?, ?)", op)
+		if len(conditions) > 0 {
+			query += " AND " + cursorCondition
+		} else {
+			query += " WHERE " + cursorCondition
+		}
+
+		query += fmt.Sprintf(" ORDER BY block_number %s, log_index %s, id %s LIMIT ?", sortOrder, sortOrder, sortOrder)
+		args = append(args, qp.After.BlockNumber, qp.After.LogIndex, id, qp.Limit)
+
+		return query, args
+	}
+
+	// Apply sorting with whitelist to prevent SQL injection
+	orderBy := make([]string, 0, len(qp.SortBy))
+	for _, column := range qp.SortBy {
+		if indexer.IsSortColumn(column) {
+			orderBy = append(orderBy, column+" "+sortOrder)
+		}
+	}
+	if len(orderBy) == 0 {
+		orderBy = append(orderBy, "block_number "+sortOrder) // default
+	}
+	// Events of the same block and log are ordered like with a cursor, so a cursor can follow the page
+	for _, column := range []string{"log_index", "id"} {
+		if !slices.Contains(qp.SortBy, column) {
+			orderBy = append(orderBy, column+" "+sortOrder)
+		}
+	}
+
+	query += fmt.Sprintf(" ORDER BY %s LIMIT ? OFFSET ?", strings.Join(orderBy, ", "))
 	args = append(args, qp.Limit, qp.Offset)
 
-	// Execute query and scan using meddler
+	return query, args
+}
+
+// validateFields checks that every projected field is a column of the events of meta.
+func validateFields(meta *EventMetadata, fields []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	columns, err := meddler.Columns(reflect.New(meta.EventType.Elem()).Interface(), true)
+	if err != nil {
+		return fmt.Errorf("failed to get the columns of %s events: %w", meta.Name, err)
+	}
+
+	var unknown []string
+	for _, field := range fields {
+		if !slices.Contains(columns, field) {
+			unknown = append(unknown, field)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: %s events have no %s (valid fields: %s)",
+			indexer.ErrUnknownField, meta.Name, strings.Join(unknown, ", "), strings.Join(columns, ", "))
+	}
+
+	return nil
+}
+
+// scanEventMaps reads the rows of a projected query into maps keyed by column name.
+// Text the driver returns as bytes is converted to strings, so it is encoded as such in JSON.
+func scanEventMaps(rows *sql.Rows) ([]map[string]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]map[string]interface{}, 0)
+
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		targets := make([]interface{}, len(columns))
+		for i := range values {
+			targets[i] = &values[i]
+		}
+
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+
+		event := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			event[column] = values[i]
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// scanEvents reads the rows one at a time into a slice of eventType, which is a pointer type.
+// Columns are mapped to struct fields by their meddler tags.
+func scanEvents(rows *sql.Rows, eventType reflect.Type) (interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	events := reflect.MakeSlice(reflect.SliceOf(eventType), 0, 0)
+
+	for rows.Next() {
+		event, err := scanEvent(rows, columns, eventType)
+		if err != nil {
+			return nil, err
+		}
+
+		events = reflect.Append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events.Interface(), nil
+}
+
+// scanEvent reads the current row into a new event of eventType, which is a pointer type.
+func scanEvent(rows *sql.Rows, columns []string, eventType reflect.Type) (reflect.Value, error) {
+	event := reflect.New(eventType.Elem())
+
+	targets, err := meddler.Targets(event.Interface(), columns)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if err := rows.Scan(targets...); err != nil {
+		return reflect.Value{}, err
+	}
+	if err := meddler.WriteTargets(event.Interface(), columns, targets); err != nil {
+		return reflect.Value{}, err
+	}
+
+	return event, nil
+}
+
+// hasColumn reports whether events of the given type have a database column with the given name.
+func hasColumn(eventType reflect.Type, column string) bool {
+	columns, err := meddler.Columns(reflect.New(eventType.Elem()).Interface(), true)
+	if err != nil {
+		return false
+	}
+
+	return slices.Contains(columns, column)
+}
+
+// ExportEvents calls fn for every event matching the filters of qp, in block and log index order.
+// Pagination and sorting parameters are ignored. Rows are scanned one at a time, so the events are
+// never held in memory together. The events are read in a single transaction on the read-only
+// connection, so a long export neither blocks the indexer's writes nor sees them. If more than
+// maxRows events match, it fails with indexer.ErrExportTooLarge before calling fn.
+func (b *BaseIndexer) ExportEvents(
+	ctx context.Context,
+	provider MetadataProvider,
+	qp indexer.QueryParams,
+	maxRows uint64,
+	fn func(event any) error,
+) error {
+	meta, err := b.getEventMetadata(provider, qp.EventType)
+	if err != nil {
+		return err
+	}
+
+	query, args, _, err := eventsQuery(meta, qp)
+	if err != nil {
+		return err
+	}
+
+	database, err := b.readOnlyDB()
+	if err != nil {
+		return err
+	}
+
+	tx, err := database.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			b.log.Errorf("failed to rollback transaction: %v", err)
+		}
+	}()
+
+	countQuery := strings.Replace(query, "SELECT *", "SELECT COUNT(*)", 1)
+	var total uint64
+	if err := tx.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return fmt.Errorf("failed to get total count: %w", err)
+	}
+	if total > maxRows {
+		return fmt.Errorf("%w: %d %s events match, at most %d can be exported",
+			indexer.ErrExportTooLarge, total, meta.Name, maxRows)
+	}
+
+	rows, err := tx.QueryContext(ctx, query+" ORDER BY block_number ASC, log_index ASC", args...)
+	if err != nil {
+		return fmt.Errorf("failed to query %s events: %w", meta.Name, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("failed to scan %s events: %w", meta.Name, err)
+	}
+
+	for rows.Next() {
+		event, err := scanEvent(rows, columns, meta.EventType)
+		if err != nil {
+			return fmt.Errorf("failed to scan %s events: %w", meta.Name, err)
+		}
+
+		if err := fn(event.Interface()); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to scan %s events: %w", meta.Name, err)
+	}
+
+	return nil
+}
+
+// readOnlyDB returns the read-only connection to the SQLite database of the indexer, opening it
+// on first use. Without a configured SQLite database, the read-write connection is returned.
+func (b *BaseIndexer) readOnlyDB() (*sql.DB, error) {
+	if b.cfg.DB.Path == "" || (b.cfg.DB.Driver != "" && b.cfg.DB.Driver != config.DBDriverSQLite) {
+		return b.DB, nil
+	}
+
+	b.readOnlyMu.Lock()
+	defer b.readOnlyMu.Unlock()
+
+	if b.readOnly == nil {
+		readOnly, err := db.NewReadOnlySQLiteDBFromConfig(b.cfg.DB)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open read-only database: %w", err)
+		}
+		b.readOnly = readOnly
+	}
+
+	return b.readOnly, nil
+}
+
+// QueryFirstEvent retrieves the earliest event of the given type ordered by block and log index.
+func (b *BaseIndexer) QueryFirstEvent(
+	ctx context.Context,
+	provider MetadataProvider,
+	eventType string,
+) (interface{}, error) {
+	return b.queryEdgeEvent(ctx, provider, eventType, "ASC")
+}
+
+// QueryLastEvent retrieves the latest event of the given type ordered by block and log index.
+func (b *BaseIndexer) QueryLastEvent(
+	ctx context.Context,
+	provider MetadataProvider,
+	eventType string,
+) (interface{}, error) {
+	return b.queryEdgeEvent(ctx, provider, eventType, "DESC")
+}
+
+// queryEdgeEvent fetches a single event from either end of the event table.
+// It bypasses the pagination path of QueryEvents since no total count is needed.
+func (b *BaseIndexer) queryEdgeEvent(
+	ctx context.Context,
+	provider MetadataProvider,
+	eventType string,
+	sortOrder string,
+) (interface{}, error) {
+	meta, err := b.getEventMetadata(provider, eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:gosec // Table name comes from trusted metadata, sort order is not user input
+	query := fmt.Sprintf("SELECT * FROM %s ORDER BY block_number %s, log_index %s LIMIT 1",
+		meta.Table, sortOrder, sortOrder)
+
+	rows, err := b.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s event: %w", meta.Name, err)
+	}
+
+	// EventType is a pointer type, so allocate the underlying struct
+	event := reflect.New(meta.EventType.Elem())
+	if err := meddler.ScanRow(rows, event.Interface()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan %s event: %w", meta.Name, err)
+	}
+
+	return event.Interface(), nil
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, which use backslash as their escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// QueryDistinctValues returns the distinct non-null values of a column of the events of the given type,
+// in ascending order. The field is checked against the columns of the event type before it is used
+// in the query, and fails with indexer.ErrUnknownField if it is not one of them.
+func (b *BaseIndexer) QueryDistinctValues(
+	ctx context.Context,
+	provider MetadataProvider,
+	params indexer.DistinctParams,
+) ([]any, error) {
+	meta, err := b.getEventMetadata(provider, params.EventType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateFields(meta, []string{params.Field}); err != nil {
+		return nil, err
+	}
+
+	//nolint:gosec // Table name comes from trusted metadata, the field is a validated column
+	query := fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s IS NOT NULL", params.Field, meta.Table, params.Field)
+	args := []interface{}{}
+
+	if params.FromBlock != nil {
+		query += " AND block_number >= ?"
+		args = append(args, *params.FromBlock)
+	}
+	if params.ToBlock != nil {
+		query += " AND block_number <= ?"
+		args = append(args, *params.ToBlock)
+	}
+	if params.Prefix != "" {
+		query += fmt.Sprintf(` AND %s LIKE ? || '%%' ESCAPE '\'`, params.Field)
+		args = append(args, likeEscaper.Replace(params.Prefix))
+	}
+
+	query += fmt.Sprintf(" ORDER BY %s LIMIT ?", params.Field)
+	args = append(args, params.Limit)
+
 	rows, err := b.DB.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query %s events: %w", meta.Name, err)
+		return nil, fmt.Errorf("failed to query distinct %s of %s events: %w", params.Field, meta.Name, err)
 	}
 	defer rows.Close()
 
-	// Create a slice pointer to hold event pointers using reflection
-	sliceType := reflect.SliceOf(meta.EventType)
-	slicePtr := reflect.New(sliceType)
-	slice := slicePtr.Elem()
+	values := make([]any, 0)
+	for rows.Next() {
+		var value any
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan distinct %s of %s events: %w", params.Field, meta.Name, err)
+		}
+
+		// Text the driver returns as bytes is encoded as a string in JSON
+		if raw, ok := value.([]byte); ok {
+			value = string(raw)
+		}
+		values = append(values, value)
+	}
 
-	if err := meddler.ScanAll(rows, slicePtr.Interface()); err != nil {
-		return nil, 0, fmt.Errorf("failed to scan %s events: %w", meta.Name, err)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan distinct %s of %s events: %w", params.Field, meta.Name, err)
 	}
 
-	return slice.Interface(), total, nil
+	return values, nil
 }
 
 // GetStats returns statistics about the indexed data.
@@ -438,14 +1132,54 @@ func (b *BaseIndexer) StartBlock() uint64 {
 	return b.cfg.StartBlock
 }
 
-// Close closes the database connection.
+// ConfirmationBuffer returns the number of additional confirmations logs must have
+// before they are delivered to this indexer.
+func (b *BaseIndexer) ConfirmationBuffer() uint64 {
+	return b.cfg.ConfirmationBuffer
+}
+
+// Close closes the database connections.
 func (b *BaseIndexer) Close() error {
+	b.readOnlyMu.Lock()
+	if b.readOnly != nil {
+		if err := b.readOnly.Close(); err != nil {
+			b.log.Errorf("failed to close read-only database: %v", err)
+		}
+		b.readOnly = nil
+	}
+	b.readOnlyMu.Unlock()
+
 	if b.DB != nil {
 		return b.DB.Close()
 	}
 	return nil
 }
 
+// Ping checks that the database is reachable by executing a trivial query.
+func (b *BaseIndexer) Ping(ctx context.Context) error {
+	if _, err := b.DB.ExecContext(ctx, "SELECT 1"); err != nil {
+		return fmt.Errorf("failed to query database: %w", err)
+	}
+
+	return nil
+}
+
+// InvalidateQueryCache drops the cached results of the event queries covering the blocks of the
+// given logs. Indexers call it after storing the events of the logs.
+func (b *BaseIndexer) InvalidateQueryCache(logs []types.Log) {
+	if b.cache == nil || len(logs) == 0 {
+		return
+	}
+
+	fromBlock, toBlock := logs[0].BlockNumber, logs[0].BlockNumber
+	for _, log := range logs[1:] {
+		fromBlock = min(fromBlock, log.BlockNumber)
+		toBlock = max(toBlock, log.BlockNumber)
+	}
+
+	b.cache.Invalidate(fromBlock, toBlock)
+}
+
 // HandleReorg handles a blockchain reorganization by removing data from the reorg point.
 // This is generic and works with any indexer.
 func (b *BaseIndexer) HandleReorg(provider MetadataProvider, blockNum uint64) error {
@@ -479,6 +1213,11 @@ func (b *BaseIndexer) HandleReorg(provider MetadataProvider, blockNum uint64) er
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	if b.cache != nil {
+		b.cache.Invalidate(blockNum, math.MaxUint64)
+	}
+
+	metrics.ForIndexer(b.cfg.Name, b.cfg.Type).ReorgHandledInc()
 	b.log.Infof("Handled reorg from block %d", blockNum)
 
 	return nil
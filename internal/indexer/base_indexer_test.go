@@ -2,10 +2,22 @@ package indexer
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"path"
+	"reflect"
+	"strings"
 	"testing"
 
+	gethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/require"
 )
@@ -20,7 +32,7 @@ func (m *MockMetadataProvider) InitEventMetadata() map[string]*EventMetadata {
 }
 
 // setupTestDB creates an in-memory SQLite database for testing.
-func setupTestDB(t *testing.T) *sql.DB {
+func setupTestDB(t testing.TB) *sql.DB {
 	t.Helper()
 
 	db, err := sql.Open("sqlite3", ":memory:")
@@ -151,6 +163,21 @@ func TestStartBlock(t *testing.T) {
 	require.Equal(t, uint64(12345), idx.StartBlock())
 }
 
+func TestPing(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+
+	log, err := logger.NewLogger("debug", true)
+	require.NoError(t, err)
+
+	idx := NewBaseIndexer(db, log, config.IndexerConfig{Type: "erc20", Name: "test"})
+	require.NoError(t, idx.Ping(t.Context()))
+
+	require.NoError(t, db.Close())
+	require.Error(t, idx.Ping(t.Context()))
+}
+
 func TestGetEventTypes(t *testing.T) {
 	t.Parallel()
 
@@ -216,6 +243,1271 @@ func TestGetStats(t *testing.T) {
 	require.Equal(t, int64(1), eventCounts["Approval"])
 }
 
+// testTransfer is a minimal event model matching the transfers test table.
+type testTransfer struct {
+	ID          int64  `meddler:"id,pk"`
+	BlockNumber uint64 `meddler:"block_number"`
+	TxIndex     uint   `meddler:"tx_index"`
+	LogIndex    uint   `meddler:"log_index"`
+	TxHash      string `meddler:"tx_hash,zeroisnull"`
+	BlockHash   string `meddler:"block_hash,zeroisnull"`
+	From        string `meddler:"from_address" abi:"from,address,indexed"`
+	To          string `meddler:"to_address" abi:"to,address,indexed"`
+	Value       string `meddler:"value" abi:"value,uint256"`
+}
+
+// testApproval is an event model with a non-pointer event type.
+type testApproval struct {
+	ID      int64  `meddler:"id,pk"`
+	Owner   string `meddler:"owner" abi:"owner,address,indexed"`
+	Spender string `meddler:"spender" abi:"spender,address,indexed"`
+	Value   string `meddler:"value" abi:"value,uint256"`
+}
+
+func TestGetEventSchema(t *testing.T) {
+	t.Parallel()
+
+	bi := NewBaseIndexer(nil, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test"})
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	metadata["approval"].EventType = reflect.TypeOf(testApproval{})
+
+	schema := bi.GetEventSchema(&MockMetadataProvider{metadata: metadata})
+	require.Equal(t, []indexer.EventSchema{
+		{
+			Name: "Approval",
+			Fields: []indexer.EventFieldSchema{
+				{Name: "owner", Type: "address", Indexed: true, Column: "owner"},
+				{Name: "spender", Type: "address", Indexed: true, Column: "spender"},
+				{Name: "value", Type: "uint256", Indexed: false, Column: "value"},
+			},
+		},
+		{
+			Name: "Transfer",
+			Fields: []indexer.EventFieldSchema{
+				{Name: "from", Type: "address", Indexed: true, Column: "from_address"},
+				{Name: "to", Type: "address", Indexed: true, Column: "to_address"},
+				{Name: "value", Type: "uint256", Indexed: false, Column: "value"},
+			},
+		},
+	}, schema)
+}
+
+func TestQueryFirstAndLastEvent(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	log, err := logger.NewLogger("debug", true)
+	require.NoError(t, err)
+	bi := NewBaseIndexer(db, log, config.IndexerConfig{Type: "test", Name: "test"})
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	provider := &MockMetadataProvider{metadata: metadata}
+
+	ctx := t.Context()
+
+	// Empty table returns no event
+	event, err := bi.QueryFirstEvent(ctx, provider, "Transfer")
+	require.NoError(t, err)
+	require.Nil(t, event)
+
+	_, err = db.Exec(`
+	INSERT INTO transfers (block_number, tx_index, log_index, from_address, to_address, value)
+	VALUES (101, 2, 1, '0xccc', '0xddd', '2000'),
+	       (100, 1, 0, '0xaaa', '0xbbb', '1000'),
+	       (102, 1, 0, '0xeee', '0xfff', '3000'),
+	       (102, 1, 3, '0x111', '0x222', '4000');
+	`)
+	require.NoError(t, err)
+
+	event, err = bi.QueryFirstEvent(ctx, provider, "Transfer")
+	require.NoError(t, err)
+	first, ok := event.(*testTransfer)
+	require.True(t, ok)
+	require.Equal(t, uint64(100), first.BlockNumber)
+	require.Equal(t, "1000", first.Value)
+
+	event, err = bi.QueryLastEvent(ctx, provider, "transfer")
+	require.NoError(t, err)
+	last, ok := event.(*testTransfer)
+	require.True(t, ok)
+	require.Equal(t, uint64(102), last.BlockNumber)
+	require.Equal(t, uint(3), last.LogIndex)
+
+	_, err = bi.QueryLastEvent(ctx, provider, "Unknown")
+	require.ErrorContains(t, err, "unknown event type")
+}
+
+func TestQueryEvents(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bi := NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test"})
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	provider := &MockMetadataProvider{metadata: metadata}
+
+	_, err := db.Exec(`
+	INSERT INTO transfers (block_number, tx_index, log_index, from_address, to_address, value)
+	VALUES (100, 0, 0, '0xaaa', '0xbbb', '1'),
+	       (100, 0, 1, '0xaaa', '0xccc', '2'),
+	       (101, 0, 0, '0xccc', '0xaaa', '3'),
+	       (102, 0, 0, '0xbbb', '0xccc', '4'),
+	       (102, 1, 5, '0xaaa', '0xbbb', '5');
+	`)
+	require.NoError(t, err)
+
+	opaqueCursor := indexer.EncodeCursor(indexer.EventCursor{BlockNumber: 100, LogIndex: 1, ID: 2})
+
+	values := func(events interface{}) []string {
+		transfers, ok := events.([]*testTransfer)
+		require.True(t, ok)
+
+		result := make([]string, len(transfers))
+		for i, transfer := range transfers {
+			result[i] = transfer.Value
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name          string
+		params        indexer.QueryParams
+		expected      []string
+		expectedTotal int
+	}{
+		{
+			name:          "offset",
+			params:        indexer.QueryParams{Limit: 2, Offset: 1, SortOrder: "asc"},
+			expected:      []string{"2", "3"},
+			expectedTotal: 5,
+		},
+		{
+			name:          "sort by several columns",
+			params:        indexer.QueryParams{Limit: 10, SortBy: []string{"log_index", "block_number"}, SortOrder: "asc"},
+			expected:      []string{"1", "3", "4", "2", "5"},
+			expectedTotal: 5,
+		},
+		{
+			name: "secondary sort column orders events of the same block",
+			params: indexer.QueryParams{
+				Limit: 2, Offset: 1, SortBy: []string{"block_number", "log_index"}, SortOrder: "desc",
+			},
+			expected:      []string{"4", "3"},
+			expectedTotal: 5,
+		},
+		{
+			name: "unknown sort columns are ignored",
+			params: indexer.QueryParams{
+				Limit: 10, SortBy: []string{"value", "log_index", "block_number"}, SortOrder: "desc",
+			},
+			expected:      []string{"5", "2", "4", "3", "1"},
+			expectedTotal: 5,
+		},
+		{
+			name: "cursor ascending",
+			params: indexer.QueryParams{
+				Limit: 2, SortOrder: "asc", After: &indexer.EventCursor{BlockNumber: 100, LogIndex: 1},
+			},
+			expected:      []string{"3", "4"},
+			expectedTotal: 5,
+		},
+		{
+			name: "cursor descending",
+			params: indexer.QueryParams{
+				Limit: 10, SortOrder: "desc", After: &indexer.EventCursor{BlockNumber: 102, LogIndex: 0},
+			},
+			expected:      []string{"3", "2", "1"},
+			expectedTotal: 5,
+		},
+		{
+			name: "cursor ignores offset and sort_by",
+			params: indexer.QueryParams{
+				Limit: 10, Offset: 3, SortBy: []string{"tx_index"}, SortOrder: "asc",
+				After: &indexer.EventCursor{BlockNumber: 101, LogIndex: 0},
+			},
+			expected:      []string{"4", "5"},
+			expectedTotal: 5,
+		},
+		{
+			name: "cursor with filters",
+			params: indexer.QueryParams{
+				Limit: 10, SortOrder: "asc", Address: "0xAAA",
+				After: &indexer.EventCursor{BlockNumber: 100, LogIndex: 0},
+			},
+			expected:      []string{"2", "3", "5"},
+			expectedTotal: 4,
+		},
+		{
+			name: "opaque cursor",
+			params: indexer.QueryParams{
+				Limit: 2, SortOrder: "asc", Cursor: &opaqueCursor,
+			},
+			expected:      []string{"3", "4"},
+			expectedTotal: 5,
+		},
+		{
+			name: "cursor after the last event",
+			params: indexer.QueryParams{
+				Limit: 10, SortOrder: "asc", After: &indexer.EventCursor{BlockNumber: 102, LogIndex: 5},
+			},
+			expected:      []string{},
+			expectedTotal: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.EventType = "Transfer"
+
+			events, total, err := bi.QueryEvents(t.Context(), provider, tt.params)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedTotal, total)
+			require.Equal(t, tt.expected, values(events))
+		})
+	}
+}
+
+func TestQueryEvents_InvalidCursor(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bi := NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test"})
+	provider := &MockMetadataProvider{metadata: createTestMetadata(t)}
+
+	cursor := "not a cursor"
+	_, _, err := bi.QueryEvents(t.Context(), provider, indexer.QueryParams{
+		EventType: "Transfer", Limit: 10, Cursor: &cursor,
+	})
+	require.ErrorIs(t, err, indexer.ErrInvalidCursor)
+}
+
+func TestQueryEvents_Fields(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bi := NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test"})
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	provider := &MockMetadataProvider{metadata: metadata}
+
+	_, err := db.Exec(`
+	INSERT INTO transfers (block_number, tx_index, log_index, from_address, to_address, value)
+	VALUES (100, 0, 0, '0xaaa', '0xbbb', '1'),
+	       (101, 0, 0, '0xccc', '0xaaa', '2'),
+	       (102, 0, 0, '0xbbb', '0xccc', '3');
+	`)
+	require.NoError(t, err)
+
+	t.Run("all fields", func(t *testing.T) {
+		events, total, err := bi.QueryEvents(t.Context(), provider, indexer.QueryParams{
+			EventType: "Transfer", Limit: 10, SortOrder: "asc",
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, total)
+
+		transfers, ok := events.([]*testTransfer)
+		require.True(t, ok)
+		require.Len(t, transfers, 3)
+		require.Equal(t, testTransfer{
+			ID: 1, BlockNumber: 100, From: "0xaaa", To: "0xbbb", Value: "1",
+		}, *transfers[0])
+	})
+
+	t.Run("projected fields", func(t *testing.T) {
+		events, total, err := bi.QueryEvents(t.Context(), provider, indexer.QueryParams{
+			EventType: "Transfer", Limit: 2, SortOrder: "desc", Address: "0xAAA",
+			Fields: []string{"block_number", "from_address"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, total)
+		require.Equal(t, []map[string]interface{}{
+			{"block_number": int64(101), "from_address": "0xccc"},
+			{"block_number": int64(100), "from_address": "0xaaa"},
+		}, events)
+	})
+
+	t.Run("unknown fields", func(t *testing.T) {
+		_, _, err := bi.QueryEvents(t.Context(), provider, indexer.QueryParams{
+			EventType: "Transfer", Limit: 10,
+			Fields: []string{"block_number", "amount", "owner"},
+		})
+		require.ErrorIs(t, err, indexer.ErrUnknownField)
+		require.ErrorContains(t, err, "Transfer events have no amount, owner")
+	})
+}
+
+func TestQueryEvents_Aggregation(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bi := NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test"})
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	metadata["transfer"].NumericColumns = []string{"value"}
+	provider := &MockMetadataProvider{metadata: metadata}
+
+	// Values wider than 64 bits are stored as decimal text. The values above 2^53 are not exactly
+	// representable as floating point numbers, so they are only aggregated exactly with big.Int
+	_, err := db.Exec(`
+	INSERT INTO transfers (block_number, tx_index, log_index, from_address, to_address, value)
+	VALUES (100, 0, 0, '0xaaa', '0xbbb', '9'),
+	       (101, 0, 0, '0xccc', '0xaaa', '9007199254740993'),
+	       (102, 0, 0, '0xbbb', '0xccc', '100000000000000000001');
+	`)
+	require.NoError(t, err)
+
+	fromBlock := uint64(101)
+	emptyFromBlock := uint64(200)
+
+	tests := []struct {
+		name        string
+		aggregation indexer.Aggregation
+		fromBlock   *uint64
+		result      interface{}
+	}{
+		{name: "sum", aggregation: indexer.Aggregation{Field: "value", Function: "sum"}, result: "100009007199254741003"},
+		{
+			name:        "avg",
+			aggregation: indexer.Aggregation{Field: "value", Function: "avg"},
+			result:      "33336335733084913667.666666666666666667",
+		},
+		{name: "min", aggregation: indexer.Aggregation{Field: "value", Function: "min"}, result: "9"},
+		{name: "max", aggregation: indexer.Aggregation{Field: "value", Function: "max"}, result: "100000000000000000001"},
+		{name: "count", aggregation: indexer.Aggregation{Function: "count"}, result: int64(3)},
+		{name: "count of a field", aggregation: indexer.Aggregation{Field: "value", Function: "COUNT"}, result: int64(3)},
+		{
+			name:        "filtered",
+			aggregation: indexer.Aggregation{Field: "value", Function: "min"},
+			fromBlock:   &fromBlock,
+			result:      "9007199254740993",
+		},
+		{
+			name:        "sum of no events",
+			aggregation: indexer.Aggregation{Field: "value", Function: "sum"},
+			fromBlock:   &emptyFromBlock,
+			result:      "0",
+		},
+		{
+			name:        "avg of no events",
+			aggregation: indexer.Aggregation{Field: "value", Function: "avg"},
+			fromBlock:   &emptyFromBlock,
+			result:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, total, err := bi.QueryEvents(t.Context(), provider, indexer.QueryParams{
+				EventType: "Transfer", Limit: 1, FromBlock: tt.fromBlock, Aggregation: &tt.aggregation,
+			})
+			require.NoError(t, err)
+			require.Equal(t, 1, total)
+			require.Equal(t, map[string]interface{}{"result": tt.result}, result)
+		})
+	}
+
+	t.Run("invalid aggregations", func(t *testing.T) {
+		for _, aggregation := range []indexer.Aggregation{
+			{Field: "from_address", Function: "sum"},
+			{Field: "value", Function: "median"},
+			{Function: "avg"},
+		} {
+			_, _, err := bi.QueryEvents(t.Context(), provider, indexer.QueryParams{
+				EventType: "Transfer", Aggregation: &aggregation,
+			})
+			require.ErrorIs(t, err, indexer.ErrInvalidAggregation)
+		}
+	})
+}
+
+func TestQueryDistinctValues(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bi := NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test"})
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	provider := &MockMetadataProvider{metadata: metadata}
+
+	_, err := db.Exec(`
+	INSERT INTO transfers (block_number, tx_index, log_index, from_address, to_address, value)
+	VALUES (100, 0, 0, '0xccc', '0xbbb', '1'),
+	       (101, 0, 0, '0xaaa', '0xaaa', '2'),
+	       (102, 0, 0, '0xccc', '0xccc', '3'),
+	       (103, 0, 0, '0xab_', '0xccc', '4'),
+	       (104, 0, 0, NULL, '0xccc', '5');
+	`)
+	require.NoError(t, err)
+
+	fromBlock, toBlock := uint64(101), uint64(102)
+
+	tests := []struct {
+		name   string
+		params indexer.DistinctParams
+		values []any
+	}{
+		{
+			name:   "all values",
+			params: indexer.DistinctParams{EventType: "Transfer", Field: "from_address", Limit: 10},
+			values: []any{"0xaaa", "0xab_", "0xccc"},
+		},
+		{
+			name:   "limit",
+			params: indexer.DistinctParams{EventType: "Transfer", Field: "from_address", Limit: 1},
+			values: []any{"0xaaa"},
+		},
+		{
+			name: "block range",
+			params: indexer.DistinctParams{
+				EventType: "Transfer", Field: "from_address", Limit: 10,
+				FromBlock: &fromBlock, ToBlock: &toBlock,
+			},
+			values: []any{"0xaaa", "0xccc"},
+		},
+		{
+			name:   "prefix",
+			params: indexer.DistinctParams{EventType: "Transfer", Field: "from_address", Prefix: "0XA", Limit: 10},
+			values: []any{"0xaaa", "0xab_"},
+		},
+		{
+			name:   "prefix with wildcards",
+			params: indexer.DistinctParams{EventType: "Transfer", Field: "from_address", Prefix: "0xa_", Limit: 10},
+			values: []any{},
+		},
+		{
+			name:   "integer field",
+			params: indexer.DistinctParams{EventType: "Transfer", Field: "block_number", FromBlock: &toBlock, Limit: 10},
+			values: []any{int64(102), int64(103), int64(104)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := bi.QueryDistinctValues(t.Context(), provider, tt.params)
+			require.NoError(t, err)
+			require.Equal(t, tt.values, values)
+		})
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := bi.QueryDistinctValues(t.Context(), provider, indexer.DistinctParams{
+			EventType: "Transfer", Field: "from_address FROM approvals --", Limit: 10,
+		})
+		require.ErrorIs(t, err, indexer.ErrUnknownField)
+	})
+}
+
+// testTimedTransfer is a transfer event model that records the timestamp of its block.
+type testTimedTransfer struct {
+	ID          int64  `meddler:"id,pk"`
+	BlockNumber uint64 `meddler:"block_number"`
+	TxIndex     uint   `meddler:"tx_index"`
+	LogIndex    uint   `meddler:"log_index"`
+	Value       string `meddler:"value"`
+	Timestamp   uint64 `meddler:"timestamp"`
+}
+
+func TestQueryEvents_TimestampFilter(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bi := NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test"})
+
+	_, err := db.Exec(`
+	CREATE TABLE timed_transfers (
+		id INTEGER PRIMARY KEY,
+		block_number INTEGER NOT NULL,
+		tx_index INTEGER NOT NULL,
+		log_index INTEGER NOT NULL,
+		value TEXT,
+		timestamp INTEGER NOT NULL DEFAULT 0
+	);
+
+	INSERT INTO timed_transfers (block_number, tx_index, log_index, value, timestamp)
+	VALUES (100, 0, 0, '1', 1700000000),
+	       (101, 0, 0, '2', 1700000012),
+	       (102, 0, 0, '3', 1700000024);
+	`)
+	require.NoError(t, err)
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	metadata["timedtransfer"] = &EventMetadata{
+		Name:      "TimedTransfer",
+		Table:     "timed_transfers",
+		EventType: reflect.TypeOf((*testTimedTransfer)(nil)),
+	}
+	provider := &MockMetadataProvider{metadata: metadata}
+
+	timestamp := func(ts uint64) *uint64 { return &ts }
+
+	tests := []struct {
+		name          string
+		fromTimestamp *uint64
+		toTimestamp   *uint64
+		expected      []string
+	}{
+		{
+			name:          "range",
+			fromTimestamp: timestamp(1700000012),
+			toTimestamp:   timestamp(1700000024),
+			expected:      []string{"2", "3"},
+		},
+		{
+			name:          "exact timestamp",
+			fromTimestamp: timestamp(1700000012),
+			toTimestamp:   timestamp(1700000012),
+			expected:      []string{"2"},
+		},
+		{name: "from only", fromTimestamp: timestamp(1700000001), expected: []string{"2", "3"}},
+		{name: "to only", toTimestamp: timestamp(1700000011), expected: []string{"1"}},
+		{name: "no match", fromTimestamp: timestamp(1800000000), expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, total, err := bi.QueryEvents(t.Context(), provider, indexer.QueryParams{
+				EventType:     "TimedTransfer",
+				Limit:         10,
+				SortOrder:     "asc",
+				FromTimestamp: tt.fromTimestamp,
+				ToTimestamp:   tt.toTimestamp,
+			})
+			require.NoError(t, err)
+			require.Equal(t, len(tt.expected), total)
+
+			transfers, ok := events.([]*testTimedTransfer)
+			require.True(t, ok)
+
+			values := make([]string, len(transfers))
+			for i, transfer := range transfers {
+				values[i] = transfer.Value
+			}
+			require.Equal(t, tt.expected, values)
+		})
+	}
+
+	t.Run("event type without timestamp", func(t *testing.T) {
+		_, _, err := bi.QueryEvents(t.Context(), provider, indexer.QueryParams{
+			EventType: "Transfer", Limit: 10, FromTimestamp: timestamp(1700000000),
+		})
+		require.ErrorIs(t, err, indexer.ErrTimestampFilterUnsupported)
+	})
+}
+
+// testDataTransfer is a transfer event model that keeps the raw data of its log.
+type testDataTransfer struct {
+	ID          int64  `meddler:"id,pk"`
+	BlockNumber uint64 `meddler:"block_number"`
+	TxIndex     uint   `meddler:"tx_index"`
+	LogIndex    uint   `meddler:"log_index"`
+	From        string `meddler:"from_address" abi:"from,address,indexed"`
+	To          string `meddler:"to_address" abi:"to,address,indexed"`
+	Data        []byte `meddler:"data"`
+}
+
+// testABIProvider is a metadata provider that provides the ABI of its events.
+type testABIProvider struct {
+	MockMetadataProvider
+	abi gethabi.ABI
+}
+
+func (p *testABIProvider) ABIFields() gethabi.ABI {
+	return p.abi
+}
+
+func TestQueryEvents_DecodeData(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bi := NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test"})
+
+	eventsABI, err := gethabi.JSON(strings.NewReader(`[{"type": "event", "name": "Transfer", "inputs": [
+		{"name": "from", "type": "address", "indexed": true},
+		{"name": "to", "type": "address", "indexed": true},
+		{"name": "value", "type": "uint256", "indexed": false}
+	]}]`))
+	require.NoError(t, err)
+
+	// A value beyond the range of 64-bit integers
+	value, ok := new(big.Int).SetString("1000000000000000000000000", 10)
+	require.True(t, ok)
+	data, err := eventsABI.Events["Transfer"].Inputs.NonIndexed().Pack(value)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`
+	CREATE TABLE data_transfers (
+		id INTEGER PRIMARY KEY,
+		block_number INTEGER NOT NULL,
+		tx_index INTEGER NOT NULL,
+		log_index INTEGER NOT NULL,
+		from_address TEXT,
+		to_address TEXT,
+		data BLOB
+	)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO data_transfers (block_number, tx_index, log_index, from_address, to_address, data)
+		VALUES (100, 0, 0, '0xaaa', '0xbbb', ?)`, data)
+	require.NoError(t, err)
+
+	metadata := map[string]*EventMetadata{
+		"transfer": {
+			Name:      "Transfer",
+			Table:     "data_transfers",
+			EventType: reflect.TypeOf((*testDataTransfer)(nil)),
+		},
+	}
+	provider := &testABIProvider{MockMetadataProvider: MockMetadataProvider{metadata: metadata}, abi: eventsABI}
+
+	t.Run("uint256 decoded as a decimal string", func(t *testing.T) {
+		events, total, err := bi.QueryEvents(t.Context(), provider, indexer.QueryParams{
+			EventType:  "Transfer",
+			Limit:      10,
+			DecodeData: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, total)
+
+		transfers, ok := events.([]map[string]interface{})
+		require.True(t, ok)
+		require.Len(t, transfers, 1)
+		require.Equal(t, map[string]interface{}{"value": "1000000000000000000000000"}, transfers[0]["decoded"])
+		require.Equal(t, hexutil.Encode(data), transfers[0]["data"])
+		require.Equal(t, "0xbbb", transfers[0]["to_address"])
+	})
+
+	t.Run("data column only returned when requested", func(t *testing.T) {
+		events, _, err := bi.QueryEvents(t.Context(), provider, indexer.QueryParams{
+			EventType:  "Transfer",
+			Limit:      10,
+			Fields:     []string{"block_number"},
+			DecodeData: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{{
+			"block_number": int64(100),
+			"decoded":      map[string]interface{}{"value": "1000000000000000000000000"},
+		}}, events)
+	})
+
+	t.Run("indexers without an ABI are unaffected", func(t *testing.T) {
+		events, _, err := bi.QueryEvents(t.Context(), &provider.MockMetadataProvider, indexer.QueryParams{
+			EventType:  "Transfer",
+			Limit:      10,
+			DecodeData: true,
+		})
+		require.NoError(t, err)
+
+		transfers, ok := events.([]*testDataTransfer)
+		require.True(t, ok)
+		require.Len(t, transfers, 1)
+		require.Equal(t, data, transfers[0].Data)
+	})
+}
+
+// TestQueryEvents_CursorStablePagination pages through the newest events first while new
+// events are indexed between the requests. The newly indexed events shift the offset pages,
+// so the second offset page repeats events of the first, while the cursor pages do not.
+// testTokenEvent is an event model with indexed parameters of several types.
+type testTokenEvent struct {
+	ID          int64  `meddler:"id,pk"`
+	BlockNumber uint64 `meddler:"block_number"`
+	LogIndex    uint   `meddler:"log_index"`
+	Owner       string `meddler:"owner_address" abi:"owner,address,indexed"`
+	TokenID     string `meddler:"token_id" abi:"tokenId,uint256,indexed"`
+	Name        string `meddler:"name" abi:"name,string,indexed"`
+}
+
+func TestQueryEvents_TopicFilter(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bi := NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test"})
+
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+	_, err := db.Exec(`
+	INSERT INTO transfers (block_number, tx_index, log_index, from_address, to_address, value)
+	VALUES (100, 0, 0, ?, ?, '1'),
+	       (101, 0, 0, ?, ?, '2'),
+	       (102, 0, 0, ?, ?, '3');
+
+	CREATE TABLE tokens (
+		id INTEGER PRIMARY KEY,
+		block_number INTEGER NOT NULL,
+		log_index INTEGER NOT NULL,
+		owner_address TEXT,
+		token_id TEXT,
+		name TEXT
+	);
+
+	INSERT INTO tokens (block_number, log_index, owner_address, token_id, name)
+	VALUES (100, 0, ?, '7', 'a'),
+	       (101, 0, ?, '8', 'b');
+	`, alice.Hex(), bob.Hex(), bob.Hex(), alice.Hex(), strings.ToLower(alice.Hex()), bob.Hex(),
+		alice.Hex(), alice.Hex())
+	require.NoError(t, err)
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	metadata["token"] = &EventMetadata{
+		Name:      "Token",
+		Table:     "tokens",
+		EventType: reflect.TypeOf((*testTokenEvent)(nil)),
+	}
+	provider := &MockMetadataProvider{metadata: metadata}
+
+	topic := func(hash common.Hash) *common.Hash { return &hash }
+	addressTopic := func(address common.Address) *common.Hash { return topic(common.BytesToHash(address.Bytes())) }
+	transferTopic := topic(crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")))
+
+	query := func(params indexer.QueryParams) ([]string, error) {
+		if params.EventType == "" {
+			params.EventType = "Transfer"
+		}
+		params.Limit = 10
+		params.SortOrder = "asc"
+
+		events, total, err := bi.QueryEvents(t.Context(), provider, params)
+		if err != nil {
+			return nil, err
+		}
+
+		values := []string{}
+		switch events := events.(type) {
+		case []*testTransfer:
+			for _, event := range events {
+				values = append(values, event.Value)
+			}
+		case []*testTokenEvent:
+			for _, event := range events {
+				values = append(values, event.Name)
+			}
+		}
+		require.Len(t, values, total)
+
+		return values, nil
+	}
+
+	tests := []struct {
+		name     string
+		params   indexer.QueryParams
+		expected []string
+	}{
+		{name: "topic0", params: indexer.QueryParams{Topic0: transferTopic}, expected: []string{"1", "2", "3"}},
+		{name: "topic0 of another event", params: indexer.QueryParams{Topic0: topic(common.HexToHash("0x1"))},
+			expected: []string{}},
+		{name: "topic1", params: indexer.QueryParams{Topic1: addressTopic(alice)}, expected: []string{"1", "3"}},
+		{name: "topic2", params: indexer.QueryParams{Topic2: addressTopic(alice)}, expected: []string{"2"}},
+		{
+			name:     "topic0 and topic1",
+			params:   indexer.QueryParams{Topic0: transferTopic, Topic1: addressTopic(bob)},
+			expected: []string{"2"},
+		},
+		{
+			name:     "topic1 and topic2",
+			params:   indexer.QueryParams{Topic1: addressTopic(alice), Topic2: addressTopic(bob)},
+			expected: []string{"1", "3"},
+		},
+		{
+			name:     "topic1 and topic2 without match",
+			params:   indexer.QueryParams{Topic1: addressTopic(bob), Topic2: addressTopic(bob)},
+			expected: []string{},
+		},
+		{
+			name:     "integer topic",
+			params:   indexer.QueryParams{EventType: "Token", Topic2: topic(common.BigToHash(big.NewInt(8)))},
+			expected: []string{"b"},
+		},
+		{
+			name: "topic and address",
+			params: indexer.QueryParams{
+				EventType: "Token", Topic1: addressTopic(alice), Topic2: topic(common.BigToHash(big.NewInt(7))),
+			},
+			expected: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := query(tt.params)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, values)
+		})
+	}
+
+	t.Run("topic the event does not have", func(t *testing.T) {
+		_, err := query(indexer.QueryParams{Topic3: addressTopic(alice)})
+		require.ErrorIs(t, err, indexer.ErrTopicFilterUnsupported)
+	})
+
+	t.Run("hashed topic", func(t *testing.T) {
+		_, err := query(indexer.QueryParams{EventType: "Token", Topic3: topic(crypto.Keccak256Hash([]byte("a")))})
+		require.ErrorIs(t, err, indexer.ErrTopicFilterUnsupported)
+	})
+}
+
+// testOrderEvent is an event model with a tuple parameter flattened into a field per tuple field.
+type testOrderEvent struct {
+	ID          int64  `meddler:"id,pk"`
+	BlockNumber uint64 `meddler:"block_number"`
+	OrderHash   string `meddler:"order_hash" abi:"orderHash,bytes32,indexed"`
+	OrderMaker  string `meddler:"order_maker" abi:"order.maker,address"`
+	OrderAsset  string `meddler:"order_asset_token" abi:"order.asset.token,address"`
+	OrderAmount string `meddler:"order_asset_amount" abi:"order.asset.amount,uint256"`
+	Fee         string `meddler:"fee" abi:"fee,uint256"`
+}
+
+func TestEventSignature_TupleFields(t *testing.T) {
+	t.Parallel()
+
+	signature, indexed, err := eventSignature("OrderFilled", reflect.TypeOf(testOrderEvent{}))
+	require.NoError(t, err)
+	require.Equal(t, "OrderFilled(bytes32,(address,(address,uint256)),uint256)", signature)
+	require.Len(t, indexed, 1)
+	require.Equal(t, "order_hash", indexed[0].column)
+}
+
+func TestQueryEvents_TxHashFilter(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bi := NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test"})
+
+	sale := common.HexToHash("0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060")
+	other := common.HexToHash("0x1")
+
+	_, err := db.Exec(`
+	INSERT INTO transfers (block_number, tx_index, log_index, tx_hash, from_address, to_address, value)
+	VALUES (100, 0, 0, ?, '0xa', '0xb', '1'),
+	       (100, 1, 1, ?, '0xa', '0xb', '2'),
+	       (100, 0, 2, ?, '0xb', '0xa', '3');
+	`, sale.Hex(), other.Hex(), sale.Hex())
+	require.NoError(t, err)
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	metadata["approval"].EventType = reflect.TypeOf((*testApproval)(nil))
+	provider := &MockMetadataProvider{metadata: metadata}
+
+	events, total, err := bi.QueryEvents(t.Context(), provider, indexer.QueryParams{
+		EventType: "Transfer",
+		TxHash:    &sale,
+		Limit:     10,
+		SortOrder: "asc",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+
+	transfers, ok := events.([]*testTransfer)
+	require.True(t, ok)
+	require.Len(t, transfers, 2)
+	require.Equal(t, "1", transfers[0].Value)
+	require.Equal(t, "3", transfers[1].Value)
+
+	// Approvals of the test model do not record their transaction
+	_, _, err = bi.QueryEvents(t.Context(), provider, indexer.QueryParams{
+		EventType: "Approval",
+		TxHash:    &sale,
+		Limit:     10,
+	})
+	require.ErrorIs(t, err, indexer.ErrTxHashFilterUnsupported)
+}
+
+func TestQueryEvents_CursorStablePagination(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bi := NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test"})
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	provider := &MockMetadataProvider{metadata: metadata}
+
+	insert := func(blockNumber uint64, value string) {
+		_, err := db.Exec(`
+		INSERT INTO transfers (block_number, tx_index, log_index, from_address, to_address, value)
+		VALUES (?, 0, 0, '0xaaa', '0xbbb', ?)`, blockNumber, value)
+		require.NoError(t, err)
+	}
+
+	query := func(params indexer.QueryParams) []*testTransfer {
+		params.EventType = "Transfer"
+		params.Limit = 2
+		params.SortOrder = "desc"
+
+		events, _, err := bi.QueryEvents(t.Context(), provider, params)
+		require.NoError(t, err)
+
+		transfers, ok := events.([]*testTransfer)
+		require.True(t, ok)
+
+		return transfers
+	}
+
+	values := func(transfers []*testTransfer) []string {
+		result := make([]string, len(transfers))
+		for i, transfer := range transfers {
+			result[i] = transfer.Value
+		}
+
+		return result
+	}
+
+	for i := range uint64(5) {
+		insert(100+i, fmt.Sprint(i+1))
+	}
+
+	firstPage := query(indexer.QueryParams{})
+	require.Equal(t, []string{"5", "4"}, values(firstPage))
+
+	last := firstPage[len(firstPage)-1]
+	cursor := indexer.EncodeCursor(indexer.EventCursor{
+		BlockNumber: last.BlockNumber, LogIndex: last.LogIndex, ID: last.ID,
+	})
+
+	// Two new events are indexed before the second page is requested
+	insert(105, "6")
+	insert(106, "7")
+
+	require.Equal(t, []string{"5", "4"}, values(query(indexer.QueryParams{Offset: 2})))
+	require.Equal(t, []string{"3", "2"}, values(query(indexer.QueryParams{Cursor: &cursor})))
+}
+
+func TestQueryEvents_CursorSplitsLogs(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bi := NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test"})
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	provider := &MockMetadataProvider{metadata: metadata}
+
+	// Logs storing several events, like ERC-1155 batch transfers, share their block number and log index
+	_, err := db.Exec(`
+	INSERT INTO transfers (block_number, tx_index, log_index, from_address, to_address, value)
+	VALUES (100, 0, 0, '0xaaa', '0xbbb', '1'),
+	       (100, 0, 0, '0xaaa', '0xbbb', '2'),
+	       (100, 0, 0, '0xaaa', '0xbbb', '3'),
+	       (100, 0, 1, '0xaaa', '0xccc', '4'),
+	       (101, 0, 0, '0xccc', '0xaaa', '5'),
+	       (101, 0, 0, '0xccc', '0xaaa', '6');
+	`)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		sortOrder string
+		expected  []string
+	}{
+		{sortOrder: "asc", expected: []string{"1", "2", "3", "4", "5", "6"}},
+		{sortOrder: "desc", expected: []string{"6", "5", "4", "3", "2", "1"}},
+	} {
+		t.Run(tt.sortOrder, func(t *testing.T) {
+			// Every page boundary but the last falls inside a log
+			values := make([]string, 0, len(tt.expected))
+			params := indexer.QueryParams{EventType: "Transfer", Limit: 2, SortOrder: tt.sortOrder}
+			for {
+				events, _, err := bi.QueryEvents(t.Context(), provider, params)
+				require.NoError(t, err)
+
+				transfers, ok := events.([]*testTransfer)
+				require.True(t, ok)
+				for _, transfer := range transfers {
+					values = append(values, transfer.Value)
+				}
+				if len(transfers) < params.Limit {
+					break
+				}
+
+				cursor, ok := indexer.CursorOf(reflect.ValueOf(transfers[len(transfers)-1]))
+				require.True(t, ok)
+				params.After = &cursor
+			}
+
+			require.Equal(t, tt.expected, values)
+		})
+	}
+}
+
+func TestQueryEvents_MaxOffset(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bi := NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test", MaxOffset: 4})
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	provider := &MockMetadataProvider{metadata: metadata}
+
+	for i := range 6 {
+		_, err := db.Exec(`
+		INSERT INTO transfers (block_number, tx_index, log_index, from_address, to_address, value)
+		VALUES (?, 0, 0, '0xaaa', '0xbbb', ?)`, 100+i, fmt.Sprint(i+1))
+		require.NoError(t, err)
+	}
+
+	query := func(params indexer.QueryParams) ([]string, error) {
+		params.EventType = "Transfer"
+		params.Limit = 10
+		params.SortOrder = "asc"
+
+		events, _, err := bi.QueryEvents(t.Context(), provider, params)
+		if err != nil {
+			return nil, err
+		}
+
+		transfers, ok := events.([]*testTransfer)
+		require.True(t, ok)
+
+		values := make([]string, len(transfers))
+		for i, transfer := range transfers {
+			values[i] = transfer.Value
+		}
+
+		return values, nil
+	}
+
+	// The maximum offset itself is allowed
+	values, err := query(indexer.QueryParams{Offset: 4})
+	require.NoError(t, err)
+	require.Equal(t, []string{"5", "6"}, values)
+
+	_, err = query(indexer.QueryParams{Offset: 5})
+	require.ErrorIs(t, err, indexer.ErrOffsetTooLarge)
+	require.ErrorContains(t, err, "offset 5 exceeds the maximum of 4")
+
+	// Cursors reach the same position, ignoring the offset
+	cursor := indexer.EncodeCursor(indexer.EventCursor{BlockNumber: 104, LogIndex: 0})
+	values, err = query(indexer.QueryParams{Cursor: &cursor, Offset: 5})
+	require.NoError(t, err)
+	require.Equal(t, []string{"6"}, values)
+
+	values, err = query(indexer.QueryParams{After: &indexer.EventCursor{BlockNumber: 104}, Offset: 5})
+	require.NoError(t, err)
+	require.Equal(t, []string{"6"}, values)
+
+	// Without a maximum, any offset is allowed
+	bi = NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test"})
+	values, err = query(indexer.QueryParams{Offset: 5})
+	require.NoError(t, err)
+	require.Equal(t, []string{"6"}, values)
+}
+
+func TestExportEvents(t *testing.T) {
+	t.Parallel()
+
+	// Exports read from a read-only connection, which needs a database file
+	dbCfg := config.DatabaseConfig{Path: path.Join(t.TempDir(), "export.db")}
+	dbCfg.ApplyDefaults()
+
+	database, err := db.NewSQLiteDBFromConfig(dbCfg)
+	require.NoError(t, err)
+
+	_, err = database.Exec(`CREATE TABLE transfers (
+		id INTEGER PRIMARY KEY,
+		block_number INTEGER NOT NULL,
+		tx_index INTEGER NOT NULL,
+		log_index INTEGER NOT NULL,
+		tx_hash TEXT,
+		block_hash TEXT,
+		from_address TEXT,
+		to_address TEXT,
+		value TEXT
+	)`)
+	require.NoError(t, err)
+
+	bi := NewBaseIndexer(database, logger.NewNopLogger(), config.IndexerConfig{Type: "test", Name: "test", DB: dbCfg})
+	defer bi.Close()
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	provider := &MockMetadataProvider{metadata: metadata}
+
+	insert := func(blockNumber uint64, logIndex uint, from, value string) {
+		_, err := database.Exec(`
+		INSERT INTO transfers (block_number, tx_index, log_index, from_address, to_address, value)
+		VALUES (?, 0, ?, ?, '0xbbb', ?)`, blockNumber, logIndex, from, value)
+		require.NoError(t, err)
+	}
+
+	insert(102, 0, "0xaaa", "4")
+	insert(100, 1, "0xaaa", "2")
+	insert(100, 0, "0xccc", "1")
+	insert(101, 0, "0xaaa", "3")
+
+	export := func(params indexer.QueryParams, maxRows uint64) ([]string, error) {
+		params.EventType = "transfer"
+
+		var values []string
+		err := bi.ExportEvents(t.Context(), provider, params, maxRows, func(event any) error {
+			transfer, ok := event.(*testTransfer)
+			require.True(t, ok)
+
+			values = append(values, transfer.Value)
+			return nil
+		})
+
+		return values, err
+	}
+
+	t.Run("all events in block and log index order", func(t *testing.T) {
+		values, err := export(indexer.QueryParams{Limit: 1, Offset: 1, SortOrder: "desc"}, 4)
+		require.NoError(t, err)
+		require.Equal(t, []string{"1", "2", "3", "4"}, values)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		fromBlock := uint64(101)
+		values, err := export(indexer.QueryParams{FromBlock: &fromBlock}, 10)
+		require.NoError(t, err)
+		require.Equal(t, []string{"3", "4"}, values)
+
+		values, err = export(indexer.QueryParams{Address: "0xCCC"}, 10)
+		require.NoError(t, err)
+		require.Equal(t, []string{"1"}, values)
+	})
+
+	t.Run("too many events", func(t *testing.T) {
+		values, err := export(indexer.QueryParams{}, 3)
+		require.ErrorIs(t, err, indexer.ErrExportTooLarge)
+		require.Empty(t, values)
+	})
+
+	t.Run("writes during an export", func(t *testing.T) {
+		var values []string
+		err := bi.ExportEvents(t.Context(), provider, indexer.QueryParams{EventType: "transfer"}, 10,
+			func(event any) error {
+				// The indexer keeps storing events while the export reads, and the export does not see them
+				if len(values) == 0 {
+					insert(103, 0, "0xaaa", "5")
+				}
+
+				transfer, ok := event.(*testTransfer)
+				require.True(t, ok)
+
+				values = append(values, transfer.Value)
+				return nil
+			})
+		require.NoError(t, err)
+		require.Equal(t, []string{"1", "2", "3", "4"}, values)
+
+		values, err = export(indexer.QueryParams{}, 10)
+		require.NoError(t, err)
+		require.Equal(t, []string{"1", "2", "3", "4", "5"}, values)
+	})
+
+	t.Run("callback error", func(t *testing.T) {
+		errStop := errors.New("client disconnected")
+		err := bi.ExportEvents(t.Context(), provider, indexer.QueryParams{EventType: "transfer"}, 10,
+			func(any) error { return errStop })
+		require.ErrorIs(t, err, errStop)
+	})
+}
+
+// BenchmarkQueryEvents_DeepPage compares fetching a page deep into a large table with
+// OFFSET and with a keyset cursor. The OFFSET query gets slower with the page position,
+// while the keyset query seeks directly to the cursor.
+func BenchmarkQueryEvents_DeepPage(b *testing.B) {
+	const (
+		events        = 1_000_100
+		logsPerBlock  = 10
+		pageSize      = 100
+		deepPosition  = 1_000_000
+		shallowOffset = 1_000
+	)
+
+	db := setupTestDB(b)
+	defer db.Close()
+
+	// Every connection to an in-memory database opens its own empty database
+	db.SetMaxOpenConns(1)
+
+	_, err := db.Exec(`CREATE INDEX idx_transfers_block_number ON transfers(block_number)`)
+	require.NoError(b, err)
+
+	_, err = db.Exec(`
+	WITH RECURSIVE seq(n) AS (SELECT 0 UNION ALL SELECT n + 1 FROM seq WHERE n < ?)
+	INSERT INTO transfers (block_number, tx_index, log_index, from_address, to_address, value)
+	SELECT n / ?, 0, n % ?, '0xaaa', '0xbbb', '1' FROM seq`, events-1, logsPerBlock, logsPerBlock)
+	require.NoError(b, err)
+
+	meta := &EventMetadata{Table: "transfers", EventType: reflect.TypeOf((*testTransfer)(nil))}
+
+	for _, position := range []int{shallowOffset, deepPosition} {
+		// The cursor points at the event right before the position, so both queries return the same page
+		cursor := &indexer.EventCursor{
+			BlockNumber: uint64((position - 1) / logsPerBlock),
+			LogIndex:    uint((position - 1) % logsPerBlock),
+		}
+
+		for _, bc := range []struct {
+			name   string
+			params indexer.QueryParams
+		}{
+			{name: "offset", params: indexer.QueryParams{Limit: pageSize, Offset: position, SortOrder: "asc"}},
+			{name: "keyset", params: indexer.QueryParams{Limit: pageSize, After: cursor, SortOrder: "asc"}},
+		} {
+			b.Run(fmt.Sprintf("%s/position_%d", bc.name, position), func(b *testing.B) {
+				query, args := eventsPageQuery("SELECT * FROM "+meta.Table, nil, nil, bc.params)
+
+				for b.Loop() {
+					rows, err := db.QueryContext(b.Context(), query, args...)
+					if err != nil {
+						b.Fatal(err)
+					}
+
+					page, err := scanEvents(rows, meta.EventType)
+					rows.Close()
+					if err != nil {
+						b.Fatal(err)
+					}
+
+					transfers, ok := page.([]*testTransfer)
+					if !ok || len(transfers) != pageSize || transfers[0].BlockNumber != uint64(position/logsPerBlock) {
+						b.Fatalf("unexpected page at position %d", position)
+					}
+				}
+			})
+		}
+	}
+}
+
 func TestGetStatsEmptyTables(t *testing.T) {
 	t.Parallel()
 
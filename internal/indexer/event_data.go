@@ -0,0 +1,115 @@
+package indexer
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+)
+
+const (
+	// dataColumn is the column holding the raw data of the event logs
+	dataColumn = "data"
+
+	// decodedKey is the key of the ABI-decoded parameters in the events queried with DecodeData
+	decodedKey = "decoded"
+)
+
+// ABIProvider is implemented by the metadata providers of indexers that keep the raw data of their
+// event logs in a data column. Their events can be queried with QueryParams.DecodeData, which
+// decodes the data with the ABI of the event; other indexers ignore it.
+type ABIProvider interface {
+	MetadataProvider
+
+	// ABIFields returns the ABI of the indexed events. Events are looked up by their metadata name
+	ABIFields() abi.ABI
+}
+
+// dataEvent returns the ABI of the events of meta if the query decodes their data and the indexer
+// supports it, and nil otherwise.
+func dataEvent(provider MetadataProvider, meta *EventMetadata, qp indexer.QueryParams) *abi.Event {
+	if !qp.DecodeData || qp.Aggregation != nil {
+		return nil
+	}
+
+	abiProvider, ok := provider.(ABIProvider)
+	if !ok || !hasColumn(meta.EventType, dataColumn) {
+		return nil
+	}
+
+	event, ok := abiProvider.ABIFields().Events[meta.Name]
+	if !ok {
+		return nil
+	}
+
+	return &event
+}
+
+// decodeEventData adds the non-indexed parameters of the event, ABI-decoded from the data column of
+// every event map, under the decoded key. The data column is kept as hex if keepData is set, and
+// removed otherwise.
+func decodeEventData(events []map[string]interface{}, event *abi.Event, keepData bool) error {
+	args := event.Inputs.NonIndexed()
+
+	for _, e := range events {
+		// The data was scanned as a string by scanEventMaps
+		var data []byte
+		switch value := e[dataColumn].(type) {
+		case string:
+			data = []byte(value)
+		case []byte:
+			data = value
+		}
+
+		values, err := args.UnpackValues(data)
+		if err != nil {
+			return fmt.Errorf("failed to decode the data of %s event %v: %w", event.Name, e["id"], err)
+		}
+
+		decoded := make(map[string]interface{}, len(args))
+		for i, arg := range args {
+			decoded[arg.Name] = decodedValue(values[i])
+		}
+		e[decodedKey] = decoded
+
+		if keepData {
+			e[dataColumn] = hexutil.Encode(data)
+		} else {
+			delete(e, dataColumn)
+		}
+	}
+
+	return nil
+}
+
+// decodedValue converts an ABI-decoded value for JSON responses: integers become decimal strings,
+// as JSON numbers lose the precision of 256-bit integers, and byte strings become hex.
+func decodedValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case []*big.Int:
+		values := make([]string, len(v))
+		for i, n := range v {
+			values[i] = n.String()
+		}
+		return values
+	case []byte:
+		return hexutil.Encode(v)
+	default:
+		return v
+	}
+}
+
+// dataFields returns the fields a query decoding the event data selects: the requested fields and
+// the data column. All columns are selected when no fields are requested.
+func dataFields(fields []string) []string {
+	if len(fields) == 0 || slices.Contains(fields, dataColumn) {
+		return fields
+	}
+
+	return append(slices.Clone(fields), dataColumn)
+}
@@ -26,6 +26,7 @@ type EventMetadata struct {
 	Table          string       // Database table name (e.g., "transfers")
 	EventType      reflect.Type // Reflection type for scanning
 	AddressColumns []string     // Column names containing addresses
+	NumericColumns []string     // Column names containing integers, which events can be aggregated over
 }
 
 // CalibrationPoint represents a block number to timestamp mapping for interpolation.
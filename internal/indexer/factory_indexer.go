@@ -0,0 +1,87 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+)
+
+var _ indexer.DynamicAddressProvider = (*FactoryIndexer)(nil)
+
+// FactoryIndexer discovers the contracts deployed by a factory contract from the table in which
+// another, ordinary indexer stores the factory's creation events, e.g. the PairCreated events of
+// a Uniswap V2 factory. Embed it in the indexer of the deployed contracts to implement
+// indexer.DynamicAddressProvider.
+type FactoryIndexer struct {
+	factoryDB *sql.DB
+	query     string
+	events    map[common.Hash]struct{}
+}
+
+// NewFactoryIndexer creates a FactoryIndexer that reads the addresses of the deployed contracts
+// from the given column of a factory event table in factoryDB. Like every event table, the table
+// must have a block_number column. events are the topics to index for every deployed contract.
+func NewFactoryIndexer(
+	factoryDB *sql.DB,
+	table, addressColumn string,
+	events map[common.Hash]struct{},
+) (*FactoryIndexer, error) {
+	if table == "" || addressColumn == "" {
+		return nil, errors.New("factory event table and address column are required")
+	}
+	if len(events) == 0 {
+		return nil, errors.New("at least one event to index for the deployed contracts is required")
+	}
+
+	// A contract is discovered at the block of its first creation event
+	query := fmt.Sprintf(`
+		SELECT %[2]s FROM %[1]s
+		WHERE block_number <= ?
+		GROUP BY %[2]s
+		ORDER BY MIN(block_number), %[2]s`,
+		table, addressColumn)
+
+	return &FactoryIndexer{
+		factoryDB: factoryDB,
+		query:     query,
+		events:    events,
+	}, nil
+}
+
+// AddressFor returns the addresses of the contracts whose creation event was indexed at or before
+// the given block, in the order they were created.
+func (f *FactoryIndexer) AddressFor(ctx context.Context, blockNumber uint64) ([]common.Address, error) {
+	rows, err := f.factoryDB.QueryContext(ctx, f.query, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query factory events: %w", err)
+	}
+	defer rows.Close()
+
+	var addresses []common.Address
+	for rows.Next() {
+		var address string
+		if err := rows.Scan(&address); err != nil {
+			return nil, fmt.Errorf("failed to scan factory event: %w", err)
+		}
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid contract address in factory event: %q", address)
+		}
+
+		addresses = append(addresses, common.HexToAddress(address))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read factory events: %w", err)
+	}
+
+	return addresses, nil
+}
+
+// DynamicEvents returns the set of event topic hashes indexed for every deployed contract.
+func (f *FactoryIndexer) DynamicEvents() map[common.Hash]struct{} {
+	return f.events
+}
@@ -0,0 +1,102 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func setupFactoryTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	_, err = db.Exec(`
+	CREATE TABLE pair_created (
+		id INTEGER PRIMARY KEY,
+		block_number INTEGER NOT NULL,
+		log_index INTEGER NOT NULL,
+		token0 TEXT,
+		token1 TEXT,
+		pair TEXT
+	)`)
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestFactoryIndexer_AddressFor(t *testing.T) {
+	t.Parallel()
+
+	db := setupFactoryTestDB(t)
+	pair1 := common.HexToAddress("0x11")
+	pair2 := common.HexToAddress("0x12")
+	pair3 := common.HexToAddress("0x13")
+
+	for _, event := range []struct {
+		block uint64
+		pair  common.Address
+	}{
+		{block: 20, pair: pair2},
+		{block: 10, pair: pair1},
+		{block: 30, pair: pair3},
+		// A contract created again, e.g. after a reorg, is discovered at its first creation event
+		{block: 40, pair: pair1},
+	} {
+		_, err := db.Exec("INSERT INTO pair_created (block_number, log_index, pair) VALUES (?, 0, ?)",
+			event.block, event.pair.Hex())
+		require.NoError(t, err)
+	}
+
+	swapTopic := common.HexToHash("0xd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d822")
+	factory, err := NewFactoryIndexer(db, "pair_created", "pair", map[common.Hash]struct{}{swapTopic: {}})
+	require.NoError(t, err)
+	require.Equal(t, map[common.Hash]struct{}{swapTopic: {}}, factory.DynamicEvents())
+
+	tests := []struct {
+		blockNumber uint64
+		expected    []common.Address
+	}{
+		{blockNumber: 9, expected: nil},
+		{blockNumber: 10, expected: []common.Address{pair1}},
+		{blockNumber: 29, expected: []common.Address{pair1, pair2}},
+		{blockNumber: 100, expected: []common.Address{pair1, pair2, pair3}},
+	}
+
+	for _, tt := range tests {
+		addresses, err := factory.AddressFor(context.Background(), tt.blockNumber)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, addresses, "block %d", tt.blockNumber)
+	}
+}
+
+func TestFactoryIndexer_Errors(t *testing.T) {
+	t.Parallel()
+
+	db := setupFactoryTestDB(t)
+	events := map[common.Hash]struct{}{common.HexToHash("0x01"): {}}
+
+	_, err := NewFactoryIndexer(db, "", "pair", events)
+	require.ErrorContains(t, err, "table and address column are required")
+
+	_, err = NewFactoryIndexer(db, "pair_created", "pair", nil)
+	require.ErrorContains(t, err, "at least one event")
+
+	missing, err := NewFactoryIndexer(db, "pairs", "pair", events)
+	require.NoError(t, err)
+	_, err = missing.AddressFor(context.Background(), 1)
+	require.ErrorContains(t, err, "failed to query factory events")
+
+	_, err = db.Exec("INSERT INTO pair_created (block_number, log_index, pair) VALUES (1, 0, 'not an address')")
+	require.NoError(t, err)
+
+	invalid, err := NewFactoryIndexer(db, "pair_created", "pair", events)
+	require.NoError(t, err)
+	_, err = invalid.AddressFor(context.Background(), 1)
+	require.ErrorContains(t, err, "invalid contract address")
+}
@@ -0,0 +1,161 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+)
+
+// FallbackIndexerName is the name and type of the fallback indexer.
+const FallbackIndexerName = "fallback"
+
+var _ indexer.Indexer = (*FallbackIndexer)(nil)
+
+// SignatureResolver resolves event topic0 hashes to human-readable event signatures.
+type SignatureResolver interface {
+	// Name returns the event signature of the given topic0, or its hex representation if it is unknown
+	Name(ctx context.Context, topic0 common.Hash) string
+}
+
+// FallbackIndexer stores logs that no registered indexer claimed in the unmatched_logs table,
+// keeping the raw data, address and topics so that no events are lost when a contract starts
+// emitting events before its indexer is configured.
+// It does not take part in log filtering and is always consulted after all other indexers.
+type FallbackIndexer struct {
+	db         *sql.DB
+	log        *logger.Logger
+	signatures SignatureResolver
+}
+
+// NewFallbackIndexer creates a new FallbackIndexer that writes to the given database.
+// The database must contain the unmatched_logs table created by the downloader migrations.
+func NewFallbackIndexer(db *sql.DB, log *logger.Logger) *FallbackIndexer {
+	return &FallbackIndexer{
+		db:  db,
+		log: log,
+	}
+}
+
+// SetSignatureResolver sets the resolver used to log the event signatures of unmatched logs
+// instead of their raw topic0 when debug logging is enabled.
+func (f *FallbackIndexer) SetSignatureResolver(resolver SignatureResolver) {
+	f.signatures = resolver
+}
+
+// EventsToIndex returns an empty map, since the fallback indexer only receives
+// logs that were fetched for other indexers but not claimed by any of them.
+func (f *FallbackIndexer) EventsToIndex() map[common.Address]map[common.Hash]struct{} {
+	return map[common.Address]map[common.Hash]struct{}{}
+}
+
+// HandleLogs stores the given logs in the unmatched_logs table.
+// Logs that are already stored are ignored.
+func (f *FallbackIndexer) HandleLogs(logs []types.Log) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	tx, err := f.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			f.log.Errorf("failed to rollback transaction: %v", err)
+		}
+	}()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO unmatched_logs (
+			address, block_number, block_hash, tx_hash, tx_index, log_index,
+			topic0, topic1, topic2, topic3, data
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(address, block_number, tx_hash, log_index) DO NOTHING`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare unmatched log insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, log := range logs {
+		topics := make([]any, 4) //nolint:mnd // a log has at most 4 topics
+		for i, topic := range log.Topics {
+			if i >= len(topics) {
+				break
+			}
+			topics[i] = topic.Hex()
+		}
+
+		if _, err := stmt.Exec(
+			log.Address.Hex(), log.BlockNumber, log.BlockHash.Hex(), log.TxHash.Hex(), log.TxIndex, log.Index,
+			topics[0], topics[1], topics[2], topics[3], log.Data,
+		); err != nil {
+			return fmt.Errorf("failed to insert unmatched log at block %d: %w", log.BlockNumber, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	f.log.Debugf("stored %d unmatched logs", len(logs))
+	f.logEvents(logs)
+
+	return nil
+}
+
+// logEvents logs the resolved event signature of every unmatched log at debug level.
+// Nothing is resolved unless a signature resolver is set and debug logging is enabled.
+func (f *FallbackIndexer) logEvents(logs []types.Log) {
+	if f.signatures == nil || !f.log.DebugEnabled() {
+		return
+	}
+
+	for _, log := range logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+
+		f.log.Debugf("unmatched log: address=%s, block=%d, tx=%s, index=%d, event=%s",
+			log.Address.Hex(), log.BlockNumber, log.TxHash.Hex(), log.Index,
+			f.signatures.Name(context.Background(), log.Topics[0]))
+	}
+}
+
+// HandleReorg removes unmatched logs at or after the given block number.
+func (f *FallbackIndexer) HandleReorg(blockNum uint64) error {
+	if _, err := f.db.Exec("DELETE FROM unmatched_logs WHERE block_number >= ?", blockNum); err != nil {
+		return fmt.Errorf("failed to delete unmatched logs: %w", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the unmatched logs database is reachable by executing a trivial query.
+func (f *FallbackIndexer) Ping(ctx context.Context) error {
+	if _, err := f.db.ExecContext(ctx, "SELECT 1"); err != nil {
+		return fmt.Errorf("failed to query database: %w", err)
+	}
+
+	return nil
+}
+
+// StartBlock returns 0, since the fallback indexer accepts unmatched logs from any block.
+func (f *FallbackIndexer) StartBlock() uint64 {
+	return 0
+}
+
+// GetType returns the type identifier of the fallback indexer.
+func (f *FallbackIndexer) GetType() string {
+	return FallbackIndexerName
+}
+
+// GetName returns the name of the fallback indexer.
+func (f *FallbackIndexer) GetName() string {
+	return FallbackIndexerName
+}
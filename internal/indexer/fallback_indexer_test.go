@@ -0,0 +1,175 @@
+package indexer
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/internal/migrations"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupFallbackIndexer(t *testing.T) *FallbackIndexer {
+	t.Helper()
+
+	dbConfig := config.DatabaseConfig{Path: filepath.Join(t.TempDir(), "fallback_test.db")}
+	dbConfig.ApplyDefaults()
+
+	require.NoError(t, migrations.RunMigrations(dbConfig))
+
+	database, err := db.NewSQLiteDBFromConfig(dbConfig)
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	return NewFallbackIndexer(database, logger.NewNopLogger())
+}
+
+func countUnmatchedLogs(t *testing.T, f *FallbackIndexer) int {
+	t.Helper()
+
+	var count int
+	require.NoError(t, f.db.QueryRow("SELECT COUNT(*) FROM unmatched_logs").Scan(&count))
+
+	return count
+}
+
+func TestFallbackIndexer_HandleLogs(t *testing.T) {
+	t.Parallel()
+
+	f := setupFallbackIndexer(t)
+
+	addr := common.HexToAddress("0xc0ffee")
+	topics := []common.Hash{
+		common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03"), common.HexToHash("0x04"),
+	}
+	logs := []types.Log{
+		{
+			Address:     addr,
+			Topics:      topics,
+			Data:        []byte{0xde, 0xad, 0xbe, 0xef},
+			BlockNumber: 10,
+			BlockHash:   common.HexToHash("0xb10c"),
+			TxHash:      common.HexToHash("0x7a"),
+			TxIndex:     2,
+			Index:       5,
+		},
+		{
+			Address:     addr,
+			Topics:      topics[:1],
+			BlockNumber: 20,
+			TxHash:      common.HexToHash("0x7b"),
+		},
+	}
+
+	require.NoError(t, f.HandleLogs(logs))
+	// Re-processing the same range does not duplicate logs
+	require.NoError(t, f.HandleLogs(logs))
+	require.Equal(t, 2, countUnmatchedLogs(t, f))
+
+	var (
+		address, topic0, topic3 string
+		topic1                  *string
+		data                    []byte
+	)
+	require.NoError(t, f.db.QueryRow(
+		"SELECT address, topic0, topic1, topic3, data FROM unmatched_logs WHERE block_number = 10",
+	).Scan(&address, &topic0, &topic1, &topic3, &data))
+	require.Equal(t, addr.Hex(), address)
+	require.Equal(t, topics[0].Hex(), topic0)
+	require.NotNil(t, topic1)
+	require.Equal(t, topics[1].Hex(), *topic1)
+	require.Equal(t, topics[3].Hex(), topic3)
+	require.Equal(t, logs[0].Data, data)
+
+	require.NoError(t, f.db.QueryRow(
+		"SELECT topic1 FROM unmatched_logs WHERE block_number = 20",
+	).Scan(&topic1))
+	require.Nil(t, topic1)
+
+	require.NoError(t, f.HandleReorg(15))
+	require.Equal(t, 1, countUnmatchedLogs(t, f))
+}
+
+// recordingResolver is a SignatureResolver that records the topics it resolves.
+type recordingResolver struct {
+	resolved []common.Hash
+}
+
+func (r *recordingResolver) Name(_ context.Context, topic0 common.Hash) string {
+	r.resolved = append(r.resolved, topic0)
+	return topic0.Hex()
+}
+
+func TestFallbackIndexer_SignatureResolver(t *testing.T) {
+	t.Parallel()
+
+	f := setupFallbackIndexer(t)
+	resolver := &recordingResolver{}
+	f.SetSignatureResolver(resolver)
+
+	addr := common.HexToAddress("0xc0ffee")
+	logs := []types.Log{
+		{Address: addr, Topics: []common.Hash{common.HexToHash("0x01")}, BlockNumber: 1},
+		{Address: addr, BlockNumber: 2},
+	}
+
+	// Topics are not resolved unless debug logging is enabled
+	require.NoError(t, f.HandleLogs(logs))
+	require.Empty(t, resolver.resolved)
+
+	f.log = logger.NewComponentLogger("fallback-test", "debug", false)
+	require.NoError(t, f.HandleLogs(logs))
+	require.Equal(t, []common.Hash{common.HexToHash("0x01")}, resolver.resolved)
+}
+
+func TestIndexerCoordinator_FallbackReceivesUnclaimedLogs(t *testing.T) {
+	t.Parallel()
+
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
+	addr := common.HexToAddress("0x5555")
+	claimedTopic := common.HexToHash("0x6666")
+	unclaimedTopic := common.HexToHash("0x7777")
+	claimed := newTestLog(addr, claimedTopic, 1)
+	unclaimed := newTestLog(addr, unclaimedTopic, 1)
+	otherAddress := newTestLog(common.HexToAddress("0x8888"), claimedTopic, 2)
+
+	idx := mocks.NewIndexer(t)
+	idx.EXPECT().GetName().Return("testIndexer")
+	idx.EXPECT().GetType().Return("mock")
+	idx.EXPECT().StartBlock().Return(uint64(0))
+	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
+		addr: {claimedTopic: {}},
+	})
+	var handled []types.Log
+	idx.On("HandleLogs", mock.Anything).Return(nil).Run(captureHandledLogs(&handled))
+
+	fallback := mocks.NewIndexer(t)
+	fallback.EXPECT().GetName().Return(FallbackIndexerName)
+	fallback.EXPECT().GetType().Return("mock")
+	var unmatched []types.Log
+	fallback.On("HandleLogs", mock.Anything).Return(nil).Run(captureHandledLogs(&unmatched))
+
+	// The fallback is consulted last regardless of when it is set
+	coord.SetFallbackIndexer(fallback)
+	coord.RegisterIndexer(idx)
+
+	require.NoError(t, coord.HandleLogs(t.Context(), []types.Log{claimed, unclaimed, otherAddress}, 0, 2))
+	require.Equal(t, []types.Log{claimed}, handled)
+	require.Equal(t, []types.Log{unclaimed, otherAddress}, unmatched)
+
+	// The fallback is not listed as a registered indexer
+	require.Len(t, coord.ListAll(), 1)
+	require.Equal(t, []uint64{0}, coord.IndexerStartBlocks())
+
+	// Reorgs also roll back the fallback
+	idx.EXPECT().HandleReorg(uint64(1)).Return(nil).Once()
+	fallback.EXPECT().HandleReorg(uint64(1)).Return(nil).Once()
+	require.NoError(t, coord.HandleReorg(1))
+}
@@ -1,23 +1,40 @@
 package indexer
 
 import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
-	"runtime"
+	"maps"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
+	"github.com/goran-ethernal/ChainIndexor/internal/tracing"
 	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 )
 
-const goRoutineMultiplier = 2
+// defaultMaxConcurrency is the number of indexers handling logs concurrently, unless configured otherwise.
+const defaultMaxConcurrency = 4
+
+// LogsHandledFunc is called after an indexer has successfully handled a batch of logs.
+// It runs on the indexing path, so it must return quickly and must not call the coordinator.
+type LogsHandledFunc func(indexerName string, logs []types.Log)
 
 // IndexerCoordinator manages multiple indexers and routes events to them based on address and topics.
 type IndexerCoordinator struct {
-	mu sync.RWMutex
+	mu  sync.RWMutex
+	log *logger.Logger
 
 	// addressTopics maps address -> topic -> indexers for specific topic filters
 	addressTopics map[common.Address]map[common.Hash][]indexer.Indexer
@@ -30,15 +47,70 @@ type IndexerCoordinator struct {
 
 	// startBlocks maps each indexer to its start block
 	startBlocks map[indexer.Indexer]uint64
+
+	// fallback receives logs that no registered indexer claimed, if set
+	fallback indexer.Indexer
+
+	// confirmationBuffers maps indexers that delay log delivery to their confirmation buffer
+	confirmationBuffers map[indexer.Indexer]uint64
+
+	// pending holds log batches waiting for enough confirmations, per indexer, in arrival order
+	pending map[indexer.Indexer][]logBatch
+
+	// finalizedBlock is the latest finalized block, used to release pending log batches
+	finalizedBlock uint64
+
+	// coverageDB is the downloader database holding the coverage_stats table, if set
+	coverageDB *sql.DB
+
+	// onLogsHandled is notified of every batch of logs handled by a registered indexer, if set
+	onLogsHandled LogsHandledFunc
+
+	// metrics holds the metrics of every indexer logs are delivered to, including the fallback indexer
+	metrics map[indexer.Indexer]*metrics.IndexerMetrics
+
+	// hooks are notified of every batch of logs handled by a registered indexer, in registration order
+	hooks []namedHook
+
+	// maxConcurrency is the number of indexers handling logs concurrently
+	maxConcurrency int
+
+	// chainID labels the indexing metrics
+	chainID uint64
+}
+
+// namedHook is a post-process hook and the name it was registered under.
+type namedHook struct {
+	name string
+	hook indexer.PostProcessHook
+}
+
+// handledBatch is a batch of logs an indexer handled, to be passed to the post-process hooks.
+type handledBatch struct {
+	indexerName string
+	logBatch
+}
+
+// logBatch is a set of logs for a single indexer from one fetched block range.
+type logBatch struct {
+	fromBlock uint64
+	toBlock   uint64
+	logs      []types.Log
 }
 
 // NewIndexerCoordinator creates a new IndexerCoordinator.
-func NewIndexerCoordinator() *IndexerCoordinator {
+func NewIndexerCoordinator(log *logger.Logger) *IndexerCoordinator {
 	return &IndexerCoordinator{
+		log:              log,
 		indexers:         make([]indexer.Indexer, 0),
 		addressTopics:    make(map[common.Address]map[common.Hash][]indexer.Indexer),
 		addressAllTopics: make(map[common.Address][]indexer.Indexer),
 		startBlocks:      make(map[indexer.Indexer]uint64),
+
+		confirmationBuffers: make(map[indexer.Indexer]uint64),
+		pending:             make(map[indexer.Indexer][]logBatch),
+		metrics:             make(map[indexer.Indexer]*metrics.IndexerMetrics),
+		maxConcurrency:      defaultMaxConcurrency,
 	}
 }
 
@@ -50,30 +122,161 @@ func (ic *IndexerCoordinator) RegisterIndexer(idx indexer.Indexer) {
 	// Store the indexer's start block
 	ic.startBlocks[idx] = idx.StartBlock()
 
+	if buffered, ok := idx.(indexer.ConfirmationBuffered); ok && buffered.ConfirmationBuffer() > 0 {
+		ic.confirmationBuffers[idx] = buffered.ConfirmationBuffer()
+	}
+
 	addressTopics := idx.EventsToIndex()
 	for addr, topics := range addressTopics {
-		if len(topics) == 0 {
-			// Empty topic set means indexer wants ALL events from this address
-			ic.addressAllTopics[addr] = append(ic.addressAllTopics[addr], idx)
-		} else {
-			// Specific topics - build routing map
-			if _, exists := ic.addressTopics[addr]; !exists {
-				ic.addressTopics[addr] = make(map[common.Hash][]indexer.Indexer)
-			}
-			for topic := range topics {
-				ic.addressTopics[addr][topic] = append(ic.addressTopics[addr][topic], idx)
-			}
-		}
+		ic.routeLocked(idx, addr, topics)
 	}
 
 	ic.indexers = append(ic.indexers, idx)
 }
 
+// AddAddresses routes the logs of the given topics emitted by the given addresses to a registered
+// indexer, e.g. for contracts discovered while indexing.
+func (ic *IndexerCoordinator) AddAddresses(
+	idx indexer.Indexer,
+	addresses []common.Address,
+	topics map[common.Hash]struct{},
+) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	for _, addr := range addresses {
+		ic.routeLocked(idx, addr, topics)
+	}
+}
+
+// routeLocked routes the logs of the given topics emitted by the address to the indexer.
+func (ic *IndexerCoordinator) routeLocked(idx indexer.Indexer, addr common.Address, topics map[common.Hash]struct{}) {
+	if len(topics) == 0 {
+		// Empty topic set means indexer wants ALL events from this address
+		ic.addressAllTopics[addr] = append(ic.addressAllTopics[addr], idx)
+		return
+	}
+
+	// Specific topics - build routing map
+	if _, exists := ic.addressTopics[addr]; !exists {
+		ic.addressTopics[addr] = make(map[common.Hash][]indexer.Indexer)
+	}
+	for topic := range topics {
+		ic.addressTopics[addr][topic] = append(ic.addressTopics[addr][topic], idx)
+	}
+}
+
+// SetFallbackIndexer sets the indexer that receives logs not claimed by any registered indexer.
+// The fallback indexer has the lowest priority: it is kept apart from the registered indexers,
+// so it never affects routing, start blocks or API listings, and is rolled back last on reorgs.
+func (ic *IndexerCoordinator) SetFallbackIndexer(idx indexer.Indexer) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	ic.fallback = idx
+}
+
+// SetMaxConcurrency sets the number of indexers handling logs concurrently.
+// Values below 1 keep the default of 4.
+func (ic *IndexerCoordinator) SetMaxConcurrency(maxConcurrency int) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	if maxConcurrency < 1 {
+		maxConcurrency = defaultMaxConcurrency
+	}
+	ic.maxConcurrency = maxConcurrency
+}
+
+// SetChainID sets the chain ID that labels the indexing metrics. It must be set before indexing starts.
+func (ic *IndexerCoordinator) SetChainID(chainID uint64) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	ic.chainID = chainID
+}
+
+// SetLogsHandledHook sets the function notified after a registered indexer handles a batch of logs.
+// The fallback indexer does not trigger it. It must be set before indexing starts.
+func (ic *IndexerCoordinator) SetLogsHandledHook(fn LogsHandledFunc) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	ic.onLogsHandled = fn
+}
+
+// RegisterHook registers a hook notified after every batch of logs a registered indexer handles.
+// Hooks run sequentially in registration order once all indexers handled their logs.
+// Registering a hook under the name of another hook replaces it in place.
+func (ic *IndexerCoordinator) RegisterHook(name string, hook indexer.PostProcessHook) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	for i := range ic.hooks {
+		if ic.hooks[i].name == name {
+			ic.hooks[i].hook = hook
+			return
+		}
+	}
+
+	ic.hooks = append(ic.hooks, namedHook{name: name, hook: hook})
+}
+
+// DeregisterHook removes the hook registered under the given name, if any.
+func (ic *IndexerCoordinator) DeregisterHook(name string) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	ic.hooks = slices.DeleteFunc(ic.hooks, func(h namedHook) bool {
+		return h.name == name
+	})
+}
+
+// SetFinalizedBlock records the latest finalized block. Log batches buffered for indexers with
+// a confirmation buffer are released on the next HandleLogs call once the finalized block
+// exceeds the batch's last block plus the buffer. The finalized block never moves backwards.
+func (ic *IndexerCoordinator) SetFinalizedBlock(block uint64) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	ic.finalizedBlock = max(ic.finalizedBlock, block)
+}
+
 // HandleLogs processes a batch of logs and routes them to the appropriate indexers.
 // Each log is sent to indexers that registered interest in both its address AND topic.
-func (ic *IndexerCoordinator) HandleLogs(logs []types.Log, from, to uint64) error {
-	ic.mu.RLock()
-	defer ic.mu.RUnlock()
+// Logs that no indexer claimed are sent to the fallback indexer, if one is set.
+// Logs for indexers with a confirmation buffer are held in memory until they are confirmed,
+// so HandleLogs should also be called without logs as the finalized block advances.
+// Indexers handle their logs concurrently, up to the configured max concurrency. A failing indexer
+// does not stop the others, the errors of all failing indexers are returned joined together.
+func (ic *IndexerCoordinator) HandleLogs(ctx context.Context, logs []types.Log, from, to uint64) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "IndexerCoordinator.HandleLogs", trace.WithAttributes(
+		attribute.Int64("from_block", int64(from)),
+		attribute.Int64("to_block", int64(to)),
+		attribute.Int("logs", len(logs)),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	handled, hooks, err := ic.dispatchLogs(ctx, logs, from, to)
+	if err != nil {
+		return err
+	}
+
+	// Hooks run outside the lock, so a slow hook does not block readers of the coordinator
+	ic.runHooks(ctx, hooks, handled)
+
+	return nil
+}
+
+// dispatchLogs routes the logs to the indexers and returns the batches the registered indexers handled,
+// along with the hooks to notify of them.
+func (ic *IndexerCoordinator) dispatchLogs(
+	ctx context.Context,
+	logs []types.Log,
+	from, to uint64,
+) ([]handledBatch, []namedHook, error) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
 
 	// Group logs by indexer to avoid duplicate processing
 	indexerLogs := make(map[indexer.Indexer][]types.Log)
@@ -100,62 +303,216 @@ func (ic *IndexerCoordinator) HandleLogs(logs []types.Log, from, to uint64) erro
 			}
 		}
 
+		if len(interestedIndexers) == 0 && ic.fallback != nil {
+			interestedIndexers[ic.fallback] = struct{}{}
+		}
+
 		// Add this log to all interested indexers
 		for idx := range interestedIndexers {
 			indexerLogs[idx] = append(indexerLogs[idx], log)
 		}
 	}
 
+	// Buffer logs for indexers waiting for confirmations, deliver the rest right away
+	deliveries := make(map[indexer.Indexer][]logBatch, len(indexerLogs))
+	for idx, relevantLogs := range indexerLogs {
+		batch := logBatch{fromBlock: from, toBlock: to, logs: relevantLogs}
+		if _, buffered := ic.confirmationBuffers[idx]; buffered {
+			ic.pending[idx] = append(ic.pending[idx], batch)
+			continue
+		}
+
+		deliveries[idx] = []logBatch{batch}
+	}
+
+	ic.releaseConfirmedLocked(deliveries)
+
 	// Call HandleLogs for each indexer with their relevant logs concurrently
-	var g errgroup.Group
-	g.SetLimit(runtime.NumCPU() * goRoutineMultiplier) // limit concurrency
+	var (
+		g         errgroup.Group
+		resultsMu sync.Mutex
+		handled   []handledBatch
+		failed    = make(map[string]error)
+		reorged   atomic.Bool
+	)
+	g.SetLimit(ic.maxConcurrency)
+
+	for idx, batches := range deliveries {
+		// A reorg invalidates the logs of the chunk, so there is no point in delivering more of them
+		if reorged.Load() {
+			break
+		}
 
-	for idx, relevantLogs := range indexerLogs {
 		// Capture loop variables
 		indexer := idx
 		indexerName := indexer.GetName()
-		logs := relevantLogs
+		indexerMetrics := ic.indexerMetricsLocked(indexer, indexerName)
 
 		g.Go(func() error {
-			start := time.Now()
-			defer func() {
-				metrics.BlockProcessingTimeLog(indexerName, time.Since(start))
-			}()
-
-			// Filter logs based on the indexer's start block
-			startBlock := ic.startBlocks[indexer]
-			filteredLogs := make([]types.Log, 0, len(logs))
-			for _, log := range logs {
-				if log.BlockNumber >= startBlock {
-					filteredLogs = append(filteredLogs, log)
+			// Batches are delivered in order, so an indexer never sees an older block range after a newer one
+			for _, batch := range batches {
+				if reorged.Load() {
+					return nil
 				}
-			}
 
-			// Only call HandleLogs if there are logs to process
-			if len(filteredLogs) > 0 {
-				if err := indexer.HandleLogs(filteredLogs); err != nil {
-					return fmt.Errorf("indexer failed to handle logs: %w", err)
+				filteredLogs, err := ic.deliver(ctx, indexer, indexerName, indexerMetrics, batch)
+				if err != nil {
+					var reorgErr *reorg.ReorgDetectedError
+					if errors.As(err, &reorgErr) {
+						reorged.Store(true)
+					}
+
+					// Errors are collected rather than returned, so a failing indexer does not stop the others
+					resultsMu.Lock()
+					failed[indexerName] = err
+					resultsMu.Unlock()
+
+					return nil
 				}
-			}
 
-			logMetrics(indexerName, len(filteredLogs), start, from, to)
+				if len(filteredLogs) == 0 || indexer == ic.fallback || len(ic.hooks) == 0 {
+					continue
+				}
+
+				resultsMu.Lock()
+				handled = append(handled, handledBatch{
+					indexerName: indexerName,
+					logBatch:    logBatch{fromBlock: batch.fromBlock, toBlock: batch.toBlock, logs: filteredLogs},
+				})
+				resultsMu.Unlock()
+			}
 
 			return nil
 		})
 	}
 
-	if err := g.Wait(); err != nil {
-		return err
+	// The goroutines never return an error, the errors of the indexers are in failed
+	_ = g.Wait()
+
+	if len(failed) > 0 {
+		errs := make([]error, 0, len(failed))
+		for _, name := range slices.Sorted(maps.Keys(failed)) {
+			errs = append(errs, failed[name])
+		}
+
+		return nil, nil, errors.Join(errs...)
 	}
 
-	return nil
+	// Indexers are delivered concurrently, notify hooks in a deterministic order
+	slices.SortStableFunc(handled, func(a, b handledBatch) int {
+		return cmp.Or(cmp.Compare(a.indexerName, b.indexerName), cmp.Compare(a.fromBlock, b.fromBlock))
+	})
+
+	return handled, slices.Clone(ic.hooks), nil
+}
+
+// runHooks notifies every hook, in registration order, of every batch of logs the indexers handled.
+// Hook errors are logged and do not stop indexing.
+func (ic *IndexerCoordinator) runHooks(ctx context.Context, hooks []namedHook, handled []handledBatch) {
+	for _, batch := range handled {
+		for _, h := range hooks {
+			if err := h.hook.OnLogsProcessed(ctx, batch.indexerName, batch.logs, batch.fromBlock, batch.toBlock); err != nil {
+				ic.log.Warnf("Hook %s failed for indexer %s (blocks %d-%d): %v",
+					h.name, batch.indexerName, batch.fromBlock, batch.toBlock, err)
+			}
+		}
+	}
+}
+
+// releaseConfirmedLocked moves pending log batches that have enough confirmations into deliveries.
+// The caller must hold the write lock.
+func (ic *IndexerCoordinator) releaseConfirmedLocked(deliveries map[indexer.Indexer][]logBatch) {
+	for idx, batches := range ic.pending {
+		buffer := ic.confirmationBuffers[idx]
+
+		released := 0
+		for released < len(batches) && batches[released].toBlock+buffer < ic.finalizedBlock {
+			released++
+		}
+
+		if released == 0 {
+			continue
+		}
+
+		deliveries[idx] = append(deliveries[idx], batches[:released]...)
+		if released == len(batches) {
+			delete(ic.pending, idx)
+		} else {
+			ic.pending[idx] = batches[released:]
+		}
+	}
+}
+
+// indexerMetricsLocked returns the metrics of an indexer, creating them on its first delivery.
+// The caller must hold the write lock.
+func (ic *IndexerCoordinator) indexerMetricsLocked(idx indexer.Indexer, indexerName string) *metrics.IndexerMetrics {
+	indexerMetrics, exists := ic.metrics[idx]
+	if !exists {
+		indexerMetrics = metrics.ForIndexer(indexerName, idx.GetType())
+		ic.metrics[idx] = indexerMetrics
+	}
+
+	return indexerMetrics
+}
+
+// deliver filters a log batch by the indexer's start block and passes it to the indexer.
+func (ic *IndexerCoordinator) deliver(
+	ctx context.Context,
+	idx indexer.Indexer,
+	indexerName string,
+	indexerMetrics *metrics.IndexerMetrics,
+	batch logBatch,
+) (filteredLogs []types.Log, err error) {
+	_, span := tracing.Tracer().Start(ctx, "Indexer.HandleLogs", trace.WithAttributes(
+		tracing.AttrIndexer.String(indexerName),
+		attribute.Int64("from_block", int64(batch.fromBlock)),
+		attribute.Int64("to_block", int64(batch.toBlock)),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	start := time.Now()
+	defer func() {
+		metrics.BlockProcessingTimeLog(ic.chainID, indexerName, time.Since(start))
+	}()
+
+	// Filter logs based on the indexer's start block
+	startBlock := ic.startBlocks[idx]
+	filteredLogs = make([]types.Log, 0, len(batch.logs))
+	for _, log := range batch.logs {
+		if log.BlockNumber >= startBlock {
+			filteredLogs = append(filteredLogs, log)
+		}
+	}
+
+	// Only call HandleLogs if there are logs to process
+	if len(filteredLogs) > 0 {
+		handleStart := time.Now()
+		if err := idx.HandleLogs(filteredLogs); err != nil {
+			return nil, fmt.Errorf("indexer %s failed to handle logs: %w", indexerName, err)
+		}
+		indexerMetrics.HandleLogsDurationLog(time.Since(handleStart))
+		indexerMetrics.EventsProcessedAdd(len(filteredLogs))
+
+		if ic.onLogsHandled != nil && idx != ic.fallback {
+			ic.onLogsHandled(indexerName, filteredLogs)
+		}
+	}
+
+	indexerMetrics.LastProcessedBlockSet(batch.toBlock)
+	logMetrics(ic.chainID, indexerName, len(filteredLogs), start, batch.fromBlock, batch.toBlock)
+	span.SetAttributes(attribute.Int("logs", len(filteredLogs)))
+
+	return filteredLogs, nil
 }
 
 // HandleReorg notifies all registered indexers about a blockchain reorganization.
 // All indexers are called sequentially to roll back their state.
+// Buffered logs at or after the reorg block are discarded.
 func (ic *IndexerCoordinator) HandleReorg(blockNum uint64) error {
-	ic.mu.RLock()
-	defer ic.mu.RUnlock()
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	ic.discardPendingLocked(blockNum)
 
 	for _, indexer := range ic.indexers {
 		if err := indexer.HandleReorg(blockNum); err != nil {
@@ -163,9 +520,46 @@ func (ic *IndexerCoordinator) HandleReorg(blockNum uint64) error {
 		}
 	}
 
+	if ic.fallback != nil {
+		if err := ic.fallback.HandleReorg(blockNum); err != nil {
+			return fmt.Errorf("fallback indexer failed to handle reorg at block %d: %w", blockNum, err)
+		}
+	}
+
 	return nil
 }
 
+// discardPendingLocked drops buffered logs at or after blockNum.
+// The caller must hold the write lock.
+func (ic *IndexerCoordinator) discardPendingLocked(blockNum uint64) {
+	for idx, batches := range ic.pending {
+		kept := make([]logBatch, 0, len(batches))
+		for _, batch := range batches {
+			if batch.fromBlock >= blockNum {
+				continue
+			}
+
+			if batch.toBlock >= blockNum {
+				logs := make([]types.Log, 0, len(batch.logs))
+				for _, log := range batch.logs {
+					if log.BlockNumber < blockNum {
+						logs = append(logs, log)
+					}
+				}
+				batch = logBatch{fromBlock: batch.fromBlock, toBlock: blockNum - 1, logs: logs}
+			}
+
+			kept = append(kept, batch)
+		}
+
+		if len(kept) == 0 {
+			delete(ic.pending, idx)
+		} else {
+			ic.pending[idx] = kept
+		}
+	}
+}
+
 // IndexerStartBlocks returns a slice of start blocks for all registered indexers.
 func (ic *IndexerCoordinator) IndexerStartBlocks() []uint64 {
 	ic.mu.RLock()
@@ -192,6 +586,49 @@ func (ic *IndexerCoordinator) GetByName(name string) indexer.Indexer {
 	return nil
 }
 
+// SetCoverageDB sets the downloader database that GetCoverageStats reads from.
+func (ic *IndexerCoordinator) SetCoverageDB(db *sql.DB) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	ic.coverageDB = db
+}
+
+// GetCoverageStats returns the log coverage of every address the downloader has fetched logs for.
+// The stats are maintained by triggers on the log_coverage table, so reading them is cheap.
+func (ic *IndexerCoordinator) GetCoverageStats() ([]indexer.CoverageStat, error) {
+	ic.mu.RLock()
+	db := ic.coverageDB
+	ic.mu.RUnlock()
+
+	if db == nil {
+		return nil, errors.New("coverage database is not set")
+	}
+
+	rows, err := db.Query(`
+		SELECT address, total_covered_blocks, earliest_block, latest_block
+		FROM coverage_stats ORDER BY address`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query coverage stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]indexer.CoverageStat, 0)
+	for rows.Next() {
+		var stat indexer.CoverageStat
+		if err := rows.Scan(&stat.Address, &stat.TotalCoveredBlocks, &stat.EarliestBlock, &stat.LatestBlock); err != nil {
+			return nil, fmt.Errorf("failed to scan coverage stats: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate coverage stats: %w", err)
+	}
+
+	return stats, nil
+}
+
 // ListAll returns all registered indexers.
 func (ic *IndexerCoordinator) ListAll() []indexer.Indexer {
 	ic.mu.RLock()
@@ -204,16 +641,22 @@ func (ic *IndexerCoordinator) ListAll() []indexer.Indexer {
 }
 
 // logMetrics records metrics for the indexing operation.
-func logMetrics(indexer string, numOfLogsIndexed int, processingStart time.Time, fromBlock, toBlock uint64) {
+func logMetrics(
+	chainID uint64,
+	indexer string,
+	numOfLogsIndexed int,
+	processingStart time.Time,
+	fromBlock, toBlock uint64,
+) {
 	blocksProcessed := toBlock - fromBlock + 1
-	metrics.LogsIndexedInc(indexer, numOfLogsIndexed)
-	metrics.BlocksProcessedInc(indexer, blocksProcessed)
-	metrics.LastIndexedBlockInc(indexer, toBlock)
+	metrics.LogsIndexedInc(chainID, indexer, numOfLogsIndexed)
+	metrics.BlocksProcessedInc(chainID, indexer, blocksProcessed)
+	metrics.LastIndexedBlockInc(chainID, indexer, toBlock)
 
 	elapsed := time.Since(processingStart).Seconds()
 	if elapsed == 0 {
 		elapsed = 1 // prevent division by zero
 	}
 
-	metrics.IndexingRateLog(indexer, float64(blocksProcessed)/elapsed)
+	metrics.IndexingRateLog(chainID, indexer, float64(blocksProcessed)/elapsed)
 }
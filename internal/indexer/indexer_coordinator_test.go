@@ -1,12 +1,24 @@
 package indexer
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/internal/migrations"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
 	"github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -32,7 +44,7 @@ func captureHandledLogs(captured *[]types.Log) func(mock.Arguments) {
 func TestIndexerCoordinator_RegisterIndexer(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	addr := common.HexToAddress("0x1234")
 	topic := common.HexToHash("0xabcd")
 
@@ -51,13 +63,14 @@ func TestIndexerCoordinator_RegisterIndexer(t *testing.T) {
 func TestIndexerCoordinator_HandleLogsRoutesByAddressAndTopic(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	addr := common.HexToAddress("0xdeadbeef")
 	topic := common.HexToHash("0xfeedface")
 	logEntry := newTestLog(addr, topic, 1)
 
 	idx := mocks.NewIndexer(t)
 	idx.EXPECT().GetName().Return("testIndexer")
+	idx.EXPECT().GetType().Return("mock")
 	idx.EXPECT().StartBlock().Return(uint64(0))
 	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
 		addr: {topic: {}},
@@ -68,21 +81,54 @@ func TestIndexerCoordinator_HandleLogsRoutesByAddressAndTopic(t *testing.T) {
 
 	coord.RegisterIndexer(idx)
 
-	err := coord.HandleLogs([]types.Log{logEntry}, 0, 1)
+	err := coord.HandleLogs(t.Context(), []types.Log{logEntry}, 0, 1)
 	require.NoError(t, err)
 	assert.Equal(t, []types.Log{logEntry}, handled)
 }
 
+func TestIndexerCoordinator_HandleLogsRoutesAddedAddresses(t *testing.T) {
+	t.Parallel()
+
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
+	factory := common.HexToAddress("0xfac7")
+	pair := common.HexToAddress("0xbeef")
+	pairCreated := common.HexToHash("0x0d3648bd")
+	swap := common.HexToHash("0xd78ad95f")
+
+	idx := mocks.NewIndexer(t)
+	idx.EXPECT().GetName().Return("testIndexer")
+	idx.EXPECT().GetType().Return("mock")
+	idx.EXPECT().StartBlock().Return(uint64(0))
+	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
+		factory: {pairCreated: {}},
+	})
+
+	var handled []types.Log
+	idx.On("HandleLogs", mock.Anything).Return(nil).Run(captureHandledLogs(&handled))
+
+	coord.RegisterIndexer(idx)
+	coord.AddAddresses(idx, []common.Address{pair}, map[common.Hash]struct{}{swap: {}})
+
+	logs := []types.Log{
+		newTestLog(factory, pairCreated, 1),
+		newTestLog(pair, swap, 2),
+		newTestLog(pair, pairCreated, 2),
+	}
+	require.NoError(t, coord.HandleLogs(t.Context(), logs, 1, 2))
+	require.Equal(t, logs[:2], handled)
+}
+
 func TestIndexerCoordinator_HandleLogsIgnoresLogsBeforeStartBlock(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	addr := common.HexToAddress("0xbeefdead")
 	topic := common.HexToHash("0xcafebabe")
 	logEntry := newTestLog(addr, topic, 5)
 
 	idx := mocks.NewIndexer(t)
 	idx.EXPECT().GetName().Return("testIndexer")
+	idx.EXPECT().GetType().Return("mock")
 	idx.EXPECT().StartBlock().Return(uint64(10))
 	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
 		addr: {topic: {}},
@@ -90,7 +136,7 @@ func TestIndexerCoordinator_HandleLogsIgnoresLogsBeforeStartBlock(t *testing.T)
 
 	coord.RegisterIndexer(idx)
 
-	err := coord.HandleLogs([]types.Log{logEntry}, 0, 5)
+	err := coord.HandleLogs(t.Context(), []types.Log{logEntry}, 0, 5)
 	require.NoError(t, err)
 	idx.AssertNotCalled(t, "HandleLogs", mock.Anything)
 }
@@ -98,13 +144,14 @@ func TestIndexerCoordinator_HandleLogsIgnoresLogsBeforeStartBlock(t *testing.T)
 func TestIndexerCoordinator_HandleLogsFiltersLogsAtExactStartBlock(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	addr := common.HexToAddress("0xaabbccdd")
 	topic := common.HexToHash("0x11223344")
 	logEntry := newTestLog(addr, topic, 10)
 
 	idx := mocks.NewIndexer(t)
 	idx.EXPECT().GetName().Return("testIndexer")
+	idx.EXPECT().GetType().Return("mock")
 	idx.EXPECT().StartBlock().Return(uint64(10))
 	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
 		addr: {topic: {}},
@@ -119,7 +166,7 @@ func TestIndexerCoordinator_HandleLogsFiltersLogsAtExactStartBlock(t *testing.T)
 
 	coord.RegisterIndexer(idx)
 
-	err := coord.HandleLogs([]types.Log{logEntry}, 0, 10)
+	err := coord.HandleLogs(t.Context(), []types.Log{logEntry}, 0, 10)
 	require.NoError(t, err)
 	assert.Equal(t, []types.Log{logEntry}, handled)
 }
@@ -127,13 +174,14 @@ func TestIndexerCoordinator_HandleLogsFiltersLogsAtExactStartBlock(t *testing.T)
 func TestIndexerCoordinator_HandleLogsSupportsAllTopics(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	addr := common.HexToAddress("0xabcdef12")
 	topic := common.HexToHash("0x12345678")
 	logEntry := newTestLog(addr, topic, 1)
 
 	idx := mocks.NewIndexer(t)
 	idx.EXPECT().GetName().Return("testIndexer")
+	idx.EXPECT().GetType().Return("mock")
 	idx.EXPECT().StartBlock().Return(uint64(0))
 	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
 		addr: {}, // Empty topic set means all topics
@@ -148,7 +196,7 @@ func TestIndexerCoordinator_HandleLogsSupportsAllTopics(t *testing.T) {
 
 	coord.RegisterIndexer(idx)
 
-	err := coord.HandleLogs([]types.Log{logEntry}, 0, 1)
+	err := coord.HandleLogs(t.Context(), []types.Log{logEntry}, 0, 1)
 	require.NoError(t, err)
 	assert.Equal(t, []types.Log{logEntry}, handled)
 }
@@ -156,13 +204,14 @@ func TestIndexerCoordinator_HandleLogsSupportsAllTopics(t *testing.T) {
 func TestIndexerCoordinator_HandleLogsRoutesToMultipleIndexers(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	addr := common.HexToAddress("0x1111")
 	topic := common.HexToHash("0x2222")
 	logEntry := newTestLog(addr, topic, 1)
 
 	idx1 := mocks.NewIndexer(t)
 	idx1.EXPECT().GetName().Return("testIndexer1")
+	idx1.EXPECT().GetType().Return("mock")
 	idx1.EXPECT().StartBlock().Return(uint64(0))
 	idx1.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
 		addr: {topic: {}},
@@ -172,6 +221,7 @@ func TestIndexerCoordinator_HandleLogsRoutesToMultipleIndexers(t *testing.T) {
 
 	idx2 := mocks.NewIndexer(t)
 	idx2.EXPECT().GetName().Return("testIndexer2")
+	idx2.EXPECT().GetType().Return("mock")
 	idx2.EXPECT().StartBlock().Return(uint64(0))
 	idx2.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
 		addr: {topic: {}},
@@ -182,7 +232,7 @@ func TestIndexerCoordinator_HandleLogsRoutesToMultipleIndexers(t *testing.T) {
 	coord.RegisterIndexer(idx1)
 	coord.RegisterIndexer(idx2)
 
-	err := coord.HandleLogs([]types.Log{logEntry}, 0, 1)
+	err := coord.HandleLogs(t.Context(), []types.Log{logEntry}, 0, 1)
 	require.NoError(t, err)
 	assert.Equal(t, []types.Log{logEntry}, handled1)
 	assert.Equal(t, []types.Log{logEntry}, handled2)
@@ -191,7 +241,7 @@ func TestIndexerCoordinator_HandleLogsRoutesToMultipleIndexers(t *testing.T) {
 func TestIndexerCoordinator_HandleLogsIgnoresUnmatchedAddress(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	addr1 := common.HexToAddress("0x1111")
 	addr2 := common.HexToAddress("0x2222")
 	topic := common.HexToHash("0x3333")
@@ -205,7 +255,7 @@ func TestIndexerCoordinator_HandleLogsIgnoresUnmatchedAddress(t *testing.T) {
 
 	coord.RegisterIndexer(idx)
 
-	err := coord.HandleLogs([]types.Log{logEntry}, 0, 1)
+	err := coord.HandleLogs(t.Context(), []types.Log{logEntry}, 0, 1)
 	require.NoError(t, err)
 	idx.AssertNotCalled(t, "HandleLogs", mock.Anything)
 }
@@ -213,7 +263,7 @@ func TestIndexerCoordinator_HandleLogsIgnoresUnmatchedAddress(t *testing.T) {
 func TestIndexerCoordinator_HandleLogsIgnoresUnmatchedTopic(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	addr := common.HexToAddress("0x1111")
 	topic1 := common.HexToHash("0x2222")
 	topic2 := common.HexToHash("0x3333")
@@ -227,7 +277,7 @@ func TestIndexerCoordinator_HandleLogsIgnoresUnmatchedTopic(t *testing.T) {
 
 	coord.RegisterIndexer(idx)
 
-	err := coord.HandleLogs([]types.Log{logEntry}, 0, 1)
+	err := coord.HandleLogs(t.Context(), []types.Log{logEntry}, 0, 1)
 	require.NoError(t, err)
 	idx.AssertNotCalled(t, "HandleLogs", mock.Anything)
 }
@@ -235,13 +285,14 @@ func TestIndexerCoordinator_HandleLogsIgnoresUnmatchedTopic(t *testing.T) {
 func TestIndexerCoordinator_HandleLogsPropagatesErrors(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	addr := common.HexToAddress("0x11110000")
 	topic := common.HexToHash("0x22220000")
 	logEntry := newTestLog(addr, topic, 1)
 
 	idx := mocks.NewIndexer(t)
 	idx.EXPECT().GetName().Return("testIndexer")
+	idx.EXPECT().GetType().Return("mock")
 	idx.EXPECT().StartBlock().Return(uint64(0))
 	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
 		addr: {topic: {}},
@@ -251,7 +302,7 @@ func TestIndexerCoordinator_HandleLogsPropagatesErrors(t *testing.T) {
 
 	coord.RegisterIndexer(idx)
 
-	err := coord.HandleLogs([]types.Log{logEntry}, 0, 1)
+	err := coord.HandleLogs(t.Context(), []types.Log{logEntry}, 0, 1)
 	require.Error(t, err)
 	assert.ErrorContains(t, err, expectedErr.Error())
 }
@@ -259,7 +310,7 @@ func TestIndexerCoordinator_HandleLogsPropagatesErrors(t *testing.T) {
 func TestIndexerCoordinator_HandleLogsWithMultipleLogs(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	addr := common.HexToAddress("0x1234")
 	topic := common.HexToHash("0x5678")
 	log1 := newTestLog(addr, topic, 1)
@@ -268,6 +319,7 @@ func TestIndexerCoordinator_HandleLogsWithMultipleLogs(t *testing.T) {
 
 	idx := mocks.NewIndexer(t)
 	idx.EXPECT().GetName().Return("testIndexer")
+	idx.EXPECT().GetType().Return("mock")
 	idx.EXPECT().StartBlock().Return(uint64(0))
 	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
 		addr: {topic: {}},
@@ -278,7 +330,7 @@ func TestIndexerCoordinator_HandleLogsWithMultipleLogs(t *testing.T) {
 
 	coord.RegisterIndexer(idx)
 
-	err := coord.HandleLogs([]types.Log{log1, log2, log3}, 0, 3)
+	err := coord.HandleLogs(t.Context(), []types.Log{log1, log2, log3}, 0, 3)
 	require.NoError(t, err)
 	assert.Len(t, handled, 3)
 	assert.Contains(t, handled, log1)
@@ -289,7 +341,7 @@ func TestIndexerCoordinator_HandleLogsWithMultipleLogs(t *testing.T) {
 func TestIndexerCoordinator_HandleLogsWithEmptyLogList(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	addr := common.HexToAddress("0x1234")
 	topic := common.HexToHash("0x5678")
 
@@ -301,7 +353,7 @@ func TestIndexerCoordinator_HandleLogsWithEmptyLogList(t *testing.T) {
 
 	coord.RegisterIndexer(idx)
 
-	err := coord.HandleLogs([]types.Log{}, 0, 0)
+	err := coord.HandleLogs(t.Context(), []types.Log{}, 0, 0)
 	require.NoError(t, err)
 	idx.AssertNotCalled(t, "HandleLogs", mock.Anything)
 }
@@ -309,7 +361,7 @@ func TestIndexerCoordinator_HandleLogsWithEmptyLogList(t *testing.T) {
 func TestIndexerCoordinator_HandleReorgSuccess(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 
 	idx1 := mocks.NewIndexer(t)
 	idx1.EXPECT().StartBlock().Return(uint64(0))
@@ -334,7 +386,7 @@ func TestIndexerCoordinator_HandleReorgSuccess(t *testing.T) {
 func TestIndexerCoordinator_HandleReorgPropagatesErrors(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 
 	idx1 := mocks.NewIndexer(t)
 	idx1.EXPECT().StartBlock().Return(uint64(0))
@@ -359,7 +411,7 @@ func TestIndexerCoordinator_HandleReorgPropagatesErrors(t *testing.T) {
 func TestIndexerCoordinator_IndexerStartBlocks(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 
 	idx1 := mocks.NewIndexer(t)
 	idx1.EXPECT().StartBlock().Return(uint64(5))
@@ -383,14 +435,14 @@ func TestIndexerCoordinator_IndexerStartBlocks(t *testing.T) {
 func TestIndexerCoordinator_IndexerStartBlocksEmpty(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	assert.Equal(t, []uint64{}, coord.IndexerStartBlocks())
 }
 
 func TestIndexerCoordinator_HandleLogsWithMixedStartBlocks(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	addr := common.HexToAddress("0x1234")
 	topic := common.HexToHash("0x5678")
 	log1 := newTestLog(addr, topic, 5)
@@ -400,6 +452,7 @@ func TestIndexerCoordinator_HandleLogsWithMixedStartBlocks(t *testing.T) {
 	// Indexer 1 starts at block 10
 	idx1 := mocks.NewIndexer(t)
 	idx1.EXPECT().GetName().Return("testIndexer1")
+	idx1.EXPECT().GetType().Return("mock")
 	idx1.EXPECT().StartBlock().Return(uint64(10))
 	idx1.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
 		addr: {topic: {}},
@@ -410,6 +463,7 @@ func TestIndexerCoordinator_HandleLogsWithMixedStartBlocks(t *testing.T) {
 	// Indexer 2 starts at block 20
 	idx2 := mocks.NewIndexer(t)
 	idx2.EXPECT().GetName().Return("testIndexer2")
+	idx2.EXPECT().GetType().Return("mock")
 	idx2.EXPECT().StartBlock().Return(uint64(20))
 	idx2.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
 		addr: {topic: {}},
@@ -420,7 +474,7 @@ func TestIndexerCoordinator_HandleLogsWithMixedStartBlocks(t *testing.T) {
 	coord.RegisterIndexer(idx1)
 	coord.RegisterIndexer(idx2)
 
-	err := coord.HandleLogs([]types.Log{log1, log2, log3}, 0, 30)
+	err := coord.HandleLogs(t.Context(), []types.Log{log1, log2, log3}, 0, 30)
 	require.NoError(t, err)
 
 	// idx1 should get logs from blocks 15 and 25
@@ -436,7 +490,7 @@ func TestIndexerCoordinator_HandleLogsWithMixedStartBlocks(t *testing.T) {
 func TestIndexerCoordinator_HandleLogsDeduplicatesLogPerIndexer(t *testing.T) {
 	t.Parallel()
 
-	coord := NewIndexerCoordinator()
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
 	addr := common.HexToAddress("0x1234")
 	topic1 := common.HexToHash("0x5678")
 	topic2 := common.HexToHash("0x9abc")
@@ -451,6 +505,7 @@ func TestIndexerCoordinator_HandleLogsDeduplicatesLogPerIndexer(t *testing.T) {
 	// Indexer interested in the same address with all topics
 	idx := mocks.NewIndexer(t)
 	idx.EXPECT().GetName().Return("testIndexer")
+	idx.EXPECT().GetType().Return("mock")
 	idx.EXPECT().StartBlock().Return(uint64(0))
 	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
 		addr: {}, // All topics
@@ -467,10 +522,442 @@ func TestIndexerCoordinator_HandleLogsDeduplicatesLogPerIndexer(t *testing.T) {
 
 	coord.RegisterIndexer(idx)
 
-	err := coord.HandleLogs([]types.Log{logEntry}, 0, 10)
+	err := coord.HandleLogs(t.Context(), []types.Log{logEntry}, 0, 10)
 	require.NoError(t, err)
 
 	// Should only be called once despite matching multiple criteria
 	assert.Equal(t, 1, callCount)
 	assert.Len(t, handled, 1)
 }
+
+// bufferedIndexer is a mock indexer with a confirmation buffer.
+type bufferedIndexer struct {
+	*mocks.Indexer
+	buffer uint64
+}
+
+func (b *bufferedIndexer) ConfirmationBuffer() uint64 {
+	return b.buffer
+}
+
+func newBufferedIndexer(t *testing.T, addr common.Address, topic common.Hash, buffer uint64) *bufferedIndexer {
+	t.Helper()
+
+	idx := &bufferedIndexer{Indexer: mocks.NewIndexer(t), buffer: buffer}
+	idx.EXPECT().StartBlock().Return(uint64(0))
+	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
+		addr: {topic: {}},
+	})
+
+	return idx
+}
+
+func TestIndexerCoordinator_HandleLogsNotifiesLogsHandledHook(t *testing.T) {
+	t.Parallel()
+
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
+	addr := common.HexToAddress("0xfade")
+	topic := common.HexToHash("0xbead")
+	early := newTestLog(addr, topic, 5)
+	logEntry := newTestLog(addr, topic, 15)
+	unclaimed := newTestLog(common.HexToAddress("0xdead"), topic, 15)
+
+	idx := mocks.NewIndexer(t)
+	idx.EXPECT().GetName().Return("hooked")
+	idx.EXPECT().GetType().Return("mock")
+	idx.EXPECT().StartBlock().Return(uint64(10))
+	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
+		addr: {topic: {}},
+	})
+	idx.EXPECT().HandleLogs(mock.Anything).Return(nil).Once()
+
+	fallback := mocks.NewIndexer(t)
+	fallback.EXPECT().GetName().Return("fallback")
+	fallback.EXPECT().GetType().Return("mock")
+	fallback.EXPECT().HandleLogs(mock.Anything).Return(nil).Once()
+
+	coord.RegisterIndexer(idx)
+	coord.SetFallbackIndexer(fallback)
+
+	notified := make(map[string][]types.Log)
+	coord.SetLogsHandledHook(func(indexerName string, logs []types.Log) {
+		notified[indexerName] = logs
+	})
+
+	require.NoError(t, coord.HandleLogs(t.Context(), []types.Log{early, logEntry, unclaimed}, 0, 20))
+
+	// Only the logs the indexer handled are reported, and the fallback indexer is not reported at all
+	require.Equal(t, map[string][]types.Log{"hooked": {logEntry}}, notified)
+
+	// Batches without logs for the indexer are not reported
+	clear(notified)
+	require.NoError(t, coord.HandleLogs(t.Context(), []types.Log{early}, 0, 9))
+	require.Empty(t, notified)
+}
+
+func TestIndexerCoordinator_HandleLogsRunsPostProcessHooks(t *testing.T) {
+	t.Parallel()
+
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
+	addr := common.HexToAddress("0xfeed")
+	topic := common.HexToHash("0xf00d")
+	logEntry := newTestLog(addr, topic, 15)
+
+	idx := mocks.NewIndexer(t)
+	idx.EXPECT().GetName().Return("hooked")
+	idx.EXPECT().GetType().Return("mock")
+	idx.EXPECT().StartBlock().Return(uint64(0))
+	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
+		addr: {topic: {}},
+	})
+	idx.EXPECT().HandleLogs(mock.Anything).Return(nil).Twice()
+
+	coord.RegisterIndexer(idx)
+
+	var calls []string
+	newHook := func(name string, err error) *mocks.PostProcessHook {
+		hook := mocks.NewPostProcessHook(t)
+		hook.EXPECT().OnLogsProcessed(mock.Anything, "hooked", []types.Log{logEntry}, uint64(10), uint64(20)).
+			Run(func(context.Context, string, []types.Log, uint64, uint64) { calls = append(calls, name) }).
+			Return(err)
+		return hook
+	}
+
+	// A failing hook does not fail indexing nor keep the following hooks from running
+	coord.RegisterHook("first", newHook("first", errors.New("webhook unreachable")))
+	coord.RegisterHook("second", newHook("second", nil))
+	coord.RegisterHook("third", newHook("third", nil))
+
+	require.NoError(t, coord.HandleLogs(t.Context(), []types.Log{logEntry}, 10, 20))
+	require.Equal(t, []string{"first", "second", "third"}, calls)
+
+	// Deregistered hooks are no longer called, replaced hooks keep their position
+	calls = nil
+	coord.DeregisterHook("second")
+	coord.RegisterHook("first", newHook("replaced", nil))
+
+	require.NoError(t, coord.HandleLogs(t.Context(), []types.Log{logEntry}, 10, 20))
+	require.Equal(t, []string{"replaced", "third"}, calls)
+}
+
+func TestIndexerCoordinator_HandleLogsSkipsHooksOnError(t *testing.T) {
+	t.Parallel()
+
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
+	addr := common.HexToAddress("0xfeed")
+	topic := common.HexToHash("0xf00d")
+
+	idx := mocks.NewIndexer(t)
+	idx.EXPECT().GetName().Return("failing")
+	idx.EXPECT().GetType().Return("mock")
+	idx.EXPECT().StartBlock().Return(uint64(0))
+	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
+		addr: {topic: {}},
+	})
+	idx.EXPECT().HandleLogs(mock.Anything).Return(errors.New("db locked")).Once()
+
+	coord.RegisterIndexer(idx)
+
+	// The mock fails the test if the hook is called
+	coord.RegisterHook("hook", mocks.NewPostProcessHook(t))
+
+	require.Error(t, coord.HandleLogs(t.Context(), []types.Log{newTestLog(addr, topic, 15)}, 10, 20))
+}
+
+// newHandlerIndexer creates a mock indexer of all events of addr that handles logs with handle.
+// It is not required to be called, so tests can register indexers that are never delivered to.
+func newHandlerIndexer(t *testing.T, name string, addr common.Address, handle func([]types.Log) error) *mocks.Indexer {
+	t.Helper()
+
+	idx := mocks.NewIndexer(t)
+	idx.EXPECT().GetName().Return(name).Maybe()
+	idx.EXPECT().GetType().Return("mock").Maybe()
+	idx.EXPECT().StartBlock().Return(uint64(0))
+	idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{addr: {}})
+	idx.EXPECT().HandleLogs(mock.Anything).RunAndReturn(handle).Maybe()
+
+	return idx
+}
+
+func TestIndexerCoordinator_HandleLogsBoundsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	const numIndexers = 4
+
+	tests := []struct {
+		name           string
+		maxConcurrency int
+		wantPeak       int32
+	}{
+		{name: "sequential", maxConcurrency: 1, wantPeak: 1},
+		{name: "bounded", maxConcurrency: 2, wantPeak: 2},
+		{name: "default", maxConcurrency: 0, wantPeak: defaultMaxConcurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			coord := NewIndexerCoordinator(logger.NewNopLogger())
+			coord.SetMaxConcurrency(tt.maxConcurrency)
+			addr := common.HexToAddress("0xc0c0")
+
+			var (
+				inFlight, peak atomic.Int32
+				full           = make(chan struct{})
+				fullOnce       sync.Once
+			)
+			handle := func([]types.Log) error {
+				running := inFlight.Add(1)
+				defer inFlight.Add(-1)
+
+				for current := peak.Load(); running > current && !peak.CompareAndSwap(current, running); {
+					current = peak.Load()
+				}
+				if running == tt.wantPeak {
+					fullOnce.Do(func() { close(full) })
+				}
+
+				// Every indexer waits until the limit is reached, which a sequential dispatch never does
+				select {
+				case <-full:
+					return nil
+				case <-time.After(5 * time.Second):
+					return errors.New("concurrency limit not reached")
+				}
+			}
+
+			for i := range numIndexers {
+				coord.RegisterIndexer(newHandlerIndexer(t, fmt.Sprintf("indexer%d", i), addr, handle))
+			}
+
+			require.NoError(t, coord.HandleLogs(t.Context(), []types.Log{newTestLog(addr, common.Hash{}, 1)}, 0, 1))
+			require.Equal(t, tt.wantPeak, peak.Load())
+		})
+	}
+}
+
+func TestIndexerCoordinator_HandleLogsAggregatesErrors(t *testing.T) {
+	t.Parallel()
+
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
+	addr := common.HexToAddress("0xe770")
+
+	dbLocked := errors.New("db locked")
+	diskFull := errors.New("disk full")
+	var handled atomic.Bool
+
+	coord.RegisterIndexer(newHandlerIndexer(t, "locked", addr, func([]types.Log) error { return dbLocked }))
+	coord.RegisterIndexer(newHandlerIndexer(t, "healthy", addr, func([]types.Log) error {
+		handled.Store(true)
+		return nil
+	}))
+	coord.RegisterIndexer(newHandlerIndexer(t, "full", addr, func([]types.Log) error { return diskFull }))
+
+	err := coord.HandleLogs(t.Context(), []types.Log{newTestLog(addr, common.Hash{}, 1)}, 0, 1)
+
+	// A failing indexer does not keep the others from handling their logs
+	require.ErrorIs(t, err, dbLocked)
+	require.ErrorIs(t, err, diskFull)
+	require.Equal(t, "indexer full failed to handle logs: disk full\n"+
+		"indexer locked failed to handle logs: db locked", err.Error())
+	require.True(t, handled.Load())
+}
+
+func TestIndexerCoordinator_HandleLogsStopsDispatchOnReorg(t *testing.T) {
+	t.Parallel()
+
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
+	coord.SetMaxConcurrency(1)
+	addr := common.HexToAddress("0x4e06")
+
+	var calls atomic.Int32
+	handle := func([]types.Log) error {
+		calls.Add(1)
+		return reorg.NewReorgError(1, "block hash mismatch")
+	}
+	for i := range 3 {
+		coord.RegisterIndexer(newHandlerIndexer(t, fmt.Sprintf("indexer%d", i), addr, handle))
+	}
+
+	err := coord.HandleLogs(t.Context(), []types.Log{newTestLog(addr, common.Hash{}, 1)}, 0, 1)
+
+	var reorgErr *reorg.ReorgDetectedError
+	require.ErrorAs(t, err, &reorgErr)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestIndexerCoordinator_HandleLogsSlowIndexerDoesNotBlockFastOne(t *testing.T) {
+	t.Parallel()
+
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
+	addr := common.HexToAddress("0x5105")
+
+	release := make(chan struct{})
+	fastDone := make(chan struct{})
+
+	coord.RegisterIndexer(newHandlerIndexer(t, "slow", addr, func([]types.Log) error {
+		<-release
+		return nil
+	}))
+	coord.RegisterIndexer(newHandlerIndexer(t, "fast", addr, func([]types.Log) error {
+		close(fastDone)
+		return nil
+	}))
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- coord.HandleLogs(t.Context(), []types.Log{newTestLog(addr, common.Hash{}, 1)}, 0, 1)
+	}()
+
+	// The fast indexer finishes while the slow one is still handling its logs
+	select {
+	case <-fastDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fast indexer was blocked by the slow one")
+	}
+
+	// HandleLogs returns only once every indexer is done
+	select {
+	case <-errCh:
+		t.Fatal("HandleLogs returned before the slow indexer finished")
+	default:
+	}
+
+	close(release)
+	require.NoError(t, <-errCh)
+}
+
+func TestIndexerCoordinator_HandleLogsDelaysDeliveryByConfirmationBuffer(t *testing.T) {
+	t.Parallel()
+
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
+	addr := common.HexToAddress("0xc0ff")
+	topic := common.HexToHash("0xc0de")
+	logEntry := newTestLog(addr, topic, 95)
+
+	buffered := newBufferedIndexer(t, addr, topic, 10)
+	buffered.EXPECT().GetName().Return("buffered")
+	buffered.EXPECT().GetType().Return("mock")
+	var bufferedHandled []types.Log
+	buffered.On("HandleLogs", mock.Anything).Return(nil).Run(captureHandledLogs(&bufferedHandled)).Once()
+
+	immediate := mocks.NewIndexer(t)
+	immediate.EXPECT().GetName().Return("immediate")
+	immediate.EXPECT().GetType().Return("mock")
+	immediate.EXPECT().StartBlock().Return(uint64(0))
+	immediate.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{
+		addr: {topic: {}},
+	})
+	var immediateHandled []types.Log
+	immediate.On("HandleLogs", mock.Anything).Return(nil).Run(captureHandledLogs(&immediateHandled)).Once()
+
+	coord.RegisterIndexer(buffered)
+	coord.RegisterIndexer(immediate)
+
+	// Indexers without a buffer receive logs right away
+	coord.SetFinalizedBlock(100)
+	require.NoError(t, coord.HandleLogs(t.Context(), []types.Log{logEntry}, 91, 100))
+	require.Equal(t, []types.Log{logEntry}, immediateHandled)
+	require.Empty(t, bufferedHandled)
+
+	// The finalized block must exceed the batch's last block plus the buffer
+	coord.SetFinalizedBlock(110)
+	require.NoError(t, coord.HandleLogs(t.Context(), nil, 101, 110))
+	require.Empty(t, bufferedHandled)
+
+	// A lower finalized block does not move the buffer backwards
+	coord.SetFinalizedBlock(50)
+	coord.SetFinalizedBlock(111)
+	require.NoError(t, coord.HandleLogs(t.Context(), nil, 111, 111))
+	require.Equal(t, []types.Log{logEntry}, bufferedHandled)
+
+	// Released logs are delivered only once
+	require.NoError(t, coord.HandleLogs(t.Context(), nil, 112, 112))
+}
+
+func TestIndexerCoordinator_HandleReorgDiscardsBufferedLogs(t *testing.T) {
+	t.Parallel()
+
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
+	addr := common.HexToAddress("0xb0ff")
+	topic := common.HexToHash("0xe0e0")
+	kept := newTestLog(addr, topic, 10)
+	reorged := newTestLog(addr, topic, 15)
+	laterBatch := newTestLog(addr, topic, 25)
+
+	buffered := newBufferedIndexer(t, addr, topic, 100)
+	buffered.EXPECT().HandleReorg(uint64(12)).Return(nil)
+	buffered.EXPECT().GetName().Return("buffered")
+	buffered.EXPECT().GetType().Return("mock")
+	var handled []types.Log
+	buffered.On("HandleLogs", mock.Anything).Return(nil).Run(captureHandledLogs(&handled)).Once()
+
+	coord.RegisterIndexer(buffered)
+
+	coord.SetFinalizedBlock(20)
+	require.NoError(t, coord.HandleLogs(t.Context(), []types.Log{kept, reorged}, 1, 20))
+	require.NoError(t, coord.HandleLogs(t.Context(), []types.Log{laterBatch}, 21, 30))
+	require.NoError(t, coord.HandleReorg(12))
+
+	coord.SetFinalizedBlock(1000)
+	require.NoError(t, coord.HandleLogs(t.Context(), nil, 31, 1000))
+	require.Equal(t, []types.Log{kept}, handled)
+}
+
+func TestIndexerCoordinator_GetCoverageStats(t *testing.T) {
+	t.Parallel()
+
+	coord := NewIndexerCoordinator(logger.NewNopLogger())
+	_, err := coord.GetCoverageStats()
+	require.ErrorContains(t, err, "coverage database is not set")
+
+	dbConfig := config.DatabaseConfig{Path: filepath.Join(t.TempDir(), "coverage_test.db")}
+	dbConfig.ApplyDefaults()
+	require.NoError(t, migrations.RunMigrations(dbConfig))
+
+	database, err := db.NewSQLiteDBFromConfig(dbConfig)
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	coord.SetCoverageDB(database)
+
+	addr1 := common.HexToAddress("0x1111").Hex()
+	addr2 := common.HexToAddress("0x2222").Hex()
+
+	exec := func(query string, args ...any) {
+		t.Helper()
+
+		_, err := database.Exec(query, args...)
+		require.NoError(t, err)
+	}
+	insertCoverage := func(address string, fromBlock, toBlock uint64) {
+		t.Helper()
+
+		exec("INSERT INTO log_coverage (address, from_block, to_block) VALUES (?, ?, ?)", address, fromBlock, toBlock)
+	}
+
+	// Overlapping and nested ranges are only counted once
+	insertCoverage(addr1, 0, 99)
+	insertCoverage(addr1, 50, 149)
+	insertCoverage(addr1, 60, 70)
+	insertCoverage(addr1, 200, 299)
+	insertCoverage(addr2, 100, 199)
+
+	stats, err := coord.GetCoverageStats()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []indexer.CoverageStat{
+		{Address: addr1, TotalCoveredBlocks: 250, EarliestBlock: 0, LatestBlock: 299},
+		{Address: addr2, TotalCoveredBlocks: 100, EarliestBlock: 100, LatestBlock: 199},
+	}, stats)
+
+	// Reorg truncation updates and deletes ranges
+	exec("UPDATE log_coverage SET to_block = 249 WHERE from_block < 250 AND to_block >= 250")
+	exec("DELETE FROM log_coverage WHERE address = ? AND from_block >= 100", addr2)
+
+	stats, err = coord.GetCoverageStats()
+	require.NoError(t, err)
+	require.Equal(t, []indexer.CoverageStat{
+		{Address: addr1, TotalCoveredBlocks: 200, EarliestBlock: 0, LatestBlock: 249},
+	}, stats)
+}
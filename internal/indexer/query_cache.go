@@ -0,0 +1,113 @@
+package indexer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"sync"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+// QueryCache caches the results of event queries in memory for a limited time, so queries repeated
+// by dashboards do not hit the database every time. Results are keyed by a hash of their query
+// parameters and dropped when the indexer stores events in the block range they cover.
+type QueryCache struct {
+	name        string
+	indexerType string
+	entries     *expirable.LRU[string, *cachedQuery]
+
+	mu sync.Mutex
+	// generation is incremented by every invalidation, so the results of queries that were
+	// running while events were stored are not cached
+	generation uint64
+}
+
+// cachedQuery is the result of an event query and the block range the query covers.
+type cachedQuery struct {
+	events    any
+	total     int
+	fromBlock uint64
+	toBlock   uint64
+}
+
+// NewQueryCache creates a query cache of the named indexer with the given settings.
+func NewQueryCache(name, indexerType string, cfg config.CacheConfig) *QueryCache {
+	return &QueryCache{
+		name:        name,
+		indexerType: indexerType,
+		entries:     expirable.NewLRU[string, *cachedQuery](cfg.MaxEntries, nil, cfg.TTL.Duration),
+	}
+}
+
+// Query returns the cached result of the query with the given parameters. On a miss,
+// it runs the query and caches its result. Failed queries are not cached.
+func (c *QueryCache) Query(qp indexer.QueryParams, query func() (any, int, error)) (any, int, error) {
+	key, err := queryCacheKey(qp)
+	if err != nil {
+		return query()
+	}
+
+	if cached, ok := c.entries.Get(key); ok {
+		metrics.ForIndexer(c.name, c.indexerType).QueryCacheHitInc()
+		return cached.events, cached.total, nil
+	}
+	metrics.ForIndexer(c.name, c.indexerType).QueryCacheMissInc()
+
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	events, total, err := query()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entry := &cachedQuery{events: events, total: total, fromBlock: 0, toBlock: math.MaxUint64}
+	if qp.FromBlock != nil {
+		entry.fromBlock = *qp.FromBlock
+	}
+	if qp.ToBlock != nil {
+		entry.toBlock = *qp.ToBlock
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.entries.Add(key, entry)
+	}
+	c.mu.Unlock()
+
+	return events, total, nil
+}
+
+// Invalidate drops the cached results of the queries covering any block of the given range.
+// Queries filtered by timestamp only are assumed to cover every block.
+func (c *QueryCache) Invalidate(fromBlock, toBlock uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	for _, key := range c.entries.Keys() {
+		entry, ok := c.entries.Peek(key)
+		if ok && entry.fromBlock <= toBlock && fromBlock <= entry.toBlock {
+			c.entries.Remove(key)
+		}
+	}
+}
+
+// queryCacheKey returns the cache key of the query with the given parameters.
+func queryCacheKey(qp indexer.QueryParams) (string, error) {
+	data, err := json.Marshal(qp)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+
+	return hex.EncodeToString(hash[:]), nil
+}
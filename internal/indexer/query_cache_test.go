@@ -0,0 +1,209 @@
+package indexer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+// queryCacheCount returns the value of the query cache counter of the named indexer.
+func queryCacheCount(t *testing.T, family, name string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, f := range families {
+		if f.GetName() != family {
+			continue
+		}
+
+		for _, metric := range f.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "indexer" && label.GetValue() == name {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	return 0
+}
+
+func TestQueryCache(t *testing.T) {
+	t.Parallel()
+
+	const name = "query-cache-test"
+	cache := NewQueryCache(name, "test", config.CacheConfig{
+		Enabled:    true,
+		TTL:        common.NewDuration(200 * time.Millisecond),
+		MaxEntries: 10,
+	})
+
+	calls := 0
+	query := func(events string) func() (any, int, error) {
+		return func() (any, int, error) {
+			calls++
+			return events, len(events), nil
+		}
+	}
+
+	qp := indexer.QueryParams{EventType: "transfer", Limit: 100}
+
+	events, total, err := cache.Query(qp, query("first"))
+	require.NoError(t, err)
+	require.Equal(t, "first", events)
+	require.Equal(t, 5, total)
+
+	// Within the TTL, the cached result is returned even though the query would return another one
+	events, total, err = cache.Query(qp, query("second"))
+	require.NoError(t, err)
+	require.Equal(t, "first", events)
+	require.Equal(t, 5, total)
+	require.Equal(t, 1, calls)
+
+	// Other parameters are another query
+	events, _, err = cache.Query(indexer.QueryParams{EventType: "transfer", Limit: 10}, query("other"))
+	require.NoError(t, err)
+	require.Equal(t, "other", events)
+	require.Equal(t, 2, calls)
+
+	// Once the TTL passed, the query runs again and its result replaces the cached one
+	require.Eventually(t, func() bool {
+		events, _, err := cache.Query(qp, query("second"))
+		require.NoError(t, err)
+		return events == "second"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	events, _, err = cache.Query(qp, query("third"))
+	require.NoError(t, err)
+	require.Equal(t, "second", events)
+
+	require.GreaterOrEqual(t, queryCacheCount(t, "chainindexor_indexer_query_cache_hits_total", name), 2.0)
+	require.GreaterOrEqual(t, queryCacheCount(t, "chainindexor_indexer_query_cache_misses_total", name), 3.0)
+
+	// Failed queries are not cached
+	failing := indexer.QueryParams{EventType: "approval"}
+	_, _, err = cache.Query(failing, func() (any, int, error) { return nil, 0, errors.New("query failed") })
+	require.Error(t, err)
+
+	events, _, err = cache.Query(failing, query("approvals"))
+	require.NoError(t, err)
+	require.Equal(t, "approvals", events)
+}
+
+func TestQueryCache_Invalidate(t *testing.T) {
+	t.Parallel()
+
+	cache := NewQueryCache("query-cache-invalidate-test", "test", config.CacheConfig{
+		Enabled:    true,
+		TTL:        common.NewDuration(time.Hour),
+		MaxEntries: 10,
+	})
+
+	block := func(n uint64) *uint64 { return &n }
+	queries := map[string]indexer.QueryParams{
+		"unbounded": {EventType: "transfer"},
+		"up to 99":  {EventType: "transfer", ToBlock: block(99)},
+		"from 100":  {EventType: "transfer", FromBlock: block(100)},
+		"200-300":   {EventType: "transfer", FromBlock: block(200), ToBlock: block(300)},
+	}
+
+	cached := func(qp indexer.QueryParams) bool {
+		events, _, err := cache.Query(qp, func() (any, int, error) { return "fresh", 0, nil })
+		require.NoError(t, err)
+		return events == "cached"
+	}
+
+	for _, qp := range queries {
+		_, _, err := cache.Query(qp, func() (any, int, error) { return "cached", 0, nil })
+		require.NoError(t, err)
+	}
+
+	// Only the queries covering the stored blocks are dropped
+	cache.Invalidate(150, 160)
+	require.False(t, cached(queries["unbounded"]))
+	require.False(t, cached(queries["from 100"]))
+	require.True(t, cached(queries["up to 99"]))
+	require.True(t, cached(queries["200-300"]))
+
+	cache.Invalidate(300, 300)
+	require.False(t, cached(queries["200-300"]))
+	require.True(t, cached(queries["up to 99"]))
+}
+
+func TestQueryCache_NotCachedAfterConcurrentInvalidation(t *testing.T) {
+	t.Parallel()
+
+	cache := NewQueryCache("query-cache-concurrent-test", "test", config.CacheConfig{
+		Enabled:    true,
+		TTL:        common.NewDuration(time.Hour),
+		MaxEntries: 10,
+	})
+	qp := indexer.QueryParams{EventType: "transfer"}
+
+	// Events stored while the query runs may be missing from its result, so it is not cached
+	_, _, err := cache.Query(qp, func() (any, int, error) {
+		cache.Invalidate(1, 1)
+		return "outdated", 0, nil
+	})
+	require.NoError(t, err)
+
+	events, _, err := cache.Query(qp, func() (any, int, error) { return "fresh", 0, nil })
+	require.NoError(t, err)
+	require.Equal(t, "fresh", events)
+}
+
+func TestBaseIndexer_QueryCache(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bi := NewBaseIndexer(db, logger.NewNopLogger(), config.IndexerConfig{
+		Type:  "test",
+		Name:  "base-indexer-query-cache-test",
+		Cache: &config.CacheConfig{Enabled: true},
+	})
+
+	metadata := createTestMetadata(t)
+	metadata["transfer"].EventType = reflect.TypeOf((*testTransfer)(nil))
+	provider := &MockMetadataProvider{metadata: metadata}
+
+	insert := func(block uint64) {
+		_, err := db.Exec(`INSERT INTO transfers (block_number, tx_index, log_index, from_address, to_address, value)
+			VALUES (?, 0, 0, '0xaaa', '0xbbb', '1')`, block)
+		require.NoError(t, err)
+	}
+
+	qp := indexer.QueryParams{EventType: "transfer", Limit: 100}
+	count := func() int {
+		_, total, err := bi.QueryEvents(t.Context(), provider, qp)
+		require.NoError(t, err)
+		return total
+	}
+
+	insert(100)
+	require.Equal(t, 1, count())
+
+	// Events stored without invalidating the cache are not seen within the TTL
+	insert(101)
+	require.Equal(t, 1, count())
+
+	// Handled logs drop the cached results covering their blocks
+	bi.InvalidateQueryCache([]types.Log{{BlockNumber: 101}})
+	require.Equal(t, 2, count())
+
+	// So do reorgs
+	require.NoError(t, bi.HandleReorg(provider, 101))
+	require.Equal(t, 1, count())
+}
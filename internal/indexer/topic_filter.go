@@ -0,0 +1,193 @@
+package indexer
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/russross/meddler"
+)
+
+// indexedField is an indexed parameter of an event and the column it is stored in.
+type indexedField struct {
+	name         string
+	solidityType string
+	column       string
+	kind         reflect.Kind
+}
+
+// topicConditions returns the WHERE conditions and arguments filtering events of meta by the
+// topics of qp. Event tables do not store topics, so topic0 is checked against the signature of
+// the event, and topic1 to topic3 are compared with the columns of the indexed parameters.
+func topicConditions(meta *EventMetadata, qp indexer.QueryParams) ([]string, []interface{}, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	topics := qp.Topics()
+	if topics == [4]*common.Hash{} {
+		return nil, nil, nil
+	}
+
+	eventType := meta.EventType
+	if eventType.Kind() == reflect.Ptr {
+		eventType = eventType.Elem()
+	}
+
+	signature, fields, err := eventSignature(meta.Name, eventType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Every event of the table has the signature of the event type as topic0
+	if topics[0] != nil && *topics[0] != crypto.Keccak256Hash([]byte(signature)) {
+		conditions = append(conditions, "1 = 0")
+	}
+
+	for i, topic := range topics[1:] {
+		if topic == nil {
+			continue
+		}
+
+		if i >= len(fields) {
+			return nil, nil, fmt.Errorf("%w: %s events have no topic%d", indexer.ErrTopicFilterUnsupported, meta.Name, i+1)
+		}
+
+		field := fields[i]
+		value, err := topicValue(*topic, field)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: topic%d of %s events (%s %s) %w",
+				indexer.ErrTopicFilterUnsupported, i+1, meta.Name, field.solidityType, field.name, err)
+		}
+
+		if text, ok := value.(string); ok && (field.solidityType == "address" || field.solidityType == "bytes32") {
+			conditions = append(conditions, "LOWER("+field.column+") = ?")
+			args = append(args, strings.ToLower(text))
+		} else {
+			conditions = append(conditions, field.column+" = ?")
+			args = append(args, value)
+		}
+	}
+
+	return conditions, args, nil
+}
+
+// eventSignature returns the signature of an event, e.g. "Transfer(address,address,uint256)",
+// and its indexed parameters in declaration order, read from the abi tags of the event type.
+func eventSignature(name string, eventType reflect.Type) (string, []indexedField, error) {
+	columns, err := meddler.Columns(reflect.New(eventType).Interface(), true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		params  []abiParam
+		indexed []indexedField
+	)
+
+	for i := range eventType.NumField() {
+		field := eventType.Field(i)
+		tag, ok := field.Tag.Lookup("abi")
+		if !ok {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		if len(parts) < 2 { //nolint:mnd
+			continue
+		}
+		params = append(params, abiParam{path: strings.Split(parts[0], "."), solidityType: parts[1]})
+
+		if len(parts) > 2 && parts[2] == "indexed" { //nolint:mnd
+			column, _, _ := strings.Cut(field.Tag.Get("meddler"), ",")
+			if !slices.Contains(columns, column) {
+				return "", nil, fmt.Errorf("indexed parameter %s of %s events has no column", parts[0], name)
+			}
+
+			indexed = append(indexed, indexedField{
+				name:         parts[0],
+				solidityType: parts[1],
+				column:       column,
+				kind:         field.Type.Kind(),
+			})
+		}
+	}
+
+	return name + "(" + strings.Join(abiParamTypes(params, 0), ",") + ")", indexed, nil
+}
+
+// abiParam is a parameter read from an abi tag. Tuple parameters are flattened into a field per
+// tuple field, whose tag names the path to it, e.g. "order.maker".
+type abiParam struct {
+	path         []string
+	solidityType string
+}
+
+// abiParamTypes returns the types of the parameters at the given tuple depth, joining the consecutive
+// fields of the same tuple into the tuple type, e.g. "(address,uint256)".
+func abiParamTypes(params []abiParam, depth int) []string {
+	var types []string
+
+	for i := 0; i < len(params); {
+		if len(params[i].path) <= depth+1 {
+			types = append(types, params[i].solidityType)
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(params) && len(params[end].path) > depth+1 && params[end].path[depth] == params[i].path[depth] {
+			end++
+		}
+
+		types = append(types, "("+strings.Join(abiParamTypes(params[i:end], depth+1), ",")+")")
+		i = end
+	}
+
+	return types
+}
+
+// topicValue converts a topic to the value the indexed parameter is stored as.
+// Only addresses, bytes32 and integers are supported: indexed parameters of dynamic types
+// are stored in topics as hashes of their values, so they cannot be compared with the stored values.
+func topicValue(topic common.Hash, field indexedField) (interface{}, error) {
+	switch {
+	case field.solidityType == "address":
+		return common.BytesToAddress(topic.Bytes()).Hex(), nil
+	case field.solidityType == "bytes32":
+		return topic.Hex(), nil
+	case strings.HasPrefix(field.solidityType, "uint"), strings.HasPrefix(field.solidityType, "int"):
+		// Indexers store indexed integers as the unsigned value of their topic
+		return integerValue(topic.Big(), field.kind)
+	default:
+		return nil, errors.New("is not stored")
+	}
+}
+
+// integerValue converts an integer to the Go kind of the field it is stored in.
+// Integers of up to 64 bits are stored as numbers, larger ones as decimal strings.
+func integerValue(value *big.Int, kind reflect.Kind) (interface{}, error) {
+	switch kind {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Uint64, reflect.Uint, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		if !value.IsUint64() {
+			return nil, errors.New("is out of range")
+		}
+		return value.Uint64(), nil
+	case reflect.Int64, reflect.Int, reflect.Int32, reflect.Int16, reflect.Int8:
+		if !value.IsInt64() {
+			return nil, errors.New("is out of range")
+		}
+		return value.Int64(), nil
+	default:
+		return nil, fmt.Errorf("is stored as %s", kind)
+	}
+}
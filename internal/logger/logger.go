@@ -1,6 +1,8 @@
 package logger
 
 import (
+	"fmt"
+	"sync"
 	"sync/atomic"
 
 	"go.uber.org/zap"
@@ -17,6 +19,12 @@ var ValidLogLevels = map[string]struct{}{
 // root logger
 var log atomic.Pointer[Logger]
 
+// componentLevels holds the levels of all component loggers by component, so they can be changed at runtime
+var (
+	componentLevelsMu sync.Mutex
+	componentLevels   = make(map[string][]zap.AtomicLevel)
+)
+
 // LoggingConfig interface to avoid circular dependency with pkg/config.
 // Components will receive this interface instead of concrete config type.
 type LoggingConfig interface {
@@ -73,9 +81,38 @@ func NewComponentLogger(component string, level string, development bool) *Logge
 	if err != nil {
 		panic(err)
 	}
+
+	componentLevelsMu.Lock()
+	componentLevels[component] = append(componentLevels[component], logger.atomicLevel)
+	componentLevelsMu.Unlock()
+
 	return logger.WithComponent(component)
 }
 
+// SetComponentLevels changes the level of every component logger to the level the config sets for its component.
+func SetComponentLevels(cfg LoggingConfig) error {
+	componentLevelsMu.Lock()
+	defer componentLevelsMu.Unlock()
+
+	// Parse all levels first, so an invalid level changes none of them
+	zapLevels := make(map[string]zapcore.Level, len(componentLevels))
+	for component := range componentLevels {
+		zapLevel, err := zapcore.ParseLevel(cfg.GetComponentLevel(component))
+		if err != nil {
+			return fmt.Errorf("invalid log level for component %s: %w", component, err)
+		}
+		zapLevels[component] = zapLevel
+	}
+
+	for component, levels := range componentLevels {
+		for _, level := range levels {
+			level.SetLevel(zapLevels[component])
+		}
+	}
+
+	return nil
+}
+
 // NewComponentLoggerFromConfig creates a logger for a component using the provided logging config.
 // It uses the component-specific level if set, otherwise falls back to the default level.
 func NewComponentLoggerFromConfig(component string, cfg LoggingConfig) *Logger {
@@ -96,6 +133,15 @@ func NewNopLogger() *Logger {
 	}
 }
 
+// NewLoggerWithCore creates a logger writing to the given zap core, at debug level.
+// Useful for testing, e.g. with an observer core recording the logged entries.
+func NewLoggerWithCore(core zapcore.Core) *Logger {
+	return &Logger{
+		SugaredLogger: zap.New(core).Sugar(),
+		atomicLevel:   zap.NewAtomicLevelAt(zapcore.DebugLevel),
+	}
+}
+
 // WithComponent creates a child logger with a component name field.
 func (l *Logger) WithComponent(component string) *Logger {
 	return &Logger{
@@ -105,6 +151,25 @@ func (l *Logger) WithComponent(component string) *Logger {
 	}
 }
 
+// WithChainID creates a child logger with a chain ID field, for components of a multi-chain deployment.
+func (l *Logger) WithChainID(chainID uint64) *Logger {
+	return &Logger{
+		SugaredLogger: l.With("chain_id", chainID),
+		atomicLevel:   l.atomicLevel,
+		component:     l.component,
+	}
+}
+
+// WithCorrelationID creates a child logger with a correlation ID field, so the lines logged
+// while handling a request can be matched to it.
+func (l *Logger) WithCorrelationID(id string) *Logger {
+	return &Logger{
+		SugaredLogger: l.With("correlation_id", id),
+		atomicLevel:   l.atomicLevel,
+		component:     l.component,
+	}
+}
+
 // SetLevel changes the log level dynamically at runtime.
 func (l *Logger) SetLevel(level string) error {
 	zapLevel, err := zapcore.ParseLevel(level)
@@ -120,6 +185,11 @@ func (l *Logger) GetLevel() string {
 	return l.atomicLevel.Level().String()
 }
 
+// DebugEnabled reports whether debug messages are logged at the current log level.
+func (l *Logger) DebugEnabled() bool {
+	return l.atomicLevel.Enabled(zapcore.DebugLevel)
+}
+
 // GetComponent returns the component name if set.
 func (l *Logger) GetComponent() string {
 	return l.component
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestNewLogger(t *testing.T) {
@@ -135,6 +136,18 @@ func TestLogger_WithComponent(t *testing.T) {
 	require.Equal(t, "debug", componentLogger.GetLevel())
 }
 
+func TestLogger_WithChainID(t *testing.T) {
+	logger := NewComponentLogger("test-component", "info", false)
+
+	chainLogger := logger.WithChainID(137)
+	require.NotNil(t, chainLogger)
+	require.Equal(t, "test-component", chainLogger.GetComponent())
+
+	// Changing level on parent should affect child
+	require.NoError(t, logger.SetLevel("debug"))
+	require.Equal(t, "debug", chainLogger.GetLevel())
+}
+
 func TestNewComponentLogger(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -347,3 +360,43 @@ func TestLogger_MultipleComponents(t *testing.T) {
 	require.Equal(t, "debug", fetcher.GetLevel())
 	require.Equal(t, "debug", store.GetLevel())
 }
+
+func TestSetComponentLevels(t *testing.T) {
+	fetcherLog := NewComponentLogger("reload-fetcher", "info", false)
+	storeLog := NewComponentLogger("reload-store", "info", false)
+	chainLog := NewComponentLogger("reload-fetcher", "info", false).WithChainID(1)
+
+	err := SetComponentLevels(&mockLoggingConfig{
+		defaultLevel:    "warn",
+		componentLevels: map[string]string{"reload-fetcher": "debug"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "debug", fetcherLog.GetLevel())
+	require.Equal(t, "debug", chainLog.GetLevel())
+	require.Equal(t, "warn", storeLog.GetLevel())
+
+	err = SetComponentLevels(&mockLoggingConfig{
+		defaultLevel:    "info",
+		componentLevels: map[string]string{"reload-store": "verbose"},
+	})
+	require.ErrorContains(t, err, "invalid log level for component reload-store")
+	require.Equal(t, "debug", fetcherLog.GetLevel())
+}
+
+func TestLogger_WithCorrelationID(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := NewLoggerWithCore(core).WithComponent("api")
+
+	requestLogger := logger.WithCorrelationID("request-1")
+	require.Equal(t, "api", requestLogger.GetComponent())
+
+	requestLogger.Infof("handled %s", "request")
+	logger.Info("not a request")
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	require.Equal(t, "handled request", entries[0].Message)
+	require.Equal(t, "request-1", entries[0].ContextMap()["correlation_id"])
+	require.NotContains(t, entries[1].ContextMap(), "correlation_id")
+}
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	indexerEventsProcessed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "chainindexor_indexer_events_processed_total",
+			Help: "Total number of events handled by an indexer",
+		},
+		[]string{"chain_id", "indexer", "type"},
+	)
+
+	indexerLastProcessedBlock = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "chainindexor_indexer_last_processed_block",
+			Help: "The last block of the latest block range handled by an indexer",
+		},
+		[]string{"chain_id", "indexer"},
+	)
+
+	indexerHandleLogsDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "chainindexor_indexer_handle_logs_duration_seconds",
+			Help:    "Time an indexer takes to handle a batch of logs",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"chain_id", "indexer"},
+	)
+
+	indexerReorgsHandled = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "chainindexor_indexer_reorg_handled_total",
+			Help: "Total number of reorgs an indexer rolled back",
+		},
+		[]string{"chain_id", "indexer"},
+	)
+
+	indexerQueryCacheHits = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "chainindexor_indexer_query_cache_hits_total",
+			Help: "Total number of event queries of an indexer served from its query cache",
+		},
+		[]string{"chain_id", "indexer"},
+	)
+
+	indexerQueryCacheMisses = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "chainindexor_indexer_query_cache_misses_total",
+			Help: "Total number of event queries of an indexer not found in its query cache",
+		},
+		[]string{"chain_id", "indexer"},
+	)
+)
+
+var (
+	indexerChainsMu sync.RWMutex
+
+	// indexerChains maps indexer names to the ID of the chain they index
+	indexerChains = make(map[string]string)
+)
+
+// SetIndexerChainID sets the ID of the chain the named indexer indexes, which labels its metrics.
+// It must be called before the indexer's metrics are first used.
+func SetIndexerChainID(name string, chainID uint64) {
+	indexerChainsMu.Lock()
+	defer indexerChainsMu.Unlock()
+
+	indexerChains[name] = ChainLabel(chainID)
+}
+
+// IndexerMetrics holds the metrics of a single indexer, labelled with its chain ID, name and type.
+type IndexerMetrics struct {
+	eventsProcessed    prometheus.Counter
+	lastProcessedBlock prometheus.Gauge
+	handleLogsDuration prometheus.Observer
+	reorgsHandled      prometheus.Counter
+	queryCacheHits     prometheus.Counter
+	queryCacheMisses   prometheus.Counter
+}
+
+// ForIndexer returns the metrics of the indexer with the given name and type.
+// Indexers with the same name share their metrics. The chain ID label is empty
+// unless set with SetIndexerChainID.
+func ForIndexer(name, indexerType string) *IndexerMetrics {
+	indexerChainsMu.RLock()
+	chainID := indexerChains[name]
+	indexerChainsMu.RUnlock()
+
+	return &IndexerMetrics{
+		eventsProcessed:    indexerEventsProcessed.WithLabelValues(chainID, name, indexerType),
+		lastProcessedBlock: indexerLastProcessedBlock.WithLabelValues(chainID, name),
+		handleLogsDuration: indexerHandleLogsDuration.WithLabelValues(chainID, name),
+		reorgsHandled:      indexerReorgsHandled.WithLabelValues(chainID, name),
+		queryCacheHits:     indexerQueryCacheHits.WithLabelValues(chainID, name),
+		queryCacheMisses:   indexerQueryCacheMisses.WithLabelValues(chainID, name),
+	}
+}
+
+// EventsProcessedAdd records events handled by the indexer.
+func (m *IndexerMetrics) EventsProcessedAdd(count int) {
+	m.eventsProcessed.Add(float64(count))
+}
+
+// LastProcessedBlockSet records the last block of the block range the indexer handled.
+func (m *IndexerMetrics) LastProcessedBlockSet(blockNum uint64) {
+	m.lastProcessedBlock.Set(float64(blockNum))
+}
+
+// HandleLogsDurationLog records how long the indexer took to handle a batch of logs.
+func (m *IndexerMetrics) HandleLogsDurationLog(duration time.Duration) {
+	m.handleLogsDuration.Observe(duration.Seconds())
+}
+
+// ReorgHandledInc records a reorg rolled back by the indexer.
+func (m *IndexerMetrics) ReorgHandledInc() {
+	m.reorgsHandled.Inc()
+}
+
+// QueryCacheHitInc records an event query served from the query cache.
+func (m *IndexerMetrics) QueryCacheHitInc() {
+	m.queryCacheHits.Inc()
+}
+
+// QueryCacheMissInc records an event query not found in the query cache.
+func (m *IndexerMetrics) QueryCacheMissInc() {
+	m.queryCacheMisses.Inc()
+}
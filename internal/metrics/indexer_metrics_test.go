@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+// findMetric returns the metric of the named family in the default registry with the given labels.
+func findMetric(t *testing.T, family string, labels map[string]string) *dto.Metric {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, f := range families {
+		if f.GetName() != family {
+			continue
+		}
+
+		for _, metric := range f.GetMetric() {
+			if maps.Equal(metricLabels(metric), labels) {
+				return metric
+			}
+		}
+	}
+
+	require.Failf(t, "metric not found", "%s%v", family, labels)
+
+	return nil
+}
+
+func metricLabels(metric *dto.Metric) map[string]string {
+	labels := make(map[string]string, len(metric.GetLabel()))
+	for _, label := range metric.GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+
+	return labels
+}
+
+func TestForIndexer(t *testing.T) {
+	t.Parallel()
+
+	SetIndexerChainID("metrics-test-erc20", 1)
+
+	first := ForIndexer("metrics-test-erc20", "erc20")
+	second := ForIndexer("metrics-test-erc721", "erc721")
+
+	first.EventsProcessedAdd(3)
+	first.LastProcessedBlockSet(120)
+	first.HandleLogsDurationLog(50 * time.Millisecond)
+	first.ReorgHandledInc()
+
+	second.EventsProcessedAdd(7)
+	second.LastProcessedBlockSet(80)
+
+	// The chain ID label is empty for indexers without a chain ID
+	erc20 := map[string]string{"chain_id": "1", "indexer": "metrics-test-erc20"}
+	erc721 := map[string]string{"chain_id": "", "indexer": "metrics-test-erc721"}
+
+	require.InDelta(t, 3, findMetric(t, "chainindexor_indexer_events_processed_total",
+		map[string]string{"chain_id": "1", "indexer": "metrics-test-erc20", "type": "erc20"}).GetCounter().GetValue(), 0)
+	require.InDelta(t, 7, findMetric(t, "chainindexor_indexer_events_processed_total",
+		map[string]string{"chain_id": "", "indexer": "metrics-test-erc721", "type": "erc721"}).GetCounter().GetValue(), 0)
+
+	require.InDelta(t, 120, findMetric(t, "chainindexor_indexer_last_processed_block", erc20).GetGauge().GetValue(), 0)
+	require.InDelta(t, 80, findMetric(t, "chainindexor_indexer_last_processed_block", erc721).GetGauge().GetValue(), 0)
+
+	require.Equal(t, uint64(1), findMetric(t, "chainindexor_indexer_handle_logs_duration_seconds", erc20).
+		GetHistogram().GetSampleCount())
+	require.InDelta(t, 1, findMetric(t, "chainindexor_indexer_reorg_handled_total", erc20).GetCounter().GetValue(), 0)
+
+	// Indexers with the same name share their metrics
+	ForIndexer("metrics-test-erc20", "erc20").ReorgHandledInc()
+	require.InDelta(t, 2, findMetric(t, "chainindexor_indexer_reorg_handled_total", erc20).GetCounter().GetValue(), 0)
+}
+
+func TestLabelledGatherer(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_total", Help: "test"}, []string{"indexer"})
+	registry.MustRegister(counter)
+	counter.WithLabelValues("erc20").Inc()
+
+	gatherer := &labelledGatherer{
+		gatherer: registry,
+		labels:   map[string]string{"deployment": "mainnet", "indexer": "overridden"},
+	}
+
+	families, err := gatherer.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	require.Len(t, families[0].GetMetric(), 1)
+
+	// The metric's own label takes precedence, and labels stay sorted by name
+	labels := families[0].GetMetric()[0].GetLabel()
+	require.Len(t, labels, 2)
+	require.Equal(t, "deployment", labels[0].GetName())
+	require.Equal(t, "mainnet", labels[0].GetValue())
+	require.Equal(t, "indexer", labels[1].GetName())
+	require.Equal(t, "erc20", labels[1].GetValue())
+}
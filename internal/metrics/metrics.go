@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"runtime"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -15,7 +16,7 @@ var (
 			Name: "chainindexor_db_queries_total",
 			Help: "Total number of database queries",
 		},
-		[]string{"db", "operation"},
+		[]string{"chain_id", "db", "operation"},
 	)
 
 	dbQueryTime = promauto.NewHistogramVec(
@@ -24,7 +25,7 @@ var (
 			Help:    "Duration of database queries",
 			Buckets: prometheus.DefBuckets,
 		},
-		[]string{"db", "operation"},
+		[]string{"chain_id", "db", "operation"},
 	)
 
 	dbErrors = promauto.NewCounterVec(
@@ -32,7 +33,7 @@ var (
 			Name: "chainindexor_db_errors_total",
 			Help: "Total number of database errors",
 		},
-		[]string{"db", "error_type"},
+		[]string{"chain_id", "db", "error_type"},
 	)
 
 	// Indexing metrics
@@ -41,7 +42,7 @@ var (
 			Name: "chainindexor_last_indexed_block",
 			Help: "The last block number successfully indexed",
 		},
-		[]string{"indexer"},
+		[]string{"chain_id", "indexer"},
 	)
 
 	blocksProcessed = promauto.NewCounterVec(
@@ -49,7 +50,7 @@ var (
 			Name: "chainindexor_blocks_processed_total",
 			Help: "Total number of blocks processed",
 		},
-		[]string{"indexer"},
+		[]string{"chain_id", "indexer"},
 	)
 
 	logsIndexed = promauto.NewCounterVec(
@@ -57,7 +58,7 @@ var (
 			Name: "chainindexor_logs_indexed_total",
 			Help: "Total number of logs indexed",
 		},
-		[]string{"indexer"},
+		[]string{"chain_id", "indexer"},
 	)
 
 	blockProcessingTime = promauto.NewHistogramVec(
@@ -66,7 +67,7 @@ var (
 			Help:    "Time taken to process a batch of blocks",
 			Buckets: prometheus.DefBuckets,
 		},
-		[]string{"indexer"},
+		[]string{"chain_id", "indexer"},
 	)
 
 	indexingRate = promauto.NewGaugeVec(
@@ -74,7 +75,7 @@ var (
 			Name: "chainindexor_indexing_rate_blocks_per_second",
 			Help: "Current indexing rate in blocks per second",
 		},
-		[]string{"indexer"},
+		[]string{"chain_id", "indexer"},
 	)
 
 	// System metrics
@@ -90,7 +91,7 @@ var (
 			Name: "chainindexor_component_health",
 			Help: "Component health status (1=healthy, 0=unhealthy)",
 		},
-		[]string{"component"},
+		[]string{"chain_id", "component"},
 	)
 
 	goroutines = promauto.NewGauge(
@@ -111,45 +112,50 @@ var (
 	startTime = time.Now()
 )
 
-func DBQueryInc(db string, operation string) {
-	dbQueries.WithLabelValues(db, operation).Inc()
+// ChainLabel returns the value of the chain_id label of the metrics of a chain.
+func ChainLabel(chainID uint64) string {
+	return strconv.FormatUint(chainID, 10)
 }
 
-func DBQueryDuration(db string, operation string, duration time.Duration) {
-	dbQueryTime.WithLabelValues(db, operation).Observe(duration.Seconds())
+func DBQueryInc(chainID uint64, db string, operation string) {
+	dbQueries.WithLabelValues(ChainLabel(chainID), db, operation).Inc()
 }
 
-func DBErrorsInc(db string, errorType string) {
-	dbErrors.WithLabelValues(db, errorType).Inc()
+func DBQueryDuration(chainID uint64, db string, operation string, duration time.Duration) {
+	dbQueryTime.WithLabelValues(ChainLabel(chainID), db, operation).Observe(duration.Seconds())
 }
 
-func BlockProcessingTimeLog(indexer string, duration time.Duration) {
-	blockProcessingTime.WithLabelValues(indexer).Observe(duration.Seconds())
+func DBErrorsInc(chainID uint64, db string, errorType string) {
+	dbErrors.WithLabelValues(ChainLabel(chainID), db, errorType).Inc()
 }
 
-func LastIndexedBlockInc(indexer string, blockNum uint64) {
-	lastIndexedBlock.WithLabelValues(indexer).Set(float64(blockNum))
+func BlockProcessingTimeLog(chainID uint64, indexer string, duration time.Duration) {
+	blockProcessingTime.WithLabelValues(ChainLabel(chainID), indexer).Observe(duration.Seconds())
 }
 
-func BlocksProcessedInc(indexer string, count uint64) {
-	blocksProcessed.WithLabelValues(indexer).Add(float64(count))
+func LastIndexedBlockInc(chainID uint64, indexer string, blockNum uint64) {
+	lastIndexedBlock.WithLabelValues(ChainLabel(chainID), indexer).Set(float64(blockNum))
 }
 
-func LogsIndexedInc(indexer string, count int) {
-	logsIndexed.WithLabelValues(indexer).Add(float64(count))
+func BlocksProcessedInc(chainID uint64, indexer string, count uint64) {
+	blocksProcessed.WithLabelValues(ChainLabel(chainID), indexer).Add(float64(count))
 }
 
-func IndexingRateLog(indexer string, rate float64) {
-	indexingRate.WithLabelValues(indexer).Set(rate)
+func LogsIndexedInc(chainID uint64, indexer string, count int) {
+	logsIndexed.WithLabelValues(ChainLabel(chainID), indexer).Add(float64(count))
 }
 
-func ComponentHealthSet(component string, healthy bool) {
+func IndexingRateLog(chainID uint64, indexer string, rate float64) {
+	indexingRate.WithLabelValues(ChainLabel(chainID), indexer).Set(rate)
+}
+
+func ComponentHealthSet(chainID uint64, component string, healthy bool) {
 	boolAsFloat := float64(1)
 	if !healthy {
 		boolAsFloat = 0
 	}
 
-	componentHealth.WithLabelValues(component).Set(boolAsFloat)
+	componentHealth.WithLabelValues(ChainLabel(chainID), component).Set(boolAsFloat)
 }
 
 // UpdateSystemMetrics updates runtime system metrics.
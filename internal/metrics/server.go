@@ -3,23 +3,32 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
 )
 
 // Server is the HTTP server that exposes Prometheus metrics.
 type Server struct {
 	config *config.MetricsConfig
 	server *http.Server
+
+	// labels are added to every exposed metric that does not have a label of the same name
+	labels map[string]string
 }
 
 // NewServer creates a new metrics server.
 func NewServer(config *config.MetricsConfig) *Server {
 	return &Server{
 		config: config,
+		labels: maps.Clone(config.Labels),
 	}
 }
 
@@ -32,7 +41,7 @@ func (s *Server) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
 
 	// Register Prometheus metrics handler
-	mux.Handle(s.config.Path, promhttp.Handler())
+	mux.Handle(s.config.Path, s.metricsHandler())
 
 	// Add health check endpoint
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -76,6 +85,57 @@ func (s *Server) Stop(ctx context.Context) error {
 	return nil
 }
 
+// metricsHandler returns the handler exposing the metrics of the default registry,
+// with the server's labels added to every metric.
+func (s *Server) metricsHandler() http.Handler {
+	if len(s.labels) == 0 {
+		return promhttp.Handler()
+	}
+
+	return promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(&labelledGatherer{gatherer: prometheus.DefaultGatherer, labels: s.labels},
+			promhttp.HandlerOpts{}),
+	)
+}
+
+// labelledGatherer adds constant labels to the metrics of another gatherer.
+type labelledGatherer struct {
+	gatherer prometheus.Gatherer
+	labels   map[string]string
+}
+
+// Gather implements prometheus.Gatherer.
+func (g *labelledGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.gatherer.Gather()
+
+	names := slices.Sorted(maps.Keys(g.labels))
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, name := range names {
+				if !hasLabel(metric, name) {
+					value := g.labels[name]
+					metric.Label = append(metric.Label, &dto.LabelPair{Name: &name, Value: &value})
+				}
+			}
+
+			// The exposition format expects labels sorted by name
+			slices.SortFunc(metric.Label, func(a, b *dto.LabelPair) int {
+				return strings.Compare(a.GetName(), b.GetName())
+			})
+		}
+	}
+
+	return families, err
+}
+
+// hasLabel reports whether the metric has a label with the given name.
+func hasLabel(metric *dto.Metric, name string) bool {
+	return slices.ContainsFunc(metric.GetLabel(), func(label *dto.LabelPair) bool {
+		return label.GetName() == name
+	})
+}
+
 // updateSystemMetrics periodically updates system-level metrics.
 func (s *Server) updateSystemMetrics(ctx context.Context) {
 	ticker := time.NewTicker(15 * time.Second) //nolint:mnd
@@ -0,0 +1,28 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_unmatched_logs_block_number;
+DROP INDEX IF EXISTS idx_unmatched_logs_address_topic0;
+DROP TABLE IF EXISTS unmatched_logs;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS unmatched_logs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	address TEXT NOT NULL,
+	block_number INTEGER NOT NULL,
+	block_hash TEXT NOT NULL,
+	tx_hash TEXT NOT NULL,
+	tx_index INTEGER NOT NULL,
+	log_index INTEGER NOT NULL,
+	topic0 TEXT,
+	topic1 TEXT,
+	topic2 TEXT,
+	topic3 TEXT,
+	data BLOB,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+	-- Composite unique constraint to prevent duplicates
+	UNIQUE(address, block_number, tx_hash, log_index)
+);
+
+-- Indexes for finding unclaimed events of a contract and for reorg rollbacks
+CREATE INDEX IF NOT EXISTS idx_unmatched_logs_address_topic0 ON unmatched_logs(address, topic0);
+CREATE INDEX IF NOT EXISTS idx_unmatched_logs_block_number ON unmatched_logs(block_number);
@@ -0,0 +1,11 @@
+-- +migrate Down
+DROP TABLE IF EXISTS event_signatures;
+
+-- +migrate Up
+-- Cache of topic0 hashes resolved by the signature registry.
+-- An empty signature caches a topic0 the registry does not know
+CREATE TABLE IF NOT EXISTS event_signatures (
+	topic0 TEXT PRIMARY KEY,
+	signature TEXT NOT NULL,
+	fetched_at INTEGER NOT NULL
+);
@@ -0,0 +1,71 @@
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_log_coverage_stats_update;
+DROP TRIGGER IF EXISTS trg_log_coverage_stats_delete;
+DROP TRIGGER IF EXISTS trg_log_coverage_stats_insert;
+DROP TABLE IF EXISTS coverage_stats;
+
+-- +migrate Up
+-- Per address summary of log_coverage, kept up to date by the triggers below.
+-- Coverage ranges of an address can overlap, so total_covered_blocks is the size of the union
+-- of the ranges: ordered by from_block, each range only adds the blocks past the highest
+-- to_block of the ranges before it
+CREATE TABLE IF NOT EXISTS coverage_stats (
+	address TEXT PRIMARY KEY,
+	total_covered_blocks INTEGER NOT NULL,
+	earliest_block INTEGER NOT NULL,
+	latest_block INTEGER NOT NULL
+);
+
+INSERT OR REPLACE INTO coverage_stats (address, total_covered_blocks, earliest_block, latest_block)
+SELECT address, SUM(covered), MIN(from_block), MAX(to_block) FROM (
+	SELECT address, from_block, to_block,
+		MAX(0, to_block - MAX(from_block, COALESCE(MAX(to_block) OVER (
+			PARTITION BY address ORDER BY from_block, to_block
+			ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING) + 1, from_block)) + 1) AS covered
+	FROM log_coverage
+)
+GROUP BY address;
+
+CREATE TRIGGER IF NOT EXISTS trg_log_coverage_stats_insert AFTER INSERT ON log_coverage
+BEGIN
+	INSERT OR REPLACE INTO coverage_stats (address, total_covered_blocks, earliest_block, latest_block)
+	SELECT address, SUM(covered), MIN(from_block), MAX(to_block) FROM (
+		SELECT address, from_block, to_block,
+			MAX(0, to_block - MAX(from_block, COALESCE(MAX(to_block) OVER (
+				ORDER BY from_block, to_block
+				ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING) + 1, from_block)) + 1) AS covered
+		FROM log_coverage WHERE address = NEW.address
+	)
+	GROUP BY address;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_log_coverage_stats_delete AFTER DELETE ON log_coverage
+BEGIN
+	-- Removed if the last range of the address was deleted
+	DELETE FROM coverage_stats WHERE address = OLD.address;
+
+	INSERT INTO coverage_stats (address, total_covered_blocks, earliest_block, latest_block)
+	SELECT address, SUM(covered), MIN(from_block), MAX(to_block) FROM (
+		SELECT address, from_block, to_block,
+			MAX(0, to_block - MAX(from_block, COALESCE(MAX(to_block) OVER (
+				ORDER BY from_block, to_block
+				ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING) + 1, from_block)) + 1) AS covered
+		FROM log_coverage WHERE address = OLD.address
+	)
+	GROUP BY address;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_log_coverage_stats_update AFTER UPDATE ON log_coverage
+BEGIN
+	DELETE FROM coverage_stats WHERE address IN (OLD.address, NEW.address);
+
+	INSERT INTO coverage_stats (address, total_covered_blocks, earliest_block, latest_block)
+	SELECT address, SUM(covered), MIN(from_block), MAX(to_block) FROM (
+		SELECT address, from_block, to_block,
+			MAX(0, to_block - MAX(from_block, COALESCE(MAX(to_block) OVER (
+				PARTITION BY address ORDER BY from_block, to_block
+				ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING) + 1, from_block)) + 1) AS covered
+		FROM log_coverage WHERE address IN (OLD.address, NEW.address)
+	)
+	GROUP BY address;
+END;
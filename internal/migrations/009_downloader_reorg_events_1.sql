@@ -0,0 +1,15 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_reorg_events_first_reorg_block;
+DROP TABLE IF EXISTS reorg_events;
+
+-- +migrate Up
+-- Audit log of the reorgs detected by the reorg detector. detected_at is a unix timestamp in seconds
+CREATE TABLE IF NOT EXISTS reorg_events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	detected_at INTEGER NOT NULL,
+	first_reorg_block INTEGER NOT NULL,
+	depth INTEGER NOT NULL,
+	details TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_reorg_events_first_reorg_block ON reorg_events(first_reorg_block);
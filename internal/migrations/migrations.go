@@ -1,6 +1,7 @@
 package migrations
 
 import (
+	"database/sql"
 	_ "embed"
 
 	"github.com/goran-ethernal/ChainIndexor/internal/db"
@@ -16,8 +17,72 @@ var mig002 string
 //go:embed 003_downloader_reorg_detector_1.sql
 var mig003 string
 
-func RunMigrations(dbConfig config.DatabaseConfig) error {
-	migrations := []db.Migration{
+//go:embed 004_downloader_unmatched_logs_1.sql
+var mig004 string
+
+//go:embed 005_downloader_signature_cache_1.sql
+var mig005 string
+
+//go:embed 006_downloader_coverage_stats_1.sql
+var mig006 string
+
+//go:embed 007_downloader_log_timestamp_1.sql
+var mig007 string
+
+//go:embed 008_downloader_meta_1.sql
+var mig008 string
+
+//go:embed 009_downloader_reorg_events_1.sql
+var mig009 string
+
+//go:embed postgres/001_downloader_sync_manager_1.sql
+var pgMig001 string
+
+//go:embed postgres/002_downloader_log_store_1.sql
+var pgMig002 string
+
+//go:embed postgres/003_downloader_reorg_detector_1.sql
+var pgMig003 string
+
+//go:embed postgres/004_downloader_unmatched_logs_1.sql
+var pgMig004 string
+
+//go:embed postgres/005_downloader_signature_cache_1.sql
+var pgMig005 string
+
+//go:embed postgres/006_downloader_coverage_stats_1.sql
+var pgMig006 string
+
+//go:embed postgres/007_downloader_log_timestamp_1.sql
+var pgMig007 string
+
+//go:embed postgres/008_downloader_meta_1.sql
+var pgMig008 string
+
+//go:embed postgres/009_downloader_reorg_events_1.sql
+var pgMig009 string
+
+// downloaderMigrations returns the ordered list of downloader database migrations for the configured driver.
+func downloaderMigrations(dbConfig config.DatabaseConfig) []db.Migration {
+	if dbConfig.Driver == config.DBDriverPostgres {
+		return postgresDownloaderMigrations()
+	}
+
+	return sqliteDownloaderMigrations()
+}
+
+// databaseMigrations returns the ordered list of downloader database migrations for the driver of the database.
+func databaseMigrations(database *sql.DB) []db.Migration {
+	if db.IsPostgres(database) {
+		return postgresDownloaderMigrations()
+	}
+
+	return sqliteDownloaderMigrations()
+}
+
+// sqliteDownloaderMigrations returns the ordered list of SQLite downloader database migrations.
+func sqliteDownloaderMigrations() []db.Migration {
+	return []db.Migration{
 		{
 			ID:  "001_downloader_sync_manager_1.sql",
 			SQL: mig001,
@@ -30,7 +95,93 @@ func RunMigrations(dbConfig config.DatabaseConfig) error {
 			ID:  "003_downloader_reorg_detector_1.sql",
 			SQL: mig003,
 		},
+		{
+			ID:  "004_downloader_unmatched_logs_1.sql",
+			SQL: mig004,
+		},
+		{
+			ID:  "005_downloader_signature_cache_1.sql",
+			SQL: mig005,
+		},
+		{
+			ID:  "006_downloader_coverage_stats_1.sql",
+			SQL: mig006,
+		},
+		{
+			ID:  "007_downloader_log_timestamp_1.sql",
+			SQL: mig007,
+		},
+		{
+			ID:  "008_downloader_meta_1.sql",
+			SQL: mig008,
+		},
+		{
+			ID:  "009_downloader_reorg_events_1.sql",
+			SQL: mig009,
+		},
+	}
+}
+
+// postgresDownloaderMigrations returns the ordered list of PostgreSQL downloader database migrations.
+// They mirror the SQLite migrations, one to one.
+func postgresDownloaderMigrations() []db.Migration {
+	return []db.Migration{
+		{
+			ID:  "001_downloader_sync_manager_1.sql",
+			SQL: pgMig001,
+		},
+		{
+			ID:  "002_downloader_log_store_1.sql",
+			SQL: pgMig002,
+		},
+		{
+			ID:  "003_downloader_reorg_detector_1.sql",
+			SQL: pgMig003,
+		},
+		{
+			ID:  "004_downloader_unmatched_logs_1.sql",
+			SQL: pgMig004,
+		},
+		{
+			ID:  "005_downloader_signature_cache_1.sql",
+			SQL: pgMig005,
+		},
+		{
+			ID:  "006_downloader_coverage_stats_1.sql",
+			SQL: pgMig006,
+		},
+		{
+			ID:  "007_downloader_log_timestamp_1.sql",
+			SQL: pgMig007,
+		},
+		{
+			ID:  "008_downloader_meta_1.sql",
+			SQL: pgMig008,
+		},
+		{
+			ID:  "009_downloader_reorg_events_1.sql",
+			SQL: pgMig009,
+		},
 	}
+}
+
+func RunMigrations(dbConfig config.DatabaseConfig) error {
+	return db.RunMigrations(dbConfig, downloaderMigrations(dbConfig))
+}
+
+// RollbackMigrations reverts the last `steps` applied downloader migrations.
+// Intended for development, when iterating on schema changes.
+func RollbackMigrations(dbConfig config.DatabaseConfig, steps int) error {
+	return db.RollbackMigrations(dbConfig, downloaderMigrations(dbConfig), steps)
+}
+
+// Version returns the number of downloader migrations applied to the database.
+func Version(database *sql.DB) (int, error) {
+	return db.MigrationVersion(database, databaseMigrations(database))
+}
 
-	return db.RunMigrations(dbConfig, migrations)
+// RollbackTo reverts the downloader migrations newer than targetVersion in a single transaction.
+// Version 0 reverts every migration.
+func RollbackTo(database *sql.DB, targetVersion int) error {
+	return db.RollbackTo(database, databaseMigrations(database), targetVersion)
 }
@@ -0,0 +1,109 @@
+package migrations
+
+import (
+	"database/sql"
+	"path"
+	"testing"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func columnExists(t *testing.T, database *sql.DB, table, column string) bool {
+	t.Helper()
+
+	var count int
+	err := database.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	require.NoError(t, err)
+
+	return count > 0
+}
+
+func TestLogTimestampMigration(t *testing.T) {
+	t.Parallel()
+
+	dbConfig := config.DatabaseConfig{Path: path.Join(t.TempDir(), "migrations_test.db")}
+	dbConfig.ApplyDefaults()
+
+	// Roll back the log timestamp migration and the migrations after it
+	require.NoError(t, RunMigrations(dbConfig))
+	require.NoError(t, RollbackMigrations(dbConfig, 3))
+
+	database, err := db.NewSQLiteDBFromConfig(dbConfig)
+	require.NoError(t, err)
+	defer database.Close()
+
+	require.False(t, columnExists(t, database, "event_logs", "timestamp"))
+
+	// A log stored before the migration
+	_, err = database.Exec(`
+	INSERT INTO event_logs (address, block_number, block_hash, tx_hash, tx_index, log_index)
+	VALUES ('0x01', 100, '0x02', '0x03', 0, 0)`)
+	require.NoError(t, err)
+
+	require.NoError(t, RunMigrations(dbConfig))
+	require.True(t, columnExists(t, database, "event_logs", "timestamp"))
+
+	var timestamp uint64
+	require.NoError(t, database.QueryRow("SELECT timestamp FROM event_logs WHERE block_number = 100").Scan(&timestamp))
+	require.Zero(t, timestamp)
+
+	require.NoError(t, RollbackMigrations(dbConfig, 3))
+	require.False(t, columnExists(t, database, "event_logs", "timestamp"))
+}
+
+// schema returns the definitions of the tables, indexes, views and triggers of the database,
+// except the migrations table.
+func schema(t *testing.T, database *sql.DB) map[string]string {
+	t.Helper()
+
+	rows, err := database.Query(`
+	SELECT type, name, COALESCE(sql, '') FROM sqlite_master
+	WHERE name != 'gorp_migrations' AND name NOT LIKE 'sqlite_%'`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	definitions := make(map[string]string)
+	for rows.Next() {
+		var kind, name, definition string
+		require.NoError(t, rows.Scan(&kind, &name, &definition))
+		definitions[kind+" "+name] = definition
+	}
+	require.NoError(t, rows.Err())
+
+	return definitions
+}
+
+func TestRollbackTo(t *testing.T) {
+	t.Parallel()
+
+	for i, migration := range sqliteDownloaderMigrations() {
+		t.Run(migration.ID, func(t *testing.T) {
+			t.Parallel()
+
+			dbConfig := config.DatabaseConfig{Path: path.Join(t.TempDir(), "migrations_test.db")}
+			dbConfig.ApplyDefaults()
+
+			require.NoError(t, db.RunMigrations(dbConfig, sqliteDownloaderMigrations()[:i]))
+
+			database, err := db.NewSQLiteDBFromConfig(dbConfig)
+			require.NoError(t, err)
+			defer database.Close()
+
+			before := schema(t, database)
+
+			require.NoError(t, db.RunMigrations(dbConfig, sqliteDownloaderMigrations()[:i+1]))
+			version, err := Version(database)
+			require.NoError(t, err)
+			require.Equal(t, i+1, version)
+
+			require.NoError(t, RollbackTo(database, i))
+
+			version, err = Version(database)
+			require.NoError(t, err)
+			require.Equal(t, i, version)
+			require.Equal(t, before, schema(t, database))
+		})
+	}
+}
@@ -0,0 +1,16 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync_state;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS sync_state (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    last_indexed_block BIGINT NOT NULL DEFAULT 0,
+    last_indexed_block_hash TEXT NOT NULL DEFAULT '',
+    last_indexed_timestamp BIGINT NOT NULL,
+    mode TEXT NOT NULL
+);
+
+-- Insert initial state
+INSERT INTO sync_state (id, last_indexed_block, last_indexed_block_hash, last_indexed_timestamp, mode)
+VALUES (1, 0, '', 0, 'backfill')
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,13 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_block_hash;
+DROP TABLE IF EXISTS block_hashes;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS block_hashes (
+    block_number BIGINT PRIMARY KEY,
+    block_hash TEXT NOT NULL,
+    parent_hash TEXT NOT NULL,
+    created_at TEXT DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_block_hash ON block_hashes(block_hash);
@@ -0,0 +1,66 @@
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_log_coverage_stats ON log_coverage;
+DROP FUNCTION IF EXISTS log_coverage_stats_trigger();
+DROP FUNCTION IF EXISTS refresh_coverage_stats(TEXT);
+DROP TABLE IF EXISTS coverage_stats;
+
+-- +migrate Up
+-- Per address summary of log_coverage, kept up to date by the trigger below.
+-- Coverage ranges of an address can overlap, so total_covered_blocks is the size of the union
+-- of the ranges: ordered by from_block, each range only adds the blocks past the highest
+-- to_block of the ranges before it
+CREATE TABLE IF NOT EXISTS coverage_stats (
+	address TEXT PRIMARY KEY,
+	total_covered_blocks BIGINT NOT NULL,
+	earliest_block BIGINT NOT NULL,
+	latest_block BIGINT NOT NULL
+);
+
+INSERT INTO coverage_stats (address, total_covered_blocks, earliest_block, latest_block)
+SELECT address, SUM(covered), MIN(from_block), MAX(to_block) FROM (
+	SELECT address, from_block, to_block,
+		GREATEST(0, to_block - GREATEST(from_block, COALESCE(MAX(to_block) OVER (
+			PARTITION BY address ORDER BY from_block, to_block
+			ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING) + 1, from_block)) + 1) AS covered
+	FROM log_coverage
+) AS ranges
+GROUP BY address
+ON CONFLICT (address) DO UPDATE SET
+	total_covered_blocks = excluded.total_covered_blocks,
+	earliest_block = excluded.earliest_block,
+	latest_block = excluded.latest_block;
+
+-- Recomputes the summary of a single address, removing it if the address has no ranges left
+CREATE OR REPLACE FUNCTION refresh_coverage_stats(stats_address TEXT) RETURNS VOID AS $$
+BEGIN
+	DELETE FROM coverage_stats WHERE address = stats_address;
+
+	INSERT INTO coverage_stats (address, total_covered_blocks, earliest_block, latest_block)
+	SELECT address, SUM(covered), MIN(from_block), MAX(to_block) FROM (
+		SELECT address, from_block, to_block,
+			GREATEST(0, to_block - GREATEST(from_block, COALESCE(MAX(to_block) OVER (
+				ORDER BY from_block, to_block
+				ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING) + 1, from_block)) + 1) AS covered
+		FROM log_coverage WHERE address = stats_address
+	) AS ranges
+	GROUP BY address;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION log_coverage_stats_trigger() RETURNS TRIGGER AS $$
+BEGIN
+	IF TG_OP IN ('UPDATE', 'DELETE') THEN
+		PERFORM refresh_coverage_stats(OLD.address);
+	END IF;
+
+	IF TG_OP IN ('INSERT', 'UPDATE') THEN
+		PERFORM refresh_coverage_stats(NEW.address);
+	END IF;
+
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_log_coverage_stats ON log_coverage;
+CREATE TRIGGER trg_log_coverage_stats AFTER INSERT OR UPDATE OR DELETE ON log_coverage
+FOR EACH ROW EXECUTE FUNCTION log_coverage_stats_trigger();
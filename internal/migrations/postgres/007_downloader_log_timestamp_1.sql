@@ -0,0 +1,9 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_event_logs_timestamp;
+ALTER TABLE event_logs DROP COLUMN IF EXISTS timestamp;
+
+-- +migrate Up
+-- Timestamp of the block a log was emitted in, as Unix seconds.
+-- Logs stored before this migration have no timestamp and keep 0
+ALTER TABLE event_logs ADD COLUMN IF NOT EXISTS timestamp BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_event_logs_timestamp ON event_logs(timestamp);
@@ -0,0 +1,9 @@
+-- +migrate Down
+DROP TABLE IF EXISTS downloader_meta;
+
+-- +migrate Up
+-- Key-value metadata of the downloader database, such as the ID of the chain it was created for
+CREATE TABLE IF NOT EXISTS downloader_meta (
+	key TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
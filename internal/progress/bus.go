@@ -0,0 +1,77 @@
+// Package progress distributes the sync progress of the downloader to any number of subscribers.
+package progress
+
+import (
+	"sync"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/downloader"
+)
+
+// defaultSubscriberBuffer is the number of events queued per subscriber when NewBus is given none.
+const defaultSubscriberBuffer = 64
+
+// Bus fans out progress events to its subscribers. Publishing never blocks:
+// events are dropped for subscribers that do not keep up.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[chan downloader.ProgressEvent]struct{}
+
+	// buffer is the number of events queued per subscriber
+	buffer int
+}
+
+// NewBus creates a Bus that queues up to buffer events per subscriber.
+func NewBus(buffer int) *Bus {
+	if buffer <= 0 {
+		buffer = defaultSubscriberBuffer
+	}
+
+	return &Bus{
+		subscribers: make(map[chan downloader.ProgressEvent]struct{}),
+		buffer:      buffer,
+	}
+}
+
+// Subscribe returns a channel receiving every event published from now on,
+// and a function that unsubscribes and closes the channel.
+func (b *Bus) Subscribe() (<-chan downloader.ProgressEvent, func()) {
+	ch := make(chan downloader.ProgressEvent, b.buffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// HasSubscribers reports whether any subscriber would receive a published event.
+func (b *Bus) HasSubscribers() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.subscribers) > 0
+}
+
+// Publish sends the event to every subscriber, skipping subscribers whose queue is full.
+func (b *Bus) Publish(event downloader.ProgressEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
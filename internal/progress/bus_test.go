@@ -0,0 +1,41 @@
+package progress
+
+import (
+	"testing"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/downloader"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus(t *testing.T) {
+	t.Parallel()
+
+	bus := NewBus(1)
+	require.False(t, bus.HasSubscribers())
+
+	// Publishing without subscribers is a no-op
+	bus.Publish(downloader.ProgressEvent{Indexer: "dropped"})
+
+	first, unsubscribeFirst := bus.Subscribe()
+	second, unsubscribeSecond := bus.Subscribe()
+	require.True(t, bus.HasSubscribers())
+
+	bus.Publish(downloader.ProgressEvent{Indexer: "erc20", CurrentBlock: 1})
+	require.Equal(t, "erc20", (<-first).Indexer)
+
+	// The queue of the second subscriber is full, so the event is dropped for it only
+	bus.Publish(downloader.ProgressEvent{Indexer: "erc20", CurrentBlock: 2})
+	require.Equal(t, uint64(2), (<-first).CurrentBlock)
+	require.Equal(t, uint64(1), (<-second).CurrentBlock)
+	require.Empty(t, second)
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+
+	_, open := <-first
+	require.False(t, open)
+	require.True(t, bus.HasSubscribers())
+
+	unsubscribeSecond()
+	require.False(t, bus.HasSubscribers())
+}
@@ -0,0 +1,74 @@
+package progress
+
+import "time"
+
+// DefaultEstimatorWindow is the number of progress events the sync rate is averaged over.
+const DefaultEstimatorWindow = 30
+
+// sample is the block an indexer reached at a point in time.
+type sample struct {
+	block uint64
+	at    time.Time
+}
+
+// Estimator estimates the remaining sync time of an indexer from a rolling average
+// of its blocks per second over the last progress events.
+type Estimator struct {
+	window  int
+	samples []sample
+}
+
+// NewEstimator creates an Estimator that averages the sync rate over the last window events.
+func NewEstimator(window int) *Estimator {
+	if window <= 0 {
+		window = DefaultEstimatorWindow
+	}
+
+	return &Estimator{window: window}
+}
+
+// Observe records that the indexer reached block at the given time.
+func (e *Estimator) Observe(block uint64, at time.Time) {
+	// A rewind, e.g. after a reorg, makes the earlier samples meaningless
+	if n := len(e.samples); n > 0 && block < e.samples[n-1].block {
+		e.samples = e.samples[:0]
+	}
+
+	e.samples = append(e.samples, sample{block: block, at: at})
+
+	// The rate over the last window events spans window+1 samples
+	if len(e.samples) > e.window+1 {
+		e.samples = e.samples[len(e.samples)-e.window-1:]
+	}
+}
+
+// BlocksPerSecond returns the average sync rate over the observed window,
+// or 0 if it cannot be estimated yet.
+func (e *Estimator) BlocksPerSecond() float64 {
+	if len(e.samples) < 2 { //nolint:mnd
+		return 0
+	}
+
+	first, last := e.samples[0], e.samples[len(e.samples)-1]
+	elapsed := last.at.Sub(first.at).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return float64(last.block-first.block) / elapsed
+}
+
+// EstimatedSecondsRemaining returns the estimated time to sync from currentBlock to targetBlock.
+// The second return value is false if the sync rate cannot be estimated yet.
+func (e *Estimator) EstimatedSecondsRemaining(currentBlock, targetBlock uint64) (uint64, bool) {
+	if currentBlock >= targetBlock {
+		return 0, true
+	}
+
+	rate := e.BlocksPerSecond()
+	if rate <= 0 {
+		return 0, false
+	}
+
+	return uint64(float64(targetBlock-currentBlock) / rate), true
+}
@@ -0,0 +1,64 @@
+package progress
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimator(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1700000000, 0)
+
+	t.Run("unknown rate", func(t *testing.T) {
+		t.Parallel()
+
+		estimator := NewEstimator(3)
+
+		_, ok := estimator.EstimatedSecondsRemaining(100, 200)
+		require.False(t, ok)
+
+		estimator.Observe(100, start)
+		_, ok = estimator.EstimatedSecondsRemaining(100, 200)
+		require.False(t, ok)
+
+		eta, ok := estimator.EstimatedSecondsRemaining(200, 200)
+		require.True(t, ok)
+		require.Zero(t, eta)
+	})
+
+	t.Run("rolling window", func(t *testing.T) {
+		t.Parallel()
+
+		estimator := NewEstimator(3)
+
+		// 10 blocks per second, then 100 blocks per second
+		estimator.Observe(0, start)
+		estimator.Observe(10, start.Add(time.Second))
+		require.InDelta(t, 10, estimator.BlocksPerSecond(), 0.001)
+
+		for i := range 3 {
+			estimator.Observe(uint64(110+100*i), start.Add(time.Duration(i+2)*time.Second))
+		}
+
+		// The window holds the last 3 events, all at 100 blocks per second
+		require.InDelta(t, 100, estimator.BlocksPerSecond(), 0.001)
+
+		eta, ok := estimator.EstimatedSecondsRemaining(310, 1310)
+		require.True(t, ok)
+		require.Equal(t, uint64(10), eta)
+	})
+
+	t.Run("rewind resets the window", func(t *testing.T) {
+		t.Parallel()
+
+		estimator := NewEstimator(3)
+		estimator.Observe(100, start)
+		estimator.Observe(200, start.Add(time.Second))
+		estimator.Observe(150, start.Add(2*time.Second))
+
+		require.Zero(t, estimator.BlocksPerSecond())
+	})
+}
@@ -1,47 +1,108 @@
 package reorg
 
 import (
+	"sync"
 	"time"
 
+	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 var (
-	reorgsDetected = promauto.NewCounter(
+	reorgsDetected = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "chainindexor_reorgs_detected_total",
 			Help: "Total number of blockchain reorganizations detected",
 		},
+		[]string{"chain_id"},
 	)
 
-	reorgDepth = promauto.NewHistogram(
+	reorgDepth = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "chainindexor_reorg_depth_blocks",
 			Help:    "Depth of blockchain reorganizations in blocks",
 			Buckets: []float64{1, 2, 5, 10, 20, 50, 100},
 		},
+		[]string{"chain_id"},
 	)
 
-	reorgLastDetected = promauto.NewGauge(
+	reorgMaxDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "chainindexor_reorg_max_depth",
+			Help: "Depth in blocks of the deepest blockchain reorganization ever detected",
+		},
+		[]string{"chain_id"},
+	)
+
+	reorgLastDetected = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "chainindexor_reorg_last_detected_timestamp",
 			Help: "Unix timestamp of last reorg detection",
 		},
+		[]string{"chain_id"},
 	)
 
-	reorgFromBlock = promauto.NewHistogram(
+	reorgFromBlock = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "chainindexor_reorg_from_block",
 			Help:    "Block numbers where reorgs started",
 			Buckets: []float64{0, 1000000, 3000000, 5000000, 7000000, 9000000, 10000000},
 		},
+		[]string{"chain_id"},
+	)
+
+	headerCacheHits = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "chainindexor_reorg_detector_cache_hits_total",
+			Help: "Total number of block headers the reorg detector served from its cache",
+		},
+		[]string{"chain_id"},
+	)
+
+	headerCacheMisses = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "chainindexor_reorg_detector_cache_misses_total",
+			Help: "Total number of block headers the reorg detector fetched from the RPC node",
+		},
+		[]string{"chain_id"},
 	)
 )
 
-func ReorgDetectedLog(depth, fromBlock uint64) {
-	reorgsDetected.Inc()
-	reorgDepth.Observe(float64(depth))
-	reorgLastDetected.Set(float64(time.Now().UTC().Unix()))
-	reorgFromBlock.Observe(float64(fromBlock))
+// maxDepth holds the value of the reorg max depth gauge of each chain, which only ever increases.
+var maxDepth struct {
+	sync.Mutex
+	depths map[uint64]uint64
+}
+
+func ReorgDetectedLog(chainID, depth, fromBlock uint64) {
+	chain := metrics.ChainLabel(chainID)
+	reorgsDetected.WithLabelValues(chain).Inc()
+	reorgDepth.WithLabelValues(chain).Observe(float64(depth))
+	ReorgMaxDepthObserve(chainID, depth)
+	reorgLastDetected.WithLabelValues(chain).Set(float64(time.Now().UTC().Unix()))
+	reorgFromBlock.WithLabelValues(chain).Observe(float64(fromBlock))
+}
+
+// HeaderCacheLog records the block headers served from the header cache and fetched from the RPC node.
+func HeaderCacheLog(chainID uint64, hits, misses int) {
+	chain := metrics.ChainLabel(chainID)
+	headerCacheHits.WithLabelValues(chain).Add(float64(hits))
+	headerCacheMisses.WithLabelValues(chain).Add(float64(misses))
+}
+
+// ReorgMaxDepthObserve raises the reorg max depth gauge of the chain to depth,
+// if it is deeper than any reorg seen on the chain before.
+func ReorgMaxDepthObserve(chainID, depth uint64) {
+	maxDepth.Lock()
+	defer maxDepth.Unlock()
+
+	if maxDepth.depths == nil {
+		maxDepth.depths = make(map[uint64]uint64)
+	}
+
+	if current, ok := maxDepth.depths[chainID]; !ok || depth > current {
+		maxDepth.depths[chainID] = depth
+		reorgMaxDepth.WithLabelValues(metrics.ChainLabel(chainID)).Set(float64(depth))
+	}
 }
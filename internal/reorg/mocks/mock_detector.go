@@ -7,6 +7,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	reorg "github.com/goran-ethernal/ChainIndexor/pkg/reorg"
+
 	types "github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -68,6 +70,113 @@ func (_c *Detector_Close_Call) RunAndReturn(run func() error) *Detector_Close_Ca
 	return _c
 }
 
+// GetReorgHistory provides a mock function with given fields: ctx, fromBlock, toBlock
+func (_m *Detector) GetReorgHistory(ctx context.Context, fromBlock uint64, toBlock uint64) ([]reorg.ReorgEvent, error) {
+	ret := _m.Called(ctx, fromBlock, toBlock)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReorgHistory")
+	}
+
+	var r0 []reorg.ReorgEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) ([]reorg.ReorgEvent, error)); ok {
+		return rf(ctx, fromBlock, toBlock)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) []reorg.ReorgEvent); ok {
+		r0 = rf(ctx, fromBlock, toBlock)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reorg.ReorgEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, fromBlock, toBlock)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Detector_GetReorgHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReorgHistory'
+type Detector_GetReorgHistory_Call struct {
+	*mock.Call
+}
+
+// GetReorgHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fromBlock uint64
+//   - toBlock uint64
+func (_e *Detector_Expecter) GetReorgHistory(ctx interface{}, fromBlock interface{}, toBlock interface{}) *Detector_GetReorgHistory_Call {
+	return &Detector_GetReorgHistory_Call{Call: _e.mock.On("GetReorgHistory", ctx, fromBlock, toBlock)}
+}
+
+func (_c *Detector_GetReorgHistory_Call) Run(run func(ctx context.Context, fromBlock uint64, toBlock uint64)) *Detector_GetReorgHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(uint64))
+	})
+	return _c
+}
+
+func (_c *Detector_GetReorgHistory_Call) Return(_a0 []reorg.ReorgEvent, _a1 error) *Detector_GetReorgHistory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Detector_GetReorgHistory_Call) RunAndReturn(run func(context.Context, uint64, uint64) ([]reorg.ReorgEvent, error)) *Detector_GetReorgHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HandleReorg provides a mock function with given fields: ctx, fromBlock
+func (_m *Detector) HandleReorg(ctx context.Context, fromBlock uint64) error {
+	ret := _m.Called(ctx, fromBlock)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HandleReorg")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, fromBlock)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Detector_HandleReorg_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HandleReorg'
+type Detector_HandleReorg_Call struct {
+	*mock.Call
+}
+
+// HandleReorg is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fromBlock uint64
+func (_e *Detector_Expecter) HandleReorg(ctx interface{}, fromBlock interface{}) *Detector_HandleReorg_Call {
+	return &Detector_HandleReorg_Call{Call: _e.mock.On("HandleReorg", ctx, fromBlock)}
+}
+
+func (_c *Detector_HandleReorg_Call) Run(run func(ctx context.Context, fromBlock uint64)) *Detector_HandleReorg_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64))
+	})
+	return _c
+}
+
+func (_c *Detector_HandleReorg_Call) Return(_a0 error) *Detector_HandleReorg_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Detector_HandleReorg_Call) RunAndReturn(run func(context.Context, uint64) error) *Detector_HandleReorg_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // VerifyAndRecordBlocks provides a mock function with given fields: ctx, logs, fromBlock, toBlock
 func (_m *Detector) VerifyAndRecordBlocks(ctx context.Context, logs []types.Log, fromBlock uint64, toBlock uint64) ([]*types.Header, error) {
 	ret := _m.Called(ctx, logs, fromBlock, toBlock)
@@ -5,6 +5,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -12,37 +15,70 @@ import (
 	"github.com/goran-ethernal/ChainIndexor/internal/db"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
+	"github.com/goran-ethernal/ChainIndexor/internal/tracing"
 	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
 	"github.com/goran-ethernal/ChainIndexor/pkg/rpc"
+	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/russross/meddler"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var _ reorg.Detector = (*ReorgDetector)(nil)
 
+// defaultHeaderCacheSize is the number of block headers cached when no cache size is given.
+const defaultHeaderCacheSize = 256
+
 // ReorgDetector detects blockchain reorganizations by tracking block hashes.
 type ReorgDetector struct {
 	db                     *sql.DB
 	log                    *logger.Logger
 	rpc                    rpc.EthClient
 	maintenanceCoordinator db.Maintenance
+	chainID                uint64
+
+	// headerCache holds the headers of non-finalized blocks fetched from the RPC node by block number
+	headerCache *lru.Cache[uint64, *types.Header]
 }
 
 // NewReorgDetector creates a new ReorgDetector with the given database configuration.
+// It caches up to headerCacheSize block headers, or a default number of headers if it is 0.
+// Its metrics are labelled with chainID.
 func NewReorgDetector(
+	chainID uint64,
 	db *sql.DB,
 	rpcClient rpc.EthClient,
 	log *logger.Logger,
 	maintenanceCoordinator db.Maintenance,
+	headerCacheSize int,
 ) (*ReorgDetector, error) {
+	if headerCacheSize == 0 {
+		headerCacheSize = defaultHeaderCacheSize
+	}
+
+	headerCache, err := lru.New[uint64, *types.Header](headerCacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create header cache: %w", err)
+	}
+
 	detector := &ReorgDetector{
 		db:                     db,
 		rpc:                    rpcClient,
 		log:                    log,
 		maintenanceCoordinator: maintenanceCoordinator,
+		chainID:                chainID,
+		headerCache:            headerCache,
+	}
+
+	// The max depth gauge reports the deepest reorg ever seen, including before a restart
+	var deepest uint64
+	if err := db.QueryRow("SELECT COALESCE(MAX(depth), 0) FROM reorg_events").Scan(&deepest); err != nil {
+		return nil, fmt.Errorf("failed to get deepest recorded reorg: %w", err)
 	}
+	ReorgMaxDepthObserve(chainID, deepest)
 
 	// Initialize component health
-	metrics.ComponentHealthSet(internalcommon.ComponentReorgDetector, true)
+	metrics.ComponentHealthSet(chainID, internalcommon.ComponentReorgDetector, true)
 
 	detector.log.Info("reorg detector initialized")
 
@@ -58,7 +94,14 @@ func NewReorgDetector(
 // All database operations are performed atomically within a single transaction.
 func (r *ReorgDetector) VerifyAndRecordBlocks(
 	ctx context.Context,
-	logs []types.Log, fromBlock, toBlock uint64) ([]*types.Header, error) {
+	logs []types.Log, fromBlock, toBlock uint64) (_ []*types.Header, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ReorgDetector.VerifyAndRecordBlocks", trace.WithAttributes(
+		attribute.Int64("from_block", int64(fromBlock)),
+		attribute.Int64("to_block", int64(toBlock)),
+		attribute.Int("logs", len(logs)),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	// Acquire operation lock if maintenance coordinator is available
 	unlock := r.maintenanceCoordinator.AcquireOperationLock()
 	defer unlock()
@@ -87,6 +130,9 @@ func (r *ReorgDetector) VerifyAndRecordBlocks(
 	}
 	finalizedBlockNum := finalizedHeader.Number.Uint64()
 
+	// Finalized blocks are never verified again
+	r.evictHeaders(func(blockNum uint64) bool { return blockNum <= finalizedBlockNum })
+
 	// Check if we have the finalized block in our DB
 	cachedFinalizedBlock, err := r.getStoredBlockTx(tx, finalizedBlockNum)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -116,8 +162,8 @@ func (r *ReorgDetector) VerifyAndRecordBlocks(
 			blockNums[i] = block.BlockNumber
 		}
 
-		// Fetch current headers from RPC
-		currentHeaders, err := r.rpc.BatchGetBlockHeaders(ctx, blockNums)
+		// Fetch current headers, reusing the cached headers still on the canonical chain
+		currentHeaders, err := r.getHeaders(ctx, blockNums)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch non-finalized headers: %w", err)
 		}
@@ -134,8 +180,7 @@ func (r *ReorgDetector) VerifyAndRecordBlocks(
 					cachedHash.Hex(),
 					currentHash.Hex(),
 				)
-				ReorgDetectedLog(uint64(len(nonFinalizedBlocks)-i), header.Number.Uint64())
-				return nil, reorg.NewReorgError(header.Number.Uint64(),
+				return nil, r.reorgDetected(tx, header.Number.Uint64(), uint64(len(nonFinalizedBlocks)-i),
 					fmt.Sprintf("cached_hash=%s current_hash=%s", cachedHash.Hex(), currentHash.Hex()))
 			}
 		}
@@ -156,7 +201,7 @@ func (r *ReorgDetector) VerifyAndRecordBlocks(
 		return nil, nil
 	}
 
-	headers, err := r.rpc.BatchGetBlockHeaders(ctx, blockNums)
+	headers, err := r.getHeaders(ctx, blockNums)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch headers for range: %w", err)
 	}
@@ -182,8 +227,7 @@ func (r *ReorgDetector) VerifyAndRecordBlocks(
 					logHash.Hex(),
 					headerHash.Hex(),
 				)
-				ReorgDetectedLog(uint64(len(headers)-i), blockNum)
-				return nil, reorg.NewReorgError(blockNum,
+				return nil, r.reorgDetected(tx, blockNum, uint64(len(headers)-i),
 					fmt.Sprintf("log_hash=%s header_hash=%s", logHash.Hex(), headerHash.Hex()))
 			}
 		}
@@ -202,8 +246,7 @@ func (r *ReorgDetector) VerifyAndRecordBlocks(
 					expectedParent.Hex(),
 					actualParent.Hex(),
 				)
-				ReorgDetectedLog(uint64(len(headers)-i), headers[i].Number.Uint64())
-				return nil, reorg.NewReorgError(headers[i].Number.Uint64(),
+				return nil, r.reorgDetected(tx, headers[i].Number.Uint64(), uint64(len(headers)-i),
 					fmt.Sprintf("chain discontinuity between blocks %d and %d",
 						headers[i-1].Number.Uint64(), headers[i].Number.Uint64()))
 			}
@@ -231,6 +274,120 @@ func (r *ReorgDetector) VerifyAndRecordBlocks(
 	return headers, nil
 }
 
+// reorgDetected records a detected reorg in the reorg history and returns the error reporting it.
+// The transaction is committed with the recorded reorg, as nothing else is recorded once a reorg
+// is detected. The reorg is still reported if it cannot be recorded.
+func (r *ReorgDetector) reorgDetected(tx *sql.Tx, firstReorgBlock, depth uint64, details string) error {
+	ReorgDetectedLog(r.chainID, depth, firstReorgBlock)
+
+	event := &reorgEvent{
+		DetectedAt:      time.Now().UTC().Unix(),
+		FirstReorgBlock: firstReorgBlock,
+		Depth:           depth,
+		Details:         details,
+	}
+
+	if err := db.Meddler(r.db).Insert(tx, "reorg_events", event); err != nil {
+		r.log.Errorf("failed to record reorg: first_reorg_block=%d error=%v", firstReorgBlock, err)
+	} else if err := tx.Commit(); err != nil {
+		r.log.Errorf("failed to commit recorded reorg: first_reorg_block=%d error=%v", firstReorgBlock, err)
+	}
+
+	return reorg.NewReorgError(firstReorgBlock, details)
+}
+
+// getHeaders returns the current headers of the given blocks, in ascending order.
+// A cached header is only used if it is the parent of the header of the next block, so it is known
+// to still be on the canonical chain. The header of the highest block is therefore always fetched,
+// and cached headers of reorged blocks are fetched again.
+func (r *ReorgDetector) getHeaders(ctx context.Context, blockNums []uint64) ([]*types.Header, error) {
+	headers := make([]*types.Header, len(blockNums))
+	cached := make([]bool, len(blockNums))
+
+	// Split the blocks into cache hits and misses. Only blocks followed by the next block
+	// can be verified against the next header's parent hash
+	var misses []int
+	for i, blockNum := range blockNums {
+		if i+1 < len(blockNums) && blockNums[i+1] == blockNum+1 {
+			if header, ok := r.headerCache.Get(blockNum); ok {
+				headers[i] = header
+				cached[i] = true
+				continue
+			}
+		}
+		misses = append(misses, i)
+	}
+
+	if err := r.fetchHeaders(ctx, blockNums, misses, headers); err != nil {
+		return nil, err
+	}
+
+	// Verify the cached headers from the highest block down. A cached header that does not match
+	// the parent hash of the next header, or is followed by a stale header, is stale and fetched again
+	isStale := make([]bool, len(blockNums))
+	var stale []int
+	for next := len(blockNums) - 1; next > 0; next-- {
+		i := next - 1
+		if cached[i] && (isStale[next] || headers[i].Hash() != headers[next].ParentHash) {
+			isStale[i] = true
+			stale = append(stale, i)
+		}
+	}
+
+	hits := len(blockNums) - len(misses) - len(stale)
+	HeaderCacheLog(r.chainID, hits, len(misses)+len(stale))
+
+	if len(stale) == 0 {
+		return headers, nil
+	}
+
+	r.log.Debugf("cached headers are no longer canonical, fetching them again: count=%d", len(stale))
+	slices.Reverse(stale)
+	if err := r.fetchHeaders(ctx, blockNums, stale, headers); err != nil {
+		return nil, err
+	}
+
+	return headers, nil
+}
+
+// fetchHeaders fetches the headers of the blocks at the given indexes of blockNums from the RPC node,
+// stores them at the same indexes of headers and caches them.
+func (r *ReorgDetector) fetchHeaders(
+	ctx context.Context,
+	blockNums []uint64,
+	indexes []int,
+	headers []*types.Header,
+) error {
+	toFetch := make([]uint64, len(indexes))
+	for i, index := range indexes {
+		toFetch[i] = blockNums[index]
+	}
+
+	fetched, err := r.rpc.BatchGetBlockHeaders(ctx, toFetch)
+	if err != nil {
+		return err
+	}
+	if len(fetched) != len(toFetch) {
+		return fmt.Errorf("expected %d headers, got %d", len(toFetch), len(fetched))
+	}
+
+	for i, header := range fetched {
+		headers[indexes[i]] = header
+		r.headerCache.Add(header.Number.Uint64(), header)
+	}
+
+	return nil
+}
+
+// evictHeaders removes the cached headers of the blocks matching the predicate.
+func (r *ReorgDetector) evictHeaders(evict func(blockNum uint64) bool) {
+	for _, blockNum := range r.headerCache.Keys() {
+		if evict(blockNum) {
+			r.headerCache.Remove(blockNum)
+		}
+	}
+}
+
 // StoredBlock represents a block stored in the database.
 // Uses meddler tags for automatic struct-to-db mapping.
 type StoredBlock struct {
@@ -240,6 +397,15 @@ type StoredBlock struct {
 	CreatedAt   string      `meddler:"created_at"`
 }
 
+// reorgEvent represents a detected reorg stored in the database.
+type reorgEvent struct {
+	ID              int64  `meddler:"id,pk"`
+	DetectedAt      int64  `meddler:"detected_at"`
+	FirstReorgBlock uint64 `meddler:"first_reorg_block"`
+	Depth           uint64 `meddler:"depth"`
+	Details         string `meddler:"details"`
+}
+
 // getStoredBlockTx retrieves the cached block for a specific block number using a transaction.
 func (r *ReorgDetector) getStoredBlockTx(tx *sql.Tx, blockNum uint64) (StoredBlock, error) {
 	var block StoredBlock
@@ -272,7 +438,7 @@ func (r *ReorgDetector) recordBlocksTx(tx *sql.Tx, headers []*types.Header) erro
 			ParentHash:  header.ParentHash,
 		}
 
-		if err := meddler.Save(tx, "block_hashes", block); err != nil {
+		if err := db.Meddler(r.db).Save(tx, "block_hashes", block); err != nil {
 			return fmt.Errorf("failed to insert block %d: %w", header.Number.Uint64(), err)
 		}
 	}
@@ -301,6 +467,68 @@ func (r *ReorgDetector) pruneOldBlocksTx(tx *sql.Tx, keepFromBlock uint64) error
 	return nil
 }
 
+// HandleReorg removes the recorded block hashes at or after fromBlock.
+// Without this, the stale hashes of reorged blocks would be reported as a reorg again
+// every time the range is re-fetched.
+func (r *ReorgDetector) HandleReorg(ctx context.Context, fromBlock uint64) error {
+	// Acquire operation lock if maintenance coordinator is available
+	unlock := r.maintenanceCoordinator.AcquireOperationLock()
+	defer unlock()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM block_hashes WHERE block_number >= ?", fromBlock)
+	if err != nil {
+		return fmt.Errorf("failed to remove reorged block hashes: %w", err)
+	}
+
+	r.evictHeaders(func(blockNum uint64) bool { return blockNum >= fromBlock })
+
+	rowsAffected, _ := result.RowsAffected()
+	r.log.Infof("removed reorged block hashes: from_block=%d deleted_count=%d", fromBlock, rowsAffected)
+
+	return nil
+}
+
+// GetReorgHistory returns the recorded reorgs whose first reorged block is within the
+// inclusive block range, oldest first.
+func (r *ReorgDetector) GetReorgHistory(ctx context.Context, fromBlock, toBlock uint64) ([]reorg.ReorgEvent, error) {
+	// Acquire operation lock if maintenance coordinator is available
+	unlock := r.maintenanceCoordinator.AcquireOperationLock()
+	defer unlock()
+
+	// Block numbers are stored as signed integers, so larger bounds cannot be passed to the query
+	toBlock = min(toBlock, math.MaxInt64)
+	if fromBlock > toBlock {
+		return []reorg.ReorgEvent{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, detected_at, first_reorg_block, depth, details FROM reorg_events
+		WHERE first_reorg_block >= ? AND first_reorg_block <= ?
+		ORDER BY id ASC`, fromBlock, toBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reorg history: %w", err)
+	}
+	defer rows.Close()
+
+	events := []reorg.ReorgEvent{}
+	for rows.Next() {
+		var (
+			event      reorg.ReorgEvent
+			detectedAt int64
+		)
+		if err := rows.Scan(&event.ID, &detectedAt, &event.FirstReorgBlock, &event.Depth, &event.Details); err != nil {
+			return nil, fmt.Errorf("failed to scan reorg event: %w", err)
+		}
+		event.DetectedAt = time.Unix(detectedAt, 0).UTC()
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reorg history: %w", err)
+	}
+
+	return events, nil
+}
+
 // GetStoredBlock retrieves a cached block for a specific block number.
 // This method is exposed for testing purposes.
 func (r *ReorgDetector) GetStoredBlock(blockNum uint64) (StoredBlock, error) {
@@ -325,6 +553,6 @@ func (r *ReorgDetector) GetStoredBlockCount() (int, error) {
 
 // Close closes the database connection.
 func (r *ReorgDetector) Close() error {
-	metrics.ComponentHealthSet(internalcommon.ComponentReorgDetector, false)
+	metrics.ComponentHealthSet(r.chainID, internalcommon.ComponentReorgDetector, false)
 	return r.db.Close()
 }
@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"math"
 	"math/big"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -16,6 +18,9 @@ import (
 	"github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
 	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -41,7 +46,7 @@ func setupTestReorgDetector(t *testing.T) (*ReorgDetector, *mocks.EthClient, fun
 	log, err := logger.NewLogger("error", true)
 	require.NoError(t, err)
 
-	detector, err := NewReorgDetector(database, mockRPC, log, &db.NoOpMaintenance{})
+	detector, err := NewReorgDetector(0, database, mockRPC, log, &db.NoOpMaintenance{}, 0)
 	require.NoError(t, err)
 
 	cleanup := func() {
@@ -91,8 +96,8 @@ func TestReorgDetector_VerifyAndRecordBlocks_FirstTime(t *testing.T) {
 	finalizedHeader := createTestHeader(50, common.HexToHash("0x49"))
 
 	// Mock RPC calls
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader, nil)
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{100, 101, 102}).
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil)
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101, 102}).
 		Return([]*types.Header{header100, header101, header102}, nil)
 
 	// Create test logs
@@ -143,8 +148,8 @@ func TestReorgDetector_VerifyAndRecordBlocks_WithNonFinalizedBlocks(t *testing.T
 	header101 := createTestHeader(101, header100.Hash())
 	finalizedHeader := createTestHeader(50, common.HexToHash("0x49"))
 
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader, nil).Once()
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{100, 101}).
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101}).
 		Return([]*types.Header{header100, header101}, nil).Once()
 
 	logs := []types.Log{
@@ -160,12 +165,13 @@ func TestReorgDetector_VerifyAndRecordBlocks_WithNonFinalizedBlocks(t *testing.T
 	header102 := createTestHeader(102, header101.Hash())
 	header103 := createTestHeader(103, header102.Hash())
 
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader, nil).Once()
-	// Should verify blocks 100 and 101 (non-finalized)
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{100, 101}).
-		Return([]*types.Header{header100, header101}, nil).Once()
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	// Should verify blocks 100 and 101 (non-finalized), reusing the cached header of block 100,
+	// which is the parent of block 101
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{101}).
+		Return([]*types.Header{header101}, nil).Once()
 	// Then fetch new blocks 102-103
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{102, 103}).
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{102, 103}).
 		Return([]*types.Header{header102, header103}, nil).Once()
 
 	logs2 := []types.Log{
@@ -204,8 +210,8 @@ func TestReorgDetector_VerifyAndRecordBlocks_ReorgInNonFinalizedBlocks(t *testin
 	header101 := createTestHeader(101, header100.Hash())
 	finalizedHeader := createTestHeader(50, common.HexToHash("0x49"))
 
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader, nil).Once()
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{100, 101}).
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101}).
 		Return([]*types.Header{header100, header101}, nil).Once()
 
 	logs := []types.Log{
@@ -221,10 +227,10 @@ func TestReorgDetector_VerifyAndRecordBlocks_ReorgInNonFinalizedBlocks(t *testin
 	header101Reorg := createTestHeader(101, header100.Hash())
 	header101Reorg.GasUsed = 1000 // Make it different
 
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
 	// Should verify blocks 100 and 101, but 101 has changed!
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{100, 101}).
-		Return([]*types.Header{header100, header101Reorg}, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{101}).
+		Return([]*types.Header{header101Reorg}, nil).Once()
 
 	logs2 := []types.Log{
 		{BlockNumber: 102, BlockHash: common.HexToHash("0x102")},
@@ -240,6 +246,313 @@ func TestReorgDetector_VerifyAndRecordBlocks_ReorgInNonFinalizedBlocks(t *testin
 	require.Equal(t, uint64(101), reorgErr.FirstReorgBlock)
 }
 
+func TestReorgDetector_HandleReorg(t *testing.T) {
+	t.Parallel()
+
+	detector, mockRPC, cleanup := setupTestReorgDetector(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	header100 := createTestHeader(100, common.HexToHash("0x99"))
+	header101 := createTestHeader(101, header100.Hash())
+	header102 := createTestHeader(102, header101.Hash())
+	finalizedHeader := createTestHeader(50, common.HexToHash("0x49"))
+
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101, 102}).
+		Return([]*types.Header{header100, header101, header102}, nil).Once()
+
+	_, err := detector.VerifyAndRecordBlocks(ctx, nil, 100, 102)
+	require.NoError(t, err)
+
+	require.NoError(t, detector.HandleReorg(ctx, 101))
+
+	count, err := detector.GetStoredBlockCount()
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	_, err = detector.GetStoredBlock(101)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// The re-fetched range is verified against the new chain without reporting the reorg again
+	header101Reorg := createTestHeader(101, header100.Hash())
+	header101Reorg.GasUsed = 1000
+	header102Reorg := createTestHeader(102, header101Reorg.Hash())
+
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100}).
+		Return([]*types.Header{header100}, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{101, 102}).
+		Return([]*types.Header{header101Reorg, header102Reorg}, nil).Once()
+
+	headers, err := detector.VerifyAndRecordBlocks(ctx, nil, 101, 102)
+	require.NoError(t, err)
+	require.Len(t, headers, 2)
+}
+
+func TestReorgDetector_GetReorgHistory(t *testing.T) {
+	t.Parallel()
+
+	detector, mockRPC, cleanup := setupTestReorgDetector(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	finalizedHeader := createTestHeader(50, common.HexToHash("0x49"))
+
+	// detectReorg makes the detector find a reorg at the first block of the range, by returning
+	// logs whose block hash does not match the block's header
+	detectReorg := func(fromBlock, toBlock uint64) {
+		t.Helper()
+
+		headers := make([]*types.Header, 0, toBlock-fromBlock+1)
+		blockNums := make([]uint64, 0, toBlock-fromBlock+1)
+		parentHash := common.HexToHash("0x99")
+		for blockNum := fromBlock; blockNum <= toBlock; blockNum++ {
+			header := createTestHeader(blockNum, parentHash)
+			headers = append(headers, header)
+			blockNums = append(blockNums, blockNum)
+			parentHash = header.Hash()
+		}
+
+		mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+		mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, blockNums).Return(headers, nil).Once()
+
+		logs := []types.Log{{BlockNumber: fromBlock, BlockHash: common.HexToHash("0xdead")}}
+		_, err := detector.VerifyAndRecordBlocks(ctx, logs, fromBlock, toBlock)
+
+		var reorgErr *reorg.ReorgDetectedError
+		require.ErrorAs(t, err, &reorgErr)
+		require.Equal(t, fromBlock, reorgErr.FirstReorgBlock)
+	}
+
+	before := time.Now().UTC().Add(-time.Second)
+
+	detectReorg(100, 101)
+	detectReorg(200, 200)
+	detectReorg(300, 302)
+
+	// firstReorgBlocks returns the first reorged block of every event
+	firstReorgBlocks := func(events []reorg.ReorgEvent) []uint64 {
+		blocks := make([]uint64, len(events))
+		for i, event := range events {
+			blocks[i] = event.FirstReorgBlock
+		}
+		return blocks
+	}
+
+	events, err := detector.GetReorgHistory(ctx, 0, math.MaxUint64)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, []uint64{100, 200, 300}, firstReorgBlocks(events))
+	require.Equal(t, []uint64{2, 1, 3}, []uint64{events[0].Depth, events[1].Depth, events[2].Depth})
+	require.Contains(t, events[0].Details, "log_hash=")
+	for _, event := range events {
+		require.NotZero(t, event.ID)
+		require.False(t, event.DetectedAt.Before(before))
+	}
+
+	tests := []struct {
+		name      string
+		fromBlock uint64
+		toBlock   uint64
+		expected  []uint64
+	}{
+		{name: "range bounds are inclusive", fromBlock: 100, toBlock: 200, expected: []uint64{100, 200}},
+		{name: "range within a reorg's blocks", fromBlock: 101, toBlock: 302, expected: []uint64{200, 300}},
+		{name: "single block", fromBlock: 200, toBlock: 200, expected: []uint64{200}},
+		{name: "no reorgs in range", fromBlock: 201, toBlock: 299, expected: []uint64{}},
+		{name: "inverted range", fromBlock: 300, toBlock: 100, expected: []uint64{}},
+	}
+
+	for _, tt := range tests {
+		events, err := detector.GetReorgHistory(ctx, tt.fromBlock, tt.toBlock)
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.expected, firstReorgBlocks(events), tt.name)
+	}
+}
+
+// counterValue returns the current value of a counter.
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	t.Helper()
+
+	var metric dto.Metric
+	require.NoError(t, counter.Write(&metric))
+
+	return metric.GetCounter().GetValue()
+}
+
+// TestReorgDetectedLog is not parallel, so no other test observes reorg depths while it runs.
+func TestReorgDetectedLog(t *testing.T) {
+	histogram := func() *dto.Histogram {
+		var metric dto.Metric
+		require.NoError(t, reorgDepth.WithLabelValues("1").(prometheus.Metric).Write(&metric))
+		return metric.GetHistogram()
+	}
+
+	// bucketCount returns the number of observations up to the bucket's upper bound
+	bucketCount := func(h *dto.Histogram, upperBound float64) uint64 {
+		for _, bucket := range h.GetBucket() {
+			if bucket.GetUpperBound() == upperBound {
+				return bucket.GetCumulativeCount()
+			}
+		}
+		t.Fatalf("no bucket with upper bound %v", upperBound)
+		return 0
+	}
+
+	gauge := func(chain string) float64 {
+		var metric dto.Metric
+		require.NoError(t, reorgMaxDepth.WithLabelValues(chain).Write(&metric))
+		return metric.GetGauge().GetValue()
+	}
+
+	before := histogram()
+	maxDepthBefore := gauge("1")
+
+	ReorgDetectedLog(1, 3, 1000)
+	ReorgDetectedLog(1, 101, 2000)
+
+	// The max depth gauge never decreases
+	require.InDelta(t, max(maxDepthBefore, 101), gauge("1"), 0)
+	ReorgDetectedLog(1, 4, 3000)
+	require.InDelta(t, max(maxDepthBefore, 101), gauge("1"), 0)
+
+	// The max depth gauge of another chain is tracked separately
+	ReorgMaxDepthObserve(137, 7)
+	require.InDelta(t, 7, gauge("137"), 0)
+	require.InDelta(t, max(maxDepthBefore, 101), gauge("1"), 0)
+
+	after := histogram()
+	require.Equal(t, before.GetSampleCount()+3, after.GetSampleCount())
+	require.InDelta(t, before.GetSampleSum()+108, after.GetSampleSum(), 0)
+	require.Equal(t, bucketCount(before, 2), bucketCount(after, 2))
+	require.Equal(t, bucketCount(before, 5)+2, bucketCount(after, 5))
+	// A reorg deeper than 100 blocks is only counted in the +Inf bucket
+	require.Equal(t, bucketCount(before, 100)+2, bucketCount(after, 100))
+}
+
+// TestReorgDetector_HeaderCache is not parallel, so no other test changes the cache metrics while it runs.
+func TestReorgDetector_HeaderCache(t *testing.T) {
+	detector, mockRPC, cleanup := setupTestReorgDetector(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	header100 := createTestHeader(100, common.HexToHash("0x99"))
+	header101 := createTestHeader(101, header100.Hash())
+	header102 := createTestHeader(102, header101.Hash())
+	header103 := createTestHeader(103, header102.Hash())
+	header104 := createTestHeader(104, header103.Hash())
+	finalizedHeader := createTestHeader(50, common.HexToHash("0x49"))
+
+	hits, misses := counterValue(t, headerCacheHits.WithLabelValues("0")), counterValue(t, headerCacheMisses.WithLabelValues("0"))
+
+	// All headers are fetched the first time
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101, 102}).
+		Return([]*types.Header{header100, header101, header102}, nil).Once()
+
+	_, err := detector.VerifyAndRecordBlocks(ctx, nil, 100, 102)
+	require.NoError(t, err)
+	require.Equal(t, hits, counterValue(t, headerCacheHits.WithLabelValues("0")))
+	require.Equal(t, misses+3, counterValue(t, headerCacheMisses.WithLabelValues("0")))
+	require.Equal(t, 3, detector.headerCache.Len())
+
+	// Blocks 100 and 101 are served from the cache, since they are the ancestors of the fetched block 102
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{102}).
+		Return([]*types.Header{header102}, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{103, 104}).
+		Return([]*types.Header{header103, header104}, nil).Once()
+
+	headers, err := detector.VerifyAndRecordBlocks(ctx, nil, 103, 104)
+	require.NoError(t, err)
+	require.Equal(t, []*types.Header{header103, header104}, headers)
+	require.Equal(t, hits+2, counterValue(t, headerCacheHits.WithLabelValues("0")))
+	require.Equal(t, misses+6, counterValue(t, headerCacheMisses.WithLabelValues("0")))
+	require.Equal(t, 5, detector.headerCache.Len())
+
+	// Headers of finalized blocks are evicted
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(header102, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{104}).
+		Return([]*types.Header{header104}, nil).Once()
+
+	headers, err = detector.VerifyAndRecordBlocks(ctx, nil, 100, 102)
+	require.NoError(t, err)
+	require.Empty(t, headers)
+	require.Equal(t, []uint64{103, 104}, detector.headerCache.Keys())
+}
+
+func TestReorgDetector_HeaderCache_StaleHeaders(t *testing.T) {
+	t.Parallel()
+
+	detector, mockRPC, cleanup := setupTestReorgDetector(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	header100 := createTestHeader(100, common.HexToHash("0x99"))
+	header101 := createTestHeader(101, header100.Hash())
+	header102 := createTestHeader(102, header101.Hash())
+	finalizedHeader := createTestHeader(50, common.HexToHash("0x49"))
+
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101, 102}).
+		Return([]*types.Header{header100, header101, header102}, nil).Once()
+
+	_, err := detector.VerifyAndRecordBlocks(ctx, nil, 100, 102)
+	require.NoError(t, err)
+
+	// Blocks 101 and 102 are reorged, so the cached header of 101 is not the parent of the new 102.
+	// It is fetched again, and so is the cached header of 100 that can no longer be verified
+	header101Reorg := createTestHeader(101, header100.Hash())
+	header101Reorg.GasUsed = 1000
+	header102Reorg := createTestHeader(102, header101Reorg.Hash())
+
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{102}).
+		Return([]*types.Header{header102Reorg}, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101}).
+		Return([]*types.Header{header100, header101Reorg}, nil).Once()
+
+	headers, err := detector.VerifyAndRecordBlocks(ctx, nil, 103, 103)
+	require.Nil(t, headers)
+
+	var reorgErr *reorg.ReorgDetectedError
+	require.ErrorAs(t, err, &reorgErr)
+	require.Equal(t, uint64(101), reorgErr.FirstReorgBlock)
+
+	// The reorged headers replace the stale ones in the cache
+	cached, ok := detector.headerCache.Peek(101)
+	require.True(t, ok)
+	require.Equal(t, header101Reorg.Hash(), cached.Hash())
+}
+
+func TestReorgDetector_HeaderCache_HandleReorgEvicts(t *testing.T) {
+	t.Parallel()
+
+	detector, mockRPC, cleanup := setupTestReorgDetector(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	header100 := createTestHeader(100, common.HexToHash("0x99"))
+	header101 := createTestHeader(101, header100.Hash())
+	header102 := createTestHeader(102, header101.Hash())
+	finalizedHeader := createTestHeader(50, common.HexToHash("0x49"))
+
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101, 102}).
+		Return([]*types.Header{header100, header101, header102}, nil).Once()
+
+	_, err := detector.VerifyAndRecordBlocks(ctx, nil, 100, 102)
+	require.NoError(t, err)
+
+	require.NoError(t, detector.HandleReorg(ctx, 101))
+	require.Equal(t, []uint64{100}, detector.headerCache.Keys())
+}
+
 func TestReorgDetector_VerifyAndRecordBlocks_ReorgBetweenRPCCalls(t *testing.T) {
 	t.Parallel()
 
@@ -253,8 +566,8 @@ func TestReorgDetector_VerifyAndRecordBlocks_ReorgBetweenRPCCalls(t *testing.T)
 	header101 := createTestHeader(101, header100.Hash())
 	finalizedHeader := createTestHeader(50, common.HexToHash("0x49"))
 
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader, nil).Once()
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{100, 101}).
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101}).
 		Return([]*types.Header{header100, header101}, nil).Once()
 
 	// Logs have different hash than headers (reorg happened between eth_getLogs and eth_getBlockByNumber)
@@ -286,8 +599,8 @@ func TestReorgDetector_VerifyAndRecordBlocks_ChainDiscontinuity(t *testing.T) {
 	header101 := createTestHeader(101, common.HexToHash("0xwrong")) // Wrong parent!
 	finalizedHeader := createTestHeader(50, common.HexToHash("0x49"))
 
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader, nil).Once()
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{100, 101}).
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101}).
 		Return([]*types.Header{header100, header101}, nil).Once()
 
 	logs := []types.Log{
@@ -319,8 +632,8 @@ func TestReorgDetector_VerifyAndRecordBlocks_PrunesFinalizedBlocks(t *testing.T)
 	header52 := createTestHeader(52, header51.Hash())
 	finalizedHeader40 := createTestHeader(40, common.HexToHash("0x39"))
 
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader40, nil).Once()
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{50, 51, 52}).
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader40, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{50, 51, 52}).
 		Return([]*types.Header{header50, header51, header52}, nil).Once()
 
 	logs := []types.Log{
@@ -336,12 +649,12 @@ func TestReorgDetector_VerifyAndRecordBlocks_PrunesFinalizedBlocks(t *testing.T)
 	// Now finalized block is 51, should prune blocks <= 51
 	header53 := createTestHeader(53, header52.Hash())
 
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(header51, nil).Once()
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(header51, nil).Once()
 	// Should verify only block 52 (non-finalized)
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{52}).
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{52}).
 		Return([]*types.Header{header52}, nil).Once()
 	// Then fetch new block 53
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{53}).
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{53}).
 		Return([]*types.Header{header53}, nil).Once()
 
 	logs2 := []types.Log{
@@ -381,8 +694,8 @@ func TestReorgDetector_VerifyAndRecordBlocks_EmptyLogs(t *testing.T) {
 	header101 := createTestHeader(101, header100.Hash())
 	finalizedHeader := createTestHeader(50, common.HexToHash("0x49"))
 
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader, nil).Once()
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{100, 101}).
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101}).
 		Return([]*types.Header{header100, header101}, nil).Once()
 
 	// Empty logs array (no logs in this range, but still need to verify blocks)
@@ -418,8 +731,8 @@ func TestReorgDetector_VerifyAndRecordBlocks_SingleBlock(t *testing.T) {
 	header100 := createTestHeader(100, common.HexToHash("0x99"))
 	finalizedHeader := createTestHeader(50, common.HexToHash("0x49"))
 
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader, nil).Once()
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{100}).
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100}).
 		Return([]*types.Header{header100}, nil).Once()
 
 	logs := []types.Log{
@@ -469,8 +782,8 @@ func TestReorgDetector_StoredBlockOperations(t *testing.T) {
 	header102 := createTestHeader(102, header101.Hash())
 	finalizedHeader := createTestHeader(50, common.HexToHash("0x49"))
 
-	mockRPC.EXPECT().GetFinalizedBlockHeader(ctx).Return(finalizedHeader, nil).Once()
-	mockRPC.EXPECT().BatchGetBlockHeaders(ctx, []uint64{100, 101, 102}).
+	mockRPC.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalizedHeader, nil).Once()
+	mockRPC.EXPECT().BatchGetBlockHeaders(mock.Anything, []uint64{100, 101, 102}).
 		Return([]*types.Header{header100, header101, header102}, nil).Once()
 
 	logs := []types.Log{
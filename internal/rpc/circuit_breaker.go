@@ -0,0 +1,194 @@
+package rpc
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+)
+
+// ErrCircuitOpen is returned without calling the node while the circuit breaker of its endpoint is open.
+var ErrCircuitOpen = errors.New("rpc circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every call through
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen fails every call immediately
+	CircuitOpen
+
+	// CircuitHalfOpen lets a single probe call through to check whether the node is back
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker stops calls to a node that keeps failing, so they fail fast instead of
+// piling up on an unreachable endpoint.
+//
+// The breaker opens after FailureThreshold consecutive failed attempts. Once OpenDuration
+// has passed it turns half-open and lets a single probe call through: a successful probe
+// closes the breaker and a failed one opens it again. Another probe is allowed if the
+// previous one has not completed within HalfOpenProbeInterval.
+// Only transient failures count, errors returned by the node mean it is reachable.
+type CircuitBreaker struct {
+	url string
+	cfg config.CircuitBreakerConfig
+
+	mu    sync.Mutex
+	now   func() time.Time
+	state CircuitState
+
+	// chainID labels the state metric of the breaker
+	chainID uint64
+
+	// failures is the number of consecutive failed attempts while closed
+	failures int
+
+	// openedAt is when the breaker last opened
+	openedAt time.Time
+
+	// probeStarted is when the pending probe call started, zero if there is none
+	probeStarted time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker for the node at the given URL.
+// Defaults are applied to the unset values of the configuration.
+func NewCircuitBreaker(url string, cfg config.CircuitBreakerConfig) *CircuitBreaker {
+	cfg.ApplyDefaults()
+
+	b := &CircuitBreaker{
+		url: nodeLabel(url),
+		cfg: cfg,
+		now: time.Now,
+	}
+	RPCCircuitBreakerStateSet(b.chainID, b.url, CircuitClosed)
+
+	return b
+}
+
+// SetChainID sets the ID of the chain the node serves, which labels the state metric of the breaker.
+// A nil breaker ignores it.
+func (b *CircuitBreaker) SetChainID(chainID uint64) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	RPCCircuitBreakerStateDelete(b.chainID, b.url)
+	b.chainID = chainID
+	RPCCircuitBreakerStateSet(b.chainID, b.url, b.state)
+}
+
+// State returns the current state of the breaker.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Call runs fn if the breaker lets the call through, and records its outcome.
+// It returns ErrCircuitOpen without running fn otherwise.
+// A nil breaker runs every call.
+func (b *CircuitBreaker) Call(fn func() error) error {
+	if b == nil {
+		return fn()
+	}
+
+	if err := b.allow(); err != nil {
+		return err
+	}
+
+	err := fn()
+	b.record(err)
+
+	return err
+}
+
+// allow reports whether a call may go through, turning the breaker half-open once it has been open long enough.
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+
+	switch b.state {
+	case CircuitClosed:
+		return nil
+	case CircuitOpen:
+		if now.Sub(b.openedAt) < b.cfg.OpenDuration.Duration {
+			return ErrCircuitOpen
+		}
+		b.setState(CircuitHalfOpen)
+	case CircuitHalfOpen:
+		if !b.probeStarted.IsZero() && now.Sub(b.probeStarted) < b.cfg.HalfOpenProbeInterval.Duration {
+			return ErrCircuitOpen
+		}
+	}
+
+	b.probeStarted = now
+
+	return nil
+}
+
+// record updates the breaker with the outcome of a call it let through.
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil || !retryableError(err) {
+		b.failures = 0
+		b.probeStarted = time.Time{}
+		b.setState(CircuitClosed)
+		return
+	}
+
+	switch b.state {
+	case CircuitClosed:
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.open()
+		}
+	case CircuitHalfOpen:
+		b.open()
+	case CircuitOpen:
+		// A call let through before the breaker opened, the breaker is already open
+	}
+}
+
+// open opens the breaker, failing calls until OpenDuration has passed.
+func (b *CircuitBreaker) open() {
+	b.failures = 0
+	b.probeStarted = time.Time{}
+	b.openedAt = b.now()
+	b.setState(CircuitOpen)
+}
+
+// setState changes the state of the breaker and exports it.
+func (b *CircuitBreaker) setState(state CircuitState) {
+	if b.state == state {
+		return
+	}
+
+	b.state = state
+	RPCCircuitBreakerStateSet(b.chainID, b.url, state)
+}
@@ -0,0 +1,144 @@
+package rpc
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCircuitBreaker creates a breaker opening after 3 failures for 30s, whose clock is advanced by the test.
+func newTestCircuitBreaker(t *testing.T) (*CircuitBreaker, func(time.Duration)) {
+	t.Helper()
+
+	breaker := NewCircuitBreaker("http://"+t.Name(), config.CircuitBreakerConfig{
+		FailureThreshold:      3,
+		OpenDuration:          common.NewDuration(30 * time.Second),
+		HalfOpenProbeInterval: common.NewDuration(5 * time.Second),
+	})
+
+	now := time.Unix(1_700_000_000, 0)
+	breaker.now = func() time.Time { return now }
+
+	return breaker, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	succeed := func() error { calls++; return nil }
+	fail := func() error { calls++; return syscall.ECONNREFUSED }
+
+	breaker, advance := newTestCircuitBreaker(t)
+	require.Equal(t, CircuitClosed, breaker.State())
+
+	// Closed -> Open after 3 consecutive failures, a success in between resets the count
+	require.Error(t, breaker.Call(fail))
+	require.Error(t, breaker.Call(fail))
+	require.NoError(t, breaker.Call(succeed))
+	require.Error(t, breaker.Call(fail))
+	require.Error(t, breaker.Call(fail))
+	require.Equal(t, CircuitClosed, breaker.State())
+	require.Error(t, breaker.Call(fail))
+	require.Equal(t, CircuitOpen, breaker.State())
+
+	// Open calls fail without reaching the node
+	calls = 0
+	advance(29 * time.Second)
+	require.ErrorIs(t, breaker.Call(succeed), ErrCircuitOpen)
+	require.Zero(t, calls)
+
+	// Open -> HalfOpen -> Open when the probe fails
+	advance(time.Second)
+	require.ErrorIs(t, breaker.Call(fail), syscall.ECONNREFUSED)
+	require.Equal(t, 1, calls)
+	require.Equal(t, CircuitOpen, breaker.State())
+	require.ErrorIs(t, breaker.Call(succeed), ErrCircuitOpen)
+
+	// Open -> HalfOpen -> Closed when the probe succeeds
+	advance(30 * time.Second)
+	require.NoError(t, breaker.Call(succeed))
+	require.Equal(t, CircuitClosed, breaker.State())
+	require.NoError(t, breaker.Call(succeed))
+}
+
+func TestCircuitBreaker_SingleProbe(t *testing.T) {
+	t.Parallel()
+
+	breaker, advance := newTestCircuitBreaker(t)
+	for range 3 {
+		require.Error(t, breaker.Call(func() error { return syscall.ECONNRESET }))
+	}
+	advance(30 * time.Second)
+
+	// While the probe is pending, other calls fail fast
+	err := breaker.Call(func() error {
+		require.Equal(t, CircuitHalfOpen, breaker.State())
+		require.ErrorIs(t, breaker.Call(func() error { return nil }), ErrCircuitOpen)
+
+		// A probe that has not completed within the probe interval does not block another one
+		advance(5 * time.Second)
+		require.NoError(t, breaker.Call(func() error { return nil }))
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, CircuitClosed, breaker.State())
+}
+
+func TestCircuitBreaker_NodeErrorsDoNotCount(t *testing.T) {
+	t.Parallel()
+
+	breaker, _ := newTestCircuitBreaker(t)
+	for range 5 {
+		require.Error(t, breaker.Call(func() error { return jsonRPCError{} }))
+	}
+	require.Equal(t, CircuitClosed, breaker.State())
+}
+
+func TestCircuitBreaker_Nil(t *testing.T) {
+	t.Parallel()
+
+	var breaker *CircuitBreaker
+	expected := errors.New("failed")
+	require.ErrorIs(t, breaker.Call(func() error { return expected }), expected)
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	retry := &config.RetryConfig{MaxAttempts: 10}
+	retry.ApplyDefaults()
+	retry.InitialBackoff = common.NewDuration(time.Millisecond)
+	retry.MaxBackoff = common.NewDuration(time.Millisecond)
+
+	client, err := NewClient(t.Context(), server.URL, retry, &config.CircuitBreakerConfig{FailureThreshold: 3})
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	// The breaker opens after 3 attempts and stops the retries
+	_, err = client.GetLatestBlockHeader(t.Context())
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.EqualValues(t, 3, requests.Load())
+	require.Equal(t, CircuitOpen, client.breaker.State())
+
+	// Later calls fail without reaching the node
+	_, err = client.GetLatestBlockHeader(t.Context())
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.EqualValues(t, 3, requests.Load())
+}
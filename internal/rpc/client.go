@@ -4,39 +4,80 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/goran-ethernal/ChainIndexor/internal/tracing"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
 	pkgrpc "github.com/goran-ethernal/ChainIndexor/pkg/rpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Compile-time check to ensure Client implements pkgrpc.EthClient interface.
 var _ pkgrpc.EthClient = (*Client)(nil)
 
+// Compile-time check to ensure Client implements pkgrpc.PendingClient interface.
+var _ pkgrpc.PendingClient = (*Client)(nil)
+
+// Compile-time check to ensure Client implements pkgrpc.HeadClient interface.
+var _ pkgrpc.HeadClient = (*Client)(nil)
+
 // Client wraps the Ethereum RPC client with convenience methods for indexing.
 // It implements the pkgrpc.EthClient interface.
 type Client struct {
 	eth         *ethclient.Client
 	rpc         *rpc.Client
 	retryConfig *config.RetryConfig
+	breaker     *CircuitBreaker
+
+	// chainID labels the metrics of the client's calls, 0 until it is set with SetChainID
+	chainID atomic.Uint64
 }
 
 // NewClient creates a new RPC client connected to the given endpoint.
-func NewClient(ctx context.Context, endpoint string, retryConfig *config.RetryConfig) (*Client, error) {
+// Every attempt of a call goes through a circuit breaker if breakerConfig is set.
+func NewClient(
+	ctx context.Context,
+	endpoint string,
+	retryConfig *config.RetryConfig,
+	breakerConfig *config.CircuitBreakerConfig,
+) (*Client, error) {
 	rpcClient, err := rpc.DialContext(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Client{
+	client := &Client{
 		eth:         ethclient.NewClient(rpcClient),
 		rpc:         rpcClient,
 		retryConfig: retryConfig,
-	}, nil
+	}
+	if breakerConfig != nil {
+		client.breaker = NewCircuitBreaker(endpoint, *breakerConfig)
+	}
+
+	return client, nil
+}
+
+// SetChainID sets the ID of the chain the node serves, which labels the metrics of the client and its
+// circuit breaker. Calls made before it is set, like the one identifying the chain, are labelled 0.
+func (c *Client) SetChainID(chainID uint64) {
+	c.chainID.Store(chainID)
+	c.breaker.SetChainID(chainID)
+}
+
+// retry runs fn with the retries of the retry configuration, each attempt guarded by the circuit breaker.
+// An open breaker fails the call without further attempts.
+func (c *Client) retry(ctx context.Context, operation string, fn func() error) error {
+	return retryWithBackoff(ctx, c.retryConfig, c.chainID.Load(), operation, func() error {
+		return c.breaker.Call(fn)
+	})
 }
 
 // Close closes the RPC client connection.
@@ -44,23 +85,60 @@ func (c *Client) Close() {
 	c.eth.Close()
 }
 
+// ChainID retrieves the ID of the chain the node serves.
+func (c *Client) ChainID(ctx context.Context) (_ uint64, err error) {
+	start := time.Now()
+	RPCMethodInc(c.chainID.Load(), "eth_chainId")
+	defer func() {
+		RPCMethodDuration(c.chainID.Load(), "eth_chainId", time.Since(start), err)
+	}()
+
+	var chainID *big.Int
+	err = c.retry(ctx, "eth_chainId", func() error {
+		var fetchErr error
+		chainID, fetchErr = c.eth.ChainID(ctx)
+		return fetchErr
+	})
+
+	if err != nil {
+		RPCMethodError(c.chainID.Load(), "eth_chainId", "error")
+		return 0, err
+	}
+
+	return chainID.Uint64(), nil
+}
+
 // GetLogs retrieves logs matching the given filter query.
-func (c *Client) GetLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+func (c *Client) GetLogs(ctx context.Context, query ethereum.FilterQuery) (_ []types.Log, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "RPCClient.GetLogs", trace.WithAttributes(
+		attribute.String("rpc.method", "eth_getLogs"),
+		attribute.Int("addresses", len(query.Addresses)),
+	))
+	if query.FromBlock != nil && query.ToBlock != nil {
+		span.SetAttributes(
+			attribute.Int64("from_block", query.FromBlock.Int64()),
+			attribute.Int64("to_block", query.ToBlock.Int64()),
+		)
+	}
+
 	start := time.Now()
-	RPCMethodInc("eth_getLogs")
+	RPCMethodInc(c.chainID.Load(), "eth_getLogs")
 	defer func() {
-		RPCMethodDuration("eth_getLogs", time.Since(start))
+		RPCMethodDuration(c.chainID.Load(), "eth_getLogs", time.Since(start), err)
 	}()
 
 	var logs []types.Log
-	err := retryWithBackoff(ctx, c.retryConfig, "eth_getLogs", func() error {
+	err = c.retry(ctx, "eth_getLogs", func() error {
 		var fetchErr error
 		logs, fetchErr = c.eth.FilterLogs(ctx, query)
 		return fetchErr
 	})
 
+	span.SetAttributes(attribute.Int("logs", len(logs)))
+	tracing.EndSpan(span, err)
+
 	if err != nil {
-		RPCMethodError("eth_getLogs", "error")
+		RPCMethodError(c.chainID.Load(), "eth_getLogs", "error")
 		return nil, err
 	}
 
@@ -68,22 +146,29 @@ func (c *Client) GetLogs(ctx context.Context, query ethereum.FilterQuery) ([]typ
 }
 
 // GetBlockHeader retrieves the header for a specific block number.
-func (c *Client) GetBlockHeader(ctx context.Context, blockNum uint64) (*types.Header, error) {
+func (c *Client) GetBlockHeader(ctx context.Context, blockNum uint64) (_ *types.Header, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "RPCClient.GetBlockHeader", trace.WithAttributes(
+		attribute.String("rpc.method", "eth_getBlockByNumber"),
+		attribute.Int64("block", int64(blockNum)),
+	))
+
 	start := time.Now()
-	RPCMethodInc("eth_getBlockByNumber")
+	RPCMethodInc(c.chainID.Load(), "eth_getBlockByNumber")
 	defer func() {
-		RPCMethodDuration("eth_getBlockByNumber", time.Since(start))
+		RPCMethodDuration(c.chainID.Load(), "eth_getBlockByNumber", time.Since(start), err)
 	}()
 
 	var header *types.Header
-	err := retryWithBackoff(ctx, c.retryConfig, "eth_getBlockByNumber", func() error {
+	err = c.retry(ctx, "eth_getBlockByNumber", func() error {
 		var fetchErr error
 		header, fetchErr = c.eth.HeaderByNumber(ctx, big.NewInt(int64(blockNum)))
 		return fetchErr
 	})
 
+	tracing.EndSpan(span, err)
+
 	if err != nil {
-		RPCMethodError("eth_getBlockByNumber", "error")
+		RPCMethodError(c.chainID.Load(), "eth_getBlockByNumber", "error")
 		return nil, err
 	}
 
@@ -91,22 +176,22 @@ func (c *Client) GetBlockHeader(ctx context.Context, blockNum uint64) (*types.He
 }
 
 // GetLatestBlockHeader retrieves the latest block header.
-func (c *Client) GetLatestBlockHeader(ctx context.Context) (*types.Header, error) {
+func (c *Client) GetLatestBlockHeader(ctx context.Context) (_ *types.Header, err error) {
 	start := time.Now()
-	RPCMethodInc("eth_getBlockByNumber")
+	RPCMethodInc(c.chainID.Load(), "eth_getBlockByNumber")
 	defer func() {
-		RPCMethodDuration("eth_getBlockByNumber", time.Since(start))
+		RPCMethodDuration(c.chainID.Load(), "eth_getBlockByNumber", time.Since(start), err)
 	}()
 
 	var header *types.Header
-	err := retryWithBackoff(ctx, c.retryConfig, "eth_getBlockByNumber", func() error {
+	err = c.retry(ctx, "eth_getBlockByNumber", func() error {
 		var fetchErr error
 		header, fetchErr = c.eth.HeaderByNumber(ctx, nil)
 		return fetchErr
 	})
 
 	if err != nil {
-		RPCMethodError("eth_getBlockByNumber", "error")
+		RPCMethodError(c.chainID.Load(), "eth_getBlockByNumber", "error")
 		return nil, err
 	}
 
@@ -114,22 +199,22 @@ func (c *Client) GetLatestBlockHeader(ctx context.Context) (*types.Header, error
 }
 
 // GetFinalizedBlockHeader retrieves the finalized block header.
-func (c *Client) GetFinalizedBlockHeader(ctx context.Context) (*types.Header, error) {
+func (c *Client) GetFinalizedBlockHeader(ctx context.Context) (_ *types.Header, err error) {
 	start := time.Now()
-	RPCMethodInc("eth_getBlockByNumber")
+	RPCMethodInc(c.chainID.Load(), "eth_getBlockByNumber")
 	defer func() {
-		RPCMethodDuration("eth_getBlockByNumber", time.Since(start))
+		RPCMethodDuration(c.chainID.Load(), "eth_getBlockByNumber", time.Since(start), err)
 	}()
 
 	var header *types.Header
-	err := retryWithBackoff(ctx, c.retryConfig, "eth_getBlockByNumber", func() error {
+	err = c.retry(ctx, "eth_getBlockByNumber", func() error {
 		var fetchErr error
 		header, fetchErr = c.eth.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
 		return fetchErr
 	})
 
 	if err != nil {
-		RPCMethodError("eth_getBlockByNumber", "error")
+		RPCMethodError(c.chainID.Load(), "eth_getBlockByNumber", "error")
 		return nil, err
 	}
 
@@ -137,38 +222,62 @@ func (c *Client) GetFinalizedBlockHeader(ctx context.Context) (*types.Header, er
 }
 
 // GetSafeBlockHeader retrieves the safe block header.
-func (c *Client) GetSafeBlockHeader(ctx context.Context) (*types.Header, error) {
+func (c *Client) GetSafeBlockHeader(ctx context.Context) (_ *types.Header, err error) {
 	start := time.Now()
-	RPCMethodInc("eth_getBlockByNumber")
+	RPCMethodInc(c.chainID.Load(), "eth_getBlockByNumber")
 	defer func() {
-		RPCMethodDuration("eth_getBlockByNumber", time.Since(start))
+		RPCMethodDuration(c.chainID.Load(), "eth_getBlockByNumber", time.Since(start), err)
 	}()
 
 	var header *types.Header
-	err := retryWithBackoff(ctx, c.retryConfig, "eth_getBlockByNumber", func() error {
+	err = c.retry(ctx, "eth_getBlockByNumber", func() error {
 		var fetchErr error
 		header, fetchErr = c.eth.HeaderByNumber(ctx, big.NewInt(int64(rpc.SafeBlockNumber)))
 		return fetchErr
 	})
 
 	if err != nil {
-		RPCMethodError("eth_getBlockByNumber", "error")
+		RPCMethodError(c.chainID.Load(), "eth_getBlockByNumber", "error")
 		return nil, err
 	}
 
 	return header, nil
 }
 
+// GetContractCode retrieves the code of the contract at the given address as of the given block.
+// A nil block number reads the latest block.
+func (c *Client) GetContractCode(ctx context.Context, address common.Address, blockNum *big.Int) (_ []byte, err error) {
+	start := time.Now()
+	RPCMethodInc(c.chainID.Load(), "eth_getCode")
+	defer func() {
+		RPCMethodDuration(c.chainID.Load(), "eth_getCode", time.Since(start), err)
+	}()
+
+	var code []byte
+	err = c.retry(ctx, "eth_getCode", func() error {
+		var fetchErr error
+		code, fetchErr = c.eth.CodeAt(ctx, address, blockNum)
+		return fetchErr
+	})
+
+	if err != nil {
+		RPCMethodError(c.chainID.Load(), "eth_getCode", "error")
+		return nil, err
+	}
+
+	return code, nil
+}
+
 // BatchGetLogs retrieves logs for multiple filter queries in a single batch call.
-func (c *Client) BatchGetLogs(ctx context.Context, queries []ethereum.FilterQuery) ([][]types.Log, error) {
+func (c *Client) BatchGetLogs(ctx context.Context, queries []ethereum.FilterQuery) (_ [][]types.Log, err error) {
 	start := time.Now()
-	RPCMethodInc("eth_getLogs_batch")
+	RPCMethodInc(c.chainID.Load(), "eth_getLogs_batch")
 	defer func() {
-		RPCMethodDuration("eth_getLogs_batch", time.Since(start))
+		RPCMethodDuration(c.chainID.Load(), "eth_getLogs_batch", time.Since(start), err)
 	}()
 
 	var results [][]types.Log
-	err := retryWithBackoff(ctx, c.retryConfig, "eth_getLogs_batch", func() error {
+	err = c.retry(ctx, "eth_getLogs_batch", func() error {
 		batch := make([]rpc.BatchElem, len(queries))
 		results = make([][]types.Log, len(queries))
 
@@ -195,7 +304,7 @@ func (c *Client) BatchGetLogs(ctx context.Context, queries []ethereum.FilterQuer
 	})
 
 	if err != nil {
-		RPCMethodError("eth_getLogs_batch", "error")
+		RPCMethodError(c.chainID.Load(), "eth_getLogs_batch", "error")
 		return nil, err
 	}
 
@@ -203,14 +312,20 @@ func (c *Client) BatchGetLogs(ctx context.Context, queries []ethereum.FilterQuer
 }
 
 // BatchGetBlockHeaders retrieves headers for multiple block numbers in a single batch call.
-func (c *Client) BatchGetBlockHeaders(ctx context.Context, blockNums []uint64) ([]*types.Header, error) {
+func (c *Client) BatchGetBlockHeaders(ctx context.Context, blockNums []uint64) (_ []*types.Header, err error) {
 	const maxBatch = 100
 	var allResults []*types.Header
 
+	ctx, span := tracing.Tracer().Start(ctx, "RPCClient.BatchGetBlockHeaders", trace.WithAttributes(
+		attribute.String("rpc.method", "eth_getBlockByNumber_batch"),
+		attribute.Int("blocks", len(blockNums)),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	start := time.Now()
-	RPCMethodInc("eth_getBlockByNumber_batch")
+	RPCMethodInc(c.chainID.Load(), "eth_getBlockByNumber_batch")
 	defer func() {
-		RPCMethodDuration("eth_getBlockByNumber_batch", time.Since(start))
+		RPCMethodDuration(c.chainID.Load(), "eth_getBlockByNumber_batch", time.Since(start), err)
 	}()
 
 	for i := 0; i < len(blockNums); i += maxBatch {
@@ -218,7 +333,7 @@ func (c *Client) BatchGetBlockHeaders(ctx context.Context, blockNums []uint64) (
 		chunk := blockNums[i:end]
 
 		var chunkResults []*types.Header
-		err := retryWithBackoff(ctx, c.retryConfig, "eth_getBlockByNumber_batch", func() error {
+		err = c.retry(ctx, "eth_getBlockByNumber_batch", func() error {
 			batch := make([]rpc.BatchElem, len(chunk))
 			chunkResults = make([]*types.Header, len(chunk))
 
@@ -245,7 +360,7 @@ func (c *Client) BatchGetBlockHeaders(ctx context.Context, blockNums []uint64) (
 		})
 
 		if err != nil {
-			RPCMethodError("eth_getBlockByNumber_batch", "error")
+			RPCMethodError(c.chainID.Load(), "eth_getBlockByNumber_batch", "error")
 			return nil, err
 		}
 
@@ -255,6 +370,100 @@ func (c *Client) BatchGetBlockHeaders(ctx context.Context, blockNums []uint64) (
 	return allResults, nil
 }
 
+// SubscribePendingTransactions subscribes to transactions entering the node's mempool.
+// Subscriptions require a websocket or IPC endpoint.
+func (c *Client) SubscribePendingTransactions(
+	ctx context.Context,
+	ch chan<- *types.Transaction,
+) (_ ethereum.Subscription, err error) {
+	start := time.Now()
+	RPCMethodInc(c.chainID.Load(), "eth_subscribe")
+	defer func() {
+		RPCMethodDuration(c.chainID.Load(), "eth_subscribe", time.Since(start), err)
+	}()
+
+	// true requests full transactions instead of hashes, saving a lookup per transaction
+	sub, err := c.rpc.EthSubscribe(ctx, ch, "newPendingTransactions", true)
+	if err != nil {
+		RPCMethodError(c.chainID.Load(), "eth_subscribe", "error")
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// SubscribeNewHeads subscribes to the headers of blocks added to the head of the chain.
+// Subscriptions require a websocket or IPC endpoint.
+func (c *Client) SubscribeNewHeads(ctx context.Context, ch chan<- *types.Header) (_ ethereum.Subscription, err error) {
+	start := time.Now()
+	RPCMethodInc(c.chainID.Load(), "eth_subscribe")
+	defer func() {
+		RPCMethodDuration(c.chainID.Load(), "eth_subscribe", time.Since(start), err)
+	}()
+
+	sub, err := c.eth.SubscribeNewHead(ctx, ch)
+	if err != nil {
+		RPCMethodError(c.chainID.Load(), "eth_subscribe", "error")
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// SimulateTransaction executes the transaction from the given sender on top of the latest
+// block without submitting it, and returns the logs it would emit.
+// eth_call does not return logs, so the transaction is executed with eth_simulateV1,
+// which runs the same call and additionally reports the emitted logs.
+// A reverting transaction emits no logs.
+func (c *Client) SimulateTransaction(
+	ctx context.Context,
+	from common.Address,
+	tx *types.Transaction,
+) (_ []types.Log, err error) {
+	start := time.Now()
+	RPCMethodInc(c.chainID.Load(), "eth_simulateV1")
+	defer func() {
+		RPCMethodDuration(c.chainID.Load(), "eth_simulateV1", time.Since(start), err)
+	}()
+
+	opts := ethclient.SimulateOptions{
+		BlockStateCalls: []ethclient.SimulateBlock{{
+			Calls: []ethereum.CallMsg{{
+				From:       from,
+				To:         tx.To(),
+				Gas:        tx.Gas(),
+				Value:      tx.Value(),
+				Data:       tx.Data(),
+				AccessList: tx.AccessList(),
+			}},
+		}},
+	}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	var results []ethclient.SimulateBlockResult
+	err = c.retry(ctx, "eth_simulateV1", func() error {
+		var simulateErr error
+		results, simulateErr = c.eth.SimulateV1(ctx, opts, &latest)
+		return simulateErr
+	})
+
+	if err != nil {
+		RPCMethodError(c.chainID.Load(), "eth_simulateV1", "error")
+		return nil, err
+	}
+
+	var logs []types.Log
+	for _, block := range results {
+		for _, call := range block.Calls {
+			for _, log := range call.Logs {
+				logs = append(logs, *log)
+			}
+		}
+	}
+
+	return logs, nil
+}
+
 // toFilterArg converts ethereum.FilterQuery to the format expected by eth_getLogs.
 func toFilterArg(q ethereum.FilterQuery) any {
 	arg := map[string]any{
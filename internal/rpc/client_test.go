@@ -1,11 +1,20 @@
 package rpc
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	pkgrpc "github.com/goran-ethernal/ChainIndexor/pkg/rpc"
 	"github.com/stretchr/testify/require"
 )
@@ -268,3 +277,119 @@ func TestToFilterArg_BlockHashTakesPrecedence(t *testing.T) {
 	require.NotContains(t, m, "fromBlock", "fromBlock should not be present when blockHash is set")
 	require.NotContains(t, m, "toBlock", "toBlock should not be present when blockHash is set")
 }
+
+type jsonRPCRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+}
+
+// newFakeNode starts a JSON-RPC server answering eth_getLogs with no logs and
+// eth_getBlockByNumber with an empty header, for single and batch requests.
+func newFakeNode(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0)}
+
+	respond := func(req jsonRPCRequest) jsonRPCResponse {
+		resp := jsonRPCResponse{JSONRPC: "2.0", ID: req.ID}
+		switch req.Method {
+		case "eth_getLogs":
+			resp.Result = []types.Log{}
+		case "eth_getBlockByNumber":
+			resp.Result = header
+		}
+
+		return resp
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body json.RawMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+			var reqs []jsonRPCRequest
+			require.NoError(t, json.Unmarshal(body, &reqs))
+
+			resps := make([]jsonRPCResponse, len(reqs))
+			for i, req := range reqs {
+				resps[i] = respond(req)
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(resps))
+
+			return
+		}
+
+		var req jsonRPCRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+		require.NoError(t, json.NewEncoder(w).Encode(respond(req)))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// fakeHeadsService serves the newHeads subscription of eth_subscribe, pushing its headers to every subscriber.
+type fakeHeadsService struct {
+	headers []*types.Header
+}
+
+func (s *fakeHeadsService) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	sub := notifier.CreateSubscription()
+	go func() {
+		for _, header := range s.headers {
+			if err := notifier.Notify(sub.ID, header); err != nil {
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+func TestClient_SubscribeNewHeads(t *testing.T) {
+	t.Parallel()
+
+	headers := make([]*types.Header, 3)
+	for i := range headers {
+		headers[i] = &types.Header{Number: big.NewInt(int64(100 + i)), Difficulty: big.NewInt(0)}
+	}
+
+	node := rpc.NewServer()
+	require.NoError(t, node.RegisterName("eth", &fakeHeadsService{headers: headers}))
+	t.Cleanup(node.Stop)
+
+	server := httptest.NewServer(node.WebsocketHandler([]string{"*"}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(t.Context(), "ws"+strings.TrimPrefix(server.URL, "http"), nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	ch := make(chan *types.Header, len(headers))
+	sub, err := client.SubscribeNewHeads(t.Context(), ch)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	for _, want := range headers {
+		select {
+		case got := <-ch:
+			require.Equal(t, want.Hash(), got.Hash())
+		case err := <-sub.Err():
+			require.FailNow(t, "subscription failed", err)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for new head")
+		}
+	}
+}
@@ -0,0 +1,57 @@
+//go:build tracing
+
+package rpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/goran-ethernal/ChainIndexor/internal/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestClient_EmitsSpans(t *testing.T) {
+	// The spans are recorded through the global tracer provider, so this test does not run in parallel
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	client, err := NewClient(t.Context(), newFakeNode(t).URL, nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	ctx, parent := tracing.Tracer().Start(t.Context(), "parent")
+
+	_, err = client.GetLogs(ctx, ethereum.FilterQuery{FromBlock: big.NewInt(1), ToBlock: big.NewInt(10)})
+	require.NoError(t, err)
+
+	_, err = client.GetBlockHeader(ctx, 1)
+	require.NoError(t, err)
+
+	_, err = client.BatchGetBlockHeaders(ctx, []uint64{1, 2, 3})
+	require.NoError(t, err)
+
+	parent.End()
+
+	spans := recorder.Ended()
+	names := make([]string, 0, len(spans))
+	for _, span := range spans {
+		names = append(names, span.Name())
+	}
+	require.Equal(t, []string{
+		"RPCClient.GetLogs",
+		"RPCClient.GetBlockHeader",
+		"RPCClient.BatchGetBlockHeaders",
+		"parent",
+	}, names)
+
+	// The RPC spans are children of the span of the caller
+	for _, span := range spans[:3] {
+		require.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+}
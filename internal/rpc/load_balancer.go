@@ -0,0 +1,373 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	pkgrpc "github.com/goran-ethernal/ChainIndexor/pkg/rpc"
+)
+
+const (
+	// nodeInitialBackoff is how long a node is skipped after its first transport failure
+	nodeInitialBackoff = time.Second
+
+	// nodeMaxBackoff caps how long a repeatedly failing node is skipped
+	nodeMaxBackoff = time.Minute
+)
+
+// Compile-time check to ensure LoadBalancedClient implements pkgrpc.EthClient interface.
+var _ pkgrpc.EthClient = (*LoadBalancedClient)(nil)
+
+// Compile-time check to ensure LoadBalancedClient implements pkgrpc.PendingClient interface.
+var _ pkgrpc.PendingClient = (*LoadBalancedClient)(nil)
+
+// Compile-time check to ensure LoadBalancedClient implements pkgrpc.HeadClient interface.
+var _ pkgrpc.HeadClient = (*LoadBalancedClient)(nil)
+
+// nodeClient is the client of a single node behind the load balancer.
+type nodeClient interface {
+	pkgrpc.EthClient
+	pkgrpc.PendingClient
+	pkgrpc.HeadClient
+
+	ChainID(ctx context.Context) (uint64, error)
+	SetChainID(chainID uint64)
+}
+
+// node is an RPC endpoint behind the load balancer and its health.
+type node struct {
+	url    string
+	client nodeClient
+
+	// failures is the number of consecutive transport failures of the node
+	failures int
+
+	// unhealthyUntil is when the node is tried again after a transport failure
+	unhealthyUntil time.Time
+}
+
+// LoadBalancedClient dispatches calls round-robin across several nodes. A node whose call
+// fails with a transport error, after the retries of its own client, is skipped for an
+// exponentially growing back-off window and the call is retried on the next healthy node.
+// Errors returned by a node, such as reverts or unknown blocks, are returned as they are.
+type LoadBalancedClient struct {
+	nodes []*node
+	next  atomic.Uint64
+
+	mu  sync.Mutex
+	now func() time.Time
+
+	// chainID labels the node health metrics, guarded by mu
+	chainID uint64
+}
+
+// NewLoadBalancedClient creates a client balancing calls across the nodes at the given URLs.
+// Calls are retried on each node according to the retry configuration before failing over,
+// and fail over immediately while the circuit breaker of the node is open.
+func NewLoadBalancedClient(
+	ctx context.Context,
+	urls []string,
+	retryConfig *config.RetryConfig,
+	breakerConfig *config.CircuitBreakerConfig,
+) (*LoadBalancedClient, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("at least one RPC URL is required")
+	}
+
+	clients := make([]nodeClient, 0, len(urls))
+	for _, endpoint := range urls {
+		client, err := NewClient(ctx, endpoint, retryConfig, breakerConfig)
+		if err != nil {
+			for _, c := range clients {
+				c.Close()
+			}
+
+			return nil, fmt.Errorf("failed to connect to %s: %w", nodeLabel(endpoint), err)
+		}
+
+		clients = append(clients, client)
+	}
+
+	return newLoadBalancedClient(urls, clients), nil
+}
+
+// newLoadBalancedClient creates a load balancer over the given node clients.
+func newLoadBalancedClient(urls []string, clients []nodeClient) *LoadBalancedClient {
+	b := &LoadBalancedClient{
+		nodes: make([]*node, len(clients)),
+		now:   time.Now,
+	}
+
+	for i, client := range clients {
+		b.nodes[i] = &node{url: nodeLabel(urls[i]), client: client}
+		RPCNodeHealthySet(b.chainID, b.nodes[i].url, true)
+	}
+
+	return b
+}
+
+// SetChainID sets the ID of the chain the nodes serve, which labels the metrics of the load balancer
+// and of the clients of its nodes.
+func (b *LoadBalancedClient) SetChainID(chainID uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, n := range b.nodes {
+		n.client.SetChainID(chainID)
+		RPCNodeHealthyDelete(b.chainID, n.url)
+		RPCNodeHealthySet(chainID, n.url, n.failures == 0)
+	}
+	b.chainID = chainID
+}
+
+// candidates returns the healthy nodes in round-robin order. If no node is healthy,
+// it returns the node whose back-off window ends first, so calls are never refused.
+func (b *LoadBalancedClient) candidates() []*node {
+	start := int(b.next.Add(1)-1) % len(b.nodes)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+
+	var (
+		healthy []*node
+		soonest *node
+	)
+
+	for i := range b.nodes {
+		n := b.nodes[(start+i)%len(b.nodes)]
+		if !now.Before(n.unhealthyUntil) {
+			healthy = append(healthy, n)
+			continue
+		}
+
+		if soonest == nil || n.unhealthyUntil.Before(soonest.unhealthyUntil) {
+			soonest = n
+		}
+	}
+
+	if len(healthy) == 0 {
+		return []*node{soonest}
+	}
+
+	return healthy
+}
+
+// markHealthy records a successful call to the node.
+func (b *LoadBalancedClient) markHealthy(n *node) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n.failures == 0 {
+		return
+	}
+
+	n.failures = 0
+	n.unhealthyUntil = time.Time{}
+	RPCNodeHealthySet(b.chainID, n.url, true)
+}
+
+// markUnhealthy skips the node for a back-off window that doubles with every consecutive failure.
+func (b *LoadBalancedClient) markUnhealthy(n *node) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	backoff := nodeMaxBackoff
+	if n.failures < 16 { //nolint:mnd // larger shifts are capped anyway
+		backoff = min(nodeInitialBackoff<<n.failures, nodeMaxBackoff)
+	}
+
+	n.failures++
+	n.unhealthyUntil = b.now().Add(backoff)
+	RPCNodeHealthySet(b.chainID, n.url, false)
+}
+
+// call dispatches fn to the healthy nodes in turn until one of them does not fail with a transport error.
+func call[T any](ctx context.Context, b *LoadBalancedClient, fn func(client nodeClient) (T, error)) (T, error) {
+	var (
+		result T
+		err    error
+	)
+
+	for _, n := range b.candidates() {
+		result, err = fn(n.client)
+		if err == nil {
+			b.markHealthy(n)
+			return result, nil
+		}
+
+		if !transportError(ctx, err) {
+			return result, err
+		}
+
+		b.markUnhealthy(n)
+	}
+
+	return result, err
+}
+
+// transportError reports whether the error is a failure to reach the node,
+// rather than an error returned by the node.
+func transportError(ctx context.Context, err error) bool {
+	// The caller gave up, the node is not to blame
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+
+	var jsonErr rpc.Error
+	if errors.As(err, &jsonErr) {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return true
+	}
+
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || retryableError(err)
+}
+
+// nodeLabel returns the URL without its credentials, to identify a node in errors and metrics.
+func nodeLabel(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.User == nil {
+		return endpoint
+	}
+
+	u.User = nil
+
+	return u.String()
+}
+
+// Nodes returns the URLs of the nodes without their credentials, so they can be logged.
+func (b *LoadBalancedClient) Nodes() []string {
+	urls := make([]string, len(b.nodes))
+	for i, n := range b.nodes {
+		urls[i] = n.url
+	}
+
+	return urls
+}
+
+// Close closes the connections to all nodes.
+func (b *LoadBalancedClient) Close() {
+	for _, n := range b.nodes {
+		n.client.Close()
+	}
+}
+
+// ChainID retrieves the ID of the chain the nodes serve.
+func (b *LoadBalancedClient) ChainID(ctx context.Context) (uint64, error) {
+	return call(ctx, b, func(client nodeClient) (uint64, error) {
+		return client.ChainID(ctx)
+	})
+}
+
+// GetLogs retrieves logs matching the given filter query.
+func (b *LoadBalancedClient) GetLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return call(ctx, b, func(client nodeClient) ([]types.Log, error) {
+		return client.GetLogs(ctx, query)
+	})
+}
+
+// GetBlockHeader retrieves the header for a specific block number.
+func (b *LoadBalancedClient) GetBlockHeader(ctx context.Context, blockNum uint64) (*types.Header, error) {
+	return call(ctx, b, func(client nodeClient) (*types.Header, error) {
+		return client.GetBlockHeader(ctx, blockNum)
+	})
+}
+
+// GetLatestBlockHeader retrieves the latest block header.
+func (b *LoadBalancedClient) GetLatestBlockHeader(ctx context.Context) (*types.Header, error) {
+	return call(ctx, b, func(client nodeClient) (*types.Header, error) {
+		return client.GetLatestBlockHeader(ctx)
+	})
+}
+
+// GetFinalizedBlockHeader retrieves the finalized block header.
+func (b *LoadBalancedClient) GetFinalizedBlockHeader(ctx context.Context) (*types.Header, error) {
+	return call(ctx, b, func(client nodeClient) (*types.Header, error) {
+		return client.GetFinalizedBlockHeader(ctx)
+	})
+}
+
+// GetSafeBlockHeader retrieves the safe block header.
+func (b *LoadBalancedClient) GetSafeBlockHeader(ctx context.Context) (*types.Header, error) {
+	return call(ctx, b, func(client nodeClient) (*types.Header, error) {
+		return client.GetSafeBlockHeader(ctx)
+	})
+}
+
+// BatchGetLogs retrieves logs for multiple filter queries in a single batch call.
+func (b *LoadBalancedClient) BatchGetLogs(ctx context.Context, queries []ethereum.FilterQuery) ([][]types.Log, error) {
+	return call(ctx, b, func(client nodeClient) ([][]types.Log, error) {
+		return client.BatchGetLogs(ctx, queries)
+	})
+}
+
+// BatchGetBlockHeaders retrieves headers for multiple block numbers in a single batch call.
+func (b *LoadBalancedClient) BatchGetBlockHeaders(ctx context.Context, blockNums []uint64) ([]*types.Header, error) {
+	return call(ctx, b, func(client nodeClient) ([]*types.Header, error) {
+		return client.BatchGetBlockHeaders(ctx, blockNums)
+	})
+}
+
+// GetContractCode retrieves the code of the contract at the given address as of the given block.
+func (b *LoadBalancedClient) GetContractCode(
+	ctx context.Context,
+	address common.Address,
+	blockNum *big.Int,
+) ([]byte, error) {
+	return call(ctx, b, func(client nodeClient) ([]byte, error) {
+		return client.GetContractCode(ctx, address, blockNum)
+	})
+}
+
+// SubscribePendingTransactions subscribes to transactions entering the mempool of a healthy node.
+func (b *LoadBalancedClient) SubscribePendingTransactions(
+	ctx context.Context,
+	ch chan<- *types.Transaction,
+) (ethereum.Subscription, error) {
+	return call(ctx, b, func(client nodeClient) (ethereum.Subscription, error) {
+		return client.SubscribePendingTransactions(ctx, ch)
+	})
+}
+
+// SubscribeNewHeads subscribes to the headers of new blocks of a healthy node.
+func (b *LoadBalancedClient) SubscribeNewHeads(
+	ctx context.Context,
+	ch chan<- *types.Header,
+) (ethereum.Subscription, error) {
+	return call(ctx, b, func(client nodeClient) (ethereum.Subscription, error) {
+		return client.SubscribeNewHeads(ctx, ch)
+	})
+}
+
+// SimulateTransaction executes the transaction from the given sender on top of the latest
+// block without submitting it, and returns the logs it would emit.
+func (b *LoadBalancedClient) SimulateTransaction(
+	ctx context.Context,
+	from common.Address,
+	tx *types.Transaction,
+) ([]types.Log, error) {
+	return call(ctx, b, func(client nodeClient) ([]types.Log, error) {
+		return client.SimulateTransaction(ctx, from, tx)
+	})
+}
@@ -3,6 +3,7 @@ package rpc
 import (
 	"time"
 
+	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -14,7 +15,7 @@ var (
 			Name: "chainindexor_rpc_requests_total",
 			Help: "Total number of RPC requests by method",
 		},
-		[]string{"method"},
+		[]string{"chain_id", "method"},
 	)
 
 	rpcErrors = promauto.NewCounterVec(
@@ -22,16 +23,16 @@ var (
 			Name: "chainindexor_rpc_errors_total",
 			Help: "Total number of RPC errors by method and type",
 		},
-		[]string{"method", "error_type"},
+		[]string{"chain_id", "method", "error_type"},
 	)
 
 	rpcDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "chainindexor_rpc_request_duration_seconds",
-			Help:    "Duration of RPC requests",
-			Buckets: prometheus.DefBuckets,
+			Help:    "Duration of RPC requests by method and status, including retries",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
 		},
-		[]string{"method"},
+		[]string{"chain_id", "method", "status"},
 	)
 
 	rpcRetries = promauto.NewCounterVec(
@@ -39,22 +40,69 @@ var (
 			Name: "chainindexor_rpc_retries_total",
 			Help: "Total number of RPC retries by method",
 		},
-		[]string{"method"},
+		[]string{"chain_id", "method"},
+	)
+
+	rpcNodeHealthy = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "chainindexor_rpc_node_healthy",
+			Help: "Whether a load balanced RPC node is healthy (1) or skipped after a transport failure (0)",
+		},
+		[]string{"chain_id", "url"},
+	)
+
+	rpcCircuitBreakerState = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "chainindexor_rpc_circuit_breaker_state",
+			Help: "State of the circuit breaker of an RPC node: closed (0), open (1) or half-open (2)",
+		},
+		[]string{"chain_id", "url"},
 	)
 )
 
-func RPCMethodInc(method string) {
-	rpcRequests.WithLabelValues(method).Inc()
+func RPCMethodInc(chainID uint64, method string) {
+	rpcRequests.WithLabelValues(metrics.ChainLabel(chainID), method).Inc()
+}
+
+// RPCMethodDuration records the duration of an RPC request, whose status is "error" if it returned an error
+// and "success" otherwise.
+func RPCMethodDuration(chainID uint64, method string, duration time.Duration, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+
+	rpcDuration.WithLabelValues(metrics.ChainLabel(chainID), method, status).Observe(duration.Seconds())
+}
+
+func RPCMethodError(chainID uint64, method, errorType string) {
+	rpcErrors.WithLabelValues(metrics.ChainLabel(chainID), method, errorType).Inc()
+}
+
+func RPCRetryInc(chainID uint64, method string) {
+	rpcRetries.WithLabelValues(metrics.ChainLabel(chainID), method).Inc()
+}
+
+func RPCNodeHealthySet(chainID uint64, url string, healthy bool) {
+	boolAsFloat := float64(1)
+	if !healthy {
+		boolAsFloat = 0
+	}
+
+	rpcNodeHealthy.WithLabelValues(metrics.ChainLabel(chainID), url).Set(boolAsFloat)
 }
 
-func RPCMethodDuration(method string, duration time.Duration) {
-	rpcDuration.WithLabelValues(method).Observe(duration.Seconds())
+// RPCNodeHealthyDelete removes the health of a node labelled with a chain ID it is no longer labelled with.
+func RPCNodeHealthyDelete(chainID uint64, url string) {
+	rpcNodeHealthy.DeleteLabelValues(metrics.ChainLabel(chainID), url)
 }
 
-func RPCMethodError(method, errorType string) {
-	rpcErrors.WithLabelValues(method, errorType).Inc()
+func RPCCircuitBreakerStateSet(chainID uint64, url string, state CircuitState) {
+	rpcCircuitBreakerState.WithLabelValues(metrics.ChainLabel(chainID), url).Set(float64(state))
 }
 
-func RPCRetryInc(method string) {
-	rpcRetries.WithLabelValues(method).Inc()
+// RPCCircuitBreakerStateDelete removes the state of a circuit breaker labelled with a chain ID it is
+// no longer labelled with.
+func RPCCircuitBreakerStateDelete(chainID uint64, url string) {
+	rpcCircuitBreakerState.DeleteLabelValues(metrics.ChainLabel(chainID), url)
 }
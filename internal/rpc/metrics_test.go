@@ -0,0 +1,107 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+// rpcDurationHistogram returns the current RPC duration histogram of a method and status.
+func rpcDurationHistogram(t *testing.T, method, status string) *dto.Histogram {
+	t.Helper()
+
+	observer, err := rpcDuration.GetMetricWithLabelValues("0", method, status)
+	require.NoError(t, err)
+
+	var metric dto.Metric
+	require.NoError(t, observer.(prometheus.Histogram).Write(&metric))
+
+	return metric.GetHistogram()
+}
+
+// TestClient_DurationMetrics is not parallel, so no other test records RPC durations while it runs.
+func TestClient_DurationMetrics(t *testing.T) {
+	client, err := NewClient(t.Context(), newFakeNode(t).URL, nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	t.Cleanup(failing.Close)
+
+	failingClient, err := NewClient(t.Context(), failing.URL, &config.RetryConfig{MaxAttempts: 1}, nil)
+	require.NoError(t, err)
+	t.Cleanup(failingClient.Close)
+
+	headerCount := rpcDurationHistogram(t, "eth_getBlockByNumber", "success").GetSampleCount()
+	batchCount := rpcDurationHistogram(t, "eth_getBlockByNumber_batch", "success").GetSampleCount()
+	logsCount := rpcDurationHistogram(t, "eth_getLogs", "success").GetSampleCount()
+	logsErrorCount := rpcDurationHistogram(t, "eth_getLogs", "error").GetSampleCount()
+
+	_, err = client.GetBlockHeader(t.Context(), 1)
+	require.NoError(t, err)
+	_, err = client.GetFinalizedBlockHeader(t.Context())
+	require.NoError(t, err)
+	_, err = client.BatchGetBlockHeaders(t.Context(), []uint64{1, 2})
+	require.NoError(t, err)
+	_, err = client.GetLogs(t.Context(), ethereum.FilterQuery{})
+	require.NoError(t, err)
+	_, err = failingClient.GetLogs(t.Context(), ethereum.FilterQuery{})
+	require.Error(t, err)
+
+	require.Equal(t, headerCount+2, rpcDurationHistogram(t, "eth_getBlockByNumber", "success").GetSampleCount())
+	require.Equal(t, batchCount+1, rpcDurationHistogram(t, "eth_getBlockByNumber_batch", "success").GetSampleCount())
+	require.Equal(t, logsCount+1, rpcDurationHistogram(t, "eth_getLogs", "success").GetSampleCount())
+	require.Equal(t, logsErrorCount+1, rpcDurationHistogram(t, "eth_getLogs", "error").GetSampleCount())
+
+	var bounds []float64
+	for _, bucket := range rpcDurationHistogram(t, "eth_getLogs", "success").GetBucket() {
+		bounds = append(bounds, bucket.GetUpperBound())
+	}
+	require.Equal(t, []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}, bounds)
+}
+
+// requestCount returns the number of requests of a method recorded for a chain.
+func requestCount(t *testing.T, chainID, method string) float64 {
+	t.Helper()
+
+	var metric dto.Metric
+	require.NoError(t, rpcRequests.WithLabelValues(chainID, method).Write(&metric))
+
+	return metric.GetCounter().GetValue()
+}
+
+func TestClient_ChainIDLabel(t *testing.T) {
+	t.Parallel()
+
+	const method = "eth_getBlockByNumber"
+
+	// Chains 2010001 and 2010137 are not used by any other test
+	mainnet, err := NewClient(t.Context(), newFakeNode(t).URL, nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(mainnet.Close)
+	mainnet.SetChainID(2010001)
+
+	polygon, err := NewClient(t.Context(), newFakeNode(t).URL, nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(polygon.Close)
+	polygon.SetChainID(2010137)
+
+	_, err = mainnet.GetBlockHeader(t.Context(), 1)
+	require.NoError(t, err)
+	_, err = polygon.GetBlockHeader(t.Context(), 1)
+	require.NoError(t, err)
+	_, err = polygon.GetBlockHeader(t.Context(), 1)
+	require.NoError(t, err)
+
+	// Each chain records its own series
+	require.InDelta(t, 1, requestCount(t, "2010001", method), 0)
+	require.InDelta(t, 2, requestCount(t, "2010137", method), 0)
+}
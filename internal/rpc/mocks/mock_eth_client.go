@@ -3,6 +3,10 @@
 package mocks
 
 import (
+	big "math/big"
+
+	common "github.com/ethereum/go-ethereum/common"
+
 	context "context"
 
 	ethereum "github.com/ethereum/go-ethereum"
@@ -233,6 +237,66 @@ func (_c *EthClient_GetBlockHeader_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// GetContractCode provides a mock function with given fields: ctx, address, blockNum
+func (_m *EthClient) GetContractCode(ctx context.Context, address common.Address, blockNum *big.Int) ([]byte, error) {
+	ret := _m.Called(ctx, address, blockNum)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetContractCode")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, *big.Int) ([]byte, error)); ok {
+		return rf(ctx, address, blockNum)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, *big.Int) []byte); ok {
+		r0 = rf(ctx, address, blockNum)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, *big.Int) error); ok {
+		r1 = rf(ctx, address, blockNum)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EthClient_GetContractCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetContractCode'
+type EthClient_GetContractCode_Call struct {
+	*mock.Call
+}
+
+// GetContractCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - address common.Address
+//   - blockNum *big.Int
+func (_e *EthClient_Expecter) GetContractCode(ctx interface{}, address interface{}, blockNum interface{}) *EthClient_GetContractCode_Call {
+	return &EthClient_GetContractCode_Call{Call: _e.mock.On("GetContractCode", ctx, address, blockNum)}
+}
+
+func (_c *EthClient_GetContractCode_Call) Run(run func(ctx context.Context, address common.Address, blockNum *big.Int)) *EthClient_GetContractCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(common.Address), args[2].(*big.Int))
+	})
+	return _c
+}
+
+func (_c *EthClient_GetContractCode_Call) Return(_a0 []byte, _a1 error) *EthClient_GetContractCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *EthClient_GetContractCode_Call) RunAndReturn(run func(context.Context, common.Address, *big.Int) ([]byte, error)) *EthClient_GetContractCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetFinalizedBlockHeader provides a mock function with given fields: ctx
 func (_m *EthClient) GetFinalizedBlockHeader(ctx context.Context) (*types.Header, error) {
 	ret := _m.Called(ctx)
@@ -2,11 +2,10 @@ package rpc
 
 import (
 	"context"
-	"crypto/rand"
 	"errors"
 	"fmt"
 	"math"
-	"math/big"
+	"math/rand/v2"
 	"net"
 	"strings"
 	"syscall"
@@ -69,8 +68,9 @@ func retryableError(err error) bool {
 	return false
 }
 
-// calculateBackoff computes the backoff duration for a given attempt with jitter.
-func calculateBackoff(attempt int, cfg *config.RetryConfig) time.Duration {
+// calculateBackoff computes the backoff duration for a given attempt, extended by a random
+// fraction of up to cfg.Jitter drawn from rng.
+func calculateBackoff(attempt int, cfg *config.RetryConfig, rng *rand.Rand) time.Duration {
 	if attempt <= 1 {
 		return 0
 	}
@@ -83,29 +83,21 @@ func calculateBackoff(attempt int, cfg *config.RetryConfig) time.Duration {
 		backoff = float64(cfg.MaxBackoff.Duration)
 	}
 
-	// Add jitter (±25%)
-	jitterRange := backoff * 0.25 //nolint:mnd
-	// Use crypto/rand for secure random jitter
-	randomBig, err := rand.Int(rand.Reader, big.NewInt(10000)) //nolint:mnd
-	if err != nil {
-		// Fallback to no jitter if random generation fails
-		randomBig = big.NewInt(5000) //nolint:mnd
-	}
-	randomFactor := float64(randomBig.Int64()) / 10000.0 //nolint:mnd // 0.0 to 1.0
-	jitter := (randomFactor * 2 * jitterRange) - jitterRange
-	backoff += jitter
-
-	// Ensure non-negative
-	if backoff < 0 {
-		backoff = 0
-	}
+	// Add jitter, so backoffs fall within [backoff, backoff*(1+jitter)]
+	backoff *= 1 + rng.Float64()*cfg.Jitter
 
 	return time.Duration(backoff)
 }
 
 // retryWithBackoff executes a function with exponential backoff retry logic.
-// It respects context cancellation and deadlines.
-func retryWithBackoff(ctx context.Context, cfg *config.RetryConfig, operation string, fn func() error) error {
+// It respects context cancellation and deadlines. Retries are counted for the chain with the given ID.
+func retryWithBackoff(
+	ctx context.Context,
+	cfg *config.RetryConfig,
+	chainID uint64,
+	operation string,
+	fn func() error,
+) error {
 	if cfg == nil {
 		// No retry config, execute once
 		return fn()
@@ -114,6 +106,9 @@ func retryWithBackoff(ctx context.Context, cfg *config.RetryConfig, operation st
 	var lastErr error
 	startTime := time.Now()
 
+	// Each retry loop draws its jitter from its own source, so concurrent calls do not contend on one
+	rng := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())) //nolint:gosec // jitter needs no secure randomness
+
 	for attempt := 1; attempt <= cfg.MaxAttempts; attempt++ {
 		// Check context before attempting
 		if err := ctx.Err(); err != nil {
@@ -126,7 +121,7 @@ func retryWithBackoff(ctx context.Context, cfg *config.RetryConfig, operation st
 			// Success
 			if attempt > 1 {
 				// Log retry success metrics
-				RPCRetryInc(operation)
+				RPCRetryInc(chainID, operation)
 			}
 			return nil
 		}
@@ -146,7 +141,7 @@ func retryWithBackoff(ctx context.Context, cfg *config.RetryConfig, operation st
 		}
 
 		// Calculate backoff duration
-		backoffDuration := calculateBackoff(attempt, cfg)
+		backoffDuration := calculateBackoff(attempt, cfg, rng)
 
 		// Wait with context awareness
 		if backoffDuration > 0 {
@@ -160,7 +155,7 @@ func retryWithBackoff(ctx context.Context, cfg *config.RetryConfig, operation st
 		}
 
 		// Increment retry counter
-		RPCRetryInc(operation)
+		RPCRetryInc(chainID, operation)
 	}
 
 	// All retries exhausted
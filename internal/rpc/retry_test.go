@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"net"
 	"syscall"
 	"testing"
@@ -147,7 +148,9 @@ func TestCalculateBackoff(t *testing.T) {
 		InitialBackoff:    common.NewDuration(1 * time.Second),
 		MaxBackoff:        common.NewDuration(30 * time.Second),
 		BackoffMultiplier: 2.0,
+		Jitter:            0.2,
 	}
+	rng := rand.New(rand.NewPCG(1, 2))
 
 	tests := []struct {
 		name        string
@@ -164,26 +167,26 @@ func TestCalculateBackoff(t *testing.T) {
 		{
 			name:        "attempt 2 - initial backoff with jitter",
 			attempt:     2,
-			minExpected: 750 * time.Millisecond,  // 1s - 25%
-			maxExpected: 1250 * time.Millisecond, // 1s + 25%
+			minExpected: 1 * time.Second,         // 1s
+			maxExpected: 1200 * time.Millisecond, // 1s + 20%
 		},
 		{
 			name:        "attempt 3 - exponential backoff",
 			attempt:     3,
-			minExpected: 1500 * time.Millisecond, // 2s - 25%
-			maxExpected: 2500 * time.Millisecond, // 2s + 25%
+			minExpected: 2 * time.Second,         // 2s
+			maxExpected: 2400 * time.Millisecond, // 2s + 20%
 		},
 		{
 			name:        "attempt 4",
 			attempt:     4,
-			minExpected: 3 * time.Second, // 4s - 25%
-			maxExpected: 5 * time.Second, // 4s + 25%
+			minExpected: 4 * time.Second,         // 4s
+			maxExpected: 4800 * time.Millisecond, // 4s + 20%
 		},
 		{
 			name:        "attempt 5",
 			attempt:     5,
-			minExpected: 6 * time.Second,  // 8s - 25%
-			maxExpected: 10 * time.Second, // 8s + 25%
+			minExpected: 8 * time.Second,         // 8s
+			maxExpected: 9600 * time.Millisecond, // 8s + 20%
 		},
 	}
 
@@ -191,7 +194,7 @@ func TestCalculateBackoff(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Run multiple times to account for jitter randomness
 			for i := 0; i < 10; i++ {
-				backoff := calculateBackoff(tt.attempt, cfg)
+				backoff := calculateBackoff(tt.attempt, cfg, rng)
 				assert.GreaterOrEqual(t, backoff, tt.minExpected, "backoff should be >= min")
 				assert.LessOrEqual(t, backoff, tt.maxExpected, "backoff should be <= max")
 			}
@@ -204,11 +207,45 @@ func TestCalculateBackoff_CappedAtMax(t *testing.T) {
 		InitialBackoff:    common.NewDuration(1 * time.Second),
 		MaxBackoff:        common.NewDuration(5 * time.Second),
 		BackoffMultiplier: 2.0,
+		Jitter:            0.2,
 	}
 
 	// Attempt 6 would be 32s without cap, should be capped at 5s (plus jitter)
-	backoff := calculateBackoff(10, cfg)
-	assert.LessOrEqual(t, backoff, 6250*time.Millisecond, "backoff should be capped at max + 25% jitter")
+	backoff := calculateBackoff(10, cfg, rand.New(rand.NewPCG(1, 2)))
+	assert.LessOrEqual(t, backoff, 6*time.Second, "backoff should be capped at max + 20% jitter")
+}
+
+func TestCalculateBackoff_Jitter(t *testing.T) {
+	cfg := &config.RetryConfig{
+		InitialBackoff:    common.NewDuration(100 * time.Millisecond),
+		MaxBackoff:        common.NewDuration(100 * time.Millisecond),
+		BackoffMultiplier: 2.0,
+	}
+	cfg.ApplyDefaults()
+	rng := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+
+	base := cfg.MaxBackoff.Duration
+	maxBackoff := time.Duration(float64(base) * (1 + cfg.Jitter))
+
+	// Every retry after the first attempts is capped at the same backoff, only the jitter differs
+	var previous time.Duration
+	for attempt := 2; attempt < 102; attempt++ {
+		backoff := calculateBackoff(attempt, cfg, rng)
+		require.GreaterOrEqual(t, backoff, base)
+		require.LessOrEqual(t, backoff, maxBackoff)
+		require.NotEqual(t, previous, backoff, "attempt %d", attempt)
+		previous = backoff
+	}
+}
+
+func TestCalculateBackoff_NoJitter(t *testing.T) {
+	cfg := &config.RetryConfig{
+		InitialBackoff:    common.NewDuration(1 * time.Second),
+		MaxBackoff:        common.NewDuration(30 * time.Second),
+		BackoffMultiplier: 2.0,
+	}
+
+	require.Equal(t, 4*time.Second, calculateBackoff(4, cfg, rand.New(rand.NewPCG(1, 2))))
 }
 
 func TestRetryWithBackoff_Success(t *testing.T) {
@@ -226,7 +263,7 @@ func TestRetryWithBackoff_Success(t *testing.T) {
 		return nil
 	}
 
-	err := retryWithBackoff(ctx, cfg, "test_operation", fn)
+	err := retryWithBackoff(ctx, cfg, 0, "test_operation", fn)
 	require.NoError(t, err)
 	assert.Equal(t, 1, callCount, "should succeed on first attempt")
 }
@@ -249,7 +286,7 @@ func TestRetryWithBackoff_SuccessAfterRetries(t *testing.T) {
 		return nil
 	}
 
-	err := retryWithBackoff(ctx, cfg, "test_operation", fn)
+	err := retryWithBackoff(ctx, cfg, 0, "test_operation", fn)
 	require.NoError(t, err)
 	assert.Equal(t, 3, callCount, "should succeed on third attempt")
 }
@@ -270,7 +307,7 @@ func TestRetryWithBackoff_NonRetryableError(t *testing.T) {
 		return expectedErr
 	}
 
-	err := retryWithBackoff(ctx, cfg, "test_operation", fn)
+	err := retryWithBackoff(ctx, cfg, 0, "test_operation", fn)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "non-retryable error")
 	assert.ErrorIs(t, err, expectedErr)
@@ -293,7 +330,7 @@ func TestRetryWithBackoff_ExhaustedRetries(t *testing.T) {
 		return expectedErr
 	}
 
-	err := retryWithBackoff(ctx, cfg, "test_operation", fn)
+	err := retryWithBackoff(ctx, cfg, 0, "test_operation", fn)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "all 3 attempts failed")
 	assert.ErrorIs(t, err, expectedErr)
@@ -318,7 +355,7 @@ func TestRetryWithBackoff_ContextCancelled(t *testing.T) {
 		return &mockNetError{msg: "temporary error", timeout: true}
 	}
 
-	err := retryWithBackoff(ctx, cfg, "test_operation", fn)
+	err := retryWithBackoff(ctx, cfg, 0, "test_operation", fn)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "context cancelled")
 	assert.Equal(t, 2, callCount, "should stop retrying after context cancelled")
@@ -341,7 +378,7 @@ func TestRetryWithBackoff_ContextDeadline(t *testing.T) {
 		return &mockNetError{msg: "temporary error", timeout: true}
 	}
 
-	err := retryWithBackoff(ctx, cfg, "test_operation", fn)
+	err := retryWithBackoff(ctx, cfg, 0, "test_operation", fn)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "context")
 	// Should fail early due to context deadline, not reach max attempts
@@ -357,7 +394,7 @@ func TestRetryWithBackoff_NilConfig(t *testing.T) {
 		return nil
 	}
 
-	err := retryWithBackoff(ctx, nil, "test_operation", fn)
+	err := retryWithBackoff(ctx, nil, 0, "test_operation", fn)
 	require.NoError(t, err)
 	assert.Equal(t, 1, callCount, "should execute once without retry config")
 }
@@ -372,7 +409,7 @@ func TestRetryWithBackoff_NilConfigWithError(t *testing.T) {
 		return expectedErr
 	}
 
-	err := retryWithBackoff(ctx, nil, "test_operation", fn)
+	err := retryWithBackoff(ctx, nil, 0, "test_operation", fn)
 	require.Error(t, err)
 	assert.ErrorIs(t, err, expectedErr)
 	assert.Equal(t, 1, callCount, "should execute once without retry config")
@@ -398,16 +435,16 @@ func TestRetryWithBackoff_BackoffTiming(t *testing.T) {
 	}
 
 	start := time.Now()
-	err := retryWithBackoff(ctx, cfg, "test_operation", fn)
+	err := retryWithBackoff(ctx, cfg, 0, "test_operation", fn)
 	elapsed := time.Since(start)
 
 	require.Error(t, err)
 	assert.Equal(t, 3, callCount, "should make 3 attempts")
-	// With jitter (±25%), minimum time should be:
+	// Jitter only extends backoffs, so minimum time should be:
 	// attempt 1: no wait
-	// attempt 2: 75ms (100ms - 25%)
-	// attempt 3: 150ms (200ms - 25%)
-	// Total minimum: ~225ms, but allowing for some timing variance
+	// attempt 2: 100ms
+	// attempt 3: 200ms
+	// Total minimum: ~300ms, but allowing for some timing variance
 	assert.Greater(t, elapsed, 50*time.Millisecond, "should respect backoff timing")
 }
 
@@ -0,0 +1,172 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+)
+
+// signatureMatch is a signature returned by the signature database for a hash.
+type signatureMatch struct {
+	Name string `json:"name"`
+
+	// Filtered is set for signatures flagged as spam collisions of a more common signature
+	Filtered bool `json:"filtered"`
+}
+
+// signatureLookupResponse is the response of the openchain.xyz signature database lookup API.
+type signatureLookupResponse struct {
+	OK     bool   `json:"ok"`
+	Error  string `json:"error"`
+	Result struct {
+		Event map[string][]signatureMatch `json:"event"`
+	} `json:"result"`
+}
+
+// SignatureRegistry resolves event topic0 hashes to human-readable event signatures
+// using an openchain.xyz compatible signature database.
+// Lookups are cached in the event_signatures table of the downloader database.
+type SignatureRegistry struct {
+	cfg    *config.SignatureRegistryConfig
+	client *http.Client
+	db     *sql.DB
+	log    *logger.Logger
+}
+
+// NewSignatureRegistry creates a new SignatureRegistry that caches lookups in the given database.
+// The database must contain the event_signatures table created by the downloader migrations.
+func NewSignatureRegistry(cfg *config.SignatureRegistryConfig, db *sql.DB, log *logger.Logger) *SignatureRegistry {
+	return &SignatureRegistry{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout.Duration},
+		db:     db,
+		log:    log,
+	}
+}
+
+// Resolve returns the event signature of the given topic0 (e.g. "Transfer(address,address,uint256)").
+// An empty signature is returned when the registry does not know the topic0.
+// Cached lookups younger than the configured cache TTL are served without calling the registry.
+func (r *SignatureRegistry) Resolve(ctx context.Context, topic0 common.Hash) (string, error) {
+	signature, found, err := r.cached(ctx, topic0)
+	if err != nil {
+		return "", err
+	}
+	if found {
+		return signature, nil
+	}
+
+	signature, err = r.lookup(ctx, topic0)
+	if err != nil {
+		return "", err
+	}
+
+	// Unknown topics are cached as well, so they are not looked up for every log
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT INTO event_signatures (topic0, signature, fetched_at) VALUES (?, ?, ?)
+		ON CONFLICT(topic0) DO UPDATE SET signature = excluded.signature, fetched_at = excluded.fetched_at`,
+		topic0.Hex(), signature, time.Now().Unix()); err != nil {
+		return "", fmt.Errorf("failed to cache signature of %s: %w", topic0.Hex(), err)
+	}
+
+	return signature, nil
+}
+
+// Name returns the event signature of the given topic0, falling back to its hex
+// representation when the topic0 is unknown or cannot be resolved. Intended for logging.
+func (r *SignatureRegistry) Name(ctx context.Context, topic0 common.Hash) string {
+	signature, err := r.Resolve(ctx, topic0)
+	if err != nil {
+		r.log.Debugf("failed to resolve signature of %s: %v", topic0.Hex(), err)
+	}
+
+	if signature == "" {
+		return topic0.Hex()
+	}
+
+	return signature
+}
+
+// cached returns the cached signature of the given topic0 if it has not expired.
+func (r *SignatureRegistry) cached(ctx context.Context, topic0 common.Hash) (string, bool, error) {
+	var (
+		signature string
+		fetchedAt int64
+	)
+
+	err := r.db.QueryRowContext(ctx, "SELECT signature, fetched_at FROM event_signatures WHERE topic0 = ?",
+		topic0.Hex()).Scan(&signature, &fetchedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read cached signature of %s: %w", topic0.Hex(), err)
+	}
+
+	if time.Since(time.Unix(fetchedAt, 0)) > r.cfg.CacheTTL.Duration {
+		return "", false, nil
+	}
+
+	return signature, true, nil
+}
+
+// lookup queries the signature database for the given topic0.
+func (r *SignatureRegistry) lookup(ctx context.Context, topic0 common.Hash) (string, error) {
+	reqURL, err := url.Parse(r.cfg.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid signature registry url: %w", err)
+	}
+
+	query := reqURL.Query()
+	query.Set("event", topic0.Hex())
+	query.Set("filter", "true")
+	reqURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create signature lookup request: %w", err)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up signature of %s: %w", topic0.Hex(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to look up signature of %s: unexpected status %d", topic0.Hex(), resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read signature lookup response for %s: %w", topic0.Hex(), err)
+	}
+
+	var lookupResp signatureLookupResponse
+	if err := json.Unmarshal(body, &lookupResp); err != nil {
+		return "", fmt.Errorf("failed to decode signature lookup response for %s: %w", topic0.Hex(), err)
+	}
+
+	if !lookupResp.OK {
+		return "", fmt.Errorf("signature registry returned an error for %s: %s", topic0.Hex(), lookupResp.Error)
+	}
+
+	// The registry returns the signatures in order of preference
+	for _, match := range lookupResp.Result.Event[topic0.Hex()] {
+		if !match.Filtered {
+			return match.Name, nil
+		}
+	}
+
+	return "", nil
+}
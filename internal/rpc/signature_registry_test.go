@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/internal/migrations"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+const transferSignature = "Transfer(address,address,uint256)"
+
+// newSignatureServer starts a signature database that knows the Transfer event
+// and counts the lookups it serves.
+func newSignatureServer(t *testing.T, lookups *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	transferTopic := crypto.Keccak256Hash([]byte(transferSignature)).Hex()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups.Add(1)
+
+		topic := r.URL.Query().Get("event")
+
+		var resp signatureLookupResponse
+		resp.OK = true
+		resp.Result.Event = map[string][]signatureMatch{topic: nil}
+		if topic == transferTopic {
+			resp.Result.Event[topic] = []signatureMatch{
+				{Name: "join_tg_invmru_haha_fd06787(address,bool)", Filtered: true},
+				{Name: transferSignature},
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newTestSignatureRegistry(t *testing.T, url string) *SignatureRegistry {
+	t.Helper()
+
+	dbConfig := config.DatabaseConfig{Path: filepath.Join(t.TempDir(), "signatures.db")}
+	dbConfig.ApplyDefaults()
+	require.NoError(t, migrations.RunMigrations(dbConfig))
+
+	database, err := db.NewSQLiteDBFromConfig(dbConfig)
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	cfg := &config.SignatureRegistryConfig{URL: url}
+	cfg.ApplyDefaults()
+
+	return NewSignatureRegistry(cfg, database, logger.NewNopLogger())
+}
+
+func TestSignatureRegistry_Resolve(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	transferTopic := crypto.Keccak256Hash([]byte(transferSignature))
+	unknownTopic := crypto.Keccak256Hash([]byte("Unknown()"))
+
+	var lookups atomic.Int32
+	registry := newTestSignatureRegistry(t, newSignatureServer(t, &lookups).URL)
+
+	signature, err := registry.Resolve(ctx, transferTopic)
+	require.NoError(t, err)
+	require.Equal(t, transferSignature, signature)
+
+	signature, err = registry.Resolve(ctx, unknownTopic)
+	require.NoError(t, err)
+	require.Empty(t, signature)
+	require.Equal(t, unknownTopic.Hex(), registry.Name(ctx, unknownTopic))
+
+	// Known and unknown topics are both served from the cache
+	require.Equal(t, transferSignature, registry.Name(ctx, transferTopic))
+	require.Equal(t, int32(2), lookups.Load())
+
+	// Expired entries are looked up again
+	_, err = registry.db.Exec("UPDATE event_signatures SET fetched_at = ?",
+		time.Now().Add(-registry.cfg.CacheTTL.Duration-time.Hour).Unix())
+	require.NoError(t, err)
+
+	signature, err = registry.Resolve(ctx, transferTopic)
+	require.NoError(t, err)
+	require.Equal(t, transferSignature, signature)
+	require.Equal(t, int32(3), lookups.Load())
+}
+
+func TestSignatureRegistry_ResolveError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	topic := crypto.Keccak256Hash([]byte(transferSignature))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+
+	registry := newTestSignatureRegistry(t, server.URL)
+
+	_, err := registry.Resolve(ctx, topic)
+	require.ErrorContains(t, err, "unexpected status 429")
+
+	// Failed lookups are not cached and fall back to the raw topic0
+	require.Equal(t, topic.Hex(), registry.Name(ctx, topic))
+
+	var cached int
+	require.NoError(t, registry.db.QueryRow("SELECT COUNT(*) FROM event_signatures").Scan(&cached))
+	require.Zero(t, cached)
+}
@@ -0,0 +1,213 @@
+// Package snapshot downloads and verifies database snapshots used to bootstrap
+// an indexer deployment without syncing from its start block.
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// ChecksumSuffix is appended to a snapshot URL to locate its companion SHA256 checksum file.
+	ChecksumSuffix = ".sha256"
+
+	// maxChecksumSize limits the size of checksum files.
+	maxChecksumSize = 1 << 10
+
+	snapshotFilePerm = 0o600
+)
+
+// ResolveURL converts a snapshot location into a URL that can be fetched over HTTP.
+// s3://bucket/key and gs://bucket/key are mapped to the public endpoints of Amazon S3 and
+// Google Cloud Storage; private objects must be given as pre-signed https:// URLs.
+// http(s) URLs are returned unchanged, while file:// URLs and plain paths resolve to a local path
+// with an empty URL.
+func ResolveURL(location string) (remote string, local string, err error) {
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme == "" {
+		return "", location, nil //nolint:nilerr // plain paths are local files
+	}
+
+	key := strings.TrimPrefix(u.Path, "/")
+
+	switch u.Scheme {
+	case "file":
+		return "", u.Path, nil
+	case "http", "https":
+		return location, "", nil
+	case "s3":
+		if u.Host == "" || key == "" {
+			return "", "", fmt.Errorf("invalid S3 snapshot URL %q: expected s3://bucket/key", location)
+		}
+		return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", u.Host, key), "", nil
+	case "gs":
+		if u.Host == "" || key == "" {
+			return "", "", fmt.Errorf("invalid GCS snapshot URL %q: expected gs://bucket/key", location)
+		}
+		return fmt.Sprintf("https://storage.googleapis.com/%s/%s", u.Host, key), "", nil
+	default:
+		return "", "", fmt.Errorf("unsupported snapshot URL scheme %q", u.Scheme)
+	}
+}
+
+// Download fetches the snapshot at location into dir and verifies it against the SHA256
+// checksum in the companion file at location + ".sha256". Local snapshots are verified in place.
+// It returns the local path of the verified snapshot.
+func Download(ctx context.Context, client *http.Client, location, dir string) (string, error) {
+	remote, local, err := ResolveURL(location)
+	if err != nil {
+		return "", err
+	}
+
+	if local != "" {
+		expected, err := readLocalChecksum(local + ChecksumSuffix)
+		if err != nil {
+			return "", err
+		}
+
+		if err := verifyFile(local, expected); err != nil {
+			return "", err
+		}
+
+		return local, nil
+	}
+
+	expected, err := fetchChecksum(ctx, client, remote+ChecksumSuffix)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(remote)
+	if err != nil {
+		return "", fmt.Errorf("invalid snapshot URL: %w", err)
+	}
+
+	dest := filepath.Join(dir, path.Base(u.Path))
+
+	actual, err := fetchFile(ctx, client, remote, dest)
+	if err != nil {
+		return "", err
+	}
+
+	if actual != expected {
+		_ = os.Remove(dest)
+		return "", fmt.Errorf("snapshot checksum mismatch: expected %s, got %s", expected, actual)
+	}
+
+	return dest, nil
+}
+
+// fetchFile downloads url to dest and returns the hex encoded SHA256 hash of its content.
+func fetchFile(ctx context.Context, client *http.Client, url, dest string) (string, error) {
+	body, err := get(ctx, client, url)
+	if err != nil {
+		return "", fmt.Errorf("failed to download snapshot: %w", err)
+	}
+	defer body.Close()
+
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_EXCL|os.O_WRONLY, snapshotFilePerm)
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hash), body); err != nil {
+		_ = os.Remove(dest)
+		return "", fmt.Errorf("failed to download snapshot: %w", err)
+	}
+
+	if err := f.Sync(); err != nil {
+		return "", fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// fetchChecksum downloads and parses a checksum file.
+func fetchChecksum(ctx context.Context, client *http.Client, url string) (string, error) {
+	body, err := get(ctx, client, url)
+	if err != nil {
+		return "", fmt.Errorf("failed to download snapshot checksum: %w", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxChecksumSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read snapshot checksum: %w", err)
+	}
+
+	return parseChecksum(string(data))
+}
+
+func get(ctx context.Context, client *http.Client, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	return resp.Body, nil
+}
+
+func readLocalChecksum(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read snapshot checksum: %w", err)
+	}
+
+	return parseChecksum(string(data))
+}
+
+// parseChecksum extracts the hash from checksum file content in the format produced
+// by sha256sum ("<hash>  <file name>") or containing only the hash.
+func parseChecksum(content string) (string, error) {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("snapshot checksum file is empty")
+	}
+
+	checksum := strings.ToLower(fields[0])
+	if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("invalid SHA256 checksum %q", fields[0])
+	}
+
+	return checksum, nil
+}
+
+func verifyFile(path, expected string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return fmt.Errorf("snapshot checksum mismatch: expected %s, got %s", expected, actual)
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var snapshotContent = []byte("SQLite format 3\x00 snapshot content")
+
+func checksumOf(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func TestResolveURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		location       string
+		expectedRemote string
+		expectedLocal  string
+		expectedErr    string
+	}{
+		{
+			name:           "s3",
+			location:       "s3://snapshots/mainnet/downloader.db",
+			expectedRemote: "https://snapshots.s3.amazonaws.com/mainnet/downloader.db",
+		},
+		{
+			name:           "gcs",
+			location:       "gs://snapshots/mainnet/downloader.db",
+			expectedRemote: "https://storage.googleapis.com/snapshots/mainnet/downloader.db",
+		},
+		{
+			name:           "https",
+			location:       "https://example.com/downloader.db?X-Amz-Signature=abc",
+			expectedRemote: "https://example.com/downloader.db?X-Amz-Signature=abc",
+		},
+		{
+			name:          "file url",
+			location:      "file:///data/downloader.db",
+			expectedLocal: "/data/downloader.db",
+		},
+		{
+			name:          "plain path",
+			location:      "./data/downloader.db",
+			expectedLocal: "./data/downloader.db",
+		},
+		{
+			name:        "missing key",
+			location:    "gs://snapshots",
+			expectedErr: "expected gs://bucket/key",
+		},
+		{
+			name:        "unsupported scheme",
+			location:    "ftp://example.com/downloader.db",
+			expectedErr: "unsupported snapshot URL scheme",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			remote, local, err := ResolveURL(tt.location)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedRemote, remote)
+			require.Equal(t, tt.expectedLocal, local)
+		})
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	t.Parallel()
+
+	checksum := checksumOf(snapshotContent)
+
+	parsed, err := parseChecksum(checksum + "  downloader.db\n")
+	require.NoError(t, err)
+	require.Equal(t, checksum, parsed)
+
+	_, err = parseChecksum("")
+	require.ErrorContains(t, err, "empty")
+
+	_, err = parseChecksum("abc123")
+	require.ErrorContains(t, err, "invalid SHA256 checksum")
+}
+
+func TestDownload(t *testing.T) {
+	t.Parallel()
+
+	checksum := checksumOf(snapshotContent)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.Path {
+		case "/valid.db", "/corrupted.db", "/unverified.db":
+			_, err = w.Write(snapshotContent)
+		case "/valid.db.sha256":
+			_, err = w.Write([]byte(checksum + "  valid.db\n"))
+		case "/corrupted.db.sha256":
+			_, err = w.Write([]byte(checksumOf([]byte("other content"))))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("verified download", func(t *testing.T) {
+		t.Parallel()
+
+		path, err := Download(t.Context(), server.Client(), server.URL+"/valid.db", t.TempDir())
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, snapshotContent, data)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		_, err := Download(t.Context(), server.Client(), server.URL+"/corrupted.db", dir)
+		require.ErrorContains(t, err, "checksum mismatch")
+		require.NoFileExists(t, filepath.Join(dir, "corrupted.db"))
+	})
+
+	t.Run("missing checksum file", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Download(t.Context(), server.Client(), server.URL+"/unverified.db", t.TempDir())
+		require.ErrorContains(t, err, "unexpected status 404")
+	})
+
+	t.Run("local snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		local := filepath.Join(t.TempDir(), "local.db")
+		require.NoError(t, os.WriteFile(local, snapshotContent, 0o600))
+		require.NoError(t, os.WriteFile(local+ChecksumSuffix, []byte(checksum), 0o600))
+
+		path, err := Download(t.Context(), server.Client(), local, t.TempDir())
+		require.NoError(t, err)
+		require.Equal(t, local, path)
+
+		require.NoError(t, os.WriteFile(local, []byte("tampered"), 0o600))
+		_, err = Download(t.Context(), server.Client(), local, t.TempDir())
+		require.ErrorContains(t, err, "checksum mismatch")
+	})
+}
@@ -0,0 +1,92 @@
+// Package tracing provides OpenTelemetry distributed tracing for the indexing pipeline.
+// Spans are created through the global tracer provider, so they are no-ops until Setup
+// installs an exporting provider.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope name of all ChainIndexor spans.
+const TracerName = "chainindexor"
+
+// Attribute keys shared by ChainIndexor spans and resources.
+const (
+	// AttrChainID is the ID of the chain being indexed
+	AttrChainID = attribute.Key("chainindexor.chain_id")
+
+	// AttrIndexer is the name of the indexer handling the logs
+	AttrIndexer = attribute.Key("chainindexor.indexer")
+)
+
+// Tracer returns the ChainIndexor tracer from the global tracer provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Setup installs a global tracer provider that exports spans to the OTLP collector at cfg.Endpoint,
+// sampling cfg.SamplingRatio of the traces. A non-zero chainID is reported as the chainindexor.chain_id
+// resource attribute. The returned function flushes pending spans and shuts the provider down.
+func Setup(ctx context.Context, cfg *config.TracingConfig, chainID uint64) (func(context.Context) error, error) {
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	attrs := []attribute.KeyValue{semconv.ServiceName(cfg.ServiceName)}
+	if chainID != 0 {
+		attrs = append(attrs, AttrChainID.Int64(int64(chainID)))
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, attrs...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// Child spans follow the sampling decision of their chunk, so traces are never partial
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplingRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+// newExporter creates the OTLP span exporter for the configured transport.
+func newExporter(ctx context.Context, cfg *config.TracingConfig) (sdktrace.SpanExporter, error) {
+	if cfg.Protocol == config.TracingProtocolGRPC {
+		return otlptracegrpc.New(ctx, otlptracegrpc.WithEndpointURL(cfg.Endpoint))
+	}
+
+	return otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+}
+
+// EndSpan records err on the span, if any, and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEndSpan(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(TracerName)
+
+	ctx, parent := tracer.Start(t.Context(), "parent")
+	_, child := tracer.Start(ctx, "child")
+
+	EndSpan(child, errors.New("store failed"))
+	EndSpan(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	require.Equal(t, "child", spans[0].Name())
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Equal(t, "store failed", spans[0].Status().Description)
+	require.Len(t, spans[0].Events(), 1)
+
+	// The child span belongs to the trace of its parent
+	require.Equal(t, spans[1].SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+	require.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	require.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestSetup(t *testing.T) {
+	// Setup replaces the global tracer provider, so this test does not run in parallel
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	tests := []struct {
+		name string
+		cfg  *config.TracingConfig
+	}{
+		{name: "http", cfg: &config.TracingConfig{Endpoint: "http://localhost:4318"}},
+		{
+			name: "grpc",
+			cfg: &config.TracingConfig{
+				Endpoint:      "http://localhost:4317",
+				Protocol:      config.TracingProtocolGRPC,
+				SamplingRatio: 0.5,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.ApplyDefaults()
+			require.NoError(t, tt.cfg.Validate())
+
+			shutdown, err := Setup(t.Context(), tt.cfg, 1)
+			require.NoError(t, err)
+
+			_, isSDK := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+			require.True(t, isSDK)
+
+			require.NoError(t, shutdown(t.Context()))
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package alert
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// TypeIndexerLag is the type of alerts triggered when an indexer falls too far behind the chain.
+	TypeIndexerLag = "indexer_lag"
+
+	// TypeReorgDepthExceeded is the type of alerts triggered when a reorg deeper than the
+	// max reorg depth halts the downloader.
+	TypeReorgDepthExceeded = "reorg_depth_exceeded"
+)
+
+// Alert describes a condition that requires operator attention.
+type Alert struct {
+	// Type identifies the kind of alert (e.g. "indexer_lag").
+	Type string `json:"type"`
+	// Indexer is the name of the indexer the alert refers to.
+	Indexer string `json:"indexer"`
+	// Message is a human-readable description of the condition.
+	Message string `json:"message"`
+	// RecommendedAction suggests how the operator can resolve the condition.
+	RecommendedAction string `json:"recommended_action"`
+	// Details contains alert specific values (e.g. "lag_blocks").
+	Details map[string]any `json:"details,omitempty"`
+	// Timestamp is when the alert was triggered.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Manager dispatches alerts to the configured notification channels.
+type Manager interface {
+	// Trigger sends the alert.
+	Trigger(ctx context.Context, alert Alert) error
+}
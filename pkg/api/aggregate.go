@@ -0,0 +1,378 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxConcurrentAggregateQueries bounds the indexers an aggregate query reads from at once
+const maxConcurrentAggregateQueries = 4
+
+// Supported aggregations of an aggregate query.
+const (
+	AggregationSum   = indexer.AggregationSum
+	AggregationCount = indexer.AggregationCount
+	AggregationAvg   = indexer.AggregationAvg
+	AggregationMin   = indexer.AggregationMin
+	AggregationMax   = indexer.AggregationMax
+)
+
+// errInvalidAggregate is returned when an aggregate query does not match the schema of an indexer.
+var errInvalidAggregate = errors.New("invalid aggregate query")
+
+// aggregate is the partial aggregate of the events of one or more indexers. Values are integers
+// of any width, so they are kept as big.Int. The minimum and maximum are nil without events.
+type aggregate struct {
+	count    int64
+	sum      *big.Int
+	min, max *big.Int
+}
+
+// merge adds the values of another aggregate to the aggregate.
+func (a *aggregate) merge(other aggregate) {
+	a.count += other.count
+
+	if other.sum != nil {
+		if a.sum == nil {
+			a.sum = new(big.Int)
+		}
+		a.sum.Add(a.sum, other.sum)
+	}
+	if other.min != nil && (a.min == nil || other.min.Cmp(a.min) < 0) {
+		a.min = other.min
+	}
+	if other.max != nil && (a.max == nil || other.max.Cmp(a.max) > 0) {
+		a.max = other.max
+	}
+}
+
+// value returns the result of the aggregation as a decimal string, and false if it is undefined
+// because there are no events.
+func (a aggregate) value(aggregation string) (string, bool) {
+	switch aggregation {
+	case AggregationCount:
+		return strconv.FormatInt(a.count, 10), true //nolint:mnd
+	case AggregationSum:
+		if a.sum == nil {
+			return "0", true
+		}
+		return a.sum.String(), true
+	case AggregationAvg:
+		if a.count == 0 || a.sum == nil {
+			return "", false
+		}
+		return indexer.FormatAverage(a.sum, a.count), true
+	case AggregationMin:
+		if a.min == nil {
+			return "", false
+		}
+		return a.min.String(), true
+	default:
+		if a.max == nil {
+			return "", false
+		}
+		return a.max.String(), true
+	}
+}
+
+// AggregateEvents aggregates a field of an event type across several indexers.
+// @Summary Aggregate events across indexers
+// @Description Compute the sum, count, average, minimum or maximum of a numeric event field across several indexers, e.g. the transfer volume of all ERC-20 indexers over a block range. Every indexer aggregates its own events, and the results are returned as exact decimal strings
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param query body AggregateRequest true "Aggregate query"
+// @Success 200 {object} AggregateResult "Aggregate of every indexer and of all of them"
+// @Failure 400 {object} ErrorResponse "Invalid query"
+// @Failure 404 {object} ErrorResponse "Indexer not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /query/aggregate [post]
+func (h *Handler) AggregateEvents(w http.ResponseWriter, r *http.Request) {
+	var req AggregateRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid aggregate query: %v", err))
+		return
+	}
+
+	req.Aggregation = strings.ToLower(req.Aggregation)
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid aggregate query: %v", err))
+		return
+	}
+
+	queryables := make([]indexer.Queryable, len(req.Indexers))
+	columns := make([]string, len(req.Indexers))
+	for i, name := range req.Indexers {
+		idx := h.registry.GetByName(name)
+		if idx == nil {
+			respondError(w, http.StatusNotFound, fmt.Sprintf("indexer '%s' not found", name))
+			return
+		}
+
+		queryable, ok := idx.(indexer.Queryable)
+		if !ok {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("indexer '%s' does not support querying", name))
+			return
+		}
+
+		// Only fields of the event schema are accepted, and they are aggregated by their column
+		column, err := aggregateColumn(queryable, req)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid aggregate query for indexer '%s': %v", name, err))
+			return
+		}
+
+		queryables[i] = queryable
+		columns[i] = column
+	}
+
+	aggregates := make([]aggregate, len(queryables))
+
+	g, ctx := errgroup.WithContext(r.Context())
+	g.SetLimit(maxConcurrentAggregateQueries)
+
+	for i, queryable := range queryables {
+		g.Go(func() error {
+			result, err := aggregateIndexer(ctx, queryable, req, columns[i])
+			if err != nil {
+				return fmt.Errorf("indexer %s: %w", req.Indexers[i], err)
+			}
+
+			aggregates[i] = result
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		if errors.Is(err, errInvalidAggregate) || errors.Is(err, indexer.ErrInvalidAggregation) {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid aggregate query: %v", err))
+			return
+		}
+
+		requestLogger(h.log, r).Errorf("Failed to aggregate events: %v", err)
+		respondError(w, http.StatusInternalServerError, "failed to aggregate events")
+		return
+	}
+
+	result := AggregateResult{
+		Indexers:  req.Indexers,
+		ByIndexer: make(map[string]string, len(req.Indexers)),
+	}
+
+	var total aggregate
+	for i, name := range req.Indexers {
+		if value, ok := aggregates[i].value(req.Aggregation); ok {
+			result.ByIndexer[name] = value
+		}
+		total.merge(aggregates[i])
+	}
+	if value, ok := total.value(req.Aggregation); ok {
+		result.Total = &value
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// validate checks the parts of an aggregate query that do not depend on the indexers.
+func (req AggregateRequest) validate() error {
+	if len(req.Indexers) == 0 {
+		return errors.New("at least one indexer is required")
+	}
+
+	seen := make(map[string]struct{}, len(req.Indexers))
+	for _, name := range req.Indexers {
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("duplicate indexer '%s'", name)
+		}
+		seen[name] = struct{}{}
+	}
+
+	if req.EventType == "" {
+		return errors.New("event_type is required")
+	}
+
+	switch req.Aggregation {
+	case AggregationCount:
+	case AggregationSum, AggregationAvg, AggregationMin, AggregationMax:
+		if req.Field == "" {
+			return fmt.Errorf("field is required for the %s aggregation", req.Aggregation)
+		}
+	default:
+		return errors.New("aggregation must be one of sum, count, avg, min, max")
+	}
+
+	if req.FromBlock != nil && req.ToBlock != nil && *req.FromBlock > *req.ToBlock {
+		return errors.New("from_block cannot be greater than to_block")
+	}
+
+	return nil
+}
+
+// aggregateColumn checks that the indexer handles the event type of the query and, if a field is
+// aggregated, that it is a numeric field of the event. It returns the column the field is stored in.
+func aggregateColumn(queryable indexer.Queryable, req AggregateRequest) (string, error) {
+	for _, schema := range queryable.GetEventSchema() {
+		if !strings.EqualFold(schema.Name, req.EventType) {
+			continue
+		}
+
+		if req.Field == "" {
+			return "", nil
+		}
+
+		for _, field := range schema.Fields {
+			if field.Name != req.Field {
+				continue
+			}
+
+			if !isNumericType(field.Type) {
+				return "", fmt.Errorf("field '%s' of %s events is of type %s, not a number", field.Name, schema.Name, field.Type)
+			}
+			if field.Column == "" {
+				return "", fmt.Errorf("field '%s' of %s events is not stored in a column", field.Name, schema.Name)
+			}
+
+			return field.Column, nil
+		}
+
+		return "", fmt.Errorf("unknown field '%s' of %s events", req.Field, schema.Name)
+	}
+
+	return "", fmt.Errorf("unknown event type '%s'", req.EventType)
+}
+
+// isNumericType reports whether the Solidity type is an integer type.
+func isNumericType(solidityType string) bool {
+	return (strings.HasPrefix(solidityType, "uint") || strings.HasPrefix(solidityType, "int")) &&
+		!strings.Contains(solidityType, "[")
+}
+
+// aggregateIndexer aggregates the events of an indexer matching the query in the indexer itself,
+// with the aggregations of its event queries over the column of the field. Averages are merged
+// across indexers from the sum and the count of the values, which are aggregated separately.
+func aggregateIndexer(
+	ctx context.Context,
+	queryable indexer.Queryable,
+	req AggregateRequest,
+	column string,
+) (aggregate, error) {
+	var result aggregate
+
+	switch req.Aggregation {
+	case AggregationCount:
+		count, err := queryAggregation(ctx, queryable, req, indexer.AggregationCount, "")
+		if err != nil {
+			return aggregate{}, err
+		}
+		result.count, err = countValue(count)
+		if err != nil {
+			return aggregate{}, err
+		}
+	case AggregationSum, AggregationAvg:
+		sum, err := queryAggregation(ctx, queryable, req, indexer.AggregationSum, column)
+		if err != nil {
+			return aggregate{}, err
+		}
+		if result.sum, err = integerValue(sum); err != nil {
+			return aggregate{}, err
+		}
+		if req.Aggregation == AggregationSum {
+			break
+		}
+
+		count, err := queryAggregation(ctx, queryable, req, indexer.AggregationCount, column)
+		if err != nil {
+			return aggregate{}, err
+		}
+		if result.count, err = countValue(count); err != nil {
+			return aggregate{}, err
+		}
+	default:
+		value, err := queryAggregation(ctx, queryable, req, req.Aggregation, column)
+		if err != nil {
+			return aggregate{}, err
+		}
+		number, err := integerValue(value)
+		if err != nil {
+			return aggregate{}, err
+		}
+		if req.Aggregation == AggregationMin {
+			result.min = number
+		} else {
+			result.max = number
+		}
+	}
+
+	return result, nil
+}
+
+// queryAggregation runs a single aggregation over the events of an indexer matching the query,
+// and returns its result.
+func queryAggregation(
+	ctx context.Context,
+	queryable indexer.Queryable,
+	req AggregateRequest,
+	function, column string,
+) (any, error) {
+	events, _, err := queryable.QueryEvents(ctx, indexer.QueryParams{
+		EventType:   req.EventType,
+		Limit:       1,
+		FromBlock:   req.FromBlock,
+		ToBlock:     req.ToBlock,
+		Aggregation: &indexer.Aggregation{Field: column, Function: function},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := events.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid aggregation result type: expected map, got %T", events)
+	}
+
+	return result["result"], nil
+}
+
+// countValue converts the result of a count aggregation to an int64.
+func countValue(result any) (int64, error) {
+	switch count := result.(type) {
+	case int64:
+		return count, nil
+	case int:
+		return int64(count), nil
+	default:
+		return 0, fmt.Errorf("invalid count: expected an integer, got %T", result)
+	}
+}
+
+// integerValue converts the decimal string result of a sum, min or max aggregation to a big.Int.
+// Results of aggregations without events are nil.
+func integerValue(result any) (*big.Int, error) {
+	if result == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	text, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid aggregation result: expected a decimal string, got %T", result)
+	}
+
+	number, ok := new(big.Int).SetString(text, 10) //nolint:mnd
+	if !ok {
+		return nil, fmt.Errorf("invalid aggregation result: %q is not an integer", text)
+	}
+
+	return number, nil
+}
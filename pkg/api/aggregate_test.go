@@ -0,0 +1,339 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	indexermocks "github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var transferSchema = []indexer.EventSchema{
+	{
+		Name: "Transfer",
+		Fields: []indexer.EventFieldSchema{
+			{Name: "from", Type: "address", Indexed: true, Column: "from_address"},
+			{Name: "value", Type: "uint256", Column: "value"},
+		},
+	},
+}
+
+// aggregationResult returns the result of an aggregation of an event query.
+func aggregationResult(result any) map[string]any {
+	return map[string]any{"result": result}
+}
+
+// aggregationParams matches the params of an event query running the aggregation over the field.
+func aggregationParams(function, field string) any {
+	return mock.MatchedBy(func(qp indexer.QueryParams) bool {
+		return qp.Aggregation != nil && *qp.Aggregation == indexer.Aggregation{Field: field, Function: function}
+	})
+}
+
+func TestHandler_AggregateEvents(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "malformed body",
+			body:           `{"indexers": "usdc"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", "message": "invalid aggregate query: ` +
+				`json: cannot unmarshal string into Go struct field AggregateRequest.indexers of type []string"}`,
+		},
+		{
+			name:           "no indexers",
+			body:           `{"event_type": "Transfer", "aggregation": "count"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", ` +
+				`"message": "invalid aggregate query: at least one indexer is required"}`,
+		},
+		{
+			name:           "duplicate indexer",
+			body:           `{"indexers": ["usdc", "usdc"], "event_type": "Transfer", "aggregation": "count"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", ` +
+				`"message": "invalid aggregate query: duplicate indexer 'usdc'"}`,
+		},
+		{
+			name:           "unknown aggregation",
+			body:           `{"indexers": ["usdc"], "event_type": "Transfer", "aggregation": "median", "field": "value"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", ` +
+				`"message": "invalid aggregate query: aggregation must be one of sum, count, avg, min, max"}`,
+		},
+		{
+			name:           "missing field",
+			body:           `{"indexers": ["usdc"], "event_type": "Transfer", "aggregation": "sum"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", ` +
+				`"message": "invalid aggregate query: field is required for the sum aggregation"}`,
+		},
+		{
+			name: "invalid block range",
+			body: `{"indexers": ["usdc"], "event_type": "Transfer", "aggregation": "count", ` +
+				`"from_block": 200, "to_block": 100}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", ` +
+				`"message": "invalid aggregate query: from_block cannot be greater than to_block"}`,
+		},
+		{
+			name: "indexer not found",
+			body: `{"indexers": ["usdc", "dai"], "event_type": "Transfer", "aggregation": "sum", "field": "value"}`,
+			setupMocks: func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("usdc").Return(usdc)
+				usdc.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+				registry.EXPECT().GetByName("dai").Return(nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"code": 404, "error": "Not Found", "message": "indexer 'dai' not found"}`,
+		},
+		{
+			name: "indexer not queryable",
+			body: `{"indexers": ["usdc"], "event_type": "Transfer", "aggregation": "count"}`,
+			setupMocks: func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("usdc").Return(indexermocks.NewIndexer(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", ` +
+				`"message": "indexer 'usdc' does not support querying"}`,
+		},
+		{
+			name: "unknown event type",
+			body: `{"indexers": ["usdc"], "event_type": "Swap", "aggregation": "count"}`,
+			setupMocks: func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("usdc").Return(usdc)
+				usdc.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", ` +
+				`"message": "invalid aggregate query for indexer 'usdc': unknown event type 'Swap'"}`,
+		},
+		{
+			name: "field not in the schema",
+			body: `{"indexers": ["usdc"], "event_type": "Transfer", "aggregation": "sum", ` +
+				`"field": "value) FROM transfer; DROP TABLE transfer; --"}`,
+			setupMocks: func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("usdc").Return(usdc)
+				usdc.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", "message": "invalid aggregate query for indexer 'usdc': ` +
+				`unknown field 'value) FROM transfer; DROP TABLE transfer; --' of Transfer events"}`,
+		},
+		{
+			name: "field not numeric",
+			body: `{"indexers": ["usdc"], "event_type": "Transfer", "aggregation": "max", "field": "from"}`,
+			setupMocks: func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("usdc").Return(usdc)
+				usdc.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", "message": "invalid aggregate query for indexer 'usdc': ` +
+				`field 'from' of Transfer events is of type address, not a number"}`,
+		},
+		{
+			name: "query error",
+			body: `{"indexers": ["usdc"], "event_type": "Transfer", "aggregation": "sum", "field": "value"}`,
+			setupMocks: func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("usdc").Return(usdc)
+				usdc.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+				usdc.Queryable.EXPECT().QueryEvents(mock.Anything, mock.Anything).
+					Return(nil, 0, errors.New("database locked"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"code": 500, "error": "Internal Server Error", "message": "failed to aggregate events"}`,
+		},
+		{
+			name: "field not stored in a column",
+			body: `{"indexers": ["usdc"], "event_type": "Transfer", "aggregation": "sum", "field": "value"}`,
+			setupMocks: func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("usdc").Return(usdc)
+				usdc.Queryable.EXPECT().GetEventSchema().Return([]indexer.EventSchema{
+					{Name: "Transfer", Fields: []indexer.EventFieldSchema{{Name: "value", Type: "uint256"}}},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", "message": "invalid aggregate query for indexer 'usdc': ` +
+				`field 'value' of Transfer events is not stored in a column"}`,
+		},
+		{
+			name: "aggregation rejected by the indexer",
+			body: `{"indexers": ["usdc"], "event_type": "Transfer", "aggregation": "sum", "field": "value"}`,
+			setupMocks: func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("usdc").Return(usdc)
+				usdc.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+				usdc.Queryable.EXPECT().QueryEvents(mock.Anything, mock.Anything).Return(nil, 0,
+					fmt.Errorf("%w: value is not a numeric field of Transfer events", indexer.ErrInvalidAggregation))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", "message": "invalid aggregate query: indexer usdc: ` +
+				`invalid aggregation: value is not a numeric field of Transfer events"}`,
+		},
+		{
+			name: "count",
+			body: `{"indexers": ["usdc", "usdt"], "event_type": "transfer", "aggregation": "count", ` +
+				`"from_block": 100, "to_block": 200}`,
+			setupMocks: func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer) {
+				fromBlock, toBlock := uint64(100), uint64(200)
+				params := indexer.QueryParams{
+					EventType:   "transfer",
+					Limit:       1,
+					FromBlock:   &fromBlock,
+					ToBlock:     &toBlock,
+					Aggregation: &indexer.Aggregation{Function: indexer.AggregationCount},
+				}
+
+				registry.EXPECT().GetByName("usdc").Return(usdc)
+				usdc.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+				usdc.Queryable.EXPECT().QueryEvents(mock.Anything, params).Return(aggregationResult(int64(42)), 1, nil)
+				registry.EXPECT().GetByName("usdt").Return(usdt)
+				usdt.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+				usdt.Queryable.EXPECT().QueryEvents(mock.Anything, params).Return(aggregationResult(int64(0)), 1, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"indexers": ["usdc", "usdt"], "total": "42", "by_indexer": {"usdc": "42", "usdt": "0"}}`,
+		},
+		{
+			name: "sum beyond 2^53",
+			body: `{"indexers": ["usdc", "usdt"], "event_type": "Transfer", "aggregation": "SUM", "field": "value"}`,
+			setupMocks: func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("usdc").Return(usdc)
+				usdc.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+				usdc.Queryable.EXPECT().QueryEvents(mock.Anything, aggregationParams("sum", "value")).
+					Return(aggregationResult("100000000000000000001"), 1, nil)
+				registry.EXPECT().GetByName("usdt").Return(usdt)
+				usdt.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+				usdt.Queryable.EXPECT().QueryEvents(mock.Anything, aggregationParams("sum", "value")).
+					Return(aggregationResult("9007199254740993"), 1, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"indexers": ["usdc", "usdt"], "total": "100009007199254740994", ` +
+				`"by_indexer": {"usdc": "100000000000000000001", "usdt": "9007199254740993"}}`,
+		},
+		{
+			name: "avg, without events of one indexer",
+			body: `{"indexers": ["usdc", "usdt"], "event_type": "Transfer", "aggregation": "avg", "field": "value"}`,
+			setupMocks: func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("usdc").Return(usdc)
+				usdc.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+				usdc.Queryable.EXPECT().QueryEvents(mock.Anything, aggregationParams("sum", "value")).
+					Return(aggregationResult("91"), 1, nil)
+				usdc.Queryable.EXPECT().QueryEvents(mock.Anything, aggregationParams("count", "value")).
+					Return(aggregationResult(int64(3)), 1, nil)
+				registry.EXPECT().GetByName("usdt").Return(usdt)
+				usdt.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+				usdt.Queryable.EXPECT().QueryEvents(mock.Anything, aggregationParams("sum", "value")).
+					Return(aggregationResult("0"), 1, nil)
+				usdt.Queryable.EXPECT().QueryEvents(mock.Anything, aggregationParams("count", "value")).
+					Return(aggregationResult(int64(0)), 1, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"indexers": ["usdc", "usdt"], "total": "30.333333333333333333", ` +
+				`"by_indexer": {"usdc": "30.333333333333333333"}}`,
+		},
+		{
+			name: "min",
+			body: `{"indexers": ["usdc", "usdt"], "event_type": "Transfer", "aggregation": "min", "field": "value"}`,
+			setupMocks: func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("usdc").Return(usdc)
+				usdc.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+				usdc.Queryable.EXPECT().QueryEvents(mock.Anything, aggregationParams("min", "value")).
+					Return(aggregationResult("7"), 1, nil)
+				registry.EXPECT().GetByName("usdt").Return(usdt)
+				usdt.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+				usdt.Queryable.EXPECT().QueryEvents(mock.Anything, aggregationParams("min", "value")).
+					Return(aggregationResult("3"), 1, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"indexers": ["usdc", "usdt"], "total": "3", "by_indexer": {"usdc": "7", "usdt": "3"}}`,
+		},
+		{
+			name: "max without events",
+			body: `{"indexers": ["usdc"], "event_type": "Transfer", "aggregation": "max", "field": "value"}`,
+			setupMocks: func(registry *apimocks.IndexerRegistry, usdc, usdt *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("usdc").Return(usdc)
+				usdc.Queryable.EXPECT().GetEventSchema().Return(transferSchema)
+				usdc.Queryable.EXPECT().QueryEvents(mock.Anything, aggregationParams("max", "value")).
+					Return(aggregationResult(nil), 1, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"indexers": ["usdc"], "total": null, "by_indexer": {}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			usdc := newMockQueryableIndexer(t)
+			usdt := newMockQueryableIndexer(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(registry, usdc, usdt)
+			}
+
+			handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/query/aggregate", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.AggregateEvents(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestAggregate_Value(t *testing.T) {
+	t.Parallel()
+
+	wide, ok := new(big.Int).SetString("100000000000000000001", 10)
+	require.True(t, ok)
+
+	var total, empty aggregate
+	total.merge(empty)
+	total.merge(aggregate{count: 3, sum: big.NewInt(12), min: big.NewInt(-2), max: big.NewInt(10)})
+	total.merge(aggregate{count: 1, sum: wide, min: wide, max: wide})
+
+	expected := map[string]string{
+		AggregationCount: "4",
+		AggregationSum:   "100000000000000000013",
+		AggregationAvg:   "25000000000000000003.25",
+		AggregationMin:   "-2",
+		AggregationMax:   "100000000000000000001",
+	}
+	for aggregation, value := range expected {
+		result, ok := total.value(aggregation)
+		require.True(t, ok, aggregation)
+		require.Equal(t, value, result, aggregation)
+	}
+
+	// Without events only the count and the sum are defined
+	for _, aggregation := range []string{AggregationCount, AggregationSum} {
+		result, ok := empty.value(aggregation)
+		require.True(t, ok, aggregation)
+		require.Equal(t, "0", result, aggregation)
+	}
+	for _, aggregation := range []string{AggregationAvg, AggregationMin, AggregationMax} {
+		_, ok := empty.value(aggregation)
+		require.False(t, ok, aggregation)
+	}
+}
@@ -0,0 +1,337 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+)
+
+const (
+	// APIKeyHeader is the header clients use to send their API key.
+	APIKeyHeader = "X-API-Key"
+
+	bearerPrefix = "Bearer "
+
+	// maxKeySourceSize limits the size of key files and HTTP responses.
+	maxKeySourceSize = 1 << 20
+
+	keySourceHTTPTimeout = 10 * time.Second
+)
+
+// KeySource provides the current set of valid API keys.
+type KeySource interface {
+	Keys(ctx context.Context) ([]string, error)
+}
+
+// NewKeySource creates a KeySource from the given configuration.
+func NewKeySource(cfg *config.DynamicKeySourceConfig) (KeySource, error) {
+	switch cfg.Type {
+	case config.KeySourceFile:
+		return &fileKeySource{path: cfg.Path}, nil
+	case config.KeySourceEnv:
+		return &envKeySource{envVar: cfg.EnvVar}, nil
+	case config.KeySourceHTTP:
+		return &httpKeySource{url: cfg.URL, client: &http.Client{Timeout: keySourceHTTPTimeout}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key source type %q", cfg.Type)
+	}
+}
+
+// fileKeySource reads API keys from a file.
+type fileKeySource struct {
+	path string
+}
+
+func (s *fileKeySource) Keys(_ context.Context) ([]string, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key file: %w", err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxKeySourceSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	return parseKeys(string(data)), nil
+}
+
+// envKeySource reads API keys from an environment variable.
+type envKeySource struct {
+	envVar string
+}
+
+func (s *envKeySource) Keys(_ context.Context) ([]string, error) {
+	value, ok := os.LookupEnv(s.envVar)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %s is not set", s.envVar)
+	}
+
+	return parseKeys(value), nil
+}
+
+// httpKeySource fetches API keys from a URL.
+type httpKeySource struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpKeySource) Keys(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch keys: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch keys: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxKeySourceSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keys response: %w", err)
+	}
+
+	return parseKeys(string(data)), nil
+}
+
+// parseKeys splits the raw key source content on newlines and commas,
+// skipping empty entries and comment lines.
+func parseKeys(raw string) []string {
+	var keys []string
+	for line := range strings.SplitSeq(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		for key := range strings.SplitSeq(line, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	return keys
+}
+
+// KeyStore holds the set of valid API keys. Keys removed by a rotation remain
+// valid until their grace period expires, so clients can switch keys without downtime.
+type KeyStore struct {
+	mu          sync.RWMutex
+	static      []string
+	hashes      [][sha256.Size]byte
+	active      map[string]struct{}
+	retiring    map[string]time.Time // key -> expiry
+	gracePeriod time.Duration
+	now         func() time.Time
+}
+
+// NewKeyStore creates a KeyStore with the given static keys, which are never rotated out.
+func NewKeyStore(staticKeys []string, gracePeriod time.Duration) *KeyStore {
+	ks := &KeyStore{
+		static:      staticKeys,
+		active:      make(map[string]struct{}, len(staticKeys)),
+		retiring:    make(map[string]time.Time),
+		gracePeriod: gracePeriod,
+		now:         time.Now,
+	}
+
+	for _, key := range staticKeys {
+		ks.active[key] = struct{}{}
+	}
+
+	return ks
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 hash of an API key, as configured in api_key_hashes.
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// SetKeyHashes sets the hex-encoded SHA-256 hashes of static keys, which are accepted like
+// the static keys without being stored in plaintext. It must be called before the store is used.
+func (ks *KeyStore) SetKeyHashes(hashes []string) error {
+	decoded := make([][sha256.Size]byte, 0, len(hashes))
+	for _, hash := range hashes {
+		raw, err := hex.DecodeString(hash)
+		if err != nil || len(raw) != sha256.Size {
+			return fmt.Errorf("invalid API key hash %q: must be a hex-encoded SHA-256 hash", hash)
+		}
+		decoded = append(decoded, [sha256.Size]byte(raw))
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.hashes = decoded
+
+	return nil
+}
+
+// Update replaces the set of active keys with the static keys and the given keys.
+// Keys that are no longer active remain valid for the grace period.
+func (ks *KeyStore) Update(keys []string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	now := ks.now()
+
+	active := make(map[string]struct{}, len(ks.static)+len(keys))
+	for _, key := range ks.static {
+		active[key] = struct{}{}
+	}
+	for _, key := range keys {
+		active[key] = struct{}{}
+		delete(ks.retiring, key)
+	}
+
+	for key := range ks.active {
+		if _, ok := active[key]; !ok {
+			ks.retiring[key] = now.Add(ks.gracePeriod)
+		}
+	}
+
+	for key, expiry := range ks.retiring {
+		if !now.Before(expiry) {
+			delete(ks.retiring, key)
+		}
+	}
+
+	ks.active = active
+}
+
+// Valid reports whether the given key is active or still within its grace period.
+func (ks *KeyStore) Valid(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	// Compare in constant time to avoid leaking key contents through timing
+	valid := false
+	for activeKey := range ks.active {
+		if subtle.ConstantTimeCompare([]byte(activeKey), []byte(key)) == 1 {
+			valid = true
+		}
+	}
+
+	now := ks.now()
+	for retiringKey, expiry := range ks.retiring {
+		if subtle.ConstantTimeCompare([]byte(retiringKey), []byte(key)) == 1 && now.Before(expiry) {
+			valid = true
+		}
+	}
+
+	if len(ks.hashes) > 0 {
+		keyHash := sha256.Sum256([]byte(key))
+		for _, hash := range ks.hashes {
+			if subtle.ConstantTimeCompare(hash[:], keyHash[:]) == 1 {
+				valid = true
+			}
+		}
+	}
+
+	return valid
+}
+
+// Refresh loads the current keys from the source and updates the store.
+func (ks *KeyStore) Refresh(ctx context.Context, source KeySource) error {
+	keys, err := source.Keys(ctx)
+	if err != nil {
+		return err
+	}
+
+	ks.Update(keys)
+
+	return nil
+}
+
+// Run refreshes the keys from the source every interval until the context is cancelled.
+// Refresh failures are logged and the previous keys are kept.
+func (ks *KeyStore) Run(ctx context.Context, source KeySource, interval time.Duration, log *logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ks.Refresh(ctx, source); err != nil {
+				log.Warnf("failed to refresh API keys, keeping previous keys: %v", err)
+			}
+		}
+	}
+}
+
+// AuthMiddleware rejects requests without a valid API key with 401 Unauthorized. The key is read from
+// the X-API-Key header or an "Authorization: Bearer <key>" header. Requests to the public paths,
+// such as health checks and documentation, are always accessible.
+func AuthMiddleware(keys *KeyStore, publicPaths []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions || isPublicPath(r.URL.Path, publicPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := r.Header.Get(APIKeyHeader)
+			if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), bearerPrefix); key == "" && ok {
+				key = bearer
+			}
+
+			if !keys.Valid(key) {
+				respondError(w, http.StatusUnauthorized, "invalid or missing API key")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireAPIKey returns a middleware that requires a valid API key, even for public paths.
+// Without a key store, when authentication is disabled, every request is rejected.
+func RequireAPIKey(keys *KeyStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if keys == nil {
+			return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				respondError(w, http.StatusForbidden, "this endpoint requires API authentication to be enabled")
+			})
+		}
+
+		return AuthMiddleware(keys, nil)(next)
+	}
+}
+
+// isPublicPath reports whether the path is one of the public paths, or below one ending with "/".
+func isPublicPath(path string, publicPaths []string) bool {
+	for _, public := range publicPaths {
+		if path == public || (strings.HasSuffix(public, "/") && strings.HasPrefix(path, public)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,335 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+// testPublicPaths are the default public paths of the auth configuration.
+var testPublicPaths = []string{"/health", "/swagger/"}
+
+// newTestKeyStore creates a KeyStore with a controllable clock.
+func newTestKeyStore(staticKeys []string, gracePeriod time.Duration) (*KeyStore, *atomic.Int64) {
+	var now atomic.Int64
+	now.Store(time.Unix(1_700_000_000, 0).UnixNano())
+
+	ks := NewKeyStore(staticKeys, gracePeriod)
+	ks.now = func() time.Time { return time.Unix(0, now.Load()) }
+
+	return ks, &now
+}
+
+func TestParseKeys(t *testing.T) {
+	t.Parallel()
+
+	keys := parseKeys("# comment\n key-1 \n\nkey-2,key-3,\n")
+	require.Equal(t, []string{"key-1", "key-2", "key-3"}, keys)
+	require.Empty(t, parseKeys(""))
+}
+
+func TestKeyStore_RotationWithGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	ks, now := newTestKeyStore([]string{"static"}, time.Minute)
+
+	ks.Update([]string{"old"})
+	require.True(t, ks.Valid("static"))
+	require.True(t, ks.Valid("old"))
+	require.False(t, ks.Valid("new"))
+	require.False(t, ks.Valid(""))
+
+	// Rotate: the old key stays valid during the grace period
+	ks.Update([]string{"new"})
+	require.True(t, ks.Valid("new"))
+	require.True(t, ks.Valid("old"))
+	require.True(t, ks.Valid("static"))
+
+	now.Add(int64(59 * time.Second))
+	require.True(t, ks.Valid("old"))
+
+	now.Add(int64(time.Second))
+	require.False(t, ks.Valid("old"))
+	require.True(t, ks.Valid("new"))
+
+	// Expired keys are dropped on the next update
+	ks.Update([]string{"new"})
+	require.Empty(t, ks.retiring)
+
+	// Static keys are never rotated out
+	ks.Update(nil)
+	require.True(t, ks.Valid("static"))
+	require.True(t, ks.Valid("new"))
+}
+
+func TestKeyStore_ReAddedKeyIsActive(t *testing.T) {
+	t.Parallel()
+
+	ks, now := newTestKeyStore(nil, time.Minute)
+
+	ks.Update([]string{"key"})
+	ks.Update(nil)
+	ks.Update([]string{"key"})
+
+	now.Add(int64(2 * time.Minute))
+	require.True(t, ks.Valid("key"))
+}
+
+func TestKeySources(t *testing.T) {
+	t.Parallel()
+
+	keyFile := filepath.Join(t.TempDir(), "keys.txt")
+	require.NoError(t, os.WriteFile(keyFile, []byte("file-1\nfile-2\n"), 0o600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte("http-1,http-2"))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(failing.Close)
+
+	tests := []struct {
+		name        string
+		cfg         config.DynamicKeySourceConfig
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:     "file",
+			cfg:      config.DynamicKeySourceConfig{Type: config.KeySourceFile, Path: keyFile},
+			expected: []string{"file-1", "file-2"},
+		},
+		{
+			name:        "missing file",
+			cfg:         config.DynamicKeySourceConfig{Type: config.KeySourceFile, Path: keyFile + ".missing"},
+			expectedErr: "failed to open key file",
+		},
+		{
+			name:     "env",
+			cfg:      config.DynamicKeySourceConfig{Type: config.KeySourceEnv, EnvVar: "PATH"},
+			expected: parseKeys(os.Getenv("PATH")),
+		},
+		{
+			name:        "unset env",
+			cfg:         config.DynamicKeySourceConfig{Type: config.KeySourceEnv, EnvVar: "CHAININDEXOR_TEST_UNSET_KEYS"},
+			expectedErr: "is not set",
+		},
+		{
+			name:     "http",
+			cfg:      config.DynamicKeySourceConfig{Type: config.KeySourceHTTP, URL: server.URL},
+			expected: []string{"http-1", "http-2"},
+		},
+		{
+			name:        "http error status",
+			cfg:         config.DynamicKeySourceConfig{Type: config.KeySourceHTTP, URL: failing.URL},
+			expectedErr: "unexpected status 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source, err := NewKeySource(&tt.cfg)
+			require.NoError(t, err)
+
+			keys, err := source.Keys(t.Context())
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, keys)
+		})
+	}
+
+	_, err := NewKeySource(&config.DynamicKeySourceConfig{Type: "vault"})
+	require.ErrorContains(t, err, "unsupported key source type")
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	t.Parallel()
+
+	ks := NewKeyStore([]string{"secret"}, time.Minute)
+	handler := AuthMiddleware(ks, testPublicPaths)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name           string
+		path           string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{
+			name:           "missing key",
+			path:           "/api/v1/indexers",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid key",
+			path:           "/api/v1/indexers",
+			headers:        map[string]string{APIKeyHeader: "wrong"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "api key header",
+			path:           "/api/v1/indexers",
+			headers:        map[string]string{APIKeyHeader: "secret"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "bearer token",
+			path:           "/api/v1/indexers",
+			headers:        map[string]string{"Authorization": "Bearer secret"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "authorization without bearer scheme",
+			path:           "/api/v1/indexers",
+			headers:        map[string]string{"Authorization": "secret"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "health is public",
+			path:           "/health",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+			require.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_KeyHashes(t *testing.T) {
+	t.Parallel()
+
+	const key = "3f1c9a7e5b2d4c6a8e0f1b3d5a7c9e2f4b6d8a0c2e4f6a8b0d2c4e6f8a1b3c5d"
+
+	ks := NewKeyStore(nil, time.Minute)
+	require.NoError(t, ks.SetKeyHashes([]string{HashAPIKey(key)}))
+	require.Error(t, ks.SetKeyHashes([]string{key[:10]}))
+
+	handler := AuthMiddleware(ks, []string{"/health"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name           string
+		path           string
+		token          string
+		expectedStatus int
+	}{
+		{name: "valid token", path: "/api/v1/indexers", token: key, expectedStatus: http.StatusOK},
+		{name: "invalid token", path: "/api/v1/indexers", token: key[1:], expectedStatus: http.StatusUnauthorized},
+		{
+			name:           "hash is not a token",
+			path:           "/api/v1/indexers",
+			token:          HashAPIKey(key),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{name: "missing token", path: "/api/v1/indexers", expectedStatus: http.StatusUnauthorized},
+		{name: "public path", path: "/health", expectedStatus: http.StatusOK},
+		{name: "path outside public paths", path: "/swagger/index.html", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+			require.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_ConcurrentRequestsDuringRotation(t *testing.T) {
+	t.Parallel()
+
+	keyFile := filepath.Join(t.TempDir(), "keys.txt")
+	require.NoError(t, os.WriteFile(keyFile, []byte("old-key"), 0o600))
+
+	source, err := NewKeySource(&config.DynamicKeySourceConfig{Type: config.KeySourceFile, Path: keyFile})
+	require.NoError(t, err)
+
+	ks := NewKeyStore(nil, time.Hour)
+	require.NoError(t, ks.Refresh(t.Context(), source))
+
+	handler := AuthMiddleware(ks, testPublicPaths)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	var (
+		wg       sync.WaitGroup
+		failures atomic.Int64
+		stop     = make(chan struct{})
+	)
+
+	// Clients keep using the old key while the keys are being rotated
+	for range 8 {
+		wg.Go(func() {
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/indexers", nil)
+				req.Header.Set(APIKeyHeader, "old-key")
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, req)
+
+				if w.Code != http.StatusOK {
+					failures.Add(1)
+				}
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go ks.Run(ctx, source, time.Millisecond, logger.NewNopLogger())
+
+	require.NoError(t, os.WriteFile(keyFile, []byte("new-key"), 0o600))
+	require.Eventually(t, func() bool { return ks.Valid("new-key") }, time.Second, time.Millisecond)
+
+	close(stop)
+	wg.Wait()
+
+	require.Zero(t, failures.Load(), "old key must remain valid during the grace period")
+	require.True(t, ks.Valid("old-key"))
+}
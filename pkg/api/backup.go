@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+)
+
+// CreateSnapshot writes a snapshot of the downloader database to a file on the server.
+// @Summary Snapshot the downloader database
+// @Description Write a consistent copy of the downloader database to a new file on the server while indexing continues. Requires an API key, even if the path is public
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body SnapshotRequest true "Snapshot destination"
+// @Success 200 {object} SnapshotResponse "Snapshot written"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 401 {object} ErrorResponse "Invalid or missing API key"
+// @Failure 403 {object} ErrorResponse "API authentication is disabled"
+// @Failure 409 {object} ErrorResponse "Destination file already exists"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Snapshots not available"
+// @Router /admin/snapshot [post]
+func (h *Handler) CreateSnapshot(w http.ResponseWriter, r *http.Request) {
+	if h.backup == nil {
+		respondError(w, http.StatusServiceUnavailable, "snapshots are not available")
+		return
+	}
+
+	var req SnapshotRequest
+	if err := decodeBackupRequest(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid snapshot request: %v", err))
+		return
+	}
+	if req.DestPath == "" {
+		respondError(w, http.StatusBadRequest, "dest_path is required")
+		return
+	}
+
+	if err := h.backup.Snapshot(r.Context(), req.DestPath); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			respondError(w, http.StatusConflict, fmt.Sprintf("%s already exists", req.DestPath))
+			return
+		}
+
+		requestLogger(h.log, r).Errorf("Failed to create snapshot: %v", err)
+		respondError(w, http.StatusInternalServerError, "failed to create snapshot")
+		return
+	}
+
+	response := SnapshotResponse{Path: req.DestPath}
+	if info, err := os.Stat(req.DestPath); err == nil {
+		response.SizeBytes = info.Size()
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// RestoreSnapshot replaces the downloader database with a snapshot file on the server.
+// @Summary Restore the downloader database
+// @Description Replace the downloader database with a snapshot file on the server, taken by the snapshot endpoint. Requires an API key, even if the path is public
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body RestoreRequest true "Snapshot to restore"
+// @Success 200 {object} SnapshotResponse "Snapshot restored"
+// @Failure 400 {object} ErrorResponse "Invalid request or snapshot not found"
+// @Failure 401 {object} ErrorResponse "Invalid or missing API key"
+// @Failure 403 {object} ErrorResponse "API authentication is disabled"
+// @Failure 409 {object} ErrorResponse "Snapshot taken at another migration version"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Snapshots not available"
+// @Router /admin/restore [post]
+func (h *Handler) RestoreSnapshot(w http.ResponseWriter, r *http.Request) {
+	if h.backup == nil {
+		respondError(w, http.StatusServiceUnavailable, "snapshots are not available")
+		return
+	}
+
+	var req RestoreRequest
+	if err := decodeBackupRequest(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid restore request: %v", err))
+		return
+	}
+	if req.SrcPath == "" {
+		respondError(w, http.StatusBadRequest, "src_path is required")
+		return
+	}
+
+	info, err := os.Stat(req.SrcPath)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("snapshot %s not found", req.SrcPath))
+		return
+	}
+
+	if err := h.backup.Restore(r.Context(), req.SrcPath); err != nil {
+		if errors.Is(err, db.ErrSnapshotMigrationMismatch) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+
+		requestLogger(h.log, r).Errorf("Failed to restore snapshot: %v", err)
+		respondError(w, http.StatusInternalServerError, "failed to restore snapshot")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, SnapshotResponse{Path: req.SrcPath, SizeBytes: info.Size()})
+}
+
+// decodeBackupRequest decodes the JSON body of a snapshot or restore request.
+func decodeBackupRequest(r *http.Request, req any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(req)
+}
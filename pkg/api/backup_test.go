@@ -0,0 +1,246 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_CreateSnapshot(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "snapshot.db")
+
+	tests := []struct {
+		name           string
+		body           string
+		noBackup       bool
+		setupMocks     func(backup *apimocks.DatabaseBackup)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "snapshots not configured",
+			body:           fmt.Sprintf(`{"dest_path": %q}`, destPath),
+			noBackup:       true,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"code": 503, "error": "Service Unavailable", "message": "snapshots are not available"}`,
+		},
+		{
+			name:           "unknown field",
+			body:           `{"path": "/backups/snapshot.db"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", ` +
+				`"message": "invalid snapshot request: json: unknown field \"path\""}`,
+		},
+		{
+			name:           "missing destination",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": 400, "error": "Bad Request", "message": "dest_path is required"}`,
+		},
+		{
+			name: "destination exists",
+			body: `{"dest_path": "/backups/snapshot.db"}`,
+			setupMocks: func(backup *apimocks.DatabaseBackup) {
+				backup.EXPECT().Snapshot(mock.Anything, "/backups/snapshot.db").
+					Return(fmt.Errorf("snapshot /backups/snapshot.db: %w", fs.ErrExist))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"code": 409, "error": "Conflict", "message": "/backups/snapshot.db already exists"}`,
+		},
+		{
+			name: "snapshot error",
+			body: `{"dest_path": "/backups/snapshot.db"}`,
+			setupMocks: func(backup *apimocks.DatabaseBackup) {
+				backup.EXPECT().Snapshot(mock.Anything, "/backups/snapshot.db").Return(errors.New("disk full"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"code": 500, "error": "Internal Server Error", "message": "failed to create snapshot"}`,
+		},
+		{
+			name: "successful snapshot",
+			body: fmt.Sprintf(`{"dest_path": %q}`, destPath),
+			setupMocks: func(backup *apimocks.DatabaseBackup) {
+				backup.EXPECT().Snapshot(mock.Anything, destPath).RunAndReturn(func(_ context.Context, path string) error {
+					return os.WriteFile(path, make([]byte, 4096), 0600)
+				})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   fmt.Sprintf(`{"path": %q, "size_bytes": 4096}`, destPath),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			backup := apimocks.NewDatabaseBackup(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(backup)
+			}
+
+			handler := NewHandler(apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+			if !tt.noBackup {
+				handler.backup = backup
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/snapshot", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.CreateSnapshot(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_RestoreSnapshot(t *testing.T) {
+	t.Parallel()
+
+	srcPath := filepath.Join(t.TempDir(), "snapshot.db")
+	require.NoError(t, os.WriteFile(srcPath, make([]byte, 8192), 0600))
+
+	tests := []struct {
+		name           string
+		body           string
+		noBackup       bool
+		setupMocks     func(backup *apimocks.DatabaseBackup)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "snapshots not configured",
+			body:           fmt.Sprintf(`{"src_path": %q}`, srcPath),
+			noBackup:       true,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"code": 503, "error": "Service Unavailable", "message": "snapshots are not available"}`,
+		},
+		{
+			name:           "missing source",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": 400, "error": "Bad Request", "message": "src_path is required"}`,
+		},
+		{
+			name:           "snapshot not found",
+			body:           `{"src_path": "/backups/missing.db"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": 400, "error": "Bad Request", "message": "snapshot /backups/missing.db not found"}`,
+		},
+		{
+			name: "snapshot of another migration version",
+			body: fmt.Sprintf(`{"src_path": %q}`, srcPath),
+			setupMocks: func(backup *apimocks.DatabaseBackup) {
+				backup.EXPECT().Restore(mock.Anything, srcPath).Return(fmt.Errorf("%w: snapshot is at version 8",
+					db.ErrSnapshotMigrationMismatch))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: `{"code": 409, "error": "Conflict", "message": "snapshot migration version does not match ` +
+				`the database: snapshot is at version 8"}`,
+		},
+		{
+			name: "restore error",
+			body: fmt.Sprintf(`{"src_path": %q}`, srcPath),
+			setupMocks: func(backup *apimocks.DatabaseBackup) {
+				backup.EXPECT().Restore(mock.Anything, srcPath).Return(errors.New("snapshot failed integrity check"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"code": 500, "error": "Internal Server Error", "message": "failed to restore snapshot"}`,
+		},
+		{
+			name: "successful restore",
+			body: fmt.Sprintf(`{"src_path": %q}`, srcPath),
+			setupMocks: func(backup *apimocks.DatabaseBackup) {
+				backup.EXPECT().Restore(mock.Anything, srcPath).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   fmt.Sprintf(`{"path": %q, "size_bytes": 8192}`, srcPath),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			backup := apimocks.NewDatabaseBackup(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(backup)
+			}
+
+			handler := NewHandler(apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+			if !tt.noBackup {
+				handler.backup = backup
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/restore", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.RestoreSnapshot(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestServer_BackupRequiresAPIKey(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T, auth *config.AuthConfig) *Server {
+		t.Helper()
+
+		cfg := &config.APIConfig{Enabled: true, ListenAddress: ":8080", Auth: auth}
+		cfg.ApplyDefaults()
+
+		server := NewServer(cfg, apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+
+		backup := apimocks.NewDatabaseBackup(t)
+		backup.EXPECT().Snapshot(mock.Anything, mock.Anything).Return(nil).Maybe()
+		server.SetDatabaseBackup(backup)
+
+		return server
+	}
+
+	snapshot := func(server *Server, key string) int {
+		body := fmt.Sprintf(`{"dest_path": %q}`, filepath.Join(t.TempDir(), "snapshot.db"))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/snapshot", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(APIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		server.server.Handler.ServeHTTP(w, req)
+
+		return w.Code
+	}
+
+	// Without authentication the endpoints are disabled
+	require.Equal(t, http.StatusForbidden, snapshot(newServer(t, nil), ""))
+
+	// Admin paths configured as public still require an API key
+	server := newServer(t, &config.AuthConfig{
+		Enabled:     true,
+		APIKeys:     []string{"secret"},
+		PublicPaths: []string{"/api/v1/admin/"},
+	})
+	require.Equal(t, http.StatusUnauthorized, snapshot(server, ""))
+	require.Equal(t, http.StatusUnauthorized, snapshot(server, "wrong"))
+	require.Equal(t, http.StatusOK, snapshot(server, "secret"))
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	internalcommon "github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
+)
+
+// coverageToBlock is the last block the coverage of an address is read up to. It stands for the end of
+// the chain, as database drivers do not accept unsigned integers above the largest signed one.
+const coverageToBlock = math.MaxInt64
+
+// GetIndexerCoverage returns the block ranges the downloader fetched logs for, for the contracts of an indexer.
+// @Summary Get the log coverage of an indexer
+// @Description Show the block ranges the downloader fetched and stored logs for, for every contract of the indexer, and the ranges from the indexer's start block up to the finalized block that are still missing
+// @Tags Indexers
+// @Produce json
+// @Param name path string true "Indexer name"
+// @Param address query string false "Only report the coverage of this contract address"
+// @Success 200 {object} CoverageResponse "Log coverage by contract address"
+// @Failure 400 {object} ErrorResponse "Invalid address"
+// @Failure 404 {object} ErrorResponse "Indexer or contract not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Coverage not available"
+// @Router /indexers/{name}/coverage [get]
+func (h *Handler) GetIndexerCoverage(w http.ResponseWriter, r *http.Request) {
+	indexerName := r.PathValue("name")
+	if indexerName == "" {
+		respondError(w, http.StatusBadRequest, "indexer name is required")
+		return
+	}
+
+	// Get indexer from registry
+	idx := h.registry.GetByName(indexerName)
+	if idx == nil {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("indexer '%s' not found", indexerName))
+		return
+	}
+
+	if h.logStore == nil {
+		respondError(w, http.StatusServiceUnavailable, "coverage is not available")
+		return
+	}
+
+	addresses := make([]common.Address, 0, len(idx.EventsToIndex()))
+	for address := range idx.EventsToIndex() {
+		addresses = append(addresses, address)
+	}
+
+	if value := r.URL.Query().Get("address"); value != "" {
+		address, err := internalcommon.NormalizeAddress(value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if !slices.Contains(addresses, address) {
+			respondError(w, http.StatusNotFound,
+				fmt.Sprintf("indexer '%s' does not index contract %s", indexerName, address.Hex()))
+			return
+		}
+		addresses = []common.Address{address}
+	}
+
+	header, err := h.rpcClient(indexerName).GetFinalizedBlockHeader(r.Context())
+	if err != nil {
+		requestLogger(h.log, r).Errorf("Failed to get finalized block: %v", err)
+		respondError(w, http.StatusInternalServerError, "failed to get finalized block")
+		return
+	}
+	finalizedBlock := header.Number.Uint64()
+
+	response := CoverageResponse{
+		FinalizedBlock: finalizedBlock,
+		Coverage:       make(map[string][]BlockRange, len(addresses)),
+		MissingRanges:  make(map[string][]BlockRange, len(addresses)),
+	}
+
+	coverage, err := h.addressesCoverage(r.Context(), addresses)
+	if err != nil {
+		requestLogger(h.log, r).Errorf("Failed to get coverage of %s: %v", indexerName, err)
+		respondError(w, http.StatusInternalServerError, "failed to get coverage")
+		return
+	}
+
+	for _, address := range addresses {
+		var missing []store.CoverageRange
+		if startBlock := idx.StartBlock(); startBlock <= finalizedBlock {
+			missing = store.GetMissingRanges(startBlock, finalizedBlock, coverage[address])
+		}
+
+		response.Coverage[address.Hex()] = blockRanges(coverage[address])
+		response.MissingRanges[address.Hex()] = blockRanges(missing)
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// addressesCoverage returns the coverage of the addresses from the log store, ordered by from block.
+// No event signature hashes to the zero topic, so the logs of the addresses are not read along.
+func (h *Handler) addressesCoverage(
+	ctx context.Context,
+	addresses []common.Address,
+) (map[common.Address][]store.CoverageRange, error) {
+	noTopic := common.Hash{}
+
+	_, coverage, err := h.logStore.GetLogsBatch(ctx, addresses, 0, coverageToBlock, []*common.Hash{&noTopic})
+	if err != nil {
+		return nil, err
+	}
+
+	return coverage, nil
+}
+
+// blockRanges converts coverage ranges to the block ranges of the response.
+func blockRanges(coverage []store.CoverageRange) []BlockRange {
+	ranges := make([]BlockRange, len(coverage))
+	for i, r := range coverage {
+		ranges[i] = BlockRange{FromBlock: r.FromBlock, ToBlock: r.ToBlock}
+	}
+
+	return ranges
+}
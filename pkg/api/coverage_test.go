@@ -0,0 +1,228 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
+	storemocks "github.com/goran-ethernal/ChainIndexor/internal/fetcher/store/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
+	indexermocks "github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_GetIndexerCoverage(t *testing.T) {
+	t.Parallel()
+
+	token := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	pool := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	events := map[common.Address]map[common.Hash]struct{}{token: {}, pool: {}}
+	finalized := &types.Header{Number: big.NewInt(1000)}
+
+	// Only the coverage is read, with a topic filter no log matches
+	noLogs := mock.MatchedBy(func(topics []*common.Hash) bool {
+		return len(topics) == 1 && topics[0] != nil && *topics[0] == common.Hash{}
+	})
+	getCoverage := func(logStore *storemocks.LogStore, addresses ...common.Address) *storemocks.LogStore_GetLogsBatch_Call {
+		sameAddresses := mock.MatchedBy(func(requested []common.Address) bool {
+			return len(requested) == len(addresses) && !slices.ContainsFunc(addresses, func(address common.Address) bool {
+				return !slices.Contains(requested, address)
+			})
+		})
+		return logStore.EXPECT().GetLogsBatch(mock.Anything, sameAddresses, uint64(0), uint64(coverageToBlock), noLogs)
+	}
+
+	tests := []struct {
+		name           string
+		indexerName    string
+		query          string
+		noLogStore     bool
+		setupMocks     func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, logStore *storemocks.LogStore, client *rpcmocks.EthClient) //nolint:lll
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "missing indexer name",
+			indexerName:    "",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": 400, "error": "Bad Request", "message": "indexer name is required"}`,
+		},
+		{
+			name:        "indexer not found",
+			indexerName: "nonexistent",
+			setupMocks: func(registry *apimocks.IndexerRegistry, _ *indexermocks.Indexer, _ *storemocks.LogStore, _ *rpcmocks.EthClient) {
+				registry.EXPECT().GetByName("nonexistent").Return(nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"code": 404, "error": "Not Found", "message": "indexer 'nonexistent' not found"}`,
+		},
+		{
+			name:        "log store not configured",
+			indexerName: "test-indexer",
+			noLogStore:  true,
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, _ *storemocks.LogStore, _ *rpcmocks.EthClient) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"code": 503, "error": "Service Unavailable", "message": "coverage is not available"}`,
+		},
+		{
+			name:        "invalid address",
+			indexerName: "test-indexer",
+			query:       "address=0x1234",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, _ *storemocks.LogStore, _ *rpcmocks.EthClient) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.EXPECT().EventsToIndex().Return(events)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": 400, "error": "Bad Request", "message": "invalid address: 0x1234"}`,
+		},
+		{
+			name:        "address not indexed",
+			indexerName: "test-indexer",
+			query:       "address=0x3333333333333333333333333333333333333333",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, _ *storemocks.LogStore, _ *rpcmocks.EthClient) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.EXPECT().EventsToIndex().Return(events)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{"code": 404, "error": "Not Found", "message": ` +
+				`"indexer 'test-indexer' does not index contract 0x3333333333333333333333333333333333333333"}`,
+		},
+		{
+			name:        "finalized block error",
+			indexerName: "test-indexer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, _ *storemocks.LogStore, client *rpcmocks.EthClient) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.EXPECT().EventsToIndex().Return(events)
+				client.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(nil, errors.New("connection refused"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"code": 500, "error": "Internal Server Error", "message": "failed to get finalized block"}`,
+		},
+		{
+			name:        "log store error",
+			indexerName: "test-indexer",
+			query:       "address=" + token.Hex(),
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, logStore *storemocks.LogStore, client *rpcmocks.EthClient) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.EXPECT().EventsToIndex().Return(events)
+				client.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalized, nil)
+				getCoverage(logStore, token).Return(nil, nil, errors.New("database locked"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"code": 500, "error": "Internal Server Error", "message": "failed to get coverage"}`,
+		},
+		{
+			name:        "coverage of all contracts",
+			indexerName: "test-indexer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, logStore *storemocks.LogStore, client *rpcmocks.EthClient) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.EXPECT().EventsToIndex().Return(events)
+				idx.EXPECT().StartBlock().Return(100)
+				client.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalized, nil)
+				getCoverage(logStore, token, pool).Return(nil, map[common.Address][]store.CoverageRange{
+					token: {{FromBlock: 100, ToBlock: 499}, {FromBlock: 600, ToBlock: 900}},
+					pool:  {},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"finalized_block": 1000,
+				"coverage": {
+					"0x1111111111111111111111111111111111111111": [
+						{"from_block": 100, "to_block": 499},
+						{"from_block": 600, "to_block": 900}
+					],
+					"0x2222222222222222222222222222222222222222": []
+				},
+				"missing_ranges": {
+					"0x1111111111111111111111111111111111111111": [
+						{"from_block": 500, "to_block": 599},
+						{"from_block": 901, "to_block": 1000}
+					],
+					"0x2222222222222222222222222222222222222222": [
+						{"from_block": 100, "to_block": 1000}
+					]
+				}
+			}`,
+		},
+		{
+			name:        "coverage of one contract",
+			indexerName: "test-indexer",
+			query:       "address=" + pool.Hex(),
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, logStore *storemocks.LogStore, client *rpcmocks.EthClient) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.EXPECT().EventsToIndex().Return(events)
+				idx.EXPECT().StartBlock().Return(0)
+				client.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalized, nil)
+				getCoverage(logStore, pool).Return(nil, map[common.Address][]store.CoverageRange{
+					pool: {{FromBlock: 0, ToBlock: 1200}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"finalized_block": 1000,
+				"coverage": {"0x2222222222222222222222222222222222222222": [{"from_block": 0, "to_block": 1200}]},
+				"missing_ranges": {"0x2222222222222222222222222222222222222222": []}
+			}`,
+		},
+		{
+			name:        "start block after the finalized block",
+			indexerName: "test-indexer",
+			query:       "address=" + token.Hex(),
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, logStore *storemocks.LogStore, client *rpcmocks.EthClient) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.EXPECT().EventsToIndex().Return(events)
+				idx.EXPECT().StartBlock().Return(2000)
+				client.EXPECT().GetFinalizedBlockHeader(mock.Anything).Return(finalized, nil)
+				getCoverage(logStore, token).Return(nil, map[common.Address][]store.CoverageRange{token: {}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"finalized_block": 1000,
+				"coverage": {"0x1111111111111111111111111111111111111111": []},
+				"missing_ranges": {"0x1111111111111111111111111111111111111111": []}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			idx := indexermocks.NewIndexer(t)
+			logStore := storemocks.NewLogStore(t)
+			client := rpcmocks.NewEthClient(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(registry, idx, logStore, client)
+			}
+
+			handler := NewHandler(registry, client, logger.NewNopLogger())
+			if !tt.noLogStore {
+				handler.logStore = logStore
+			}
+
+			url := fmt.Sprintf("/api/v1/indexers/%s/coverage?%s", tt.indexerName, tt.query)
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.SetPathValue("name", tt.indexerName)
+			w := httptest.NewRecorder()
+
+			handler.GetIndexerCoverage(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
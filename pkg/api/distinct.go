@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+)
+
+const (
+	// defaultDistinctValues is the number of distinct values returned without a max parameter
+	defaultDistinctValues = 1000
+
+	// maxDistinctValues is the largest max parameter accepted
+	maxDistinctValues = 10000
+)
+
+// GetDistinctValues returns the distinct values of a field of the events of a type.
+// @Summary Get the distinct values of an event field
+// @Description Retrieve the unique non-null values of a field of the events of the given type, in ascending order, e.g. to fill the options of a filter. The field must be a column of the event type, as listed by the schema endpoint. With a prefix, only values starting with it are returned, ignoring ASCII letter case
+// @Tags Events
+// @Produce json
+// @Param name path string true "Indexer name"
+// @Param field path string true "Event field, e.g. from_address"
+// @Param event_type query string true "Event type whose values are returned"
+// @Param from_block query integer false "Only consider events from this block number"
+// @Param to_block query integer false "Only consider events up to this block number"
+// @Param prefix query string false "Only return values starting with this prefix"
+// @Param max query integer false "Maximum number of values returned (1-10000)" default(1000)
+// @Success 200 {array} object "Distinct values of the field"
+// @Failure 400 {object} ErrorResponse "Invalid parameters"
+// @Failure 404 {object} ErrorResponse "Indexer not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /indexers/{name}/events/distinct/{field} [get]
+func (h *Handler) GetDistinctValues(w http.ResponseWriter, r *http.Request) {
+	indexerName := r.PathValue("name")
+	if indexerName == "" {
+		respondError(w, http.StatusBadRequest, "indexer name is required")
+		return
+	}
+
+	// Get indexer from registry
+	idx := h.registry.GetByName(indexerName)
+	if idx == nil {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("indexer '%s' not found", indexerName))
+		return
+	}
+
+	queryable, isQueryable := idx.(indexer.Queryable)
+	distinct, isDistinct := idx.(indexer.DistinctQueryable)
+	if !isQueryable || !isDistinct {
+		respondError(w, http.StatusBadRequest,
+			fmt.Sprintf("indexer '%s' does not support distinct value queries", indexerName))
+		return
+	}
+
+	params, err := parseDistinctParams(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid query parameters: %v", err))
+		return
+	}
+
+	if !hasEventType(queryable.GetEventTypes(), params.EventType) {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("unknown event type '%s' of indexer '%s'",
+			params.EventType, indexerName))
+		return
+	}
+
+	values, err := distinct.QueryDistinctValues(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, indexer.ErrUnknownField) {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid field: %v", err))
+			return
+		}
+
+		requestLogger(h.log, r).Errorf("Failed to query distinct values: %v", err)
+		respondError(w, http.StatusInternalServerError, "failed to query distinct values")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, values)
+}
+
+// parseDistinctParams parses the path and query parameters of a distinct values request.
+func parseDistinctParams(r *http.Request) (indexer.DistinctParams, error) {
+	params := indexer.DistinctParams{
+		EventType: r.URL.Query().Get("event_type"),
+		Field:     strings.ToLower(strings.TrimSpace(r.PathValue("field"))),
+		Prefix:    r.URL.Query().Get("prefix"),
+		Limit:     defaultDistinctValues,
+	}
+
+	if params.EventType == "" {
+		return params, fmt.Errorf("event_type is required")
+	}
+	if params.Field == "" {
+		return params, fmt.Errorf("field is required")
+	}
+
+	if maxStr := r.URL.Query().Get("max"); maxStr != "" {
+		limit, err := strconv.Atoi(maxStr)
+		if err != nil || limit < 1 || limit > maxDistinctValues {
+			return params, fmt.Errorf("invalid max: must be between 1 and %d", maxDistinctValues)
+		}
+		params.Limit = limit
+	}
+
+	if fromBlockStr := r.URL.Query().Get("from_block"); fromBlockStr != "" {
+		fromBlock, err := strconv.ParseUint(fromBlockStr, 10, 64)
+		if err != nil {
+			return params, fmt.Errorf("invalid from_block")
+		}
+		params.FromBlock = &fromBlock
+	}
+
+	if toBlockStr := r.URL.Query().Get("to_block"); toBlockStr != "" {
+		toBlock, err := strconv.ParseUint(toBlockStr, 10, 64)
+		if err != nil {
+			return params, fmt.Errorf("invalid to_block")
+		}
+		params.ToBlock = &toBlock
+	}
+
+	return params, nil
+}
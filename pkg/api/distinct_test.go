@@ -0,0 +1,257 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	indexermocks "github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDistinctIndexer is a composite mock that implements the Indexer, Queryable and DistinctQueryable interfaces
+type mockDistinctIndexer struct {
+	*indexermocks.Indexer
+	*indexermocks.Queryable
+	*indexermocks.DistinctQueryable
+}
+
+func TestHandler_GetDistinctValues(t *testing.T) {
+	t.Parallel()
+
+	alice := "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+	bob := "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+
+	tests := []struct {
+		name        string
+		indexerName string
+		field       string
+		queryString string
+		setupMocks  func(registry *apimocks.IndexerRegistry, idx *mockDistinctIndexer)
+		status      int
+		validate    func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "distinct values",
+			indexerName: "test-indexer",
+			field:       "from_address",
+			queryString: "event_type=Transfer&from_block=10&to_block=20",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockDistinctIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+				idx.DistinctQueryable.EXPECT().QueryDistinctValues(mock.Anything,
+					mock.MatchedBy(func(params indexer.DistinctParams) bool {
+						return params.EventType == "Transfer" && params.Field == "from_address" &&
+							*params.FromBlock == 10 && *params.ToBlock == 20 &&
+							params.Prefix == "" && params.Limit == defaultDistinctValues
+					})).Return([]any{alice, bob}, nil)
+			},
+			status: http.StatusOK,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				var values []string
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &values))
+				require.Equal(t, []string{alice, bob}, values)
+			},
+		},
+		{
+			name:        "prefix and max",
+			indexerName: "test-indexer",
+			field:       "from_address",
+			queryString: "event_type=transfer&prefix=0xf39&max=10000",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockDistinctIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+				idx.DistinctQueryable.EXPECT().QueryDistinctValues(mock.Anything,
+					mock.MatchedBy(func(params indexer.DistinctParams) bool {
+						return params.Prefix == "0xf39" && params.Limit == maxDistinctValues &&
+							params.FromBlock == nil && params.ToBlock == nil
+					})).Return([]any{alice}, nil)
+			},
+			status: http.StatusOK,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				var values []string
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &values))
+				require.Equal(t, []string{alice}, values)
+			},
+		},
+		{
+			name:        "no values",
+			indexerName: "test-indexer",
+			field:       "from_address",
+			queryString: "event_type=Transfer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockDistinctIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+				idx.DistinctQueryable.EXPECT().QueryDistinctValues(mock.Anything, mock.Anything).Return([]any{}, nil)
+			},
+			status: http.StatusOK,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.JSONEq(t, `[]`, w.Body.String())
+			},
+		},
+		{
+			name:        "invalid field",
+			indexerName: "test-indexer",
+			field:       "password",
+			queryString: "event_type=Transfer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockDistinctIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+				idx.DistinctQueryable.EXPECT().QueryDistinctValues(mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("%w: Transfer events have no password", indexer.ErrUnknownField))
+			},
+			status: http.StatusBadRequest,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Contains(t, w.Body.String(), "Transfer events have no password")
+			},
+		},
+		{
+			name:        "query failed",
+			indexerName: "test-indexer",
+			field:       "from_address",
+			queryString: "event_type=Transfer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockDistinctIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+				idx.DistinctQueryable.EXPECT().QueryDistinctValues(mock.Anything, mock.Anything).
+					Return(nil, errors.New("database is locked"))
+			},
+			status: http.StatusInternalServerError,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Contains(t, w.Body.String(), "failed to query distinct values")
+			},
+		},
+		{
+			name:        "invalid max",
+			indexerName: "test-indexer",
+			field:       "from_address",
+			queryString: "event_type=Transfer&max=10001",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockDistinctIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+			},
+			status: http.StatusBadRequest,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Contains(t, w.Body.String(), "invalid max")
+			},
+		},
+		{
+			name:        "event type required",
+			indexerName: "test-indexer",
+			field:       "from_address",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockDistinctIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+			},
+			status: http.StatusBadRequest,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Contains(t, w.Body.String(), "event_type is required")
+			},
+		},
+		{
+			name:        "unknown event type",
+			indexerName: "test-indexer",
+			field:       "from_address",
+			queryString: "event_type=Mint",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockDistinctIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+			},
+			status: http.StatusBadRequest,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Contains(t, w.Body.String(), "unknown event type 'Mint'")
+			},
+		},
+		{
+			name:        "indexer not found",
+			indexerName: "unknown",
+			field:       "from_address",
+			queryString: "event_type=Transfer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, _ *mockDistinctIndexer) {
+				registry.EXPECT().GetByName("unknown").Return(nil)
+			},
+			status: http.StatusNotFound,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Contains(t, w.Body.String(), "indexer 'unknown' not found")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			mockIdx := &mockDistinctIndexer{
+				Indexer:           indexermocks.NewIndexer(t),
+				Queryable:         indexermocks.NewQueryable(t),
+				DistinctQueryable: indexermocks.NewDistinctQueryable(t),
+			}
+			tt.setupMocks(registry, mockIdx)
+
+			handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+
+			url := fmt.Sprintf("/api/v1/indexers/%s/events/distinct/%s", tt.indexerName, tt.field)
+			if tt.queryString != "" {
+				url += "?" + tt.queryString
+			}
+
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.SetPathValue("name", tt.indexerName)
+			req.SetPathValue("field", tt.field)
+			w := httptest.NewRecorder()
+
+			handler.GetDistinctValues(w, req)
+
+			require.Equal(t, tt.status, w.Code)
+			tt.validate(t, w)
+		})
+	}
+}
+
+func TestHandler_GetDistinctValues_NotSupported(t *testing.T) {
+	t.Parallel()
+
+	registry := apimocks.NewIndexerRegistry(t)
+	registry.EXPECT().GetByName("test-indexer").Return(&mockQueryableIndexer{
+		Indexer:   indexermocks.NewIndexer(t),
+		Queryable: indexermocks.NewQueryable(t),
+	})
+
+	handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/v1/indexers/test-indexer/events/distinct/from_address?event_type=Transfer", nil)
+	req.SetPathValue("name", "test-indexer")
+	req.SetPathValue("field", "from_address")
+	w := httptest.NewRecorder()
+
+	handler.GetDistinctValues(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "does not support distinct value queries")
+}
@@ -15,6 +15,242 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/reload-labels": {
+            "post": {
+                "description": "Re-read the address labels file of the configuration, replacing the labels it set before. The labels are kept as they are if the file cannot be read. Requires an API key, even if the path is public",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Reload the address labels",
+                "responses": {
+                    "200": {
+                        "description": "Labels reloaded",
+                        "schema": {
+                            "$ref": "#/definitions/api.ReloadLabelsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or missing API key",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "API authentication is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Labels file cannot be read",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Address labels not configured",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/restore": {
+            "post": {
+                "description": "Replace the downloader database with a snapshot file on the server, taken by the snapshot endpoint. Requires an API key, even if the path is public",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Restore the downloader database",
+                "parameters": [
+                    {
+                        "description": "Snapshot to restore",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RestoreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Snapshot restored",
+                        "schema": {
+                            "$ref": "#/definitions/api.SnapshotResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or snapshot not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or missing API key",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "API authentication is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Snapshot taken at another migration version",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Snapshots not available",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/simulate-retention": {
+            "post": {
+                "description": "Show which blocks a retention policy would prune from the downloader's log store and the space it would free, without deleting anything",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Retention"
+                ],
+                "summary": "Simulate a retention policy",
+                "parameters": [
+                    {
+                        "description": "Retention policy to simulate",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/config.RetentionPolicyConfig"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Retention simulation",
+                        "schema": {
+                            "$ref": "#/definitions/store.RetentionSimulation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid retention policy",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Retention simulation not available",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/snapshot": {
+            "post": {
+                "description": "Write a consistent copy of the downloader database to a new file on the server while indexing continues. Requires an API key, even if the path is public",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Snapshot the downloader database",
+                "parameters": [
+                    {
+                        "description": "Snapshot destination",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SnapshotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Snapshot written",
+                        "schema": {
+                            "$ref": "#/definitions/api.SnapshotResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or missing API key",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "API authentication is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Destination file already exists",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Snapshots not available",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/health": {
             "get": {
                 "description": "Check the health status of the API and all registered indexers",
@@ -35,6 +271,52 @@ const docTemplate = `{
                 }
             }
         },
+        "/healthz/live": {
+            "get": {
+                "description": "Report that the API server is running, without checking its dependencies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "The server is running",
+                        "schema": {
+                            "$ref": "#/definitions/api.LivenessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz/ready": {
+            "get": {
+                "description": "Check the RPC connection, the downloader database and every registered indexer.\nReturns 503 listing the failed checks if any check fails",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "All checks passed",
+                        "schema": {
+                            "$ref": "#/definitions/api.ReadinessResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "At least one check failed",
+                        "schema": {
+                            "$ref": "#/definitions/api.ReadinessResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/indexers": {
             "get": {
                 "description": "Get a list of all registered indexers with their event types and available endpoints",
@@ -49,25 +331,969 @@ const docTemplate = `{
                     "200": {
                         "description": "List of indexers",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/api.IndexerInfo"
-                            }
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.IndexerInfo"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/indexers/{name}/coverage": {
+            "get": {
+                "description": "Show the block ranges the downloader fetched and stored logs for, for every contract of the indexer, and the ranges from the indexer's start block up to the finalized block that are still missing",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Indexers"
+                ],
+                "summary": "Get the log coverage of an indexer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Indexer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only report the coverage of this contract address",
+                        "name": "address",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Log coverage by contract address",
+                        "schema": {
+                            "$ref": "#/definitions/api.CoverageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Indexer or contract not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Coverage not available",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexers/{name}/events": {
+            "get": {
+                "description": "Retrieve events from a specific indexer with optional filtering, pagination, and sorting. With aggregate_fn, the matching events are aggregated instead, and the response is an object with the single \"result\" field, a decimal string for every function but count",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get events from an indexer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Indexer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type to filter by",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of events to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of events to skip, up to the max_offset of the indexer (default: 100000). Deprecated: use cursor instead",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The next_cursor of a previous response, to fetch the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter events from this block number",
+                        "name": "from_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter events up to this block number",
+                        "name": "to_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter events from this block timestamp (Unix seconds)",
+                        "name": "from_timestamp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter events up to this block timestamp (Unix seconds)",
+                        "name": "to_timestamp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by address (contract or participant)",
+                        "name": "address",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by event signature hash (32-byte hex)",
+                        "name": "topic0",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by the first indexed parameter, as a topic (32-byte hex)",
+                        "name": "topic1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by the second indexed parameter, as a topic (32-byte hex)",
+                        "name": "topic2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by the third indexed parameter, as a topic (32-byte hex)",
+                        "name": "topic3",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated columns to return for every event, e.g. block_number,from_address. All columns are returned when not set",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add the non-indexed event parameters, ABI-decoded from the raw log data, under a decoded field. Ignored by indexers that do not keep the log data",
+                        "name": "decode_data",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, in order (block_number, tx_index, log_index)",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order: asc or desc",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sum",
+                            "count",
+                            "avg",
+                            "min",
+                            "max"
+                        ],
+                        "type": "string",
+                        "description": "Aggregate the matching events with this function instead of returning them",
+                        "name": "aggregate_fn",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Numeric field to aggregate, e.g. value. Optional for count, which then counts the events",
+                        "name": "aggregate_field",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of events with pagination info",
+                        "schema": {
+                            "$ref": "#/definitions/api.EventResponse"
+                        },
+                        "headers": {
+                            "X-Clamped-Limit": {
+                                "type": "string",
+                                "description": "Set to true when the limit was clamped to api.max_response_rows"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Indexer not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexers/{name}/events/distinct/{field}": {
+            "get": {
+                "description": "Retrieve the unique non-null values of a field of the events of the given type, in ascending order, e.g. to fill the options of a filter. The field must be a column of the event type, as listed by the schema endpoint. With a prefix, only values starting with it are returned, ignoring ASCII letter case",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get the distinct values of an event field",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Indexer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event field, e.g. from_address",
+                        "name": "field",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type whose values are returned",
+                        "name": "event_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only consider events from this block number",
+                        "name": "from_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only consider events up to this block number",
+                        "name": "to_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return values starting with this prefix",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "Maximum number of values returned (1-10000)",
+                        "name": "max",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Distinct values of the field",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Indexer not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexers/{name}/events/first": {
+            "get": {
+                "description": "Retrieve the single earliest indexed event of the given type, ordered by block number and log index",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get the first event from an indexer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Indexer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type to look up",
+                        "name": "event_type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The earliest event",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Indexer or event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexers/{name}/events/last": {
+            "get": {
+                "description": "Retrieve the single most recent indexed event of the given type, ordered by block number and log index",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get the last event from an indexer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Indexer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type to look up",
+                        "name": "event_type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The most recent event",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Indexer or event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexers/{name}/events/pending": {
+            "get": {
+                "description": "Retrieve events that transactions in the mempool are expected to emit, extracted by simulating them against the latest block. Pending events are removed once the transaction's confirmed events are indexed. Requires pending_mode",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get pending events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Indexer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pending events",
+                        "schema": {
+                            "$ref": "#/definitions/api.PendingEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Indexer not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pending mode not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexers/{name}/events/stream": {
+            "get": {
+                "description": "Upgrade to a WebSocket connection that receives a StreamMessage for every batch of events the indexer stores, one message per event type. Only events indexed after the connection is opened are sent. Clients that fall more than max_buffered_messages behind are disconnected with close code 1008",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Stream events from an indexer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Indexer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type to filter by",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only stream events from this block number",
+                        "name": "from_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by address (contract or participant)",
+                        "name": "address",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching to the WebSocket protocol, followed by a stream of messages",
+                        "schema": {
+                            "$ref": "#/definitions/api.StreamMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Indexer not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Event streaming not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexers/{name}/events/timeseries": {
+            "get": {
+                "description": "Retrieve events aggregated by time periods (hour, day, or week) with event counts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get timeseries event data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Indexer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "hour",
+                            "day",
+                            "week"
+                        ],
+                        "type": "string",
+                        "default": "day",
+                        "description": "Time period interval",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by specific event type",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter events from this block number",
+                        "name": "from_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter events up to this block number",
+                        "name": "to_block",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Timeseries data points with event counts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.TimeseriesDataPoint"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Indexer not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexers/{name}/export": {
+            "get": {
+                "description": "Stream all events of a type matching the filters as a CSV or NDJSON file download, in block and log index order. Unlike the events endpoint, the export is not paginated: events are written as they are read from the database. Exports matching more than api.max_export_rows events are rejected. A CSV export of no events is empty, without a header row",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Export events from an indexer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Indexer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type to export",
+                        "name": "event_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Export events from this block number",
+                        "name": "from_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Export events up to this block number",
+                        "name": "to_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Export events from this block timestamp (Unix seconds)",
+                        "name": "from_timestamp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Export events up to this block timestamp (Unix seconds)",
+                        "name": "to_timestamp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by address (contract or participant)",
+                        "name": "address",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by event signature hash (32-byte hex)",
+                        "name": "topic0",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by the first indexed parameter, as a topic (32-byte hex)",
+                        "name": "topic1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by the second indexed parameter, as a topic (32-byte hex)",
+                        "name": "topic2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by the third indexed parameter, as a topic (32-byte hex)",
+                        "name": "topic3",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exported events",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or too many matching events",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Indexer not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexers/{name}/metrics": {
+            "get": {
+                "description": "Retrieve performance and processing metrics for a specific indexer",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Get indexer metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Indexer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Indexer metrics",
+                        "schema": {
+                            "$ref": "#/definitions/api.MetricsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Indexer not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexers/{name}/retention/preview": {
+            "get": {
+                "description": "Show which logs of the indexer's contracts a retention policy would prune from the downloader's log store, without deleting anything",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Retention"
+                ],
+                "summary": "Preview a retention policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Indexer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum database size in MB",
+                        "name": "max_db_size_mb",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of blocks to retain",
+                        "name": "max_blocks",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Retention preview",
+                        "schema": {
+                            "$ref": "#/definitions/store.RetentionPreview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Indexer not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Retention preview not available",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexers/{name}/schema": {
+            "get": {
+                "description": "Retrieve the fields of every event handled by an indexer with their Solidity types and indexed flags",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schema"
+                ],
+                "summary": "Get indexer event schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Indexer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Indexer event schema",
+                        "schema": {
+                            "$ref": "#/definitions/api.EventSchemaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Indexer not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexers/{name}/stats": {
+            "get": {
+                "description": "Retrieve statistics and status information for a specific indexer",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Get indexer statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Indexer name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Indexer statistics",
+                        "schema": {
+                            "$ref": "#/definitions/api.StatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Indexer not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/indexers/{name}/events": {
+        "/indexers/{name}/{path}": {
             "get": {
-                "description": "Retrieve events from a specific indexer with optional filtering, pagination, and sorting",
+                "description": "Serve a GET endpoint provided by the indexer itself below the path of the indexer, e.g. stats/volume of the uniswap_v2 indexer. The query parameters and the response depend on the endpoint, see the documentation of the indexer",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "Indexers"
                 ],
-                "summary": "Get events from an indexer",
+                "summary": "Query an endpoint of an indexer",
                 "parameters": [
                     {
                         "type": "string",
@@ -78,64 +1304,17 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Event type to filter by",
-                        "name": "event_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 100,
-                        "description": "Maximum number of events to return",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Number of events to skip",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter events from this block number",
-                        "name": "from_block",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter events up to this block number",
-                        "name": "to_block",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by address (contract or participant)",
-                        "name": "address",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Field to sort by",
-                        "name": "sort_by",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "asc",
-                            "desc"
-                        ],
-                        "type": "string",
-                        "description": "Sort order: asc or desc",
-                        "name": "sort_order",
-                        "in": "query"
+                        "description": "Path of the endpoint below the indexer, e.g. stats/volume",
+                        "name": "path",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of events with pagination info",
+                        "description": "Response of the endpoint",
                         "schema": {
-                            "$ref": "#/definitions/api.EventResponse"
+                            "type": "object"
                         }
                     },
                     "400": {
@@ -145,7 +1324,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Indexer not found",
+                        "description": "Indexer or endpoint not found",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -159,67 +1338,59 @@ const docTemplate = `{
                 }
             }
         },
-        "/indexers/{name}/events/timeseries": {
+        "/openapi.yaml": {
             "get": {
-                "description": "Retrieve events aggregated by time periods (hour, day, or week) with event counts",
+                "description": "Retrieve the OpenAPI 3.1 specification of this API, generated from the handler annotations",
+                "produces": [
+                    "application/yaml"
+                ],
+                "tags": [
+                    "Docs"
+                ],
+                "summary": "Get the OpenAPI specification",
+                "responses": {
+                    "200": {
+                        "description": "OpenAPI 3.1 specification in YAML",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/query/aggregate": {
+            "post": {
+                "description": "Compute the sum, count, average, minimum or maximum of a numeric event field across several indexers, e.g. the transfer volume of all ERC-20 indexers over a block range. Every indexer aggregates its own events, and the results are returned as exact decimal strings",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Analytics"
                 ],
-                "summary": "Get timeseries event data",
+                "summary": "Aggregate events across indexers",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Indexer name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "enum": [
-                            "hour",
-                            "day",
-                            "week"
-                        ],
-                        "type": "string",
-                        "default": "day",
-                        "description": "Time period interval",
-                        "name": "interval",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by specific event type",
-                        "name": "event_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter events from this block number",
-                        "name": "from_block",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter events up to this block number",
-                        "name": "to_block",
-                        "in": "query"
+                        "description": "Aggregate query",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AggregateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Timeseries data points with event counts",
+                        "description": "Aggregate of every indexer and of all of them",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/api.TimeseriesDataPoint"
-                            }
+                            "$ref": "#/definitions/api.AggregateResult"
                         }
                     },
                     "400": {
-                        "description": "Invalid parameters",
+                        "description": "Invalid query",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -239,46 +1410,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/indexers/{name}/metrics": {
+        "/reorgs": {
             "get": {
-                "description": "Retrieve performance and processing metrics for a specific indexer",
+                "description": "List the chain reorganizations the downloader detected, oldest first, with the first reorged block, the depth and what the reorg was detected by",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Metrics"
+                    "Reorgs"
                 ],
-                "summary": "Get indexer metrics",
+                "summary": "Get the reorg history",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Indexer name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Only list reorgs whose first reorged block is at or after this block",
+                        "name": "from_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only list reorgs whose first reorged block is at or before this block",
+                        "name": "to_block",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Indexer metrics",
+                        "description": "Detected reorgs",
                         "schema": {
-                            "$ref": "#/definitions/api.MetricsResponse"
+                            "$ref": "#/definitions/api.ReorgHistoryResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid parameters",
+                        "description": "Invalid block range",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Indexer not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "503": {
+                        "description": "Reorg history not available",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -286,55 +1462,212 @@ const docTemplate = `{
                 }
             }
         },
-        "/indexers/{name}/stats": {
+        "/status/backfill": {
             "get": {
-                "description": "Retrieve statistics and status information for a specific indexer",
+                "description": "Open a Server-Sent Events stream that receives a BackfillProgressEvent per indexer every 5 seconds. A \":keepalive\" comment is sent every 15 seconds. Once the backfill is complete, a final \"done\" event is sent and the stream is closed",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Status"
+                ],
+                "summary": "Stream backfill progress",
+                "responses": {
+                    "200": {
+                        "description": "Stream of backfill progress events",
+                        "schema": {
+                            "$ref": "#/definitions/api.BackfillProgressEvent"
+                        }
+                    },
+                    "503": {
+                        "description": "Backfill progress not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tx/{txHash}/events": {
+            "get": {
+                "description": "Look up the events emitted in a transaction in every indexer that supports querying, without knowing which indexers handle them. Events are sorted by log index, and carry the name of their indexer and their event type next to the fields returned by the events endpoint. Event types that do not record transaction hashes are skipped. A transaction without indexed events returns an empty list",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Stats"
+                    "Events"
                 ],
-                "summary": "Get indexer statistics",
+                "summary": "Get the events of a transaction",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Indexer name",
-                        "name": "name",
+                        "description": "Transaction hash (32-byte hex)",
+                        "name": "txHash",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Indexer statistics",
+                        "description": "Events of the transaction",
                         "schema": {
-                            "$ref": "#/definitions/api.StatsResponse"
+                            "$ref": "#/definitions/api.TxEventsResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid parameters",
+                        "description": "Invalid transaction hash",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Indexer not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.AggregateRequest": {
+            "description": "Aggregation of a numeric event field across indexers over an optional block range",
+            "type": "object",
+            "properties": {
+                "aggregation": {
+                    "type": "string",
+                    "enum": [
+                        "sum",
+                        "count",
+                        "avg",
+                        "min",
+                        "max"
+                    ],
+                    "example": "sum"
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "Transfer"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "value"
+                },
+                "from_block": {
+                    "type": "integer",
+                    "example": 19500000
+                },
+                "indexers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "usdc",
+                        "usdt"
+                    ]
+                },
+                "to_block": {
+                    "type": "integer",
+                    "example": 19501000
+                }
+            }
+        },
+        "api.AggregateResult": {
+            "description": "Aggregate of all indexers and of every indexer as decimal strings, which omits indexers without events for avg, min and max",
+            "type": "object",
+            "properties": {
+                "by_indexer": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "indexers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "usdc",
+                        "usdt"
+                    ]
+                },
+                "total": {
+                    "type": "string",
+                    "example": "1250000000000000000000"
+                }
+            }
+        },
+        "api.BackfillProgressEvent": {
+            "description": "Backfill progress of an indexer, with the remaining time estimated from its recent sync rate",
+            "type": "object",
+            "properties": {
+                "current_block": {
+                    "type": "integer",
+                    "example": 19000000
+                },
+                "estimated_seconds_remaining": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "indexer_name": {
+                    "type": "string",
+                    "example": "erc20"
+                },
+                "percent_complete": {
+                    "type": "number",
+                    "example": 42.5
+                },
+                "target_block": {
+                    "type": "integer",
+                    "example": 19500000
+                }
+            }
+        },
+        "api.BlockRange": {
+            "description": "Inclusive range of blocks",
+            "type": "object",
+            "properties": {
+                "from_block": {
+                    "type": "integer",
+                    "example": 19000000
+                },
+                "to_block": {
+                    "type": "integer",
+                    "example": 19500000
+                }
+            }
+        },
+        "api.CoverageResponse": {
+            "description": "Block ranges the downloader fetched logs for, and the ranges still missing up to the finalized block, by contract address",
+            "type": "object",
+            "properties": {
+                "coverage": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/api.BlockRange"
+                        }
+                    }
+                },
+                "finalized_block": {
+                    "type": "integer",
+                    "example": 19500000
+                },
+                "missing_ranges": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/api.BlockRange"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
+        },
         "api.ErrorResponse": {
             "description": "Standard error response format",
             "type": "object",
@@ -356,11 +1689,42 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "events": {},
+                "next_cursor": {
+                    "type": "string"
+                },
                 "pagination": {
                     "$ref": "#/definitions/api.PaginationResult"
                 }
             }
         },
+        "api.EventSchema": {
+            "description": "Schema of an indexed event",
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/indexer.EventFieldSchema"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Transfer"
+                }
+            }
+        },
+        "api.EventSchemaResponse": {
+            "description": "Event names, fields, Solidity types and indexed flags of an indexer",
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.EventSchema"
+                    }
+                }
+            }
+        },
         "api.HealthResponse": {
             "description": "Health status of the API and all indexers",
             "type": "object",
@@ -408,6 +1772,10 @@ const docTemplate = `{
             "description": "Status information for a single indexer",
             "type": "object",
             "properties": {
+                "coverage_percentage": {
+                    "type": "number",
+                    "example": 99.7
+                },
                 "event_count": {
                     "type": "integer",
                     "example": 150000
@@ -428,6 +1796,16 @@ const docTemplate = `{
                 }
             }
         },
+        "api.LivenessResponse": {
+            "description": "Liveness status of the API server",
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "api.MetricsResponse": {
             "description": "Performance metrics for an indexer",
             "type": "object",
@@ -472,6 +1850,128 @@ const docTemplate = `{
                 }
             }
         },
+        "api.PendingEventsResponse": {
+            "description": "Events that pending transactions are expected to emit, replaced by confirmed events once indexed",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/downloader.PendingEvent"
+                    }
+                }
+            }
+        },
+        "api.ReadinessCheckResult": {
+            "description": "Result of a single readiness check",
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "example": "rpc"
+                }
+            }
+        },
+        "api.ReadinessResponse": {
+            "description": "Result of every readiness check and the names of the failed ones",
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ReadinessCheckResult"
+                    }
+                },
+                "failed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ready"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ReloadLabelsResponse": {
+            "description": "Address labels loaded by a reload",
+            "type": "object",
+            "properties": {
+                "labels": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "api.ReorgHistoryResponse": {
+            "description": "Chain reorganizations detected by the downloader, oldest first",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "reorgs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/reorg.ReorgEvent"
+                    }
+                }
+            }
+        },
+        "api.RestoreRequest": {
+            "description": "Snapshot file on the server to restore the database from",
+            "type": "object",
+            "properties": {
+                "src_path": {
+                    "type": "string",
+                    "example": "/backups/downloader-2024-01-31.db"
+                }
+            }
+        },
+        "api.SnapshotRequest": {
+            "description": "File on the server to write the snapshot to. It must not exist",
+            "type": "object",
+            "properties": {
+                "dest_path": {
+                    "type": "string",
+                    "example": "/backups/downloader-2024-01-31.db"
+                }
+            }
+        },
+        "api.SnapshotResponse": {
+            "description": "Snapshot file that was written or restored",
+            "type": "object",
+            "properties": {
+                "path": {
+                    "type": "string",
+                    "example": "/backups/downloader-2024-01-31.db"
+                },
+                "size_bytes": {
+                    "type": "integer",
+                    "example": 104857600
+                }
+            }
+        },
         "api.StatsResponse": {
             "description": "Statistics and status information for an indexer",
             "type": "object",
@@ -497,6 +1997,29 @@ const docTemplate = `{
                 }
             }
         },
+        "api.StreamMessage": {
+            "description": "Events of one type indexed from a batch of logs, pushed over the event stream",
+            "type": "object",
+            "properties": {
+                "event_type": {
+                    "type": "string",
+                    "example": "Transfer"
+                },
+                "events": {},
+                "from_block": {
+                    "type": "integer",
+                    "example": 19500000
+                },
+                "indexer": {
+                    "type": "string",
+                    "example": "erc20"
+                },
+                "to_block": {
+                    "type": "integer",
+                    "example": 19500010
+                }
+            }
+        },
         "api.TimeseriesDataPoint": {
             "description": "A data point in a timeseries response",
             "type": "object",
@@ -522,6 +2045,177 @@ const docTemplate = `{
                     "example": "2024-01-15"
                 }
             }
+        },
+        "api.TxEvent": {
+            "description": "Event of a transaction: the fields of the event, as returned by the events endpoint, with its indexer and event type",
+            "type": "object",
+            "properties": {
+                "event_type": {
+                    "type": "string",
+                    "example": "Transfer"
+                },
+                "indexer": {
+                    "type": "string",
+                    "example": "erc20"
+                }
+            }
+        },
+        "api.TxEventsResponse": {
+            "description": "Events of a transaction from every indexer, in log index order",
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.TxEvent"
+                    }
+                },
+                "tx_hash": {
+                    "type": "string",
+                    "example": "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060"
+                }
+            }
+        },
+        "config.RetentionPolicyConfig": {
+            "type": "object",
+            "properties": {
+                "max_blocks": {
+                    "description": "MaxBlocks is the maximum number of blocks to retain (0 = unlimited)",
+                    "type": "integer"
+                },
+                "max_db_size_mb": {
+                    "description": "MaxDBSizeMB is the maximum database size in megabytes (0 = unlimited)",
+                    "type": "integer"
+                }
+            }
+        },
+        "downloader.PendingEvent": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Address is the contract that emits the event.",
+                    "type": "string"
+                },
+                "data": {
+                    "description": "Data is the non-indexed data of the simulated log.",
+                    "type": "string"
+                },
+                "indexer": {
+                    "description": "Indexer is the name of the indexer the event is relevant to.",
+                    "type": "string"
+                },
+                "log_index": {
+                    "description": "LogIndex is the index of the log within the transaction.",
+                    "type": "integer"
+                },
+                "pending": {
+                    "description": "Pending is always true, to tell previews apart from confirmed events.",
+                    "type": "boolean"
+                },
+                "seen_at": {
+                    "description": "SeenAt is when the transaction was seen in the mempool.",
+                    "type": "string"
+                },
+                "topics": {
+                    "description": "Topics are the topics of the simulated log, topic0 first.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tx_hash": {
+                    "description": "TxHash is the hash of the pending transaction.",
+                    "type": "string"
+                }
+            }
+        },
+        "indexer.EventFieldSchema": {
+            "description": "Schema of an event parameter",
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "string",
+                    "example": "from_address"
+                },
+                "indexed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "example": "from"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "address"
+                }
+            }
+        },
+        "reorg.ReorgEvent": {
+            "type": "object",
+            "properties": {
+                "depth": {
+                    "description": "Depth is the number of blocks the detector saw replaced.",
+                    "type": "integer"
+                },
+                "details": {
+                    "description": "Details describes the mismatch the reorg was detected by.",
+                    "type": "string"
+                },
+                "detected_at": {
+                    "description": "DetectedAt is when the reorg was detected.",
+                    "type": "string"
+                },
+                "first_reorg_block": {
+                    "description": "FirstReorgBlock is the first block that was replaced by the reorg.",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID is the sequence number of the reorg in the detector's database.",
+                    "type": "integer"
+                }
+            }
+        },
+        "store.RetentionPreview": {
+            "type": "object",
+            "properties": {
+                "estimated_rows_deleted": {
+                    "description": "EstimatedRowsDeleted is the number of log and coverage rows that would be deleted",
+                    "type": "integer"
+                },
+                "estimated_space_freed_mb": {
+                    "description": "EstimatedSpaceFreedMB is the estimated database space the deleted rows take up",
+                    "type": "integer"
+                },
+                "prune_before_block": {
+                    "description": "PruneBeforeBlock is the block before which logs would be pruned, 0 if nothing would be pruned",
+                    "type": "integer"
+                }
+            }
+        },
+        "store.RetentionSimulation": {
+            "type": "object",
+            "properties": {
+                "block_range_retained": {
+                    "description": "BlockRangeRetained is the first and last block of the coverage that would be kept, [0, 0] if none",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "estimated_logs_deleted": {
+                    "description": "EstimatedLogsDeleted is the number of event logs that would be deleted",
+                    "type": "integer"
+                },
+                "estimated_mb_freed": {
+                    "description": "EstimatedMBFreed is the estimated database space the deleted rows take up, in megabytes",
+                    "type": "number"
+                },
+                "prune_before_block": {
+                    "description": "PruneBeforeBlock is the block before which logs would be pruned, 0 if nothing would be pruned",
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
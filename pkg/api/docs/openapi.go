@@ -0,0 +1,8 @@
+package docs
+
+import _ "embed"
+
+// OpenAPISpec is the OpenAPI 3.1 specification of the API in YAML, generated by api-gen.
+//
+//go:embed openapi.yaml
+var OpenAPISpec []byte
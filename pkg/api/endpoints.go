@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+)
+
+// GetIndexerEndpoint serves an endpoint of an indexer's own, like the swap volume of a Uniswap V2 indexer.
+// @Summary Query an endpoint of an indexer
+// @Description Serve a GET endpoint provided by the indexer itself below the path of the indexer, e.g. stats/volume of the uniswap_v2 indexer. The query parameters and the response depend on the endpoint, see the documentation of the indexer
+// @Tags Indexers
+// @Produce json
+// @Param name path string true "Indexer name"
+// @Param path path string true "Path of the endpoint below the indexer, e.g. stats/volume"
+// @Success 200 {object} object "Response of the endpoint"
+// @Failure 400 {object} ErrorResponse "Invalid parameters"
+// @Failure 404 {object} ErrorResponse "Indexer or endpoint not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /indexers/{name}/{path} [get]
+func (h *Handler) GetIndexerEndpoint(w http.ResponseWriter, r *http.Request) {
+	indexerName := r.PathValue("name")
+	if indexerName == "" {
+		respondError(w, http.StatusBadRequest, "indexer name is required")
+		return
+	}
+
+	// Get indexer from registry
+	idx := h.registry.GetByName(indexerName)
+	if idx == nil {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("indexer '%s' not found", indexerName))
+		return
+	}
+
+	path := strings.Trim(r.PathValue("path"), "/")
+
+	var endpoint indexer.EndpointFunc
+	if provider, ok := idx.(indexer.EndpointProvider); ok {
+		endpoint = provider.Endpoints()[path]
+	}
+	if endpoint == nil {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("endpoint '%s' of indexer '%s' not found", path, indexerName))
+		return
+	}
+
+	response, err := endpoint(r.Context(), r.URL.Query())
+	if err != nil {
+		if errors.Is(err, indexer.ErrInvalidParameter) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		requestLogger(h.log, r).Errorf("Failed to serve endpoint %s of indexer %s: %v", path, indexerName, err)
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("failed to serve endpoint '%s'", path))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
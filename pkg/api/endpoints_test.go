@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	indexermocks "github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+// mockEndpointIndexer is a composite mock that implements the Indexer and EndpointProvider interfaces
+type mockEndpointIndexer struct {
+	*indexermocks.Indexer
+	*indexermocks.EndpointProvider
+}
+
+func TestHandler_GetIndexerEndpoint(t *testing.T) {
+	t.Parallel()
+
+	volume := func(_ context.Context, query url.Values) (any, error) {
+		switch query.Get("address") {
+		case "":
+			return map[string]string{"total_amount0_in": "100"}, nil
+		case "pair":
+			return nil, fmt.Errorf("%w: invalid address %q", indexer.ErrInvalidParameter, "pair")
+		default:
+			return nil, errors.New("database is locked")
+		}
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		query      string
+		setupMocks func(registry *apimocks.IndexerRegistry, idx *mockEndpointIndexer)
+		status     int
+		body       string
+	}{
+		{
+			name: "endpoint served",
+			path: "stats/volume",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockEndpointIndexer) {
+				registry.EXPECT().GetByName("pairs").Return(idx)
+				idx.EndpointProvider.EXPECT().Endpoints().Return(map[string]indexer.EndpointFunc{"stats/volume": volume})
+			},
+			status: http.StatusOK,
+			body:   `{"total_amount0_in":"100"}`,
+		},
+		{
+			name:  "invalid parameter",
+			path:  "stats/volume/",
+			query: "address=pair",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockEndpointIndexer) {
+				registry.EXPECT().GetByName("pairs").Return(idx)
+				idx.EndpointProvider.EXPECT().Endpoints().Return(map[string]indexer.EndpointFunc{"stats/volume": volume})
+			},
+			status: http.StatusBadRequest,
+			body:   `invalid parameter: invalid address \"pair\"`,
+		},
+		{
+			name:  "endpoint failed",
+			path:  "stats/volume",
+			query: "address=0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockEndpointIndexer) {
+				registry.EXPECT().GetByName("pairs").Return(idx)
+				idx.EndpointProvider.EXPECT().Endpoints().Return(map[string]indexer.EndpointFunc{"stats/volume": volume})
+			},
+			status: http.StatusInternalServerError,
+			body:   "failed to serve endpoint 'stats/volume'",
+		},
+		{
+			name: "endpoint not found",
+			path: "stats/fees",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockEndpointIndexer) {
+				registry.EXPECT().GetByName("pairs").Return(idx)
+				idx.EndpointProvider.EXPECT().Endpoints().Return(map[string]indexer.EndpointFunc{"stats/volume": volume})
+			},
+			status: http.StatusNotFound,
+			body:   "endpoint 'stats/fees' of indexer 'pairs' not found",
+		},
+		{
+			name: "indexer without endpoints",
+			path: "stats/volume",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockEndpointIndexer) {
+				registry.EXPECT().GetByName("pairs").Return(idx.Indexer)
+			},
+			status: http.StatusNotFound,
+			body:   "endpoint 'stats/volume' of indexer 'pairs' not found",
+		},
+		{
+			name: "indexer not found",
+			path: "stats/volume",
+			setupMocks: func(registry *apimocks.IndexerRegistry, _ *mockEndpointIndexer) {
+				registry.EXPECT().GetByName("pairs").Return(nil)
+			},
+			status: http.StatusNotFound,
+			body:   "indexer 'pairs' not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			mockIdx := &mockEndpointIndexer{
+				Indexer:          indexermocks.NewIndexer(t),
+				EndpointProvider: indexermocks.NewEndpointProvider(t),
+			}
+			tt.setupMocks(registry, mockIdx)
+
+			handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/indexers/pairs/"+tt.path+"?"+tt.query, nil)
+			req.SetPathValue("name", "pairs")
+			req.SetPathValue("path", tt.path)
+			w := httptest.NewRecorder()
+
+			handler.GetIndexerEndpoint(w, req)
+
+			require.Equal(t, tt.status, w.Code)
+			require.Contains(t, w.Body.String(), tt.body)
+		})
+	}
+}
+
+func TestServer_IndexerEndpointRoute(t *testing.T) {
+	t.Parallel()
+
+	mockIdx := &mockEndpointIndexer{
+		Indexer:          indexermocks.NewIndexer(t),
+		EndpointProvider: indexermocks.NewEndpointProvider(t),
+	}
+	mockIdx.EndpointProvider.EXPECT().Endpoints().Return(map[string]indexer.EndpointFunc{
+		"stats/volume": func(context.Context, url.Values) (any, error) {
+			return map[string]string{"total_amount0_in": "100"}, nil
+		},
+	})
+
+	registry := apimocks.NewIndexerRegistry(t)
+	registry.EXPECT().GetByName("pairs").Return(mockIdx)
+
+	cfg := &config.APIConfig{Enabled: true}
+	cfg.ApplyDefaults()
+	server := NewServer(cfg, registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+
+	// Paths of several segments reach the endpoint
+	w := httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/indexers/pairs/stats/volume", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"total_amount0_in":"100"}`, w.Body.String())
+}
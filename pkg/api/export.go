@@ -0,0 +1,309 @@
+package api
+
+import (
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+)
+
+const (
+	// ExportFormatCSV exports events as CSV, with a header row of the event field names
+	ExportFormatCSV = "csv"
+
+	// ExportFormatNDJSON exports events as newline-delimited JSON, one event object per line
+	ExportFormatNDJSON = "ndjson"
+)
+
+// exportContentTypes are the content types of the export formats.
+var exportContentTypes = map[string]string{
+	ExportFormatCSV:    "text/csv",
+	ExportFormatNDJSON: "application/x-ndjson",
+}
+
+// ExportEvents streams every event matching the filters of a query as a CSV or NDJSON file.
+// @Summary Export events from an indexer
+// @Description Stream all events of a type matching the filters as a CSV or NDJSON file download, in block and log index order. Unlike the events endpoint, the export is not paginated: events are written as they are read from the database. Exports matching more than api.max_export_rows events are rejected. A CSV export of no events is empty, without a header row
+// @Tags Events
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param name path string true "Indexer name"
+// @Param event_type query string true "Event type to export"
+// @Param format query string false "Export format" Enums(csv, ndjson) default(csv)
+// @Param from_block query integer false "Export events from this block number"
+// @Param to_block query integer false "Export events up to this block number"
+// @Param from_timestamp query integer false "Export events from this block timestamp (Unix seconds)"
+// @Param to_timestamp query integer false "Export events up to this block timestamp (Unix seconds)"
+// @Param address query string false "Filter by address (contract or participant)"
+// @Param topic0 query string false "Filter by event signature hash (32-byte hex)"
+// @Param topic1 query string false "Filter by the first indexed parameter, as a topic (32-byte hex)"
+// @Param topic2 query string false "Filter by the second indexed parameter, as a topic (32-byte hex)"
+// @Param topic3 query string false "Filter by the third indexed parameter, as a topic (32-byte hex)"
+// @Success 200 {string} string "Exported events"
+// @Failure 400 {object} ErrorResponse "Invalid parameters or too many matching events"
+// @Failure 404 {object} ErrorResponse "Indexer not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /indexers/{name}/export [get]
+func (h *Handler) ExportEvents(w http.ResponseWriter, r *http.Request) {
+	indexerName := r.PathValue("name")
+	if indexerName == "" {
+		respondError(w, http.StatusBadRequest, "indexer name is required")
+		return
+	}
+
+	// Get indexer from registry
+	idx := h.registry.GetByName(indexerName)
+	if idx == nil {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("indexer '%s' not found", indexerName))
+		return
+	}
+
+	queryable, isQueryable := idx.(indexer.Queryable)
+	exportable, isExportable := idx.(indexer.Exportable)
+	if !isQueryable || !isExportable {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("indexer '%s' does not support exporting", indexerName))
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = ExportFormatCSV
+	}
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		respondError(w, http.StatusBadRequest, "invalid format: must be 'csv' or 'ndjson'")
+		return
+	}
+
+	params, err := parseQueryParams(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid query parameters: %v", err))
+		return
+	}
+
+	if params.EventType == "" {
+		respondError(w, http.StatusBadRequest, "event_type is required")
+		return
+	}
+	if !hasEventType(queryable.GetEventTypes(), params.EventType) {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("unknown event type '%s' of indexer '%s'",
+			params.EventType, indexerName))
+		return
+	}
+
+	log := requestLogger(h.log, r)
+	rc := http.NewResponseController(w)
+
+	// Large exports take longer than the server's write timeout
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Debugf("Failed to clear the write deadline of the export: %v", err)
+	}
+
+	// The response starts with the first event, so errors found before it still get an error response
+	var encoder eventEncoder
+	start := func() {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf("attachment; filename=%q", exportFilename(indexerName, *params, format)))
+		w.WriteHeader(http.StatusOK)
+
+		encoder = newEventEncoder(w, format)
+	}
+
+	err = exportable.ExportEvents(r.Context(), *params, h.maxExportRows, func(event any) error {
+		if encoder == nil {
+			start()
+		}
+
+		return encoder.Encode(event)
+	})
+	if err != nil {
+		if encoder != nil {
+			// The status was already sent, so the client only sees a truncated file
+			log.Errorf("Failed to export events, the export is incomplete: %v", err)
+			return
+		}
+
+		if errors.Is(err, indexer.ErrExportTooLarge) {
+			respondError(w, http.StatusBadRequest,
+				fmt.Sprintf("%v, narrow the block or timestamp range", err))
+			return
+		}
+		if errors.Is(err, indexer.ErrTimestampFilterUnsupported) ||
+			errors.Is(err, indexer.ErrTopicFilterUnsupported) {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid query parameters: %v", err))
+			return
+		}
+
+		log.Errorf("Failed to export events: %v", err)
+		respondError(w, http.StatusInternalServerError, "failed to export events")
+		return
+	}
+
+	if encoder == nil {
+		start()
+	}
+
+	if err := encoder.Flush(); err != nil {
+		log.Errorf("Failed to export events, the export is incomplete: %v", err)
+	}
+}
+
+// hasEventType reports whether eventType is one of the event types, ignoring case.
+func hasEventType(eventTypes []string, eventType string) bool {
+	for _, t := range eventTypes {
+		if strings.EqualFold(t, eventType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exportFilename returns the file name of an export: {name}_{event_type}_{from}_{to}.{ext}.
+// Without a block range, from is 0 and to is "latest".
+func exportFilename(indexerName string, params indexer.QueryParams, format string) string {
+	from, to := "0", "latest"
+	if params.FromBlock != nil {
+		from = strconv.FormatUint(*params.FromBlock, 10)
+	}
+	if params.ToBlock != nil {
+		to = strconv.FormatUint(*params.ToBlock, 10)
+	}
+
+	return fmt.Sprintf("%s_%s_%s_%s.%s", indexerName, strings.ToLower(params.EventType), from, to, format)
+}
+
+// eventEncoder writes exported events in a file format.
+type eventEncoder interface {
+	// Encode writes an event.
+	Encode(event any) error
+
+	// Flush writes any buffered events.
+	Flush() error
+}
+
+// newEventEncoder returns the encoder of the given export format writing to w.
+func newEventEncoder(w io.Writer, format string) eventEncoder {
+	if format == ExportFormatNDJSON {
+		return ndjsonEncoder{encoder: json.NewEncoder(w)}
+	}
+
+	return &csvEncoder{writer: csv.NewWriter(w)}
+}
+
+// ndjsonEncoder writes every event as a JSON object on its own line, as the events endpoint encodes it.
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+// Encode writes the event followed by a newline.
+func (e ndjsonEncoder) Encode(event any) error {
+	return e.encoder.Encode(event)
+}
+
+// Flush is a no-op, since every event is written as soon as it is encoded.
+func (e ndjsonEncoder) Flush() error {
+	return nil
+}
+
+// csvEncoder writes every event as a CSV record. The header row is written with the first event,
+// using the names its fields have in the JSON encoding of the events endpoint.
+type csvEncoder struct {
+	writer *csv.Writer
+	fields []int
+}
+
+// Encode writes the event as a CSV record, preceded by the header row for the first event.
+func (e *csvEncoder) Encode(event any) error {
+	v := reflect.Indirect(reflect.ValueOf(event))
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("unsupported event type %T", event)
+	}
+
+	if e.fields == nil {
+		var header []string
+		e.fields, header = exportFields(v.Type())
+		if err := e.writer.Write(header); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, len(e.fields))
+	for i, field := range e.fields {
+		value, err := csvValue(v.Field(field))
+		if err != nil {
+			return err
+		}
+		record[i] = value
+	}
+
+	return e.writer.Write(record)
+}
+
+// Flush writes the buffered records.
+func (e *csvEncoder) Flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// exportFields returns the indexes and JSON names of the fields of an event type that are encoded to JSON.
+func exportFields(eventType reflect.Type) ([]int, []string) {
+	fields := make([]int, 0, eventType.NumField())
+	names := make([]string, 0, eventType.NumField())
+
+	for i := range eventType.NumField() {
+		field := eventType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+
+		fields = append(fields, i)
+		names = append(names, name)
+	}
+
+	return fields, names
+}
+
+// csvValue formats a field value as its text encoding, e.g. hex for addresses and hashes,
+// falling back to its default format.
+func csvValue(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return "", nil
+	}
+
+	value := v.Interface()
+	if v.CanAddr() {
+		value = v.Addr().Interface()
+	}
+
+	if marshaler, ok := value.(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return "", err
+		}
+		return string(text), nil
+	}
+
+	return fmt.Sprint(v.Interface()), nil
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	indexermocks "github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockExportableIndexer is a composite mock that implements the Indexer, Queryable and Exportable interfaces
+type mockExportableIndexer struct {
+	*indexermocks.Indexer
+	*indexermocks.Queryable
+	*indexermocks.Exportable
+}
+
+// exportTestEvent is an event model with the field types of the models generated by indexer-gen
+type exportTestEvent struct {
+	BlockNumber uint64
+	LogIndex    uint
+	From        common.Address
+	Value       string `json:"value"`
+	Internal    string `json:"-"`
+}
+
+func TestHandler_ExportEvents(t *testing.T) {
+	t.Parallel()
+
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	events := []any{
+		&exportTestEvent{BlockNumber: 1, LogIndex: 0, From: alice, Value: "100", Internal: "x"},
+		&exportTestEvent{BlockNumber: 2, LogIndex: 3, From: alice, Value: "1,5"},
+	}
+
+	exportAll := func(_ context.Context, _ indexer.QueryParams, _ uint64, fn func(event any) error) error {
+		for _, event := range events {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name        string
+		indexerName string
+		queryString string
+		setupMocks  func(registry *apimocks.IndexerRegistry, idx *mockExportableIndexer)
+		status      int
+		validate    func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "csv",
+			indexerName: "test-indexer",
+			queryString: "event_type=Transfer&from_block=1&to_block=2",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockExportableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+				idx.Exportable.EXPECT().ExportEvents(mock.Anything, mock.MatchedBy(func(params indexer.QueryParams) bool {
+					return params.EventType == "Transfer" && *params.FromBlock == 1 && *params.ToBlock == 2
+				}), uint64(1000), mock.Anything).RunAndReturn(exportAll)
+			},
+			status: http.StatusOK,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+				require.Equal(t, `attachment; filename="test-indexer_transfer_1_2.csv"`,
+					w.Header().Get("Content-Disposition"))
+
+				records, err := csv.NewReader(w.Body).ReadAll()
+				require.NoError(t, err)
+				require.Equal(t, [][]string{
+					{"BlockNumber", "LogIndex", "From", "value"},
+					{"1", "0", strings.ToLower(alice.Hex()), "100"},
+					{"2", "3", strings.ToLower(alice.Hex()), "1,5"},
+				}, records)
+			},
+		},
+		{
+			name:        "ndjson",
+			indexerName: "test-indexer",
+			queryString: "event_type=transfer&format=ndjson",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockExportableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+				idx.Exportable.EXPECT().ExportEvents(mock.Anything, mock.Anything, uint64(1000), mock.Anything).
+					RunAndReturn(exportAll)
+			},
+			status: http.StatusOK,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+				require.Equal(t, `attachment; filename="test-indexer_transfer_0_latest.ndjson"`,
+					w.Header().Get("Content-Disposition"))
+
+				from := strings.ToLower(alice.Hex())
+				require.Equal(t,
+					fmt.Sprintf(`{"BlockNumber":1,"LogIndex":0,"From":"%s","value":"100"}`+"\n", from)+
+						fmt.Sprintf(`{"BlockNumber":2,"LogIndex":3,"From":"%s","value":"1,5"}`+"\n", from),
+					w.Body.String())
+			},
+		},
+		{
+			name:        "no matching events",
+			indexerName: "test-indexer",
+			queryString: "event_type=Transfer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockExportableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+				idx.Exportable.EXPECT().ExportEvents(mock.Anything, mock.Anything, uint64(1000), mock.Anything).
+					Return(nil)
+			},
+			status: http.StatusOK,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+				require.Empty(t, w.Body.String())
+			},
+		},
+		{
+			name:        "too many events",
+			indexerName: "test-indexer",
+			queryString: "event_type=Transfer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockExportableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+				idx.Exportable.EXPECT().ExportEvents(mock.Anything, mock.Anything, uint64(1000), mock.Anything).
+					Return(fmt.Errorf("%w: 1001 Transfer events match", indexer.ErrExportTooLarge))
+			},
+			status: http.StatusBadRequest,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Contains(t, w.Body.String(), "1001 Transfer events match")
+			},
+		},
+		{
+			name:        "export failed",
+			indexerName: "test-indexer",
+			queryString: "event_type=Transfer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockExportableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+				idx.Exportable.EXPECT().ExportEvents(mock.Anything, mock.Anything, uint64(1000), mock.Anything).
+					Return(errors.New("database is locked"))
+			},
+			status: http.StatusInternalServerError,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Contains(t, w.Body.String(), "failed to export events")
+			},
+		},
+		{
+			name:        "event type required",
+			indexerName: "test-indexer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockExportableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+			},
+			status: http.StatusBadRequest,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Contains(t, w.Body.String(), "event_type is required")
+			},
+		},
+		{
+			name:        "unknown event type",
+			indexerName: "test-indexer",
+			queryString: "event_type=Mint",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockExportableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+			},
+			status: http.StatusBadRequest,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Contains(t, w.Body.String(), "unknown event type 'Mint'")
+			},
+		},
+		{
+			name:        "invalid format",
+			indexerName: "test-indexer",
+			queryString: "event_type=Transfer&format=xml",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockExportableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+			},
+			status: http.StatusBadRequest,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Contains(t, w.Body.String(), "invalid format")
+			},
+		},
+		{
+			name:        "indexer not found",
+			indexerName: "unknown",
+			queryString: "event_type=Transfer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, _ *mockExportableIndexer) {
+				registry.EXPECT().GetByName("unknown").Return(nil)
+			},
+			status: http.StatusNotFound,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.Contains(t, w.Body.String(), "indexer 'unknown' not found")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			mockIdx := &mockExportableIndexer{
+				Indexer:    indexermocks.NewIndexer(t),
+				Queryable:  indexermocks.NewQueryable(t),
+				Exportable: indexermocks.NewExportable(t),
+			}
+			tt.setupMocks(registry, mockIdx)
+
+			handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+			handler.maxExportRows = 1000
+
+			url := fmt.Sprintf("/api/v1/indexers/%s/export", tt.indexerName)
+			if tt.queryString != "" {
+				url += "?" + tt.queryString
+			}
+
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.SetPathValue("name", tt.indexerName)
+			w := httptest.NewRecorder()
+
+			handler.ExportEvents(w, req)
+
+			require.Equal(t, tt.status, w.Code)
+			tt.validate(t, w)
+		})
+	}
+}
@@ -3,15 +3,25 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	internalcommon "github.com/goran-ethernal/ChainIndexor/internal/common"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/api/docs"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/downloader"
+	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
 	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
 	"github.com/goran-ethernal/ChainIndexor/pkg/rpc"
 )
 
@@ -24,11 +34,86 @@ type IndexerRegistry interface {
 	ListAll() []indexer.Indexer
 }
 
+// RPCClientProvider is implemented by registries whose indexers index different chains.
+type RPCClientProvider interface {
+	// RPCClient returns the RPC client of the chain the named indexer indexes, or nil if it is unknown.
+	RPCClient(indexerName string) rpc.EthClient
+}
+
+// RetentionPreviewer previews the effect of a retention policy on the downloader's log store.
+type RetentionPreviewer interface {
+	// PreviewRetention reports what the policy would delete for the given addresses,
+	// without modifying any data.
+	PreviewRetention(
+		ctx context.Context,
+		policy config.RetentionPolicyConfig,
+		addresses []common.Address,
+	) (*store.RetentionPreview, error)
+
+	// SimulateRetention reports what the policy would delete from the whole log store,
+	// without modifying any data.
+	SimulateRetention(ctx context.Context, policy config.RetentionPolicyConfig) (*store.RetentionSimulation, error)
+}
+
+// DatabaseBackup takes and restores snapshots of the downloader database.
+type DatabaseBackup interface {
+	// Snapshot writes a consistent copy of the database to destPath, which must not exist.
+	Snapshot(ctx context.Context, destPath string) error
+
+	// Restore replaces the database with the snapshot at srcPath.
+	Restore(ctx context.Context, srcPath string) error
+}
+
+// ReorgHistorySource provides the reorgs detected by the downloader.
+type ReorgHistorySource interface {
+	// GetReorgHistory returns the recorded reorgs whose first reorged block is within the
+	// inclusive block range, oldest first.
+	GetReorgHistory(ctx context.Context, fromBlock, toBlock uint64) ([]reorg.ReorgEvent, error)
+}
+
+// CoverageProvider provides the log coverage of the downloader. The indexer registry
+// implements it when the health endpoint should report coverage percentages.
+type CoverageProvider interface {
+	// GetCoverageStats returns the log coverage of every address the downloader has fetched logs for.
+	GetCoverageStats() ([]indexer.CoverageStat, error)
+}
+
+// PendingEventSource provides the previewed events of pending transactions.
+type PendingEventSource interface {
+	// PendingEvents returns the pending events of the given indexer, oldest first.
+	PendingEvents(indexer string) []downloader.PendingEvent
+}
+
+// ProgressSource provides the sync progress events of the downloader.
+type ProgressSource interface {
+	// Subscribe returns a channel receiving every progress event published from now on,
+	// and a function that unsubscribes and closes the channel.
+	Subscribe() (<-chan downloader.ProgressEvent, func())
+}
+
 // Handler handles HTTP requests for the API.
 type Handler struct {
-	registry IndexerRegistry
-	log      *logger.Logger
-	rpc      rpc.EthClient
+	registry  IndexerRegistry
+	log       *logger.Logger
+	rpc       rpc.EthClient
+	retention RetentionPreviewer
+	logStore  store.LogStore
+	backup    DatabaseBackup
+	reorgs    ReorgHistorySource
+	pending   PendingEventSource
+	stream    *EventStream
+	progress  *backfillProgress
+	labels    *LabelRegistry
+
+	// database and readiness configure the checks of the readiness probe
+	database  DatabasePinger
+	readiness config.ReadinessConfig
+
+	// maxExportRows is the maximum number of events an export may return
+	maxExportRows uint64
+
+	// maxResponseRows is the maximum number of events an events request may return
+	maxResponseRows int
 }
 
 // NewHandler creates a new API handler.
@@ -71,19 +156,31 @@ func (h *Handler) ListIndexers(w http.ResponseWriter, r *http.Request) {
 
 // GetEvents retrieves events from a specific indexer.
 // @Summary Get events from an indexer
-// @Description Retrieve events from a specific indexer with optional filtering, pagination, and sorting
+// @Description Retrieve events from a specific indexer with optional filtering, pagination, and sorting. With aggregate_fn, the matching events are aggregated instead, and the response is an object with the single "result" field, a decimal string for every function but count
 // @Tags Events
 // @Produce json
 // @Param name path string true "Indexer name"
 // @Param event_type query string false "Event type to filter by"
 // @Param limit query int false "Maximum number of events to return" default(100)
-// @Param offset query int false "Number of events to skip" default(0)
+// @Param offset query int false "Number of events to skip, up to the max_offset of the indexer (default: 100000). Deprecated: use cursor instead" default(0)
+// @Param cursor query string false "The next_cursor of a previous response, to fetch the next page"
 // @Param from_block query integer false "Filter events from this block number"
 // @Param to_block query integer false "Filter events up to this block number"
+// @Param from_timestamp query integer false "Filter events from this block timestamp (Unix seconds)"
+// @Param to_timestamp query integer false "Filter events up to this block timestamp (Unix seconds)"
 // @Param address query string false "Filter by address (contract or participant)"
-// @Param sort_by query string false "Field to sort by"
+// @Param topic0 query string false "Filter by event signature hash (32-byte hex)"
+// @Param topic1 query string false "Filter by the first indexed parameter, as a topic (32-byte hex)"
+// @Param topic2 query string false "Filter by the second indexed parameter, as a topic (32-byte hex)"
+// @Param topic3 query string false "Filter by the third indexed parameter, as a topic (32-byte hex)"
+// @Param fields query string false "Comma-separated columns to return for every event, e.g. block_number,from_address. All columns are returned when not set"
+// @Param decode_data query bool false "Add the non-indexed event parameters, ABI-decoded from the raw log data, under a decoded field. Ignored by indexers that do not keep the log data"
+// @Param sort_by query string false "Comma-separated fields to sort by, in order (block_number, tx_index, log_index)"
 // @Param sort_order query string false "Sort order: asc or desc" Enums(asc, desc)
+// @Param aggregate_fn query string false "Aggregate the matching events with this function instead of returning them" Enums(sum, count, avg, min, max)
+// @Param aggregate_field query string false "Numeric field to aggregate, e.g. value. Optional for count, which then counts the events"
 // @Success 200 {object} EventResponse "List of events with pagination info"
+// @Header 200 {string} X-Clamped-Limit "Set to true when the limit was clamped to api.max_response_rows"
 // @Failure 400 {object} ErrorResponse "Invalid parameters"
 // @Failure 404 {object} ErrorResponse "Indexer not found"
 // @Failure 500 {object} ErrorResponse "Internal server error"
@@ -116,22 +213,55 @@ func (h *Handler) GetEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Pages larger than the configured maximum are clamped rather than rejected
+	if h.maxResponseRows > 0 && params.Limit > h.maxResponseRows {
+		params.Limit = h.maxResponseRows
+		w.Header().Set("X-Clamped-Limit", "true")
+	}
+
 	// Query events
 	events, total, err := queryable.QueryEvents(r.Context(), *params)
 	if err != nil {
-		h.log.Errorf("Failed to query events: %v", err)
+		if errors.Is(err, indexer.ErrOffsetTooLarge) {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf(
+				"invalid query parameters: %v; page through deep results with the cursor parameter, "+
+					"set to the next_cursor of the previous response", err))
+			return
+		}
+
+		if errors.Is(err, indexer.ErrInvalidCursor) || errors.Is(err, indexer.ErrTimestampFilterUnsupported) ||
+			errors.Is(err, indexer.ErrTopicFilterUnsupported) || errors.Is(err, indexer.ErrUnknownField) ||
+			errors.Is(err, indexer.ErrInvalidAggregation) {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid query parameters: %v", err))
+			return
+		}
+
+		requestLogger(h.log, r).Errorf("Failed to query events: %v", err)
 		respondError(w, http.StatusInternalServerError, "failed to query events")
 		return
 	}
 
+	// Aggregations return a single result instead of a page of events
+	if params.Aggregation != nil {
+		respondJSON(w, http.StatusOK, events)
+		return
+	}
+
 	// Use reflection to get length since events could be any slice type
 	eventsVal := reflect.ValueOf(events)
 	if eventsVal.Kind() != reflect.Slice {
-		h.log.Errorf("Invalid events type returned from indexer '%s': expected slice, got %T", indexerName, events)
+		requestLogger(h.log, r).Errorf("Invalid events type returned from indexer '%s': expected slice, got %T",
+			indexerName, events)
 		respondError(w, http.StatusInternalServerError, "invalid events type returned from indexer")
 		return
 	}
 
+	// With a cursor the position in the result set is unknown, so a full page means there may be more
+	hasMore := params.Offset+eventsVal.Len() < total
+	if params.Cursor != nil {
+		hasMore = eventsVal.Len() == params.Limit
+	}
+
 	// Build response
 	response := EventResponse{
 		Events: events,
@@ -139,13 +269,101 @@ func (h *Handler) GetEvents(w http.ResponseWriter, r *http.Request) {
 			Total:   total,
 			Limit:   params.Limit,
 			Offset:  params.Offset,
-			HasMore: params.Offset+eventsVal.Len() < total,
+			HasMore: hasMore,
 		},
 	}
 
+	// A cursor continues in (block_number, log_index, id) order, so it can only follow pages sorted by block
+	// number first. Without the id, the cursor would skip the remaining events of the log of the last event
+	if hasMore && (params.Cursor != nil || len(params.SortBy) == 0 || params.SortBy[0] == "block_number") {
+		if cursor, ok := indexer.CursorOf(eventsVal.Index(eventsVal.Len() - 1)); ok && cursor.ID != 0 {
+			nextCursor := indexer.EncodeCursor(cursor)
+			response.NextCursor = &nextCursor
+		}
+	}
+
 	respondJSON(w, http.StatusOK, response)
 }
 
+// GetFirstEvent retrieves the earliest indexed event of a given type.
+// @Summary Get the first event from an indexer
+// @Description Retrieve the single earliest indexed event of the given type, ordered by block number and log index
+// @Tags Events
+// @Produce json
+// @Param name path string true "Indexer name"
+// @Param event_type query string true "Event type to look up"
+// @Success 200 {object} any "The earliest event"
+// @Failure 400 {object} ErrorResponse "Invalid parameters"
+// @Failure 404 {object} ErrorResponse "Indexer or event not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /indexers/{name}/events/first [get]
+func (h *Handler) GetFirstEvent(w http.ResponseWriter, r *http.Request) {
+	h.getEdgeEvent(w, r, indexer.Queryable.QueryFirstEvent)
+}
+
+// GetLastEvent retrieves the most recently indexed event of a given type.
+// @Summary Get the last event from an indexer
+// @Description Retrieve the single most recent indexed event of the given type, ordered by block number and log index
+// @Tags Events
+// @Produce json
+// @Param name path string true "Indexer name"
+// @Param event_type query string true "Event type to look up"
+// @Success 200 {object} any "The most recent event"
+// @Failure 400 {object} ErrorResponse "Invalid parameters"
+// @Failure 404 {object} ErrorResponse "Indexer or event not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /indexers/{name}/events/last [get]
+func (h *Handler) GetLastEvent(w http.ResponseWriter, r *http.Request) {
+	h.getEdgeEvent(w, r, indexer.Queryable.QueryLastEvent)
+}
+
+// getEdgeEvent serves the first/last event endpoints using the given query method.
+func (h *Handler) getEdgeEvent(
+	w http.ResponseWriter,
+	r *http.Request,
+	query func(indexer.Queryable, context.Context, string) (any, error),
+) {
+	indexerName := r.PathValue("name")
+	if indexerName == "" {
+		respondError(w, http.StatusBadRequest, "indexer name is required")
+		return
+	}
+
+	// Get indexer from registry
+	idx := h.registry.GetByName(indexerName)
+	if idx == nil {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("indexer '%s' not found", indexerName))
+		return
+	}
+
+	// Check if indexer is queryable
+	queryable, ok := idx.(indexer.Queryable)
+	if !ok {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("indexer '%s' does not support querying", indexerName))
+		return
+	}
+
+	eventType := r.URL.Query().Get("event_type")
+	if eventType == "" {
+		respondError(w, http.StatusBadRequest, "event_type is required")
+		return
+	}
+
+	event, err := query(queryable, r.Context(), eventType)
+	if err != nil {
+		requestLogger(h.log, r).Errorf("Failed to query %s event: %v", eventType, err)
+		respondError(w, http.StatusInternalServerError, "failed to query event")
+		return
+	}
+
+	if event == nil {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("no '%s' events indexed by '%s'", eventType, indexerName))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, event)
+}
+
 // GetStats retrieves statistics for a specific indexer.
 // @Summary Get indexer statistics
 // @Description Retrieve statistics and status information for a specific indexer
@@ -181,7 +399,7 @@ func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
 	// Get stats
 	stats, err := queryable.GetStats(r.Context())
 	if err != nil {
-		h.log.Errorf("Failed to get stats: %v", err)
+		requestLogger(h.log, r).Errorf("Failed to get stats: %v", err)
 		respondError(w, http.StatusInternalServerError, "failed to get stats")
 		return
 	}
@@ -189,6 +407,272 @@ func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, stats)
 }
 
+// GetSchema retrieves the event schema of a specific indexer.
+// @Summary Get indexer event schema
+// @Description Retrieve the fields of every event handled by an indexer with their Solidity types and indexed flags
+// @Tags Schema
+// @Produce json
+// @Param name path string true "Indexer name"
+// @Success 200 {object} EventSchemaResponse "Indexer event schema"
+// @Failure 400 {object} ErrorResponse "Invalid parameters"
+// @Failure 404 {object} ErrorResponse "Indexer not found"
+// @Router /indexers/{name}/schema [get]
+func (h *Handler) GetSchema(w http.ResponseWriter, r *http.Request) {
+	indexerName := r.PathValue("name")
+	if indexerName == "" {
+		respondError(w, http.StatusBadRequest, "indexer name is required")
+		return
+	}
+
+	// Get indexer from registry
+	idx := h.registry.GetByName(indexerName)
+	if idx == nil {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("indexer '%s' not found", indexerName))
+		return
+	}
+
+	// Check if indexer is queryable
+	queryable, ok := idx.(indexer.Queryable)
+	if !ok {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("indexer '%s' does not support querying", indexerName))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, EventSchemaResponse{Events: queryable.GetEventSchema()})
+}
+
+// GetPendingEvents retrieves the previewed events of pending transactions for an indexer.
+// @Summary Get pending events
+// @Description Retrieve events that transactions in the mempool are expected to emit, extracted by simulating them against the latest block. Pending events are removed once the transaction's confirmed events are indexed. Requires pending_mode
+// @Tags Events
+// @Produce json
+// @Param name path string true "Indexer name"
+// @Success 200 {object} PendingEventsResponse "Pending events"
+// @Failure 400 {object} ErrorResponse "Invalid parameters"
+// @Failure 404 {object} ErrorResponse "Indexer not found"
+// @Failure 503 {object} ErrorResponse "Pending mode not enabled"
+// @Router /indexers/{name}/events/pending [get]
+func (h *Handler) GetPendingEvents(w http.ResponseWriter, r *http.Request) {
+	indexerName := r.PathValue("name")
+	if indexerName == "" {
+		respondError(w, http.StatusBadRequest, "indexer name is required")
+		return
+	}
+
+	if h.registry.GetByName(indexerName) == nil {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("indexer '%s' not found", indexerName))
+		return
+	}
+
+	if h.pending == nil {
+		respondError(w, http.StatusServiceUnavailable, "pending mode is not enabled")
+		return
+	}
+
+	events := h.pending.PendingEvents(indexerName)
+	respondJSON(w, http.StatusOK, PendingEventsResponse{Events: events, Count: len(events)})
+}
+
+// StreamEvents streams newly indexed events of an indexer over a WebSocket connection.
+// @Summary Stream events from an indexer
+// @Description Upgrade to a WebSocket connection that receives a StreamMessage for every batch of events the indexer stores, one message per event type. Only events indexed after the connection is opened are sent. Clients that fall more than max_buffered_messages behind are disconnected with close code 1008
+// @Tags Events
+// @Produce json
+// @Param name path string true "Indexer name"
+// @Param event_type query string false "Event type to filter by"
+// @Param from_block query integer false "Only stream events from this block number"
+// @Param address query string false "Filter by address (contract or participant)"
+// @Success 101 {object} StreamMessage "Switching to the WebSocket protocol, followed by a stream of messages"
+// @Failure 400 {object} ErrorResponse "Invalid parameters"
+// @Failure 404 {object} ErrorResponse "Indexer not found"
+// @Failure 503 {object} ErrorResponse "Event streaming not enabled"
+// @Router /indexers/{name}/events/stream [get]
+func (h *Handler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	indexerName := r.PathValue("name")
+	if indexerName == "" {
+		respondError(w, http.StatusBadRequest, "indexer name is required")
+		return
+	}
+
+	// Get indexer from registry
+	idx := h.registry.GetByName(indexerName)
+	if idx == nil {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("indexer '%s' not found", indexerName))
+		return
+	}
+
+	// Check if indexer is queryable
+	queryable, ok := idx.(indexer.Queryable)
+	if !ok {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("indexer '%s' does not support querying", indexerName))
+		return
+	}
+
+	if h.stream == nil {
+		respondError(w, http.StatusServiceUnavailable, "event streaming is not enabled")
+		return
+	}
+
+	// The stream accepts the filters of GetEvents that select events, pagination and sorting do not apply
+	params, err := parseQueryParams(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid query parameters: %v", err))
+		return
+	}
+
+	eventTypes := queryable.GetEventTypes()
+	if params.EventType != "" {
+		i := slices.IndexFunc(eventTypes, func(eventType string) bool {
+			return strings.EqualFold(eventType, params.EventType)
+		})
+		if i < 0 {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("unknown event type '%s'", params.EventType))
+			return
+		}
+		eventTypes = eventTypes[i : i+1]
+	}
+
+	// Upgrade replies with an HTTP error itself when the request is not a valid WebSocket handshake
+	conn, err := streamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		requestLogger(h.log, r).Debugf("Failed to upgrade event stream request: %v", err)
+		return
+	}
+
+	h.stream.serve(conn, &streamClient{
+		indexer:    indexerName,
+		queryable:  queryable,
+		eventTypes: eventTypes,
+		address:    params.Address,
+		fromBlock:  params.FromBlock,
+		send:       make(chan []byte, h.stream.maxBuffered),
+		dropped:    make(chan struct{}),
+	})
+}
+
+// GetRetentionPreview previews what a retention policy would delete for an indexer.
+// @Summary Preview a retention policy
+// @Description Show which logs of the indexer's contracts a retention policy would prune from the downloader's log store, without deleting anything
+// @Tags Retention
+// @Produce json
+// @Param name path string true "Indexer name"
+// @Param max_db_size_mb query integer false "Maximum database size in MB"
+// @Param max_blocks query integer false "Maximum number of blocks to retain"
+// @Success 200 {object} store.RetentionPreview "Retention preview"
+// @Failure 400 {object} ErrorResponse "Invalid parameters"
+// @Failure 404 {object} ErrorResponse "Indexer not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Retention preview not available"
+// @Router /indexers/{name}/retention/preview [get]
+func (h *Handler) GetRetentionPreview(w http.ResponseWriter, r *http.Request) {
+	indexerName := r.PathValue("name")
+	if indexerName == "" {
+		respondError(w, http.StatusBadRequest, "indexer name is required")
+		return
+	}
+
+	// Get indexer from registry
+	idx := h.registry.GetByName(indexerName)
+	if idx == nil {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("indexer '%s' not found", indexerName))
+		return
+	}
+
+	if h.retention == nil {
+		respondError(w, http.StatusServiceUnavailable, "retention preview is not available")
+		return
+	}
+
+	policy, err := parseRetentionPolicy(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid query parameters: %v", err))
+		return
+	}
+
+	addresses := make([]common.Address, 0, len(idx.EventsToIndex()))
+	for address := range idx.EventsToIndex() {
+		addresses = append(addresses, address)
+	}
+
+	preview, err := h.retention.PreviewRetention(r.Context(), policy, addresses)
+	if err != nil {
+		requestLogger(h.log, r).Errorf("Failed to preview retention: %v", err)
+		respondError(w, http.StatusInternalServerError, "failed to preview retention")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, preview)
+}
+
+// parseRetentionPolicy parses the retention policy to preview from the query parameters.
+func parseRetentionPolicy(r *http.Request) (config.RetentionPolicyConfig, error) {
+	var policy config.RetentionPolicyConfig
+
+	query := r.URL.Query()
+	if value := query.Get("max_db_size_mb"); value != "" {
+		maxDBSizeMB, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return policy, fmt.Errorf("invalid max_db_size_mb: %w", err)
+		}
+		policy.MaxDBSizeMB = maxDBSizeMB
+	}
+
+	if value := query.Get("max_blocks"); value != "" {
+		maxBlocks, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return policy, fmt.Errorf("invalid max_blocks: %w", err)
+		}
+		policy.MaxBlocks = maxBlocks
+	}
+
+	if !policy.IsEnabled() {
+		return policy, errors.New("max_db_size_mb or max_blocks must be greater than 0")
+	}
+
+	return policy, nil
+}
+
+// SimulateRetention simulates a retention policy on the whole log store of the downloader.
+// @Summary Simulate a retention policy
+// @Description Show which blocks a retention policy would prune from the downloader's log store and the space it would free, without deleting anything
+// @Tags Retention
+// @Accept json
+// @Produce json
+// @Param policy body config.RetentionPolicyConfig true "Retention policy to simulate"
+// @Success 200 {object} store.RetentionSimulation "Retention simulation"
+// @Failure 400 {object} ErrorResponse "Invalid retention policy"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Retention simulation not available"
+// @Router /admin/simulate-retention [post]
+func (h *Handler) SimulateRetention(w http.ResponseWriter, r *http.Request) {
+	if h.retention == nil {
+		respondError(w, http.StatusServiceUnavailable, "retention simulation is not available")
+		return
+	}
+
+	var policy config.RetentionPolicyConfig
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid retention policy: %v", err))
+		return
+	}
+
+	if !policy.IsEnabled() {
+		respondError(w, http.StatusBadRequest, "max_db_size_mb or max_blocks must be greater than 0")
+		return
+	}
+
+	simulation, err := h.retention.SimulateRetention(r.Context(), policy)
+	if err != nil {
+		requestLogger(h.log, r).Errorf("Failed to simulate retention: %v", err)
+		respondError(w, http.StatusInternalServerError, "failed to simulate retention")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, simulation)
+}
+
 // GetEventsTimeseries retrieves time-series aggregated event data.
 // @Summary Get timeseries event data
 // @Description Retrieve events aggregated by time periods (hour, day, or week) with event counts
@@ -233,12 +717,12 @@ func (h *Handler) GetEventsTimeseries(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Add RPC client to context so generated code can access it
-	ctx := context.WithValue(r.Context(), RPCClientContextKey{}, h.rpc)
+	ctx := context.WithValue(r.Context(), RPCClientContextKey{}, h.rpcClient(indexerName))
 
 	// Query timeseries data
 	data, err := queryable.QueryEventsTimeseries(ctx, *params)
 	if err != nil {
-		h.log.Errorf("Failed to query events timeseries: %v", err)
+		requestLogger(h.log, r).Errorf("Failed to query events timeseries: %v", err)
 		respondError(w, http.StatusInternalServerError, "failed to query events timeseries")
 		return
 	}
@@ -281,7 +765,7 @@ func (h *Handler) GetMetrics(w http.ResponseWriter, r *http.Request) {
 	// Get metrics
 	metrics, err := queryable.GetMetrics(r.Context())
 	if err != nil {
-		h.log.Errorf("Failed to get metrics: %v", err)
+		requestLogger(h.log, r).Errorf("Failed to get metrics: %v", err)
 		respondError(w, http.StatusInternalServerError, "failed to get metrics")
 		return
 	}
@@ -289,6 +773,17 @@ func (h *Handler) GetMetrics(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, metrics)
 }
 
+// rpcClient returns the RPC client of the chain the named indexer indexes.
+func (h *Handler) rpcClient(indexerName string) rpc.EthClient {
+	if provider, ok := h.registry.(RPCClientProvider); ok {
+		if client := provider.RPCClient(indexerName); client != nil {
+			return client
+		}
+	}
+
+	return h.rpc
+}
+
 // Health returns the health status of the API and all indexers.
 // @Summary Health check
 // @Description Check the health status of the API and all registered indexers
@@ -298,6 +793,7 @@ func (h *Handler) GetMetrics(w http.ResponseWriter, r *http.Request) {
 // @Router /health [get]
 func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	indexers := h.registry.ListAll()
+	coverage, latestCoveredBlock := h.coverageStats(requestLogger(h.log, r))
 
 	var statuses []IndexerStatus
 	for _, idx := range indexers {
@@ -317,6 +813,10 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 
+			if coverage != nil {
+				status.CoveragePercentage = coveragePercentage(idx, coverage, latestCoveredBlock)
+			}
+
 			statuses = append(statuses, status)
 		}
 	}
@@ -330,14 +830,78 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, response)
 }
 
+// GetOpenAPISpec serves the OpenAPI specification of the API.
+// @Summary Get the OpenAPI specification
+// @Description Retrieve the OpenAPI 3.1 specification of this API, generated from the handler annotations
+// @Tags Docs
+// @Produce application/yaml
+// @Success 200 {string} string "OpenAPI 3.1 specification in YAML"
+// @Router /openapi.yaml [get]
+func (h *Handler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(docs.OpenAPISpec); err != nil {
+		requestLogger(h.log, r).Errorf("Failed to write OpenAPI spec: %v", err)
+	}
+}
+
+// coverageStats returns the coverage stats by address and the latest block covered for any address.
+// It returns nil if the registry does not provide coverage or it cannot be read.
+func (h *Handler) coverageStats(log *logger.Logger) (map[string]indexer.CoverageStat, uint64) {
+	provider, ok := h.registry.(CoverageProvider)
+	if !ok {
+		return nil, 0
+	}
+
+	stats, err := provider.GetCoverageStats()
+	if err != nil {
+		log.Errorf("Failed to get coverage stats: %v", err)
+		return nil, 0
+	}
+
+	var latestBlock uint64
+	byAddress := make(map[string]indexer.CoverageStat, len(stats))
+	for _, stat := range stats {
+		byAddress[stat.Address] = stat
+		latestBlock = max(latestBlock, stat.LatestBlock)
+	}
+
+	return byAddress, latestBlock
+}
+
+// coveragePercentage returns the share of blocks from the indexer's start block up to latestBlock
+// that logs were fetched for, over all contracts of the indexer. Blocks covered before the start
+// block, e.g. for another indexer of the same contract, are capped so the result never exceeds 100.
+// It returns nil if the indexer has no blocks to cover yet.
+func coveragePercentage(idx indexer.Indexer, coverage map[string]indexer.CoverageStat, latestBlock uint64) *float64 {
+	startBlock := idx.StartBlock()
+	if latestBlock < startBlock {
+		return nil
+	}
+	expectedPerAddress := latestBlock - startBlock + 1
+
+	var covered, expected uint64
+	for address := range idx.EventsToIndex() {
+		expected += expectedPerAddress
+		covered += min(coverage[address.Hex()].TotalCoveredBlocks, expectedPerAddress)
+	}
+
+	if expected == 0 {
+		return nil
+	}
+
+	percentage := float64(covered) / float64(expected) * 100 //nolint:mnd
+	return &percentage
+}
+
 // parseQueryParams parses HTTP query parameters into QueryParams.
 func parseQueryParams(r *http.Request) (*indexer.QueryParams, error) {
 	params := indexer.NewDefaultQueryParams()
 
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 		limit, err := strconv.Atoi(limitStr)
-		if err != nil || limit < 1 || limit > 1000 {
-			return params, fmt.Errorf("invalid limit: must be between 1 and 1000")
+		if err != nil || limit < 1 {
+			return params, fmt.Errorf("invalid limit: must be a positive integer")
 		}
 		params.Limit = limit
 	}
@@ -350,6 +914,13 @@ func parseQueryParams(r *http.Request) (*indexer.QueryParams, error) {
 		params.Offset = offset
 	}
 
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		if _, err := indexer.DecodeCursor(cursor); err != nil {
+			return params, err
+		}
+		params.Cursor = &cursor
+	}
+
 	if fromBlockStr := r.URL.Query().Get("from_block"); fromBlockStr != "" {
 		fromBlock, err := strconv.ParseUint(fromBlockStr, 10, 64)
 		if err != nil {
@@ -366,16 +937,80 @@ func parseQueryParams(r *http.Request) (*indexer.QueryParams, error) {
 		params.ToBlock = &toBlock
 	}
 
-	if address := r.URL.Query().Get("address"); address != "" {
-		params.Address = address
+	if fromTimestampStr := r.URL.Query().Get("from_timestamp"); fromTimestampStr != "" {
+		fromTimestamp, err := strconv.ParseUint(fromTimestampStr, 10, 64)
+		if err != nil {
+			return params, fmt.Errorf("invalid from_timestamp")
+		}
+		params.FromTimestamp = &fromTimestamp
+	}
+
+	if toTimestampStr := r.URL.Query().Get("to_timestamp"); toTimestampStr != "" {
+		toTimestamp, err := strconv.ParseUint(toTimestampStr, 10, 64)
+		if err != nil {
+			return params, fmt.Errorf("invalid to_timestamp")
+		}
+		params.ToTimestamp = &toTimestamp
+	}
+
+	if value := r.URL.Query().Get("address"); value != "" {
+		address, err := internalcommon.NormalizeAddress(value)
+		if err != nil {
+			return params, err
+		}
+		params.Address = address.Hex()
 	}
 
 	if eventType := r.URL.Query().Get("event_type"); eventType != "" {
 		params.EventType = eventType
 	}
 
+	topics := []**common.Hash{&params.Topic0, &params.Topic1, &params.Topic2, &params.Topic3}
+	for i, topic := range topics {
+		name := fmt.Sprintf("topic%d", i)
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+
+		hash, err := parseHash(value)
+		if err != nil {
+			return params, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		*topic = &hash
+	}
+
+	if fields := r.URL.Query().Get("fields"); fields != "" {
+		for _, field := range strings.Split(strings.ToLower(fields), ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				return params, fmt.Errorf("invalid fields: empty field")
+			}
+			if !slices.Contains(params.Fields, field) {
+				params.Fields = append(params.Fields, field)
+			}
+		}
+	}
+
+	if decodeData := r.URL.Query().Get("decode_data"); decodeData != "" {
+		decode, err := strconv.ParseBool(decodeData)
+		if err != nil {
+			return params, fmt.Errorf("invalid decode_data: must be true or false")
+		}
+		params.DecodeData = decode
+	}
+
 	if sortBy := r.URL.Query().Get("sort_by"); sortBy != "" {
-		params.SortBy = strings.ToLower(sortBy)
+		for _, column := range strings.Split(strings.ToLower(sortBy), ",") {
+			column = strings.TrimSpace(column)
+			if !indexer.IsSortColumn(column) {
+				return params, fmt.Errorf("invalid sort_by: unknown column '%s'", column)
+			}
+			if slices.Contains(params.SortBy, column) {
+				return params, fmt.Errorf("invalid sort_by: duplicate column '%s'", column)
+			}
+			params.SortBy = append(params.SortBy, column)
+		}
 	}
 
 	if sortOrder := r.URL.Query().Get("sort_order"); sortOrder != "" {
@@ -386,6 +1021,20 @@ func parseQueryParams(r *http.Request) (*indexer.QueryParams, error) {
 		params.SortOrder = sortOrder
 	}
 
+	function := strings.ToLower(r.URL.Query().Get("aggregate_fn"))
+	field := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("aggregate_field")))
+	if function != "" || field != "" {
+		switch function {
+		case indexer.AggregationSum, indexer.AggregationCount, indexer.AggregationAvg,
+			indexer.AggregationMin, indexer.AggregationMax:
+		case "":
+			return params, fmt.Errorf("invalid aggregate_field: aggregate_fn is required")
+		default:
+			return params, fmt.Errorf("invalid aggregate_fn: must be one of sum, count, avg, min, max")
+		}
+		params.Aggregation = &indexer.Aggregation{Field: field, Function: function}
+	}
+
 	return params, nil
 }
 
@@ -430,6 +1079,16 @@ func parseTimeseriesParams(r *http.Request) (*indexer.TimeseriesParams, error) {
 	return params, nil
 }
 
+// parseHash parses a topic or transaction hash: a 32-byte hex string with a 0x prefix.
+func parseHash(value string) (common.Hash, error) {
+	data, err := hexutil.Decode(value)
+	if err != nil || len(data) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("must be a 32-byte hex string with a 0x prefix")
+	}
+
+	return common.BytesToHash(data), nil
+}
+
 // respondJSON sends a JSON response.
 func respondJSON(w http.ResponseWriter, status int, data any) {
 	w.Header().Set("Content-Type", "application/json")
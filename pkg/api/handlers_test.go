@@ -6,11 +6,18 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/downloader"
+	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
 	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
 	indexermocks "github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
 	"github.com/stretchr/testify/mock"
@@ -33,6 +40,13 @@ func newMockQueryableIndexer(t *testing.T) *mockQueryableIndexer {
 	}
 }
 
+// testEvent is an event model like the ones generated by indexer-gen
+type testEvent struct {
+	ID          int64  `meddler:"id,pk"`
+	BlockNumber uint64 `meddler:"block_number"`
+	LogIndex    uint   `meddler:"log_index"`
+}
+
 func TestRespondJSON(t *testing.T) {
 	t.Parallel()
 
@@ -174,7 +188,7 @@ func TestParseQueryParams(t *testing.T) {
 				require.NoError(t, err)
 				require.Equal(t, 100, params.Limit)
 				require.Equal(t, 0, params.Offset)
-				require.Equal(t, "", params.SortBy)
+				require.Empty(t, params.SortBy)
 				require.Equal(t, "desc", params.SortOrder)
 			},
 		},
@@ -202,14 +216,46 @@ func TestParseQueryParams(t *testing.T) {
 				require.Equal(t, uint64(2000), *params.ToBlock)
 			},
 		},
+		{
+			name:        "timestamp range",
+			queryString: "from_timestamp=1700000000&to_timestamp=1700086400",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, params.FromTimestamp)
+				require.NotNil(t, params.ToTimestamp)
+				require.Equal(t, uint64(1700000000), *params.FromTimestamp)
+				require.Equal(t, uint64(1700086400), *params.ToTimestamp)
+			},
+		},
 		{
 			name:        "address filter",
-			queryString: "address=0x1234567890abcdef",
+			queryString: "address=0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Equal(t, "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", params.Address)
+			},
+		},
+		{
+			name:        "address filter in uppercase",
+			queryString: "address=0xA0B86991C6218B36C1D19D4A2E9EB0CE3606EB48",
 			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
 				t.Helper()
 
 				require.NoError(t, err)
-				require.Equal(t, "0x1234567890abcdef", params.Address)
+				require.Equal(t, "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", params.Address)
+			},
+		},
+		{
+			name:        "invalid address",
+			queryString: "address=0x1234567890abcdef",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.EqualError(t, err, "invalid address: 0x1234567890abcdef")
 			},
 		},
 		{
@@ -222,6 +268,44 @@ func TestParseQueryParams(t *testing.T) {
 				require.Equal(t, "Transfer", params.EventType)
 			},
 		},
+		{
+			name: "topic filters",
+			queryString: "topic0=0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" +
+				"&topic2=0x00000000000000000000000070997970C51812dc3A010C7d01b50e0d17dc79C8",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, params.Topic0)
+				require.Equal(t, common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"),
+					*params.Topic0)
+				require.Nil(t, params.Topic1)
+				require.NotNil(t, params.Topic2)
+				require.Equal(t, common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+					common.BytesToAddress(params.Topic2.Bytes()))
+				require.Nil(t, params.Topic3)
+			},
+		},
+		{
+			name:        "invalid topic - too short",
+			queryString: "topic1=0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "invalid topic1")
+			},
+		},
+		{
+			name:        "invalid topic - not hex",
+			queryString: "topic3=transfer",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "invalid topic3")
+			},
+		},
 		{
 			name:        "sort parameters",
 			queryString: "sort_by=tx_index&sort_order=asc",
@@ -229,10 +313,85 @@ func TestParseQueryParams(t *testing.T) {
 				t.Helper()
 
 				require.NoError(t, err)
-				require.Equal(t, "tx_index", params.SortBy)
+				require.Equal(t, []string{"tx_index"}, params.SortBy)
 				require.Equal(t, "asc", params.SortOrder)
 			},
 		},
+		{
+			name:        "several sort columns",
+			queryString: "sort_by=Block_Number,%20log_index,tx_index",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Equal(t, []string{"block_number", "log_index", "tx_index"}, params.SortBy)
+			},
+		},
+		{
+			name:        "fields",
+			queryString: "fields=Block_Number,%20from_address,block_number",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Equal(t, []string{"block_number", "from_address"}, params.Fields)
+			},
+		},
+		{
+			name:        "empty field",
+			queryString: "fields=block_number,,from_address",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.EqualError(t, err, "invalid fields: empty field")
+			},
+		},
+		{
+			name:        "decode data",
+			queryString: "decode_data=true",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.True(t, params.DecodeData)
+			},
+		},
+		{
+			name:        "invalid decode data",
+			queryString: "decode_data=yes",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.EqualError(t, err, "invalid decode_data: must be true or false")
+			},
+		},
+		{
+			name:        "unknown sort column",
+			queryString: "sort_by=block_number,value",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.EqualError(t, err, "invalid sort_by: unknown column 'value'")
+			},
+		},
+		{
+			name:        "empty sort column",
+			queryString: "sort_by=block_number,",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.EqualError(t, err, "invalid sort_by: unknown column ''")
+			},
+		},
+		{
+			name:        "duplicate sort column",
+			queryString: "sort_by=log_index,block_number,log_index",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.EqualError(t, err, "invalid sort_by: duplicate column 'log_index'")
+			},
+		},
 		{
 			name:        "sort order uppercase",
 			queryString: "sort_order=DESC",
@@ -245,7 +404,7 @@ func TestParseQueryParams(t *testing.T) {
 		},
 		{
 			name:        "all parameters",
-			queryString: "limit=25&offset=50&from_block=100&to_block=200&address=0xabc&event_type=Approval&sort_by=log_index&sort_order=asc",
+			queryString: "limit=25&offset=50&from_block=100&to_block=200&address=0x00000000000000000000000000000000000000aB&event_type=Approval&sort_by=log_index&sort_order=asc",
 			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
 				t.Helper()
 
@@ -256,9 +415,9 @@ func TestParseQueryParams(t *testing.T) {
 				require.Equal(t, uint64(100), *params.FromBlock)
 				require.NotNil(t, params.ToBlock)
 				require.Equal(t, uint64(200), *params.ToBlock)
-				require.Equal(t, "0xabc", params.Address)
+				require.Equal(t, "0x00000000000000000000000000000000000000AB", params.Address)
 				require.Equal(t, "Approval", params.EventType)
-				require.Equal(t, "log_index", params.SortBy)
+				require.Equal(t, []string{"log_index"}, params.SortBy)
 				require.Equal(t, "asc", params.SortOrder)
 			},
 		},
@@ -273,13 +432,13 @@ func TestParseQueryParams(t *testing.T) {
 			},
 		},
 		{
-			name:        "invalid limit - too large",
-			queryString: "limit=1001",
+			name:        "limit above the default maximum response rows",
+			queryString: "limit=5000",
 			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
 				t.Helper()
 
-				require.Error(t, err)
-				require.Contains(t, err.Error(), "invalid limit")
+				require.NoError(t, err)
+				require.Equal(t, 5000, params.Limit)
 			},
 		},
 		{
@@ -322,6 +481,26 @@ func TestParseQueryParams(t *testing.T) {
 				require.Contains(t, err.Error(), "invalid from_block")
 			},
 		},
+		{
+			name:        "invalid from_timestamp",
+			queryString: "from_timestamp=-1",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "invalid from_timestamp")
+			},
+		},
+		{
+			name:        "invalid to_timestamp",
+			queryString: "to_timestamp=yesterday",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "invalid to_timestamp")
+			},
+		},
 		{
 			name:        "invalid to_block",
 			queryString: "to_block=xyz",
@@ -332,6 +511,29 @@ func TestParseQueryParams(t *testing.T) {
 				require.Contains(t, err.Error(), "invalid to_block")
 			},
 		},
+		{
+			name:        "cursor",
+			queryString: "cursor=" + indexer.EncodeCursor(indexer.EventCursor{BlockNumber: 100, LogIndex: 2, ID: 7}),
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, params.Cursor)
+
+				cursor, err := indexer.DecodeCursor(*params.Cursor)
+				require.NoError(t, err)
+				require.Equal(t, indexer.EventCursor{BlockNumber: 100, LogIndex: 2, ID: 7}, *cursor)
+			},
+		},
+		{
+			name:        "invalid cursor",
+			queryString: "cursor=not-a-cursor",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.ErrorIs(t, err, indexer.ErrInvalidCursor)
+			},
+		},
 		{
 			name:        "invalid sort_order",
 			queryString: "sort_order=invalid",
@@ -342,6 +544,44 @@ func TestParseQueryParams(t *testing.T) {
 				require.Contains(t, err.Error(), "invalid sort_order")
 			},
 		},
+		{
+			name:        "aggregation",
+			queryString: "aggregate_fn=SUM&aggregate_field=Value",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Equal(t, &indexer.Aggregation{Field: "value", Function: "sum"}, params.Aggregation)
+			},
+		},
+		{
+			name:        "count aggregation without field",
+			queryString: "aggregate_fn=count",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Equal(t, &indexer.Aggregation{Function: "count"}, params.Aggregation)
+			},
+		},
+		{
+			name:        "invalid aggregate_fn",
+			queryString: "aggregate_fn=median&aggregate_field=value",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.EqualError(t, err, "invalid aggregate_fn: must be one of sum, count, avg, min, max")
+			},
+		},
+		{
+			name:        "aggregate_field without aggregate_fn",
+			queryString: "aggregate_field=value",
+			validate: func(t *testing.T, params *indexer.QueryParams, err error) {
+				t.Helper()
+
+				require.EqualError(t, err, "invalid aggregate_field: aggregate_fn is required")
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -659,157 +899,659 @@ func TestHandler_GetEvents(t *testing.T) {
 			},
 		},
 		{
-			name:        "query with filters",
+			name:        "next cursor for offset page",
 			indexerName: "test-indexer",
-			queryString: "address=0x123&event_type=Transfer&from_block=100&to_block=200",
+			queryString: "limit=2",
 			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
 				registry.EXPECT().GetByName("test-indexer").Return(idx)
 
-				idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.MatchedBy(func(params indexer.QueryParams) bool {
-					return params.Address == "0x123" &&
-						params.EventType == "Transfer" &&
-						params.FromBlock != nil && *params.FromBlock == 100 &&
-						params.ToBlock != nil && *params.ToBlock == 200
-				})).Return([]map[string]any{}, 0, nil)
+				events := []map[string]any{
+					{"id": int64(9), "block_number": uint64(101), "log_index": uint(0)},
+					{"id": int64(8), "block_number": uint64(100), "log_index": uint(3)},
+				}
+
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.Anything).Return(events, 5, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validate: func(t *testing.T, response []byte, code int) {
 				t.Helper()
 
 				var eventResp EventResponse
-				err := json.Unmarshal(response, &eventResp)
+				require.NoError(t, json.Unmarshal(response, &eventResp))
+				require.True(t, eventResp.Pagination.HasMore)
+				require.NotNil(t, eventResp.NextCursor)
+
+				cursor, err := indexer.DecodeCursor(*eventResp.NextCursor)
 				require.NoError(t, err)
+				require.Equal(t, indexer.EventCursor{BlockNumber: 100, LogIndex: 3, ID: 8}, *cursor)
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-
-			registry := apimocks.NewIndexerRegistry(t)
-			mockIdx := newMockQueryableIndexer(t)
-			if tt.setupMocks != nil {
-				tt.setupMocks(registry, mockIdx)
-			}
-
-			log := logger.NewNopLogger()
-			handler := NewHandler(registry, rpcmocks.NewEthClient(t), log)
-
-			url := fmt.Sprintf("/api/v1/indexers/%s/events", tt.indexerName)
-			if tt.queryString != "" {
-				url += "?" + tt.queryString
-			}
-
-			req := httptest.NewRequest(http.MethodGet, url, nil)
-			req.SetPathValue("name", tt.indexerName)
-			w := httptest.NewRecorder()
-
-			handler.GetEvents(w, req)
-
-			require.Equal(t, tt.expectedStatus, w.Code)
-			tt.validate(t, w.Body.Bytes(), w.Code)
-		})
-	}
-}
+		{
+			name:        "no next cursor when sorted by another column",
+			indexerName: "test-indexer",
+			queryString: "limit=1&sort_by=tx_index",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
 
-func TestHandler_GetStats(t *testing.T) {
-	t.Parallel()
+				events := []map[string]any{{"block_number": uint64(100), "log_index": uint(0)}}
 
-	tests := []struct {
-		name           string
-		indexerName    string
-		setupMocks     func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer)
-		expectedStatus int
-		validate       func(t *testing.T, response []byte, code int)
-	}{
-		{
-			name:           "missing indexer name",
-			indexerName:    "",
-			expectedStatus: http.StatusBadRequest,
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.Anything).Return(events, 5, nil)
+			},
+			expectedStatus: http.StatusOK,
 			validate: func(t *testing.T, response []byte, code int) {
 				t.Helper()
 
-				var errResp ErrorResponse
-				err := json.Unmarshal(response, &errResp)
-				require.NoError(t, err)
-				require.Contains(t, errResp.Message, "indexer name is required")
+				var eventResp EventResponse
+				require.NoError(t, json.Unmarshal(response, &eventResp))
+				require.True(t, eventResp.Pagination.HasMore)
+				require.Nil(t, eventResp.NextCursor)
 			},
 		},
 		{
-			name:        "indexer not found",
-			indexerName: "nonexistent",
+			name:        "next cursor when sorted by block number first",
+			indexerName: "test-indexer",
+			queryString: "limit=1&sort_by=block_number,log_index",
 			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
-				registry.EXPECT().GetByName("nonexistent").Return(nil)
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+
+				events := []*testEvent{{ID: 2, BlockNumber: 100, LogIndex: 1}}
+
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.MatchedBy(func(params indexer.QueryParams) bool {
+					return slices.Equal(params.SortBy, []string{"block_number", "log_index"})
+				})).Return(events, 5, nil)
 			},
-			expectedStatus: http.StatusNotFound,
+			expectedStatus: http.StatusOK,
 			validate: func(t *testing.T, response []byte, code int) {
 				t.Helper()
 
-				var errResp ErrorResponse
-				err := json.Unmarshal(response, &errResp)
-				require.NoError(t, err)
-				require.Contains(t, errResp.Message, "not found")
+				var eventResp EventResponse
+				require.NoError(t, json.Unmarshal(response, &eventResp))
+				require.NotNil(t, eventResp.NextCursor)
 			},
 		},
 		{
-			name:        "indexer not queryable",
-			indexerName: "non-queryable",
+			name:        "cursor page",
+			indexerName: "test-indexer",
+			queryString: "limit=1&cursor=" + indexer.EncodeCursor(indexer.EventCursor{BlockNumber: 100, LogIndex: 3}),
 			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
-				mockIdx := indexermocks.NewIndexer(t)
-				registry.EXPECT().GetByName("non-queryable").Return(mockIdx)
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+
+				events := []*testEvent{{ID: 4, BlockNumber: 99, LogIndex: 1}}
+
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.MatchedBy(func(params indexer.QueryParams) bool {
+					return params.Cursor != nil && params.Limit == 1
+				})).Return(events, 5, nil)
 			},
-			expectedStatus: http.StatusBadRequest,
+			expectedStatus: http.StatusOK,
 			validate: func(t *testing.T, response []byte, code int) {
 				t.Helper()
 
-				var errResp ErrorResponse
-				err := json.Unmarshal(response, &errResp)
+				var eventResp EventResponse
+				require.NoError(t, json.Unmarshal(response, &eventResp))
+				require.True(t, eventResp.Pagination.HasMore)
+				require.NotNil(t, eventResp.NextCursor)
+
+				cursor, err := indexer.DecodeCursor(*eventResp.NextCursor)
 				require.NoError(t, err)
-				require.Contains(t, errResp.Message, "does not support querying")
+				require.Equal(t, indexer.EventCursor{BlockNumber: 99, LogIndex: 1, ID: 4}, *cursor)
 			},
 		},
 		{
-			name:        "get stats error",
+			name:        "last cursor page",
 			indexerName: "test-indexer",
+			queryString: "limit=10&cursor=" + indexer.EncodeCursor(indexer.EventCursor{BlockNumber: 100, LogIndex: 3}),
 			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
 				registry.EXPECT().GetByName("test-indexer").Return(idx)
-				idx.Queryable.EXPECT().GetStats(mock.Anything).Return(indexer.StatsResponse{}, errors.New("database error"))
+
+				events := []*testEvent{{ID: 4, BlockNumber: 99, LogIndex: 1}}
+
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.Anything).Return(events, 5, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validate: func(t *testing.T, response []byte, code int) {
+				t.Helper()
+
+				var eventResp EventResponse
+				require.NoError(t, json.Unmarshal(response, &eventResp))
+				require.False(t, eventResp.Pagination.HasMore)
+				require.Nil(t, eventResp.NextCursor)
+			},
+		},
+		{
+			name:        "invalid cursor",
+			indexerName: "test-indexer",
+			queryString: "cursor=%25%25",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+			},
+			expectedStatus: http.StatusBadRequest,
+			validate: func(t *testing.T, response []byte, code int) {
+				t.Helper()
+
+				var errResp ErrorResponse
+				require.NoError(t, json.Unmarshal(response, &errResp))
+				require.Contains(t, errResp.Message, "invalid cursor")
+			},
+		},
+		{
+			name:        "projected fields",
+			indexerName: "test-indexer",
+			queryString: "fields=block_number,from_address",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+
+				events := []map[string]any{{"block_number": int64(100), "from_address": "0xaaa"}}
+
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.MatchedBy(func(params indexer.QueryParams) bool {
+					return slices.Equal(params.Fields, []string{"block_number", "from_address"})
+				})).Return(events, 1, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validate: func(t *testing.T, response []byte, code int) {
+				t.Helper()
+
+				require.JSONEq(t, `{
+					"events": [{"block_number": 100, "from_address": "0xaaa"}],
+					"pagination": {"total": 1, "limit": 100, "offset": 0, "has_more": false}
+				}`, string(response))
+			},
+		},
+		{
+			name:        "unknown fields",
+			indexerName: "test-indexer",
+			queryString: "fields=block_number,amount",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.Anything).
+					Return(nil, 0, fmt.Errorf("%w: Transfer events have no amount", indexer.ErrUnknownField))
+			},
+			expectedStatus: http.StatusBadRequest,
+			validate: func(t *testing.T, response []byte, code int) {
+				t.Helper()
+
+				var errResp ErrorResponse
+				require.NoError(t, json.Unmarshal(response, &errResp))
+				require.Equal(t, "invalid query parameters: unknown field: Transfer events have no amount", errResp.Message)
+			},
+		},
+		{
+			name:        "offset too large",
+			indexerName: "test-indexer",
+			queryString: "offset=100001",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.Anything).
+					Return(nil, 0, fmt.Errorf("%w: offset 100001 exceeds the maximum of 100000", indexer.ErrOffsetTooLarge))
+			},
+			expectedStatus: http.StatusBadRequest,
+			validate: func(t *testing.T, response []byte, code int) {
+				t.Helper()
+
+				var errResp ErrorResponse
+				require.NoError(t, json.Unmarshal(response, &errResp))
+				require.Contains(t, errResp.Message, "offset too large: offset 100001 exceeds the maximum of 100000")
+				require.Contains(t, errResp.Message, "page through deep results with the cursor parameter")
+			},
+		},
+		{
+			name:        "timestamp filter not supported",
+			indexerName: "test-indexer",
+			queryString: "from_timestamp=1700000000",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.Anything).
+					Return(nil, 0, fmt.Errorf("%w: Transfer events have no timestamp", indexer.ErrTimestampFilterUnsupported))
+			},
+			expectedStatus: http.StatusBadRequest,
+			validate: func(t *testing.T, response []byte, code int) {
+				t.Helper()
+
+				var errResp ErrorResponse
+				require.NoError(t, json.Unmarshal(response, &errResp))
+				require.Contains(t, errResp.Message, "timestamp filter not supported")
+			},
+		},
+		{
+			name:        "query with filters",
+			indexerName: "test-indexer",
+			queryString: "address=0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48&event_type=Transfer&from_block=100&to_block=200",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.MatchedBy(func(params indexer.QueryParams) bool {
+					return params.Address == "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48" &&
+						params.EventType == "Transfer" &&
+						params.FromBlock != nil && *params.FromBlock == 100 &&
+						params.ToBlock != nil && *params.ToBlock == 200
+				})).Return([]map[string]any{}, 0, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validate: func(t *testing.T, response []byte, code int) {
+				t.Helper()
+
+				var eventResp EventResponse
+				err := json.Unmarshal(response, &eventResp)
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			mockIdx := newMockQueryableIndexer(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(registry, mockIdx)
+			}
+
+			log := logger.NewNopLogger()
+			handler := NewHandler(registry, rpcmocks.NewEthClient(t), log)
+
+			url := fmt.Sprintf("/api/v1/indexers/%s/events", tt.indexerName)
+			if tt.queryString != "" {
+				url += "?" + tt.queryString
+			}
+
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.SetPathValue("name", tt.indexerName)
+			w := httptest.NewRecorder()
+
+			handler.GetEvents(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			tt.validate(t, w.Body.Bytes(), w.Code)
+		})
+	}
+}
+
+func TestHandler_GetEventsMaxResponseRows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		maxResponseRows int
+		limit           int
+		expectedLimit   int
+		clamped         bool
+	}{
+		{name: "limit within the maximum", maxResponseRows: 200, limit: 50, expectedLimit: 50},
+		{name: "limit equal to the maximum", maxResponseRows: 200, limit: 200, expectedLimit: 200},
+		{name: "limit above the maximum", maxResponseRows: 200, limit: 500, expectedLimit: 200, clamped: true},
+		{name: "limit above 1000", maxResponseRows: 2000, limit: 5000, expectedLimit: 2000, clamped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			idx := newMockQueryableIndexer(t)
+			registry.EXPECT().GetByName("test-indexer").Return(idx)
+			idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.MatchedBy(func(params indexer.QueryParams) bool {
+				return params.Limit == tt.expectedLimit
+			})).Return([]map[string]any{}, 0, nil)
+
+			handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+			handler.maxResponseRows = tt.maxResponseRows
+
+			url := fmt.Sprintf("/api/v1/indexers/test-indexer/events?limit=%d", tt.limit)
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.SetPathValue("name", "test-indexer")
+			w := httptest.NewRecorder()
+
+			handler.GetEvents(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var eventResp EventResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &eventResp))
+			require.Equal(t, tt.expectedLimit, eventResp.Pagination.Limit)
+
+			if tt.clamped {
+				require.Equal(t, "true", w.Header().Get("X-Clamped-Limit"))
+			} else {
+				require.Empty(t, w.Header().Get("X-Clamped-Limit"))
+			}
+		})
+	}
+}
+
+func TestHandler_GetFirstAndLastEvent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		indexerName    string
+		queryString    string
+		last           bool
+		setupMocks     func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer)
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{
+			name:           "missing indexer name",
+			indexerName:    "",
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "indexer name is required",
+		},
+		{
+			name:        "indexer not found",
+			indexerName: "nonexistent",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("nonexistent").Return(nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedMsg:    "not found",
+		},
+		{
+			name:        "missing event type",
+			indexerName: "test-indexer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "event_type is required",
+		},
+		{
+			name:        "query error",
+			indexerName: "test-indexer",
+			queryString: "event_type=Transfer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().QueryFirstEvent(mock.Anything, "Transfer").
+					Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "failed to query event",
+		},
+		{
+			name:        "no events indexed",
+			indexerName: "test-indexer",
+			queryString: "event_type=Transfer",
+			last:        true,
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().QueryLastEvent(mock.Anything, "Transfer").Return(nil, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedMsg:    "no 'Transfer' events indexed",
+		},
+		{
+			name:        "first event",
+			indexerName: "test-indexer",
+			queryString: "event_type=Transfer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().QueryFirstEvent(mock.Anything, "Transfer").
+					Return(map[string]any{"block_number": uint64(100)}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "last event",
+			indexerName: "test-indexer",
+			queryString: "event_type=Transfer",
+			last:        true,
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().QueryLastEvent(mock.Anything, "Transfer").
+					Return(map[string]any{"block_number": uint64(200)}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			mockIdx := newMockQueryableIndexer(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(registry, mockIdx)
+			}
+
+			handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+
+			endpoint, handle := "first", handler.GetFirstEvent
+			if tt.last {
+				endpoint, handle = "last", handler.GetLastEvent
+			}
+
+			url := fmt.Sprintf("/api/v1/indexers/%s/events/%s", tt.indexerName, endpoint)
+			if tt.queryString != "" {
+				url += "?" + tt.queryString
+			}
+
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.SetPathValue("name", tt.indexerName)
+			w := httptest.NewRecorder()
+
+			handle(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus != http.StatusOK {
+				var errResp ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+				require.Contains(t, errResp.Message, tt.expectedMsg)
+				return
+			}
+
+			var event map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &event))
+			require.Contains(t, event, "block_number")
+		})
+	}
+}
+
+func TestHandler_GetStats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		indexerName    string
+		setupMocks     func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer)
+		expectedStatus int
+		validate       func(t *testing.T, response []byte, code int)
+	}{
+		{
+			name:           "missing indexer name",
+			indexerName:    "",
+			expectedStatus: http.StatusBadRequest,
+			validate: func(t *testing.T, response []byte, code int) {
+				t.Helper()
+
+				var errResp ErrorResponse
+				err := json.Unmarshal(response, &errResp)
+				require.NoError(t, err)
+				require.Contains(t, errResp.Message, "indexer name is required")
+			},
+		},
+		{
+			name:        "indexer not found",
+			indexerName: "nonexistent",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("nonexistent").Return(nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			validate: func(t *testing.T, response []byte, code int) {
+				t.Helper()
+
+				var errResp ErrorResponse
+				err := json.Unmarshal(response, &errResp)
+				require.NoError(t, err)
+				require.Contains(t, errResp.Message, "not found")
+			},
+		},
+		{
+			name:        "indexer not queryable",
+			indexerName: "non-queryable",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				mockIdx := indexermocks.NewIndexer(t)
+				registry.EXPECT().GetByName("non-queryable").Return(mockIdx)
+			},
+			expectedStatus: http.StatusBadRequest,
+			validate: func(t *testing.T, response []byte, code int) {
+				t.Helper()
+
+				var errResp ErrorResponse
+				err := json.Unmarshal(response, &errResp)
+				require.NoError(t, err)
+				require.Contains(t, errResp.Message, "does not support querying")
+			},
+		},
+		{
+			name:        "get stats error",
+			indexerName: "test-indexer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetStats(mock.Anything).Return(indexer.StatsResponse{}, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			validate: func(t *testing.T, response []byte, code int) {
+				t.Helper()
+
+				var errResp ErrorResponse
+				err := json.Unmarshal(response, &errResp)
+				require.NoError(t, err)
+				require.Contains(t, errResp.Message, "failed to get stats")
+			},
+		},
+		{
+			name:        "successful stats retrieval",
+			indexerName: "test-indexer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+
+				stats := indexer.StatsResponse{
+					TotalEvents:   int64(1234),
+					LatestBlock:   uint64(5000),
+					EarliestBlock: uint64(1000),
+				}
+
+				idx.Queryable.EXPECT().GetStats(mock.Anything).Return(stats, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validate: func(t *testing.T, response []byte, code int) {
+				t.Helper()
+
+				var stats indexer.StatsResponse
+				err := json.Unmarshal(response, &stats)
+				require.NoError(t, err)
+				require.Equal(t, int64(1234), stats.TotalEvents)
+				require.Equal(t, uint64(5000), stats.LatestBlock)
+				require.Equal(t, uint64(1000), stats.EarliestBlock)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			mockIdx := newMockQueryableIndexer(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(registry, mockIdx)
+			}
+
+			log := logger.NewNopLogger()
+			handler := NewHandler(registry, rpcmocks.NewEthClient(t), log)
+
+			url := fmt.Sprintf("/api/v1/indexers/%s/stats", tt.indexerName)
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.SetPathValue("name", tt.indexerName)
+			w := httptest.NewRecorder()
+
+			handler.GetStats(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			tt.validate(t, w.Body.Bytes(), w.Code)
+		})
+	}
+}
+
+func TestHandler_GetSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		indexerName    string
+		setupMocks     func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer)
+		expectedStatus int
+		validate       func(t *testing.T, response []byte)
+	}{
+		{
+			name:           "missing indexer name",
+			indexerName:    "",
+			expectedStatus: http.StatusBadRequest,
+			validate: func(t *testing.T, response []byte) {
+				t.Helper()
+
+				var errResp ErrorResponse
+				require.NoError(t, json.Unmarshal(response, &errResp))
+				require.Contains(t, errResp.Message, "indexer name is required")
 			},
-			expectedStatus: http.StatusInternalServerError,
-			validate: func(t *testing.T, response []byte, code int) {
+		},
+		{
+			name:        "indexer not found",
+			indexerName: "nonexistent",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("nonexistent").Return(nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			validate: func(t *testing.T, response []byte) {
 				t.Helper()
 
 				var errResp ErrorResponse
-				err := json.Unmarshal(response, &errResp)
-				require.NoError(t, err)
-				require.Contains(t, errResp.Message, "failed to get stats")
+				require.NoError(t, json.Unmarshal(response, &errResp))
+				require.Contains(t, errResp.Message, "not found")
 			},
 		},
 		{
-			name:        "successful stats retrieval",
+			name:        "indexer not queryable",
+			indexerName: "non-queryable",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("non-queryable").Return(indexermocks.NewIndexer(t))
+			},
+			expectedStatus: http.StatusBadRequest,
+			validate: func(t *testing.T, response []byte) {
+				t.Helper()
+
+				var errResp ErrorResponse
+				require.NoError(t, json.Unmarshal(response, &errResp))
+				require.Contains(t, errResp.Message, "does not support querying")
+			},
+		},
+		{
+			name:        "successful schema retrieval",
 			indexerName: "test-indexer",
 			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
 				registry.EXPECT().GetByName("test-indexer").Return(idx)
-
-				stats := indexer.StatsResponse{
-					TotalEvents:   int64(1234),
-					LatestBlock:   uint64(5000),
-					EarliestBlock: uint64(1000),
-				}
-
-				idx.Queryable.EXPECT().GetStats(mock.Anything).Return(stats, nil)
+				idx.Queryable.EXPECT().GetEventSchema().Return([]indexer.EventSchema{
+					{
+						Name: "Transfer",
+						Fields: []indexer.EventFieldSchema{
+							{Name: "from", Type: "address", Indexed: true},
+							{Name: "value", Type: "uint256"},
+						},
+					},
+				})
 			},
 			expectedStatus: http.StatusOK,
-			validate: func(t *testing.T, response []byte, code int) {
+			validate: func(t *testing.T, response []byte) {
 				t.Helper()
 
-				var stats indexer.StatsResponse
-				err := json.Unmarshal(response, &stats)
-				require.NoError(t, err)
-				require.Equal(t, int64(1234), stats.TotalEvents)
-				require.Equal(t, uint64(5000), stats.LatestBlock)
-				require.Equal(t, uint64(1000), stats.EarliestBlock)
+				require.JSONEq(t, `{"events": [{"name": "Transfer", "fields": [
+					{"name": "from", "type": "address", "indexed": true},
+					{"name": "value", "type": "uint256", "indexed": false}
+				]}]}`, string(response))
 			},
 		},
 	}
@@ -824,18 +1566,329 @@ func TestHandler_GetStats(t *testing.T) {
 				tt.setupMocks(registry, mockIdx)
 			}
 
-			log := logger.NewNopLogger()
-			handler := NewHandler(registry, rpcmocks.NewEthClient(t), log)
+			handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
 
-			url := fmt.Sprintf("/api/v1/indexers/%s/stats", tt.indexerName)
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/indexers/%s/schema", tt.indexerName), nil)
+			req.SetPathValue("name", tt.indexerName)
+			w := httptest.NewRecorder()
+
+			handler.GetSchema(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			tt.validate(t, w.Body.Bytes())
+		})
+	}
+}
+
+func TestHandler_GetRetentionPreview(t *testing.T) {
+	t.Parallel()
+
+	contract := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	tests := []struct {
+		name           string
+		indexerName    string
+		query          string
+		noPreviewer    bool
+		setupMocks     func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, previewer *apimocks.RetentionPreviewer)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "missing indexer name",
+			indexerName:    "",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": 400, "error": "Bad Request", "message": "indexer name is required"}`,
+		},
+		{
+			name:        "indexer not found",
+			indexerName: "nonexistent",
+			setupMocks: func(registry *apimocks.IndexerRegistry, _ *indexermocks.Indexer, _ *apimocks.RetentionPreviewer) {
+				registry.EXPECT().GetByName("nonexistent").Return(nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"code": 404, "error": "Not Found", "message": "indexer 'nonexistent' not found"}`,
+		},
+		{
+			name:        "previewer not configured",
+			indexerName: "test-indexer",
+			query:       "max_blocks=1000",
+			noPreviewer: true,
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, _ *apimocks.RetentionPreviewer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"code": 503, "error": "Service Unavailable", "message": "retention preview is not available"}`,
+		},
+		{
+			name:        "no policy limits",
+			indexerName: "test-indexer",
+			query:       "max_blocks=0",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, _ *apimocks.RetentionPreviewer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", "message": ` +
+				`"invalid query parameters: max_db_size_mb or max_blocks must be greater than 0"}`,
+		},
+		{
+			name:        "invalid max_db_size_mb",
+			indexerName: "test-indexer",
+			query:       "max_db_size_mb=-1",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, _ *apimocks.RetentionPreviewer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", "message": ` +
+				`"invalid query parameters: invalid max_db_size_mb: strconv.ParseUint: parsing \"-1\": invalid syntax"}`,
+		},
+		{
+			name:        "previewer error",
+			indexerName: "test-indexer",
+			query:       "max_blocks=1000",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, previewer *apimocks.RetentionPreviewer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{contract: {}})
+				previewer.EXPECT().PreviewRetention(mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, errors.New("database locked"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"code": 500, "error": "Internal Server Error", "message": "failed to preview retention"}`,
+		},
+		{
+			name:        "successful preview",
+			indexerName: "test-indexer",
+			query:       "max_db_size_mb=100&max_blocks=1000",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, previewer *apimocks.RetentionPreviewer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.EXPECT().EventsToIndex().Return(map[common.Address]map[common.Hash]struct{}{contract: {}})
+				previewer.EXPECT().PreviewRetention(mock.Anything,
+					config.RetentionPolicyConfig{MaxDBSizeMB: 100, MaxBlocks: 1000},
+					[]common.Address{contract},
+				).Return(&store.RetentionPreview{
+					PruneBeforeBlock:      123456,
+					EstimatedRowsDeleted:  45678,
+					EstimatedSpaceFreedMB: 23,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"prune_before_block": 123456, "estimated_rows_deleted": 45678, ` +
+				`"estimated_space_freed_mb": 23}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			idx := indexermocks.NewIndexer(t)
+			previewer := apimocks.NewRetentionPreviewer(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(registry, idx, previewer)
+			}
+
+			handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+			if !tt.noPreviewer {
+				handler.retention = previewer
+			}
+
+			url := fmt.Sprintf("/api/v1/indexers/%s/retention/preview?%s", tt.indexerName, tt.query)
 			req := httptest.NewRequest(http.MethodGet, url, nil)
 			req.SetPathValue("name", tt.indexerName)
 			w := httptest.NewRecorder()
 
-			handler.GetStats(w, req)
+			handler.GetRetentionPreview(w, req)
 
 			require.Equal(t, tt.expectedStatus, w.Code)
-			tt.validate(t, w.Body.Bytes(), w.Code)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_SimulateRetention(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		body           string
+		noPreviewer    bool
+		setupMocks     func(previewer *apimocks.RetentionPreviewer)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "simulation not configured",
+			body:           `{"max_blocks": 1000}`,
+			noPreviewer:    true,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: `{"code": 503, "error": "Service Unavailable", ` +
+				`"message": "retention simulation is not available"}`,
+		},
+		{
+			name:           "malformed body",
+			body:           `{"max_blocks": -1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", "message": "invalid retention policy: ` +
+				`json: cannot unmarshal number -1 into Go struct field RetentionPolicyConfig.max_blocks of type uint64"}`,
+		},
+		{
+			name:           "unknown field",
+			body:           `{"max_block": 1000}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", ` +
+				`"message": "invalid retention policy: json: unknown field \"max_block\""}`,
+		},
+		{
+			name:           "no policy limits",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", ` +
+				`"message": "max_db_size_mb or max_blocks must be greater than 0"}`,
+		},
+		{
+			name: "simulation error",
+			body: `{"max_blocks": 1000}`,
+			setupMocks: func(previewer *apimocks.RetentionPreviewer) {
+				previewer.EXPECT().SimulateRetention(mock.Anything, mock.Anything).
+					Return(nil, errors.New("database locked"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"code": 500, "error": "Internal Server Error", "message": "failed to simulate retention"}`,
+		},
+		{
+			name: "successful simulation",
+			body: `{"max_db_size_mb": 100, "max_blocks": 1000}`,
+			setupMocks: func(previewer *apimocks.RetentionPreviewer) {
+				previewer.EXPECT().SimulateRetention(mock.Anything,
+					config.RetentionPolicyConfig{MaxDBSizeMB: 100, MaxBlocks: 1000},
+				).Return(&store.RetentionSimulation{
+					PruneBeforeBlock:     123456,
+					EstimatedLogsDeleted: 45678,
+					EstimatedMBFreed:     23.5,
+					BlockRangeRetained:   [2]uint64{123000, 124456},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"prune_before_block": 123456, "estimated_logs_deleted": 45678, ` +
+				`"estimated_mb_freed": 23.5, "block_range_retained": [123000, 124456]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			previewer := apimocks.NewRetentionPreviewer(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(previewer)
+			}
+
+			handler := NewHandler(apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+			if !tt.noPreviewer {
+				handler.retention = previewer
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/simulate-retention", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.SimulateRetention(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetPendingEvents(t *testing.T) {
+	t.Parallel()
+
+	pendingEvent := downloader.PendingEvent{
+		Indexer:  "test-indexer",
+		Address:  common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		Topics:   []common.Hash{common.HexToHash("0x01")},
+		Data:     []byte{0x2a},
+		TxHash:   common.HexToHash("0x02"),
+		LogIndex: 1,
+		Pending:  true,
+		SeenAt:   time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name           string
+		indexerName    string
+		noSource       bool
+		setupMocks     func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, source *apimocks.PendingEventSource)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "missing indexer name",
+			indexerName:    "",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": 400, "error": "Bad Request", "message": "indexer name is required"}`,
+		},
+		{
+			name:        "indexer not found",
+			indexerName: "nonexistent",
+			setupMocks: func(registry *apimocks.IndexerRegistry, _ *indexermocks.Indexer, _ *apimocks.PendingEventSource) {
+				registry.EXPECT().GetByName("nonexistent").Return(nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"code": 404, "error": "Not Found", "message": "indexer 'nonexistent' not found"}`,
+		},
+		{
+			name:        "pending mode disabled",
+			indexerName: "test-indexer",
+			noSource:    true,
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, _ *apimocks.PendingEventSource) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"code": 503, "error": "Service Unavailable", "message": "pending mode is not enabled"}`,
+		},
+		{
+			name:        "pending events",
+			indexerName: "test-indexer",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *indexermocks.Indexer, source *apimocks.PendingEventSource) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				source.EXPECT().PendingEvents("test-indexer").Return([]downloader.PendingEvent{pendingEvent})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"count": 1, "events": [{"indexer": "test-indexer", ` +
+				`"address": "0x1111111111111111111111111111111111111111", ` +
+				`"topics": ["0x0000000000000000000000000000000000000000000000000000000000000001"], ` +
+				`"data": "0x2a", ` +
+				`"tx_hash": "0x0000000000000000000000000000000000000000000000000000000000000002", ` +
+				`"log_index": 1, "pending": true, "seen_at": "2024-01-15T00:00:00Z"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			idx := indexermocks.NewIndexer(t)
+			source := apimocks.NewPendingEventSource(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(registry, idx, source)
+			}
+
+			handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+			if !tt.noSource {
+				handler.pending = source
+			}
+
+			url := fmt.Sprintf("/api/v1/indexers/%s/events/pending", tt.indexerName)
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.SetPathValue("name", tt.indexerName)
+			w := httptest.NewRecorder()
+
+			handler.GetPendingEvents(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
 		})
 	}
 }
@@ -1001,3 +2054,93 @@ func TestHandler_Health(t *testing.T) {
 		})
 	}
 }
+
+// coverageRegistry is an indexer registry that also provides log coverage
+type coverageRegistry struct {
+	*apimocks.IndexerRegistry
+	*apimocks.CoverageProvider
+}
+
+func TestHandler_HealthCoverage(t *testing.T) {
+	t.Parallel()
+
+	token := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	nft := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	newIndexer := func(name string, startBlock uint64, addresses ...common.Address) *mockQueryableIndexer {
+		events := make(map[common.Address]map[common.Hash]struct{})
+		for _, address := range addresses {
+			events[address] = map[common.Hash]struct{}{}
+		}
+
+		idx := newMockQueryableIndexer(t)
+		idx.Indexer.EXPECT().GetName().Return(name)
+		idx.Indexer.EXPECT().GetType().Return("ERC20")
+		idx.Indexer.EXPECT().StartBlock().Return(startBlock)
+		idx.Indexer.EXPECT().EventsToIndex().Return(events).Maybe()
+		idx.Queryable.EXPECT().GetStats(mock.Anything).Return(indexer.StatsResponse{}, nil)
+
+		return idx
+	}
+
+	registry := coverageRegistry{
+		IndexerRegistry:  apimocks.NewIndexerRegistry(t),
+		CoverageProvider: apimocks.NewCoverageProvider(t),
+	}
+	registry.IndexerRegistry.EXPECT().ListAll().Return([]indexer.Indexer{
+		newIndexer("tokens", 0, token),
+		newIndexer("tokens-and-nfts", 500, token, nft),
+		newIndexer("not-started", 2000, nft),
+	})
+	registry.CoverageProvider.EXPECT().GetCoverageStats().Return([]indexer.CoverageStat{
+		{Address: token.Hex(), TotalCoveredBlocks: 1000, EarliestBlock: 0, LatestBlock: 999},
+		{Address: nft.Hex(), TotalCoveredBlocks: 250, EarliestBlock: 750, LatestBlock: 999},
+	}, nil)
+
+	handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+
+	w := httptest.NewRecorder()
+	handler.Health(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var healthResp HealthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &healthResp))
+	require.Len(t, healthResp.Indexers, 3)
+
+	require.NotNil(t, healthResp.Indexers[0].CoveragePercentage)
+	require.InDelta(t, 100.0, *healthResp.Indexers[0].CoveragePercentage, 0.001)
+
+	// Blocks of the token before the start block do not count, the nft is covered for half of the range
+	require.NotNil(t, healthResp.Indexers[1].CoveragePercentage)
+	require.InDelta(t, 75.0, *healthResp.Indexers[1].CoveragePercentage, 0.001)
+
+	// Nothing to cover yet
+	require.Nil(t, healthResp.Indexers[2].CoveragePercentage)
+}
+
+type chainRegistry struct {
+	*apimocks.IndexerRegistry
+	*apimocks.RPCClientProvider
+}
+
+func TestHandler_RPCClient(t *testing.T) {
+	t.Parallel()
+
+	defaultClient := rpcmocks.NewEthClient(t)
+	chainClient := rpcmocks.NewEthClient(t)
+
+	// A registry of a single chain uses the handler's client
+	handler := NewHandler(apimocks.NewIndexerRegistry(t), defaultClient, logger.NewNopLogger())
+	require.Same(t, defaultClient, handler.rpcClient("tokens"))
+
+	registry := chainRegistry{
+		IndexerRegistry:   apimocks.NewIndexerRegistry(t),
+		RPCClientProvider: apimocks.NewRPCClientProvider(t),
+	}
+	registry.RPCClientProvider.EXPECT().RPCClient("tokens").Return(chainClient)
+	registry.RPCClientProvider.EXPECT().RPCClient("unknown").Return(nil)
+
+	handler = NewHandler(registry, defaultClient, logger.NewNopLogger())
+	require.Same(t, chainClient, handler.rpcClient("tokens"))
+	require.Same(t, defaultClient, handler.rpcClient("unknown"))
+}
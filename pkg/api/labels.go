@@ -0,0 +1,264 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	internalcommon "github.com/goran-ethernal/ChainIndexor/internal/common"
+	"gopkg.in/yaml.v3"
+)
+
+// EnrichAddressLabelsParam is the query parameter opting into the address labels of JSON responses.
+const EnrichAddressLabelsParam = "enrichAddressLabels"
+
+// labelSuffix is appended to the key of an address to name the key of its label
+const labelSuffix = "_label"
+
+// LabelRegistry maps known addresses to display names, e.g. "Uniswap V2 Router".
+// The labels of the configuration are combined with the labels of an optional file, which can be
+// re-read while the API is running. Labels of the file take precedence.
+type LabelRegistry struct {
+	mu     sync.RWMutex
+	labels map[common.Address]string
+
+	static map[common.Address]string
+	file   string
+}
+
+// NewLabelRegistry creates a registry of the given labels, keyed by hex address, and of the labels of file,
+// which are only read by Reload. file may be empty.
+func NewLabelRegistry(labels map[string]string, file string) (*LabelRegistry, error) {
+	static, err := parseLabels(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabelRegistry{
+		labels: maps.Clone(static),
+		static: static,
+		file:   file,
+	}, nil
+}
+
+// Reload re-reads the labels file, replacing the labels it set before, and returns the number of labels.
+// The labels are kept as they are if the file cannot be read.
+func (r *LabelRegistry) Reload() (int, error) {
+	labels := maps.Clone(r.static)
+
+	if r.file != "" {
+		content, err := os.ReadFile(r.file)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read address labels file: %w", err)
+		}
+
+		// YAML is a superset of JSON, so the file can be either
+		var fileLabels map[string]string
+		if err := yaml.Unmarshal(content, &fileLabels); err != nil {
+			return 0, fmt.Errorf("failed to parse address labels file %s: %w", r.file, err)
+		}
+
+		parsed, err := parseLabels(fileLabels)
+		if err != nil {
+			return 0, fmt.Errorf("invalid address labels file %s: %w", r.file, err)
+		}
+		maps.Copy(labels, parsed)
+	}
+
+	r.mu.Lock()
+	r.labels = labels
+	r.mu.Unlock()
+
+	return len(labels), nil
+}
+
+// Label returns the label of an address, if it has one.
+func (r *LabelRegistry) Label(address common.Address) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	label, ok := r.labels[address]
+	return label, ok
+}
+
+// parseLabels keys labels by the addresses they are keyed by in hex.
+func parseLabels(labels map[string]string) (map[common.Address]string, error) {
+	parsed := make(map[common.Address]string, len(labels))
+	for hexAddress, label := range labels {
+		address, err := internalcommon.NormalizeAddress(hexAddress)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(label) == "" {
+			return nil, fmt.Errorf("label of %s is required", hexAddress)
+		}
+
+		parsed[address] = label
+	}
+
+	return parsed, nil
+}
+
+// ReloadLabels re-reads the address labels file of the configuration.
+// @Summary Reload the address labels
+// @Description Re-read the address labels file of the configuration, replacing the labels it set before. The labels are kept as they are if the file cannot be read. Requires an API key, even if the path is public
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} ReloadLabelsResponse "Labels reloaded"
+// @Failure 401 {object} ErrorResponse "Invalid or missing API key"
+// @Failure 403 {object} ErrorResponse "API authentication is disabled"
+// @Failure 500 {object} ErrorResponse "Labels file cannot be read"
+// @Failure 503 {object} ErrorResponse "Address labels not configured"
+// @Router /admin/reload-labels [post]
+func (h *Handler) ReloadLabels(w http.ResponseWriter, r *http.Request) {
+	if h.labels == nil {
+		respondError(w, http.StatusServiceUnavailable, "address labels are not configured")
+		return
+	}
+
+	count, err := h.labels.Reload()
+	if err != nil {
+		requestLogger(h.log, r).Errorf("Failed to reload address labels: %v", err)
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("failed to reload address labels: %v", err))
+		return
+	}
+
+	requestLogger(h.log, r).Infof("Reloaded %d address labels", count)
+	respondJSON(w, http.StatusOK, ReloadLabelsResponse{Labels: count})
+}
+
+// LabelMiddleware attaches the labels of known addresses to JSON responses of requests opting in with
+// enrichAddressLabels=true. Every object key named "address" or ending in "_address", whose value is a
+// hex address with a label, gets a sibling "<key>_label" key with the label.
+func LabelMiddleware(labels *LabelRegistry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if enrich, _ := strconv.ParseBool(r.URL.Query().Get(EnrichAddressLabelsParam)); !enrich {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			lw := &labelWriter{ResponseWriter: w}
+			next.ServeHTTP(lw, r)
+
+			if lw.buffering {
+				writeLabeled(w, lw.statusCode, lw.body.Bytes(), labels)
+			}
+		})
+	}
+}
+
+// labelWriter buffers JSON responses for the labels to be attached to them.
+// Other responses, like event streams and CSV exports, are written through.
+type labelWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	buffering   bool
+	body        bytes.Buffer
+}
+
+func (lw *labelWriter) WriteHeader(code int) {
+	if lw.wroteHeader {
+		return
+	}
+
+	lw.wroteHeader = true
+	lw.statusCode = code
+
+	mediaType, _, _ := mime.ParseMediaType(lw.Header().Get("Content-Type"))
+	lw.buffering = mediaType == "application/json"
+	if !lw.buffering {
+		lw.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (lw *labelWriter) Write(p []byte) (int, error) {
+	if !lw.wroteHeader {
+		lw.WriteHeader(http.StatusOK)
+	}
+
+	if lw.buffering {
+		return lw.body.Write(p)
+	}
+
+	return lw.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the flushing and deadline methods of the wrapped writer.
+func (lw *labelWriter) Unwrap() http.ResponseWriter {
+	return lw.ResponseWriter
+}
+
+// Hijack lets WebSocket handlers, like the event stream, take over the connection of an opted-in request.
+// Nothing is buffered for a hijacked connection.
+func (lw *labelWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := lw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+
+	return hijacker.Hijack()
+}
+
+// writeLabeled writes a buffered JSON response with the labels attached. Responses that cannot be
+// decoded are written as they are.
+func writeLabeled(w http.ResponseWriter, status int, body []byte, labels *LabelRegistry) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err == nil {
+		if labeled, err := json.Marshal(attachLabels(value, labels)); err == nil {
+			body = labeled
+		}
+	}
+
+	w.Header().Del("Content-Length")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// attachLabels adds the labels of the addresses of the objects of a decoded JSON value.
+func attachLabels(value any, labels *LabelRegistry) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			v[key] = attachLabels(field, labels)
+
+			hexAddress, ok := field.(string)
+			if !ok || !isAddressKey(key) || !common.IsHexAddress(hexAddress) {
+				continue
+			}
+			if _, exists := v[key+labelSuffix]; exists {
+				continue
+			}
+			if label, ok := labels.Label(common.HexToAddress(hexAddress)); ok {
+				v[key+labelSuffix] = label
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = attachLabels(item, labels)
+		}
+	}
+
+	return value
+}
+
+// isAddressKey reports whether an object key names an address, ignoring case.
+func isAddressKey(key string) bool {
+	key = strings.ToLower(key)
+	return key == "address" || strings.HasSuffix(key, "_address")
+}
@@ -0,0 +1,266 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	routerAddress = "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"
+	aliceAddress  = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+	bobAddress    = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+)
+
+func TestLabelMiddleware(t *testing.T) {
+	t.Parallel()
+
+	labels, err := NewLabelRegistry(map[string]string{
+		"0x7a250d5630b4cf539739df2c5dacb4c659f2488d": "Uniswap V2 Router",
+		aliceAddress: "Alice",
+	}, "")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		status      int
+		body        string
+		expected    string
+	}{
+		{
+			name:        "labels attached to address fields",
+			query:       "?enrichAddressLabels=true",
+			contentType: "application/json",
+			status:      http.StatusOK,
+			body: `{"events":[{"from_address":"` + aliceAddress + `","to_address":"` + routerAddress + `",` +
+				`"value":"100000000000000000000"},{"from_address":"` + bobAddress + `","block_number":12}],` +
+				`"address":"` + routerAddress + `"}`,
+			expected: `{"events":[{"from_address":"` + aliceAddress + `","from_address_label":"Alice",` +
+				`"to_address":"` + routerAddress + `","to_address_label":"Uniswap V2 Router",` +
+				`"value":"100000000000000000000"},{"from_address":"` + bobAddress + `","block_number":12}],` +
+				`"address":"` + routerAddress + `","address_label":"Uniswap V2 Router"}`,
+		},
+		{
+			name:        "fields not named like addresses and existing labels are left as they are",
+			query:       "?enrichAddressLabels=true",
+			contentType: "application/json; charset=utf-8",
+			status:      http.StatusOK,
+			body: `[{"owner":"` + aliceAddress + `","to_address":"` + routerAddress + `","to_address_label":"Router"},` +
+				`{"token_address":"0x1234"}]`,
+			expected: `[{"owner":"` + aliceAddress + `","to_address":"` + routerAddress + `","to_address_label":"Router"},` +
+				`{"token_address":"0x1234"}]`,
+		},
+		{
+			name:        "status code kept",
+			query:       "?enrichAddressLabels=1",
+			contentType: "application/json",
+			status:      http.StatusNotFound,
+			body:        `{"error":"Not Found","address":"` + routerAddress + `"}`,
+			expected:    `{"error":"Not Found","address":"` + routerAddress + `","address_label":"Uniswap V2 Router"}`,
+		},
+		{
+			name:        "not opted in",
+			contentType: "application/json",
+			status:      http.StatusOK,
+			body:        `{"address":"` + routerAddress + `"}`,
+			expected:    `{"address":"` + routerAddress + `"}`,
+		},
+		{
+			name:        "opted out",
+			query:       "?enrichAddressLabels=false",
+			contentType: "application/json",
+			status:      http.StatusOK,
+			body:        `{"address":"` + routerAddress + `"}`,
+			expected:    `{"address":"` + routerAddress + `"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := LabelMiddleware(labels)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/indexers/erc20/events"+tt.query, nil))
+
+			require.Equal(t, tt.status, w.Code)
+			require.JSONEq(t, tt.expected, w.Body.String())
+		})
+	}
+
+	t.Run("responses other than JSON are written through", func(t *testing.T) {
+		t.Parallel()
+
+		body := "from_address\n" + routerAddress + "\n"
+		handler := LabelMiddleware(labels)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/csv")
+			_, _ = w.Write([]byte(body))
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/indexers/erc20/export?enrichAddressLabels=true", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, body, w.Body.String())
+	})
+}
+
+func TestLabelMiddleware_WebSocket(t *testing.T) {
+	t.Parallel()
+
+	labels, err := NewLabelRegistry(map[string]string{routerAddress: "Uniswap V2 Router"}, "")
+	require.NoError(t, err)
+
+	var upgrader websocket.Upgrader
+	handler := LabelMiddleware(labels)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"address":"`+routerAddress+`"}`))
+	}))
+
+	server := httptest.NewServer(LoggingMiddleware(logger.NewNopLogger())(handler))
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/indexers/erc20/events/stream?enrichAddressLabels=true"
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+
+	// Messages of the stream are written as they are
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"address":"`+routerAddress+`"}`, string(data))
+}
+
+func TestLabelRegistry_Reload(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "labels.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`"`+aliceAddress+`": "Alice"`+"\n"), 0600))
+
+	labels, err := NewLabelRegistry(map[string]string{routerAddress: "Uniswap V2 Router", aliceAddress: "0xf39F"}, file)
+	require.NoError(t, err)
+
+	// The file is only read by Reload
+	label, ok := labels.Label(common.HexToAddress(aliceAddress))
+	require.True(t, ok)
+	require.Equal(t, "0xf39F", label)
+
+	count, err := labels.Reload()
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	label, _ = labels.Label(common.HexToAddress(aliceAddress))
+	require.Equal(t, "Alice", label)
+
+	// Labels removed from the file are removed, and JSON files are accepted too
+	require.NoError(t, os.WriteFile(file, []byte(`{"`+bobAddress+`": "Bob"}`), 0600))
+
+	count, err = labels.Reload()
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	label, _ = labels.Label(common.HexToAddress(aliceAddress))
+	require.Equal(t, "0xf39F", label)
+	label, _ = labels.Label(common.HexToAddress(bobAddress))
+	require.Equal(t, "Bob", label)
+
+	// An invalid file keeps the labels
+	require.NoError(t, os.WriteFile(file, []byte(`{"bob": "Bob"}`), 0600))
+
+	_, err = labels.Reload()
+	require.ErrorContains(t, err, "invalid address: bob")
+
+	label, ok = labels.Label(common.HexToAddress(bobAddress))
+	require.True(t, ok)
+	require.Equal(t, "Bob", label)
+
+	_, err = NewLabelRegistry(map[string]string{routerAddress: " "}, "")
+	require.ErrorContains(t, err, "label of "+routerAddress+" is required")
+}
+
+func TestServer_ReloadLabels(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "labels.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`"`+aliceAddress+`": "Alice"`), 0600))
+
+	newServer := func(t *testing.T, cfg *config.APIConfig) *Server {
+		t.Helper()
+
+		cfg.Enabled = true
+		cfg.Auth = &config.AuthConfig{Enabled: true, APIKeys: []string{"secret"}}
+		cfg.ApplyDefaults()
+
+		return NewServer(cfg, apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+	}
+
+	reload := func(server *Server, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/reload-labels", nil)
+		if key != "" {
+			req.Header.Set(APIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		server.server.Handler.ServeHTTP(w, req)
+
+		return w
+	}
+
+	server := newServer(t, &config.APIConfig{
+		AddressLabels:     map[string]string{routerAddress: "Uniswap V2 Router"},
+		AddressLabelsFile: file,
+	})
+
+	// The labels file is read on startup
+	label, ok := server.handler.labels.Label(common.HexToAddress(aliceAddress))
+	require.True(t, ok)
+	require.Equal(t, "Alice", label)
+
+	require.Equal(t, http.StatusUnauthorized, reload(server, "").Code)
+
+	require.NoError(t, os.WriteFile(file, []byte(`"`+bobAddress+`": "Bob"`), 0600))
+
+	w := reload(server, "secret")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"labels": 2}`, w.Body.String())
+
+	label, ok = server.handler.labels.Label(common.HexToAddress(bobAddress))
+	require.True(t, ok)
+	require.Equal(t, "Bob", label)
+
+	require.NoError(t, os.Remove(file))
+
+	w = reload(server, "secret")
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Contains(t, w.Body.String(), "failed to read address labels file")
+
+	// Without labels, there is nothing to reload
+	w = reload(newServer(t, &config.APIConfig{}), "secret")
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.Contains(t, w.Body.String(), "address labels are not configured")
+}
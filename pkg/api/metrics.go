@@ -0,0 +1,21 @@
+package api
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Rate limiting metrics
+	apiRateLimited = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "chainindexor_api_rate_limited_total",
+			Help: "Total number of API requests checked by the rate limiter, by client class",
+		},
+		[]string{"ip_class"},
+	)
+)
+
+func apiRateLimitedInc(ipClass string) {
+	apiRateLimited.WithLabelValues(ipClass).Inc()
+}
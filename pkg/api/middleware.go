@@ -1,12 +1,28 @@
 package api
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
+	internalcommon "github.com/goran-ethernal/ChainIndexor/internal/common"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 )
 
+// CorrelationIDHeader is the header carrying the ID that correlates a request with the lines logged for it.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// maxCorrelationIDLength bounds the correlation IDs accepted from clients
+const maxCorrelationIDLength = 128
+
+// correlationIDKey is the context key of the correlation ID of a request.
+type correlationIDKey struct{}
+
 // CORS middleware adds CORS headers to responses.
 func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -29,7 +45,8 @@ func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
 					w.Header().Set("Access-Control-Allow-Origin", "*")
 				}
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, "+CorrelationIDHeader)
+				w.Header().Set("Access-Control-Expose-Headers", CorrelationIDHeader)
 				w.Header().Set("Access-Control-Max-Age", "86400")
 			}
 
@@ -44,6 +61,60 @@ func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
 	}
 }
 
+// CorrelationIDMiddleware tags every request with a correlation ID, taken from the X-Correlation-ID
+// header or generated as a UUID v4 if the header is missing or invalid. The ID is returned in the
+// X-Correlation-ID response header and stored in the request context, where the API logs read it from.
+func CorrelationIDMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(CorrelationIDHeader)
+			if !validCorrelationID(id) {
+				id = uuid.NewString()
+			}
+
+			w.Header().Set(CorrelationIDHeader, id)
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), correlationIDKey{}, id)))
+		})
+	}
+}
+
+// CorrelationIDFromContext returns the correlation ID of the request of the context, or "" if there is none.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// validCorrelationID reports whether a correlation ID sent by a client can be used as is.
+// Only short IDs of letters, digits and '-', '_', '.', ':' are accepted, so clients cannot
+// inject arbitrary content into the logs.
+func validCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// requestLogger returns the logger for the lines logged while handling a request,
+// tagged with the correlation ID of the request if it has one.
+func requestLogger(log *logger.Logger, r *http.Request) *logger.Logger {
+	if id := CorrelationIDFromContext(r.Context()); id != "" {
+		return log.WithCorrelationID(id)
+	}
+
+	return log
+}
+
 // LoggingMiddleware logs HTTP requests.
 func LoggingMiddleware(log *logger.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -56,7 +127,7 @@ func LoggingMiddleware(log *logger.Logger) func(http.Handler) http.Handler {
 			next.ServeHTTP(wrapped, r)
 
 			duration := time.Since(start)
-			log.Infof("%s %s - %d - %v", r.Method, r.URL.Path, wrapped.statusCode, duration)
+			requestLogger(log, r).Infof("%s %s - %d - %v", r.Method, r.URL.Path, wrapped.statusCode, duration)
 		})
 	}
 }
@@ -72,13 +143,77 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets http.ResponseController reach the flushing and deadline methods of the wrapped writer.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Hijack lets WebSocket handlers take over the connection of a logged request.
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+
+	rw.statusCode = http.StatusSwitchingProtocols
+
+	return hijacker.Hijack()
+}
+
+// MaxBodySizeMiddleware limits the size of request bodies to maxBytes.
+// Requests that declare a larger Content-Length are rejected with 413 before reaching the handler,
+// and all other bodies are wrapped with http.MaxBytesReader, so reads past the limit fail.
+func MaxBodySizeMiddleware(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				respondJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{
+					Error: "Request body too large",
+					Code:  http.StatusRequestEntityTooLarge,
+				})
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AddressPathMiddleware normalizes the path values with the given names, which hold addresses,
+// to their EIP-55 checksummed form, so handlers see the same address whatever its case.
+// Requests with an invalid address are rejected with 400. Path values are only set once the
+// request is routed, so the middleware wraps the handler of a route rather than the mux.
+func AddressPathMiddleware(names ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, name := range names {
+				value := r.PathValue(name)
+				if value == "" {
+					continue
+				}
+
+				address, err := internalcommon.NormalizeAddress(value)
+				if err != nil {
+					respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s: %s", name, value))
+					return
+				}
+				r.SetPathValue(name, address.Hex())
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // RecoveryMiddleware recovers from panics and returns a 500 error.
 func RecoveryMiddleware(log *logger.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
-					log.Errorf("Panic recovered: %v", err)
+					requestLogger(log, r).Errorf("Panic recovered: %v", err)
 					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 				}
 			}()
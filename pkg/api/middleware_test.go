@@ -1,8 +1,11 @@
 package api
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
@@ -413,3 +416,106 @@ func TestCORSPreflightScenario(t *testing.T) {
 	require.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Content-Type")
 	require.Empty(t, w.Body.String()) // No body for OPTIONS
 }
+
+func TestMaxBodySizeMiddleware(t *testing.T) {
+	t.Parallel()
+
+	const maxBytes = 1024
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		require.NoError(t, err)
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write(body)
+		require.NoError(t, err)
+	})
+
+	wrappedHandler := MaxBodySizeMiddleware(maxBytes)(handler)
+
+	t.Run("body within limit", func(t *testing.T) {
+		t.Parallel()
+
+		body := strings.Repeat("a", maxBytes)
+		req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		wrappedHandler.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, body, w.Body.String())
+	})
+
+	t.Run("body over limit", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(strings.Repeat("a", maxBytes+1)))
+		w := httptest.NewRecorder()
+
+		wrappedHandler.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		require.JSONEq(t, `{"error": "Request body too large", "code": 413}`, w.Body.String())
+	})
+
+	t.Run("body of unknown length over limit", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(strings.Repeat("a", maxBytes+1)))
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+
+		wrappedHandler.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+}
+
+func TestAddressPathMiddleware(t *testing.T) {
+	t.Parallel()
+
+	const checksummed = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /contracts/{address}/events/{event}", AddressPathMiddleware("address")(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(r.PathValue("address") + " " + r.PathValue("event")))
+			require.NoError(t, err)
+		})))
+
+	for _, address := range []string{
+		checksummed,
+		strings.ToLower(checksummed),
+		"0x" + strings.ToUpper(checksummed[2:]),
+		"0xa0B86991C6218b36C1d19d4A2e9eB0Ce3606Eb48",
+	} {
+		t.Run(address, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/contracts/"+address+"/events/Transfer", nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, checksummed+" Transfer", w.Body.String())
+		})
+	}
+
+	t.Run("invalid address", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/contracts/0x1234/events/Transfer", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.JSONEq(t, `{"code": 400, "error": "Bad Request", "message": "invalid address: 0x1234"}`, w.Body.String())
+	})
+}
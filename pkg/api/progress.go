@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/progress"
+	"github.com/goran-ethernal/ChainIndexor/pkg/downloader"
+	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher"
+)
+
+const (
+	// backfillProgressInterval is how often the latest backfill progress is pushed to clients.
+	backfillProgressInterval = 5 * time.Second
+
+	// backfillKeepaliveInterval is how often a comment is sent, so proxies do not time out idle streams.
+	backfillKeepaliveInterval = 15 * time.Second
+)
+
+// backfillProgress streams the backfill progress of the downloader to Server-Sent Events clients.
+type backfillProgress struct {
+	source ProgressSource
+
+	interval          time.Duration
+	keepaliveInterval time.Duration
+
+	// closed is closed when the server shuts down, ending all streams
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newBackfillProgress(source ProgressSource) *backfillProgress {
+	return &backfillProgress{
+		source:            source,
+		interval:          backfillProgressInterval,
+		keepaliveInterval: backfillKeepaliveInterval,
+		closed:            make(chan struct{}),
+	}
+}
+
+// Close ends all open streams.
+func (p *backfillProgress) Close() {
+	p.closeOnce.Do(func() { close(p.closed) })
+}
+
+// indexerBackfill is the latest progress of an indexer and the estimator of its sync rate.
+type indexerBackfill struct {
+	latest    downloader.ProgressEvent
+	estimator *progress.Estimator
+}
+
+// StreamBackfillProgress streams the backfill progress of every indexer as Server-Sent Events.
+// @Summary Stream backfill progress
+// @Description Open a Server-Sent Events stream that receives a BackfillProgressEvent per indexer every 5 seconds. A ":keepalive" comment is sent every 15 seconds. Once the backfill is complete, a final "done" event is sent and the stream is closed
+// @Tags Status
+// @Produce text/event-stream
+// @Success 200 {object} BackfillProgressEvent "Stream of backfill progress events"
+// @Failure 503 {object} ErrorResponse "Backfill progress not enabled"
+// @Router /status/backfill [get]
+func (h *Handler) StreamBackfillProgress(w http.ResponseWriter, r *http.Request) {
+	if h.progress == nil {
+		respondError(w, http.StatusServiceUnavailable, "backfill progress is not enabled")
+		return
+	}
+
+	events, unsubscribe := h.progress.source.Subscribe()
+	defer unsubscribe()
+
+	log := requestLogger(h.log, r)
+	rc := http.NewResponseController(w)
+
+	// The stream stays open for the whole backfill, longer than the server's write timeout
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Debugf("Failed to clear the write deadline of the backfill progress stream: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		log.Debugf("Failed to open the backfill progress stream: %v", err)
+		return
+	}
+
+	indexers := make(map[string]*indexerBackfill)
+
+	progressTicker := time.NewTicker(h.progress.interval)
+	defer progressTicker.Stop()
+
+	keepaliveTicker := time.NewTicker(h.progress.keepaliveInterval)
+	defer keepaliveTicker.Stop()
+
+	for {
+		var (
+			frame string
+			done  bool
+		)
+
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-h.progress.closed:
+			return
+
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			backfill, exists := indexers[event.Indexer]
+			if !exists {
+				backfill = &indexerBackfill{estimator: progress.NewEstimator(progress.DefaultEstimatorWindow)}
+				indexers[event.Indexer] = backfill
+			}
+			backfill.latest = event
+			backfill.estimator.Observe(event.CurrentBlock, time.Now())
+
+			// The downloader only switches to live mode once every indexer is backfilled
+			if event.Type != fetcher.ModeLive.String() {
+				continue
+			}
+
+			frame = backfillProgressFrames(indexers) + "event: done\ndata: {}\n\n"
+			done = true
+
+		case <-progressTicker.C:
+			frame = backfillProgressFrames(indexers)
+
+		case <-keepaliveTicker.C:
+			frame = ":keepalive\n\n"
+		}
+
+		if frame == "" {
+			continue
+		}
+
+		if _, err := fmt.Fprint(w, frame); err != nil {
+			log.Debugf("Failed to write to the backfill progress stream: %v", err)
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			log.Debugf("Failed to flush the backfill progress stream: %v", err)
+			return
+		}
+
+		if done {
+			return
+		}
+	}
+}
+
+// backfillProgressFrames returns a Server-Sent Events frame with the latest progress of every indexer,
+// ordered by indexer name.
+func backfillProgressFrames(indexers map[string]*indexerBackfill) string {
+	var frames strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(indexers)) {
+		backfill := indexers[name]
+
+		event := BackfillProgressEvent{
+			IndexerName:     name,
+			CurrentBlock:    backfill.latest.CurrentBlock,
+			TargetBlock:     backfill.latest.TargetBlock,
+			PercentComplete: backfill.latest.Percentage,
+		}
+		if eta, ok := backfill.estimator.EstimatedSecondsRemaining(
+			backfill.latest.CurrentBlock, backfill.latest.TargetBlock); ok {
+			event.EstimatedSecondsRemaining = &eta
+		}
+
+		// Marshaling a struct of numbers and strings cannot fail
+		data, _ := json.Marshal(event)
+		fmt.Fprintf(&frames, "data: %s\n\n", data)
+	}
+
+	return frames.String()
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/internal/progress"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/downloader"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_StreamBackfillProgressNotEnabled(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+
+	rec := httptest.NewRecorder()
+	handler.StreamBackfillProgress(rec, httptest.NewRequest(http.MethodGet, "/api/v1/status/backfill", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.JSONEq(t, `{"code": 503, "error": "Service Unavailable", "message": "backfill progress is not enabled"}`,
+		rec.Body.String())
+}
+
+func TestHandler_StreamBackfillProgress(t *testing.T) {
+	t.Parallel()
+
+	bus := progress.NewBus(10)
+
+	handler := NewHandler(apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+	handler.progress = newBackfillProgress(bus)
+	handler.progress.interval = 10 * time.Millisecond
+	handler.progress.keepaliveInterval = 10 * time.Millisecond
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/status/backfill", handler.StreamBackfillProgress)
+
+	// The logging middleware wraps the response writer, which must still support flushing
+	server := httptest.NewServer(LoggingMiddleware(logger.NewNopLogger())(mux))
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/api/v1/status/backfill")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	require.Eventually(t, bus.HasSubscribers, 5*time.Second, 10*time.Millisecond)
+
+	bus.Publish(downloader.NewProgressEvent("backfill", "erc20", 0, 100, 1000))
+	bus.Publish(downloader.NewProgressEvent("backfill", "erc20", 0, 250, 1000))
+
+	lines := bufio.NewScanner(resp.Body)
+	readUntil := func(match func(line string) bool) string {
+		t.Helper()
+
+		for lines.Scan() {
+			if match(lines.Text()) {
+				return lines.Text()
+			}
+		}
+
+		require.FailNow(t, "stream ended", "error: %v", lines.Err())
+
+		return ""
+	}
+
+	readUntil(func(line string) bool { return line == ":keepalive" })
+
+	// Progress frames sent before the second event was received may report the first one
+	data := readUntil(func(line string) bool {
+		return strings.HasPrefix(line, "data: ") && strings.Contains(line, `"current_block":250`)
+	})
+
+	var event BackfillProgressEvent
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &event))
+	require.Equal(t, "erc20", event.IndexerName)
+	require.Equal(t, uint64(250), event.CurrentBlock)
+	require.Equal(t, uint64(1000), event.TargetBlock)
+	require.InDelta(t, 25, event.PercentComplete, 0.001)
+	require.NotNil(t, event.EstimatedSecondsRemaining)
+
+	// The downloader switching to live mode completes the backfill
+	bus.Publish(downloader.NewProgressEvent("live", "erc20", 0, 1000, 1000))
+
+	readUntil(func(line string) bool { return line == "event: done" })
+	require.Equal(t, "data: {}", readUntil(func(string) bool { return true }))
+
+	for lines.Scan() {
+		require.Empty(t, lines.Text())
+	}
+	require.NoError(t, lines.Err())
+	require.Eventually(t, func() bool { return !bus.HasSubscribers() }, 5*time.Second, 10*time.Millisecond)
+}
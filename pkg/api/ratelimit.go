@@ -0,0 +1,178 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// forwardedForHeader lists the client address and the proxies a request passed through.
+	forwardedForHeader = "X-Forwarded-For"
+
+	// rateLimiterSweepInterval is how often the buckets of idle clients are dropped.
+	rateLimiterSweepInterval = time.Minute
+)
+
+// Client classes reported by the rate limiting metric.
+const (
+	ipClassAllowlisted = "allowlisted"
+	ipClassNormal      = "normal"
+	ipClassBlocked     = "blocked"
+)
+
+// RateLimiter limits the request rate of every client IP address with its own token bucket.
+// Clients in the allowlisted networks are never limited.
+type RateLimiter struct {
+	limit             rate.Limit
+	burst             int
+	trustForwardedFor bool
+	allowlist         []netip.Prefix
+
+	mu        sync.Mutex
+	clients   map[netip.Addr]*rate.Limiter
+	lastSweep time.Time
+}
+
+// NewRateLimiter creates a rate limiter from the given configuration.
+func NewRateLimiter(cfg *config.RateLimitConfig) (*RateLimiter, error) {
+	allowlist := make([]netip.Prefix, 0, len(cfg.AllowlistCIDRs))
+	for _, cidr := range cfg.AllowlistCIDRs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist CIDR %q: %w", cidr, err)
+		}
+		allowlist = append(allowlist, prefix.Masked())
+	}
+
+	return &RateLimiter{
+		limit:             rate.Limit(cfg.RequestsPerSecond),
+		burst:             cfg.Burst,
+		trustForwardedFor: cfg.TrustForwardedFor,
+		allowlist:         allowlist,
+		clients:           make(map[netip.Addr]*rate.Limiter),
+		lastSweep:         time.Now(),
+	}, nil
+}
+
+// Allow reports whether a request of the client can be served now.
+// If not, it returns how long the client has to wait before retrying.
+func (l *RateLimiter) Allow(client netip.Addr) (bool, time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	limiter, exists := l.clients[client]
+	if !exists {
+		limiter = rate.NewLimiter(l.limit, l.burst)
+		l.clients[client] = limiter
+	}
+
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return false, 0
+	}
+
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// The request is rejected, so it must not use up the tokens of the client's next requests
+		reservation.CancelAt(now)
+		return false, delay
+	}
+
+	return true, 0
+}
+
+// sweep drops the buckets that refilled completely, which behave the same as new ones.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimiterSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for client, limiter := range l.clients {
+		if limiter.TokensAt(now) >= float64(l.burst) {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// allowlisted reports whether the client is in one of the allowlisted networks.
+func (l *RateLimiter) allowlisted(client netip.Addr) bool {
+	for _, prefix := range l.allowlist {
+		if prefix.Contains(client) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clientIP returns the address of the client that sent the request. With trustForwardedFor,
+// it is the first address of the X-Forwarded-For header, falling back to the connection's remote address.
+func (l *RateLimiter) clientIP(r *http.Request) (netip.Addr, bool) {
+	if l.trustForwardedFor {
+		first, _, _ := strings.Cut(r.Header.Get(forwardedForHeader), ",")
+		if addr, err := netip.ParseAddr(strings.TrimSpace(first)); err == nil {
+			return addr.Unmap(), true
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	return addr.Unmap(), true
+}
+
+// RateLimitMiddleware rejects requests of clients exceeding their request rate with 429 Too Many Requests
+// and a Retry-After header. Requests whose client address cannot be determined are not limited.
+func RateLimitMiddleware(limiter *RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, ok := limiter.clientIP(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if limiter.allowlisted(client) {
+				apiRateLimitedInc(ipClassAllowlisted)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, retryAfter := limiter.Allow(client)
+			if !allowed {
+				apiRateLimitedInc(ipClassBlocked)
+
+				// Retry-After is in whole seconds, so round up to not invite an early retry
+				if retryAfter > 0 {
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				}
+				respondError(w, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+
+			apiRateLimitedInc(ipClassNormal)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+// newRateLimitedHandler returns a handler that allows a single request per client every 100 seconds.
+func newRateLimitedHandler(t *testing.T, trustForwardedFor bool, allowlist ...string) http.Handler {
+	t.Helper()
+
+	limiter, err := NewRateLimiter(&config.RateLimitConfig{
+		Enabled:           true,
+		RequestsPerSecond: 0.01,
+		Burst:             1,
+		TrustForwardedFor: trustForwardedFor,
+		AllowlistCIDRs:    allowlist,
+	})
+	require.NoError(t, err)
+
+	return RateLimitMiddleware(limiter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+// serve sends a request from remoteAddr, with the given X-Forwarded-For header if not empty.
+func serve(handler http.Handler, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/indexers", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set(forwardedForHeader, forwardedFor)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestRateLimitMiddleware_PerIPIsolation(t *testing.T) {
+	t.Parallel()
+
+	handler := newRateLimitedHandler(t, false)
+
+	require.Equal(t, http.StatusOK, serve(handler, "10.0.0.1:5000", "").Code)
+
+	// The second request of the same client is rejected, whatever its source port
+	rec := serve(handler, "10.0.0.1:6000", "")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "100", rec.Header().Get("Retry-After"))
+
+	// Other clients have their own buckets
+	require.Equal(t, http.StatusOK, serve(handler, "10.0.0.2:5000", "").Code)
+	require.Equal(t, http.StatusOK, serve(handler, "[2001:db8::1]:5000", "").Code)
+	require.Equal(t, http.StatusTooManyRequests, serve(handler, "[2001:db8::1]:5000", "").Code)
+}
+
+func TestRateLimitMiddleware_AllowlistBypass(t *testing.T) {
+	t.Parallel()
+
+	handler := newRateLimitedHandler(t, false, "192.168.0.0/16", "2001:db8::/32")
+
+	for range 5 {
+		require.Equal(t, http.StatusOK, serve(handler, "192.168.1.10:5000", "").Code)
+		require.Equal(t, http.StatusOK, serve(handler, "[2001:db8::1]:5000", "").Code)
+	}
+
+	// Clients outside the allowlisted networks are still limited
+	require.Equal(t, http.StatusOK, serve(handler, "10.0.0.1:5000", "").Code)
+	require.Equal(t, http.StatusTooManyRequests, serve(handler, "10.0.0.1:5000", "").Code)
+}
+
+func TestRateLimitMiddleware_ForwardedFor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		trustForwardedFor bool
+		forwardedFor      [2]string
+		wantSecond        int
+	}{
+		{
+			name:              "trusted header separates clients behind the same proxy",
+			trustForwardedFor: true,
+			forwardedFor:      [2]string{"203.0.113.1, 10.0.0.1", "203.0.113.2"},
+			wantSecond:        http.StatusOK,
+		},
+		{
+			name:              "trusted header limits the same client",
+			trustForwardedFor: true,
+			forwardedFor:      [2]string{"203.0.113.1", "203.0.113.1, 10.0.0.9"},
+			wantSecond:        http.StatusTooManyRequests,
+		},
+		{
+			name:              "invalid header falls back to the remote address",
+			trustForwardedFor: true,
+			forwardedFor:      [2]string{"unknown", "unknown"},
+			wantSecond:        http.StatusTooManyRequests,
+		},
+		{
+			name:         "untrusted header is ignored",
+			forwardedFor: [2]string{"203.0.113.1", "203.0.113.2"},
+			wantSecond:   http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := newRateLimitedHandler(t, tt.trustForwardedFor)
+
+			require.Equal(t, http.StatusOK, serve(handler, "10.0.0.1:5000", tt.forwardedFor[0]).Code)
+			require.Equal(t, tt.wantSecond, serve(handler, "10.0.0.1:5000", tt.forwardedFor[1]).Code)
+		})
+	}
+}
+
+func TestRateLimitMiddleware_AllowlistedForwardedClient(t *testing.T) {
+	t.Parallel()
+
+	// A monitoring system behind the proxy is recognized by its forwarded address
+	handler := newRateLimitedHandler(t, true, "198.51.100.0/24")
+
+	for range 3 {
+		require.Equal(t, http.StatusOK, serve(handler, "10.0.0.1:5000", "198.51.100.7").Code)
+	}
+}
+
+func TestNewRateLimiter_InvalidCIDR(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewRateLimiter(&config.RateLimitConfig{
+		RequestsPerSecond: 1,
+		Burst:             1,
+		AllowlistCIDRs:    []string{"10.0.0.0"},
+	})
+	require.ErrorContains(t, err, "invalid allowlist CIDR")
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/pkg/rpc"
+)
+
+const (
+	readinessStatusReady    = "ready"
+	readinessStatusNotReady = "not_ready"
+
+	// Names of the readiness checks. Checks of an indexer, or of the RPC client of a chain
+	// other than the default one, are suffixed with ":<indexer name>"
+	readinessCheckRPC      = "rpc"
+	readinessCheckDatabase = "downloader_db"
+	readinessCheckIndexer  = "indexer"
+)
+
+// DatabasePinger checks that the downloader's database is reachable.
+type DatabasePinger interface {
+	// PingDatabase executes a trivial query on the downloader's database.
+	PingDatabase(ctx context.Context) error
+}
+
+// readinessCheck is a named check run by the readiness probe.
+type readinessCheck struct {
+	name    string
+	timeout time.Duration
+	run     func(ctx context.Context) error
+}
+
+// Ready reports whether the indexer is ready to serve requests.
+// @Summary Readiness probe
+// @Description Check the RPC connection, the downloader database and every registered indexer.
+// @Description Returns 503 listing the failed checks if any check fails
+// @Tags Health
+// @Produce json
+// @Success 200 {object} ReadinessResponse "All checks passed"
+// @Failure 503 {object} ReadinessResponse "At least one check failed"
+// @Router /healthz/ready [get]
+func (h *Handler) Ready(w http.ResponseWriter, r *http.Request) {
+	checks := h.readinessChecks()
+	results := make([]ReadinessCheckResult, len(checks))
+
+	// Checks run concurrently, so the probe takes as long as the slowest check
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Go(func() {
+			results[i] = runReadinessCheck(r.Context(), check)
+		})
+	}
+	wg.Wait()
+
+	response := ReadinessResponse{
+		Status:    readinessStatusReady,
+		Timestamp: time.Now(),
+		Checks:    results,
+		Failed:    []string{},
+	}
+	for _, result := range results {
+		if !result.Healthy {
+			response.Failed = append(response.Failed, result.Name)
+		}
+	}
+
+	status := http.StatusOK
+	if len(response.Failed) > 0 {
+		response.Status = readinessStatusNotReady
+		status = http.StatusServiceUnavailable
+		requestLogger(h.log, r).Warnf("Readiness checks failed: %v", response.Failed)
+	}
+
+	respondJSON(w, status, response)
+}
+
+// Live reports that the API server is running. It always succeeds.
+// @Summary Liveness probe
+// @Description Report that the API server is running, without checking its dependencies
+// @Tags Health
+// @Produce json
+// @Success 200 {object} LivenessResponse "The server is running"
+// @Router /healthz/live [get]
+func (h *Handler) Live(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, LivenessResponse{Status: "ok"})
+}
+
+// readinessChecks returns the checks of the RPC clients, the downloader database and the indexers.
+func (h *Handler) readinessChecks() []readinessCheck {
+	indexers := h.registry.ListAll()
+
+	var checks []readinessCheck
+
+	// Indexers of other chains use other RPC clients, which are checked once each
+	var clients []rpc.EthClient
+	addRPCCheck := func(name string, client rpc.EthClient) {
+		for _, checked := range clients {
+			if checked == client {
+				return
+			}
+		}
+		clients = append(clients, client)
+
+		checks = append(checks, readinessCheck{
+			name:    name,
+			timeout: h.readiness.RPCTimeout.Duration,
+			run: func(ctx context.Context) error {
+				_, err := client.GetLatestBlockHeader(ctx)
+				return err
+			},
+		})
+	}
+
+	if h.rpc != nil {
+		addRPCCheck(readinessCheckRPC, h.rpc)
+	}
+	for _, idx := range indexers {
+		if client := h.rpcClient(idx.GetName()); client != nil {
+			addRPCCheck(readinessCheckRPC+":"+idx.GetName(), client)
+		}
+	}
+
+	if h.database != nil {
+		checks = append(checks, readinessCheck{
+			name:    readinessCheckDatabase,
+			timeout: h.readiness.DatabaseTimeout.Duration,
+			run:     h.database.PingDatabase,
+		})
+	}
+
+	for _, idx := range indexers {
+		checks = append(checks, readinessCheck{
+			name:    readinessCheckIndexer + ":" + idx.GetName(),
+			timeout: h.readiness.IndexerTimeout.Duration,
+			run:     idx.Ping,
+		})
+	}
+
+	return checks
+}
+
+// runReadinessCheck runs the check, failing it if it does not finish within its timeout.
+func runReadinessCheck(ctx context.Context, check readinessCheck) ReadinessCheckResult {
+	if check.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, check.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := check.run(ctx)
+	if err == nil {
+		// Checks ignoring the context are still failed when they finish too late
+		err = ctx.Err()
+	}
+
+	result := ReadinessCheckResult{
+		Name:       check.name,
+		Healthy:    err == nil,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return result
+}
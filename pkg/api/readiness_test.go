@@ -0,0 +1,193 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	indexermocks "github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// databasePingerFunc adapts a function to the DatabasePinger interface
+type databasePingerFunc func(ctx context.Context) error
+
+func (f databasePingerFunc) PingDatabase(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestHandler_Ready(t *testing.T) {
+	t.Parallel()
+
+	errUnavailable := errors.New("unavailable")
+
+	tests := []struct {
+		name           string
+		rpcErr         error
+		databaseErr    error
+		indexerErr     error
+		expectedStatus int
+		expectedFailed []string
+	}{
+		{
+			name:           "all checks pass",
+			expectedStatus: http.StatusOK,
+			expectedFailed: []string{},
+		},
+		{
+			name:           "rpc fails",
+			rpcErr:         errUnavailable,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedFailed: []string{"rpc"},
+		},
+		{
+			name:           "database fails",
+			databaseErr:    errUnavailable,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedFailed: []string{"downloader_db"},
+		},
+		{
+			name:           "rpc and indexer fail",
+			rpcErr:         errUnavailable,
+			indexerErr:     errUnavailable,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedFailed: []string{"rpc", "indexer:tokens"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := rpcmocks.NewEthClient(t)
+			client.On("GetLatestBlockHeader", mock.Anything).Return(&types.Header{}, tt.rpcErr)
+
+			idx := indexermocks.NewIndexer(t)
+			idx.EXPECT().GetName().Return("tokens")
+			idx.EXPECT().Ping(mock.Anything).Return(tt.indexerErr)
+
+			registry := apimocks.NewIndexerRegistry(t)
+			registry.EXPECT().ListAll().Return([]indexer.Indexer{idx})
+
+			handler := NewHandler(registry, client, logger.NewNopLogger())
+			handler.database = databasePingerFunc(func(ctx context.Context) error { return tt.databaseErr })
+
+			rec := httptest.NewRecorder()
+			handler.Ready(rec, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+
+			var response ReadinessResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			require.Equal(t, tt.expectedFailed, response.Failed)
+			require.Len(t, response.Checks, 3)
+
+			if tt.expectedStatus == http.StatusOK {
+				require.Equal(t, "ready", response.Status)
+			} else {
+				require.Equal(t, "not_ready", response.Status)
+			}
+
+			for _, check := range response.Checks {
+				if check.Healthy {
+					require.Empty(t, check.Error)
+				} else {
+					require.Equal(t, errUnavailable.Error(), check.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ReadyTimeout(t *testing.T) {
+	t.Parallel()
+
+	idx := indexermocks.NewIndexer(t)
+	idx.EXPECT().GetName().Return("tokens")
+	idx.EXPECT().Ping(mock.Anything).Return(nil)
+
+	registry := apimocks.NewIndexerRegistry(t)
+	registry.EXPECT().ListAll().Return([]indexer.Indexer{idx})
+
+	handler := NewHandler(registry, nil, logger.NewNopLogger())
+	handler.readiness = config.ReadinessConfig{DatabaseTimeout: common.NewDuration(10 * time.Millisecond)}
+
+	// The database check blocks until its timeout expires
+	handler.database = databasePingerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	rec := httptest.NewRecorder()
+	handler.Ready(rec, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var response ReadinessResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Equal(t, []string{"downloader_db"}, response.Failed)
+	require.Len(t, response.Checks, 2)
+	require.Equal(t, context.DeadlineExceeded.Error(), response.Checks[0].Error)
+}
+
+func TestHandler_ReadyChecksRPCClientOfEveryChain(t *testing.T) {
+	t.Parallel()
+
+	defaultClient := rpcmocks.NewEthClient(t)
+	defaultClient.On("GetLatestBlockHeader", mock.Anything).Return(&types.Header{}, nil)
+	chainClient := rpcmocks.NewEthClient(t)
+	chainClient.On("GetLatestBlockHeader", mock.Anything).Return(nil, errors.New("connection refused"))
+
+	tokens := indexermocks.NewIndexer(t)
+	tokens.EXPECT().GetName().Return("tokens")
+	tokens.EXPECT().Ping(mock.Anything).Return(nil)
+	nfts := indexermocks.NewIndexer(t)
+	nfts.EXPECT().GetName().Return("nfts")
+	nfts.EXPECT().Ping(mock.Anything).Return(nil)
+
+	registry := &chainRegistry{
+		IndexerRegistry:   apimocks.NewIndexerRegistry(t),
+		RPCClientProvider: apimocks.NewRPCClientProvider(t),
+	}
+	registry.IndexerRegistry.EXPECT().ListAll().Return([]indexer.Indexer{tokens, nfts})
+	registry.RPCClientProvider.EXPECT().RPCClient("tokens").Return(defaultClient)
+	registry.RPCClientProvider.EXPECT().RPCClient("nfts").Return(chainClient)
+
+	handler := NewHandler(registry, defaultClient, logger.NewNopLogger())
+
+	rec := httptest.NewRecorder()
+	handler.Ready(rec, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var response ReadinessResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Equal(t, []string{"rpc:nfts"}, response.Failed)
+
+	// The default client is checked once, although the tokens indexer uses it too
+	require.Len(t, response.Checks, 4)
+}
+
+func TestHandler_Live(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+
+	rec := httptest.NewRecorder()
+	handler.Live(rec, httptest.NewRequest(http.MethodGet, "/healthz/live", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"status": "ok"}`, rec.Body.String())
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// GetReorgHistory returns the chain reorganizations the downloader detected.
+// @Summary Get the reorg history
+// @Description List the chain reorganizations the downloader detected, oldest first, with the first reorged block, the depth and what the reorg was detected by
+// @Tags Reorgs
+// @Produce json
+// @Param from_block query integer false "Only list reorgs whose first reorged block is at or after this block"
+// @Param to_block query integer false "Only list reorgs whose first reorged block is at or before this block"
+// @Success 200 {object} ReorgHistoryResponse "Detected reorgs"
+// @Failure 400 {object} ErrorResponse "Invalid block range"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Reorg history not available"
+// @Router /reorgs [get]
+func (h *Handler) GetReorgHistory(w http.ResponseWriter, r *http.Request) {
+	if h.reorgs == nil {
+		respondError(w, http.StatusServiceUnavailable, "reorg history is not available")
+		return
+	}
+
+	fromBlock := uint64(0)
+	if value := r.URL.Query().Get("from_block"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid from_block")
+			return
+		}
+		fromBlock = parsed
+	}
+
+	toBlock := uint64(math.MaxUint64)
+	if value := r.URL.Query().Get("to_block"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid to_block")
+			return
+		}
+		toBlock = parsed
+	}
+
+	if fromBlock > toBlock {
+		respondError(w, http.StatusBadRequest, "from_block cannot be greater than to_block")
+		return
+	}
+
+	events, err := h.reorgs.GetReorgHistory(r.Context(), fromBlock, toBlock)
+	if err != nil {
+		requestLogger(h.log, r).Errorf("Failed to get reorg history from block %d to %d: %v", fromBlock, toBlock, err)
+		respondError(w, http.StatusInternalServerError, "failed to get reorg history")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, ReorgHistoryResponse{Reorgs: events, Count: len(events)})
+}
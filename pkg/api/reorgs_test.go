@@ -0,0 +1,141 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_GetReorgHistory(t *testing.T) {
+	t.Parallel()
+
+	events := []reorg.ReorgEvent{
+		{
+			ID:              1,
+			DetectedAt:      time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+			FirstReorgBlock: 19000100,
+			Depth:           2,
+			Details:         "cached_hash=0x01 current_hash=0x02",
+		},
+		{
+			ID:              2,
+			DetectedAt:      time.Date(2024, 2, 1, 8, 30, 0, 0, time.UTC),
+			FirstReorgBlock: 19000250,
+			Depth:           1,
+			Details:         "chain discontinuity between blocks 19000249 and 19000250",
+		},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		noSource       bool
+		setupMocks     func(source *apimocks.ReorgHistorySource)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "reorg history not configured",
+			noSource:       true,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"code": 503, "error": "Service Unavailable", "message": "reorg history is not available"}`,
+		},
+		{
+			name:           "invalid from block",
+			query:          "from_block=abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": 400, "error": "Bad Request", "message": "invalid from_block"}`,
+		},
+		{
+			name:           "invalid to block",
+			query:          "to_block=-1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": 400, "error": "Bad Request", "message": "invalid to_block"}`,
+		},
+		{
+			name:           "inverted range",
+			query:          "from_block=200&to_block=100",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": 400, "error": "Bad Request", "message": "from_block cannot be greater than to_block"}`,
+		},
+		{
+			name: "history error",
+			setupMocks: func(source *apimocks.ReorgHistorySource) {
+				source.EXPECT().GetReorgHistory(mock.Anything, uint64(0), uint64(math.MaxUint64)).
+					Return(nil, errors.New("database locked"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"code": 500, "error": "Internal Server Error", "message": "failed to get reorg history"}`,
+		},
+		{
+			name: "whole history",
+			setupMocks: func(source *apimocks.ReorgHistorySource) {
+				source.EXPECT().GetReorgHistory(mock.Anything, uint64(0), uint64(math.MaxUint64)).Return(events, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"reorgs": [
+					{
+						"id": 1,
+						"detected_at": "2024-01-31T12:00:00Z",
+						"first_reorg_block": 19000100,
+						"depth": 2,
+						"details": "cached_hash=0x01 current_hash=0x02"
+					},
+					{
+						"id": 2,
+						"detected_at": "2024-02-01T08:30:00Z",
+						"first_reorg_block": 19000250,
+						"depth": 1,
+						"details": "chain discontinuity between blocks 19000249 and 19000250"
+					}
+				],
+				"count": 2
+			}`,
+		},
+		{
+			name:  "block range",
+			query: "from_block=19000000&to_block=19000200",
+			setupMocks: func(source *apimocks.ReorgHistorySource) {
+				source.EXPECT().GetReorgHistory(mock.Anything, uint64(19000000), uint64(19000200)).
+					Return([]reorg.ReorgEvent{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"reorgs": [], "count": 0}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := apimocks.NewReorgHistorySource(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(source)
+			}
+
+			handler := NewHandler(apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+			if !tt.noSource {
+				handler.reorgs = source
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/reorgs?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.GetReorgHistory(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
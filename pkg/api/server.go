@@ -6,9 +6,11 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	"github.com/goran-ethernal/ChainIndexor/pkg/api/docs"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/fetcher/store"
 	"github.com/goran-ethernal/ChainIndexor/pkg/rpc"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
@@ -16,6 +18,7 @@ import (
 // Ensure docs are initialized
 var _ = docs.SwaggerInfo
 
+// shutdownCtxTimeout is how long shutdown waits for open requests when the config does not set a timeout
 const shutdownCtxTimeout = 10 * time.Second
 
 // Server represents the API HTTP server.
@@ -26,27 +29,94 @@ type Server struct {
 	server   *http.Server
 	log      *logger.Logger
 	rpc      rpc.EthClient
+
+	// keys and keySource are set when API key authentication is enabled
+	keys      *KeyStore
+	keySource KeySource
 }
 
 // NewServer creates a new API server.
 func NewServer(cfg *config.APIConfig, registry IndexerRegistry, rpcClient rpc.EthClient, log *logger.Logger) *Server {
 	handler := NewHandler(registry, rpcClient, log)
+	handler.stream = NewEventStream(cfg.MaxBufferedMessages, log)
+	handler.readiness = cfg.Readiness
+	handler.maxExportRows = cfg.MaxExportRows
+	handler.maxResponseRows = cfg.MaxResponseRows
+
+	var (
+		keys      *KeyStore
+		keySource KeySource
+	)
+	if cfg.Auth != nil && cfg.Auth.Enabled {
+		keys = NewKeyStore(cfg.Auth.APIKeys, cfg.Auth.KeyGracePeriod.Duration)
+		if err := keys.SetKeyHashes(cfg.Auth.APIKeyHashes); err != nil {
+			log.Errorf("failed to load API key hashes, only API keys in plaintext will be accepted: %v", err)
+		}
+
+		if cfg.Auth.DynamicKeySource != nil {
+			var err error
+			if keySource, err = NewKeySource(cfg.Auth.DynamicKeySource); err != nil {
+				log.Errorf("failed to create dynamic key source, only static API keys will be accepted: %v", err)
+			}
+		}
+	}
+
+	// Labels of the configuration are valid, as it is validated, so only the labels file can fail to load
+	var labels *LabelRegistry
+	if len(cfg.AddressLabels) > 0 || cfg.AddressLabelsFile != "" {
+		var err error
+		if labels, err = NewLabelRegistry(cfg.AddressLabels, cfg.AddressLabelsFile); err != nil {
+			log.Errorf("failed to load address labels, responses will not be labeled: %v", err)
+		} else if _, err := labels.Reload(); err != nil {
+			log.Errorf("failed to load address labels file, only the configured labels will be attached: %v", err)
+		}
+		handler.labels = labels
+	}
 
 	mux := http.NewServeMux()
 
 	// Health and info endpoints
 	mux.HandleFunc("GET /health", handler.Health)
+	mux.HandleFunc("GET /healthz/ready", handler.Ready)
+	mux.HandleFunc("GET /healthz/live", handler.Live)
 	mux.HandleFunc("GET /api/v1/indexers", handler.ListIndexers)
+	mux.HandleFunc("GET /api/v1/status/backfill", handler.StreamBackfillProgress)
 
 	// Event query endpoints - use indexer name for unique identification
 	mux.HandleFunc("GET /api/v1/indexers/{name}/events", handler.GetEvents)
+	mux.HandleFunc("GET /api/v1/indexers/{name}/events/first", handler.GetFirstEvent)
+	mux.HandleFunc("GET /api/v1/indexers/{name}/events/last", handler.GetLastEvent)
+	mux.HandleFunc("GET /api/v1/indexers/{name}/events/pending", handler.GetPendingEvents)
+	mux.HandleFunc("GET /api/v1/indexers/{name}/events/stream", handler.StreamEvents)
+	mux.HandleFunc("GET /api/v1/indexers/{name}/events/distinct/{field}", handler.GetDistinctValues)
+	mux.HandleFunc("GET /api/v1/indexers/{name}/export", handler.ExportEvents)
 	mux.HandleFunc("GET /api/v1/indexers/{name}/stats", handler.GetStats)
+	mux.HandleFunc("GET /api/v1/indexers/{name}/schema", handler.GetSchema)
+	mux.HandleFunc("GET /api/v1/indexers/{name}/coverage", handler.GetIndexerCoverage)
+
+	// Endpoints of the indexers' own, matched after every built-in endpoint of an indexer
+	mux.HandleFunc("GET /api/v1/indexers/{name}/{path...}", handler.GetIndexerEndpoint)
+
+	// Chain data quality endpoints
+	mux.HandleFunc("GET /api/v1/reorgs", handler.GetReorgHistory)
 
 	// Analytics endpoints
 	mux.HandleFunc("GET /api/v1/indexers/{name}/events/timeseries", handler.GetEventsTimeseries)
 	mux.HandleFunc("GET /api/v1/indexers/{name}/metrics", handler.GetMetrics)
+	mux.HandleFunc("POST /api/v1/query/aggregate", handler.AggregateEvents)
+	mux.HandleFunc("GET /api/v1/tx/{txHash}/events", handler.GetTransactionEvents)
 
-	// Swagger documentation endpoints
+	// Retention endpoints
+	mux.HandleFunc("GET /api/v1/indexers/{name}/retention/preview", handler.GetRetentionPreview)
+	mux.HandleFunc("POST /api/v1/admin/simulate-retention", handler.SimulateRetention)
+
+	// Backup endpoints read and write files on the server, so they always require an API key
+	mux.Handle("POST /api/v1/admin/snapshot", RequireAPIKey(keys)(http.HandlerFunc(handler.CreateSnapshot)))
+	mux.Handle("POST /api/v1/admin/restore", RequireAPIKey(keys)(http.HandlerFunc(handler.RestoreSnapshot)))
+	mux.Handle("POST /api/v1/admin/reload-labels", RequireAPIKey(keys)(http.HandlerFunc(handler.ReloadLabels)))
+
+	// API documentation endpoints
+	mux.HandleFunc("GET /api/v1/openapi.yaml", handler.GetOpenAPISpec)
 	mux.Handle("GET /swagger/", httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:8080/swagger/doc.json"),
 		httpSwagger.DeepLinking(true),
@@ -54,13 +124,38 @@ func NewServer(cfg *config.APIConfig, registry IndexerRegistry, rpcClient rpc.Et
 
 	// Apply middleware
 	var h http.Handler = mux
+	if labels != nil {
+		h = LabelMiddleware(labels)(h)
+	}
 	h = RecoveryMiddleware(log)(h)
+
+	if keys != nil {
+		h = AuthMiddleware(keys, cfg.Auth.PublicPaths)(h)
+	}
+
+	if cfg.MaxRequestBodySize > 0 {
+		h = MaxBodySizeMiddleware(cfg.MaxRequestBodySize)(h)
+	}
+
+	// Rate limiting runs before authentication, so clients guessing API keys are limited too
+	if cfg.RateLimit != nil && cfg.RateLimit.Enabled {
+		limiter, err := NewRateLimiter(cfg.RateLimit)
+		if err != nil {
+			log.Errorf("failed to create rate limiter, requests will not be rate limited: %v", err)
+		} else {
+			h = RateLimitMiddleware(limiter)(h)
+		}
+	}
+
 	h = LoggingMiddleware(log)(h)
 
 	if cfg.CORS.Enabled {
 		h = CORSMiddleware(cfg.CORS.AllowedOrigins)(h)
 	}
 
+	// The correlation ID is set first, so every line logged for a request carries it
+	h = CorrelationIDMiddleware()(h)
+
 	// Use configured timeouts (defaults already applied in config.ApplyDefaults)
 	httpServer := &http.Server{
 		Addr:         cfg.ListenAddress,
@@ -71,15 +166,62 @@ func NewServer(cfg *config.APIConfig, registry IndexerRegistry, rpcClient rpc.Et
 	}
 
 	return &Server{
-		config:   cfg,
-		registry: registry,
-		handler:  handler,
-		server:   httpServer,
-		log:      log,
-		rpc:      rpcClient,
+		config:    cfg,
+		registry:  registry,
+		handler:   handler,
+		server:    httpServer,
+		log:       log,
+		rpc:       rpcClient,
+		keys:      keys,
+		keySource: keySource,
 	}
 }
 
+// SetRetentionPreviewer enables the retention preview and simulation endpoints. It must be called before Start.
+func (s *Server) SetRetentionPreviewer(previewer RetentionPreviewer) {
+	s.handler.retention = previewer
+}
+
+// SetLogStore enables the coverage endpoint, which reads the coverage of the downloader's log store.
+// It must be called before Start.
+func (s *Server) SetLogStore(logStore store.LogStore) {
+	s.handler.logStore = logStore
+}
+
+// SetDatabaseBackup enables the snapshot and restore endpoints. It must be called before Start.
+func (s *Server) SetDatabaseBackup(backup DatabaseBackup) {
+	s.handler.backup = backup
+}
+
+// SetReorgHistorySource enables the reorg history endpoint. It must be called before Start.
+func (s *Server) SetReorgHistorySource(source ReorgHistorySource) {
+	s.handler.reorgs = source
+}
+
+// SetDatabasePinger enables the downloader database check of the readiness probe. It must be called before Start.
+func (s *Server) SetDatabasePinger(pinger DatabasePinger) {
+	s.handler.database = pinger
+}
+
+// SetPendingEventSource enables the pending events endpoint. It must be called before Start.
+func (s *Server) SetPendingEventSource(source PendingEventSource) {
+	s.handler.pending = source
+}
+
+// SetProgressSource enables the backfill progress endpoint. It must be called before Start.
+func (s *Server) SetProgressSource(source ProgressSource) {
+	s.handler.progress = newBackfillProgress(source)
+
+	// Shutdown waits for open requests, so the progress streams are ended when it starts
+	s.server.RegisterOnShutdown(s.handler.progress.Close)
+}
+
+// PublishIndexedLogs streams the events decoded from logs just handled by the named indexer
+// to the clients of the event stream endpoint. It is meant to be set as the coordinator's logs handled hook.
+func (s *Server) PublishIndexedLogs(indexerName string, logs []types.Log) {
+	s.handler.stream.Publish(indexerName, logs)
+}
+
 // Start starts the API server.
 func (s *Server) Start(ctx context.Context) error {
 	if !s.config.Enabled {
@@ -87,11 +229,34 @@ func (s *Server) Start(ctx context.Context) error {
 		return nil
 	}
 
-	s.log.Infof("Starting API server on %s", s.config.ListenAddress)
+	if s.keySource != nil {
+		if err := s.keys.Refresh(ctx, s.keySource); err != nil {
+			return fmt.Errorf("failed to load API keys: %w", err)
+		}
+
+		go s.keys.Run(ctx, s.keySource, s.config.Auth.KeyRotationInterval.Duration, s.log)
+	}
+
+	serve := s.server.ListenAndServe
+	if s.config.TLS != nil {
+		certs, err := NewCertReloader(s.config.TLS.CertFile, s.config.TLS.KeyFile)
+		if err != nil {
+			return err
+		}
+
+		if err := certs.Watch(ctx, s.log); err != nil {
+			return err
+		}
+
+		s.server.TLSConfig = certs.TLSConfig(s.config.TLS.MinVersion)
+		serve = func() error { return s.server.ListenAndServeTLS("", "") }
+	}
+
+	s.log.Infof("Starting API server on %s (TLS: %t)", s.config.ListenAddress, s.config.TLS != nil)
 
 	// Start server in goroutine
 	go func() {
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := serve(); err != nil && err != http.ErrServerClosed {
 			s.log.Errorf("API server error: %v", err)
 		}
 	}()
@@ -100,10 +265,18 @@ func (s *Server) Start(ctx context.Context) error {
 	<-ctx.Done()
 
 	// Graceful shutdown
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownCtxTimeout)
+	shutdownTimeout := s.config.ShutdownTimeout.Duration
+	if shutdownTimeout == 0 {
+		shutdownTimeout = shutdownCtxTimeout
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
 	s.log.Info("Shutting down API server...")
+
+	// Shutdown does not wait for hijacked connections, so stream clients are disconnected explicitly
+	s.handler.stream.Close()
 	if err := s.server.Shutdown(shutdownCtx); err != nil {
 		return fmt.Errorf("API server shutdown error: %w", err)
 	}
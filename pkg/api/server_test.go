@@ -2,17 +2,26 @@ package api
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
 	"github.com/goran-ethernal/ChainIndexor/internal/common"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/api/docs"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
 	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
 	indexermocks "github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestNewServer(t *testing.T) {
@@ -265,6 +274,108 @@ func TestServer_Middleware(t *testing.T) {
 	}
 }
 
+func TestServer_Auth(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.APIConfig{
+		Enabled:       true,
+		ListenAddress: ":8080",
+		Auth: &config.AuthConfig{
+			Enabled: true,
+			APIKeys: []string{"secret"},
+		},
+	}
+	cfg.ApplyDefaults()
+
+	registry := apimocks.NewIndexerRegistry(t)
+	registry.EXPECT().ListAll().Return(([]indexer.Indexer)(nil)).Maybe()
+
+	server := NewServer(cfg, registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+	require.NotNil(t, server.keys)
+	require.Nil(t, server.keySource)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/indexers", nil)
+	w := httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/indexers", nil)
+	req.Header.Set(APIKeyHeader, "secret")
+	w = httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestServer_OpenAPISpec(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.APIConfig{
+		Enabled:       true,
+		ListenAddress: ":8080",
+		Auth: &config.AuthConfig{
+			Enabled: true,
+			APIKeys: []string{"secret"},
+		},
+	}
+	cfg.ApplyDefaults()
+
+	server := NewServer(cfg, apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+
+	// The spec is public by default, like the Swagger UI
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi.yaml", nil)
+	w := httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	require.Equal(t, docs.OpenAPISpec, w.Body.Bytes())
+	require.Contains(t, w.Body.String(), "openapi: 3.1.0")
+}
+
+func TestServer_MaxRequestBodySize(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.APIConfig{Enabled: true, ListenAddress: ":8080"}
+	cfg.ApplyDefaults()
+	require.Equal(t, int64(10<<20), cfg.MaxRequestBodySize)
+
+	cfg.MaxRequestBodySize = 16
+	server := NewServer(cfg, apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/indexers", strings.NewReader(strings.Repeat("x", 17)))
+	w := httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	require.JSONEq(t, `{"error": "Request body too large", "code": 413}`, w.Body.String())
+}
+
+func TestServer_MaxExportRows(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.APIConfig{Enabled: true, ListenAddress: ":8080"}
+	cfg.ApplyDefaults()
+	require.Equal(t, uint64(10_000_000), cfg.MaxExportRows)
+
+	cfg.MaxExportRows = 500
+	server := NewServer(cfg, apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+	require.Equal(t, uint64(500), server.handler.maxExportRows)
+}
+
+func TestServer_MaxResponseRows(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.APIConfig{Enabled: true, ListenAddress: ":8080"}
+	cfg.ApplyDefaults()
+	require.Equal(t, 10_000, cfg.MaxResponseRows)
+
+	cfg.MaxResponseRows = 250
+	server := NewServer(cfg, apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+	require.Equal(t, 250, server.handler.maxResponseRows)
+
+	cfg.MaxResponseRows = -1
+	require.ErrorContains(t, cfg.Validate(), "max_response_rows must be non-negative")
+}
+
 func TestServer_Timeouts(t *testing.T) {
 	t.Parallel()
 
@@ -408,3 +519,62 @@ func TestServer_ListenAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_CorrelationID(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	registry := apimocks.NewIndexerRegistry(t)
+	idx := newMockQueryableIndexer(t)
+	registry.EXPECT().GetByName("test-indexer").Return(idx)
+	idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.Anything).
+		Return(nil, 0, errors.New("database error"))
+
+	cfg := &config.APIConfig{CORS: config.CORSConfig{Enabled: true, AllowedOrigins: []string{"*"}}}
+	server := NewServer(cfg, registry, rpcmocks.NewEthClient(t), logger.NewLoggerWithCore(core))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/indexers/test-indexer/events", nil)
+	req.Header.Set(CorrelationIDHeader, "req-42")
+	w := httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Equal(t, "req-42", w.Header().Get(CorrelationIDHeader))
+
+	// Both the handler error and the request line carry the correlation ID
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		require.Equal(t, "req-42", entry.ContextMap()["correlation_id"], entry.Message)
+	}
+
+	// A missing or invalid ID is replaced with a generated one
+	for _, header := range []string{"", "bad id\nwith newline"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/unknown", nil)
+		req.Header.Set(CorrelationIDHeader, header)
+		w = httptest.NewRecorder()
+		server.server.Handler.ServeHTTP(w, req)
+
+		id, err := uuid.Parse(w.Header().Get(CorrelationIDHeader))
+		require.NoError(t, err)
+		require.Equal(t, uuid.Version(4), id.Version())
+
+		entries = logs.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, id.String(), entries[0].ContextMap()["correlation_id"])
+	}
+}
+
+func TestServer_Start_InvalidTLSCertificate(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.APIConfig{
+		Enabled:       true,
+		ListenAddress: "localhost:0",
+		TLS:           &config.TLSConfig{CertFile: "missing.crt", KeyFile: "missing.key", MinVersion: config.TLSVersion12},
+	}
+
+	server := NewServer(cfg, apimocks.NewIndexerRegistry(t), rpcmocks.NewEthClient(t), logger.NewNopLogger())
+	require.ErrorContains(t, server.Start(t.Context()), "failed to load TLS certificate")
+}
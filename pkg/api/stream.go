@@ -0,0 +1,273 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// streamWriteWait is the time allowed to write a single message to a stream client.
+	streamWriteWait = 10 * time.Second
+
+	// streamPongWait is how long a stream client may take to answer a ping.
+	streamPongWait = 60 * time.Second
+
+	// streamPingPeriod is how often stream clients are pinged. It must be shorter than streamPongWait.
+	streamPingPeriod = streamPongWait * 9 / 10
+
+	// streamMaxReadSize is the largest message accepted from a stream client, which only sends control frames.
+	streamMaxReadSize = 512
+
+	// streamPageSize is the number of events read per query when serialising an indexed batch.
+	streamPageSize = 1000
+)
+
+// streamUpgrader upgrades event stream requests to WebSocket connections.
+// Any origin is accepted: the API authenticates with API keys rather than cookies,
+// so a cross-site page cannot open a stream with the user's credentials.
+var streamUpgrader = websocket.Upgrader{
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// streamClient is a WebSocket client of the event stream and its filters.
+type streamClient struct {
+	indexer    string
+	queryable  indexer.Queryable
+	eventTypes []string
+	address    string
+	fromBlock  *uint64
+
+	// send queues the serialised messages for the client
+	send chan []byte
+
+	// dropped is closed when the client falls more than the send buffer behind
+	dropped  chan struct{}
+	dropOnce sync.Once
+}
+
+// drop marks the client as too slow to keep up. The client is disconnected by its writer.
+func (c *streamClient) drop() {
+	c.dropOnce.Do(func() { close(c.dropped) })
+}
+
+// EventStream pushes newly indexed events to connected WebSocket clients.
+type EventStream struct {
+	mu      sync.RWMutex
+	clients map[*streamClient]struct{}
+
+	// maxBuffered is the number of messages queued per client before it is disconnected
+	maxBuffered int
+
+	// closed is closed when the stream shuts down, disconnecting all clients
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	log *logger.Logger
+}
+
+// NewEventStream creates an EventStream that queues up to maxBuffered messages per client.
+func NewEventStream(maxBuffered int, log *logger.Logger) *EventStream {
+	return &EventStream{
+		clients:     make(map[*streamClient]struct{}),
+		maxBuffered: maxBuffered,
+		closed:      make(chan struct{}),
+		log:         log,
+	}
+}
+
+// Publish serialises the events decoded from logs just handled by the named indexer and queues them
+// for the clients streaming that indexer. It is meant to be set as the coordinator's logs handled hook.
+// Publish never blocks on a client: clients whose queue is full are disconnected instead.
+func (s *EventStream) Publish(indexerName string, logs []types.Log) {
+	if len(logs) == 0 {
+		return
+	}
+
+	s.mu.RLock()
+	clients := make([]*streamClient, 0, len(s.clients))
+	for client := range s.clients {
+		if client.indexer == indexerName {
+			clients = append(clients, client)
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(clients) == 0 {
+		return
+	}
+
+	fromBlock, toBlock := logs[0].BlockNumber, logs[0].BlockNumber
+	for _, log := range logs[1:] {
+		fromBlock = min(fromBlock, log.BlockNumber)
+		toBlock = max(toBlock, log.BlockNumber)
+	}
+
+	for _, client := range clients {
+		if err := s.publishTo(client, fromBlock, toBlock); err != nil {
+			s.log.Errorf("Failed to stream events of indexer '%s': %v", indexerName, err)
+		}
+	}
+}
+
+// publishTo queues the events in [fromBlock, toBlock] that match the client's filters,
+// one message per event type.
+func (s *EventStream) publishTo(client *streamClient, fromBlock, toBlock uint64) error {
+	if client.fromBlock != nil {
+		fromBlock = max(fromBlock, *client.fromBlock)
+	}
+	if fromBlock > toBlock {
+		return nil
+	}
+
+	// The logs were just indexed, so a slow query must not hold up the indexing path for long
+	ctx, cancel := context.WithTimeout(context.Background(), streamWriteWait)
+	defer cancel()
+
+	for _, eventType := range client.eventTypes {
+		params := indexer.QueryParams{
+			EventType: eventType,
+			Limit:     streamPageSize,
+			FromBlock: &fromBlock,
+			ToBlock:   &toBlock,
+			Address:   client.address,
+			SortBy:    []string{"block_number"},
+			SortOrder: "asc",
+		}
+
+		for {
+			events, _, err := client.queryable.QueryEvents(ctx, params)
+			if err != nil {
+				return fmt.Errorf("failed to query %s events: %w", eventType, err)
+			}
+
+			eventsVal := reflect.ValueOf(events)
+			if eventsVal.Kind() != reflect.Slice {
+				return fmt.Errorf("invalid events type: expected slice, got %T", events)
+			}
+			if eventsVal.Len() == 0 {
+				break
+			}
+
+			msg, err := json.Marshal(StreamMessage{
+				Indexer:   client.indexer,
+				EventType: eventType,
+				FromBlock: fromBlock,
+				ToBlock:   toBlock,
+				Events:    events,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to encode %s events: %w", eventType, err)
+			}
+
+			select {
+			case client.send <- msg:
+			default:
+				client.drop()
+				return nil
+			}
+
+			if eventsVal.Len() < params.Limit {
+				break
+			}
+
+			// Page with a cursor, as large batches would exceed the maximum offset of the indexer
+			cursor, ok := indexer.CursorOf(eventsVal.Index(eventsVal.Len() - 1))
+			if !ok {
+				return fmt.Errorf("%s events do not expose their block number and log index", eventType)
+			}
+			params.After = &cursor
+		}
+	}
+
+	return nil
+}
+
+// Close disconnects all clients. Clients connecting afterwards are disconnected right away.
+func (s *EventStream) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+func (s *EventStream) subscribe(client *streamClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clients[client] = struct{}{}
+}
+
+func (s *EventStream) unsubscribe(client *streamClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.clients, client)
+}
+
+// serve streams messages to the client over conn until the client disconnects, falls behind
+// or the stream is closed. The connection is closed when serve returns.
+func (s *EventStream) serve(conn *websocket.Conn, client *streamClient) {
+	s.subscribe(client)
+	defer s.unsubscribe(client)
+	defer conn.Close()
+
+	// Clients only send control frames, so the reader only keeps the connection alive
+	// and notices when the client goes away
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+
+		conn.SetReadLimit(streamMaxReadSize)
+		_ = conn.SetReadDeadline(time.Now().Add(streamPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(streamPongWait))
+		})
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(streamPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg := <-client.send:
+			_ = conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)); err != nil {
+				return
+			}
+		case <-client.dropped:
+			s.log.Warnf("Disconnecting event stream client of indexer '%s': more than %d messages behind",
+				client.indexer, s.maxBuffered)
+			closeStream(conn, websocket.ClosePolicyViolation, "client is too slow")
+			return
+		case <-s.closed:
+			closeStream(conn, websocket.CloseGoingAway, "server is shutting down")
+			return
+		case <-gone:
+			return
+		}
+	}
+}
+
+// closeStream sends a close frame with the given code. Errors are ignored, since the
+// connection is closed right after either way.
+func closeStream(conn *websocket.Conn, code int, reason string) {
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(streamWriteWait))
+}
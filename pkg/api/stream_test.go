@@ -0,0 +1,251 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type streamedEvent struct {
+	BlockNumber uint64 `json:"block_number"`
+}
+
+// newStreamServer serves the event stream endpoint of a handler with the given registry.
+func newStreamServer(t *testing.T, registry IndexerRegistry, maxBuffered int) (*httptest.Server, *EventStream) {
+	t.Helper()
+
+	handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+	handler.stream = NewEventStream(maxBuffered, logger.NewNopLogger())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/indexers/{name}/events/stream", handler.StreamEvents)
+
+	// The logging middleware wraps the response writer, which must still support hijacking
+	server := httptest.NewServer(LoggingMiddleware(logger.NewNopLogger())(mux))
+	t.Cleanup(server.Close)
+	t.Cleanup(handler.stream.Close)
+
+	return server, handler.stream
+}
+
+// dialStream connects to the event stream and waits until the client is subscribed.
+func dialStream(t *testing.T, server *httptest.Server, stream *EventStream, query string) *websocket.Conn {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/indexers/test-indexer/events/stream" + query
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+
+	require.Eventually(t, func() bool {
+		stream.mu.RLock()
+		defer stream.mu.RUnlock()
+		return len(stream.clients) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return conn
+}
+
+func TestHandler_StreamEvents(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		indexerName    string
+		query          string
+		setupMocks     func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "indexer not found",
+			indexerName: "nonexistent",
+			setupMocks: func(registry *apimocks.IndexerRegistry, _ *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("nonexistent").Return(nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"code": 404, "error": "Not Found", "message": "indexer 'nonexistent' not found"}`,
+		},
+		{
+			name:        "unknown event type",
+			indexerName: "test-indexer",
+			query:       "?event_type=Swap",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": 400, "error": "Bad Request", "message": "unknown event type 'Swap'"}`,
+		},
+		{
+			name:        "invalid from_block",
+			indexerName: "test-indexer",
+			query:       "?from_block=abc",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("test-indexer").Return(idx)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"code": 400, "error": "Bad Request", ` +
+				`"message": "invalid query parameters: invalid from_block"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			idx := newMockQueryableIndexer(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(registry, idx)
+			}
+
+			handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+			handler.stream = NewEventStream(1, logger.NewNopLogger())
+
+			url := fmt.Sprintf("/api/v1/indexers/%s/events/stream%s", tt.indexerName, tt.query)
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.SetPathValue("name", tt.indexerName)
+			w := httptest.NewRecorder()
+
+			handler.StreamEvents(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestEventStream_Publish(t *testing.T) {
+	t.Parallel()
+
+	registry := apimocks.NewIndexerRegistry(t)
+	idx := newMockQueryableIndexer(t)
+	registry.EXPECT().GetByName("test-indexer").Return(idx)
+	idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer", "Approval"})
+
+	// The stream filters are applied on top of the block range of the handled logs
+	fromBlock, toBlock := uint64(11), uint64(12)
+	idx.Queryable.EXPECT().QueryEvents(mock.Anything, indexer.QueryParams{
+		EventType: "Transfer",
+		Limit:     streamPageSize,
+		FromBlock: &fromBlock,
+		ToBlock:   &toBlock,
+		Address:   "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		SortBy:    []string{"block_number"},
+		SortOrder: "asc",
+	}).Return([]streamedEvent{{BlockNumber: 11}, {BlockNumber: 12}}, 2, nil).Once()
+
+	server, stream := newStreamServer(t, registry, 8)
+	conn := dialStream(t, server, stream, "?event_type=transfer&address=0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48&from_block=11")
+
+	// Logs of other indexers and logs before from_block are not streamed
+	stream.Publish("other-indexer", []types.Log{{BlockNumber: 11}})
+	stream.Publish("test-indexer", []types.Log{{BlockNumber: 9}, {BlockNumber: 10}})
+	stream.Publish("test-indexer", []types.Log{{BlockNumber: 12}, {BlockNumber: 10}})
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"indexer": "test-indexer", "event_type": "Transfer", "from_block": 11, "to_block": 12, `+
+		`"events": [{"block_number": 11}, {"block_number": 12}]}`, string(data))
+}
+
+func TestEventStream_DisconnectsSlowClients(t *testing.T) {
+	t.Parallel()
+
+	stream := NewEventStream(1, logger.NewNopLogger())
+
+	idx := newMockQueryableIndexer(t)
+	idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.Anything).
+		Return([]streamedEvent{{BlockNumber: 1}}, 1, nil).Twice()
+
+	client := &streamClient{
+		indexer:    "test-indexer",
+		queryable:  idx,
+		eventTypes: []string{"Transfer"},
+		send:       make(chan []byte, stream.maxBuffered),
+		dropped:    make(chan struct{}),
+	}
+	stream.subscribe(client)
+
+	// Nothing reads the queue, so the second message does not fit
+	stream.Publish("test-indexer", []types.Log{{BlockNumber: 1}})
+	require.Len(t, client.send, 1)
+	select {
+	case <-client.dropped:
+		t.Fatal("client dropped before its queue was full")
+	default:
+	}
+
+	stream.Publish("test-indexer", []types.Log{{BlockNumber: 1}})
+	require.Len(t, client.send, 1)
+	<-client.dropped
+}
+
+func TestEventStream_CloseCodes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		disconnect   func(stream *EventStream)
+		expectedCode int
+	}{
+		{
+			name: "slow client",
+			disconnect: func(stream *EventStream) {
+				stream.mu.RLock()
+				defer stream.mu.RUnlock()
+				for client := range stream.clients {
+					client.drop()
+				}
+			},
+			expectedCode: websocket.ClosePolicyViolation,
+		},
+		{
+			name:         "server shutdown",
+			disconnect:   (*EventStream).Close,
+			expectedCode: websocket.CloseGoingAway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			idx := newMockQueryableIndexer(t)
+			registry.EXPECT().GetByName("test-indexer").Return(idx)
+			idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+
+			server, stream := newStreamServer(t, registry, 1)
+			conn := dialStream(t, server, stream, "")
+
+			tt.disconnect(stream)
+
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+			_, _, err := conn.ReadMessage()
+			require.True(t, websocket.IsCloseError(err, tt.expectedCode), "unexpected error: %v", err)
+
+			// The client is unsubscribed once disconnected
+			require.Eventually(t, func() bool {
+				stream.mu.RLock()
+				defer stream.mu.RUnlock()
+				return len(stream.clients) == 0
+			}, 5*time.Second, 10*time.Millisecond)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+)
+
+// tlsVersions maps the minimum TLS versions of the configuration to their crypto/tls values.
+var tlsVersions = map[string]uint16{
+	config.TLSVersion12: tls.VersionTLS12,
+	config.TLSVersion13: tls.VersionTLS13,
+}
+
+// CertReloader serves the certificate of the API server and reloads it when its files change,
+// so renewed certificates are used without restarting the process. Connections already
+// established keep the certificate they were negotiated with.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewCertReloader creates a reloader serving the certificate loaded from the given files.
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: filepath.Clean(certFile), keyFile: filepath.Clean(keyFile)}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload loads the certificate from its files. The previous certificate is kept if loading fails.
+func (r *CertReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+
+	return nil
+}
+
+// GetCertificate returns the current certificate. It is meant to be set as tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+// TLSConfig returns the server TLS configuration serving the certificate of the reloader.
+func (r *CertReloader) TLSConfig(minVersion string) *tls.Config {
+	return &tls.Config{
+		MinVersion:     tlsVersions[minVersion],
+		GetCertificate: r.GetCertificate,
+	}
+}
+
+// Watch reloads the certificate whenever its files change, until the context is cancelled.
+// The directories of the files are watched rather than the files themselves, so files
+// replaced by a rename, as done by certificate managers, are picked up too.
+func (r *CertReloader) Watch(ctx context.Context, log *logger.Logger) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create TLS certificate watcher: %w", err)
+	}
+
+	for _, dir := range []string{filepath.Dir(r.certFile), filepath.Dir(r.keyFile)} {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("failed to watch TLS certificate directory %s: %w", dir, err)
+		}
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				name := filepath.Clean(event.Name)
+				if (name != r.certFile && name != r.keyFile) || event.Op == fsnotify.Chmod {
+					continue
+				}
+
+				// The certificate and key are usually written one after the other, so loading
+				// can fail until both are in place. The event of the second file retries it
+				if err := r.Reload(); err != nil {
+					log.Warnf("failed to reload TLS certificate, keeping previous certificate: %v", err)
+					continue
+				}
+				log.Infof("Reloaded TLS certificate from %s", r.certFile)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Warnf("TLS certificate watcher error: %v", err)
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCertificate writes a self-signed certificate for 127.0.0.1 with the given serial number
+// to the certificate and key files, and returns it.
+func writeTestCertificate(t *testing.T, certFile, keyFile string, serial int64) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "chainindexor-test"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	// The key is written first, so the pair only matches once the certificate is written too
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
+
+func TestCertReloader(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	first := writeTestCertificate(t, certFile, keyFile, 1)
+
+	certs, err := NewCertReloader(certFile, keyFile)
+	require.NoError(t, err)
+	require.NoError(t, certs.Watch(t.Context(), logger.NewNopLogger()))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	// StartTLS would serve the certificate of httptest, so the listener is wrapped instead
+	server.Listener = tls.NewListener(server.Listener, certs.TLSConfig(config.TLSVersion13))
+	server.Start()
+	t.Cleanup(server.Close)
+	url := strings.Replace(server.URL, "http://", "https://", 1)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(first)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+
+	// get makes a request and returns the serial number of the certificate it was served with,
+	// and whether it reused an open connection
+	get := func(client *http.Client) (int64, bool) {
+		var reused bool
+		trace := &httptrace.ClientTrace{GotConn: func(info httptrace.GotConnInfo) { reused = info.Reused }}
+
+		req, err := http.NewRequestWithContext(httptrace.WithClientTrace(t.Context(), trace),
+			http.MethodGet, url, nil)
+		require.NoError(t, err)
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, "ok", string(body))
+		require.Equal(t, uint16(tls.VersionTLS13), resp.TLS.Version)
+
+		return resp.TLS.PeerCertificates[0].SerialNumber.Int64(), reused
+	}
+
+	serial, _ := get(client)
+	require.Equal(t, int64(1), serial)
+
+	second := writeTestCertificate(t, certFile, keyFile, 2)
+	roots.AddCert(second)
+
+	// New connections get the new certificate once it is reloaded
+	require.Eventually(t, func() bool {
+		serial, _ := get(&http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}})
+		return serial == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The connection opened before the reload is still served
+	serial, reused := get(client)
+	require.True(t, reused)
+	require.Equal(t, int64(1), serial)
+}
+
+func TestCertReloader_InvalidFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+	_, err := NewCertReloader(certFile, keyFile)
+	require.ErrorContains(t, err, "failed to load TLS certificate")
+
+	writeTestCertificate(t, certFile, keyFile, 1)
+	certs, err := NewCertReloader(certFile, keyFile)
+	require.NoError(t, err)
+
+	// A broken certificate file keeps the previous certificate
+	require.NoError(t, os.WriteFile(certFile, []byte("not a certificate"), 0o600))
+	require.Error(t, certs.Reload())
+
+	cert, err := certs.GetCertificate(nil)
+	require.NoError(t, err)
+	require.NotNil(t, cert)
+}
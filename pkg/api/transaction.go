@@ -0,0 +1,145 @@
+package api
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+)
+
+// txEventsPageSize is the number of events of an event type read per query while looking up a transaction
+const txEventsPageSize = 1000
+
+// GetTransactionEvents returns the events of a transaction from all indexers.
+// @Summary Get the events of a transaction
+// @Description Look up the events emitted in a transaction in every indexer that supports querying, without knowing which indexers handle them. Events are sorted by log index, and carry the name of their indexer and their event type next to the fields returned by the events endpoint. Event types that do not record transaction hashes are skipped. A transaction without indexed events returns an empty list
+// @Tags Events
+// @Produce json
+// @Param txHash path string true "Transaction hash (32-byte hex)"
+// @Success 200 {object} TxEventsResponse "Events of the transaction"
+// @Failure 400 {object} ErrorResponse "Invalid transaction hash"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /tx/{txHash}/events [get]
+func (h *Handler) GetTransactionEvents(w http.ResponseWriter, r *http.Request) {
+	txHash, err := parseHash(r.PathValue("txHash"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid transaction hash: %v", err))
+		return
+	}
+
+	events := []TxEvent{}
+	for _, idx := range h.registry.ListAll() {
+		queryable, ok := idx.(indexer.Queryable)
+		if !ok {
+			continue
+		}
+
+		indexerEvents, err := transactionEvents(r.Context(), idx.GetName(), queryable, txHash)
+		if err != nil {
+			requestLogger(h.log, r).Errorf("Failed to query events of transaction %s: %v", txHash.Hex(), err)
+			respondError(w, http.StatusInternalServerError, "failed to query events")
+			return
+		}
+
+		events = append(events, indexerEvents...)
+	}
+
+	// Events of the same log from several indexers keep the order of the indexers
+	slices.SortStableFunc(events, func(a, b TxEvent) int {
+		return cmp.Compare(a.logIndex, b.logIndex)
+	})
+
+	respondJSON(w, http.StatusOK, TxEventsResponse{
+		TxHash: txHash.Hex(),
+		Events: events,
+	})
+}
+
+// transactionEvents returns the events of every event type of an indexer emitted in the transaction.
+func transactionEvents(
+	ctx context.Context,
+	indexerName string,
+	queryable indexer.Queryable,
+	txHash common.Hash,
+) ([]TxEvent, error) {
+	var events []TxEvent
+
+	for _, eventType := range queryable.GetEventTypes() {
+		params := indexer.QueryParams{
+			EventType: eventType,
+			Limit:     txEventsPageSize,
+			TxHash:    &txHash,
+			SortOrder: "asc",
+		}
+
+		for {
+			page, _, err := queryable.QueryEvents(ctx, params)
+			if errors.Is(err, indexer.ErrTxHashFilterUnsupported) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("indexer %s: %w", indexerName, err)
+			}
+
+			pageVal := reflect.ValueOf(page)
+			if pageVal.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("indexer %s: invalid events type: expected slice, got %T", indexerName, page)
+			}
+
+			var cursor indexer.EventCursor
+			for i := range pageVal.Len() {
+				event := pageVal.Index(i)
+
+				var ok bool
+				cursor, ok = indexer.CursorOf(event)
+				if !ok {
+					return nil, fmt.Errorf("indexer %s: %s events do not expose their block number and log index",
+						indexerName, eventType)
+				}
+
+				events = append(events, TxEvent{
+					Indexer:   indexerName,
+					EventType: eventType,
+					Event:     event.Interface(),
+					logIndex:  cursor.LogIndex,
+				})
+			}
+
+			if pageVal.Len() < params.Limit {
+				break
+			}
+			params.After = &cursor
+		}
+	}
+
+	return events, nil
+}
+
+// MarshalJSON encodes the fields of the event with the indexer and event_type fields added.
+func (e TxEvent) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(e.Event)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("event of type %T is not encoded as an object: %w", e.Event, err)
+	}
+
+	if fields["indexer"], err = json.Marshal(e.Indexer); err != nil {
+		return nil, err
+	}
+	if fields["event_type"], err = json.Marshal(e.EventType); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(fields)
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	rpcmocks "github.com/goran-ethernal/ChainIndexor/internal/rpc/mocks"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	indexermocks "github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_GetTransactionEvents(t *testing.T) {
+	t.Parallel()
+
+	txHash := common.HexToHash("0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060")
+
+	withTxHash := mock.MatchedBy(func(params indexer.QueryParams) bool {
+		return params.TxHash != nil && *params.TxHash == txHash
+	})
+	ofType := func(eventType string) any {
+		return mock.MatchedBy(func(params indexer.QueryParams) bool {
+			return params.EventType == eventType && params.TxHash != nil && *params.TxHash == txHash
+		})
+	}
+
+	tests := []struct {
+		name       string
+		txHash     string
+		setupMocks func(t *testing.T, registry *apimocks.IndexerRegistry)
+		status     int
+		validate   func(t *testing.T, body []byte)
+	}{
+		{
+			name:   "events of all indexers sorted by log index",
+			txHash: txHash.Hex(),
+			setupMocks: func(t *testing.T, registry *apimocks.IndexerRegistry) {
+				t.Helper()
+
+				tokens := newMockQueryableIndexer(t)
+				tokens.Indexer.EXPECT().GetName().Return("tokens")
+				tokens.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer", "Approval"})
+				tokens.Queryable.EXPECT().QueryEvents(mock.Anything, ofType("Transfer")).
+					Return([]*testEvent{{ID: 1, BlockNumber: 10, LogIndex: 1}, {ID: 2, BlockNumber: 10, LogIndex: 4}}, 2, nil)
+				tokens.Queryable.EXPECT().QueryEvents(mock.Anything, ofType("Approval")).
+					Return([]*testEvent{}, 0, nil)
+
+				nfts := newMockQueryableIndexer(t)
+				nfts.Indexer.EXPECT().GetName().Return("nfts")
+				nfts.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+				nfts.Queryable.EXPECT().QueryEvents(mock.Anything, withTxHash).
+					Return([]*testEvent{{ID: 7, BlockNumber: 10, LogIndex: 2}}, 1, nil)
+
+				registry.EXPECT().ListAll().Return([]indexer.Indexer{tokens, indexermocks.NewIndexer(t), nfts})
+			},
+			status: http.StatusOK,
+			validate: func(t *testing.T, body []byte) {
+				t.Helper()
+
+				var response map[string]any
+				require.NoError(t, json.Unmarshal(body, &response))
+				require.Equal(t, txHash.Hex(), response["tx_hash"])
+
+				data, err := json.Marshal(response["events"])
+				require.NoError(t, err)
+				require.JSONEq(t, `[
+					{"indexer": "tokens", "event_type": "Transfer", "ID": 1, "BlockNumber": 10, "LogIndex": 1},
+					{"indexer": "nfts", "event_type": "Transfer", "ID": 7, "BlockNumber": 10, "LogIndex": 2},
+					{"indexer": "tokens", "event_type": "Transfer", "ID": 2, "BlockNumber": 10, "LogIndex": 4}
+				]`, string(data))
+			},
+		},
+		{
+			name:   "event types without transaction hashes are skipped",
+			txHash: txHash.Hex(),
+			setupMocks: func(t *testing.T, registry *apimocks.IndexerRegistry) {
+				t.Helper()
+
+				idx := newMockQueryableIndexer(t)
+				idx.Indexer.EXPECT().GetName().Return("tokens")
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Snapshot", "Transfer"})
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, ofType("Snapshot")).
+					Return(nil, 0, fmt.Errorf("%w: Snapshot events have no transaction hash",
+						indexer.ErrTxHashFilterUnsupported))
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, ofType("Transfer")).
+					Return([]*testEvent{{ID: 1, BlockNumber: 10, LogIndex: 0}}, 1, nil)
+
+				registry.EXPECT().ListAll().Return([]indexer.Indexer{idx})
+			},
+			status: http.StatusOK,
+			validate: func(t *testing.T, body []byte) {
+				t.Helper()
+
+				var response TxEventsResponse
+				require.NoError(t, json.Unmarshal(body, &response))
+				require.Len(t, response.Events, 1)
+				require.Equal(t, "Transfer", response.Events[0].EventType)
+			},
+		},
+		{
+			name:   "no events",
+			txHash: txHash.Hex(),
+			setupMocks: func(t *testing.T, registry *apimocks.IndexerRegistry) {
+				t.Helper()
+
+				registry.EXPECT().ListAll().Return([]indexer.Indexer{})
+			},
+			status: http.StatusOK,
+			validate: func(t *testing.T, body []byte) {
+				t.Helper()
+
+				require.JSONEq(t, fmt.Sprintf(`{"tx_hash": %q, "events": []}`, txHash.Hex()), string(body))
+			},
+		},
+		{
+			name:   "query error",
+			txHash: txHash.Hex(),
+			setupMocks: func(t *testing.T, registry *apimocks.IndexerRegistry) {
+				t.Helper()
+
+				idx := newMockQueryableIndexer(t)
+				idx.Indexer.EXPECT().GetName().Return("tokens")
+				idx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer"})
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, withTxHash).
+					Return(nil, 0, errors.New("database is locked"))
+
+				registry.EXPECT().ListAll().Return([]indexer.Indexer{idx})
+			},
+			status: http.StatusInternalServerError,
+			validate: func(t *testing.T, body []byte) {
+				t.Helper()
+
+				require.Contains(t, string(body), "failed to query events")
+			},
+		},
+		{
+			name:   "hash too short",
+			txHash: "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204",
+			status: http.StatusBadRequest,
+			validate: func(t *testing.T, body []byte) {
+				t.Helper()
+
+				require.Contains(t, string(body), "invalid transaction hash")
+			},
+		},
+		{
+			name:   "hash without prefix",
+			txHash: "5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060",
+			status: http.StatusBadRequest,
+			validate: func(t *testing.T, body []byte) {
+				t.Helper()
+
+				require.Contains(t, string(body), "invalid transaction hash")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(t, registry)
+			}
+
+			handler := NewHandler(registry, rpcmocks.NewEthClient(t), logger.NewNopLogger())
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/tx/%s/events", tt.txHash), nil)
+			req.SetPathValue("txHash", tt.txHash)
+			w := httptest.NewRecorder()
+
+			handler.GetTransactionEvents(w, req)
+
+			require.Equal(t, tt.status, w.Code)
+			tt.validate(t, w.Body.Bytes())
+		})
+	}
+}
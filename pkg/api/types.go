@@ -3,24 +3,34 @@ package api
 import (
 	"time"
 
+	"github.com/goran-ethernal/ChainIndexor/pkg/downloader"
 	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/goran-ethernal/ChainIndexor/pkg/reorg"
 )
 
 // Re-export indexer response types for API use
 type StatsResponse = indexer.StatsResponse
 type TimeseriesDataPoint = indexer.TimeseriesDataPoint
 type MetricsResponse = indexer.MetricsResponse
+type EventSchema = indexer.EventSchema
 
 // QueryParams represents common query parameters for event retrieval.
 type QueryParams struct {
 	// Pagination
-	Limit  int `json:"limit" form:"limit"`
+	Limit int `json:"limit" form:"limit"`
+	// Deprecated: Offset pages shift when events are indexed between requests, use Cursor instead.
 	Offset int `json:"offset" form:"offset"`
+	// Cursor is the next_cursor of a previous response
+	Cursor *string `json:"cursor,omitempty" form:"cursor"`
 
 	// Block range filtering
 	FromBlock *uint64 `json:"from_block,omitempty" form:"from_block"`
 	ToBlock   *uint64 `json:"to_block,omitempty" form:"to_block"`
 
+	// Block timestamp filtering, in Unix seconds
+	FromTimestamp *uint64 `json:"from_timestamp,omitempty" form:"from_timestamp"`
+	ToTimestamp   *uint64 `json:"to_timestamp,omitempty" form:"to_timestamp"`
+
 	// Address filtering (contract or participant address)
 	Address string `json:"address,omitempty" form:"address"`
 
@@ -28,8 +38,14 @@ type QueryParams struct {
 	FromTime *time.Time `json:"from_time,omitempty" form:"from_time"`
 	ToTime   *time.Time `json:"to_time,omitempty" form:"to_time"`
 
+	// Field projection
+	Fields string `json:"fields,omitempty" form:"fields"` // Comma-separated columns to return
+
+	// ABI decoding of the non-indexed parameters from the log data
+	DecodeData bool `json:"decode_data,omitempty" form:"decode_data"`
+
 	// Sorting
-	SortBy    string `json:"sort_by,omitempty" form:"sort_by"`       // Field to sort by
+	SortBy    string `json:"sort_by,omitempty" form:"sort_by"`       // Comma-separated fields to sort by
 	SortOrder string `json:"sort_order,omitempty" form:"sort_order"` // "asc" or "desc"
 }
 
@@ -38,6 +54,7 @@ type QueryParams struct {
 type EventResponse struct {
 	Events     interface{}      `json:"events" description:"Array of events"`
 	Pagination PaginationResult `json:"pagination" description:"Pagination metadata"`
+	NextCursor *string          `json:"next_cursor,omitempty" description:"Cursor to pass as the cursor parameter to fetch the next page"` //nolint:lll
 }
 
 // PaginationResult contains pagination metadata.
@@ -45,7 +62,7 @@ type EventResponse struct {
 type PaginationResult struct {
 	Total   int  `json:"total" example:"1000" description:"Total number of items"`
 	Limit   int  `json:"limit" example:"100" description:"Items per page"`
-	Offset  int  `json:"offset" example:"0" description:"Current offset"`
+	Offset  int  `json:"offset" example:"0" description:"Current offset (deprecated, use next_cursor)"`
 	HasMore bool `json:"has_more" example:"true" description:"Whether more items are available"`
 }
 
@@ -73,6 +90,114 @@ type IndexerStatus struct {
 	LatestBlock uint64 `json:"latest_block" example:"19500000" description:"Latest indexed block"`
 	EventCount  int64  `json:"event_count" example:"150000" description:"Total events indexed"`
 	Healthy     bool   `json:"healthy" example:"true" description:"Whether indexer is healthy"`
+
+	CoveragePercentage *float64 `json:"coverage_percentage,omitempty" example:"99.7" description:"Share of blocks from the start block to the latest fetched block that logs were fetched for"` //nolint:lll
+}
+
+// ReadinessResponse represents a readiness probe response.
+// @Description Result of every readiness check and the names of the failed ones
+type ReadinessResponse struct {
+	Status    string                 `json:"status" example:"ready" description:"ready if every check passed, not_ready otherwise"` //nolint:lll
+	Timestamp time.Time              `json:"timestamp" description:"Time of the readiness check"`
+	Checks    []ReadinessCheckResult `json:"checks" description:"Result of each check"`
+	Failed    []string               `json:"failed" description:"Names of the failed checks"`
+}
+
+// ReadinessCheckResult represents the result of a single readiness check.
+// @Description Result of a single readiness check
+type ReadinessCheckResult struct {
+	Name       string `json:"name" example:"rpc" description:"Check name: rpc, downloader_db or indexer:<name>"`
+	Healthy    bool   `json:"healthy" example:"true" description:"Whether the check passed"`
+	Error      string `json:"error,omitempty" description:"Why the check failed"`
+	DurationMs int64  `json:"duration_ms" example:"12" description:"Duration of the check in milliseconds"`
+}
+
+// LivenessResponse represents a liveness probe response.
+// @Description Liveness status of the API server
+type LivenessResponse struct {
+	Status string `json:"status" example:"ok" description:"Always ok"`
+}
+
+// EventSchemaResponse represents the event schema of an indexer.
+// @Description Event names, fields, Solidity types and indexed flags of an indexer
+type EventSchemaResponse struct {
+	Events []EventSchema `json:"events" description:"Schema of every event handled by the indexer"`
+}
+
+// PendingEventsResponse represents the previewed events of pending transactions.
+// @Description Events that pending transactions are expected to emit, replaced by confirmed events once indexed
+type PendingEventsResponse struct {
+	Events []downloader.PendingEvent `json:"events" description:"Pending events, oldest first"`
+	Count  int                       `json:"count" example:"3" description:"Number of pending events"`
+}
+
+// StreamMessage is a batch of newly indexed events pushed to event stream clients.
+// @Description Events of one type indexed from a batch of logs, pushed over the event stream
+type StreamMessage struct {
+	Indexer   string `json:"indexer" example:"erc20" description:"Indexer name"`
+	EventType string `json:"event_type" example:"Transfer" description:"Event type of the events"`
+	FromBlock uint64 `json:"from_block" example:"19500000" description:"First block of the indexed batch"`
+	ToBlock   uint64 `json:"to_block" example:"19500010" description:"Last block of the indexed batch"`
+	Events    any    `json:"events" description:"Decoded events, ordered by block number"`
+}
+
+// BackfillProgressEvent is the backfill progress of an indexer, pushed to backfill progress stream clients.
+// @Description Backfill progress of an indexer, with the remaining time estimated from its recent sync rate
+type BackfillProgressEvent struct {
+	IndexerName     string  `json:"indexer_name" example:"erc20" description:"Indexer name"`
+	CurrentBlock    uint64  `json:"current_block" example:"19000000" description:"Last block indexed"`
+	TargetBlock     uint64  `json:"target_block" example:"19500000" description:"Block the backfill is syncing towards"`
+	PercentComplete float64 `json:"percent_complete" example:"42.5" description:"Progress from the indexer's start block"`
+	//nolint:lll
+	EstimatedSecondsRemaining *uint64 `json:"estimated_seconds_remaining,omitempty" example:"3600" description:"Estimated time to reach the target block, omitted until the sync rate is known"`
+}
+
+// CoverageResponse is the log coverage of the contracts of an indexer.
+// @Description Block ranges the downloader fetched logs for, and the ranges still missing up to the finalized block, by contract address
+type CoverageResponse struct {
+	FinalizedBlock uint64                  `json:"finalized_block" example:"19500000" description:"Finalized block"`
+	Coverage       map[string][]BlockRange `json:"coverage" description:"Block ranges with logs, by contract"`
+	//nolint:lll
+	MissingRanges map[string][]BlockRange `json:"missing_ranges" description:"Block ranges from the indexer's start block up to the finalized block without fetched logs, by contract address"`
+}
+
+// BlockRange is an inclusive range of blocks.
+// @Description Inclusive range of blocks
+type BlockRange struct {
+	FromBlock uint64 `json:"from_block" example:"19000000" description:"First block of the range"`
+	ToBlock   uint64 `json:"to_block" example:"19500000" description:"Last block of the range"`
+}
+
+// ReorgHistoryResponse is the list of reorgs the downloader detected.
+// @Description Chain reorganizations detected by the downloader, oldest first
+type ReorgHistoryResponse struct {
+	Reorgs []reorg.ReorgEvent `json:"reorgs" description:"Detected reorgs, oldest first"`
+	Count  int                `json:"count" example:"2" description:"Number of reorgs"`
+}
+
+// SnapshotRequest is the destination of a database snapshot.
+// @Description File on the server to write the snapshot to. It must not exist
+type SnapshotRequest struct {
+	DestPath string `json:"dest_path" example:"/backups/downloader-2024-01-31.db" description:"Path of the snapshot file"`
+}
+
+// RestoreRequest is the snapshot a database is restored from.
+// @Description Snapshot file on the server to restore the database from
+type RestoreRequest struct {
+	SrcPath string `json:"src_path" example:"/backups/downloader-2024-01-31.db" description:"Path of the snapshot file"`
+}
+
+// SnapshotResponse describes the snapshot file that was written or restored.
+// @Description Snapshot file that was written or restored
+type SnapshotResponse struct {
+	Path      string `json:"path" example:"/backups/downloader-2024-01-31.db" description:"Path of the snapshot file"`
+	SizeBytes int64  `json:"size_bytes" example:"104857600" description:"Size of the snapshot file in bytes"`
+}
+
+// ReloadLabelsResponse reports the address labels loaded by a reload.
+// @Description Address labels loaded by a reload
+type ReloadLabelsResponse struct {
+	Labels int `json:"labels" example:"42" description:"Number of address labels, from the configuration and the labels file"`
 }
 
 // IndexerInfo represents information about an available indexer.
@@ -83,3 +208,40 @@ type IndexerInfo struct {
 	EventTypes []string `json:"event_types" description:"Supported event types"`
 	Endpoints  []string `json:"endpoints" description:"Available API endpoints for this indexer"`
 }
+
+// AggregateRequest is a query aggregating a field of an event type across several indexers.
+// @Description Aggregation of a numeric event field across indexers over an optional block range
+type AggregateRequest struct {
+	Indexers    []string `json:"indexers" example:"usdc,usdt" description:"Names of the indexers to aggregate"`
+	EventType   string   `json:"event_type" example:"Transfer" description:"Event type to aggregate"`
+	Aggregation string   `json:"aggregation" example:"sum" enums:"sum,count,avg,min,max" description:"Aggregation"`
+	Field       string   `json:"field,omitempty" example:"value" description:"Numeric event field, optional for count"`
+	FromBlock   *uint64  `json:"from_block,omitempty" example:"19500000" description:"Aggregate events from this block"`
+	ToBlock     *uint64  `json:"to_block,omitempty" example:"19501000" description:"Aggregate events up to this block"`
+}
+
+// AggregateResult is the result of an aggregate query.
+// @Description Aggregate of all indexers and of every indexer as decimal strings, which omits indexers without events for avg, min and max
+type AggregateResult struct {
+	Indexers  []string          `json:"indexers" example:"usdc,usdt" description:"Names of the aggregated indexers"`
+	Total     *string           `json:"total" example:"1250000000000000000000" description:"Aggregate of the events of all indexers, null for avg, min and max without events"` //nolint:lll
+	ByIndexer map[string]string `json:"by_indexer" description:"Aggregate of the events of every indexer"`
+}
+
+// TxEventsResponse lists the events of a transaction found in all indexers.
+// @Description Events of a transaction from every indexer, in log index order
+type TxEventsResponse struct {
+	TxHash string    `json:"tx_hash" example:"0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060" description:"Transaction hash"` //nolint:lll
+	Events []TxEvent `json:"events" description:"Events of the transaction"`
+}
+
+// TxEvent is an event of a transaction, with the indexer and event type it was found in.
+// It is encoded as the event object of the events endpoint, extended with the indexer and event_type fields.
+// @Description Event of a transaction: the fields of the event, as returned by the events endpoint, with its indexer and event type
+type TxEvent struct {
+	Indexer   string `json:"indexer" example:"erc20" description:"Name of the indexer that indexed the event"`
+	EventType string `json:"event_type" example:"Transfer" description:"Event type"`
+	Event     any    `json:"-"`
+
+	logIndex uint
+}
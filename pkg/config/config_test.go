@@ -0,0 +1,147 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandDBPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		path     string
+		vars     map[string]string
+		expected string
+		wantErr  string
+	}{
+		{
+			name:     "no placeholders",
+			path:     "./data/indexer.db",
+			expected: "./data/indexer.db",
+		},
+		{
+			name:     "chain id",
+			path:     "./data/{chain_id}/downloader.db",
+			vars:     map[string]string{"chain_id": "137"},
+			expected: "./data/137/downloader.db",
+		},
+		{
+			name:     "indexer name",
+			path:     "./data/{indexer_name}.db",
+			vars:     map[string]string{"indexer_name": "erc20"},
+			expected: "./data/erc20.db",
+		},
+		{
+			name:     "date",
+			path:     "./data/{date}.db",
+			expected: "./data/" + time.Now().Format(time.DateOnly) + ".db",
+		},
+		{
+			name:     "date set by the variables",
+			path:     "./data/{date}.db",
+			vars:     map[string]string{"date": "2024-01-31"},
+			expected: "./data/2024-01-31.db",
+		},
+		{
+			name:     "every placeholder",
+			path:     "./data/{chain_id}/{indexer_name}-{date}.db",
+			vars:     map[string]string{"chain_id": "1", "indexer_name": "erc20", "date": "2024-01-31"},
+			expected: "./data/1/erc20-2024-01-31.db",
+		},
+		{
+			name:     "repeated placeholder",
+			path:     "./{indexer_name}/{indexer_name}.db",
+			vars:     map[string]string{"indexer_name": "erc20"},
+			expected: "./erc20/erc20.db",
+		},
+		{
+			name:    "missing variable",
+			path:    "./data/{indexer_name}.db",
+			vars:    map[string]string{"chain_id": "1"},
+			wantErr: "no value for {indexer_name}",
+		},
+		{
+			name:    "unknown placeholder",
+			path:    "./data/{network}.db",
+			wantErr: "no value for {network}",
+		},
+		{
+			name:    "empty substitution",
+			path:    "./data/{indexer_name}.db",
+			vars:    map[string]string{"indexer_name": ""},
+			wantErr: "empty value for {indexer_name}",
+		},
+		{
+			name:    "parent directory",
+			path:    "./data/{indexer_name}/events.db",
+			vars:    map[string]string{"indexer_name": ".."},
+			wantErr: "must be a single path segment",
+		},
+		{
+			name:    "nested path",
+			path:    "./data/{indexer_name}.db",
+			vars:    map[string]string{"indexer_name": "../../etc/passwd"},
+			wantErr: "must be a single path segment",
+		},
+		{
+			name:    "absolute path",
+			path:    "{indexer_name}.db",
+			vars:    map[string]string{"indexer_name": "/tmp/erc20"},
+			wantErr: "must be a single path segment",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path, err := ExpandDBPath(tt.path, tt.vars)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, path)
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b     string
+		distance int
+	}{
+		{a: "", b: "", distance: 0},
+		{a: "erc20", b: "erc20", distance: 0},
+		{a: "", b: "erc20", distance: 5},
+		{a: "erc2o", b: "erc20", distance: 1},
+		{a: "erc72", b: "erc721", distance: 1},
+		{a: "rec20", b: "erc20", distance: 2},
+		{a: "kitten", b: "sitting", distance: 3},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.distance, levenshtein(tt.a, tt.b), "%q -> %q", tt.a, tt.b)
+		require.Equal(t, tt.distance, levenshtein(tt.b, tt.a), "%q -> %q", tt.b, tt.a)
+	}
+}
+
+func TestClosestMatch(t *testing.T) {
+	t.Parallel()
+
+	candidates := []string{"erc1155", "erc20", "erc721"}
+
+	require.Equal(t, "erc20", closestMatch("erc2o", candidates, 2))
+	require.Equal(t, "erc721", closestMatch("erc712", candidates, 2))
+	require.Empty(t, closestMatch("uniswap-v2", candidates, 2))
+	require.Empty(t, closestMatch("erc3475", candidates, 2))
+
+	// Ties are broken by the order of the candidates
+	require.Equal(t, "erc20", closestMatch("erc21", candidates, 2))
+}
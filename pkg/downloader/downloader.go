@@ -3,6 +3,7 @@ package downloader
 import (
 	"context"
 
+	"github.com/goran-ethernal/ChainIndexor/pkg/alert"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
 	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
 )
@@ -18,6 +19,15 @@ type Downloader interface {
 	// It continues until the context is cancelled or an error occurs.
 	Download(ctx context.Context, cfg config.Config) error
 
+	// SetProgressChannel sets the channel on which progress events are published
+	// after each processed block range. Sends are non-blocking, so events are dropped
+	// if the channel is full. Pass nil to stop publishing.
+	SetProgressChannel(ch chan<- ProgressEvent)
+
+	// SetAlertManager sets the manager used to send alerts, such as indexer lag alerts.
+	// It must be called before Download.
+	SetAlertManager(alerts alert.Manager)
+
 	// Close gracefully stops the downloader, ensuring all resources are cleaned up.
 	Close() error
 }
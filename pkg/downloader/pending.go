@@ -0,0 +1,30 @@
+package downloader
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// PendingEvent is an event that a pending transaction is expected to emit, extracted by
+// simulating the transaction against the latest block. It is dropped once the transaction's
+// logs are indexed from a confirmed block, which then become the authoritative data.
+type PendingEvent struct {
+	// Indexer is the name of the indexer the event is relevant to.
+	Indexer string `json:"indexer"`
+	// Address is the contract that emits the event.
+	Address common.Address `json:"address" swaggertype:"string"`
+	// Topics are the topics of the simulated log, topic0 first.
+	Topics []common.Hash `json:"topics" swaggertype:"array,string"`
+	// Data is the non-indexed data of the simulated log.
+	Data hexutil.Bytes `json:"data" swaggertype:"string"`
+	// TxHash is the hash of the pending transaction.
+	TxHash common.Hash `json:"tx_hash" swaggertype:"string"`
+	// LogIndex is the index of the log within the transaction.
+	LogIndex uint `json:"log_index"`
+	// Pending is always true, to tell previews apart from confirmed events.
+	Pending bool `json:"pending"`
+	// SeenAt is when the transaction was seen in the mempool.
+	SeenAt time.Time `json:"seen_at"`
+}
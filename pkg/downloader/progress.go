@@ -0,0 +1,36 @@
+package downloader
+
+// ProgressEvent describes the sync progress of a single indexer.
+// It is sent by the downloader after each successfully processed block range.
+type ProgressEvent struct {
+	// Type is the fetch mode the downloader was in when the range was processed ("backfill" or "live").
+	Type string `json:"type"`
+	// Indexer is the name of the indexer this progress refers to.
+	Indexer string `json:"indexer"`
+	// CurrentBlock is the last block indexed.
+	CurrentBlock uint64 `json:"current_block"`
+	// TargetBlock is the block the downloader is syncing towards (usually the finalized head).
+	TargetBlock uint64 `json:"target_block"`
+	// Percentage is the sync progress of the indexer from its start block to TargetBlock, in range [0, 100].
+	Percentage float64 `json:"percentage"`
+}
+
+// NewProgressEvent creates a ProgressEvent and calculates the sync percentage
+// relative to the indexer's start block.
+func NewProgressEvent(eventType, indexer string, startBlock, currentBlock, targetBlock uint64) ProgressEvent {
+	percentage := 100.0
+	if targetBlock > startBlock && currentBlock < targetBlock {
+		percentage = 0.0
+		if currentBlock >= startBlock {
+			percentage = float64(currentBlock-startBlock) / float64(targetBlock-startBlock) * 100 //nolint:mnd
+		}
+	}
+
+	return ProgressEvent{
+		Type:         eventType,
+		Indexer:      indexer,
+		CurrentBlock: currentBlock,
+		TargetBlock:  targetBlock,
+		Percentage:   percentage,
+	}
+}
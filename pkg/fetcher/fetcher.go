@@ -46,4 +46,6 @@ type FetchResult struct {
 	Headers   []*types.Header
 	FromBlock uint64
 	ToBlock   uint64
+	// TargetBlock is the block the fetcher is currently syncing towards.
+	TargetBlock uint64
 }
@@ -12,14 +12,26 @@ import (
 // indexers need the same log data.
 type LogStore interface {
 	// GetLogs retrieves logs for the given address and block range.
-	// Returns logs that have been previously stored.
+	// Returns logs that have been previously stored. When topics[i] is set, only logs whose
+	// topic i equals it are returned; nil and missing entries match any topic.
 	// Also returns coverage information indicating which block ranges are available in the store.
 	GetLogs(
 		ctx context.Context,
 		address common.Address,
 		fromBlock, toBlock uint64,
+		topics []*common.Hash,
 	) (logs []types.Log, coverage []CoverageRange, err error)
 
+	// GetLogsBatch retrieves logs for the given addresses and block range, like GetLogs does
+	// for a single address, with one query for the logs and one for the coverage of all addresses.
+	// The logs and coverage are keyed by address, and every address has an entry.
+	GetLogsBatch(
+		ctx context.Context,
+		addresses []common.Address,
+		fromBlock, toBlock uint64,
+		topics []*common.Hash,
+	) (logs map[common.Address][]types.Log, coverage map[common.Address][]CoverageRange, err error)
+
 	// StoreLogs saves logs to the store for the given address and block range.
 	// This should be called after fetching logs from the RPC node.
 	// The store will track coverage to know which ranges have been downloaded.
@@ -4,13 +4,50 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// RetentionPreview describes what applying a retention policy would delete from the log store.
+type RetentionPreview struct {
+	// PruneBeforeBlock is the block before which logs would be pruned, 0 if nothing would be pruned
+	PruneBeforeBlock uint64 `json:"prune_before_block"`
+
+	// EstimatedRowsDeleted is the number of log and coverage rows that would be deleted
+	EstimatedRowsDeleted uint64 `json:"estimated_rows_deleted"`
+
+	// EstimatedSpaceFreedMB is the estimated database space the deleted rows take up
+	EstimatedSpaceFreedMB uint64 `json:"estimated_space_freed_mb"`
+}
+
+// RetentionSimulation describes what applying a retention policy would delete from the whole log store.
+type RetentionSimulation struct {
+	// PruneBeforeBlock is the block before which logs would be pruned, 0 if nothing would be pruned
+	PruneBeforeBlock uint64 `json:"prune_before_block"`
+
+	// EstimatedLogsDeleted is the number of event logs that would be deleted
+	EstimatedLogsDeleted int64 `json:"estimated_logs_deleted"`
+
+	// EstimatedMBFreed is the estimated database space the deleted rows take up, in megabytes
+	EstimatedMBFreed float64 `json:"estimated_mb_freed"`
+
+	// BlockRangeRetained is the first and last block of the coverage that would be kept, [0, 0] if none
+	BlockRangeRetained [2]uint64 `json:"block_range_retained"`
+}
+
 type UnsyncedTopics struct {
-	addrToTopicCoverage map[common.Address]map[common.Hash]CoverageRange
+	addrToTopicCoverage map[common.Address]map[common.Hash]topicCoverage
+}
+
+// topicCoverage is the coverage of an unsynced address-topic combination.
+type topicCoverage struct {
+	// span reaches from the first to the last covered block, zero without coverage
+	span CoverageRange
+	// fromBlock is the first block the topic has to be covered from
+	fromBlock uint64
+	// ranges are the covered block ranges, ordered by from block
+	ranges []CoverageRange
 }
 
 func NewUnsyncedTopics() *UnsyncedTopics {
 	return &UnsyncedTopics{
-		addrToTopicCoverage: make(map[common.Address]map[common.Hash]CoverageRange),
+		addrToTopicCoverage: make(map[common.Address]map[common.Hash]topicCoverage),
 	}
 }
 
@@ -25,7 +62,7 @@ func (ut *UnsyncedTopics) ShouldCatchUp(lastIndexedBlock, downloaderStartBlock u
 
 	for _, topicMap := range ut.addrToTopicCoverage {
 		for _, coverage := range topicMap {
-			if coverage.ToBlock < lastIndexedBlock {
+			if coverage.span.ToBlock < lastIndexedBlock {
 				return true
 			}
 		}
@@ -47,14 +84,82 @@ func (ut *UnsyncedTopics) ContainsTopic(address common.Address, topic common.Has
 	return topicExists
 }
 
+// AddTopic records an unsynced topic of the address, covered by a single range from which it has
+// to be covered. A zero coverage range stands for no coverage at all.
 func (ut *UnsyncedTopics) AddTopic(address common.Address, topic common.Hash, coverage CoverageRange) {
+	var ranges []CoverageRange
+	if coverage != (CoverageRange{}) {
+		ranges = []CoverageRange{coverage}
+	}
+
+	ut.AddTopicRanges(address, topic, coverage.FromBlock, ranges)
+}
+
+// AddTopicRanges records an unsynced topic of the address with all of its covered ranges, ordered by
+// from block, and the first block the topic has to be covered from.
+func (ut *UnsyncedTopics) AddTopicRanges(
+	address common.Address,
+	topic common.Hash,
+	fromBlock uint64,
+	ranges []CoverageRange,
+) {
 	if _, exists := ut.addrToTopicCoverage[address]; !exists {
-		ut.addrToTopicCoverage[address] = make(map[common.Hash]CoverageRange)
+		ut.addrToTopicCoverage[address] = make(map[common.Hash]topicCoverage)
+	}
+
+	coverage := topicCoverage{fromBlock: fromBlock, ranges: ranges}
+	if len(ranges) > 0 {
+		coverage.span = CoverageRange{FromBlock: ranges[0].FromBlock, ToBlock: ranges[len(ranges)-1].ToBlock}
 	}
 
 	ut.addrToTopicCoverage[address][topic] = coverage
 }
 
+// MissingRanges returns the disjoint block ranges each unsynced topic has no coverage for, from the
+// first block it has to be covered from up to upToBlock, ordered by from block.
+// Topics without missing ranges up to upToBlock are left out.
+func (ut *UnsyncedTopics) MissingRanges(upToBlock uint64) map[common.Address]map[common.Hash][]CoverageRange {
+	missing := make(map[common.Address]map[common.Hash][]CoverageRange)
+
+	for address, topicMap := range ut.addrToTopicCoverage {
+		for topic, coverage := range topicMap {
+			if coverage.fromBlock > upToBlock {
+				continue
+			}
+
+			ranges := GetMissingRanges(coverage.fromBlock, upToBlock, coverage.ranges)
+			if len(ranges) == 0 {
+				continue
+			}
+
+			if _, exists := missing[address]; !exists {
+				missing[address] = make(map[common.Hash][]CoverageRange)
+			}
+			missing[address][topic] = ranges
+		}
+	}
+
+	return missing
+}
+
+// AddressTopics returns the unsynced topics of the address and the lowest block their coverage
+// reaches, which is 0 if a topic has no coverage at all.
+func (ut *UnsyncedTopics) AddressTopics(address common.Address) ([]common.Hash, uint64) {
+	topicMap := ut.addrToTopicCoverage[address]
+	if len(topicMap) == 0 {
+		return nil, 0
+	}
+
+	topics := make([]common.Hash, 0, len(topicMap))
+	minCoveredBlock := ^uint64(0) // Max uint64
+	for topic, coverage := range topicMap {
+		topics = append(topics, topic)
+		minCoveredBlock = min(minCoveredBlock, coverage.span.ToBlock)
+	}
+
+	return topics, minCoveredBlock
+}
+
 func (ut *UnsyncedTopics) GetAddressesAndTopics() ([]common.Address, [][]common.Hash, uint64) {
 	addresses := make([]common.Address, 0, len(ut.addrToTopicCoverage))
 	topics := make([][]common.Hash, 0, len(ut.addrToTopicCoverage))
@@ -66,8 +171,8 @@ func (ut *UnsyncedTopics) GetAddressesAndTopics() ([]common.Address, [][]common.
 		topicList := make([]common.Hash, 0, len(topicMap))
 		for topic, coverage := range topicMap {
 			topicList = append(topicList, topic)
-			if coverage.ToBlock < minCoveredBlock {
-				minCoveredBlock = coverage.ToBlock
+			if coverage.span.ToBlock < minCoveredBlock {
+				minCoveredBlock = coverage.span.ToBlock
 			}
 		}
 
@@ -0,0 +1,327 @@
+// Package grpc serves the query API over gRPC, as an alternative to the REST API for
+// consumers that prefer it. Both transports query the indexers through the same interfaces.
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"time"
+
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/api"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	chainindexorv1 "github.com/goran-ethernal/ChainIndexor/proto/chainindexor/v1"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// Server implements the IndexerService of chainindexor.v1 on top of an indexer registry.
+type Server struct {
+	chainindexorv1.UnimplementedIndexerServiceServer
+
+	config   *config.GRPCConfig
+	registry api.IndexerRegistry
+	server   *grpclib.Server
+	log      *logger.Logger
+}
+
+// NewServer creates a new gRPC server.
+func NewServer(cfg *config.GRPCConfig, registry api.IndexerRegistry, log *logger.Logger) *Server {
+	s := &Server{
+		config:   cfg,
+		registry: registry,
+		server:   grpclib.NewServer(),
+		log:      log,
+	}
+
+	chainindexorv1.RegisterIndexerServiceServer(s.server, s)
+
+	return s
+}
+
+// Start starts the gRPC server and blocks until the context is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	if !s.config.Enabled {
+		s.log.Info("gRPC server is disabled")
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", s.config.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.ListenAddress, err)
+	}
+
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves gRPC requests on the listener until the context is cancelled.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	s.log.Infof("Starting gRPC server on %s", listener.Addr())
+
+	// Start server in goroutine
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, grpclib.ErrServerStopped) {
+			s.log.Errorf("gRPC server error: %v", err)
+		}
+	}()
+
+	// Wait for context cancellation
+	<-ctx.Done()
+
+	s.log.Info("Shutting down gRPC server...")
+
+	// GracefulStop waits for open streams, so they are cut off if they do not finish in time
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		s.server.Stop()
+	}
+
+	s.log.Info("gRPC server stopped")
+	return nil
+}
+
+// Health returns the health status of the server and all indexers.
+func (s *Server) Health(
+	ctx context.Context, _ *chainindexorv1.HealthRequest,
+) (*chainindexorv1.HealthResponse, error) {
+	response := &chainindexorv1.HealthResponse{
+		Status:    "ok",
+		Timestamp: timestamppb.Now(),
+	}
+
+	for _, idx := range s.registry.ListAll() {
+		queryable, ok := idx.(indexer.Queryable)
+		if !ok {
+			continue
+		}
+
+		stats, err := queryable.GetStats(ctx)
+		indexerStatus := &chainindexorv1.IndexerStatus{
+			Name:    idx.GetName(),
+			Type:    idx.GetType(),
+			Healthy: err == nil,
+		}
+
+		if err == nil {
+			indexerStatus.LatestBlock = stats.LatestBlock
+			// Sum all event counts
+			for _, count := range stats.EventCounts {
+				indexerStatus.EventCount += count
+			}
+		}
+
+		response.Indexers = append(response.Indexers, indexerStatus)
+	}
+
+	return response, nil
+}
+
+// ListIndexers lists the queryable indexers.
+func (s *Server) ListIndexers(
+	_ context.Context, _ *chainindexorv1.ListIndexersRequest,
+) (*chainindexorv1.ListIndexersResponse, error) {
+	response := &chainindexorv1.ListIndexersResponse{}
+
+	for _, idx := range s.registry.ListAll() {
+		if queryable, ok := idx.(indexer.Queryable); ok {
+			response.Indexers = append(response.Indexers, &chainindexorv1.IndexerInfo{
+				Type:       idx.GetType(),
+				Name:       idx.GetName(),
+				EventTypes: queryable.GetEventTypes(),
+			})
+		}
+	}
+
+	return response, nil
+}
+
+// GetStats returns the statistics of an indexer.
+func (s *Server) GetStats(
+	ctx context.Context, req *chainindexorv1.GetStatsRequest,
+) (*chainindexorv1.GetStatsResponse, error) {
+	queryable, err := s.queryable(req.GetIndexer())
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := queryable.GetStats(ctx)
+	if err != nil {
+		s.log.Errorf("Failed to get stats: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get stats")
+	}
+
+	return &chainindexorv1.GetStatsResponse{
+		TotalEvents:   stats.TotalEvents,
+		EventCounts:   stats.EventCounts,
+		EarliestBlock: stats.EarliestBlock,
+		LatestBlock:   stats.LatestBlock,
+	}, nil
+}
+
+// GetEvents streams the events of an indexer matching the request. Events are read from the
+// database a page at a time, each page continuing after the cursor of the last event sent.
+func (s *Server) GetEvents(
+	req *chainindexorv1.GetEventsRequest, stream grpclib.ServerStreamingServer[chainindexorv1.Event],
+) error {
+	queryable, err := s.queryable(req.GetIndexer())
+	if err != nil {
+		return err
+	}
+
+	params, err := queryParams(req)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid query parameters: %v", err)
+	}
+
+	remaining := req.GetLimit()
+	for {
+		params.Limit = s.config.PageSize
+		if remaining > 0 && remaining < uint64(params.Limit) {
+			params.Limit = int(remaining)
+		}
+
+		events, _, err := queryable.QueryEvents(stream.Context(), *params)
+		if err != nil {
+			if errors.Is(err, indexer.ErrInvalidCursor) || errors.Is(err, indexer.ErrTimestampFilterUnsupported) {
+				return status.Errorf(codes.InvalidArgument, "invalid query parameters: %v", err)
+			}
+
+			s.log.Errorf("Failed to query events: %v", err)
+			return status.Error(codes.Internal, "failed to query events")
+		}
+
+		// Use reflection to iterate since events could be any slice type
+		eventsVal := reflect.ValueOf(events)
+		if eventsVal.Kind() != reflect.Slice {
+			s.log.Errorf("Invalid events type returned from indexer '%s': expected slice, got %T",
+				req.GetIndexer(), events)
+			return status.Error(codes.Internal, "invalid events type returned from indexer")
+		}
+
+		for i := range eventsVal.Len() {
+			event, cursor, err := toEvent(eventsVal.Index(i))
+			if err != nil {
+				s.log.Errorf("Failed to convert event of indexer '%s': %v", req.GetIndexer(), err)
+				return status.Error(codes.Internal, "failed to convert event")
+			}
+
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+
+			params.Cursor = &cursor
+		}
+
+		if remaining > 0 {
+			remaining -= uint64(eventsVal.Len())
+			if remaining == 0 {
+				return nil
+			}
+		}
+
+		// A short page is the last one
+		if eventsVal.Len() < params.Limit {
+			return nil
+		}
+	}
+}
+
+// queryable returns the named indexer, or the status error to return if it cannot be queried.
+func (s *Server) queryable(name string) (indexer.Queryable, error) {
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "indexer name is required")
+	}
+
+	idx := s.registry.GetByName(name)
+	if idx == nil {
+		return nil, status.Errorf(codes.NotFound, "indexer '%s' not found", name)
+	}
+
+	queryable, ok := idx.(indexer.Queryable)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "indexer '%s' does not support querying", name)
+	}
+
+	return queryable, nil
+}
+
+// queryParams converts the filters of the request to query parameters.
+func queryParams(req *chainindexorv1.GetEventsRequest) (*indexer.QueryParams, error) {
+	params := indexer.NewDefaultQueryParams()
+	params.EventType = req.GetEventType()
+	params.Address = req.GetAddress()
+	params.FromBlock = req.FromBlock
+	params.ToBlock = req.ToBlock
+	params.FromTimestamp = req.FromTimestamp
+	params.ToTimestamp = req.ToTimestamp
+
+	switch req.GetSortOrder() {
+	case "":
+	case "asc", "desc":
+		params.SortOrder = req.GetSortOrder()
+	default:
+		return nil, fmt.Errorf("invalid sort_order: must be 'asc' or 'desc'")
+	}
+
+	// Pages are chained by cursor, so the first page is read from a cursor before all events
+	// when the request has none. SQLite integers are signed, so the start of a descending
+	// scan is the largest int64 rather than the largest uint64
+	params.After = &indexer.EventCursor{}
+	if params.SortOrder == "desc" {
+		params.After = &indexer.EventCursor{BlockNumber: math.MaxInt64, LogIndex: math.MaxInt64}
+	}
+
+	if cursor := req.GetCursor(); cursor != "" {
+		if _, err := indexer.DecodeCursor(cursor); err != nil {
+			return nil, err
+		}
+		params.Cursor = &cursor
+	}
+
+	return params, nil
+}
+
+// toEvent converts an event returned by an indexer to its message, with the same fields
+// as in the REST API, and returns the cursor positioned at it.
+func toEvent(event reflect.Value) (*chainindexorv1.Event, string, error) {
+	position, ok := indexer.CursorOf(event)
+	if !ok {
+		return nil, "", fmt.Errorf("event %s has no block number and log index", event.Type())
+	}
+	cursor := indexer.EncodeCursor(position)
+
+	data, err := json.Marshal(event.Interface())
+	if err != nil {
+		return nil, "", err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, "", err
+	}
+
+	message, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &chainindexorv1.Event{Data: message, Cursor: cursor}, cursor, nil
+}
@@ -0,0 +1,395 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sort"
+	"testing"
+
+	apimocks "github.com/goran-ethernal/ChainIndexor/internal/api/mocks"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	indexermocks "github.com/goran-ethernal/ChainIndexor/pkg/indexer/mocks"
+	chainindexorv1 "github.com/goran-ethernal/ChainIndexor/proto/chainindexor/v1"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufSize = 1024 * 1024
+
+// mockQueryableIndexer is a composite mock that implements both Indexer and Queryable interfaces
+type mockQueryableIndexer struct {
+	*indexermocks.Indexer
+	*indexermocks.Queryable
+}
+
+// newMockQueryableIndexer creates a new composite mock
+func newMockQueryableIndexer(t *testing.T) *mockQueryableIndexer {
+	t.Helper()
+
+	return &mockQueryableIndexer{
+		Indexer:   indexermocks.NewIndexer(t),
+		Queryable: indexermocks.NewQueryable(t),
+	}
+}
+
+// testEvent is an event model like the ones generated by indexer-gen
+type testEvent struct {
+	ID          int64  `meddler:"id,pk" json:"id"`
+	BlockNumber uint64 `meddler:"block_number" json:"block_number"`
+	LogIndex    uint   `meddler:"log_index" json:"log_index"`
+	From        string `meddler:"from_address" json:"from"`
+}
+
+// queryTestEvents answers QueryEvents like the base indexer does with a cursor,
+// over the given events which are sorted by block number and log index.
+func queryTestEvents(events []*testEvent) func(context.Context, indexer.QueryParams) (interface{}, int, error) {
+	return func(_ context.Context, params indexer.QueryParams) (interface{}, int, error) {
+		after := params.After
+		if params.Cursor != nil {
+			var err error
+			if after, err = indexer.DecodeCursor(*params.Cursor); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		ordered := append([]*testEvent(nil), events...)
+		desc := params.SortOrder != "asc"
+		if desc {
+			sort.Slice(ordered, func(i, j int) bool { return ordered[i].BlockNumber > ordered[j].BlockNumber })
+		}
+
+		page := make([]*testEvent, 0, params.Limit)
+		for _, event := range ordered {
+			isAfter := event.BlockNumber > after.BlockNumber
+			if desc {
+				isAfter = event.BlockNumber < after.BlockNumber
+			}
+			if isAfter && len(page) < params.Limit {
+				page = append(page, event)
+			}
+		}
+
+		return page, len(events), nil
+	}
+}
+
+// newTestClient serves the registry in-process and returns a client connected to it.
+func newTestClient(t *testing.T, registry *apimocks.IndexerRegistry) chainindexorv1.IndexerServiceClient {
+	t.Helper()
+
+	cfg := &config.GRPCConfig{Enabled: true, PageSize: 2}
+	server := NewServer(cfg, registry, logger.NewNopLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	listener := bufconn.Listen(bufSize)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		require.NoError(t, server.Serve(ctx, listener))
+	}()
+
+	conn, err := grpclib.NewClient("passthrough:///bufconn",
+		grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpclib.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+		cancel()
+		<-done
+	})
+
+	return chainindexorv1.NewIndexerServiceClient(conn)
+}
+
+// receiveAll reads the events of the stream until it ends, and returns them with the error it ended with.
+func receiveAll(stream grpclib.ServerStreamingClient[chainindexorv1.Event]) ([]*chainindexorv1.Event, error) {
+	var events []*chainindexorv1.Event
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+		if err != nil {
+			return events, err
+		}
+		events = append(events, event)
+	}
+}
+
+func TestServer_Health(t *testing.T) {
+	t.Parallel()
+
+	healthyIdx := newMockQueryableIndexer(t)
+	healthyIdx.Indexer.EXPECT().GetName().Return("erc20")
+	healthyIdx.Indexer.EXPECT().GetType().Return("ERC20")
+	healthyIdx.Queryable.EXPECT().GetStats(mock.Anything).Return(indexer.StatsResponse{
+		LatestBlock: 200,
+		EventCounts: map[string]int64{"Transfer": 10, "Approval": 5},
+	}, nil)
+
+	failingIdx := newMockQueryableIndexer(t)
+	failingIdx.Indexer.EXPECT().GetName().Return("erc721")
+	failingIdx.Indexer.EXPECT().GetType().Return("ERC721")
+	failingIdx.Queryable.EXPECT().GetStats(mock.Anything).Return(indexer.StatsResponse{}, errors.New("database locked"))
+
+	registry := apimocks.NewIndexerRegistry(t)
+	registry.EXPECT().ListAll().Return([]indexer.Indexer{healthyIdx, failingIdx})
+
+	resp, err := newTestClient(t, registry).Health(context.Background(), &chainindexorv1.HealthRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp.GetStatus())
+	require.NotNil(t, resp.GetTimestamp())
+	require.Len(t, resp.GetIndexers(), 2)
+
+	require.Equal(t, "erc20", resp.GetIndexers()[0].GetName())
+	require.True(t, resp.GetIndexers()[0].GetHealthy())
+	require.Equal(t, uint64(200), resp.GetIndexers()[0].GetLatestBlock())
+	require.Equal(t, int64(15), resp.GetIndexers()[0].GetEventCount())
+
+	require.Equal(t, "erc721", resp.GetIndexers()[1].GetName())
+	require.False(t, resp.GetIndexers()[1].GetHealthy())
+}
+
+func TestServer_ListIndexers(t *testing.T) {
+	t.Parallel()
+
+	queryableIdx := newMockQueryableIndexer(t)
+	queryableIdx.Indexer.EXPECT().GetName().Return("erc20")
+	queryableIdx.Indexer.EXPECT().GetType().Return("ERC20")
+	queryableIdx.Queryable.EXPECT().GetEventTypes().Return([]string{"Transfer", "Approval"})
+
+	registry := apimocks.NewIndexerRegistry(t)
+	registry.EXPECT().ListAll().Return([]indexer.Indexer{queryableIdx, indexermocks.NewIndexer(t)})
+
+	resp, err := newTestClient(t, registry).ListIndexers(context.Background(), &chainindexorv1.ListIndexersRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetIndexers(), 1)
+	require.Equal(t, "erc20", resp.GetIndexers()[0].GetName())
+	require.Equal(t, "ERC20", resp.GetIndexers()[0].GetType())
+	require.Equal(t, []string{"Transfer", "Approval"}, resp.GetIndexers()[0].GetEventTypes())
+}
+
+func TestServer_GetStats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		indexer      string
+		setupMocks   func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer)
+		expectedCode codes.Code
+	}{
+		{
+			name:    "success",
+			indexer: "erc20",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("erc20").Return(idx)
+				idx.Queryable.EXPECT().GetStats(mock.Anything).Return(indexer.StatsResponse{
+					TotalEvents:   15,
+					EventCounts:   map[string]int64{"Transfer": 10, "Approval": 5},
+					EarliestBlock: 100,
+					LatestBlock:   200,
+				}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "missing indexer name",
+			setupMocks:   func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "indexer not found",
+			indexer: "unknown",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("unknown").Return(nil)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:    "stats error",
+			indexer: "erc20",
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("erc20").Return(idx)
+				idx.Queryable.EXPECT().GetStats(mock.Anything).Return(indexer.StatsResponse{}, errors.New("database locked"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			idx := newMockQueryableIndexer(t)
+			tt.setupMocks(registry, idx)
+
+			resp, err := newTestClient(t, registry).GetStats(context.Background(),
+				&chainindexorv1.GetStatsRequest{Indexer: tt.indexer})
+			require.Equal(t, tt.expectedCode, status.Code(err))
+
+			if tt.expectedCode == codes.OK {
+				require.Equal(t, int64(15), resp.GetTotalEvents())
+				require.Equal(t, map[string]int64{"Transfer": 10, "Approval": 5}, resp.GetEventCounts())
+				require.Equal(t, uint64(100), resp.GetEarliestBlock())
+				require.Equal(t, uint64(200), resp.GetLatestBlock())
+			}
+		})
+	}
+}
+
+func TestServer_GetEvents(t *testing.T) {
+	t.Parallel()
+
+	events := []*testEvent{
+		{ID: 1, BlockNumber: 10, From: "0xaaa"},
+		{ID: 2, BlockNumber: 11, From: "0xbbb"},
+		{ID: 3, BlockNumber: 12, From: "0xccc"},
+		{ID: 4, BlockNumber: 13, From: "0xddd"},
+		{ID: 5, BlockNumber: 14, From: "0xeee"},
+	}
+
+	tests := []struct {
+		name        string
+		request     *chainindexorv1.GetEventsRequest
+		expectedIDs []float64
+	}{
+		{
+			name:        "streams all events newest first across pages",
+			request:     &chainindexorv1.GetEventsRequest{Indexer: "erc20"},
+			expectedIDs: []float64{5, 4, 3, 2, 1},
+		},
+		{
+			name:        "ascending order",
+			request:     &chainindexorv1.GetEventsRequest{Indexer: "erc20", SortOrder: "asc"},
+			expectedIDs: []float64{1, 2, 3, 4, 5},
+		},
+		{
+			name:        "limit stops the stream",
+			request:     &chainindexorv1.GetEventsRequest{Indexer: "erc20", SortOrder: "asc", Limit: 3},
+			expectedIDs: []float64{1, 2, 3},
+		},
+		{
+			name: "continues after a cursor",
+			request: &chainindexorv1.GetEventsRequest{
+				Indexer:   "erc20",
+				SortOrder: "asc",
+				Cursor:    indexer.EncodeCursor(indexer.EventCursor{BlockNumber: 12, ID: 3}),
+			},
+			expectedIDs: []float64{4, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			idx := newMockQueryableIndexer(t)
+			idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.Anything).RunAndReturn(queryTestEvents(events))
+
+			registry := apimocks.NewIndexerRegistry(t)
+			registry.EXPECT().GetByName("erc20").Return(idx)
+
+			stream, err := newTestClient(t, registry).GetEvents(context.Background(), tt.request)
+			require.NoError(t, err)
+
+			received, err := receiveAll(stream)
+			require.NoError(t, err)
+
+			ids := make([]float64, 0, len(received))
+			for _, event := range received {
+				ids = append(ids, event.GetData().AsMap()["id"].(float64))
+
+				cursor, err := indexer.DecodeCursor(event.GetCursor())
+				require.NoError(t, err)
+				require.Equal(t, event.GetData().AsMap()["block_number"], float64(cursor.BlockNumber))
+			}
+			require.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
+
+func TestServer_GetEvents_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		request      *chainindexorv1.GetEventsRequest
+		setupMocks   func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer)
+		expectedCode codes.Code
+	}{
+		{
+			name:    "indexer not found",
+			request: &chainindexorv1.GetEventsRequest{Indexer: "unknown"},
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("unknown").Return(nil)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:    "invalid sort order",
+			request: &chainindexorv1.GetEventsRequest{Indexer: "erc20", SortOrder: "sideways"},
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("erc20").Return(idx)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "invalid cursor",
+			request: &chainindexorv1.GetEventsRequest{Indexer: "erc20", Cursor: "not-a-cursor"},
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("erc20").Return(idx)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "timestamp filter unsupported",
+			request: &chainindexorv1.GetEventsRequest{Indexer: "erc20", FromTimestamp: new(uint64)},
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("erc20").Return(idx)
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.Anything).
+					Return(nil, 0, indexer.ErrTimestampFilterUnsupported)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "query error",
+			request: &chainindexorv1.GetEventsRequest{Indexer: "erc20"},
+			setupMocks: func(registry *apimocks.IndexerRegistry, idx *mockQueryableIndexer) {
+				registry.EXPECT().GetByName("erc20").Return(idx)
+				idx.Queryable.EXPECT().QueryEvents(mock.Anything, mock.Anything).
+					Return(nil, 0, errors.New("database locked"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := apimocks.NewIndexerRegistry(t)
+			idx := newMockQueryableIndexer(t)
+			tt.setupMocks(registry, idx)
+
+			stream, err := newTestClient(t, registry).GetEvents(context.Background(), tt.request)
+			require.NoError(t, err)
+
+			_, err = receiveAll(stream)
+			require.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
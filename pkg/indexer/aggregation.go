@@ -0,0 +1,21 @@
+package indexer
+
+import (
+	"math/big"
+	"strings"
+)
+
+// averageDecimals is the number of decimal places averages are rounded to, the decimals of most tokens
+const averageDecimals = 18
+
+// FormatAverage returns sum / count as a decimal string rounded to 18 decimal places, without
+// trailing zeros, e.g. "2.5" or "12". The count must be positive.
+func FormatAverage(sum *big.Int, count int64) string {
+	average := new(big.Rat).SetFrac(sum, big.NewInt(count)).FloatString(averageDecimals)
+	average = strings.TrimSuffix(strings.TrimRight(average, "0"), ".")
+	if average == "-0" {
+		return "0"
+	}
+
+	return average
+}
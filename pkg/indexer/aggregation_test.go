@@ -0,0 +1,33 @@
+package indexer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAverage(t *testing.T) {
+	t.Parallel()
+
+	wide, ok := new(big.Int).SetString("100000000000000000001", 10)
+	require.True(t, ok)
+
+	tests := []struct {
+		sum      *big.Int
+		count    int64
+		expected string
+	}{
+		{sum: big.NewInt(36), count: 3, expected: "12"},
+		{sum: big.NewInt(5), count: 2, expected: "2.5"},
+		{sum: big.NewInt(2), count: 3, expected: "0.666666666666666667"},
+		{sum: big.NewInt(-7), count: 2, expected: "-3.5"},
+		{sum: big.NewInt(0), count: 4, expected: "0"},
+		{sum: wide, count: 1, expected: "100000000000000000001"},
+		{sum: wide, count: 2, expected: "50000000000000000000.5"},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, FormatAverage(tt.sum, tt.count), tt.sum.String())
+	}
+}
@@ -0,0 +1,104 @@
+package indexer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor encodes the position of an event into an opaque, URL safe cursor string.
+func EncodeCursor(cursor EventCursor) string {
+	// Marshaling a struct of integers cannot fail
+	data, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor decodes a cursor string produced by EncodeCursor.
+func DecodeCursor(cursor string) (*EventCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	var decoded EventCursor
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return &decoded, nil
+}
+
+// CursorOf returns the cursor positioned at the given event. Events are either structs,
+// whose fields are matched by their meddler column name, or maps keyed by column name.
+// It returns false if the event does not expose its block number and log index.
+func CursorOf(event reflect.Value) (EventCursor, bool) {
+	columns := make(map[string]reflect.Value)
+
+	for event.Kind() == reflect.Pointer || event.Kind() == reflect.Interface {
+		if event.IsNil() {
+			return EventCursor{}, false
+		}
+		event = event.Elem()
+	}
+
+	switch event.Kind() {
+	case reflect.Struct:
+		eventType := event.Type()
+		for i := range eventType.NumField() {
+			field := eventType.Field(i)
+			column, _, _ := strings.Cut(field.Tag.Get("meddler"), ",")
+			if column == "" {
+				column = field.Name
+			}
+			columns[column] = event.Field(i)
+		}
+	case reflect.Map:
+		if event.Type().Key().Kind() != reflect.String {
+			return EventCursor{}, false
+		}
+		for _, key := range event.MapKeys() {
+			columns[key.String()] = event.MapIndex(key)
+		}
+	default:
+		return EventCursor{}, false
+	}
+
+	blockNumber, ok := uintValue(columns["block_number"])
+	if !ok {
+		return EventCursor{}, false
+	}
+	logIndex, ok := uintValue(columns["log_index"])
+	if !ok {
+		return EventCursor{}, false
+	}
+
+	cursor := EventCursor{BlockNumber: blockNumber, LogIndex: uint(logIndex)}
+	if id, ok := uintValue(columns["id"]); ok {
+		cursor.ID = int64(id)
+	}
+
+	return cursor, true
+}
+
+// uintValue returns the value of an integer, or an interface holding one, as uint64.
+func uintValue(v reflect.Value) (uint64, bool) {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	switch {
+	case v.CanUint():
+		return v.Uint(), true
+	case v.CanInt() && v.Int() >= 0:
+		return uint64(v.Int()), true
+	default:
+		return 0, false
+	}
+}
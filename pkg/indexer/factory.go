@@ -1,7 +1,9 @@
 package indexer
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 
@@ -12,15 +14,37 @@ import (
 // Factory is a function that creates a new indexer instance.
 type Factory func(cfg config.IndexerConfig, log *logger.Logger) (Indexer, error)
 
+// FactoryMeta describes a registered indexer type. It is shown by the list command.
+type FactoryMeta struct {
+	// Description is a short description of what the indexer type indexes
+	Description string `json:"description,omitempty"`
+
+	// Version is the version of the indexer type
+	Version string `json:"version,omitempty"`
+
+	// Author is the author of the indexer type
+	Author string `json:"author,omitempty"`
+
+	// EventTables maps the name of every event the indexer type handles to its database table
+	EventTables map[string]string `json:"event_tables,omitempty"`
+}
+
+// RegisteredFactory is a registered indexer type with its metadata.
+type RegisteredFactory struct {
+	Type string `json:"type"`
+	FactoryMeta
+}
+
 var (
 	registry = make(map[string]Factory)
+	metadata = make(map[string]FactoryMeta)
 	mu       sync.RWMutex
 )
 
-// Register registers an indexer factory with the given type name.
+// Register registers an indexer factory with the given type name and optional metadata.
 // This is typically called in init() functions of indexer packages.
 // The type name is case-insensitive and will be stored in lowercase.
-func Register(indexerType string, factory Factory) {
+func Register(indexerType string, factory Factory, meta ...FactoryMeta) {
 	mu.Lock()
 	defer mu.Unlock()
 	name := strings.ToLower(indexerType)
@@ -30,6 +54,11 @@ func Register(indexerType string, factory Factory) {
 	}
 
 	registry[name] = factory
+
+	delete(metadata, name)
+	if len(meta) > 0 {
+		metadata[name] = meta[0]
+	}
 }
 
 // GetFactory returns the factory for the given indexer type.
@@ -53,6 +82,30 @@ func ListRegistered() []string {
 	return types
 }
 
+// ListRegisteredWithMeta returns all registered indexer types with their metadata, sorted by type.
+// Types registered without metadata have an empty FactoryMeta.
+func ListRegisteredWithMeta() []RegisteredFactory {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	factories := make([]RegisteredFactory, 0, len(registry))
+	for t := range registry {
+		factories = append(factories, RegisteredFactory{Type: t, FactoryMeta: metadata[t]})
+	}
+	slices.SortFunc(factories, func(a, b RegisteredFactory) int {
+		return cmp.Compare(a.Type, b.Type)
+	})
+
+	return factories
+}
+
+// ValidateIndexerTypes checks that every indexer of the config has a registered indexer type.
+// It reports every unknown type at once, with the closest registered type as a suggestion,
+// so a misspelled type is caught when the config is loaded instead of when the indexer is created.
+func ValidateIndexerTypes(cfg *config.Config) error {
+	return cfg.ValidateIndexerTypes(ListRegistered())
+}
+
 // Create creates a new indexer instance using the registered factory.
 // Returns an error if the type is not registered or if creation fails.
 // The type lookup is case-insensitive.
@@ -1,6 +1,7 @@
 package indexer
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -27,12 +28,14 @@ func (m *mockIndexerForFactory) EventsToIndex() map[common.Address]map[common.Ha
 	return make(map[common.Address]map[common.Hash]struct{})
 }
 func (m *mockIndexerForFactory) HandleReorg(blockNum uint64) error { return nil }
+func (m *mockIndexerForFactory) Ping(ctx context.Context) error    { return nil }
 
 // resetRegistry clears the factory registry for testing
 func resetRegistry() {
 	mu.Lock()
 	defer mu.Unlock()
 	registry = make(map[string]Factory)
+	metadata = make(map[string]FactoryMeta)
 }
 
 func TestRegister(t *testing.T) {
@@ -267,6 +270,93 @@ func TestListRegistered(t *testing.T) {
 	}
 }
 
+func TestListRegisteredWithMeta(t *testing.T) {
+	// Cannot use t.Parallel() because it modifies the global registry
+	resetRegistry()
+	defer resetRegistry()
+
+	factory := func(cfg config.IndexerConfig, log *logger.Logger) (Indexer, error) {
+		return &mockIndexerForFactory{}, nil
+	}
+
+	erc20Meta := FactoryMeta{
+		Description: "Indexes ERC20 events",
+		Version:     "1.2.0",
+		Author:      "ChainIndexor",
+		EventTables: map[string]string{"Transfer": "transfers", "Approval": "approvals"},
+	}
+
+	Register("ERC721", factory)
+	Register("erc20", factory, erc20Meta)
+
+	require.Equal(t, []RegisteredFactory{
+		{Type: "erc20", FactoryMeta: erc20Meta},
+		{Type: "erc721"},
+	}, ListRegisteredWithMeta())
+
+	// Registering a type again replaces its metadata
+	Register("erc20", factory)
+	require.Equal(t, []RegisteredFactory{{Type: "erc20"}, {Type: "erc721"}}, ListRegisteredWithMeta())
+}
+
+func TestValidateIndexerTypes(t *testing.T) {
+	// Cannot use t.Parallel() because it modifies the global registry
+	resetRegistry()
+	defer resetRegistry()
+
+	factory := func(cfg config.IndexerConfig, log *logger.Logger) (Indexer, error) {
+		return &mockIndexerForFactory{}, nil
+	}
+	Register("erc20", factory)
+	Register("erc721", factory)
+	Register("erc1155", factory)
+
+	tests := []struct {
+		name          string
+		indexerType   string
+		expectedError string
+	}{
+		{name: "registered type", indexerType: "erc20"},
+		{name: "type in another case", indexerType: "ERC721"},
+		{
+			name:        "letter O instead of zero",
+			indexerType: "erc2O",
+			expectedError: "indexer[0] (tokens): unknown indexer type 'erc2O', did you mean 'erc20'? " +
+				"(registered types: erc1155, erc20, erc721)",
+		},
+		{
+			name:        "missing character",
+			indexerType: "erc72",
+			expectedError: "indexer[0] (tokens): unknown indexer type 'erc72', did you mean 'erc721'? " +
+				"(registered types: erc1155, erc20, erc721)",
+		},
+		{
+			name:        "transposed characters",
+			indexerType: "rec1155",
+			expectedError: "indexer[0] (tokens): unknown indexer type 'rec1155', did you mean 'erc1155'? " +
+				"(registered types: erc1155, erc20, erc721)",
+		},
+		{
+			name:          "no close match",
+			indexerType:   "uniswap-v2",
+			expectedError: "indexer[0] (tokens): unknown indexer type 'uniswap-v2' (registered types: erc1155, erc20, erc721)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Indexers: []config.IndexerConfig{{Name: "tokens", Type: tt.indexerType}}}
+
+			err := ValidateIndexerTypes(cfg)
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
 func TestCreate(t *testing.T) {
 	// Cannot use t.Parallel() because tests modify the global registry
 
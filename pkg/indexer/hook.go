@@ -0,0 +1,68 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// PostProcessHook is notified after an indexer has persisted a batch of logs,
+// to trigger side effects such as publishing to a message queue or firing a webhook.
+type PostProcessHook interface {
+	// OnLogsProcessed is called with the logs the named indexer handled for the given block range.
+	// An error is logged and does not stop indexing.
+	OnLogsProcessed(ctx context.Context, indexerName string, logs []types.Log, fromBlock, toBlock uint64) error
+}
+
+// retryHookBackoffMultiplier is how much longer every retry of a RetryHook waits than the previous one.
+const retryHookBackoffMultiplier = 2
+
+// RetryHook retries a failing hook with exponential backoff.
+type RetryHook struct {
+	hook       PostProcessHook
+	maxRetries int
+	backoff    time.Duration
+}
+
+// Compile-time check to ensure RetryHook implements PostProcessHook.
+var _ PostProcessHook = (*RetryHook)(nil)
+
+// NewRetryHook wraps the hook, retrying it up to maxRetries times after a failure.
+// The first retry waits for backoff, and every following retry waits twice as long as the previous one.
+func NewRetryHook(hook PostProcessHook, maxRetries int, backoff time.Duration) *RetryHook {
+	return &RetryHook{
+		hook:       hook,
+		maxRetries: maxRetries,
+		backoff:    backoff,
+	}
+}
+
+// OnLogsProcessed implements PostProcessHook.
+func (h *RetryHook) OnLogsProcessed(
+	ctx context.Context,
+	indexerName string,
+	logs []types.Log,
+	fromBlock, toBlock uint64,
+) error {
+	err := h.hook.OnLogsProcessed(ctx, indexerName, logs, fromBlock, toBlock)
+
+	wait := h.backoff
+	for retry := 1; err != nil && retry <= h.maxRetries; retry++ {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled before retry %d/%d: %w", retry, h.maxRetries, ctx.Err())
+		}
+		wait *= retryHookBackoffMultiplier
+
+		err = h.hook.OnLogsProcessed(ctx, indexerName, logs, fromBlock, toBlock)
+	}
+
+	if err != nil && h.maxRetries > 0 {
+		return fmt.Errorf("hook failed after %d retries: %w", h.maxRetries, err)
+	}
+
+	return err
+}
@@ -0,0 +1,73 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyHook fails a number of times before it succeeds.
+type flakyHook struct {
+	failures int
+	calls    int
+}
+
+func (h *flakyHook) OnLogsProcessed(context.Context, string, []types.Log, uint64, uint64) error {
+	h.calls++
+	if h.calls <= h.failures {
+		return errors.New("webhook unreachable")
+	}
+
+	return nil
+}
+
+func TestRetryHook(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		failures   int
+		maxRetries int
+		wantCalls  int
+		wantErr    string
+	}{
+		{name: "succeeds at once", failures: 0, maxRetries: 3, wantCalls: 1},
+		{name: "succeeds on retry", failures: 2, maxRetries: 3, wantCalls: 3},
+		{name: "retries exhausted", failures: 5, maxRetries: 3, wantCalls: 4, wantErr: "hook failed after 3 retries"},
+		{name: "no retries", failures: 1, maxRetries: 0, wantCalls: 1, wantErr: "webhook unreachable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hook := &flakyHook{failures: tt.failures}
+			err := NewRetryHook(hook, tt.maxRetries, time.Millisecond).
+				OnLogsProcessed(t.Context(), "erc20", nil, 1, 10)
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantCalls, hook.calls)
+		})
+	}
+}
+
+func TestRetryHook_ContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	hook := &flakyHook{failures: 1}
+	err := NewRetryHook(hook, 3, time.Hour).OnLogsProcessed(ctx, "erc20", nil, 1, 10)
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, hook.calls)
+}
@@ -2,6 +2,7 @@ package indexer
 
 import (
 	"context"
+	"net/url"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -35,6 +36,30 @@ type Indexer interface {
 
 	// GetName returns the configured name of the indexer instance.
 	GetName() string
+
+	// Ping checks that the indexer can serve requests, typically by querying its database.
+	// It is called by the readiness probe of the API server.
+	Ping(ctx context.Context) error
+}
+
+// ConfirmationBuffered is an optional interface for indexers that want to receive logs only
+// after they have additional confirmations on top of the configured finality.
+type ConfirmationBuffered interface {
+	// ConfirmationBuffer returns the number of blocks the finalized block must advance past
+	// a log's block before the log is delivered to the indexer.
+	ConfirmationBuffer() uint64
+}
+
+// DynamicAddressProvider is an optional interface for indexers of contracts that are discovered
+// while indexing rather than configured, e.g. the pairs a Uniswap V2 factory deploys.
+// After every fetched chunk, the downloader adds the newly discovered addresses to the log filter
+// and catches up with their logs from the block they were discovered at.
+type DynamicAddressProvider interface {
+	// AddressFor returns the addresses of the contracts discovered at or before the given block.
+	AddressFor(ctx context.Context, blockNumber uint64) ([]common.Address, error)
+
+	// DynamicEvents returns the set of event topic hashes to index for every discovered contract.
+	DynamicEvents() map[common.Hash]struct{}
 }
 
 // Queryable is an optional interface that indexers can implement to support API queries.
@@ -58,4 +83,46 @@ type Queryable interface {
 	// Returns a MetricsResponse with events_per_block, avg_events_per_day,
 	// recent_blocks_analyzed, and recent_events_count.
 	GetMetrics(ctx context.Context) (MetricsResponse, error)
+
+	// QueryFirstEvent retrieves the earliest indexed event of the given type.
+	// Returns nil if no events of that type have been indexed yet.
+	QueryFirstEvent(ctx context.Context, eventType string) (interface{}, error)
+
+	// QueryLastEvent retrieves the most recently indexed event of the given type.
+	// Returns nil if no events of that type have been indexed yet.
+	QueryLastEvent(ctx context.Context, eventType string) (interface{}, error)
+
+	// GetEventSchema returns the name, Solidity type and indexed flag of the fields
+	// of every event this indexer handles.
+	GetEventSchema() []EventSchema
+}
+
+// Exportable is an optional interface for queryable indexers that can export every event matching
+// a query, which may be far more than a page of QueryEvents, without loading them into memory at once.
+type Exportable interface {
+	// ExportEvents calls fn for every event of params.EventType matching the filters of params,
+	// in block and log index order. Pagination and sorting parameters are ignored.
+	// If more than maxRows events match, it returns ErrExportTooLarge without calling fn.
+	ExportEvents(ctx context.Context, params QueryParams, maxRows uint64, fn func(event any) error) error
+}
+
+// DistinctQueryable is an optional interface for queryable indexers that can enumerate the distinct values
+// of an event field, e.g. to fill the options of a filter.
+type DistinctQueryable interface {
+	// QueryDistinctValues returns the distinct non-null values of params.Field among the events of
+	// params.EventType in the block range, in ascending order, up to params.Limit values.
+	// Fields that are not columns of the event type fail with ErrUnknownField.
+	QueryDistinctValues(ctx context.Context, params DistinctParams) ([]any, error)
+}
+
+// EndpointProvider is an optional interface for indexers serving GET endpoints of their own below
+// /api/v1/indexers/{name}/, e.g. statistics specific to the indexed contracts.
+type EndpointProvider interface {
+	// Endpoints returns the endpoints of the indexer keyed by their path below /api/v1/indexers/{name}/,
+	// e.g. "stats/volume". Paths of the built-in endpoints are served by the built-in endpoints.
+	Endpoints() map[string]EndpointFunc
 }
+
+// EndpointFunc serves an endpoint of an EndpointProvider, returning the response encoded as JSON for
+// the query parameters of the request. Errors wrapping ErrInvalidParameter are client errors.
+type EndpointFunc func(ctx context.Context, query url.Values) (any, error)
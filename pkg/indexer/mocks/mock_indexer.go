@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	common "github.com/ethereum/go-ethereum/common"
 
 	mock "github.com/stretchr/testify/mock"
@@ -252,6 +254,52 @@ func (_c *Indexer_HandleReorg_Call) RunAndReturn(run func(uint64) error) *Indexe
 	return _c
 }
 
+// Ping provides a mock function with given fields: ctx
+func (_m *Indexer) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Indexer_Ping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ping'
+type Indexer_Ping_Call struct {
+	*mock.Call
+}
+
+// Ping is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Indexer_Expecter) Ping(ctx interface{}) *Indexer_Ping_Call {
+	return &Indexer_Ping_Call{Call: _e.mock.On("Ping", ctx)}
+}
+
+func (_c *Indexer_Ping_Call) Run(run func(ctx context.Context)) *Indexer_Ping_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Indexer_Ping_Call) Return(_a0 error) *Indexer_Ping_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Indexer_Ping_Call) RunAndReturn(run func(context.Context) error) *Indexer_Ping_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StartBlock provides a mock function with no fields
 func (_m *Indexer) StartBlock() uint64 {
 	ret := _m.Called()
@@ -22,6 +22,53 @@ func (_m *Queryable) EXPECT() *Queryable_Expecter {
 	return &Queryable_Expecter{mock: &_m.Mock}
 }
 
+// GetEventSchema provides a mock function with no fields
+func (_m *Queryable) GetEventSchema() []indexer.EventSchema {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEventSchema")
+	}
+
+	var r0 []indexer.EventSchema
+	if rf, ok := ret.Get(0).(func() []indexer.EventSchema); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]indexer.EventSchema)
+		}
+	}
+
+	return r0
+}
+
+// Queryable_GetEventSchema_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEventSchema'
+type Queryable_GetEventSchema_Call struct {
+	*mock.Call
+}
+
+// GetEventSchema is a helper method to define mock.On call
+func (_e *Queryable_Expecter) GetEventSchema() *Queryable_GetEventSchema_Call {
+	return &Queryable_GetEventSchema_Call{Call: _e.mock.On("GetEventSchema")}
+}
+
+func (_c *Queryable_GetEventSchema_Call) Run(run func()) *Queryable_GetEventSchema_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Queryable_GetEventSchema_Call) Return(_a0 []indexer.EventSchema) *Queryable_GetEventSchema_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queryable_GetEventSchema_Call) RunAndReturn(run func() []indexer.EventSchema) *Queryable_GetEventSchema_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetEventTypes provides a mock function with no fields
 func (_m *Queryable) GetEventTypes() []string {
 	ret := _m.Called()
@@ -306,6 +353,124 @@ func (_c *Queryable_QueryEventsTimeseries_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// QueryFirstEvent provides a mock function with given fields: ctx, eventType
+func (_m *Queryable) QueryFirstEvent(ctx context.Context, eventType string) (interface{}, error) {
+	ret := _m.Called(ctx, eventType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryFirstEvent")
+	}
+
+	var r0 interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (interface{}, error)); ok {
+		return rf(ctx, eventType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) interface{}); ok {
+		r0 = rf(ctx, eventType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, eventType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Queryable_QueryFirstEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryFirstEvent'
+type Queryable_QueryFirstEvent_Call struct {
+	*mock.Call
+}
+
+// QueryFirstEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventType string
+func (_e *Queryable_Expecter) QueryFirstEvent(ctx interface{}, eventType interface{}) *Queryable_QueryFirstEvent_Call {
+	return &Queryable_QueryFirstEvent_Call{Call: _e.mock.On("QueryFirstEvent", ctx, eventType)}
+}
+
+func (_c *Queryable_QueryFirstEvent_Call) Run(run func(ctx context.Context, eventType string)) *Queryable_QueryFirstEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Queryable_QueryFirstEvent_Call) Return(_a0 interface{}, _a1 error) *Queryable_QueryFirstEvent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Queryable_QueryFirstEvent_Call) RunAndReturn(run func(context.Context, string) (interface{}, error)) *Queryable_QueryFirstEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryLastEvent provides a mock function with given fields: ctx, eventType
+func (_m *Queryable) QueryLastEvent(ctx context.Context, eventType string) (interface{}, error) {
+	ret := _m.Called(ctx, eventType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryLastEvent")
+	}
+
+	var r0 interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (interface{}, error)); ok {
+		return rf(ctx, eventType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) interface{}); ok {
+		r0 = rf(ctx, eventType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, eventType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Queryable_QueryLastEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryLastEvent'
+type Queryable_QueryLastEvent_Call struct {
+	*mock.Call
+}
+
+// QueryLastEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventType string
+func (_e *Queryable_Expecter) QueryLastEvent(ctx interface{}, eventType interface{}) *Queryable_QueryLastEvent_Call {
+	return &Queryable_QueryLastEvent_Call{Call: _e.mock.On("QueryLastEvent", ctx, eventType)}
+}
+
+func (_c *Queryable_QueryLastEvent_Call) Run(run func(ctx context.Context, eventType string)) *Queryable_QueryLastEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Queryable_QueryLastEvent_Call) Return(_a0 interface{}, _a1 error) *Queryable_QueryLastEvent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Queryable_QueryLastEvent_Call) RunAndReturn(run func(context.Context, string) (interface{}, error)) *Queryable_QueryLastEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewQueryable creates a new instance of Queryable. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewQueryable(t interface {
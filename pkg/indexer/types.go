@@ -1,5 +1,58 @@
 package indexer
 
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrTimestampFilterUnsupported is returned when events are filtered by timestamp,
+// but the queried event type does not record the timestamp of its block.
+var ErrTimestampFilterUnsupported = errors.New("timestamp filter not supported")
+
+// ErrTopicFilterUnsupported is returned when events are filtered by a topic that the queried
+// event type does not have, or whose value is not stored, e.g. the hash of an indexed string.
+var ErrTopicFilterUnsupported = errors.New("topic filter not supported")
+
+// ErrTxHashFilterUnsupported is returned when events are filtered by transaction hash,
+// but the queried event type does not record the hash of its transaction.
+var ErrTxHashFilterUnsupported = errors.New("transaction hash filter not supported")
+
+// ErrUnknownField is returned when events are projected to a field that the queried event type does not have.
+var ErrUnknownField = errors.New("unknown field")
+
+// ErrExportTooLarge is returned when more events match an export than the configured maximum.
+var ErrExportTooLarge = errors.New("export too large")
+
+// ErrInvalidAggregation is returned when events are aggregated with an unknown function,
+// or over a field that is not a numeric column of the queried event type.
+var ErrInvalidAggregation = errors.New("invalid aggregation")
+
+// ErrOffsetTooLarge is returned when events are queried at an offset larger than the configured maximum,
+// as SQLite reads every skipped event. Deeper pages are read with a cursor instead.
+var ErrOffsetTooLarge = errors.New("offset too large")
+
+// ErrInvalidParameter is returned by the endpoints of an EndpointProvider for invalid query parameters.
+var ErrInvalidParameter = errors.New("invalid parameter")
+
+// Aggregation functions of an Aggregation.
+const (
+	AggregationSum   = "sum"
+	AggregationCount = "count"
+	AggregationAvg   = "avg"
+	AggregationMin   = "min"
+	AggregationMax   = "max"
+)
+
+// Aggregation computes a single value over the events matching a query instead of returning them.
+type Aggregation struct {
+	// Field is the numeric column aggregated. It may be empty for count, which then counts the events
+	Field string
+
+	// Function is one of sum, count, avg, min or max
+	Function string
+}
+
 // QueryParams represents common query parameters for event retrieval.
 type QueryParams struct {
 	// Event type to query (e.g., "Transfer", "Approval")
@@ -9,16 +62,91 @@ type QueryParams struct {
 	Limit  int
 	Offset int
 
+	// After enables keyset pagination: only events after the cursor in the sort order are
+	// returned and Offset is ignored. Events are then ordered by block number, log index and id
+	After *EventCursor
+
+	// Cursor is the opaque form of After, as returned to API clients by EncodeCursor.
+	// When set, it is decoded by QueryEvents and takes precedence over After
+	Cursor *string
+
 	// Block range filtering
 	FromBlock *uint64
 	ToBlock   *uint64
 
+	// Block timestamp filtering, in Unix seconds. Only supported for events with a timestamp column
+	FromTimestamp *uint64
+	ToTimestamp   *uint64
+
 	// Address filtering
 	Address string
 
-	// Sorting
-	SortBy    string
+	// Topic filtering, by the values of the topics of the event logs. Topic0 is the event signature
+	// hash, Topic1 to Topic3 are the indexed parameters of the event in declaration order
+	Topic0 *common.Hash
+	Topic1 *common.Hash
+	Topic2 *common.Hash
+	Topic3 *common.Hash
+
+	// Transaction filtering. Only supported for events with a tx_hash column
+	TxHash *common.Hash
+
+	// Field projection, by column name. Events are returned as maps holding only these columns
+	// instead of as event structs. All columns are returned when empty
+	Fields []string
+
+	// DecodeData returns the events as maps, with the non-indexed parameters of the event ABI-decoded
+	// from the raw log data under a "decoded" key, and integers as decimal strings. Only indexers that
+	// provide the ABI of their events and keep the log data in a data column support it, others ignore it
+	DecodeData bool
+
+	// Sorting. Events are ordered by every column of SortBy in turn, all in the same SortOrder
+	SortBy    []string
 	SortOrder string // "asc" or "desc"
+
+	// Aggregation, when set, aggregates the matching events into a single result, returned as
+	// map[string]interface{}{"result": value} with a total of 1. Pagination, sorting and field
+	// projection are ignored
+	Aggregation *Aggregation
+}
+
+// sortColumns are the columns events can be sorted by. Every event type has them.
+var sortColumns = map[string]bool{
+	"block_number": true,
+	"tx_index":     true,
+	"log_index":    true,
+}
+
+// IsSortColumn reports whether events can be sorted by the given column.
+func IsSortColumn(column string) bool {
+	return sortColumns[column]
+}
+
+// EventCursor identifies the position of an event by its (block_number, log_index, id) key.
+// ID is the row id of the event, which orders the events stored for a single log, like the transfers
+// of an ERC-1155 TransferBatch. A cursor without an ID is positioned after all events of its log.
+type EventCursor struct {
+	BlockNumber uint64 `json:"block_number"`
+	LogIndex    uint   `json:"log_index"`
+	ID          int64  `json:"id,omitempty"`
+}
+
+// CoverageStat summarizes the block ranges the downloader has fetched logs for, for a single address.
+type CoverageStat struct {
+	// Address is the contract address, in checksum hex
+	Address string
+
+	// TotalCoveredBlocks is the number of distinct blocks covered
+	TotalCoveredBlocks uint64
+
+	// EarliestBlock and LatestBlock are the first and last covered blocks
+	EarliestBlock uint64
+	LatestBlock   uint64
+}
+
+// Topics returns the topic filters of the query, indexed by topic position. Unset topics are nil.
+func (qp QueryParams) Topics() [4]*common.Hash {
+	return [4]*common.Hash{qp.Topic0, qp.Topic1, qp.Topic2, qp.Topic3}
 }
 
 func NewDefaultQueryParams() *QueryParams {
@@ -42,6 +170,25 @@ type TimeseriesParams struct {
 	EventType string
 }
 
+// DistinctParams represents the parameters of a query for the distinct values of an event field.
+type DistinctParams struct {
+	// EventType is the event type whose values are enumerated
+	EventType string
+
+	// Field is the column whose distinct values are returned
+	Field string
+
+	// Block range filtering
+	FromBlock *uint64
+	ToBlock   *uint64
+
+	// Prefix, when set, only matches values starting with it, ignoring ASCII letter case
+	Prefix string
+
+	// Limit is the maximum number of values returned
+	Limit int
+}
+
 // StatsResponse represents indexer statistics.
 // @Description Statistics and status information for an indexer
 type StatsResponse struct {
@@ -61,6 +208,22 @@ type TimeseriesDataPoint struct {
 	MaxBlock  uint64 `json:"max_block" example:"19510000" description:"Maximum block number in period"`
 }
 
+// EventSchema describes the fields of an indexed event.
+// @Description Schema of an indexed event
+type EventSchema struct {
+	Name   string             `json:"name" example:"Transfer" description:"Event name"`
+	Fields []EventFieldSchema `json:"fields" description:"Event parameters in declaration order"`
+}
+
+// EventFieldSchema describes a single event parameter.
+// @Description Schema of an event parameter
+type EventFieldSchema struct {
+	Name    string `json:"name" example:"from" description:"Parameter name"`
+	Type    string `json:"type" example:"address" description:"Solidity type"`
+	Indexed bool   `json:"indexed" example:"true" description:"Whether the parameter is indexed (a log topic)"`
+	Column  string `json:"column,omitempty" example:"from_address" description:"Database column of the parameter"`
+}
+
 // MetricsResponse represents performance and processing metrics.
 // @Description Performance metrics for an indexer
 type MetricsResponse struct {
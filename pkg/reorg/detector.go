@@ -2,10 +2,25 @@ package reorg
 
 import (
 	"context"
+	"time"
 
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
+// ReorgEvent is a reorg recorded by the detector when it was detected.
+type ReorgEvent struct {
+	// ID is the sequence number of the reorg in the detector's database.
+	ID uint64 `json:"id"`
+	// DetectedAt is when the reorg was detected.
+	DetectedAt time.Time `json:"detected_at"`
+	// FirstReorgBlock is the first block that was replaced by the reorg.
+	FirstReorgBlock uint64 `json:"first_reorg_block"`
+	// Depth is the number of blocks the detector saw replaced.
+	Depth uint64 `json:"depth"`
+	// Details describes the mismatch the reorg was detected by.
+	Details string `json:"details"`
+}
+
 // Detector detects blockchain reorganizations by tracking block hashes.
 type Detector interface {
 	// VerifyAndRecordBlocks checks for reorgs and records blocks for the given range.
@@ -18,6 +33,14 @@ type Detector interface {
 	// Returns ErrReorgDetected if a reorg is detected.
 	VerifyAndRecordBlocks(ctx context.Context, logs []types.Log, fromBlock, toBlock uint64) ([]*types.Header, error)
 
+	// HandleReorg removes the recorded blocks at or after fromBlock, so the reorged range
+	// is verified against the new chain when it is fetched again.
+	HandleReorg(ctx context.Context, fromBlock uint64) error
+
+	// GetReorgHistory returns the recorded reorgs whose first reorged block is within the
+	// inclusive block range, oldest first.
+	GetReorgHistory(ctx context.Context, fromBlock, toBlock uint64) ([]ReorgEvent, error)
+
 	// Close closes the detector and releases any resources.
 	Close() error
 }
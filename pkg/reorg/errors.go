@@ -1,6 +1,13 @@
 package reorg
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrReorgDepthExceeded is returned when a reorg is deeper than the max reorg depth or the limit of automatic recovery.
+// The downloader halts instead of re-indexing the reorged blocks, so that operators can review the reorg.
+var ErrReorgDepthExceeded = errors.New("reorg depth exceeded")
 
 // ReorgDetectedError is returned when a blockchain reorganization is detected.
 type ReorgDetectedError struct {
@@ -2,8 +2,10 @@ package rpc
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -33,4 +35,24 @@ type EthClient interface {
 
 	// BatchGetBlockHeaders retrieves headers for multiple block numbers in a single batch call.
 	BatchGetBlockHeaders(ctx context.Context, blockNums []uint64) ([]*types.Header, error)
+
+	// GetContractCode retrieves the code of the contract at the given address as of the given block.
+	// The code is empty if no contract is deployed at the address. A nil block number reads the latest block.
+	GetContractCode(ctx context.Context, address common.Address, blockNum *big.Int) ([]byte, error)
+}
+
+// PendingClient defines the RPC operations used to preview the events of pending transactions.
+type PendingClient interface {
+	// SubscribePendingTransactions subscribes to transactions entering the node's mempool.
+	SubscribePendingTransactions(ctx context.Context, ch chan<- *types.Transaction) (ethereum.Subscription, error)
+
+	// SimulateTransaction executes the transaction from the given sender on top of the latest
+	// block without submitting it, and returns the logs it would emit.
+	SimulateTransaction(ctx context.Context, from common.Address, tx *types.Transaction) ([]types.Log, error)
+}
+
+// HeadClient defines the RPC operations used to be notified of new blocks as they are added to the chain.
+type HeadClient interface {
+	// SubscribeNewHeads subscribes to the headers of blocks added to the head of the chain.
+	SubscribeNewHeads(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
 }
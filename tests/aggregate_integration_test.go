@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/pkg/api"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/tests/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAggregate_Integration aggregates the transfers of two ERC-20 indexers through the API
+func TestAggregate_Integration(t *testing.T) {
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	usdt := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+	erc20Indexer := func(name string, token common.Address) config.IndexerConfig {
+		return config.IndexerConfig{
+			Name: name,
+			Type: "erc20",
+			Contracts: []config.ContractConfig{
+				{
+					Address: token.Hex(),
+					Events: []string{
+						"Transfer(address,address,uint256)",
+						"Approval(address,address,uint256)",
+					},
+				},
+			},
+		}
+	}
+
+	stack := helpers.NewTestStack(t, helpers.TestStackOptions{
+		Indexers: []config.IndexerConfig{erc20Indexer("USDC", usdc), erc20Indexer("USDT", usdt)},
+	})
+
+	first := stack.Advance([]types.Log{
+		erc20Transfer(usdc, alice, bob, big.NewInt(100)),
+		erc20Transfer(usdt, alice, bob, big.NewInt(40)),
+	})
+	stack.Advance([]types.Log{
+		erc20Transfer(usdc, bob, alice, big.NewInt(25)),
+		erc20Transfer(usdc, alice, bob, big.NewInt(75)),
+	})
+	last := stack.Advance([]types.Log{erc20Transfer(usdt, bob, alice, big.NewInt(10))})
+
+	// 2^53 + 1 is not representable as a floating point number, so it is only summed exactly with big.Int
+	wide := stack.Advance([]types.Log{erc20Transfer(usdt, bob, alice, big.NewInt(1<<53+1))})
+
+	aggregate := func(body string) (int, []byte) {
+		t.Helper()
+
+		resp, err := http.Post(stack.APIURL+"/api/v1/query/aggregate", "application/json",
+			bytes.NewBufferString(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var result bytes.Buffer
+		_, err = result.ReadFrom(resp.Body)
+		require.NoError(t, err)
+
+		return resp.StatusCode, result.Bytes()
+	}
+
+	tests := []struct {
+		name        string
+		aggregation string
+		blocks      string
+		total       string
+		byIndexer   map[string]string
+	}{
+		{
+			name:        "sum",
+			aggregation: "sum",
+			blocks:      fmt.Sprintf(`, "to_block": %d`, last),
+			total:       "250",
+			byIndexer:   map[string]string{"USDC": "200", "USDT": "50"},
+		},
+		{
+			name:        "sum beyond 2^53",
+			aggregation: "sum",
+			blocks:      fmt.Sprintf(`, "to_block": %d`, wide),
+			total:       "9007199254741243",
+			byIndexer:   map[string]string{"USDC": "200", "USDT": "9007199254741043"},
+		},
+		{
+			name:        "count",
+			aggregation: "count",
+			blocks:      fmt.Sprintf(`, "to_block": %d`, last),
+			total:       "5",
+			byIndexer:   map[string]string{"USDC": "3", "USDT": "2"},
+		},
+		{
+			name:        "avg",
+			aggregation: "avg",
+			blocks:      fmt.Sprintf(`, "to_block": %d`, last),
+			total:       "50",
+			byIndexer:   map[string]string{"USDC": "66.666666666666666667", "USDT": "25"},
+		},
+		{
+			name:        "max",
+			aggregation: "max",
+			blocks:      fmt.Sprintf(`, "from_block": %d, "to_block": %d`, first+1, last),
+			total:       "75",
+			byIndexer:   map[string]string{"USDC": "75", "USDT": "10"},
+		},
+		{
+			name:        "min",
+			aggregation: "min",
+			blocks:      fmt.Sprintf(`, "to_block": %d`, first),
+			total:       "40",
+			byIndexer:   map[string]string{"USDC": "100", "USDT": "40"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := aggregate(fmt.Sprintf(
+				`{"indexers": ["USDC", "USDT"], "event_type": "transfer", "aggregation": "%s", "field": "value"%s}`,
+				tt.aggregation, tt.blocks))
+			require.Equal(t, http.StatusOK, status, string(body))
+
+			var result api.AggregateResult
+			require.NoError(t, json.Unmarshal(body, &result))
+			require.Equal(t, []string{"USDC", "USDT"}, result.Indexers)
+			require.NotNil(t, result.Total)
+			require.Equal(t, tt.total, *result.Total)
+			require.Equal(t, tt.byIndexer, result.ByIndexer)
+		})
+	}
+
+	t.Run("field not in the schema", func(t *testing.T) {
+		status, body := aggregate(
+			`{"indexers": ["USDC", "USDT"], "event_type": "transfer", "aggregation": "sum", "field": "block_number"}`)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Contains(t, string(body), "unknown field 'block_number' of Transfer events")
+	})
+}
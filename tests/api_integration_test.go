@@ -134,7 +134,7 @@ func TestAPI_IntegrationWithERC20(t *testing.T) {
 			AllowedOrigins: []string{"*"},
 		},
 	}
-	rpcClient, err := rpc.NewClient(ctx, anvil.URL, nil)
+	rpcClient, err := rpc.NewClient(ctx, anvil.URL, nil, nil)
 	require.NoError(t, err)
 
 	apiServer := api.NewServer(apiConfig, coordinator, rpcClient, log)
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	_ "github.com/goran-ethernal/ChainIndexor/examples/indexers/erc20"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/tests/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+// erc20Transfer returns the log of an ERC-20 transfer of the given amount
+func erc20Transfer(token, from, to common.Address, amount *big.Int) types.Log {
+	return types.Log{
+		Address: token,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")),
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+		},
+		Data: common.BigToHash(amount).Bytes(),
+	}
+}
+
+// TestERC20_Integration indexes ERC-20 transfers and checks that the API returns their
+// uint256 values as decimal strings, decoded from the log data when the logs were indexed
+func TestERC20_Integration(t *testing.T) {
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+	// A million tokens with 18 decimals, far beyond what an INTEGER column can hold
+	amount, ok := new(big.Int).SetString("1000000000000000000000000", 10)
+	require.True(t, ok)
+
+	stack := helpers.NewTestStack(t, helpers.TestStackOptions{
+		Indexers: []config.IndexerConfig{
+			{
+				Name: "StackERC20Indexer",
+				Type: "erc20",
+				Contracts: []config.ContractConfig{
+					{
+						Address: token.Hex(),
+						Events:  []string{"Transfer(address,address,uint256)", "Approval(address,address,uint256)"},
+					},
+				},
+			},
+		},
+	})
+
+	stack.Advance([]types.Log{
+		erc20Transfer(token, alice, bob, amount),
+		erc20Transfer(token, bob, alice, big.NewInt(1)),
+	})
+
+	resp, err := http.Get(fmt.Sprintf(
+		"%s/api/v1/indexers/StackERC20Indexer/events?event_type=transfer&sort_order=asc", stack.APIURL))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Events []map[string]any `json:"events"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Events, 2)
+
+	require.Equal(t, "1000000000000000000000000", result.Events[0]["Value"])
+	require.Equal(t, strings.ToLower(alice.Hex()), result.Events[0]["From"])
+	require.Equal(t, strings.ToLower(bob.Hex()), result.Events[0]["To"])
+	require.Equal(t, "1", result.Events[1]["Value"])
+}
@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	_ "github.com/goran-ethernal/ChainIndexor/examples/indexers/erc721"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/tests/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+// mockERC721 emits the logs of testdata/TestERC721.sol without deploying it
+type mockERC721 struct {
+	address common.Address
+}
+
+func (c mockERC721) transfer(from, to common.Address, tokenID *big.Int) types.Log {
+	return types.Log{
+		Address: c.address,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")),
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+			common.BigToHash(tokenID),
+		},
+	}
+}
+
+func (c mockERC721) approval(owner, approved common.Address, tokenID *big.Int) types.Log {
+	return types.Log{
+		Address: c.address,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("Approval(address,address,uint256)")),
+			common.BytesToHash(owner.Bytes()),
+			common.BytesToHash(approved.Bytes()),
+			common.BigToHash(tokenID),
+		},
+	}
+}
+
+func (c mockERC721) approvalForAll(owner, operator common.Address, approved bool) types.Log {
+	data := make([]byte, 32)
+	if approved {
+		data[31] = 1
+	}
+
+	return types.Log{
+		Address: c.address,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("ApprovalForAll(address,address,bool)")),
+			common.BytesToHash(owner.Bytes()),
+			common.BytesToHash(operator.Bytes()),
+		},
+		Data: data,
+	}
+}
+
+// TestERC721_Integration indexes the events of a mock ERC-721 contract and queries them through the API
+func TestERC721_Integration(t *testing.T) {
+	token := mockERC721{address: common.HexToAddress("0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512")}
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	operator := common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+
+	// Token ids use the full uint256 range, far beyond what an INTEGER column can hold
+	smallTokenID := big.NewInt(1)
+	largeTokenID, ok := new(big.Int).SetString(
+		"115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	require.True(t, ok)
+
+	stack := helpers.NewTestStack(t, helpers.TestStackOptions{
+		Indexers: []config.IndexerConfig{
+			{
+				Name: "StackERC721Indexer",
+				Type: "erc721",
+				Contracts: []config.ContractConfig{
+					{
+						Address: token.address.Hex(),
+						Events: []string{
+							"Transfer(address,address,uint256)",
+							"Approval(address,address,uint256)",
+							"ApprovalForAll(address,address,bool)",
+						},
+					},
+				},
+			},
+		},
+	})
+
+	stack.Advance([]types.Log{
+		token.transfer(common.Address{}, alice, smallTokenID),
+		token.transfer(common.Address{}, alice, largeTokenID),
+	})
+	stack.Advance([]types.Log{
+		token.approval(alice, bob, largeTokenID),
+		token.approvalForAll(alice, operator, true),
+	})
+	stack.Advance([]types.Log{
+		token.transfer(alice, bob, largeTokenID),
+		token.approvalForAll(alice, operator, false),
+	})
+
+	getEvents := func(query string) []map[string]any {
+		t.Helper()
+
+		resp, err := http.Get(fmt.Sprintf("%s/api/v1/indexers/StackERC721Indexer/events?%s", stack.APIURL, query))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Events []map[string]any `json:"events"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+		return result.Events
+	}
+
+	t.Run("transfers", func(t *testing.T) {
+		events := getEvents("event_type=transfer&sort_order=asc")
+		require.Len(t, events, 3)
+
+		require.Equal(t, smallTokenID.String(), events[0]["Tokenid"])
+		require.Equal(t, largeTokenID.String(), events[1]["Tokenid"])
+		require.Equal(t, largeTokenID.String(), events[2]["Tokenid"])
+		require.Equal(t, strings.ToLower(bob.Hex()), events[2]["To"])
+	})
+
+	t.Run("transfers filtered by address", func(t *testing.T) {
+		// The address filter matches both the sender and the recipient
+		events := getEvents("event_type=transfer&sort_order=asc&address=" + alice.Hex())
+		require.Len(t, events, 3)
+
+		events = getEvents("event_type=transfer&address=" + bob.Hex())
+		require.Len(t, events, 1)
+		require.Equal(t, strings.ToLower(alice.Hex()), events[0]["From"])
+		require.Equal(t, strings.ToLower(bob.Hex()), events[0]["To"])
+		require.Equal(t, largeTokenID.String(), events[0]["Tokenid"])
+
+		events = getEvents("event_type=transfer&address=" + operator.Hex())
+		require.Empty(t, events)
+	})
+
+	t.Run("approvals", func(t *testing.T) {
+		events := getEvents("event_type=approval")
+		require.Len(t, events, 1)
+
+		require.Equal(t, strings.ToLower(alice.Hex()), events[0]["Owner"])
+		require.Equal(t, strings.ToLower(bob.Hex()), events[0]["Approved"])
+		require.Equal(t, largeTokenID.String(), events[0]["Tokenid"])
+	})
+
+	t.Run("approvals for all filtered by operator", func(t *testing.T) {
+		events := getEvents("event_type=approvalforall&sort_order=asc&address=" + operator.Hex())
+		require.Len(t, events, 2)
+
+		require.Equal(t, strings.ToLower(operator.Hex()), events[0]["Operator"])
+		require.Equal(t, true, events[0]["Approved"])
+		require.Equal(t, false, events[1]["Approved"])
+	})
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/tests/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEventsAggregation_Integration aggregates the transfers of an ERC-20 indexer through the events endpoint
+func TestEventsAggregation_Integration(t *testing.T) {
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+	stack := helpers.NewTestStack(t, helpers.TestStackOptions{
+		Indexers: []config.IndexerConfig{
+			{
+				Name: "erc20",
+				Type: "erc20",
+				Contracts: []config.ContractConfig{
+					{
+						Address: token.Hex(),
+						Events: []string{
+							"Transfer(address,address,uint256)",
+							"Approval(address,address,uint256)",
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// Amounts in wei, wider than 64 bits, as uint256 values are stored as decimal text
+	ether := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	amount := func(tenths int64) *big.Int {
+		return new(big.Int).Div(new(big.Int).Mul(big.NewInt(tenths), ether), big.NewInt(10))
+	}
+
+	first := stack.Advance([]types.Log{
+		erc20Transfer(token, alice, bob, amount(15)),
+		erc20Transfer(token, alice, bob, amount(25)),
+	})
+	second := stack.Advance([]types.Log{erc20Transfer(token, bob, alice, amount(200))})
+	// One wei more than 0.5 ether, which is not representable as a floating point number
+	stack.Advance([]types.Log{erc20Transfer(token, bob, alice, new(big.Int).Add(amount(5), big.NewInt(1)))})
+
+	aggregate := func(query string) (int, map[string]any) {
+		t.Helper()
+
+		resp, err := http.Get(stack.APIURL + "/api/v1/indexers/erc20/events?event_type=Transfer&" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+		return resp.StatusCode, body
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		result any
+	}{
+		{
+			name:   "sum",
+			query:  "aggregate_fn=sum&aggregate_field=value",
+			result: "24500000000000000001",
+		},
+		{
+			name:   "sum over a block range",
+			query:  fmt.Sprintf("aggregate_fn=sum&aggregate_field=value&from_block=%d&to_block=%d", first, second),
+			result: "24000000000000000000",
+		},
+		{
+			name:   "sum of no events",
+			query:  fmt.Sprintf("aggregate_fn=sum&aggregate_field=value&from_block=%d", second+10),
+			result: "0",
+		},
+		{
+			name:   "avg",
+			query:  "aggregate_fn=avg&aggregate_field=value",
+			result: "6125000000000000000.25",
+		},
+		{
+			name:   "min",
+			query:  "aggregate_fn=min&aggregate_field=value",
+			result: "500000000000000001",
+		},
+		{
+			name:   "max",
+			query:  fmt.Sprintf("aggregate_fn=max&aggregate_field=value&to_block=%d", first),
+			result: "2500000000000000000",
+		},
+		{
+			name:   "count",
+			query:  "aggregate_fn=count",
+			result: 4.0,
+		},
+		{
+			name:   "avg of no events",
+			query:  fmt.Sprintf("aggregate_fn=avg&aggregate_field=value&from_block=%d", second+10),
+			result: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := aggregate(tt.query)
+			require.Equal(t, http.StatusOK, status, body)
+			require.Equal(t, map[string]any{"result": tt.result}, body)
+		})
+	}
+
+	t.Run("non-numeric field", func(t *testing.T) {
+		status, body := aggregate("aggregate_fn=sum&aggregate_field=from_address")
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Contains(t, body["message"], "from_address is not a numeric field of Transfer events")
+	})
+}
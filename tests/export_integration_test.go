@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/tests/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExport_Integration indexes a known set of ERC-20 transfers and checks that the export endpoint
+// returns all of them, in block and log index order, as parseable CSV and NDJSON files
+func TestExport_Integration(t *testing.T) {
+	const (
+		blocks            = 20
+		transfersPerBlock = 15
+	)
+
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+	stack := helpers.NewTestStack(t, helpers.TestStackOptions{
+		Indexers: []config.IndexerConfig{
+			{
+				Name: "ExportERC20Indexer",
+				Type: "erc20",
+				Contracts: []config.ContractConfig{
+					{
+						Address: token.Hex(),
+						Events:  []string{"Transfer(address,address,uint256)", "Approval(address,address,uint256)"},
+					},
+				},
+			},
+		},
+	})
+
+	// The transfer values count up, so the expected order of the export is known
+	var (
+		expected  []string
+		lastBlock uint64
+	)
+	for range blocks {
+		logs := make([]types.Log, transfersPerBlock)
+		for i := range logs {
+			value := int64(len(expected) + 1)
+			logs[i] = erc20Transfer(token, alice, bob, big.NewInt(value))
+			expected = append(expected, fmt.Sprint(value))
+		}
+		lastBlock = stack.Advance(logs)
+	}
+
+	export := func(format string) *http.Response {
+		t.Helper()
+
+		resp, err := http.Get(fmt.Sprintf("%s/api/v1/indexers/ExportERC20Indexer/export?event_type=transfer&format=%s",
+			stack.APIURL, format))
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		return resp
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		resp := export("csv")
+		require.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+		require.Equal(t, `attachment; filename="ExportERC20Indexer_transfer_0_latest.csv"`,
+			resp.Header.Get("Content-Disposition"))
+
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, len(expected)+1)
+
+		header := records[0]
+		require.Equal(t, []string{"ID", "BlockNumber", "BlockHash", "TxHash", "TxIndex", "LogIndex", "From", "To", "Value"},
+			header)
+
+		for i, record := range records[1:] {
+			require.Equal(t, expected[i], record[8])
+			require.Equal(t, strings.ToLower(alice.Hex()), record[6])
+			require.Equal(t, strings.ToLower(bob.Hex()), record[7])
+		}
+		require.Equal(t, fmt.Sprint(lastBlock), records[len(records)-1][1])
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		resp := export("ndjson")
+		require.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+		var values []string
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			var event map[string]any
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+
+			require.Equal(t, strings.ToLower(alice.Hex()), event["From"])
+			value, ok := event["Value"].(string)
+			require.True(t, ok)
+			values = append(values, value)
+		}
+		require.NoError(t, scanner.Err())
+		require.Equal(t, expected, values)
+	})
+
+	t.Run("block range", func(t *testing.T) {
+		resp, err := http.Get(fmt.Sprintf(
+			"%s/api/v1/indexers/ExportERC20Indexer/export?event_type=transfer&format=csv&from_block=%d&to_block=%d",
+			stack.APIURL, lastBlock, lastBlock))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, fmt.Sprintf(`attachment; filename="ExportERC20Indexer_transfer_%d_%d.csv"`, lastBlock, lastBlock),
+			resp.Header.Get("Content-Disposition"))
+
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, transfersPerBlock+1)
+		require.Equal(t, expected[len(expected)-1], records[len(records)-1][8])
+	})
+}
@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/indexer"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	pkgindexer "github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/goran-ethernal/ChainIndexor/tests/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	pairCreatedTopic = crypto.Keccak256Hash([]byte("PairCreated(address,address,address,uint256)"))
+	swapTopic        = crypto.Keccak256Hash([]byte("Swap(address,uint256,uint256,uint256,uint256,address)"))
+)
+
+// eventTestIndexer stores the address, block and log index of every log it handles in a table.
+type eventTestIndexer struct {
+	cfg   config.IndexerConfig
+	db    *sql.DB
+	table string
+}
+
+func newEventTestIndexer(cfg config.IndexerConfig, table string, columns string) (*eventTestIndexer, error) {
+	database, err := db.NewSQLiteDBFromConfig(cfg.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := database.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		block_number INTEGER NOT NULL,
+		log_index INTEGER NOT NULL,
+		address TEXT NOT NULL%s
+	)`, table, columns)); err != nil {
+		return nil, err
+	}
+
+	return &eventTestIndexer{cfg: cfg, db: database, table: table}, nil
+}
+
+func (e *eventTestIndexer) HandleLogs(logs []types.Log) error {
+	for _, log := range logs {
+		if _, err := e.db.Exec(
+			fmt.Sprintf("INSERT INTO %s (block_number, log_index, address) VALUES (?, ?, ?)", e.table),
+			log.BlockNumber, log.Index, log.Address.Hex(),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *eventTestIndexer) HandleReorg(blockNum uint64) error {
+	_, err := e.db.Exec(fmt.Sprintf("DELETE FROM %s WHERE block_number >= ?", e.table), blockNum)
+	return err
+}
+
+func (e *eventTestIndexer) EventsToIndex() map[common.Address]map[common.Hash]struct{} {
+	eventsToIndex := make(map[common.Address]map[common.Hash]struct{})
+	for _, contract := range e.cfg.Contracts {
+		topics := make(map[common.Hash]struct{})
+		for _, event := range contract.Events {
+			topics[crypto.Keccak256Hash([]byte(event))] = struct{}{}
+		}
+		eventsToIndex[common.HexToAddress(contract.Address)] = topics
+	}
+
+	return eventsToIndex
+}
+
+func (e *eventTestIndexer) StartBlock() uint64             { return e.cfg.StartBlock }
+func (e *eventTestIndexer) GetType() string                { return e.cfg.Type }
+func (e *eventTestIndexer) GetName() string                { return e.cfg.Name }
+func (e *eventTestIndexer) Ping(ctx context.Context) error { return e.db.PingContext(ctx) }
+func (e *eventTestIndexer) Close() error                   { return e.db.Close() }
+
+// pairFactoryIndexer is an ordinary indexer of the PairCreated events of a Uniswap V2 factory.
+type pairFactoryIndexer struct {
+	*eventTestIndexer
+}
+
+// HandleLogs stores the pair address, the first word of the event data, of every PairCreated event.
+func (f *pairFactoryIndexer) HandleLogs(logs []types.Log) error {
+	for _, log := range logs {
+		if _, err := f.db.Exec(
+			"INSERT INTO pair_created (block_number, log_index, address, pair) VALUES (?, ?, ?, ?)",
+			log.BlockNumber, log.Index, log.Address.Hex(), common.BytesToAddress(log.Data[:32]).Hex(),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pairIndexer indexes the Swap events of the configured pairs and of the pairs the factory deploys.
+type pairIndexer struct {
+	*eventTestIndexer
+	*indexer.FactoryIndexer
+	factoryDB *sql.DB
+}
+
+func (p *pairIndexer) Close() error {
+	return errors.Join(p.eventTestIndexer.Close(), p.factoryDB.Close())
+}
+
+// TestFactory_Integration indexes the events of contracts discovered from the events of a factory contract
+func TestFactory_Integration(t *testing.T) {
+	factory := common.HexToAddress("0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f")
+	configuredPair := common.HexToAddress("0xA478c2975Ab1Ea89e8196811F51A7B7Ade33eB11")
+	pair1 := common.HexToAddress("0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc")
+	pair2 := common.HexToAddress("0x0d4a11d5EEaaC28EC3F61d100daF4d40471f1852")
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+	pairCreated := func(pair common.Address) types.Log {
+		return types.Log{
+			Address: factory,
+			Topics:  []common.Hash{pairCreatedTopic, common.BytesToHash(token.Bytes()), common.BytesToHash(pair.Bytes())},
+			Data:    append(common.LeftPadBytes(pair.Bytes(), 32), common.LeftPadBytes([]byte{1}, 32)...),
+		}
+	}
+	swap := func(pair common.Address) types.Log {
+		return types.Log{
+			Address: pair,
+			Topics:  []common.Hash{swapTopic, common.BytesToHash(token.Bytes()), common.BytesToHash(token.Bytes())},
+		}
+	}
+
+	factoryDB := config.DatabaseConfig{Path: path.Join(t.TempDir(), "factory.db")}
+	factoryDB.ApplyDefaults()
+
+	pkgindexer.Register("test-pair-factory", func(cfg config.IndexerConfig, _ *logger.Logger) (pkgindexer.Indexer, error) {
+		base, err := newEventTestIndexer(cfg, "pair_created", ", pair TEXT NOT NULL")
+		if err != nil {
+			return nil, err
+		}
+
+		return &pairFactoryIndexer{eventTestIndexer: base}, nil
+	})
+	pkgindexer.Register("test-pair", func(cfg config.IndexerConfig, _ *logger.Logger) (pkgindexer.Indexer, error) {
+		base, err := newEventTestIndexer(cfg, "swaps", "")
+		if err != nil {
+			return nil, err
+		}
+
+		// The pairs are read from the table of the factory indexer
+		database, err := db.NewSQLiteDBFromConfig(factoryDB)
+		if err != nil {
+			return nil, err
+		}
+
+		factoryIndexer, err := indexer.NewFactoryIndexer(database, "pair_created", "pair",
+			map[common.Hash]struct{}{swapTopic: {}})
+		if err != nil {
+			return nil, err
+		}
+
+		return &pairIndexer{eventTestIndexer: base, FactoryIndexer: factoryIndexer, factoryDB: database}, nil
+	})
+
+	stack := helpers.NewTestStack(t, helpers.TestStackOptions{
+		Indexers: []config.IndexerConfig{
+			{
+				Name: "PairFactory",
+				Type: "test-pair-factory",
+				DB:   factoryDB,
+				Contracts: []config.ContractConfig{
+					{Address: factory.Hex(), Events: []string{"PairCreated(address,address,address,uint256)"}},
+				},
+			},
+			{
+				// Pairs created before the factory was indexed are configured
+				Name: "Pairs",
+				Type: "test-pair",
+				Contracts: []config.ContractConfig{
+					{Address: configuredPair.Hex(), Events: []string{"Swap(address,uint256,uint256,uint256,uint256,address)"}},
+				},
+			},
+		},
+	})
+	pairs, ok := stack.Indexers[1].(*pairIndexer)
+	require.True(t, ok)
+
+	swaps := func() []string {
+		rows, err := pairs.db.Query("SELECT block_number, address FROM swaps ORDER BY block_number, log_index")
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var result []string
+		for rows.Next() {
+			var (
+				block   uint64
+				address string
+			)
+			require.NoError(t, rows.Scan(&block, &address))
+			result = append(result, fmt.Sprintf("%d:%s", block, address))
+		}
+		require.NoError(t, rows.Err())
+
+		return result
+	}
+
+	// Before the pair is created its swaps are not fetched
+	first := stack.Advance([]types.Log{swap(pair1), swap(configuredPair)})
+
+	// The swap in the block that created the pair is caught up with once the pair is discovered
+	created1 := stack.Advance([]types.Log{pairCreated(pair1), swap(pair1)})
+
+	// Several blocks indexed in one chunk, the second pair created in the middle of them
+	stack.Chain.Mine([]types.Log{swap(pair1)})
+	created2 := stack.Chain.Mine([]types.Log{pairCreated(pair2), swap(pair2)})
+	last := stack.Chain.Mine([]types.Log{swap(pair1), swap(pair2)})
+	stack.WaitForBlock(last)
+
+	expected := []string{
+		fmt.Sprintf("%d:%s", first, configuredPair.Hex()),
+		fmt.Sprintf("%d:%s", created1, pair1.Hex()),
+		fmt.Sprintf("%d:%s", created1+1, pair1.Hex()),
+		fmt.Sprintf("%d:%s", created2, pair2.Hex()),
+		fmt.Sprintf("%d:%s", last, pair1.Hex()),
+		fmt.Sprintf("%d:%s", last, pair2.Hex()),
+	}
+	require.Eventually(t, func() bool {
+		return len(swaps()) == len(expected)
+	}, 10*time.Second, 10*time.Millisecond, "swaps of the discovered pairs were not indexed")
+	require.Equal(t, expected, swaps())
+}
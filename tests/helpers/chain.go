@@ -0,0 +1,247 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goran-ethernal/ChainIndexor/pkg/rpc"
+)
+
+// Compile-time check to ensure MockChain implements rpc.EthClient interface.
+var _ rpc.EthClient = (*MockChain)(nil)
+
+// MockChain is an in-memory chain that serves blocks and logs through the rpc.EthClient interface.
+// Unless a finalized lag is set, every block is final as soon as it is mined, so the latest, safe
+// and finalized blocks are the same.
+type MockChain struct {
+	mu      sync.RWMutex
+	headers []*types.Header
+	logs    [][]types.Log
+
+	// finalizedLag is the number of blocks the finalized block is behind the latest block
+	finalizedLag uint64
+
+	// forks counts the reorgs, so the blocks mined after a reorg get hashes of their own
+	forks uint64
+}
+
+// NewMockChain creates a chain containing only the genesis block.
+func NewMockChain() *MockChain {
+	genesis := &types.Header{
+		Number:     big.NewInt(0),
+		Difficulty: big.NewInt(1),
+		GasLimit:   30_000_000,
+	}
+
+	return &MockChain{
+		headers: []*types.Header{genesis},
+		logs:    [][]types.Log{nil},
+	}
+}
+
+// Mine appends a block containing the given logs and returns its number.
+// Block number, block hash and log index are set on the logs, and logs without a
+// transaction hash get a unique one.
+func (c *MockChain) Mine(logs []types.Log) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	parent := c.headers[len(c.headers)-1]
+	blockNum := parent.Number.Uint64() + 1
+
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).SetUint64(blockNum),
+		Difficulty: big.NewInt(1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + 12, //nolint:mnd
+	}
+	if c.forks > 0 {
+		header.Extra = new(big.Int).SetUint64(c.forks).Bytes()
+	}
+
+	for _, log := range logs {
+		header.Bloom.Add(log.Address.Bytes())
+		for _, topic := range log.Topics {
+			header.Bloom.Add(topic.Bytes())
+		}
+	}
+
+	blockHash := header.Hash()
+	blockLogs := make([]types.Log, len(logs))
+	for i, log := range logs {
+		log.BlockNumber = blockNum
+		log.BlockHash = blockHash
+		log.Index = uint(i)
+		if log.TxHash == (common.Hash{}) {
+			log.TxHash = crypto.Keccak256Hash(blockHash.Bytes(), big.NewInt(int64(i)).Bytes())
+			log.TxIndex = uint(i)
+		}
+		blockLogs[i] = log
+	}
+
+	c.headers = append(c.headers, header)
+	c.logs = append(c.logs, blockLogs)
+
+	return blockNum
+}
+
+// SetFinalizedLag makes the finalized block lag blocks behind the latest block,
+// so the reorg detector keeps verifying the blocks in between.
+func (c *MockChain) SetFinalizedLag(lag uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.finalizedLag = lag
+}
+
+// Reorg removes the blocks from fromBlock on, so the blocks mined next replace them with different hashes.
+func (c *MockChain) Reorg(fromBlock uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if fromBlock == 0 || fromBlock >= uint64(len(c.headers)) {
+		return
+	}
+
+	c.headers = c.headers[:fromBlock]
+	c.logs = c.logs[:fromBlock]
+	c.forks++
+}
+
+// Head returns the number of the latest block.
+func (c *MockChain) Head() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return uint64(len(c.headers) - 1)
+}
+
+// Close implements rpc.EthClient.
+func (c *MockChain) Close() {}
+
+// GetLogs returns the logs matching the filter query.
+func (c *MockChain) GetLogs(_ context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	fromBlock, toBlock := uint64(0), uint64(len(c.headers)-1)
+	if query.FromBlock != nil {
+		fromBlock = query.FromBlock.Uint64()
+	}
+	if query.ToBlock != nil {
+		toBlock = min(toBlock, query.ToBlock.Uint64())
+	}
+
+	result := make([]types.Log, 0)
+	for blockNum := fromBlock; blockNum <= toBlock && blockNum < uint64(len(c.logs)); blockNum++ {
+		for _, log := range c.logs[blockNum] {
+			if matchesQuery(log, query) {
+				result = append(result, log)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// GetBlockHeader returns the header of the given block.
+func (c *MockChain) GetBlockHeader(_ context.Context, blockNum uint64) (*types.Header, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.headerLocked(blockNum)
+}
+
+// GetLatestBlockHeader returns the header of the latest block.
+func (c *MockChain) GetLatestBlockHeader(_ context.Context) (*types.Header, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.headers[len(c.headers)-1], nil
+}
+
+// GetFinalizedBlockHeader returns the header of the latest block, or of the block the finalized lag behind it.
+func (c *MockChain) GetFinalizedBlockHeader(_ context.Context) (*types.Header, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	head := uint64(len(c.headers) - 1)
+	return c.headers[head-min(head, c.finalizedLag)], nil
+}
+
+// GetSafeBlockHeader returns the header of the latest block.
+func (c *MockChain) GetSafeBlockHeader(ctx context.Context) (*types.Header, error) {
+	return c.GetLatestBlockHeader(ctx)
+}
+
+// BatchGetLogs returns the logs matching each of the filter queries.
+func (c *MockChain) BatchGetLogs(ctx context.Context, queries []ethereum.FilterQuery) ([][]types.Log, error) {
+	results := make([][]types.Log, len(queries))
+	for i, query := range queries {
+		logs, err := c.GetLogs(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = logs
+	}
+
+	return results, nil
+}
+
+// BatchGetBlockHeaders returns the headers of the given blocks.
+func (c *MockChain) BatchGetBlockHeaders(_ context.Context, blockNums []uint64) ([]*types.Header, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	headers := make([]*types.Header, len(blockNums))
+	for i, blockNum := range blockNums {
+		header, err := c.headerLocked(blockNum)
+		if err != nil {
+			return nil, err
+		}
+		headers[i] = header
+	}
+
+	return headers, nil
+}
+
+// GetContractCode returns placeholder code for every address, as if all contracts were deployed in the genesis block.
+func (c *MockChain) GetContractCode(_ context.Context, _ common.Address, _ *big.Int) ([]byte, error) {
+	return []byte{0x00}, nil
+}
+
+func (c *MockChain) headerLocked(blockNum uint64) (*types.Header, error) {
+	if blockNum >= uint64(len(c.headers)) {
+		return nil, fmt.Errorf("block %d not found", blockNum)
+	}
+
+	return c.headers[blockNum], nil
+}
+
+// matchesQuery reports whether the log matches the addresses and topics of the query,
+// following the eth_getLogs filter semantics.
+func matchesQuery(log types.Log, query ethereum.FilterQuery) bool {
+	if len(query.Addresses) > 0 && !slices.Contains(query.Addresses, log.Address) {
+		return false
+	}
+
+	if len(query.Topics) > len(log.Topics) {
+		return false
+	}
+
+	for i, topics := range query.Topics {
+		if len(topics) > 0 && !slices.Contains(topics, log.Topics[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,277 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/downloader"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/internal/metrics"
+	"github.com/goran-ethernal/ChainIndexor/internal/migrations"
+	"github.com/goran-ethernal/ChainIndexor/internal/reorg"
+	"github.com/goran-ethernal/ChainIndexor/pkg/api"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// stackPollInterval is how often the downloader checks the mock chain for new blocks
+	stackPollInterval = 10 * time.Millisecond
+
+	// stackWaitTimeout bounds how long the stack waits for a block to be indexed or a server to start
+	stackWaitTimeout = 10 * time.Second
+
+	stackWaitTick = 10 * time.Millisecond
+)
+
+// TestStackOptions configures a TestStack.
+type TestStackOptions struct {
+	// Indexers are the indexers to run. The indexer types must be registered, e.g. by importing
+	// the package of a built-in indexer. Indexers without a database path get a temporary database
+	Indexers []config.IndexerConfig
+
+	// ChunkSize is the block range per eth_getLogs call, defaults to the downloader default
+	ChunkSize uint64
+
+	// Metrics starts a Prometheus metrics server next to the API server
+	Metrics bool
+
+	// WriteBatch batches the writes of the log store, they are stored right away when nil
+	WriteBatch *config.WriteBatchConfig
+}
+
+// TestStack is a complete in-process ChainIndexor stack for integration tests.
+// It runs the downloader against a MockChain, so tests do not need an Anvil node.
+type TestStack struct {
+	// Chain is the mock chain the downloader indexes
+	Chain *MockChain
+
+	// Downloader is the running downloader
+	Downloader *downloader.Downloader
+
+	// Indexers are the registered indexers, in the order of TestStackOptions.Indexers
+	Indexers []indexer.Indexer
+
+	// APIURL is the base URL of the running REST API server
+	APIURL string
+
+	// MetricsURL is the base URL of the metrics server, empty if metrics are disabled
+	MetricsURL string
+
+	// Config is the configuration the stack was started with
+	Config config.Config
+
+	t           *testing.T
+	syncManager *downloader.SyncManager
+
+	// mu guards Indexers, which a config reload appends to while the stack runs
+	mu sync.Mutex
+}
+
+// NewTestStack starts a downloader, indexers, an API server and optionally a metrics server
+// against a new MockChain. Everything is stopped when the test finishes.
+//
+// Databases are temporary files rather than in-memory SQLite databases: the components open
+// several connections, and each connection to an in-memory database would see its own copy.
+func NewTestStack(t *testing.T, opts TestStackOptions) *TestStack {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	cfg := config.Config{
+		Downloader: config.DownloaderConfig{
+			RPCURL:       "mock://chain",
+			ChunkSize:    opts.ChunkSize,
+			PollInterval: common.NewDuration(stackPollInterval),
+			DB:           config.DatabaseConfig{Path: path.Join(dir, "downloader.db")},
+			WriteBatch:   opts.WriteBatch,
+		},
+		Indexers: make([]config.IndexerConfig, len(opts.Indexers)),
+		API: &config.APIConfig{
+			Enabled:       true,
+			ListenAddress: fmt.Sprintf("127.0.0.1:%d", getFreePort(t)),
+		},
+		Logging: &config.LoggingConfig{DefaultLevel: "error"},
+	}
+
+	for i, idxCfg := range opts.Indexers {
+		if idxCfg.DB.Path == "" {
+			idxCfg.DB.Path = path.Join(dir, fmt.Sprintf("indexer_%d.db", i))
+		}
+		cfg.Indexers[i] = idxCfg
+	}
+
+	if opts.Metrics {
+		cfg.Metrics = &config.MetricsConfig{
+			Enabled:       true,
+			ListenAddress: fmt.Sprintf("127.0.0.1:%d", getFreePort(t)),
+		}
+	}
+
+	cfg.ApplyDefaults()
+	require.NoError(t, cfg.Validate())
+
+	log := logger.NewNopLogger()
+	chain := NewMockChain()
+
+	require.NoError(t, migrations.RunMigrations(cfg.Downloader.DB))
+
+	database, err := db.NewDBFromConfig(cfg.Downloader.DB)
+	require.NoError(t, err)
+
+	maintenance := &db.NoOpMaintenance{}
+
+	chainID := cfg.Downloader.ExpectedChainID
+	reorgDetector, err := reorg.NewReorgDetector(
+		chainID, database, chain, log, maintenance, cfg.Downloader.HeaderCacheSize)
+	require.NoError(t, err)
+
+	syncManager, err := downloader.NewSyncManager(database, log, maintenance)
+	require.NoError(t, err)
+
+	dl, err := downloader.New(chainID, cfg.Downloader, chain, reorgDetector, syncManager, maintenance, log)
+	require.NoError(t, err)
+
+	stack := &TestStack{
+		Chain:       chain,
+		Downloader:  dl,
+		Indexers:    make([]indexer.Indexer, 0, len(cfg.Indexers)),
+		APIURL:      "http://" + cfg.API.ListenAddress,
+		Config:      cfg,
+		t:           t,
+		syncManager: syncManager,
+	}
+
+	for _, idxCfg := range cfg.Indexers {
+		idx, err := indexer.Create(idxCfg.Type, idxCfg, log)
+		require.NoError(t, err)
+
+		dl.RegisterIndexer(idx)
+		stack.Indexers = append(stack.Indexers, idx)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	apiDone := make(chan error, 1)
+	apiServer := api.NewServer(cfg.API, dl.Coordinator(), chain, log)
+	apiServer.SetRetentionPreviewer(dl)
+	apiServer.SetLogStore(dl.LogStore())
+	apiServer.SetProgressSource(dl.ProgressBus())
+	go func() { apiDone <- apiServer.Start(ctx) }()
+
+	var metricsServer *metrics.Server
+	if cfg.Metrics != nil {
+		metricsServer = metrics.NewServer(cfg.Metrics)
+		require.NoError(t, metricsServer.Start(ctx))
+		stack.MetricsURL = "http://" + cfg.Metrics.ListenAddress
+	}
+
+	downloadDone := make(chan error, 1)
+	go func() { downloadDone <- dl.Download(ctx, cfg) }()
+
+	t.Cleanup(func() {
+		cancel()
+
+		if err := <-downloadDone; err != nil && !errors.Is(err, context.Canceled) {
+			t.Errorf("downloader failed: %v", err)
+		}
+		if err := <-apiDone; err != nil {
+			t.Errorf("API server failed: %v", err)
+		}
+		if metricsServer != nil {
+			if err := metricsServer.Stop(context.Background()); err != nil {
+				t.Errorf("failed to stop metrics server: %v", err)
+			}
+		}
+
+		stack.mu.Lock()
+		defer stack.mu.Unlock()
+
+		for _, idx := range stack.Indexers {
+			if closer, ok := idx.(interface{ Close() error }); ok {
+				_ = closer.Close()
+			}
+		}
+		_ = dl.Close()
+	})
+
+	stack.waitForServer(stack.APIURL + "/health")
+	if stack.MetricsURL != "" {
+		stack.waitForServer(stack.MetricsURL + "/health")
+	}
+
+	return stack
+}
+
+// AddIndexer creates an indexer and registers it with the running downloader, as a config reload does.
+// An indexer without a database path gets a temporary database.
+func (s *TestStack) AddIndexer(idxCfg config.IndexerConfig) error {
+	if idxCfg.DB.Path == "" {
+		idxCfg.DB.Path = path.Join(s.t.TempDir(), idxCfg.Name+".db")
+		idxCfg.DB.ApplyDefaults()
+	}
+
+	idx, err := indexer.Create(idxCfg.Type, idxCfg, logger.NewNopLogger())
+	if err != nil {
+		return err
+	}
+
+	s.Downloader.RegisterIndexer(idx)
+
+	s.mu.Lock()
+	s.Indexers = append(s.Indexers, idx)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// UpdateDownloaderConfig applies the chunk size and retention policy to the running downloader.
+func (s *TestStack) UpdateDownloaderConfig(cfg config.DownloaderConfig) {
+	s.Downloader.UpdateConfig(cfg)
+}
+
+// Advance mines a block containing the given logs on the mock chain and waits until
+// the downloader has indexed it. It returns the number of the new block.
+func (s *TestStack) Advance(logs []types.Log) uint64 {
+	s.t.Helper()
+
+	blockNum := s.Chain.Mine(logs)
+	s.WaitForBlock(blockNum)
+
+	return blockNum
+}
+
+// WaitForBlock waits until the downloader has indexed the given block.
+func (s *TestStack) WaitForBlock(blockNum uint64) {
+	s.t.Helper()
+
+	require.Eventually(s.t, func() bool {
+		lastIndexed, err := s.syncManager.GetLastIndexedBlock()
+		return err == nil && lastIndexed >= blockNum
+	}, stackWaitTimeout, stackWaitTick, "block %d was not indexed", blockNum)
+}
+
+// waitForServer waits until the HTTP server at url responds.
+func (s *TestStack) waitForServer(url string) {
+	s.t.Helper()
+
+	require.Eventually(s.t, func() bool {
+		resp, err := http.Get(url) //nolint:gosec,noctx
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+
+		return true
+	}, stackWaitTimeout, stackWaitTick, "server at %s did not start", url)
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	_ "github.com/goran-ethernal/ChainIndexor/examples/indexers/erc20"
+	iconfig "github.com/goran-ethernal/ChainIndexor/internal/config"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/tests/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReload_AddsIndexerOnSIGHUP adds an indexer to the config file of a running stack,
+// sends SIGHUP and checks that the new indexer catches up and indexes new blocks.
+func TestReload_AddsIndexerOnSIGHUP(t *testing.T) {
+	transferSig := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	tokenAddress := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	newTokenAddress := common.HexToAddress("0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512")
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+	transfer := func(token common.Address, value int64) types.Log {
+		return types.Log{
+			Address: token,
+			Topics:  []common.Hash{transferSig, common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes())},
+			Data:    common.LeftPadBytes(big.NewInt(value).Bytes(), 32),
+		}
+	}
+
+	stack := helpers.NewTestStack(t, helpers.TestStackOptions{
+		Indexers: []config.IndexerConfig{
+			{
+				Name: "ReloadERC20Indexer",
+				Type: "erc20",
+				Contracts: []config.ContractConfig{
+					{Address: tokenAddress.Hex(), Events: []string{"Transfer(address,address,uint256)"}},
+				},
+			},
+		},
+	})
+
+	configPath := path.Join(t.TempDir(), "config.json")
+	writeConfig := func(cfg config.Config) {
+		data, err := json.Marshal(cfg)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(configPath, data, 0o600))
+	}
+	writeConfig(stack.Config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
+	defer signal.Stop(reloadCh)
+
+	cfg := stack.Config
+	reloader := iconfig.NewConfigReloader(configPath, &cfg,
+		map[uint64]iconfig.ReloadTarget{0: stack}, logger.NewNopLogger())
+	go reloader.Run(ctx, reloadCh)
+
+	// A transfer of the new token before its indexer is configured
+	stack.Advance([]types.Log{transfer(tokenAddress, 100), transfer(newTokenAddress, 10)})
+
+	newCfg := stack.Config
+	newCfg.Indexers = append([]config.IndexerConfig(nil), stack.Config.Indexers...)
+	newCfg.Indexers = append(newCfg.Indexers, config.IndexerConfig{
+		Name: "ReloadedERC20Indexer",
+		Type: "erc20",
+		DB:   config.DatabaseConfig{Path: path.Join(t.TempDir(), "reloaded.db")},
+		Contracts: []config.ContractConfig{
+			{Address: newTokenAddress.Hex(), Events: []string{"Transfer(address,address,uint256)"}},
+		},
+	})
+	writeConfig(newCfg)
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	require.Eventually(t, func() bool {
+		return stack.Downloader.Coordinator().GetByName("ReloadedERC20Indexer") != nil
+	}, 10*time.Second, 10*time.Millisecond, "indexer was not added on SIGHUP")
+
+	stack.Advance([]types.Log{transfer(newTokenAddress, 20)})
+
+	// The new indexer catches up with the transfer before it was added, and indexes the new one
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(stack.APIURL + "/api/v1/indexers/ReloadedERC20Indexer/events?event_type=transfer")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+
+		var result struct {
+			Events []any `json:"events"`
+		}
+		if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&result) != nil {
+			return false
+		}
+
+		return len(result.Events) == 2
+	}, 10*time.Second, 50*time.Millisecond, "new indexer did not index the transfers")
+}
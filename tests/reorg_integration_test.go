@@ -2,18 +2,22 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"math/big"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/examples/indexers/erc20"
 	"github.com/goran-ethernal/ChainIndexor/internal/db"
 	"github.com/goran-ethernal/ChainIndexor/internal/logger"
 	"github.com/goran-ethernal/ChainIndexor/internal/reorg"
 	"github.com/goran-ethernal/ChainIndexor/internal/rpc"
 	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
 	pkgreorg "github.com/goran-ethernal/ChainIndexor/pkg/reorg"
 	"github.com/goran-ethernal/ChainIndexor/tests/helpers"
 	"github.com/goran-ethernal/ChainIndexor/tests/testdata"
@@ -36,7 +40,7 @@ func TestReorg_SimpleBlockReplacement(t *testing.T) {
 
 	// Setup RPC client (with no retries for faster tests)
 	retryConfig := config.RetryConfig{MaxAttempts: 1}
-	rpcClient, err := rpc.NewClient(ctx, anvil.URL, &retryConfig)
+	rpcClient, err := rpc.NewClient(ctx, anvil.URL, &retryConfig, nil)
 	require.NoError(t, err)
 	defer rpcClient.Close()
 
@@ -45,7 +49,7 @@ func TestReorg_SimpleBlockReplacement(t *testing.T) {
 	require.NoError(t, err)
 
 	// Create ReorgDetector
-	detector, err := reorg.NewReorgDetector(database, rpcClient, log, &db.NoOpMaintenance{})
+	detector, err := reorg.NewReorgDetector(0, database, rpcClient, log, &db.NoOpMaintenance{}, 0)
 	require.NoError(t, err)
 
 	// Deploy test contract
@@ -194,14 +198,14 @@ func TestReorg_DeepReorg(t *testing.T) {
 	ctx := context.Background()
 
 	retryConfig := config.RetryConfig{MaxAttempts: 1}
-	rpcClient, err := rpc.NewClient(ctx, anvil.URL, &retryConfig)
+	rpcClient, err := rpc.NewClient(ctx, anvil.URL, &retryConfig, nil)
 	require.NoError(t, err)
 	defer rpcClient.Close()
 
 	log, err := logger.NewLogger("info", false)
 	require.NoError(t, err)
 
-	detector, err := reorg.NewReorgDetector(database, rpcClient, log, &db.NoOpMaintenance{})
+	detector, err := reorg.NewReorgDetector(0, database, rpcClient, log, &db.NoOpMaintenance{}, 0)
 	require.NoError(t, err)
 
 	// Deploy test contract
@@ -281,14 +285,14 @@ func TestReorg_NoLogsOnReorgChain(t *testing.T) {
 	ctx := context.Background()
 
 	retryConfig := config.RetryConfig{MaxAttempts: 1}
-	rpcClient, err := rpc.NewClient(ctx, anvil.URL, &retryConfig)
+	rpcClient, err := rpc.NewClient(ctx, anvil.URL, &retryConfig, nil)
 	require.NoError(t, err)
 	defer rpcClient.Close()
 
 	log, err := logger.NewLogger("info", false)
 	require.NoError(t, err)
 
-	detector, err := reorg.NewReorgDetector(database, rpcClient, log, &db.NoOpMaintenance{})
+	detector, err := reorg.NewReorgDetector(0, database, rpcClient, log, &db.NoOpMaintenance{}, 0)
 	require.NoError(t, err)
 
 	// Deploy contract
@@ -356,14 +360,14 @@ func TestReorg_NewLogsOnReorgChain(t *testing.T) {
 	ctx := context.Background()
 
 	retryConfig := config.RetryConfig{MaxAttempts: 1}
-	rpcClient, err := rpc.NewClient(ctx, anvil.URL, &retryConfig)
+	rpcClient, err := rpc.NewClient(ctx, anvil.URL, &retryConfig, nil)
 	require.NoError(t, err)
 	defer rpcClient.Close()
 
 	log, err := logger.NewLogger("info", false)
 	require.NoError(t, err)
 
-	detector, err := reorg.NewReorgDetector(database, rpcClient, log, &db.NoOpMaintenance{})
+	detector, err := reorg.NewReorgDetector(0, database, rpcClient, log, &db.NoOpMaintenance{}, 0)
 	require.NoError(t, err)
 
 	// Deploy contract
@@ -440,14 +444,14 @@ func TestReorg_Chaos_RapidMultipleReorgs(t *testing.T) {
 	ctx := context.Background()
 
 	retryConfig := config.RetryConfig{MaxAttempts: 1}
-	rpcClient, err := rpc.NewClient(ctx, anvil.URL, &retryConfig)
+	rpcClient, err := rpc.NewClient(ctx, anvil.URL, &retryConfig, nil)
 	require.NoError(t, err)
 	defer rpcClient.Close()
 
 	log, err := logger.NewLogger("info", false)
 	require.NoError(t, err)
 
-	detector, err := reorg.NewReorgDetector(database, rpcClient, log, &db.NoOpMaintenance{})
+	detector, err := reorg.NewReorgDetector(0, database, rpcClient, log, &db.NoOpMaintenance{}, 0)
 	require.NoError(t, err)
 
 	// Deploy contract
@@ -574,3 +578,132 @@ func TestReorg_Chaos_RapidMultipleReorgs(t *testing.T) {
 	// If we got here, the detector handled rapid state changes gracefully
 	t.Logf("✓ Database integrity verified: no corruption after %d rapid reorgs", reorgCount)
 }
+
+// TestReorg_AutoRecovery replaces indexed blocks with Anvil's snapshot and revert while the downloader
+// is running, and checks that it recovers from the reorg and re-indexes the blocks of the new chain
+func TestReorg_AutoRecovery(t *testing.T) {
+	helpers.SkipIfAnvilNotAvailable(t)
+
+	anvil := helpers.StartAnvil(t)
+
+	initialSupply := new(big.Int).Mul(big.NewInt(1000000), big.NewInt(1e18))
+	tokenAddress, _, token, err := testdata.DeployTestERC20(anvil.Signer, anvil.Client, initialSupply)
+	require.NoError(t, err)
+	time.Sleep(2 * time.Second)
+
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+	client, err := rpc.NewClient(context.Background(), anvil.URL, &config.RetryConfig{MaxAttempts: 1}, nil)
+	require.NoError(t, err)
+	// Auto recovery is enabled by default
+	stack := newReorgTestStack(t, client, tokenAddress, 1)
+
+	forkPoint := anvil.GetBlockNumber(t)
+	snapshotID := anvil.CreateSnapshot(t)
+
+	transfer := func(amount int64) {
+		t.Helper()
+
+		_, err := token.Transfer(anvil.Signer, bob, big.NewInt(amount))
+		require.NoError(t, err)
+		time.Sleep(1 * time.Second)
+	}
+
+	// transfersIndexed reports whether the transfers after the fork point are the expected ones
+	transfersIndexed := func(expected ...string) func() bool {
+		return func() bool {
+			events, _, err := stack.indexer.QueryEvents(context.Background(), indexer.QueryParams{
+				EventType: "Transfer",
+				FromBlock: &forkPoint,
+				Limit:     100,
+				SortOrder: "asc",
+			})
+			if err != nil {
+				return false
+			}
+
+			transfers, ok := events.([]*erc20.Transfer)
+			if !ok {
+				return false
+			}
+
+			values := make([]string, 0, len(transfers))
+			for _, transfer := range transfers {
+				if transfer.BlockNumber > forkPoint {
+					values = append(values, transfer.Value)
+				}
+			}
+
+			return slices.Equal(expected, values)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- stack.downloader.Download(ctx, stack.cfg) }()
+
+	// The original chain, with a block on top so that the blocks of the transfers are final
+	transfer(100)
+	transfer(200)
+	anvil.Mine(t, 1)
+	require.Eventually(t, transfersIndexed("100", "200"), 30*time.Second, 100*time.Millisecond)
+
+	// The new chain replaces the indexed blocks and grows past them, so the downloader
+	// detects the reorg when it verifies the indexed blocks before fetching the next ones
+	anvil.RevertToForkPoint(t, snapshotID)
+	transfer(300)
+	transfer(400)
+	anvil.Mine(t, 2)
+	require.Eventually(t, transfersIndexed("300", "400"), 30*time.Second, 100*time.Millisecond)
+
+	cancel()
+	if err := <-done; err != nil && !errors.Is(err, context.Canceled) {
+		t.Fatalf("downloader failed: %v", err)
+	}
+}
+
+// TestReorg_AutoRecoveryByDefault replaces indexed blocks of a downloader whose config does not set
+// auto_recovery, and checks that it keeps running and re-indexes the blocks of the new chain
+func TestReorg_AutoRecoveryByDefault(t *testing.T) {
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+	// The reorg detector verifies the indexed blocks that are not final yet
+	chain := helpers.NewMockChain()
+	chain.SetFinalizedLag(4)
+	stack := newReorgTestStack(t, chain, token, 1)
+	require.True(t, *stack.cfg.Downloader.AutoRecovery)
+
+	mine := func(values ...int64) {
+		for _, value := range values {
+			chain.Mine([]types.Log{erc20Transfer(token, alice, bob, big.NewInt(value))})
+		}
+		// A block on top, so that the blocks of the transfers are indexed
+		chain.Mine(nil)
+	}
+
+	transfersIndexed := func(expected ...string) func() bool {
+		return func() bool {
+			values := stack.transferValues(0, chain.Head())
+			return slices.Equal(expected, values)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- stack.downloader.Download(ctx, stack.cfg) }()
+
+	mine(1, 2, 3, 4)
+	require.Eventually(t, transfersIndexed("1", "2", "3", "4"), 30*time.Second, 10*time.Millisecond)
+
+	// The new chain replaces the blocks of the last two transfers and grows past them
+	chain.Reorg(3)
+	mine(30, 40, 50)
+	require.Eventually(t, transfersIndexed("1", "2", "30", "40", "50"), 30*time.Second, 10*time.Millisecond)
+
+	cancel()
+	if err := <-done; err != nil && !errors.Is(err, context.Canceled) {
+		t.Fatalf("downloader failed: %v", err)
+	}
+}
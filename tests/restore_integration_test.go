@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"path"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	_ "github.com/goran-ethernal/ChainIndexor/examples/indexers/erc20"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/tests/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStack_RestoreWhileDownloading restores a snapshot of the downloader database while the downloader
+// runs, and checks that the blocks indexed after the snapshot are indexed again, exactly once
+func TestStack_RestoreWhileDownloading(t *testing.T) {
+	tokenAddress := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+	stack := helpers.NewTestStack(t, helpers.TestStackOptions{
+		Indexers: []config.IndexerConfig{
+			{
+				Name: "RestoredERC20Indexer",
+				Type: "erc20",
+				Contracts: []config.ContractConfig{
+					{Address: tokenAddress.Hex(), Events: []string{"Transfer(address,address,uint256)"}},
+				},
+			},
+		},
+	})
+
+	transferSig := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	transfer := func(value int64) []types.Log {
+		return []types.Log{{
+			Address: tokenAddress,
+			Topics:  []common.Hash{transferSig, common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes())},
+			Data:    common.LeftPadBytes(big.NewInt(value).Bytes(), 32),
+		}}
+	}
+
+	stack.Advance(transfer(1))
+	snapshotBlock := stack.Advance(transfer(2))
+
+	ctx := context.Background()
+	snapshotPath := path.Join(t.TempDir(), "snapshot.db")
+	require.NoError(t, stack.Downloader.Snapshot(ctx, snapshotPath))
+
+	stack.Advance(transfer(3))
+	stack.Advance(transfer(4))
+
+	// The downloader keeps running, and continues from the block of the snapshot
+	require.NoError(t, stack.Downloader.Restore(ctx, snapshotPath))
+
+	database, err := db.NewDBFromConfig(stack.Config.Downloader.DB)
+	require.NoError(t, err)
+	defer database.Close()
+
+	var lastIndexed uint64
+	require.NoError(t, database.QueryRow("SELECT last_indexed_block FROM sync_state").Scan(&lastIndexed))
+	require.GreaterOrEqual(t, lastIndexed, snapshotBlock)
+
+	stack.Advance(transfer(5))
+
+	// The log store fetched the blocks after the snapshot again
+	var logs int
+	require.NoError(t, database.QueryRow("SELECT COUNT(*) FROM event_logs").Scan(&logs))
+	require.Equal(t, 5, logs)
+
+	// The indexer rolled back the transfers after the snapshot before indexing them again
+	resp, err := http.Get(stack.APIURL + "/api/v1/indexers/RestoredERC20Indexer/events?event_type=transfer&sort_order=asc")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Events []struct {
+			Value string `json:"value"`
+		} `json:"events"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+	values := make([]string, 0, len(result.Events))
+	for _, event := range result.Events {
+		values = append(values, event.Value)
+	}
+	require.Equal(t, []string{"1", "2", "3", "4", "5"}, values)
+}
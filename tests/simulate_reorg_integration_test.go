@@ -0,0 +1,265 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/examples/indexers/erc20"
+	internalcommon "github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/internal/downloader"
+	"github.com/goran-ethernal/ChainIndexor/internal/logger"
+	"github.com/goran-ethernal/ChainIndexor/internal/migrations"
+	"github.com/goran-ethernal/ChainIndexor/internal/reorg"
+	"github.com/goran-ethernal/ChainIndexor/internal/rpc"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/pkg/indexer"
+	pkgrpc "github.com/goran-ethernal/ChainIndexor/pkg/rpc"
+	"github.com/goran-ethernal/ChainIndexor/tests/helpers"
+	"github.com/goran-ethernal/ChainIndexor/tests/testdata"
+	"github.com/stretchr/testify/require"
+)
+
+// reorgTestStack is a downloader with a single ERC-20 indexer that is started and stopped by the test,
+// since a reorg can only be simulated while the download is not running
+type reorgTestStack struct {
+	t           *testing.T
+	cfg         config.Config
+	downloader  *downloader.Downloader
+	syncManager *downloader.SyncManager
+	indexer     indexer.Queryable
+}
+
+// newReorgTestStack creates the stack. A zero finalizedLag keeps the default finality, otherwise blocks
+// are considered final finalizedLag blocks behind the latest block. The options adjust the config
+// before its defaults are applied.
+func newReorgTestStack(
+	t *testing.T,
+	client pkgrpc.EthClient,
+	token common.Address,
+	finalizedLag uint64,
+	opts ...func(cfg *config.Config),
+) *reorgTestStack {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	cfg := config.Config{
+		Downloader: config.DownloaderConfig{
+			RPCURL:       "http://reorg.test",
+			ChunkSize:    2,
+			PollInterval: internalcommon.NewDuration(10 * time.Millisecond),
+			DB:           config.DatabaseConfig{Path: path.Join(dir, "downloader.db")},
+		},
+		Indexers: []config.IndexerConfig{
+			{
+				Name: "ReorgERC20Indexer",
+				Type: "erc20",
+				DB:   config.DatabaseConfig{Path: path.Join(dir, "indexer.db")},
+				Contracts: []config.ContractConfig{
+					{
+						Address: token.Hex(),
+						Events:  []string{"Transfer(address,address,uint256)", "Approval(address,address,uint256)"},
+					},
+				},
+			},
+		},
+		Logging: &config.LoggingConfig{DefaultLevel: "error"},
+	}
+	if finalizedLag > 0 {
+		cfg.Downloader.Finality = "latest"
+		cfg.Downloader.FinalizedLag = finalizedLag
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	cfg.ApplyDefaults()
+	require.NoError(t, cfg.Validate())
+
+	log := logger.NewNopLogger()
+	maintenance := &db.NoOpMaintenance{}
+
+	require.NoError(t, migrations.RunMigrations(cfg.Downloader.DB))
+	database, err := db.NewDBFromConfig(cfg.Downloader.DB)
+	require.NoError(t, err)
+
+	chainID := cfg.Downloader.ExpectedChainID
+	reorgDetector, err := reorg.NewReorgDetector(chainID, database, client, log, maintenance, cfg.Downloader.HeaderCacheSize)
+	require.NoError(t, err)
+
+	syncManager, err := downloader.NewSyncManager(database, log, maintenance)
+	require.NoError(t, err)
+
+	dl, err := downloader.New(chainID, cfg.Downloader, client, reorgDetector, syncManager, maintenance, log)
+	require.NoError(t, err)
+
+	idx, err := indexer.Create(cfg.Indexers[0].Type, cfg.Indexers[0], log)
+	require.NoError(t, err)
+	dl.RegisterIndexer(idx)
+
+	queryable, ok := idx.(indexer.Queryable)
+	require.True(t, ok)
+
+	t.Cleanup(func() {
+		if closer, ok := idx.(interface{ Close() error }); ok {
+			_ = closer.Close()
+		}
+		_ = dl.Close()
+	})
+
+	return &reorgTestStack{t: t, cfg: cfg, downloader: dl, syncManager: syncManager, indexer: queryable}
+}
+
+// indexTo runs the download until the given block is indexed, then stops it
+func (s *reorgTestStack) indexTo(blockNum uint64) {
+	s.t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.downloader.Download(ctx, s.cfg) }()
+
+	require.Eventually(s.t, func() bool {
+		lastIndexed, err := s.syncManager.GetLastIndexedBlock()
+		return err == nil && lastIndexed >= blockNum
+	}, 30*time.Second, 10*time.Millisecond, "block %d was not indexed", blockNum)
+
+	cancel()
+	if err := <-done; err != nil && !errors.Is(err, context.Canceled) {
+		s.t.Fatalf("downloader failed: %v", err)
+	}
+}
+
+// transferValues returns the values of the indexed transfers of the block range, in block and log index order
+func (s *reorgTestStack) transferValues(fromBlock, toBlock uint64) []string {
+	s.t.Helper()
+
+	events, _, err := s.indexer.QueryEvents(context.Background(), indexer.QueryParams{
+		EventType: "Transfer",
+		FromBlock: &fromBlock,
+		ToBlock:   &toBlock,
+		Limit:     1000,
+		SortOrder: "asc",
+	})
+	require.NoError(s.t, err)
+
+	transfers, ok := events.([]*erc20.Transfer)
+	require.True(s.t, ok)
+
+	values := make([]string, len(transfers))
+	for i, transfer := range transfers {
+		values[i] = transfer.Value
+	}
+
+	return values
+}
+
+// TestSimulateReorg_Integration simulates a reorg of indexed blocks of an unchanged chain and checks
+// that the indexer ends up with the same events it had before
+func TestSimulateReorg_Integration(t *testing.T) {
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+	chain := helpers.NewMockChain()
+	stack := newReorgTestStack(t, chain, token, 0)
+
+	var lastBlock uint64
+	for i := range 5 {
+		lastBlock = chain.Mine([]types.Log{
+			erc20Transfer(token, alice, bob, big.NewInt(int64(2*i+1))),
+			erc20Transfer(token, bob, alice, big.NewInt(int64(2*i+2))),
+		})
+	}
+	stack.indexTo(lastBlock)
+
+	before := stack.transferValues(0, lastBlock)
+	require.Len(t, before, 10)
+
+	// Blocks 2-4 are reorged, block 5 is rolled back with them and left for the next run
+	simulation, err := stack.downloader.SimulateReorg(context.Background(), stack.cfg, 2, 3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), simulation.FromBlock)
+	require.Equal(t, uint64(4), simulation.ToBlock)
+	require.Equal(t, uint64(3), simulation.BlocksReindexed)
+	require.Equal(t, 6, simulation.EventsDeleted)
+	require.Equal(t, 6, simulation.EventsReadded)
+	require.Equal(t, uint64(4), simulation.ResumeBlock)
+	require.Equal(t, uint64(1), simulation.BlocksToCatchUp)
+
+	require.Equal(t, before[:8], stack.transferValues(0, lastBlock))
+
+	lastIndexed, err := stack.syncManager.GetLastIndexedBlock()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), lastIndexed)
+
+	// The next run catches up with the rolled back block
+	stack.indexTo(lastBlock)
+	require.Equal(t, before, stack.transferValues(0, lastBlock))
+
+	t.Run("blocks not indexed yet", func(t *testing.T) {
+		_, err := stack.downloader.SimulateReorg(context.Background(), stack.cfg, lastBlock, 2)
+		require.ErrorContains(t, err, "not indexed yet")
+	})
+}
+
+// TestSimulateReorg_Anvil indexes transfers, replaces their blocks with Anvil's snapshot and revert,
+// and checks that a simulated reorg of the blocks re-indexes the transfers of the new chain
+func TestSimulateReorg_Anvil(t *testing.T) {
+	helpers.SkipIfAnvilNotAvailable(t)
+
+	anvil := helpers.StartAnvil(t)
+	ctx := context.Background()
+
+	initialSupply := new(big.Int).Mul(big.NewInt(1000000), big.NewInt(1e18))
+	tokenAddress, _, token, err := testdata.DeployTestERC20(anvil.Signer, anvil.Client, initialSupply)
+	require.NoError(t, err)
+	time.Sleep(2 * time.Second)
+
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+	client, err := rpc.NewClient(ctx, anvil.URL, &config.RetryConfig{MaxAttempts: 1}, nil)
+	require.NoError(t, err)
+	// Anvil finalizes blocks two epochs behind the latest block, so the latest block minus one is indexed
+	stack := newReorgTestStack(t, client, tokenAddress, 1)
+
+	forkPoint := anvil.GetBlockNumber(t)
+	snapshotID := anvil.CreateSnapshot(t)
+
+	transfer := func(amount int64) {
+		t.Helper()
+
+		_, err := token.Transfer(anvil.Signer, bob, big.NewInt(amount))
+		require.NoError(t, err)
+		time.Sleep(1 * time.Second)
+	}
+
+	// The original chain, with a block on top so that the blocks of the transfers are final
+	transfer(100)
+	transfer(200)
+	anvil.Mine(t, 1)
+	head := anvil.GetBlockNumber(t) - 1
+	stack.indexTo(head)
+	require.Equal(t, []string{"100", "200"}, stack.transferValues(forkPoint+1, head))
+
+	// The chain the indexer has not seen yet replaces the blocks of the original transfers
+	anvil.RevertToForkPoint(t, snapshotID)
+	transfer(300)
+	transfer(400)
+	anvil.Mine(t, 1)
+	require.Equal(t, head+1, anvil.GetBlockNumber(t))
+
+	simulation, err := stack.downloader.SimulateReorg(ctx, stack.cfg, forkPoint+1, head-forkPoint)
+	require.NoError(t, err)
+	require.Equal(t, 2, simulation.EventsDeleted)
+	require.Equal(t, 2, simulation.EventsReadded)
+	require.Zero(t, simulation.BlocksToCatchUp)
+
+	require.Equal(t, []string{"300", "400"}, stack.transferValues(forkPoint+1, head))
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	_ "github.com/goran-ethernal/ChainIndexor/examples/indexers/erc20"
+	internalcommon "github.com/goran-ethernal/ChainIndexor/internal/common"
+	"github.com/goran-ethernal/ChainIndexor/internal/db"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/tests/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStack_IndexesAdvancedBlocks runs the full stack against a mock chain, without Anvil
+func TestStack_IndexesAdvancedBlocks(t *testing.T) {
+	tokenAddress := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+	stack := helpers.NewTestStack(t, helpers.TestStackOptions{
+		Indexers: []config.IndexerConfig{
+			{
+				Name: "StackERC20Indexer",
+				Type: "erc20",
+				Contracts: []config.ContractConfig{
+					{
+						Address: tokenAddress.Hex(),
+						Events:  []string{"Transfer(address,address,uint256)", "Approval(address,address,uint256)"},
+					},
+				},
+			},
+		},
+		Metrics: true,
+	})
+
+	transferSig := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	transfer := func(from, to common.Address, value int64) types.Log {
+		return types.Log{
+			Address: tokenAddress,
+			Topics:  []common.Hash{transferSig, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+			Data:    common.LeftPadBytes(big.NewInt(value).Bytes(), 32),
+		}
+	}
+
+	// A block without logs is indexed as well
+	stack.Advance(nil)
+	stack.Advance([]types.Log{transfer(alice, bob, 100), transfer(alice, bob, 50)})
+	lastBlock := stack.Advance([]types.Log{transfer(bob, alice, 25)})
+	require.Equal(t, stack.Chain.Head(), lastBlock)
+
+	resp, err := http.Get(stack.APIURL + "/api/v1/indexers/StackERC20Indexer/events?event_type=transfer")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+	events, ok := result["events"].([]any)
+	require.True(t, ok)
+	require.Len(t, events, 3)
+
+	metricsResp, err := http.Get(stack.MetricsURL + stack.Config.Metrics.Path)
+	require.NoError(t, err)
+	defer metricsResp.Body.Close()
+	require.Equal(t, http.StatusOK, metricsResp.StatusCode)
+}
+
+// TestStack_WriteBatchFlushedBeforeIndexing checks that batched logs are committed to the log store
+// before the indexers get them, even if the batch is neither full nor due
+func TestStack_WriteBatchFlushedBeforeIndexing(t *testing.T) {
+	tokenAddress := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+	stack := helpers.NewTestStack(t, helpers.TestStackOptions{
+		Indexers: []config.IndexerConfig{
+			{
+				Name: "BatchedERC20Indexer",
+				Type: "erc20",
+				Contracts: []config.ContractConfig{
+					{Address: tokenAddress.Hex(), Events: []string{"Transfer(address,address,uint256)"}},
+				},
+			},
+		},
+		WriteBatch: &config.WriteBatchConfig{
+			BatchSize:     1_000_000,
+			FlushInterval: internalcommon.NewDuration(time.Hour),
+		},
+	})
+
+	transferSig := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	transfer := types.Log{
+		Address: tokenAddress,
+		Topics:  []common.Hash{transferSig, common.BytesToHash(alice.Bytes()), common.BytesToHash(alice.Bytes())},
+		Data:    common.LeftPadBytes(big.NewInt(100).Bytes(), 32),
+	}
+
+	stack.Advance([]types.Log{transfer, transfer})
+
+	// Read the log store through a connection of its own, as reads through the API flush the batch first
+	database, err := db.NewDBFromConfig(stack.Config.Downloader.DB)
+	require.NoError(t, err)
+	defer database.Close()
+
+	var count int
+	require.NoError(t, database.QueryRow("SELECT COUNT(*) FROM event_logs").Scan(&count))
+	require.Equal(t, 2, count)
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.5.0;
+
+contract TestERC721 {
+    string public name = "Test NFT";
+    string public symbol = "TNFT";
+
+    mapping(uint256 => address) public ownerOf;
+    mapping(address => uint256) public balanceOf;
+    mapping(uint256 => address) public getApproved;
+    mapping(address => mapping(address => bool)) public isApprovedForAll;
+
+    event Transfer(address indexed from, address indexed to, uint256 indexed tokenId);
+    event Approval(address indexed owner, address indexed approved, uint256 indexed tokenId);
+    event ApprovalForAll(address indexed owner, address indexed operator, bool approved);
+
+    function mint(address to, uint256 tokenId) public {
+        require(to != address(0), "Mint to zero address");
+        require(ownerOf[tokenId] == address(0), "Token already minted");
+        ownerOf[tokenId] = to;
+        balanceOf[to] += 1;
+        emit Transfer(address(0), to, tokenId);
+    }
+
+    function approve(address approved, uint256 tokenId) public {
+        address owner = ownerOf[tokenId];
+        require(msg.sender == owner || isApprovedForAll[owner][msg.sender], "Not authorized");
+        getApproved[tokenId] = approved;
+        emit Approval(owner, approved, tokenId);
+    }
+
+    function setApprovalForAll(address operator, bool approved) public {
+        isApprovedForAll[msg.sender][operator] = approved;
+        emit ApprovalForAll(msg.sender, operator, approved);
+    }
+
+    function transferFrom(address from, address to, uint256 tokenId) public {
+        require(ownerOf[tokenId] == from, "Not the owner");
+        require(to != address(0), "Transfer to zero address");
+        require(
+            msg.sender == from || getApproved[tokenId] == msg.sender || isApprovedForAll[from][msg.sender],
+            "Not authorized"
+        );
+        delete getApproved[tokenId];
+        balanceOf[from] -= 1;
+        balanceOf[to] += 1;
+        ownerOf[tokenId] = to;
+        emit Transfer(from, to, tokenId);
+    }
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goran-ethernal/ChainIndexor/pkg/config"
+	"github.com/goran-ethernal/ChainIndexor/tests/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransactionEvents_Integration indexes a transaction emitting events of an ERC-20 and an ERC-721
+// contract and looks them up by transaction hash across both indexers
+func TestTransactionEvents_Integration(t *testing.T) {
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	nft := mockERC721{address: common.HexToAddress("0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512")}
+	alice := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	bob := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+	stack := helpers.NewTestStack(t, helpers.TestStackOptions{
+		Indexers: []config.IndexerConfig{
+			{
+				Name: "USDC",
+				Type: "erc20",
+				Contracts: []config.ContractConfig{
+					{
+						Address: usdc.Hex(),
+						Events:  []string{"Transfer(address,address,uint256)", "Approval(address,address,uint256)"},
+					},
+				},
+			},
+			{
+				Name: "NFT",
+				Type: "erc721",
+				Contracts: []config.ContractConfig{
+					{
+						Address: nft.address.Hex(),
+						Events: []string{
+							"Transfer(address,address,uint256)",
+							"Approval(address,address,uint256)",
+							"ApprovalForAll(address,address,bool)",
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// A sale: the buyer pays in USDC and receives the token, which the seller approved for the marketplace
+	sale := common.HexToHash("0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060")
+	inSale := func(log types.Log) types.Log {
+		log.TxHash = sale
+		return log
+	}
+
+	stack.Advance([]types.Log{
+		inSale(nft.approval(alice, bob, big.NewInt(7))),
+		erc20Transfer(usdc, bob, alice, big.NewInt(5)),
+		inSale(erc20Transfer(usdc, bob, alice, big.NewInt(100))),
+		inSale(nft.transfer(alice, bob, big.NewInt(7))),
+	})
+
+	txEvents := func(txHash string) (int, map[string]any) {
+		t.Helper()
+
+		resp, err := http.Get(fmt.Sprintf("%s/api/v1/tx/%s/events", stack.APIURL, txHash))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+		return resp.StatusCode, body
+	}
+
+	t.Run("events of the transaction", func(t *testing.T) {
+		status, body := txEvents(sale.Hex())
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, sale.Hex(), body["tx_hash"])
+
+		events, ok := body["events"].([]any)
+		require.True(t, ok)
+		require.Len(t, events, 3)
+
+		expected := []struct {
+			indexer   string
+			eventType string
+			logIndex  float64
+		}{
+			{indexer: "NFT", eventType: "Approval", logIndex: 0},
+			{indexer: "USDC", eventType: "Transfer", logIndex: 2},
+			{indexer: "NFT", eventType: "Transfer", logIndex: 3},
+		}
+		for i, want := range expected {
+			event, ok := events[i].(map[string]any)
+			require.True(t, ok)
+			require.Equal(t, want.indexer, event["indexer"])
+			require.Equal(t, want.eventType, event["event_type"])
+			require.Equal(t, want.logIndex, event["LogIndex"])
+		}
+
+		usdcTransfer, ok := events[1].(map[string]any)
+		require.True(t, ok)
+		require.Equal(t, "100", usdcTransfer["Value"])
+	})
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		status, body := txEvents(common.HexToHash("0x1").Hex())
+		require.Equal(t, http.StatusOK, status)
+		require.Empty(t, body["events"])
+	})
+
+	t.Run("invalid transaction hash", func(t *testing.T) {
+		status, _ := txEvents("0x1234")
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+}